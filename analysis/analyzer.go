@@ -0,0 +1,438 @@
+// Package analysis builds file dependency graphs for programs that embed clarity. It
+// is the code path the clarity CLI uses, so an Analyzer configured like a command line
+// produces the graph that command prints.
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+// ErrCleanWorkingTree is returned when uncommitted changes are analyzed and there are none.
+var ErrCleanWorkingTree = errors.New("working tree has no uncommitted changes")
+
+// Analyzer selects files from a repository and builds their dependency graph. The zero
+// value of every field except RepoPath is a usable default.
+type Analyzer struct {
+	// RepoPath is the root of the git repository to analyze.
+	RepoPath string
+	// CommitRange is a commit (f0459ec, HEAD~3) or a range (main..feature,
+	// main...feature) whose changed files are analyzed, read at the head commit. An
+	// empty CommitRange analyzes the uncommitted changes in the working tree.
+	CommitRange string
+	// ExplicitPaths lists the files to analyze instead of the changed ones; a non-nil
+	// empty list analyzes no files. Relative paths are resolved against RepoPath.
+	ExplicitPaths []string
+	// Uncommitted selects which uncommitted changes are analyzed when CommitRange is
+	// empty. When nil, staged, unstaged, and untracked changes are all analyzed.
+	Uncommitted *git.UncommittedOptions
+	// WholeTree analyzes every supported file of the head commit of CommitRange, or of
+	// the working tree when it is empty, instead of the changed ones. ExplicitPaths
+	// still takes precedence.
+	WholeTree bool
+	// ExtraRepoPaths lists the roots of other repositories analyzed in the same graph,
+	// such as sibling checkouts linked by a go.work file. Their uncommitted changes
+	// are selected and their statistics read like RepoPath's. They cannot be combined
+	// with CommitRange, since commits belong to one repository.
+	ExtraRepoPaths []string
+
+	// IncludeExtensions keeps only files with these extensions, e.g. ".go".
+	IncludeExtensions []string
+	// ExcludeExtensions drops files with these extensions.
+	ExcludeExtensions []string
+	// IncludePatterns keeps only files whose repo-relative path matches one of these
+	// path patterns, e.g. "src/**/*.ts".
+	IncludePatterns []string
+	// ExcludePatterns drops files whose repo-relative path matches one of these path
+	// patterns. A file matched by both pattern lists is dropped.
+	ExcludePatterns []string
+
+	// ContentReader reads the analyzed files. When nil, files are read from the head
+	// commit of CommitRange, or from the working tree when it is empty.
+	ContentReader vcs.ContentReader
+	// Cache reuses what earlier runs parsed out of files whose content is unchanged.
+	Cache *depgraph.ParseCache
+	// Parallelism bounds how many files are parsed at once; 0 means one per CPU.
+	Parallelism int
+	// Lenient skips files the intra-package analysis cannot read or parse, logging a
+	// warning for each, instead of failing the analysis.
+	Lenient bool
+	// ShowExternal keeps the third-party packages the files import as external nodes,
+	// one per package, instead of dropping those imports.
+	ShowExternal bool
+	// GoModules maps Go import path prefixes to the absolute directories holding their
+	// packages, for repositories without go.mod files. When set, go.mod files are not
+	// read.
+	GoModules map[string]string
+	// PathAliases maps symlinks in the repository to the canonical paths they point
+	// at, so imports written through a symlinked directory reach the files behind it.
+	PathAliases map[string]string
+	// SkipStats leaves Result.FileStats empty instead of reading addition and deletion
+	// counts from git.
+	SkipStats bool
+	// Progress, when set, is called as files are parsed with the number parsed so far
+	// and the total, from several goroutines at once.
+	Progress func(parsed, total int)
+	// Refine, when set, transforms the dependency graph after it is built and before
+	// file metadata is attached, e.g. to narrow it to the files around one file.
+	Refine func(graph depgraph.DependencyGraph) (depgraph.DependencyGraph, error)
+}
+
+// Result is the outcome of an analysis.
+type Result struct {
+	// Graph is the dependency graph with per-file and per-edge metadata.
+	Graph depgraph.FileDependencyGraph
+	// FileStats holds the addition and deletion counts of the changed files.
+	FileStats map[string]vcs.FileStats
+	// StatsErr reports why FileStats is empty when reading statistics failed. It does
+	// not fail the analysis.
+	StatsErr error
+	// Files lists the absolute paths of the analyzed files.
+	Files []string
+	// Diagnostics reports files whose dependencies were only partly resolved.
+	Diagnostics []moduleapi.Diagnostic
+}
+
+// Run selects the files, builds their dependency graph, and reads their statistics.
+// It returns ErrCleanWorkingTree when uncommitted changes are analyzed and there are none.
+func (a Analyzer) Run(ctx context.Context) (Result, error) {
+	if a.RepoPath == "" {
+		return Result{}, fmt.Errorf("repository path is required")
+	}
+	if a.CommitRange != "" && len(a.ExtraRepoPaths) > 0 {
+		return Result{}, fmt.Errorf("other repositories can only be analyzed with uncommitted changes, not with a commit range")
+	}
+	includeSet, err := compilePatterns("include pattern", a.IncludePatterns)
+	if err != nil {
+		return Result{}, err
+	}
+	excludeSet, err := compilePatterns("exclude pattern", a.ExcludePatterns)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var fromCommit, toCommit string
+	var isCommitRange bool
+	if a.CommitRange != "" {
+		fromCommit, toCommit, isCommitRange, err = git.ResolveCommitRangeContext(ctx, a.RepoPath, a.CommitRange)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	filePaths, err := a.selectFiles(ctx, fromCommit, toCommit, isCommitRange)
+	if err != nil {
+		return Result{}, err
+	}
+	filePaths = KeepExtensions(filePaths, a.IncludeExtensions)
+	filePaths = DropExtensions(filePaths, a.ExcludeExtensions)
+	filePaths = a.filterPatterns(filePaths, includeSet, excludeSet)
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	contentReader := a.ContentReader
+	if contentReader == nil {
+		contentReader = vcs.FilesystemContentReader()
+		if toCommit != "" {
+			contentReader = git.GitCommitContentReaderContext(ctx, a.RepoPath, toCommit)
+			if closer, ok := contentReader.(io.Closer); ok {
+				defer closer.Close()
+			}
+		}
+	}
+
+	graph, diagnostics, err := depgraph.BuildDependencyGraphContext(ctx, filePaths, contentReader, depgraph.BuildOptions{
+		Cache:        a.Cache,
+		Parallelism:  a.Parallelism,
+		Lenient:      a.Lenient,
+		ShowExternal: a.ShowExternal,
+		Progress:     a.Progress,
+		GoModules:    a.GoModules,
+		PathAliases:  a.PathAliases,
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return Result{}, ctxErr
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	if a.Refine != nil {
+		graph, err = a.Refine(graph)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	result := Result{Files: filePaths, Diagnostics: diagnostics}
+	if !a.SkipStats {
+		result.FileStats, result.StatsErr = a.fileStats(ctx, fromCommit, toCommit, isCommitRange)
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	result.Graph, err = depgraph.NewFileDependencyGraph(graph, result.FileStats, contentReader)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to build file graph metadata: %w", err)
+	}
+	if len(a.ExtraRepoPaths) > 0 {
+		result.Graph.AssignRepositories(a.repoPaths())
+	} else {
+		result.Graph.AssignDirectories(a.RepoPath)
+	}
+	return result, nil
+}
+
+// selectFiles returns ExplicitPaths, made absolute, or the files changed in the commit,
+// range, or working tree.
+func (a Analyzer) selectFiles(ctx context.Context, fromCommit, toCommit string, isCommitRange bool) ([]string, error) {
+	if a.ExplicitPaths != nil {
+		filePaths := make([]string, 0, len(a.ExplicitPaths))
+		for _, path := range a.ExplicitPaths {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(a.RepoPath, path)
+			}
+			filePaths = append(filePaths, filepath.Clean(path))
+		}
+		return filePaths, nil
+	}
+	if a.WholeTree {
+		var filePaths []string
+		for _, repoPath := range a.repoPaths() {
+			files, err := TreeFiles(ctx, repoPath, toCommit)
+			if err != nil {
+				return nil, err
+			}
+			filePaths = append(filePaths, SupportedFiles(files)...)
+		}
+		return filePaths, nil
+	}
+
+	switch {
+	case isCommitRange:
+		filePaths, changed, err := ListChangedFiles(a.IncludeExtensions, func(exts ...string) ([]string, error) {
+			return git.GetCommitRangeFilesContext(ctx, a.RepoPath, fromCommit, toCommit, exts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get files from commit range: %w", err)
+		}
+		if !changed {
+			return nil, fmt.Errorf("no files changed in commit range %s", a.CommitRange)
+		}
+		return filePaths, nil
+	case toCommit != "":
+		filePaths, changed, err := ListChangedFiles(a.IncludeExtensions, func(exts ...string) ([]string, error) {
+			return git.GetCommitFilesContext(ctx, a.RepoPath, toCommit, exts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get files from commit: %w", err)
+		}
+		if !changed {
+			return nil, fmt.Errorf("no files changed in commit %s", a.CommitRange)
+		}
+		return filePaths, nil
+	default:
+		var filePaths []string
+		var changed bool
+		for _, repoPath := range a.repoPaths() {
+			repoFiles, repoChanged, err := ListChangedFiles(a.IncludeExtensions, func(exts ...string) ([]string, error) {
+				return git.GetUncommittedFilesWithOptionsContext(ctx, repoPath, a.uncommittedOptions(), exts...)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get uncommitted files: %w", err)
+			}
+			filePaths = append(filePaths, repoFiles...)
+			changed = changed || repoChanged
+		}
+		if !changed {
+			return nil, ErrCleanWorkingTree
+		}
+		return filePaths, nil
+	}
+}
+
+// TreeFiles returns the absolute paths of every file in the tree of commitID, or, when
+// commitID is empty, of the tracked files still on disk and the untracked files that
+// are not ignored.
+func TreeFiles(ctx context.Context, repoPath, commitID string) ([]string, error) {
+	if commitID != "" {
+		files, err := git.GetCommitTreeFilesContext(ctx, repoPath, commitID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files at %s: %w", commitID, err)
+		}
+		return files, nil
+	}
+
+	tracked, err := git.ListTrackedFilesContext(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list working tree files: %w", err)
+	}
+	untracked, err := git.ListUntrackedFilesContext(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list working tree files: %w", err)
+	}
+	var files []string
+	for _, path := range append(tracked, untracked...) {
+		exists, err := git.FileExists(path)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// SupportedFiles returns the files in a language clarity can analyze.
+func SupportedFiles(filePaths []string) []string {
+	supported := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if registry.IsSupportedLanguageExtension(filepath.Ext(filePath)) {
+			supported = append(supported, filePath)
+		}
+	}
+	return supported
+}
+
+// ListChangedFiles lists changed files with list, asking git for only files with one
+// of exts so a huge change is not listed only to be discarded. changed reports whether
+// any file changed at all: when none has one of exts, list runs once more without them
+// to tell an empty change from one the filter empties.
+func ListChangedFiles(exts []string, list func(exts ...string) ([]string, error)) (files []string, changed bool, err error) {
+	files, err = list(exts...)
+	if err != nil || len(files) > 0 || len(exts) == 0 {
+		return files, len(files) > 0, err
+	}
+	all, err := list()
+	return files, len(all) > 0, err
+}
+
+// fileStats reads addition and deletion counts for the commit, range, or working tree.
+func (a Analyzer) fileStats(ctx context.Context, fromCommit, toCommit string, isCommitRange bool) (map[string]vcs.FileStats, error) {
+	switch {
+	case isCommitRange:
+		return git.GetCommitRangeFileStatsContext(ctx, a.RepoPath, fromCommit, toCommit)
+	case toCommit != "":
+		return git.GetCommitFileStatsContext(ctx, a.RepoPath, toCommit)
+	default:
+		stats := make(map[string]vcs.FileStats)
+		for _, repoPath := range a.repoPaths() {
+			repoStats, err := git.GetUncommittedFileStatsWithOptionsContext(ctx, repoPath, a.uncommittedOptions(), vcs.FilesystemContentReader())
+			if err != nil {
+				return nil, err
+			}
+			for file, fileStats := range repoStats {
+				stats[file] = fileStats
+			}
+		}
+		return stats, nil
+	}
+}
+
+// repoPaths returns RepoPath followed by ExtraRepoPaths.
+func (a Analyzer) repoPaths() []string {
+	return append([]string{a.RepoPath}, a.ExtraRepoPaths...)
+}
+
+// uncommittedOptions returns Uncommitted, or every kind of change when it is nil.
+func (a Analyzer) uncommittedOptions() git.UncommittedOptions {
+	if a.Uncommitted == nil {
+		return git.AllUncommittedChanges()
+	}
+	return *a.Uncommitted
+}
+
+// filterPatterns keeps the files matched by include, when it has patterns, and not
+// matched by exclude. Files outside RepoPath have no repo-relative path and are kept.
+func (a Analyzer) filterPatterns(filePaths []string, include, exclude patterns.Set) []string {
+	if len(a.IncludePatterns) == 0 && len(a.ExcludePatterns) == 0 {
+		return filePaths
+	}
+
+	filtered := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		relPath, err := filepath.Rel(a.RepoPath, filePath)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			filtered = append(filtered, filePath)
+			continue
+		}
+		if len(a.IncludePatterns) > 0 && !include.Match(relPath) {
+			continue
+		}
+		if exclude.Match(relPath) {
+			continue
+		}
+		filtered = append(filtered, filePath)
+	}
+	return filtered
+}
+
+func compilePatterns(kind string, values []string) (patterns.Set, error) {
+	if len(values) == 0 {
+		return patterns.Set{}, nil
+	}
+	set, err := patterns.CompileSet(values)
+	if err != nil {
+		return patterns.Set{}, fmt.Errorf("%s: %w", kind, err)
+	}
+	return set, nil
+}
+
+// KeepExtensions returns the files whose extension, compared case-insensitively, is one
+// of exts. An empty exts keeps every file.
+func KeepExtensions(filePaths []string, exts []string) []string {
+	if len(exts) == 0 {
+		return filePaths
+	}
+	wanted := extensionSet(exts)
+	filtered := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if wanted[strings.ToLower(filepath.Ext(filePath))] {
+			filtered = append(filtered, filePath)
+		}
+	}
+	return filtered
+}
+
+// DropExtensions returns the files whose extension, compared case-insensitively, is not
+// one of exts.
+func DropExtensions(filePaths []string, exts []string) []string {
+	if len(exts) == 0 {
+		return filePaths
+	}
+	unwanted := extensionSet(exts)
+	filtered := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if !unwanted[strings.ToLower(filepath.Ext(filePath))] {
+			filtered = append(filtered, filePath)
+		}
+	}
+	return filtered
+}
+
+// extensionSet lowercases exts and adds the leading dot where it is missing.
+func extensionSet(exts []string) map[string]bool {
+	set := make(map[string]bool, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		set[ext] = true
+	}
+	return set
+}
@@ -0,0 +1,228 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestAnalyzer_CommitRangeAnalyzesChangedFilesAtHead(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+	// An uncommitted import must not appear in a commit range analysis.
+	writeAnalysisFile(t, repoDir, "src/c.ts", "import { b } from './b';\nexport const c = b;\n")
+
+	result, err := Analyzer{RepoPath: repoDir, CommitRange: "HEAD~1..HEAD"}.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"src/a.ts", "src/b.ts"}, relativeFiles(repoDir, result.Files))
+	assert.Equal(t, map[string][]string{
+		"src/a.ts": nil,
+		"src/b.ts": {"src/a.ts"},
+	}, relativeAdjacency(t, repoDir, result.Graph.Graph))
+	require.NoError(t, result.StatsErr)
+	assert.Equal(t, vcs.FileStats{Additions: 2, IsNew: true}, result.FileStats[filepath.Join(repoDir, "src", "b.ts")])
+	require.NotNil(t, result.Graph.Meta.Files[filepath.Join(repoDir, "src", "b.ts")].Stats)
+}
+
+func TestAnalyzer_ExplicitPathsAreReadFromTheWorkingTree(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+	writeAnalysisFile(t, repoDir, "src/c.ts", "import { b } from './b';\nexport const c = b;\n")
+
+	result, err := Analyzer{RepoPath: repoDir, ExplicitPaths: []string{"src/b.ts", "src/c.ts"}}.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"src/b.ts": nil,
+		"src/c.ts": {"src/b.ts"},
+	}, relativeAdjacency(t, repoDir, result.Graph.Graph))
+}
+
+func TestAnalyzer_WholeTreeAnalyzesEverySupportedFile(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+	writeAnalysisFile(t, repoDir, "src/c.ts", "import { b } from './b';\nexport const c = b;\n")
+	writeAnalysisFile(t, repoDir, "docs/notes.txt", "notes\n")
+	require.NoError(t, os.Remove(filepath.Join(repoDir, "src", "a.ts")))
+
+	result, err := Analyzer{RepoPath: repoDir, WholeTree: true, SkipStats: true}.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/b.ts", "src/c.ts"}, relativeFiles(repoDir, result.Files))
+
+	result, err = Analyzer{RepoPath: repoDir, CommitRange: "HEAD", WholeTree: true, SkipStats: true}.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/a.ts", "src/b.ts"}, relativeFiles(repoDir, result.Files))
+}
+
+func TestAnalyzer_CleanWorkingTree(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+
+	_, err := Analyzer{RepoPath: repoDir}.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrCleanWorkingTree)
+}
+
+func TestAnalyzer_FiltersByExtensionAndPattern(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+	writeAnalysisFile(t, repoDir, "src/gen/g.ts", "export const g = 1;\n")
+	writeAnalysisFile(t, repoDir, "docs/notes.md", "# notes\n")
+
+	result, err := Analyzer{
+		RepoPath:          repoDir,
+		ExcludeExtensions: []string{"md"},
+		IncludePatterns:   []string{"src/**"},
+		ExcludePatterns:   []string{"src/gen/**"},
+	}.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, relativeFiles(repoDir, result.Files))
+
+	result, err = Analyzer{
+		RepoPath:          repoDir,
+		ExplicitPaths:     []string{"src/a.ts", "src/b.ts", "src/gen/g.ts", "docs/notes.md"},
+		IncludeExtensions: []string{".TS"},
+		ExcludePatterns:   []string{"src/gen/**"},
+	}.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"src/a.ts", "src/b.ts"}, relativeFiles(repoDir, result.Files))
+}
+
+func TestAnalyzer_IncludeExtensionsThatEmptyACommitIsNotAnEmptyCommit(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+
+	result, err := Analyzer{RepoPath: repoDir, CommitRange: "HEAD", IncludeExtensions: []string{"go"}}.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.Files)
+
+	writeAnalysisFile(t, repoDir, "src/c.ts", "export const c = 1;\n")
+	result, err = Analyzer{RepoPath: repoDir, IncludeExtensions: []string{"go"}}.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.Files)
+
+	gitRun(t, repoDir, "commit", "--allow-empty", "-m", "empty")
+	_, err = Analyzer{RepoPath: repoDir, CommitRange: "HEAD", IncludeExtensions: []string{"go"}}.Run(context.Background())
+	assert.EqualError(t, err, "no files changed in commit HEAD")
+}
+
+func TestAnalyzer_RefineRunsBeforeMetadata(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+
+	result, err := Analyzer{
+		RepoPath:    repoDir,
+		CommitRange: "HEAD~1..HEAD",
+		SkipStats:   true,
+		Refine: func(graph depgraph.DependencyGraph) (depgraph.DependencyGraph, error) {
+			return depgraph.NewDependencyGraph(), nil
+		},
+	}.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Graph.Meta.Files)
+	assert.Nil(t, result.FileStats)
+}
+
+func TestAnalyzer_RejectsInvalidConfiguration(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+
+	_, err := Analyzer{}.Run(context.Background())
+	require.ErrorContains(t, err, "repository path is required")
+
+	_, err = Analyzer{RepoPath: repoDir, CommitRange: "HEAD", IncludePatterns: []string{"src/[a-"}}.Run(context.Background())
+	require.ErrorContains(t, err, `include pattern: invalid pattern "src/[a-" at offset 4`)
+}
+
+func TestAnalyzer_StopsWhenContextIsCanceled(t *testing.T) {
+	repoDir := writeAnalysisRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Analyzer{RepoPath: repoDir, CommitRange: "HEAD"}.Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestExtensionFilters(t *testing.T) {
+	files := []string{"a.go", "b.GO", "c.ts", "Makefile"}
+
+	assert.Equal(t, []string{"a.go", "b.GO"}, KeepExtensions(files, []string{"go"}))
+	assert.Equal(t, []string{"c.ts", "Makefile"}, DropExtensions(files, []string{".go"}))
+	assert.Equal(t, files, KeepExtensions(files, nil))
+}
+
+// writeAnalysisRepo creates a repository whose second commit adds b.ts, which imports
+// a.ts, and changes a.ts.
+func writeAnalysisRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+
+	writeAnalysisFile(t, repoDir, "src/a.ts", "export const a = 1;\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+
+	writeAnalysisFile(t, repoDir, "src/a.ts", "export const a = 2;\n")
+	writeAnalysisFile(t, repoDir, "src/b.ts", "import { a } from './a';\nexport const b = a;\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add b")
+	return repoDir
+}
+
+func writeAnalysisFile(t *testing.T, repoDir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(repoDir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
+
+func relativeFiles(repoDir string, files []string) []string {
+	relFiles := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, _ := filepath.Rel(repoDir, file)
+		relFiles = append(relFiles, filepath.ToSlash(rel))
+	}
+	sort.Strings(relFiles)
+	return relFiles
+}
+
+func relativeAdjacency(t *testing.T, repoDir string, graph depgraph.DependencyGraph) map[string][]string {
+	t.Helper()
+
+	adjacency, err := depgraph.AdjacencyList(graph)
+	require.NoError(t, err)
+	relAdjacency := make(map[string][]string, len(adjacency))
+	for file, deps := range adjacency {
+		var relDeps []string
+		if len(deps) > 0 {
+			relDeps = relativeFiles(repoDir, deps)
+		}
+		relAdjacency[relativeFiles(repoDir, []string{file})[0]] = relDeps
+	}
+	return relAdjacency
+}
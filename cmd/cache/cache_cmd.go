@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the cache command.
+var Cmd = NewCommand()
+
+// NewCommand returns a new cache command instance.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the parse cache",
+		Long: `Manage the on-disk cache of what clarity show parsed out of each file, such as
+its imports.
+
+Entries are keyed by file content, so a file is parsed again whenever it changes and
+clearing the cache is never needed for correctness; it only frees disk space. The
+cache prunes the entries used least recently once it outgrows 256 MiB.
+
+Examples:
+  clarity cache dir
+  clarity cache clear`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached parse result",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cache, err := defaultCache()
+			if err != nil {
+				return err
+			}
+			if err := cache.Clear(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Cleared %s\n", cache.Dir())
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dir",
+		Short: "Print the cache directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cache, err := defaultCache()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), cache.Dir())
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func defaultCache() (*depgraph.ParseCache, error) {
+	dir, err := depgraph.DefaultParseCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return depgraph.NewParseCache(dir, depgraph.DefaultParseCacheMaxBytes), nil
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+func TestCacheClear_RemovesCacheDirectory(t *testing.T) {
+	t.Setenv(depgraph.CacheDirEnv, t.TempDir())
+
+	var dirOut bytes.Buffer
+	cmd := NewCommand()
+	cmd.SetOut(&dirOut)
+	cmd.SetArgs([]string{"dir"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cache dir error = %v", err)
+	}
+	dir := strings.TrimSpace(dirOut.String())
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "entry.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd = NewCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"clear"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cache clear error = %v", err)
+	}
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, stat error = %v", dir, err)
+	}
+	if want := "Cleared " + dir + "\n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
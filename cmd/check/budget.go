@@ -0,0 +1,232 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/analysis"
+	"github.com/LegacyCodeHQ/clarity/depgraph/rules"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/internal/projectconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+type budgetOptions struct {
+	repoPath       string
+	commitID       string
+	outputFormat   string
+	baselinePath   string
+	updateBaseline bool
+}
+
+func newBudgetCommand() *cobra.Command {
+	opts := &budgetOptions{
+		outputFormat: formatText,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "budget",
+		Short: "List files over the dependency budget in .clarity.yml",
+		Long: `List the files whose out-degree, in-degree, or lines of code exceed the limits in
+the budget section of .clarity.yml, each with its value and limit. Limits apply to
+the repo-relative paths their patterns match; when several limits of a metric match
+a file, the smallest applies:
+
+  budget:
+    limits:
+      - path: "**"
+        max-loc: 800
+        max-in-degree: 30
+      - path: "cmd/**"
+        max-out-degree: 15
+
+The whole tree is checked: the head commit with --commit, or the working tree
+otherwise. With a range, only the files it changes are reported. The run fails on
+any violation; with --baseline, only on violations the baseline file does not
+record or whose value has grown since. --update-baseline writes the current
+violations to the baseline file instead.
+
+Examples:
+  clarity check budget
+  clarity check budget -c main...HEAD --baseline budget-baseline.json
+  clarity check budget --baseline budget-baseline.json --update-baseline`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBudget(cmd, opts)
+		},
+	}
+
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVarP(&opts.commitID, "commit", "c", "", "Git commit or range whose head to check (e.g., f0459ec, HEAD~3, main...HEAD); the working tree when empty")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", opts.outputFormat, fmt.Sprintf("Output format (%s)", supportedFormats()))
+	cmd.Flags().StringVar(&opts.baselinePath, "baseline", "", "JSON file of accepted violations; only new or worsened violations fail the run")
+	cmd.Flags().BoolVar(&opts.updateBaseline, "update-baseline", false, "Write the current violations to the --baseline file and succeed")
+
+	return cmd
+}
+
+func runBudget(cmd *cobra.Command, opts *budgetOptions) error {
+	if !isSupportedFormat(opts.outputFormat) {
+		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, supportedFormats())
+	}
+	if opts.updateBaseline && opts.baselinePath == "" {
+		return fmt.Errorf("--update-baseline requires --baseline")
+	}
+
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	repoRoot := repo.RepoRoot
+
+	budget, err := loadBudget(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	contentReader, only, err := loadBudgetScope(ctx, repoRoot, opts.commitID)
+	if err != nil {
+		return err
+	}
+	if closer, ok := contentReader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	result, err := analysis.Analyzer{
+		RepoPath:      repoRoot,
+		CommitRange:   opts.commitID,
+		WholeTree:     true,
+		ContentReader: contentReader,
+		SkipStats:     true,
+	}.Run(ctx)
+	if err != nil {
+		return err
+	}
+	violations, err := budget.Evaluate(result.Graph, repoRoot, contentReader, only)
+	if err != nil {
+		return err
+	}
+
+	if opts.updateBaseline {
+		if err := rules.WriteBudgetBaseline(opts.baselinePath, violations); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d budget violation(s) to %s.\n", len(violations), opts.baselinePath)
+		return nil
+	}
+	if opts.baselinePath != "" {
+		baseline, err := rules.LoadBudgetBaseline(opts.baselinePath)
+		if err != nil {
+			return err
+		}
+		violations = baseline.Classify(violations)
+	}
+
+	if err := writeBudgetViolations(cmd.OutOrStdout(), opts.outputFormat, violations); err != nil {
+		return err
+	}
+	if opts.baselinePath != "" {
+		if failures := countBudgetFailures(violations); failures > 0 {
+			return fmt.Errorf("%d new or worsened budget violation(s)", failures)
+		}
+		return nil
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d budget violation(s)", len(violations))
+	}
+	return nil
+}
+
+// loadBudget reads the budget limits of the config file at the repository root. A
+// missing file or an empty budget section is an error, since there is nothing to
+// check.
+func loadBudget(repoRoot string) (rules.Budget, error) {
+	path, err := projectconfig.Find(repoRoot)
+	if err != nil {
+		return rules.Budget{}, err
+	}
+	if path == "" {
+		return rules.Budget{}, fmt.Errorf("no budget limits: add a budget section to %s at the repository root", projectconfig.FileNames[0])
+	}
+	budget, err := rules.LoadBudget(path)
+	if err != nil {
+		return rules.Budget{}, err
+	}
+	if len(budget.Limits) == 0 {
+		return rules.Budget{}, fmt.Errorf("no budget limits: add a budget section to %s", path)
+	}
+	return budget, nil
+}
+
+// loadBudgetScope returns a reader for the tree to check, the head commit of commitID
+// or the working tree when commitID is empty, and the files to report. Every file is
+// reported, and the files to report are nil, unless commitID is a range.
+func loadBudgetScope(ctx context.Context, repoRoot, commitID string) (vcs.ContentReader, map[string]bool, error) {
+	if commitID == "" {
+		return vcs.FilesystemContentReader(), nil, nil
+	}
+
+	fromCommit, toCommit, isCommitRange, err := git.ResolveCommitRangeContext(ctx, repoRoot, commitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var only map[string]bool
+	if isCommitRange {
+		changed, err := git.GetCommitRangeFilesContext(ctx, repoRoot, fromCommit, toCommit)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get changed files: %w", err)
+		}
+		only = make(map[string]bool, len(changed))
+		for _, file := range changed {
+			only[file] = true
+		}
+	}
+	return git.GitCommitContentReaderContext(ctx, repoRoot, toCommit), only, nil
+}
+
+func writeBudgetViolations(out io.Writer, format string, violations []rules.BudgetViolation) error {
+	if format == formatJSON {
+		output, err := json.MarshalIndent(struct {
+			Violations []rules.BudgetViolation `json:"violations"`
+		}{violations}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		fmt.Fprintln(out, string(output))
+		return nil
+	}
+
+	if len(violations) == 0 {
+		fmt.Fprintln(out, "No budget violations.")
+		return nil
+	}
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, violation := range violations {
+		line := fmt.Sprintf("%s\t%s\t%d > %d", violation.File, violation.Metric, violation.Value, violation.Limit)
+		if violation.Status != "" {
+			line += "\t" + violation.Status
+		}
+		fmt.Fprintln(writer, line)
+	}
+	return writer.Flush()
+}
+
+func countBudgetFailures(violations []rules.BudgetViolation) int {
+	count := 0
+	for _, violation := range violations {
+		if violation.Status != rules.BudgetStatusBaselined {
+			count++
+		}
+	}
+	return count
+}
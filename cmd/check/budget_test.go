@@ -0,0 +1,117 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/rules"
+)
+
+func TestBudget_ReportsViolationsAndFails(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+
+	stdout, _, err := runCheckCommand(t, "budget", "-r", repoDir)
+
+	require.EqualError(t, err, "1 budget violation(s)")
+	assert.True(t, strings.HasPrefix(stdout, "src/util.ts  in-degree  2 > 1\nUsage:"), stdout)
+}
+
+func TestBudget_JSONAtCommit(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+
+	stdout, _, err := runCheckCommand(t, "budget", "-r", repoDir, "-c", "HEAD", "--format", "json")
+	require.Error(t, err)
+
+	var report struct {
+		Violations []rules.BudgetViolation `json:"violations"`
+	}
+	require.NoError(t, json.NewDecoder(strings.NewReader(stdout)).Decode(&report), stdout)
+	assert.Equal(t, []rules.BudgetViolation{
+		{File: "src/util.ts", Metric: rules.MetricInDegree, Value: 2, Limit: 1},
+	}, report.Violations)
+}
+
+func TestBudget_RangeReportsOnlyChangedFiles(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+	writeCoverageFile(t, repoDir, "src/a.ts", "import { util } from './util';\nexport const a = util + 1;\n")
+	gitRun(t, repoDir, "commit", "-am", "change a")
+
+	stdout, _, err := runCheckCommand(t, "budget", "-r", repoDir, "-c", "HEAD~1..HEAD")
+
+	require.NoError(t, err)
+	assert.Equal(t, "No budget violations.\n", stdout)
+}
+
+func TestBudget_BaselineFailsOnlyOnNewOrWorsenedViolations(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+	baselinePath := filepath.Join(t.TempDir(), "budget-baseline.json")
+
+	stdout, _, err := runCheckCommand(t, "budget", "-r", repoDir, "--baseline", baselinePath, "--update-baseline")
+	require.NoError(t, err)
+	assert.Equal(t, "Wrote 1 budget violation(s) to "+baselinePath+".\n", stdout)
+
+	stdout, _, err = runCheckCommand(t, "budget", "-r", repoDir, "--baseline", baselinePath)
+	require.NoError(t, err)
+	assert.Equal(t, "src/util.ts  in-degree  2 > 1  baselined\n", stdout)
+
+	writeCoverageFile(t, repoDir, "src/c.ts", "import { util } from './util';\nexport const c = util;\n")
+	stdout, _, err = runCheckCommand(t, "budget", "-r", repoDir, "--baseline", baselinePath)
+	require.EqualError(t, err, "1 new or worsened budget violation(s)")
+	assert.True(t, strings.HasPrefix(stdout, "src/util.ts  in-degree  3 > 1  worsened\nUsage:"), stdout)
+}
+
+func TestBudget_UpdateBaselineRequiresBaseline(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+
+	_, _, err := runCheckCommand(t, "budget", "-r", repoDir, "--update-baseline")
+	require.EqualError(t, err, "--update-baseline requires --baseline")
+}
+
+func TestBudget_RequiresBudgetSection(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+	require.NoError(t, os.Remove(filepath.Join(repoDir, ".clarity.yml")))
+
+	_, _, err := runCheckCommand(t, "budget", "-r", repoDir)
+	require.ErrorContains(t, err, "no budget limits: add a budget section to .clarity.yml")
+}
+
+func TestBudget_StopsWhenCanceled(t *testing.T) {
+	repoDir := writeBudgetRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"budget", "-r", repoDir})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.ExecuteContext(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// writeBudgetRepo creates a repository where a.ts and b.ts import util.ts, with a
+// budget that allows each file one importer.
+func writeBudgetRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+
+	writeCoverageFile(t, repoDir, ".clarity.yml", "budget:\n  limits:\n    - path: \"src/**\"\n      max-in-degree: 1\n      max-loc: 10\n")
+	writeCoverageFile(t, repoDir, "src/util.ts", "export const util = 1;\n")
+	writeCoverageFile(t, repoDir, "src/a.ts", "import { util } from './util';\nexport const a = util;\n")
+	writeCoverageFile(t, repoDir, "src/b.ts", "import { util } from './util';\nexport const b = util;\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
@@ -0,0 +1,28 @@
+package check
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the check command.
+var Cmd = NewCommand()
+
+// NewCommand returns a new check command instance.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Run review checks against the changed files",
+		Long: `Run review checks against the files changed in a commit, a range, or the working
+tree, or against the whole tree for the dependency budget.
+
+Examples:
+  clarity check test-coverage
+  clarity check test-coverage -c main...HEAD --strict
+  clarity check budget --baseline budget-baseline.json`,
+	}
+
+	cmd.AddCommand(newTestCoverageCommand())
+	cmd.AddCommand(newBudgetCommand())
+
+	return cmd
+}
@@ -0,0 +1,238 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/analysis"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+type testCoverageOptions struct {
+	repoPath     string
+	commitID     string
+	outputFormat string
+	strict       bool
+}
+
+func newTestCoverageCommand() *cobra.Command {
+	opts := &testCoverageOptions{
+		outputFormat: formatText,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "test-coverage",
+		Short: "List changed files without changed tests, and changed tests without changed subjects",
+		Long: `List the changed files that no changed test file imports, and the changed test files
+that import none of the changed files. Test files are recognized by each language's
+conventions, such as Go's _test.go suffix, Dart's test/ directory, and TypeScript's
+.test and .spec files. Dependencies are read from the whole tree: the head commit
+with --commit, or the working tree otherwise.
+
+Examples:
+  clarity check test-coverage
+  clarity check test-coverage -c main...HEAD --strict --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTestCoverage(cmd, opts)
+		},
+	}
+
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVarP(&opts.commitID, "commit", "c", "", "Git commit or range to check (e.g., f0459ec, HEAD~3, f0459ec...be3d11a); uncommitted changes when empty")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", opts.outputFormat, fmt.Sprintf("Output format (%s)", supportedFormats()))
+	cmd.Flags().BoolVar(&opts.strict, "strict", false, "Exit with an error when a changed file has no changed test")
+
+	return cmd
+}
+
+func runTestCoverage(cmd *cobra.Command, opts *testCoverageOptions) error {
+	if !isSupportedFormat(opts.outputFormat) {
+		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, supportedFormats())
+	}
+
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	repoRoot := repo.RepoRoot
+
+	ctx := cmd.Context()
+	changed, contentReader, err := loadChangeSet(ctx, repoRoot, opts.commitID)
+	if err != nil {
+		return err
+	}
+	if closer, ok := contentReader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	result, err := analysis.Analyzer{
+		RepoPath:      repoRoot,
+		CommitRange:   opts.commitID,
+		WholeTree:     true,
+		ContentReader: contentReader,
+		SkipStats:     true,
+	}.Run(ctx)
+	if err != nil {
+		return err
+	}
+	adjacency, err := depgraph.AdjacencyList(result.Graph.Graph)
+	if err != nil {
+		return err
+	}
+
+	report := buildCoverageReport(repoRoot, adjacency, analysis.SupportedFiles(changed), func(file string) bool {
+		return registry.IsTestFile(file, contentReader)
+	})
+
+	output, err := formatReport(opts.outputFormat, report)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), output)
+
+	if opts.strict && len(report.Untested) > 0 {
+		return fmt.Errorf("%d changed file(s) have no changed test (--strict)", len(report.Untested))
+	}
+	return nil
+}
+
+// loadChangeSet returns the changed files and a reader for the tree they belong to:
+// the head commit of commitID, or the working tree when commitID is empty. git is
+// stopped when ctx is done.
+func loadChangeSet(ctx context.Context, repoRoot, commitID string) ([]string, vcs.ContentReader, error) {
+	if commitID == "" {
+		changed, err := git.GetUncommittedFilesWithOptionsContext(ctx, repoRoot, git.AllUncommittedChanges())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get changed files: %w", err)
+		}
+		return changed, vcs.FilesystemContentReader(), nil
+	}
+
+	fromCommit, toCommit, isCommitRange, err := git.ResolveCommitRangeContext(ctx, repoRoot, commitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var changed []string
+	if isCommitRange {
+		changed, err = git.GetCommitRangeFilesContext(ctx, repoRoot, fromCommit, toCommit)
+	} else {
+		changed, err = git.GetCommitFilesContext(ctx, repoRoot, toCommit)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get changed files: %w", err)
+	}
+	return changed, git.GitCommitContentReaderContext(ctx, repoRoot, toCommit), nil
+}
+
+// coverageReport lists the two review smells test-coverage looks for. Paths are
+// relative to the repository root, use forward slashes, and are sorted.
+type coverageReport struct {
+	// Untested lists the changed files that are not tests and that no changed test
+	// file imports directly.
+	Untested []string `json:"untested"`
+	// OrphanedTests lists the changed test files that import none of the changed files.
+	OrphanedTests []string `json:"orphanedTests"`
+}
+
+// buildCoverageReport checks the changed files against the direct imports in
+// adjacency. isTest reports whether a file is a test file.
+func buildCoverageReport(repoRoot string, adjacency map[string][]string, changed []string, isTest func(string) bool) coverageReport {
+	report := coverageReport{Untested: []string{}, OrphanedTests: []string{}}
+
+	changedSet := make(map[string]bool, len(changed))
+	for _, file := range changed {
+		changedSet[file] = true
+	}
+
+	tested := make(map[string]bool)
+	for _, file := range changed {
+		if !isTest(file) {
+			continue
+		}
+		coversChange := false
+		for _, dep := range adjacency[file] {
+			if changedSet[dep] {
+				tested[dep] = true
+				coversChange = true
+			}
+		}
+		if !coversChange {
+			report.OrphanedTests = append(report.OrphanedTests, relativePath(repoRoot, file))
+		}
+	}
+
+	for _, file := range changed {
+		if !isTest(file) && !tested[file] {
+			report.Untested = append(report.Untested, relativePath(repoRoot, file))
+		}
+	}
+
+	sort.Strings(report.Untested)
+	sort.Strings(report.OrphanedTests)
+	return report
+}
+
+func formatReport(format string, report coverageReport) (string, error) {
+	switch format {
+	case formatText:
+		return formatTextReport(report), nil
+	case formatJSON:
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return string(output) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown format: %s (valid options: %s)", format, supportedFormats())
+	}
+}
+
+func formatTextReport(report coverageReport) string {
+	if len(report.Untested) == 0 && len(report.OrphanedTests) == 0 {
+		return "Every changed file has a changed test, and every changed test imports a changed file.\n"
+	}
+	var b strings.Builder
+	writeGroup(&b, "Changed files without a changed test", report.Untested)
+	writeGroup(&b, "Changed tests without a changed subject", report.OrphanedTests)
+	return b.String()
+}
+
+func writeGroup(b *strings.Builder, heading string, files []string) {
+	fmt.Fprintf(b, "%s (%d):\n", heading, len(files))
+	for _, file := range files {
+		fmt.Fprintf(b, "  %s\n", file)
+	}
+}
+
+func relativePath(repoRoot, file string) string {
+	rel, err := filepath.Rel(repoRoot, file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func isSupportedFormat(format string) bool {
+	return format == formatText || format == formatJSON
+}
+
+func supportedFormats() string {
+	return strings.Join([]string{formatText, formatJSON}, ", ")
+}
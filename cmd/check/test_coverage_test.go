@@ -0,0 +1,140 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCoverage_ListsUntestedFilesAndOrphanedTests(t *testing.T) {
+	repoDir := writeCoverageRepo(t)
+
+	stdout, _, err := runCheckCommand(t, "test-coverage", "-r", repoDir, "-c", "HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, `Changed files without a changed test (1):
+  src/b.ts
+Changed tests without a changed subject (1):
+  src/c.spec.ts
+`, stdout)
+}
+
+func TestTestCoverage_JSONForRange(t *testing.T) {
+	repoDir := writeCoverageRepo(t)
+
+	stdout, _, err := runCheckCommand(t, "test-coverage", "-r", repoDir, "-c", "HEAD~1..HEAD", "--format", "json")
+	require.NoError(t, err)
+
+	var report coverageReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Equal(t, coverageReport{
+		Untested:      []string{"src/b.ts"},
+		OrphanedTests: []string{"src/c.spec.ts"},
+	}, report)
+}
+
+func TestTestCoverage_StrictFailsOnUntestedFiles(t *testing.T) {
+	repoDir := writeCoverageRepo(t)
+
+	stdout, _, err := runCheckCommand(t, "test-coverage", "-r", repoDir, "-c", "HEAD", "--strict")
+
+	require.EqualError(t, err, "1 changed file(s) have no changed test (--strict)")
+	assert.Contains(t, stdout, "src/b.ts")
+}
+
+func TestTestCoverage_StrictIgnoresOrphanedTests(t *testing.T) {
+	repoDir := writeCoverageRepo(t)
+	writeCoverageFile(t, repoDir, "src/c.spec.ts", "import { c } from './c';\ntest('c again', () => c);\n")
+
+	stdout, _, err := runCheckCommand(t, "test-coverage", "-r", repoDir, "--strict")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Changed files without a changed test (0):\nChanged tests without a changed subject (1):\n  src/c.spec.ts\n", stdout)
+}
+
+func TestTestCoverage_UncommittedChangesWithGoTests(t *testing.T) {
+	repoDir := writeCoverageRepo(t)
+	writeCoverageFile(t, repoDir, "go.mod", "module example.com/calc\n\ngo 1.21\n")
+	writeCoverageFile(t, repoDir, "calc/add.go", "package calc\n\nfunc Add(a, b int) int { return a + b }\n")
+	writeCoverageFile(t, repoDir, "calc/add_test.go", "package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) { _ = Add(1, 2) }\n")
+
+	stdout, _, err := runCheckCommand(t, "test-coverage", "-r", repoDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Every changed file has a changed test, and every changed test imports a changed file.\n", stdout)
+}
+
+func TestTestCoverage_RejectsUnknownFormat(t *testing.T) {
+	repoDir := writeCoverageRepo(t)
+
+	_, _, err := runCheckCommand(t, "test-coverage", "-r", repoDir, "--format", "dot")
+	require.ErrorContains(t, err, "unknown format: dot (valid options: text, json)")
+}
+
+func runCheckCommand(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+// writeCoverageRepo creates a repository where each of a.ts, b.ts and c.ts has a test
+// importing it, then commits a change to a.ts and its test, to b.ts alone, and to
+// c.spec.ts alone.
+func writeCoverageRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+
+	writeCoverageFile(t, repoDir, "src/a.ts", "export const a = 1;\n")
+	writeCoverageFile(t, repoDir, "src/a.test.ts", "import { a } from './a';\ntest('a', () => a);\n")
+	writeCoverageFile(t, repoDir, "src/b.ts", "export const b = 1;\n")
+	writeCoverageFile(t, repoDir, "src/b.test.ts", "import { b } from './b';\ntest('b', () => b);\n")
+	writeCoverageFile(t, repoDir, "src/c.ts", "export const c = 1;\n")
+	writeCoverageFile(t, repoDir, "src/c.spec.ts", "import { c } from './c';\ntest('c', () => c);\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+
+	writeCoverageFile(t, repoDir, "src/a.ts", "export const a = 2;\n")
+	writeCoverageFile(t, repoDir, "src/a.test.ts", "import { a } from './a';\ntest('a is 2', () => a);\n")
+	writeCoverageFile(t, repoDir, "src/b.ts", "export const b = 2;\n")
+	writeCoverageFile(t, repoDir, "src/c.spec.ts", "import { c } from './c';\ntest('c is 1', () => c);\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "change a, b and the c spec")
+	return repoDir
+}
+
+func writeCoverageFile(t *testing.T, repoDir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(repoDir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
@@ -2,7 +2,6 @@ package diff
 
 import (
 	"fmt"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -13,45 +12,27 @@ import (
 type SemanticAnalyzer func(base, target depgraph.DependencyGraph, delta graphDelta) ([]string, error)
 
 func buildGraphDelta(base, target depgraph.DependencyGraph) (graphDelta, error) {
-	baseAdj, err := depgraph.AdjacencyList(base)
+	diff, err := depgraph.DiffGraphs(base, target)
 	if err != nil {
-		return graphDelta{}, fmt.Errorf("failed to read base adjacency: %w", err)
-	}
-	targetAdj, err := depgraph.AdjacencyList(target)
-	if err != nil {
-		return graphDelta{}, fmt.Errorf("failed to read target adjacency: %w", err)
+		return graphDelta{}, err
 	}
 
-	baseNodes := collectNodes(baseAdj)
-	targetNodes := collectNodes(targetAdj)
+	return graphDelta{
+		nodesAdded:     diff.AddedNodes,
+		nodesRemoved:   diff.RemovedNodes,
+		edgesAdded:     toGraphEdges(diff.AddedEdges),
+		edgesRemoved:   toGraphEdges(diff.RemovedEdges),
+		edgesUnchanged: toGraphEdges(diff.UnchangedEdges),
+		summary:        diff.Summary(),
+	}, nil
+}
 
-	delta := graphDelta{
-		nodesAdded:   setDifference(targetNodes, baseNodes),
-		nodesRemoved: setDifference(baseNodes, targetNodes),
-		edgesAdded:   edgeDifference(collectEdges(targetAdj), collectEdges(baseAdj)),
-		edgesRemoved: edgeDifference(collectEdges(baseAdj), collectEdges(targetAdj)),
+func toGraphEdges(edges []depgraph.FileEdge) []graphEdge {
+	result := make([]graphEdge, 0, len(edges))
+	for _, edge := range edges {
+		result = append(result, graphEdge{from: edge.From, to: edge.To})
 	}
-
-	sort.Strings(delta.nodesAdded)
-	sort.Strings(delta.nodesRemoved)
-	sort.Slice(delta.edgesAdded, func(i, j int) bool {
-		leftFrom := filepath.Clean(delta.edgesAdded[i].from)
-		rightFrom := filepath.Clean(delta.edgesAdded[j].from)
-		if leftFrom == rightFrom {
-			return filepath.Clean(delta.edgesAdded[i].to) < filepath.Clean(delta.edgesAdded[j].to)
-		}
-		return leftFrom < rightFrom
-	})
-	sort.Slice(delta.edgesRemoved, func(i, j int) bool {
-		leftFrom := filepath.Clean(delta.edgesRemoved[i].from)
-		rightFrom := filepath.Clean(delta.edgesRemoved[j].from)
-		if leftFrom == rightFrom {
-			return filepath.Clean(delta.edgesRemoved[i].to) < filepath.Clean(delta.edgesRemoved[j].to)
-		}
-		return leftFrom < rightFrom
-	})
-
-	return delta, nil
+	return result
 }
 
 func applySemanticAnalyzers(base, target depgraph.DependencyGraph, delta graphDelta, analyzers []SemanticAnalyzer) (graphDelta, error) {
@@ -75,49 +56,11 @@ func applySemanticAnalyzers(base, target depgraph.DependencyGraph, delta graphDe
 	return delta, nil
 }
 
-func collectNodes(adj map[string][]string) map[string]struct{} {
-	nodes := make(map[string]struct{}, len(adj))
-	for from, deps := range adj {
-		nodes[from] = struct{}{}
-		for _, to := range deps {
-			nodes[to] = struct{}{}
-		}
-	}
-	return nodes
-}
-
-func setDifference(left, right map[string]struct{}) []string {
-	result := []string{}
-	for v := range left {
-		if _, ok := right[v]; !ok {
-			result = append(result, v)
-		}
-	}
-	return result
-}
-
-func collectEdges(adj map[string][]string) map[graphEdge]struct{} {
-	edges := make(map[graphEdge]struct{})
-	for from, deps := range adj {
-		for _, to := range deps {
-			edges[graphEdge{from: from, to: to}] = struct{}{}
-		}
-	}
-	return edges
-}
-
-func edgeDifference(left, right map[graphEdge]struct{}) []graphEdge {
-	result := []graphEdge{}
-	for edge := range left {
-		if _, ok := right[edge]; !ok {
-			result = append(result, edge)
-		}
-	}
-	return result
-}
-
 func renderSummary(delta graphDelta) string {
 	var lines []string
+	if delta.summary != "" {
+		lines = append(lines, delta.summary)
+	}
 	lines = append(lines, fmt.Sprintf("Nodes added: %d", len(delta.nodesAdded)))
 	lines = append(lines, delta.nodesAdded...)
 	lines = append(lines, fmt.Sprintf("Nodes removed: %d", len(delta.nodesRemoved)))
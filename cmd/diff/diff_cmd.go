@@ -1,11 +1,13 @@
 package diff
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
 	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 	"github.com/LegacyCodeHQ/clarity/vcs/git"
 	"github.com/spf13/cobra"
 )
@@ -37,10 +39,10 @@ func NewCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repoPath, "repo", "r", "", "Git repository path (default: current directory)")
-	cmd.Flags().StringVarP(&opts.outputFmt, "format", "f", opts.outputFmt, fmt.Sprintf("Output format (%s)", formatters.SupportedFormats()))
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVarP(&opts.outputFmt, "format", "f", opts.outputFmt, fmt.Sprintf("Output format (%s)", supportedFormats()))
 	cmd.Flags().BoolVar(&opts.summary, "summary", false, "Print text summary only")
-	cmd.Flags().StringVarP(&opts.commitSpec, "commit", "c", "", "Compare committed snapshots (<commit> or <A>,<B>)")
+	cmd.Flags().StringVarP(&opts.commitSpec, "commit", "c", "", "Compare committed snapshots (<commit>, <A>,<B>, <A>..<B> or <A>...<B>)")
 
 	// Reserved snapshot selectors for future working-tree controls.
 	cmd.Flags().Bool("staged", false, "Include staged changes")
@@ -54,26 +56,28 @@ func NewCommand() *cobra.Command {
 }
 
 func runDiff(cmd *cobra.Command, opts *diffOptions) error {
-	repoPath := opts.repoPath
-	if repoPath == "" {
-		repoPath = "."
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
 	}
+	repoPath := repo.RepoPath
 
 	comparison, err := resolveModeAndCommitComparison(cmd, repoPath, opts.commitSpec)
 	if err != nil {
 		return err
 	}
 
-	snapshots, err := resolveSnapshots(repoPath, comparison)
+	ctx := cmd.Context()
+	snapshots, err := resolveSnapshots(ctx, repoPath, comparison)
 	if err != nil {
 		return err
 	}
 
-	baseGraph, err := buildGraphFromSnapshot(snapshots.base)
+	baseGraph, err := buildGraphFromSnapshot(ctx, snapshots.base)
 	if err != nil {
 		return fmt.Errorf("failed to build base dependency graph: %w", err)
 	}
-	targetGraph, err := buildGraphFromSnapshot(snapshots.target)
+	targetGraph, err := buildGraphFromSnapshot(ctx, snapshots.target)
 	if err != nil {
 		return fmt.Errorf("failed to build target dependency graph: %w", err)
 	}
@@ -82,7 +86,7 @@ func runDiff(cmd *cobra.Command, opts *diffOptions) error {
 	if err != nil {
 		return err
 	}
-	delta.changedNodes, err = resolveChangedNodes(repoPath, comparison)
+	delta.changedNodes, err = resolveChangedNodes(ctx, repoPath, comparison)
 	if err != nil {
 		return err
 	}
@@ -101,21 +105,23 @@ func runDiff(cmd *cobra.Command, opts *diffOptions) error {
 		return err
 	}
 	fmt.Fprintln(cmd.OutOrStdout(), out)
+	fmt.Fprintln(cmd.ErrOrStderr(), delta.summary)
 
 	return nil
 }
 
-func buildGraphFromSnapshot(s snapshot) (depgraph.DependencyGraph, error) {
+func buildGraphFromSnapshot(ctx context.Context, s snapshot) (depgraph.DependencyGraph, error) {
 	if len(s.filePaths) == 0 {
 		return depgraph.NewDependencyGraph(), nil
 	}
 	if s.contentRead == nil {
 		return nil, fmt.Errorf("content reader is required for non-empty snapshot %q", s.ref)
 	}
-	return depgraph.BuildDependencyGraph(s.filePaths, s.contentRead)
+	graph, _, err := depgraph.BuildDependencyGraphContext(ctx, s.filePaths, s.contentRead, depgraph.BuildOptions{})
+	return graph, err
 }
 
-func resolveChangedNodes(repoPath string, comparison commitComparison) (map[string]struct{}, error) {
+func resolveChangedNodes(ctx context.Context, repoPath string, comparison commitComparison) (map[string]struct{}, error) {
 	var (
 		changed []string
 		err     error
@@ -123,12 +129,12 @@ func resolveChangedNodes(repoPath string, comparison commitComparison) (map[stri
 
 	switch comparison.mode {
 	case diffModeWorkingTree:
-		changed, err = git.GetUncommittedFiles(repoPath)
+		changed, err = git.GetUncommittedFilesWithOptionsContext(ctx, repoPath, git.AllUncommittedChanges())
 	case diffModeCommit:
 		if comparison.baseRef != "" {
-			changed, err = git.GetCommitRangeFiles(repoPath, comparison.baseRef, comparison.targetRef)
+			changed, err = git.GetCommitRangeFilesContext(ctx, repoPath, comparison.baseRef, comparison.targetRef)
 		} else {
-			changed, err = git.GetCommitDartFiles(repoPath, comparison.targetRef)
+			changed, err = git.GetCommitFilesContext(ctx, repoPath, comparison.targetRef)
 		}
 	default:
 		return nil, fmt.Errorf("unknown diff mode: %s", comparison.mode)
@@ -154,6 +160,10 @@ func resolveModeAndCommitComparison(cmd *cobra.Command, repoPath, commitSpec str
 		return commitComparison{}, err
 	}
 
+	if _, _, isRange := git.ParseCommitRange(trimmedCommit); isRange {
+		return resolveCommitRangeComparison(repoPath, trimmedCommit)
+	}
+
 	baseRef, targetRef, err := parseCommitSpec(trimmedCommit)
 	if err != nil {
 		return commitComparison{}, err
@@ -176,6 +186,30 @@ func resolveModeAndCommitComparison(cmd *cobra.Command, repoPath, commitSpec str
 	return commitComparison{baseRef: baseRef, targetRef: targetRef, mode: diffModeCommit}, nil
 }
 
+// resolveCommitRangeComparison compares the two sides of a git range: a two-dot range
+// compares its ends, and a three-dot range starts from their merge-base. Unlike show,
+// a reversed range is kept as given, so the delta reads from left to right.
+func resolveCommitRangeComparison(repoPath, commitSpec string) (commitComparison, error) {
+	from, to, _ := git.ParseCommitRange(commitSpec)
+	if from == "" || to == "" {
+		return commitComparison{}, fmt.Errorf("invalid --commit value %q: both refs are required in a range", commitSpec)
+	}
+	for _, ref := range []string{from, to} {
+		if err := git.ValidateCommit(repoPath, ref); err != nil {
+			return commitComparison{}, err
+		}
+	}
+
+	if git.IsMergeBaseRange(commitSpec) {
+		mergeBase, err := git.GetMergeBase(repoPath, from, to)
+		if err != nil {
+			return commitComparison{}, fmt.Errorf("failed to find merge-base of commit range: %w", err)
+		}
+		from = mergeBase
+	}
+	return commitComparison{baseRef: from, targetRef: to, mode: diffModeCommit}, nil
+}
+
 func validateCommitModeConflicts(cmd *cobra.Command) error {
 	for _, flagName := range snapshotSelectorFlags {
 		flag := cmd.Flags().Lookup(flagName)
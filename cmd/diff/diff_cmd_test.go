@@ -166,6 +166,110 @@ func TestRenderDelta_Mermaid_UnchangedNodesUseDottedBorder(t *testing.T) {
 	}
 }
 
+func TestRenderDelta_Mermaid_StylesEdgesByChange(t *testing.T) {
+	delta := graphDelta{
+		nodesAdded: []string{"/repo/new.go"},
+		edgesAdded: []graphEdge{{from: "/repo/a.go", to: "/repo/new.go"}},
+		edgesRemoved: []graphEdge{
+			{from: "/repo/a.go", to: "/repo/b.go"},
+		},
+		edgesUnchanged: []graphEdge{
+			{from: "/repo/b.go", to: "/repo/a.go"},
+			{from: "/repo/b.go", to: "/repo/new.go"},
+			{from: "/repo/x.go", to: "/repo/y.go"},
+		},
+		summary: "+1 edge, -1 edge; +1 file, -0 files",
+	}
+
+	out, err := renderDelta("mermaid", delta)
+	if err != nil {
+		t.Fatalf("renderDelta(mermaid) error = %v", err)
+	}
+	for _, want := range []string{
+		"title: \"+1 edge, -1 edge; +1 file, -0 files\"",
+		"linkStyle 0,1 stroke:#9aa0a6",
+		"linkStyle 2 stroke:#2e8b57",
+		"linkStyle 3 stroke:#b22222",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "x.go") {
+		t.Fatalf("expected unchanged edges between unrelated files to be left out, got:\n%s", out)
+	}
+}
+
+func TestDiffCommand_CommitRangeStylesEdgeChanges(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeDiffFiles(t, repoDir, map[string]string{
+		"a.ts":   "import { b } from './b';\nimport { c } from './c';\nexport const a = b + c;\n",
+		"b.ts":   "import { c } from './c';\nexport const b = c;\n",
+		"c.ts":   "export const c = 1;\n",
+		"old.ts": "import { c } from './c';\nexport const old = c;\n",
+	})
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "base")
+	base := strings.TrimSpace(gitOutput(t, repoDir, "rev-parse", "HEAD"))
+
+	gitRun(t, repoDir, "rm", "-q", "old.ts")
+	writeDiffFiles(t, repoDir, map[string]string{
+		"a.ts": "import { b } from './b';\nexport const a = b;\n",
+		"b.ts": "import { c } from './c';\nimport { d } from './d';\nexport const b = c + d;\n",
+		"d.ts": "export const d = 1;\n",
+	})
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "target")
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"--repo", repoDir, "-c", base + "...HEAD"})
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	node := func(name string) string { return `"` + filepath.Join(repoDir, name) + `"` }
+	out := stdout.String()
+	for _, want := range []string{
+		`label="+1 edge, -2 edges; +1 file, -1 file";`,
+		node("a.ts") + " -> " + node("b.ts") + ` [color="#9aa0a6"];`,
+		node("b.ts") + " -> " + node("d.ts") + ` [color="#2e8b57", style=dashed];`,
+		node("a.ts") + " -> " + node("c.ts") + ` [color="#b22222", style=dashed];`,
+		node("old.ts") + " -> " + node("c.ts") + ` [color="#b22222", style=dashed];`,
+		node("old.ts") + ` [label="old.ts", style=filled, fillcolor="#f8d7da", color="#b22222"];`,
+		node("d.ts") + ` [label="d.ts", style=filled, fillcolor="#d9f2d9", color="#2e8b57"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in output:\n%s", want, out)
+		}
+	}
+	if strings.TrimSpace(stderr.String()) != "+1 edge, -2 edges; +1 file, -1 file" {
+		t.Fatalf("expected summary on stderr, got %q", stderr.String())
+	}
+}
+
+func TestDiffCommitRange_RejectsMissingSide(t *testing.T) {
+	repoDir, head := initGitRepoWithSingleCommit(t)
+
+	_, err := resolveModeAndCommitComparison(NewCommand(), repoDir, head+"..")
+	if err == nil || !strings.Contains(err.Error(), "both refs are required in a range") {
+		t.Fatalf("expected missing ref error, got %v", err)
+	}
+}
+
+func writeDiffFiles(t *testing.T, repoDir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+}
+
 func initGitRepoWithSingleCommit(t *testing.T) (repoDir string, commit string) {
 	t.Helper()
 
@@ -2,6 +2,7 @@ package diff
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
 )
@@ -15,11 +16,17 @@ type dotDiffFormatter struct{}
 
 type mermaidDiffFormatter struct{}
 
+// supportedFormats lists the show output formats diff can render; deltas have no JSON
+// form yet.
+func supportedFormats() string {
+	return strings.Join([]string{formatters.OutputFormatDOT.String(), formatters.OutputFormatMermaid.String()}, ", ")
+}
+
 // NewDiffFormatter constructs a formatter for the requested output format.
 func NewDiffFormatter(format string) (Formatter, error) {
 	parsed, ok := formatters.ParseOutputFormat(format)
 	if !ok {
-		return nil, fmt.Errorf("unknown format: %s (valid options: %s)", format, formatters.SupportedFormats())
+		return nil, fmt.Errorf("unknown format: %s (valid options: %s)", format, supportedFormats())
 	}
 
 	switch parsed {
@@ -28,6 +35,6 @@ func NewDiffFormatter(format string) (Formatter, error) {
 	case formatters.OutputFormatMermaid:
 		return mermaidDiffFormatter{}, nil
 	default:
-		return nil, fmt.Errorf("unknown format: %s (valid options: %s)", format, formatters.SupportedFormats())
+		return nil, fmt.Errorf("unknown format: %s (valid options: %s)", format, supportedFormats())
 	}
 }
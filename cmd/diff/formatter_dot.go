@@ -15,6 +15,10 @@ func renderDeltaDOT(delta graphDelta) string {
 	b.WriteString("digraph diff {\n")
 	b.WriteString("  rankdir=LR;\n")
 	b.WriteString("  node [shape=box];\n")
+	if delta.summary != "" {
+		b.WriteString(fmt.Sprintf("  label=%q;\n", delta.summary))
+		b.WriteString("  labelloc=t;\n")
+	}
 
 	changedNodes := sortedChangedNodes(delta.changedNodes)
 	for _, n := range changedNodes {
@@ -27,8 +31,11 @@ func renderDeltaDOT(delta graphDelta) string {
 		b.WriteString(fmt.Sprintf("  %q [label=%q, style=filled, fillcolor=\"#f8d7da\", color=\"#b22222\"];\n", n, filepath.Base(n)))
 	}
 
+	for _, e := range contextEdges(delta) {
+		b.WriteString(fmt.Sprintf("  %q -> %q [color=\"#9aa0a6\"];\n", e.from, e.to))
+	}
 	for _, e := range delta.edgesAdded {
-		b.WriteString(fmt.Sprintf("  %q -> %q [color=\"#2e8b57\"];\n", e.from, e.to))
+		b.WriteString(fmt.Sprintf("  %q -> %q [color=\"#2e8b57\", style=dashed];\n", e.from, e.to))
 	}
 	for _, e := range delta.edgesRemoved {
 		b.WriteString(fmt.Sprintf("  %q -> %q [color=\"#b22222\", style=dashed];\n", e.from, e.to))
@@ -13,3 +13,31 @@ func sortedChangedNodes(changed map[string]struct{}) []string {
 	sort.Strings(nodes)
 	return nodes
 }
+
+// contextEdges returns the unchanged edges of delta between files the delta already
+// shows: changed, added and removed files and the ends of added and removed edges.
+func contextEdges(delta graphDelta) []graphEdge {
+	shown := make(map[string]bool)
+	for n := range delta.changedNodes {
+		shown[n] = true
+	}
+	for _, nodes := range [][]string{delta.nodesAdded, delta.nodesRemoved} {
+		for _, n := range nodes {
+			shown[n] = true
+		}
+	}
+	for _, edges := range [][]graphEdge{delta.edgesAdded, delta.edgesRemoved} {
+		for _, e := range edges {
+			shown[e.from] = true
+			shown[e.to] = true
+		}
+	}
+
+	var edges []graphEdge
+	for _, e := range delta.edgesUnchanged {
+		if shown[e.from] && shown[e.to] {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -13,6 +14,10 @@ func (mermaidDiffFormatter) Format(delta graphDelta) (string, error) {
 
 func renderDeltaMermaid(delta graphDelta) string {
 	var b strings.Builder
+	if delta.summary != "" {
+		// A Go-quoted ASCII string is a valid YAML double-quoted scalar.
+		b.WriteString(fmt.Sprintf("---\ntitle: %s\n---\n", strconv.Quote(delta.summary)))
+	}
 	b.WriteString("flowchart LR\n")
 
 	nodeIDs := make(map[string]string)
@@ -26,35 +31,46 @@ func renderDeltaMermaid(delta graphDelta) string {
 		b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", id, filepath.Base(n)))
 	}
 
-	for _, e := range delta.edgesAdded {
-		fromID := nodeIDs[e.from]
-		if fromID == "" {
-			fromID = fmt.Sprintf("anon_%d", len(nodeIDs))
-			b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", fromID, filepath.Base(e.from)))
-			nodeIDs[e.from] = fromID
-		}
-		toID := nodeIDs[e.to]
-		if toID == "" {
-			toID = fmt.Sprintf("anon_%d", len(nodeIDs))
-			b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", toID, filepath.Base(e.to)))
-			nodeIDs[e.to] = toID
+	nodeID := func(path string) string {
+		if id := nodeIDs[path]; id != "" {
+			return id
 		}
+		id := fmt.Sprintf("anon_%d", len(nodeIDs))
+		b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", id, filepath.Base(path)))
+		nodeIDs[path] = id
+		return id
+	}
+
+	// Mermaid styles links by their position, so each kind of edge records the
+	// indices it occupies.
+	var unchangedLinks, addedLinks, removedLinks []string
+	link := 0
+	for _, e := range contextEdges(delta) {
+		fromID, toID := nodeID(e.from), nodeID(e.to)
 		b.WriteString(fmt.Sprintf("    %s --> %s\n", fromID, toID))
+		unchangedLinks = append(unchangedLinks, strconv.Itoa(link))
+		link++
+	}
+	for _, e := range delta.edgesAdded {
+		fromID, toID := nodeID(e.from), nodeID(e.to)
+		b.WriteString(fmt.Sprintf("    %s -.-> %s\n", fromID, toID))
+		addedLinks = append(addedLinks, strconv.Itoa(link))
+		link++
 	}
 	for _, e := range delta.edgesRemoved {
-		fromID := nodeIDs[e.from]
-		if fromID == "" {
-			fromID = fmt.Sprintf("anon_%d", len(nodeIDs))
-			b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", fromID, filepath.Base(e.from)))
-			nodeIDs[e.from] = fromID
-		}
-		toID := nodeIDs[e.to]
-		if toID == "" {
-			toID = fmt.Sprintf("anon_%d", len(nodeIDs))
-			b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", toID, filepath.Base(e.to)))
-			nodeIDs[e.to] = toID
-		}
+		fromID, toID := nodeID(e.from), nodeID(e.to)
 		b.WriteString(fmt.Sprintf("    %s -.-> %s\n", fromID, toID))
+		removedLinks = append(removedLinks, strconv.Itoa(link))
+		link++
+	}
+	if len(unchangedLinks) > 0 {
+		b.WriteString(fmt.Sprintf("    linkStyle %s stroke:#9aa0a6\n", strings.Join(unchangedLinks, ",")))
+	}
+	if len(addedLinks) > 0 {
+		b.WriteString(fmt.Sprintf("    linkStyle %s stroke:#2e8b57\n", strings.Join(addedLinks, ",")))
+	}
+	if len(removedLinks) > 0 {
+		b.WriteString(fmt.Sprintf("    linkStyle %s stroke:#b22222\n", strings.Join(removedLinks, ",")))
 	}
 
 	if len(delta.changedNodes) > 0 {
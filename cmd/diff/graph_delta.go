@@ -10,6 +10,12 @@ type graphDelta struct {
 	nodesRemoved []string
 	edgesAdded   []graphEdge
 	edgesRemoved []graphEdge
-	findings     []string
-	changedNodes map[string]struct{}
+	// edgesUnchanged are the edges in both snapshots. Renderers draw those between
+	// files the delta shows, as context.
+	edgesUnchanged []graphEdge
+	findings       []string
+	changedNodes   map[string]struct{}
+	// summary counts the added and removed edges and files, as in "+4 edges, -2
+	// edges; +1 file, -0 files".
+	summary string
 }
@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"sort"
@@ -22,29 +23,29 @@ type snapshotPair struct {
 	target snapshot
 }
 
-func resolveSnapshots(repoPath string, comparison commitComparison) (snapshotPair, error) {
+func resolveSnapshots(ctx context.Context, repoPath string, comparison commitComparison) (snapshotPair, error) {
 	switch comparison.mode {
 	case diffModeCommit:
-		return resolveCommitModeSnapshots(repoPath, comparison)
+		return resolveCommitModeSnapshots(ctx, repoPath, comparison)
 	case diffModeWorkingTree:
-		return resolveWorkingTreeSnapshots(repoPath)
+		return resolveWorkingTreeSnapshots(ctx, repoPath)
 	default:
 		return snapshotPair{}, fmt.Errorf("unknown diff mode: %s", comparison.mode)
 	}
 }
 
-func resolveWorkingTreeSnapshots(repoPath string) (snapshotPair, error) {
+func resolveWorkingTreeSnapshots(ctx context.Context, repoPath string) (snapshotPair, error) {
 	baseRef := "HEAD"
 	if err := git.ValidateCommit(repoPath, baseRef); err != nil {
 		return snapshotPair{}, err
 	}
 
-	baseFiles, err := git.GetCommitTreeFiles(repoPath, baseRef)
+	baseFiles, err := git.GetCommitTreeFilesContext(ctx, repoPath, baseRef)
 	if err != nil {
 		return snapshotPair{}, fmt.Errorf("failed to load base snapshot from %s: %w", baseRef, err)
 	}
 
-	targetFiles, err := loadWorkingSnapshotFiles(repoPath)
+	targetFiles, err := loadWorkingSnapshotFiles(ctx, repoPath)
 	if err != nil {
 		return snapshotPair{}, fmt.Errorf("failed to load working snapshot: %w", err)
 	}
@@ -54,7 +55,7 @@ func resolveWorkingTreeSnapshots(repoPath string) (snapshotPair, error) {
 		base: snapshot{
 			ref:         baseRef,
 			filePaths:   baseFiles,
-			contentRead: git.GitCommitContentReader(repoPath, baseRef),
+			contentRead: git.GitCommitContentReaderContext(ctx, repoPath, baseRef),
 		},
 		target: snapshot{
 			ref:         "WORKING_TREE",
@@ -64,13 +65,13 @@ func resolveWorkingTreeSnapshots(repoPath string) (snapshotPair, error) {
 	}, nil
 }
 
-func resolveCommitModeSnapshots(repoPath string, comparison commitComparison) (snapshotPair, error) {
+func resolveCommitModeSnapshots(ctx context.Context, repoPath string, comparison commitComparison) (snapshotPair, error) {
 	if comparison.baseRef != "" {
-		baseFiles, err := git.GetCommitTreeFiles(repoPath, comparison.baseRef)
+		baseFiles, err := git.GetCommitTreeFilesContext(ctx, repoPath, comparison.baseRef)
 		if err != nil {
 			return snapshotPair{}, fmt.Errorf("failed to load base snapshot from %s: %w", comparison.baseRef, err)
 		}
-		targetFiles, err := git.GetCommitTreeFiles(repoPath, comparison.targetRef)
+		targetFiles, err := git.GetCommitTreeFilesContext(ctx, repoPath, comparison.targetRef)
 		if err != nil {
 			return snapshotPair{}, fmt.Errorf("failed to load target snapshot from %s: %w", comparison.targetRef, err)
 		}
@@ -80,12 +81,12 @@ func resolveCommitModeSnapshots(repoPath string, comparison commitComparison) (s
 			base: snapshot{
 				ref:         comparison.baseRef,
 				filePaths:   baseFiles,
-				contentRead: git.GitCommitContentReader(repoPath, comparison.baseRef),
+				contentRead: git.GitCommitContentReaderContext(ctx, repoPath, comparison.baseRef),
 			},
 			target: snapshot{
 				ref:         comparison.targetRef,
 				filePaths:   targetFiles,
-				contentRead: git.GitCommitContentReader(repoPath, comparison.targetRef),
+				contentRead: git.GitCommitContentReaderContext(ctx, repoPath, comparison.targetRef),
 			},
 		}, nil
 	}
@@ -95,21 +96,21 @@ func resolveCommitModeSnapshots(repoPath string, comparison commitComparison) (s
 		return snapshotPair{}, fmt.Errorf("failed to resolve base snapshot for %s: %w", comparison.targetRef, err)
 	}
 
-	targetFiles, err := git.GetCommitTreeFiles(repoPath, comparison.targetRef)
+	targetFiles, err := git.GetCommitTreeFilesContext(ctx, repoPath, comparison.targetRef)
 	if err != nil {
 		return snapshotPair{}, fmt.Errorf("failed to load target snapshot from %s: %w", comparison.targetRef, err)
 	}
 
 	base := snapshot{ref: "EMPTY", filePaths: nil, contentRead: nil}
 	if hasParent {
-		baseFiles, err := git.GetCommitTreeFiles(repoPath, firstParent)
+		baseFiles, err := git.GetCommitTreeFilesContext(ctx, repoPath, firstParent)
 		if err != nil {
 			return snapshotPair{}, fmt.Errorf("failed to load base snapshot from %s: %w", firstParent, err)
 		}
 		base = snapshot{
 			ref:         firstParent,
 			filePaths:   baseFiles,
-			contentRead: git.GitCommitContentReader(repoPath, firstParent),
+			contentRead: git.GitCommitContentReaderContext(ctx, repoPath, firstParent),
 		}
 	}
 
@@ -119,17 +120,17 @@ func resolveCommitModeSnapshots(repoPath string, comparison commitComparison) (s
 		target: snapshot{
 			ref:         comparison.targetRef,
 			filePaths:   targetFiles,
-			contentRead: git.GitCommitContentReader(repoPath, comparison.targetRef),
+			contentRead: git.GitCommitContentReaderContext(ctx, repoPath, comparison.targetRef),
 		},
 	}, nil
 }
 
-func loadWorkingSnapshotFiles(repoPath string) ([]string, error) {
-	tracked, err := git.ListTrackedFiles(repoPath)
+func loadWorkingSnapshotFiles(ctx context.Context, repoPath string) ([]string, error) {
+	tracked, err := git.ListTrackedFilesContext(ctx, repoPath)
 	if err != nil {
 		return nil, err
 	}
-	untracked, err := git.ListUntrackedFiles(repoPath)
+	untracked, err := git.ListUntrackedFilesContext(ctx, repoPath)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,149 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+const (
+	filesFormatText = "text"
+	filesFormatJSON = "json"
+)
+
+// fileEntry is one file in the JSON output of the files command.
+type fileEntry struct {
+	Path     string               `json:"path"`
+	Language string               `json:"language,omitempty"`
+	Reason   show.SelectionReason `json:"reason"`
+	Stats    *fileEntryStats      `json:"stats,omitempty"`
+}
+
+type fileEntryStats struct {
+	Additions int  `json:"additions"`
+	Deletions int  `json:"deletions"`
+	New       bool `json:"new"`
+	Binary    bool `json:"binary,omitempty"`
+}
+
+// NewCommand returns a new files command instance.
+func NewCommand() *cobra.Command {
+	var selection *show.Selection
+	format := filesFormatText
+	noStats := false
+
+	cmd := &cobra.Command{
+		Use:   "files",
+		Short: "List the files show would analyze",
+		Long: `List the files show would analyze for the same selection flags, without building
+a graph. Paths are repo-relative, one per line; --format json adds each file's
+language, change stats, and why it was selected (changed, untracked, included, or
+context).`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFiles(cmd, selection, format, noStats)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", format, fmt.Sprintf("Output format (%s, %s)", filesFormatText, filesFormatJSON))
+	selection = show.AddSelection(cmd)
+	cmd.Flags().BoolVar(&noStats, "no-stats", false, "Skip file addition/deletion statistics in json output")
+
+	return cmd
+}
+
+func runFiles(cmd *cobra.Command, selection *show.Selection, format string, noStats bool) (err error) {
+	if format != filesFormatText && format != filesFormatJSON {
+		return fmt.Errorf("unknown format: %s (valid options: %s, %s)", format, filesFormatText, filesFormatJSON)
+	}
+
+	selected, err := selection.Collect(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := selected.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	filePaths := append([]string(nil), selected.Files...)
+	sort.Strings(filePaths)
+
+	if format == filesFormatText {
+		for _, filePath := range filePaths {
+			fmt.Fprintln(cmd.OutOrStdout(), selected.RelativePath(filePath))
+		}
+		return nil
+	}
+
+	entries, err := buildFileEntries(cmd, selection, selected, filePaths, noStats)
+	if err != nil {
+		return err
+	}
+	output, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode files: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(output))
+	return nil
+}
+
+// buildFileEntries describes each selected file for the JSON output.
+func buildFileEntries(cmd *cobra.Command, selection *show.Selection, selected *show.SelectedFiles, filePaths []string, noStats bool) ([]fileEntry, error) {
+	entries := make([]fileEntry, 0, len(filePaths))
+	if selected.Clean {
+		return entries, nil
+	}
+
+	untracked := make(map[string]bool)
+	if selected.Reason == show.ReasonChanged && selection.CommitRange() == "" {
+		files, err := git.ListUntrackedFilesContext(cmd.Context(), selected.RepoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list untracked files: %w", err)
+		}
+		for _, file := range files {
+			untracked[file] = true
+		}
+	}
+
+	var fileStats map[string]vcs.FileStats
+	if !noStats {
+		fileStats = selected.FileStats(cmd)
+	}
+
+	detector := depgraph.NewLanguageDetector(selected.ContentReader)
+	for _, filePath := range filePaths {
+		entry := fileEntry{
+			Path:   selected.RelativePath(filePath),
+			Reason: selected.Reason,
+		}
+		if untracked[filePath] {
+			entry.Reason = show.ReasonUntracked
+		}
+		if module, ok := registry.ModuleForExtension(detector.Extension(filePath)); ok {
+			entry.Language = module.Name()
+		}
+		if stats, ok := fileStats[filePath]; ok {
+			entry.Stats = &fileEntryStats{
+				Additions: stats.Additions,
+				Deletions: stats.Deletions,
+				New:       stats.IsNew,
+				Binary:    stats.IsBinary,
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,309 @@
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show"
+)
+
+func runFilesCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+var dotNodePattern = regexp.MustCompile(`(?m)^  "([^"]+)" \[label=`)
+
+// showNodes returns the sorted node IDs of a dot show run with args.
+func showNodes(t *testing.T, args ...string) []string {
+	t.Helper()
+	cmd := show.NewCommand()
+	cmd.SetArgs(append(args, "-f", "dot", "--no-stats", "--render-limit", "0"))
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("show %v error = %v", args, err)
+	}
+	var nodes []string
+	for _, match := range dotNodePattern.FindAllStringSubmatch(stdout.String(), -1) {
+		nodes = append(nodes, match[1])
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// writeFilesTestRepo commits a Go module whose main.go imports legacy and util, and
+// a README.
+func writeFilesTestRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeRepoFiles(t, repoDir, map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.21\n",
+		"main.go":      "package main\n\nimport (\n\t\"example.com/app/legacy\"\n\t\"example.com/app/util\"\n)\n\nfunc main() { legacy.Open(); util.Do() }\n",
+		"legacy/db.go": "package legacy\n\nfunc Open() {}\n",
+		"util/util.go": "package util\n\nfunc Do() {}\n",
+		"README.md":    "# app\n",
+	})
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
+
+func TestFilesCommand_MatchesShowNodes(t *testing.T) {
+	repoDir := writeFilesTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repoDir, "util", "util.go"), []byte("package util\n\nfunc Do() { Other() }\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "util", "other.go"), []byte("package util\n\nfunc Other() {}\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"-r", repoDir},
+		{"-r", repoDir, "-i", "."},
+		{"-r", repoDir, "-i", ".", "--exclude", "legacy"},
+		{"-r", repoDir, "-i", ".", "--include-ext", ".go"},
+		{"-r", repoDir, "-c", "HEAD"},
+	} {
+		t.Run(strings.Join(args[2:], " "), func(t *testing.T) {
+			output, err := runFilesCommand(t, args...)
+			if err != nil {
+				t.Fatalf("files %v error = %v", args, err)
+			}
+			got := strings.Fields(output)
+			want := showNodes(t, args...)
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Fatalf("files = %v, show nodes = %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFilesCommand_JSONReportsLanguageStatsAndReason(t *testing.T) {
+	repoDir := writeFilesTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repoDir, "util", "util.go"), []byte("package util\n\nfunc Do() {}\n\nfunc Undo() {}\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("todo\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, err := runFilesCommand(t, "-r", repoDir, "-f", "json")
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+	var entries []fileEntry
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+
+	want := []fileEntry{
+		{Path: "notes.txt", Reason: show.ReasonUntracked, Stats: &fileEntryStats{Additions: 1, New: true}},
+		{Path: "util/util.go", Language: "Go", Reason: show.ReasonChanged, Stats: &fileEntryStats{Additions: 2}},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i].Path != want[i].Path || entries[i].Language != want[i].Language || entries[i].Reason != want[i].Reason {
+			t.Fatalf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+		if entries[i].Stats == nil || *entries[i].Stats != *want[i].Stats {
+			t.Fatalf("entry %d stats = %+v, want %+v", i, entries[i].Stats, want[i].Stats)
+		}
+	}
+}
+
+func TestFilesCommand_JSONMarksInputsIncluded(t *testing.T) {
+	repoDir := writeFilesTestRepo(t)
+
+	output, err := runFilesCommand(t, "-r", repoDir, "-i", "util", "-f", "json", "--no-stats")
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+	want := `[
+  {
+    "path": "util/util.go",
+    "language": "Go",
+    "reason": "included"
+  }
+]
+`
+	if output != want {
+		t.Fatalf("files output =\n%s\nwant\n%s", output, want)
+	}
+}
+
+func TestFilesCommand_CleanWorkingDirectoryPrintsNothing(t *testing.T) {
+	repoDir := writeFilesTestRepo(t)
+
+	output, err := runFilesCommand(t, "-r", repoDir)
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+	if output != "" {
+		t.Fatalf("expected no output for a clean working directory, got:\n%s", output)
+	}
+}
+
+func TestFilesCommand_UnknownFormat(t *testing.T) {
+	_, err := runFilesCommand(t, "-f", "yaml")
+	if err == nil || !strings.Contains(err.Error(), "unknown format: yaml (valid options: text, json)") {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+}
+
+// writeGlobTestRepo creates nested Go and TypeScript sources with generated files
+// alongside them.
+func writeGlobTestRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	writeRepoFiles(t, repoDir, map[string]string{
+		"main.go":                         "package main\n",
+		"api/v1/service.go":               "package v1\n",
+		"api/v1/service_generated.go":     "package v1\n",
+		"api/v2/deep/types_generated.go":  "package deep\n",
+		"src/app/index.ts":                "export const app = 1;\n",
+		"src/app/widgets/button.tsx":      "export const button = 1;\n",
+		"src/app/widgets/button.test.tsx": "export const test = 1;\n",
+		"scripts/build.ts":                "export const build = 1;\n",
+	})
+	return repoDir
+}
+
+func TestGraphInput_Exclude_GlobMatchesNestedDirectories(t *testing.T) {
+	repoDir := writeGlobTestRepo(t)
+
+	output, err := runFilesCommand(t, "-r", repoDir, "-i", ".", "--exclude", "**/*_generated.go,scripts/")
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+
+	want := "api/v1/service.go\nmain.go\nsrc/app/index.ts\nsrc/app/widgets/button.test.tsx\nsrc/app/widgets/button.tsx\n"
+	if output != want {
+		t.Fatalf("files = %q, want %q", output, want)
+	}
+}
+
+func TestGraphInput_IncludeGlob_WithBraces(t *testing.T) {
+	repoDir := writeGlobTestRepo(t)
+
+	output, err := runFilesCommand(t, "-r", repoDir, "-i", ".", "--include-glob", `"src/**/*.{ts,tsx}"`)
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+
+	want := "src/app/index.ts\nsrc/app/widgets/button.test.tsx\nsrc/app/widgets/button.tsx\n"
+	if output != want {
+		t.Fatalf("files = %q, want %q", output, want)
+	}
+}
+
+func TestGraphInput_IncludeGlobAndExcludeGlob_ExcludeWins(t *testing.T) {
+	repoDir := writeGlobTestRepo(t)
+
+	output, err := runFilesCommand(t, "-r", repoDir, "-i", ".",
+		"--include-glob", "src/**",
+		"--exclude", "**/*.test.tsx",
+		"--include-ext", ".tsx")
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+
+	if output != "src/app/widgets/button.tsx\n" {
+		t.Fatalf("files = %q, want only button.tsx", output)
+	}
+}
+
+func TestGraphInput_IncludeGlob_NoMatches_ReturnsError(t *testing.T) {
+	repoDir := writeGlobTestRepo(t)
+
+	_, err := runFilesCommand(t, "-r", repoDir, "-i", ".", "--include-glob", "lib/**")
+	if err == nil || !strings.Contains(err.Error(), "no files remain after applying --include-glob") {
+		t.Fatalf("expected include-glob error, got: %v", err)
+	}
+}
+
+func TestGraphInput_Exclude_MalformedGlob_ReportsPosition(t *testing.T) {
+	repoDir := writeGlobTestRepo(t)
+
+	_, err := runFilesCommand(t, "-r", repoDir, "-i", ".", "--exclude", "api/[v")
+	if err == nil || !strings.Contains(err.Error(), `--exclude: invalid pattern "api/[v" at offset 4`) {
+		t.Fatalf("expected malformed pattern error, got: %v", err)
+	}
+}
+
+func TestGraph_CommitIncludeExtKeepsOnlyMatchingChangedFiles(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeRepoFiles(t, repoDir, map[string]string{
+		"main.go":   "package main\n",
+		"README.md": "# readme\n",
+		"web/a.ts":  "export const a = 1;\n",
+	})
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "mixed")
+
+	output, err := runFilesCommand(t, "-r", repoDir, "-c", "HEAD", "--include-ext", ".ts")
+	if err != nil {
+		t.Fatalf("files error = %v", err)
+	}
+	if output != "web/a.ts\n" {
+		t.Fatalf("files = %q, want only web/a.ts", output)
+	}
+
+	_, err = runFilesCommand(t, "-r", repoDir, "-c", "HEAD", "--include-ext", ".rs")
+	if err == nil || !strings.Contains(err.Error(), `no files remain after applying --include-ext ".rs"`) {
+		t.Fatalf("expected include-ext error, got: %v", err)
+	}
+}
+
+func writeRepoFiles(t *testing.T, repoDir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+}
+
+func gitInitRepo(t *testing.T, repoDir string) {
+	t.Helper()
+
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
@@ -0,0 +1,188 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+const (
+	formatText = "text"
+	formatDOT  = "dot"
+)
+
+// defaultLimit is the number of commits listed when --limit is not given.
+const defaultLimit = 20
+
+type historyOptions struct {
+	repoPath     string
+	file         string
+	limit        int
+	outputFormat string
+}
+
+// Cmd represents the history command.
+var Cmd = NewCommand()
+
+// NewCommand returns a new history command instance.
+func NewCommand() *cobra.Command {
+	opts := &historyOptions{
+		limit:        defaultLimit,
+		outputFormat: formatText,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the commits that changed a file and the files changed with it",
+		Long: `List the most recent commits that changed a file, following it across renames,
+together with the other files each commit changed. With --format dot, draw the
+co-change graph instead: an edge joins two files changed in the same commit, and its
+weight is the number of such commits.
+
+Examples:
+  clarity history --file src/app.ts
+  clarity history --file src/app.ts --limit 50 --format dot`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(cmd, opts)
+		},
+	}
+
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVar(&opts.file, "file", "", "File whose history is listed, relative to --repo")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", opts.limit, "Number of most recent commits to read (0 = all)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", opts.outputFormat, fmt.Sprintf("Output format (%s)", supportedFormats()))
+
+	return cmd
+}
+
+func runHistory(cmd *cobra.Command, opts *historyOptions) error {
+	if opts.outputFormat != formatText && opts.outputFormat != formatDOT {
+		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, supportedFormats())
+	}
+	if opts.file == "" {
+		return fmt.Errorf("--file is required")
+	}
+	if opts.limit < 0 {
+		return fmt.Errorf("--limit must be at least 0")
+	}
+
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	file, err := repoRelativePath(repo, opts.file)
+	if err != nil {
+		return err
+	}
+
+	commits, err := git.GetCommitsTouchingFile(repo.RepoRoot, file, opts.limit)
+	if err != nil {
+		return fmt.Errorf("failed to read the history of %s: %w", file, err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits change %s", file)
+	}
+
+	if opts.outputFormat == formatDOT {
+		fmt.Fprint(cmd.OutOrStdout(), formatCoChangeDOT(file, commits))
+		return nil
+	}
+	return writeHistoryTable(cmd, file, commits)
+}
+
+// repoRelativePath returns file, given relative to the --repo directory or absolute,
+// relative to the repository root with forward slashes.
+func repoRelativePath(repo cliconfig.Context, file string) (string, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(repo.RepoPath, file)
+	}
+	rel, err := filepath.Rel(repo.RepoRoot, filepath.Clean(file))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("--file %s is outside the repository %s", file, repo.RepoRoot)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func writeHistoryTable(cmd *cobra.Command, file string, commits []git.FileCommit) error {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%s: %d commit(s)\n", file, len(commits))
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "COMMIT\tDATE\tPATH\tCHANGED WITH")
+	for _, commit := range commits {
+		coChanged := "-"
+		if len(commit.CoChanged) > 0 {
+			coChanged = strings.Join(commit.CoChanged, ", ")
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", shortSHA(commit.SHA), commit.Date.Format("2006-01-02"), commit.Path, coChanged)
+	}
+	return writer.Flush()
+}
+
+// coChangePair is two files, in sorted order, changed in the same commit.
+type coChangePair struct {
+	a, b string
+}
+
+// coChangeCounts counts, for every pair of files changed in the same commit, the
+// commits that changed both. Commits made before a rename count towards file.
+func coChangeCounts(file string, commits []git.FileCommit) map[coChangePair]int {
+	counts := make(map[coChangePair]int)
+	for _, commit := range commits {
+		files := append([]string{file}, commit.CoChanged...)
+		sort.Strings(files)
+		for i := range files {
+			for j := i + 1; j < len(files); j++ {
+				if files[i] != files[j] {
+					counts[coChangePair{a: files[i], b: files[j]}]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// formatCoChangeDOT draws the co-change graph of the commits as an undirected DOT
+// graph. file is highlighted, and edges are labeled and weighted by their count.
+func formatCoChangeDOT(file string, commits []git.FileCommit) string {
+	counts := coChangeCounts(file, commits)
+	pairs := make([]coChangePair, 0, len(counts))
+	for pair := range counts {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].a != pairs[j].a {
+			return pairs[i].a < pairs[j].a
+		}
+		return pairs[i].b < pairs[j].b
+	})
+
+	var b strings.Builder
+	b.WriteString("graph {\n")
+	fmt.Fprintf(&b, "  label=%q;\n", fmt.Sprintf("Co-changes of %s (%d commits)", file, len(commits)))
+	b.WriteString("  node [shape=box, style=filled, fillcolor=white];\n")
+	fmt.Fprintf(&b, "  %q [fillcolor=lightyellow];\n", file)
+	for _, pair := range pairs {
+		count := counts[pair]
+		fmt.Fprintf(&b, "  %q -- %q [label=\"%d\", weight=%d, penwidth=%d];\n", pair.a, pair.b, count, count, min(count, 8))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func supportedFormats() string {
+	return strings.Join([]string{formatText, formatDOT}, ", ")
+}
@@ -0,0 +1,158 @@
+package history
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryCommand_TableFollowsRename(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, err := runHistoryCommand(t, "-r", repoDir, "--file", "src/x.ts")
+	require.NoError(t, err)
+
+	assert.Equal(t, `src/x.ts: 4 commit(s)
+COMMIT   DATE        PATH      CHANGED WITH
+SHA      DATE        src/x.ts  src/c.ts
+SHA      DATE        src/x.ts  src/b.ts
+SHA      DATE        src/a.ts  src/b.ts, src/c.ts
+SHA      DATE        src/a.ts  src/b.ts
+`, maskCommits(stdout))
+}
+
+func TestHistoryCommand_Limit(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, err := runHistoryCommand(t, "-r", repoDir, "--file", "src/x.ts", "-n", "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, `src/x.ts: 1 commit(s)
+COMMIT   DATE        PATH      CHANGED WITH
+SHA      DATE        src/x.ts  src/c.ts
+`, maskCommits(stdout))
+}
+
+func TestHistoryCommand_CoChangeGraph(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, err := runHistoryCommand(t, "-r", repoDir, "--file", "src/x.ts", "-f", "dot")
+	require.NoError(t, err)
+
+	assert.Equal(t, `graph {
+  label="Co-changes of src/x.ts (4 commits)";
+  node [shape=box, style=filled, fillcolor=white];
+  "src/x.ts" [fillcolor=lightyellow];
+  "src/b.ts" -- "src/c.ts" [label="1", weight=1, penwidth=1];
+  "src/b.ts" -- "src/x.ts" [label="3", weight=3, penwidth=3];
+  "src/c.ts" -- "src/x.ts" [label="2", weight=2, penwidth=2];
+}
+`, stdout)
+}
+
+func TestHistoryCommand_FileRelativeToRepoDirectory(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, err := runHistoryCommand(t, "-r", filepath.Join(repoDir, "src"), "--file", "x.ts", "-n", "1")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(stdout, "src/x.ts: 1 commit(s)\n"), stdout)
+}
+
+func TestHistoryCommand_RejectsInvalidOptions(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	_, err := runHistoryCommand(t, "-r", repoDir)
+	require.ErrorContains(t, err, "--file is required")
+
+	_, err = runHistoryCommand(t, "-r", repoDir, "--file", "src/x.ts", "-f", "json")
+	require.ErrorContains(t, err, "unknown format")
+
+	_, err = runHistoryCommand(t, "-r", repoDir, "--file", "src/x.ts", "-n", "-1")
+	require.ErrorContains(t, err, "--limit must be at least 0")
+
+	_, err = runHistoryCommand(t, "-r", repoDir, "--file", "src/missing.ts")
+	require.ErrorContains(t, err, "no commits change src/missing.ts")
+}
+
+func runHistoryCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+var commitRowPattern = regexp.MustCompile(`(?m)^[0-9a-f]{7}  \d{4}-\d{2}-\d{2}`)
+
+// maskCommits replaces the SHA and date columns of table rows, which differ per run.
+func maskCommits(output string) string {
+	return commitRowPattern.ReplaceAllString(output, "SHA      DATE      ")
+}
+
+// writeHistoryRepo commits src/a.ts with src/b.ts, then with src/b.ts and src/c.ts,
+// renames it to src/x.ts alongside a change to src/b.ts, and changes src/x.ts with
+// src/c.ts. A last commit changes only src/b.ts.
+func writeHistoryRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+
+	commit := func(message string, files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(repoDir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		}
+		gitRun(t, repoDir, "add", ".")
+		gitRun(t, repoDir, "commit", "-m", message)
+	}
+
+	commit("add a and b", map[string]string{
+		"src/a.ts": "export const a = 1;\nexport const name = 'a';\n",
+		"src/b.ts": "export const b = 1;\n",
+	})
+	commit("change a, b and c", map[string]string{
+		"src/a.ts": "export const a = 2;\nexport const name = 'a';\n",
+		"src/b.ts": "export const b = 2;\n",
+		"src/c.ts": "export const c = 1;\n",
+	})
+	gitRun(t, repoDir, "mv", "src/a.ts", "src/x.ts")
+	commit("rename a to x", map[string]string{
+		"src/b.ts": "export const b = 3;\n",
+	})
+	commit("change x and c", map[string]string{
+		"src/x.ts": "export const a = 3;\nexport const name = 'a';\n",
+		"src/c.ts": "export const c = 2;\n",
+	})
+	commit("change b", map[string]string{
+		"src/b.ts": "export const b = 4;\n",
+	})
+	return repoDir
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
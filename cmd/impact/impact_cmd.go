@@ -0,0 +1,237 @@
+package impact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+type impactOptions struct {
+	repoPath     string
+	commitID     string
+	outputFormat string
+	excludeTests bool
+}
+
+// Cmd represents the impact command.
+var Cmd = NewCommand()
+
+// NewCommand returns a new impact command instance.
+func NewCommand() *cobra.Command {
+	opts := &impactOptions{
+		outputFormat: formatText,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "impact",
+		Short: "List the files a commit or range could affect",
+		Long: `List the files changed in a commit or range together with every file that depends
+on them, directly or transitively, grouped by how many dependency hops away they are.
+Dependencies are read from the whole tree at the head commit.
+
+Examples:
+  clarity impact -c main...feature
+  clarity impact -c HEAD~3..HEAD --exclude-tests --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImpact(cmd, opts)
+		},
+	}
+
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVarP(&opts.commitID, "commit", "c", "", "Git commit or range to analyze (e.g., f0459ec, HEAD~3, f0459ec...be3d11a)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", opts.outputFormat, fmt.Sprintf("Output format (%s)", supportedFormats()))
+	cmd.Flags().BoolVar(&opts.excludeTests, "exclude-tests", false, "Leave test files out of the listed files")
+
+	return cmd
+}
+
+func runImpact(cmd *cobra.Command, opts *impactOptions) error {
+	if !isSupportedFormat(opts.outputFormat) {
+		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, supportedFormats())
+	}
+
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	repoRoot := repo.RepoRoot
+	if opts.commitID == "" {
+		return fmt.Errorf("--commit is required (e.g., -c main...feature)")
+	}
+
+	ctx := cmd.Context()
+	fromCommit, toCommit, isCommitRange, err := git.ResolveCommitRangeContext(ctx, repoRoot, opts.commitID)
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	if isCommitRange {
+		changed, err = git.GetCommitRangeFilesContext(ctx, repoRoot, fromCommit, toCommit)
+	} else {
+		changed, err = git.GetCommitFilesContext(ctx, repoRoot, toCommit)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get changed files: %w", err)
+	}
+
+	treeFiles, err := git.GetCommitTreeFilesContext(ctx, repoRoot, toCommit)
+	if err != nil {
+		return fmt.Errorf("failed to list files at %s: %w", opts.commitID, err)
+	}
+
+	contentReader := git.GitCommitContentReaderContext(ctx, repoRoot, toCommit)
+	if closer, ok := contentReader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	graph, _, err := depgraph.BuildDependencyGraphContext(ctx, supportedFiles(treeFiles), contentReader, depgraph.BuildOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	impact, err := depgraph.ReverseClosure(graph, supportedFiles(changed), 0)
+	if err != nil {
+		return err
+	}
+
+	report := buildReport(repoRoot, impact, func(file string) bool {
+		return opts.excludeTests && registry.IsTestFile(file, contentReader)
+	})
+
+	output, err := formatReport(opts.outputFormat, report)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), output)
+	return nil
+}
+
+// supportedFiles keeps the files Clarity can analyze.
+func supportedFiles(files []string) []string {
+	supported := make([]string, 0, len(files))
+	for _, file := range files {
+		if registry.IsSupportedLanguageExtension(filepath.Ext(file)) {
+			supported = append(supported, file)
+		}
+	}
+	return supported
+}
+
+// impactReport is the affected files grouped by distance from the changed files.
+// Paths are relative to the repository root and use forward slashes.
+type impactReport struct {
+	Changed  []string        `json:"changed"`
+	Affected []affectedGroup `json:"affected"`
+}
+
+// affectedGroup lists, sorted, the files a number of dependency hops away from the
+// nearest changed file.
+type affectedGroup struct {
+	Distance int      `json:"distance"`
+	Files    []string `json:"files"`
+}
+
+// buildReport groups the files of impact by distance, leaving out those skip reports.
+// Groups left empty by skip are dropped.
+func buildReport(repoRoot string, impact depgraph.Impact, skip func(string) bool) impactReport {
+	report := impactReport{Changed: []string{}, Affected: []affectedGroup{}}
+	byDistance := make(map[int][]string)
+	for _, file := range impact.Files() {
+		if skip(file) {
+			continue
+		}
+		distance := impact.Distances[file]
+		relPath := relativePath(repoRoot, file)
+		if distance == 0 {
+			report.Changed = append(report.Changed, relPath)
+			continue
+		}
+		byDistance[distance] = append(byDistance[distance], relPath)
+	}
+
+	distances := make([]int, 0, len(byDistance))
+	for distance := range byDistance {
+		distances = append(distances, distance)
+	}
+	sort.Ints(distances)
+	for _, distance := range distances {
+		files := byDistance[distance]
+		sort.Strings(files)
+		report.Affected = append(report.Affected, affectedGroup{Distance: distance, Files: files})
+	}
+	sort.Strings(report.Changed)
+	return report
+}
+
+func formatReport(format string, report impactReport) (string, error) {
+	switch format {
+	case formatText:
+		return formatTextReport(report), nil
+	case formatJSON:
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		return string(output) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown format: %s (valid options: %s)", format, supportedFormats())
+	}
+}
+
+func formatTextReport(report impactReport) string {
+	var b strings.Builder
+	writeGroup(&b, "Changed", report.Changed)
+	for _, group := range report.Affected {
+		writeGroup(&b, distanceHeading(group.Distance), group.Files)
+	}
+	if len(report.Affected) == 0 {
+		b.WriteString("No other files depend on the changed files.\n")
+	}
+	return b.String()
+}
+
+func writeGroup(b *strings.Builder, heading string, files []string) {
+	fmt.Fprintf(b, "%s (%d):\n", heading, len(files))
+	for _, file := range files {
+		fmt.Fprintf(b, "  %s\n", file)
+	}
+}
+
+func distanceHeading(distance int) string {
+	if distance == 1 {
+		return "Direct dependents"
+	}
+	return fmt.Sprintf("%d hops", distance)
+}
+
+func relativePath(repoRoot, file string) string {
+	rel, err := filepath.Rel(repoRoot, file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func isSupportedFormat(format string) bool {
+	return format == formatText || format == formatJSON
+}
+
+func supportedFormats() string {
+	return strings.Join([]string{formatText, formatJSON}, ", ")
+}
@@ -0,0 +1,149 @@
+package impact
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpactCommand_GroupsDependentsByDistance(t *testing.T) {
+	repoDir := writeImpactRepo(t)
+
+	stdout, _, err := runImpactCommand(t, "-r", repoDir, "-c", "HEAD~1..HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, `Changed (1):
+  src/a.ts
+Direct dependents (2):
+  src/a.test.ts
+  src/b.ts
+2 hops (1):
+  src/c.ts
+`, stdout)
+}
+
+func TestImpactCommand_SingleCommitComparesWithParent(t *testing.T) {
+	repoDir := writeImpactRepo(t)
+
+	single, _, err := runImpactCommand(t, "-r", repoDir, "-c", "HEAD")
+	require.NoError(t, err)
+	rangeOutput, _, err := runImpactCommand(t, "-r", repoDir, "-c", "HEAD~1..HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, rangeOutput, single)
+}
+
+func TestImpactCommand_ExcludeTestsJSON(t *testing.T) {
+	repoDir := writeImpactRepo(t)
+
+	stdout, _, err := runImpactCommand(t, "-r", repoDir, "-c", "HEAD~1..HEAD", "--exclude-tests", "--format", "json")
+	require.NoError(t, err)
+
+	var report impactReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Equal(t, impactReport{
+		Changed: []string{"src/a.ts"},
+		Affected: []affectedGroup{
+			{Distance: 1, Files: []string{"src/b.ts"}},
+			{Distance: 2, Files: []string{"src/c.ts"}},
+		},
+	}, report)
+}
+
+func TestImpactCommand_ReadsTheHeadCommitNotTheWorkingTree(t *testing.T) {
+	repoDir := writeImpactRepo(t)
+	// An uncommitted import must not add d.ts to the impact of the range.
+	writeImpactFile(t, repoDir, "src/d.ts", "import { a } from './a';\nexport const d = a;\n")
+
+	stdout, _, err := runImpactCommand(t, "-r", repoDir, "-c", "HEAD~1..HEAD")
+	require.NoError(t, err)
+
+	assert.NotContains(t, stdout, "src/d.ts")
+}
+
+func TestImpactCommand_NoDependents(t *testing.T) {
+	repoDir := writeImpactRepo(t)
+	writeImpactFile(t, repoDir, "src/d.ts", "export const d = 2;\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "change d")
+
+	stdout, _, err := runImpactCommand(t, "-r", repoDir, "-c", "HEAD~1..HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Changed (1):\n  src/d.ts\nNo other files depend on the changed files.\n", stdout)
+}
+
+func TestImpactCommand_RejectsInvalidOptions(t *testing.T) {
+	repoDir := writeImpactRepo(t)
+
+	_, _, err := runImpactCommand(t, "-r", repoDir)
+	require.ErrorContains(t, err, "--commit is required")
+
+	_, _, err = runImpactCommand(t, "-r", repoDir, "-c", "HEAD", "--format", "dot")
+	require.ErrorContains(t, err, "unknown format")
+}
+
+func runImpactCommand(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+// writeImpactRepo creates a repository where c.ts depends on b.ts, and b.ts and
+// a.test.ts depend on a.ts, then commits a change to a.ts. d.ts depends on nothing.
+func writeImpactRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+
+	writeImpactFile(t, repoDir, "src/a.ts", "export const a = 1;\n")
+	writeImpactFile(t, repoDir, "src/b.ts", "import { a } from './a';\nexport const b = a;\n")
+	writeImpactFile(t, repoDir, "src/c.ts", "import { b } from './b';\nexport const c = b;\n")
+	writeImpactFile(t, repoDir, "src/a.test.ts", "import { a } from './a';\ntest('a', () => a);\n")
+	writeImpactFile(t, repoDir, "src/d.ts", "export const d = 1;\n")
+	writeImpactFile(t, repoDir, "README.md", "# impact\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+
+	writeImpactFile(t, repoDir, "src/a.ts", "export const a = 2;\n")
+	writeImpactFile(t, repoDir, "README.md", "# impact, changed\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "change a")
+	return repoDir
+}
+
+func writeImpactFile(t *testing.T, repoDir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(repoDir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
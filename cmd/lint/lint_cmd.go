@@ -0,0 +1,227 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/analysis"
+	"github.com/LegacyCodeHQ/clarity/depgraph/rules"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/internal/projectconfig"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+type lintOptions struct {
+	repoPath     string
+	commitID     string
+	outputFormat string
+	suppressFile string
+}
+
+// NewCommand returns a new lint command instance.
+func NewCommand() *cobra.Command {
+	opts := &lintOptions{
+		outputFormat: formatText,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check the dependency graph against the architecture rules in .clarity.yml",
+		Long: `Check the dependency graph of the whole tree against the rules in the lint section
+of .clarity.yml, and list every violation with its rule and offending edge. Rules
+deny or allow dependencies between repo-relative directories, limit the fan-in of
+any one file, or forbid cycles:
+
+  lint:
+    rules:
+      - name: parsers-stay-independent
+        deny: {from: parsers, to: cmd}
+      - name: acyclic
+        no-cycles: true
+        severity: warning
+
+The run fails when a rule of error severity, the default, is broken. Dependencies
+are read from the head commit with --commit, or the working tree otherwise.
+Violations on an edge matched by a rule of --suppress-file, the file show reads,
+are counted but not reported.
+
+Examples:
+  clarity lint
+  clarity lint -c HEAD --format json
+  clarity lint --suppress-file suppressions.yml`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLint(cmd, opts)
+		},
+	}
+
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVarP(&opts.commitID, "commit", "c", "", "Git commit or range whose head to check (e.g., f0459ec, HEAD~3, main...HEAD); the working tree when empty")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", opts.outputFormat, fmt.Sprintf("Output format (%s)", supportedFormats()))
+	cmd.Flags().StringVar(&opts.suppressFile, "suppress-file", "", "Edge suppression rules file; violations on a matched edge are not reported")
+
+	return cmd
+}
+
+func runLint(cmd *cobra.Command, opts *lintOptions) error {
+	if opts.outputFormat != formatText && opts.outputFormat != formatJSON {
+		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, supportedFormats())
+	}
+
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	repoRoot := repo.RepoRoot
+
+	lintRules, err := loadLintRules(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	result, err := analysis.Analyzer{
+		RepoPath:    repoRoot,
+		CommitRange: opts.commitID,
+		WholeTree:   true,
+		SkipStats:   true,
+	}.Run(cmd.Context())
+	if err != nil {
+		return err
+	}
+	violations, err := lintRules.Evaluate(result.Graph, repoRoot)
+	if err != nil {
+		return err
+	}
+	violations, suppressed, err := suppressViolations(cmd, opts.suppressFile, violations, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := writeViolations(cmd.OutOrStdout(), opts.outputFormat, violations, suppressed); err != nil {
+		return err
+	}
+	if rules.HasErrors(violations) {
+		return fmt.Errorf("%d lint violation(s) of error rules", countErrors(violations))
+	}
+	return nil
+}
+
+// loadLintRules reads the lint rules of the config file at the repository root. A
+// missing file or an empty lint section is an error, since there is nothing to check.
+func loadLintRules(repoRoot string) (rules.LintRules, error) {
+	path, err := projectconfig.Find(repoRoot)
+	if err != nil {
+		return rules.LintRules{}, err
+	}
+	if path == "" {
+		return rules.LintRules{}, fmt.Errorf("no lint rules: add a lint section to %s at the repository root", projectconfig.FileNames[0])
+	}
+	lintRules, err := rules.LoadLintRules(path)
+	if err != nil {
+		return rules.LintRules{}, err
+	}
+	if len(lintRules.Rules) == 0 {
+		return rules.LintRules{}, fmt.Errorf("no lint rules: add a lint section to %s", path)
+	}
+	return lintRules, nil
+}
+
+// suppressViolations drops the violations whose edge an active rule of the
+// suppression file at path covers, warning about expired rules, and returns how many
+// it dropped. Violations of a single file, such as max-fan-in, have no edge and are
+// always kept.
+func suppressViolations(cmd *cobra.Command, path string, violations []rules.LintViolation, now time.Time) ([]rules.LintViolation, int, error) {
+	if path == "" {
+		return violations, 0, nil
+	}
+
+	suppressions, err := rules.LoadSuppressions(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	active, stale := suppressions.Partition(now)
+	for _, rule := range stale {
+		cliconfig.Warnf(cmd, "stale suppression %s -> %s expired on %s and is no longer applied", rule.From, rule.To, rule.Expires)
+	}
+
+	kept := make([]rules.LintViolation, 0, len(violations))
+	for _, violation := range violations {
+		if violation.From != "" {
+			if _, ok := rules.Match(active, violation.From, violation.To); ok {
+				continue
+			}
+		}
+		kept = append(kept, violation)
+	}
+	return kept, len(violations) - len(kept), nil
+}
+
+func writeViolations(out io.Writer, format string, violations []rules.LintViolation, suppressed int) error {
+	if format == formatJSON {
+		output, err := json.MarshalIndent(struct {
+			Violations []rules.LintViolation `json:"violations"`
+			Suppressed int                   `json:"suppressed"`
+		}{violations, suppressed}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		fmt.Fprintln(out, string(output))
+		return nil
+	}
+
+	if len(violations) == 0 {
+		fmt.Fprintln(out, "No lint violations.")
+		writeSuppressed(out, suppressed)
+		return nil
+	}
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, violation := range violations {
+		target := violation.To
+		if violation.From != "" {
+			target = violation.From + " -> " + violation.To
+		}
+		line := fmt.Sprintf("%s\t%s\t%s", violation.Severity, violation.Rule, target)
+		if violation.Detail != "" {
+			line += "\t" + violation.Detail
+		}
+		fmt.Fprintln(writer, line)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	writeSuppressed(out, suppressed)
+	return nil
+}
+
+func writeSuppressed(out io.Writer, suppressed int) {
+	if suppressed > 0 {
+		fmt.Fprintf(out, "%d suppressed violation(s) not shown.\n", suppressed)
+	}
+}
+
+func countErrors(violations []rules.LintViolation) int {
+	count := 0
+	for _, violation := range violations {
+		if violation.Severity == rules.SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+func supportedFormats() string {
+	return strings.Join([]string{formatText, formatJSON}, ", ")
+}
@@ -0,0 +1,182 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/rules"
+)
+
+const lintConfig = `lint:
+  rules:
+    - name: parsers-stay-independent
+      deny: {from: src/parsers, to: src/cmd}
+    - name: acyclic
+      no-cycles: true
+      severity: warning
+`
+
+func TestLint_ReportsViolationsAndFailsOnErrorRules(t *testing.T) {
+	repoDir := writeLintRepo(t)
+
+	stdout, _, err := runLintCommand(t, "-r", repoDir)
+
+	require.EqualError(t, err, "1 lint violation(s) of error rules")
+	assert.True(t, strings.HasPrefix(stdout, `error    parsers-stay-independent  src/parsers/parser.ts -> src/cmd/main.ts
+warning  acyclic                   src/cmd/main.ts -> src/parsers/parser.ts  cycle: src/cmd/main.ts -> src/parsers/parser.ts -> src/cmd/main.ts
+`), stdout)
+}
+
+func TestLint_WarningsAloneDoNotFail(t *testing.T) {
+	repoDir := writeLintRepo(t)
+	writeLintFile(t, repoDir, "src/parsers/parser.ts", "export const parse = 1;\n")
+
+	stdout, _, err := runLintCommand(t, "-r", repoDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "No lint violations.\n", stdout)
+}
+
+func TestLint_JSONAtCommit(t *testing.T) {
+	repoDir := writeLintRepo(t)
+	writeLintFile(t, repoDir, "src/parsers/parser.ts", "export const parse = 1;\n")
+
+	stdout, _, err := runLintCommand(t, "-r", repoDir, "-c", "HEAD", "--format", "json")
+
+	require.Error(t, err)
+	var document struct {
+		Violations []rules.LintViolation `json:"violations"`
+	}
+	require.NoError(t, json.NewDecoder(strings.NewReader(stdout)).Decode(&document), stdout)
+	require.Len(t, document.Violations, 2)
+	assert.Equal(t, rules.LintViolation{
+		Rule:     "parsers-stay-independent",
+		Severity: rules.SeverityError,
+		From:     "src/parsers/parser.ts",
+		To:       "src/cmd/main.ts",
+	}, document.Violations[0])
+}
+
+func TestLint_SuppressFileHidesMatchingViolations(t *testing.T) {
+	repoDir := writeLintRepo(t)
+	suppressFile := filepath.Join(t.TempDir(), "suppressions.yml")
+	require.NoError(t, os.WriteFile(suppressFile, []byte(`suppressions:
+  - from: "src/parsers/**"
+    to: "src/cmd/**"
+    reason: migration in progress
+  - from: "src/cmd/**"
+    to: "src/parsers/**"
+    reason: expired
+    expires: "2000-01-01"
+`), 0o644))
+
+	stdout, stderr, err := runLintCommand(t, "-r", repoDir, "--suppress-file", suppressFile)
+
+	require.NoError(t, err)
+	assert.Equal(t, `warning  acyclic  src/cmd/main.ts -> src/parsers/parser.ts  cycle: src/cmd/main.ts -> src/parsers/parser.ts -> src/cmd/main.ts
+1 suppressed violation(s) not shown.
+`, stdout)
+	assert.Contains(t, stderr, "stale suppression src/cmd/** -> src/parsers/** expired on 2000-01-01 and is no longer applied")
+
+	stdout, _, err = runLintCommand(t, "-r", repoDir, "--suppress-file", suppressFile, "--format", "json")
+
+	require.NoError(t, err)
+	var document struct {
+		Violations []rules.LintViolation `json:"violations"`
+		Suppressed int                   `json:"suppressed"`
+	}
+	require.NoError(t, json.NewDecoder(strings.NewReader(stdout)).Decode(&document), stdout)
+	assert.Len(t, document.Violations, 1)
+	assert.Equal(t, 1, document.Suppressed)
+}
+
+func TestLint_StopsWhenCanceled(t *testing.T) {
+	repoDir := writeLintRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"-r", repoDir})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.ExecuteContext(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLint_RequiresRules(t *testing.T) {
+	repoDir := writeLintRepo(t)
+	require.NoError(t, os.Remove(filepath.Join(repoDir, ".clarity.yml")))
+
+	_, _, err := runLintCommand(t, "-r", repoDir)
+
+	require.EqualError(t, err, "no lint rules: add a lint section to .clarity.yml at the repository root")
+}
+
+func TestLint_InvalidRuleNamesTheFile(t *testing.T) {
+	repoDir := writeLintRepo(t)
+	writeLintFile(t, repoDir, ".clarity.yml", "lint:\n  rules:\n    - name: r\n")
+
+	_, _, err := runLintCommand(t, "-r", repoDir)
+
+	require.ErrorContains(t, err, ".clarity.yml: line 3: lint rule 1: one of deny, allow, max-fan-in, or no-cycles is required")
+}
+
+func runLintCommand(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+// writeLintRepo commits a repository whose parser imports the command that imports
+// it, breaking the deny rule of lintConfig and forming a cycle.
+func writeLintRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+
+	writeLintFile(t, repoDir, ".clarity.yml", lintConfig)
+	writeLintFile(t, repoDir, "src/cmd/main.ts", "import { parse } from '../parsers/parser';\nexport const run = parse;\n")
+	writeLintFile(t, repoDir, "src/parsers/parser.ts", "import { run } from '../cmd/main';\nexport const parse = run;\n")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
+
+func writeLintFile(t *testing.T, repoDir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(repoDir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
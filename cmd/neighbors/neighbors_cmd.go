@@ -0,0 +1,357 @@
+package neighbors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show"
+	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+const (
+	neighborsFormatText = "text"
+	neighborsFormatJSON = "json"
+)
+
+// neighborsSchemaVersion is the version of the neighbors JSON document. Fields are
+// only added under a version; renaming or removing one bumps it.
+const neighborsSchemaVersion = 1
+
+// Direction tags of a neighbor, relative to the --file file.
+const (
+	// neighborImports marks a file the --file file depends on, directly or through
+	// other files.
+	neighborImports = "imports"
+	// neighborImportedBy marks a file that depends on the --file file.
+	neighborImportedBy = "imported-by"
+)
+
+// --scope values: the directions followed from the --file file.
+const (
+	// scopeDownstream follows the files the --file file depends on.
+	scopeDownstream = "downstream"
+	// scopeUpstream follows the files that depend on the --file file.
+	scopeUpstream = "upstream"
+	// scopeBoth follows both directions.
+	scopeBoth = "both"
+)
+
+// supportedScopes returns the accepted --scope values.
+func supportedScopes() string {
+	return strings.Join([]string{scopeDownstream, scopeUpstream, scopeBoth}, ", ")
+}
+
+// neighborsOptions are the flags of the neighbors command besides the selection flags.
+type neighborsOptions struct {
+	format string
+	level  int
+	scope  string
+}
+
+// neighborsDocument is the JSON output of the neighbors command:
+//
+//	{
+//	  "schemaVersion": 2,
+//	  "file": {"path": "/repo/src/app.ts", "relativePath": "src/app.ts"},
+//	  "level": 2,
+//	  "neighbors": [
+//	    {
+//	      "path": "/repo/src/util.ts",
+//	      "relativePath": "src/util.ts",
+//	      "directions": ["imports"],
+//	      "distance": 1,
+//	      "imports": ["src/log.ts"],
+//	      "stats": {"additions": 3, "deletions": 1, "isNew": false, "isBinary": false}
+//	    }
+//	  ]
+//	}
+//
+// file also carries imports and stats. Neighbors are sorted by distance and then by
+// relative path. imports lists the relative paths of the files a file depends on
+// among file and its neighbors, so together they form the filtered adjacency list.
+// stats is present only for files with uncommitted changes, and only when --commit
+// is not set.
+type neighborsDocument struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	File          neighborFile    `json:"file"`
+	Level         int             `json:"level"`
+	Neighbors     []neighborEntry `json:"neighbors"`
+}
+
+// neighborFile is a file of the neighbors document.
+type neighborFile struct {
+	Path         string                    `json:"path"`
+	RelativePath string                    `json:"relativePath"`
+	Imports      []string                  `json:"imports"`
+	Stats        *formatters.JSONNodeStats `json:"stats,omitempty"`
+}
+
+// neighborEntry is a file within --level steps of the --file file.
+type neighborEntry struct {
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+	// Directions holds neighborImports, neighborImportedBy, or both for files in a
+	// cycle with the --file file.
+	Directions []string `json:"directions"`
+	// Distance is the fewest dependency steps between the file and the --file file,
+	// in either direction.
+	Distance int                       `json:"distance"`
+	Imports  []string                  `json:"imports"`
+	Stats    *formatters.JSONNodeStats `json:"stats,omitempty"`
+}
+
+// NewCommand returns a new neighbors command instance.
+func NewCommand() *cobra.Command {
+	var selection *show.Selection
+	opts := &neighborsOptions{
+		format: neighborsFormatText,
+		level:  1,
+		scope:  scopeBoth,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "neighbors",
+		Short: "List the files within a number of dependency steps of a file",
+		Long: `List the files within --level dependency steps of the --file file, tagged with
+whether the file imports them or is imported by them. The json format is a stable
+interface for editor integrations: its fields are covered by schemaVersion, and
+files with uncommitted changes carry their line counts.`,
+		Example: `  clarity neighbors --file src/app.ts
+  clarity neighbors --file src/app.ts --level 2 --format json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNeighbors(cmd, selection, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.format, "format", "f", opts.format, fmt.Sprintf("Output format (%s, %s)", neighborsFormatText, neighborsFormatJSON))
+	selection = show.AddSelection(cmd)
+	cmd.Flags().IntVarP(&opts.level, "level", "l", opts.level, "Dependency steps from --file to include (0 = unlimited)")
+	cmd.Flags().StringVar(&opts.scope, "scope", opts.scope, fmt.Sprintf("Directions to follow from --file (%s)", supportedScopes()))
+	selection.AddParseFlags(cmd)
+
+	return cmd
+}
+
+func runNeighbors(cmd *cobra.Command, selection *show.Selection, opts *neighborsOptions) (err error) {
+	if opts.format != neighborsFormatText && opts.format != neighborsFormatJSON {
+		return fmt.Errorf("unknown format: %s (valid options: %s, %s)", opts.format, neighborsFormatText, neighborsFormatJSON)
+	}
+	if selection.TargetFile() == "" {
+		return fmt.Errorf("--file is required")
+	}
+	if len(selection.Between()) > 0 {
+		return fmt.Errorf("--between cannot be used with neighbors")
+	}
+	if opts.level < 0 {
+		return fmt.Errorf("--level must not be negative")
+	}
+	scope := strings.ToLower(strings.TrimSpace(opts.scope))
+	switch scope {
+	case scopeDownstream, scopeUpstream, scopeBoth:
+		opts.scope = scope
+	default:
+		return fmt.Errorf("unknown scope: %s (valid options: %s)", opts.scope, supportedScopes())
+	}
+
+	selected, err := selection.Collect(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := selected.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	analyzer := selected.Analyzer(cmd)
+	// Line counts describe work in progress, so they are read only for the working
+	// tree.
+	analyzer.SkipStats = selection.CommitRange() != ""
+	result, err := analyzer.Run(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	target, err := selected.ResolveFile(cmd, result.Graph.Graph, selection.TargetFile())
+	if err != nil {
+		return err
+	}
+
+	document, err := buildNeighborsDocument(selected, opts, result.Graph.Graph, result.FileStats, target)
+	if err != nil {
+		return err
+	}
+
+	if opts.format == neighborsFormatJSON {
+		output, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode neighbors: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	}
+	return writeNeighbors(cmd, document)
+}
+
+// buildNeighborsDocument lists the files within opts.level steps of target along the
+// directions opts.scope selects.
+func buildNeighborsDocument(selected *show.SelectedFiles, opts *neighborsOptions, graph depgraph.DependencyGraph, fileStats map[string]vcs.FileStats, target string) (neighborsDocument, error) {
+	adjacency, err := depgraph.AdjacencyList(graph)
+	if err != nil {
+		return neighborsDocument{}, fmt.Errorf("failed to list neighbors of %s: %w", target, err)
+	}
+
+	var downstream, upstream map[string]int
+	if opts.scope == scopeDownstream || opts.scope == scopeBoth {
+		downstream = levelDistances(adjacency, target, opts.level)
+	}
+	if opts.scope == scopeUpstream || opts.scope == scopeBoth {
+		upstream = levelDistances(reverseAdjacency(adjacency), target, opts.level)
+	}
+
+	// The neighborhood keeps only the edges between target and its neighbors.
+	filteredAdjacency := map[string][]string{target: nil}
+	for file := range downstream {
+		filteredAdjacency[file] = nil
+	}
+	for file := range upstream {
+		filteredAdjacency[file] = nil
+	}
+	for file := range filteredAdjacency {
+		for _, dep := range adjacency[file] {
+			if _, ok := filteredAdjacency[dep]; ok {
+				filteredAdjacency[file] = append(filteredAdjacency[file], dep)
+			}
+		}
+	}
+
+	relativeImports := func(file string) []string {
+		imports := make([]string, 0, len(filteredAdjacency[file]))
+		for _, dep := range filteredAdjacency[file] {
+			imports = append(imports, selected.RelativePath(dep))
+		}
+		sort.Strings(imports)
+		return imports
+	}
+
+	document := neighborsDocument{
+		SchemaVersion: neighborsSchemaVersion,
+		File: neighborFile{
+			Path:         target,
+			RelativePath: selected.RelativePath(target),
+			Imports:      relativeImports(target),
+			Stats:        neighborStats(fileStats, target),
+		},
+		Level:     opts.level,
+		Neighbors: []neighborEntry{},
+	}
+	for file := range filteredAdjacency {
+		if file == target {
+			continue
+		}
+		entry := neighborEntry{
+			Path:         file,
+			RelativePath: selected.RelativePath(file),
+			Imports:      relativeImports(file),
+			Stats:        neighborStats(fileStats, file),
+		}
+		distance := 0
+		if d, ok := downstream[file]; ok {
+			entry.Directions = append(entry.Directions, neighborImports)
+			distance = d
+		}
+		if d, ok := upstream[file]; ok {
+			entry.Directions = append(entry.Directions, neighborImportedBy)
+			if distance == 0 || d < distance {
+				distance = d
+			}
+		}
+		entry.Distance = distance
+		document.Neighbors = append(document.Neighbors, entry)
+	}
+	sort.Slice(document.Neighbors, func(i, j int) bool {
+		a, b := document.Neighbors[i], document.Neighbors[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.RelativePath < b.RelativePath
+	})
+	return document, nil
+}
+
+// levelDistances returns the fewest steps along adjacency from start to each file
+// reachable within level steps (any number when level is 0), leaving out start.
+func levelDistances(adjacency map[string][]string, start string, level int) map[string]int {
+	distances := make(map[string]int)
+	seen := map[string]bool{start: true}
+	current := []string{start}
+	for step := 1; (level == 0 || step <= level) && len(current) > 0; step++ {
+		var next []string
+		for _, file := range current {
+			for _, dep := range adjacency[file] {
+				if !seen[dep] {
+					seen[dep] = true
+					distances[dep] = step
+					next = append(next, dep)
+				}
+			}
+		}
+		current = next
+	}
+	return distances
+}
+
+// reverseAdjacency maps each file to the files that depend on it.
+func reverseAdjacency(adjacency map[string][]string) map[string][]string {
+	reversed := make(map[string][]string, len(adjacency))
+	for source, deps := range adjacency {
+		for _, dep := range deps {
+			reversed[dep] = append(reversed[dep], source)
+		}
+	}
+	for _, sources := range reversed {
+		sort.Strings(sources)
+	}
+	return reversed
+}
+
+func neighborStats(fileStats map[string]vcs.FileStats, file string) *formatters.JSONNodeStats {
+	stats, ok := fileStats[file]
+	if !ok {
+		return nil
+	}
+	return &formatters.JSONNodeStats{
+		Additions:   stats.Additions,
+		Deletions:   stats.Deletions,
+		IsNew:       stats.IsNew,
+		IsBinary:    stats.IsBinary,
+		RenamedFrom: stats.RenamedFrom,
+	}
+}
+
+func writeNeighbors(cmd *cobra.Command, document neighborsDocument) error {
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, document.File.RelativePath)
+	if len(document.Neighbors) == 0 {
+		fmt.Fprintln(out, "  (no neighbors)")
+		return nil
+	}
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, neighbor := range document.Neighbors {
+		for _, direction := range neighbor.Directions {
+			fmt.Fprintf(writer, "  %s\t%d\t%s\n", direction, neighbor.Distance, neighbor.RelativePath)
+		}
+	}
+	return writer.Flush()
+}
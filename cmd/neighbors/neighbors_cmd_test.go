@@ -0,0 +1,175 @@
+package neighbors
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runNeighborsCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+// writeNeighborsRepo commits TypeScript sources where index.ts imports main.ts,
+// main.ts imports app.ts, app.ts imports config.ts and util.ts, config.ts imports
+// util.ts, and util.ts imports log.ts.
+func writeNeighborsRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+	for name, content := range map[string]string{
+		"src/index.ts":  "import { main } from './main';\nmain();\n",
+		"src/main.ts":   "import { app } from './app';\nexport const main = () => app;\n",
+		"src/app.ts":    "import { util } from './util';\nimport { config } from './config';\nexport const app = util + config;\n",
+		"src/config.ts": "import { util } from './util';\nexport const config = util;\n",
+		"src/util.ts":   "import { log } from './log';\nexport const util = log;\n",
+		"src/log.ts":    "export const log = 1;\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
+
+func decodeNeighbors(t *testing.T, output string) neighborsDocument {
+	t.Helper()
+	var document neighborsDocument
+	require.NoError(t, json.Unmarshal([]byte(output), &document), output)
+	return document
+}
+
+func neighborSummary(document neighborsDocument) map[string][]any {
+	summary := make(map[string][]any)
+	for _, neighbor := range document.Neighbors {
+		summary[neighbor.RelativePath] = []any{neighbor.Directions, neighbor.Distance, neighbor.Imports}
+	}
+	return summary
+}
+
+func TestNeighborsCommand_JSONLevelOne(t *testing.T) {
+	repoDir := writeNeighborsRepo(t)
+
+	output, err := runNeighborsCommand(t, "-r", repoDir, "--file", "src/app.ts", "-f", "json")
+	require.NoError(t, err)
+
+	document := decodeNeighbors(t, output)
+	assert.Equal(t, 1, document.SchemaVersion)
+	assert.Equal(t, 1, document.Level)
+	assert.Equal(t, filepath.Join(repoDir, "src", "app.ts"), document.File.Path)
+	assert.Equal(t, "src/app.ts", document.File.RelativePath)
+	assert.Equal(t, []string{"src/config.ts", "src/util.ts"}, document.File.Imports)
+	assert.Equal(t, map[string][]any{
+		"src/config.ts": {[]string{"imports"}, 1, []string{"src/util.ts"}},
+		"src/main.ts":   {[]string{"imported-by"}, 1, []string{"src/app.ts"}},
+		"src/util.ts":   {[]string{"imports"}, 1, []string{}},
+	}, neighborSummary(document))
+	assert.Equal(t, filepath.Join(repoDir, "src", "config.ts"), document.Neighbors[0].Path)
+}
+
+func TestNeighborsCommand_JSONLevelTwo(t *testing.T) {
+	repoDir := writeNeighborsRepo(t)
+
+	output, err := runNeighborsCommand(t, "-r", repoDir, "--file", "src/app.ts", "--level", "2", "-f", "json")
+	require.NoError(t, err)
+
+	document := decodeNeighbors(t, output)
+	assert.Equal(t, 2, document.Level)
+	assert.Equal(t, map[string][]any{
+		"src/config.ts": {[]string{"imports"}, 1, []string{"src/util.ts"}},
+		"src/main.ts":   {[]string{"imported-by"}, 1, []string{"src/app.ts"}},
+		"src/util.ts":   {[]string{"imports"}, 1, []string{"src/log.ts"}},
+		"src/index.ts":  {[]string{"imported-by"}, 2, []string{"src/main.ts"}},
+		"src/log.ts":    {[]string{"imports"}, 2, []string{}},
+	}, neighborSummary(document))
+
+	var order []string
+	for _, neighbor := range document.Neighbors {
+		order = append(order, neighbor.RelativePath)
+	}
+	assert.Equal(t, []string{"src/config.ts", "src/main.ts", "src/util.ts", "src/index.ts", "src/log.ts"}, order)
+}
+
+func TestNeighborsCommand_CycleIsTaggedBothWays(t *testing.T) {
+	repoDir := writeNeighborsRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "src", "util.ts"),
+		[]byte("import { log } from './log';\nimport { app } from './app';\nexport const util = log;\n"), 0o644))
+
+	output, err := runNeighborsCommand(t, "-r", repoDir, "--file", "src/app.ts", "-f", "json")
+	require.NoError(t, err)
+
+	document := decodeNeighbors(t, output)
+	assert.Equal(t, []any{[]string{"imports", "imported-by"}, 1, []string{"src/app.ts"}}, neighborSummary(document)["src/util.ts"])
+}
+
+func TestNeighborsCommand_StatsOnlyForDirtyFiles(t *testing.T) {
+	repoDir := writeNeighborsRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "src", "config.ts"),
+		[]byte("import { util } from './util';\n// tuned\nexport const config = util * 2;\n"), 0o644))
+
+	output, err := runNeighborsCommand(t, "-r", repoDir, "--file", "src/app.ts", "-f", "json")
+	require.NoError(t, err)
+
+	document := decodeNeighbors(t, output)
+	assert.Nil(t, document.File.Stats)
+	for _, neighbor := range document.Neighbors {
+		if neighbor.RelativePath != "src/config.ts" {
+			assert.Nil(t, neighbor.Stats, neighbor.RelativePath)
+			continue
+		}
+		require.NotNil(t, neighbor.Stats)
+		assert.Equal(t, 2, neighbor.Stats.Additions)
+		assert.Equal(t, 1, neighbor.Stats.Deletions)
+	}
+}
+
+func TestNeighborsCommand_Text(t *testing.T) {
+	repoDir := writeNeighborsRepo(t)
+
+	output, err := runNeighborsCommand(t, "-r", repoDir, "--file", "src/app.ts", "--scope", "upstream", "--level", "0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "src/app.ts\n  imported-by  1  src/main.ts\n  imported-by  2  src/index.ts\n", output)
+}
+
+func TestNeighborsCommand_RequiresFile(t *testing.T) {
+	_, err := runNeighborsCommand(t, "-f", "json")
+	require.EqualError(t, err, "--file is required")
+}
+
+func TestNeighborsCommand_UnknownScope(t *testing.T) {
+	_, err := runNeighborsCommand(t, "--file", "src/app.ts", "--scope", "sideways")
+	require.EqualError(t, err, "unknown scope: sideways (valid options: downstream, upstream, both)")
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
@@ -1,19 +1,31 @@
 package cmd
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"runtime/pprof"
 	"strconv"
 
+	cachecmd "github.com/LegacyCodeHQ/clarity/cmd/cache"
+	checkcmd "github.com/LegacyCodeHQ/clarity/cmd/check"
 	diffcmd "github.com/LegacyCodeHQ/clarity/cmd/diff"
 	extensionscmd "github.com/LegacyCodeHQ/clarity/cmd/extensions"
+	filescmd "github.com/LegacyCodeHQ/clarity/cmd/files"
+	historycmd "github.com/LegacyCodeHQ/clarity/cmd/history"
+	impactcmd "github.com/LegacyCodeHQ/clarity/cmd/impact"
 	"github.com/LegacyCodeHQ/clarity/cmd/languages"
+	lintcmd "github.com/LegacyCodeHQ/clarity/cmd/lint"
+	neighborscmd "github.com/LegacyCodeHQ/clarity/cmd/neighbors"
 	setupcmd "github.com/LegacyCodeHQ/clarity/cmd/setup"
 	"github.com/LegacyCodeHQ/clarity/cmd/show"
+	statscmd "github.com/LegacyCodeHQ/clarity/cmd/stats"
+	trendcmd "github.com/LegacyCodeHQ/clarity/cmd/trend"
+	tuicmd "github.com/LegacyCodeHQ/clarity/cmd/tui"
 	watchcmd "github.com/LegacyCodeHQ/clarity/cmd/watch"
 	whycmd "github.com/LegacyCodeHQ/clarity/cmd/why"
 	workspacecmd "github.com/LegacyCodeHQ/clarity/cmd/workspace"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 	"github.com/LegacyCodeHQ/clarity/internal/mcplogdlog"
 	"github.com/spf13/cobra"
 )
@@ -33,53 +45,112 @@ var enableDevCommands = "false"
 var cpuProfilePath string
 var cpuProfileFile *os.File
 
+// cancelTimeout releases the --timeout deadline of the running command.
+var cancelTimeout context.CancelFunc
+
 // rootCmd represents the base command when called without any subcommands
-var rootCmd = &cobra.Command{
-	Use:   "clarity",
-	Short: "A software design tool for AI-native developers and coding agents.",
-	Long: `A software design tool for AI-native developers and coding agents.
+var rootCmd = newRootCommand(isDevelopmentBuild(enableDevCommands))
+
+// newRootCommand builds the root command with fresh subcommands. Development-only
+// subcommands are registered when devCommands is set.
+func newRootCommand(devCommands bool) *cobra.Command {
+	root := &cobra.Command{
+		Use:   "clarity",
+		Short: "A software design tool for AI-native developers and coding agents.",
+		Long: `A software design tool for AI-native developers and coding agents.
 
 Use cases:
 - Keep a live impact view while coding with "clarity watch"
 - Generate focused change snapshots with "clarity show"
 - Run repeatable design checks in developer and coding-agent workflows`,
-	Version: version,
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		verbose, _ := cmd.Flags().GetBool("verbose")
-		level := slog.LevelWarn
-		if verbose {
-			level = slog.LevelDebug
-		}
-		slog.SetDefault(slog.New(slog.NewTextHandler(cmd.ErrOrStderr(), &slog.HandlerOptions{
-			Level: level,
-		})))
-		mcplogdlog.Info("command start", map[string]any{
-			"command":   cmd.Name(),
-			"version":   version,
-			"commit":    commit,
-			"buildDate": buildDate,
-		})
-
-		if cpuProfilePath != "" {
-			f, err := os.Create(cpuProfilePath)
+		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			level, err := cliconfig.LogLevel(cmd)
 			if err != nil {
 				return err
 			}
-			if err := pprof.StartCPUProfile(f); err != nil {
-				_ = f.Close()
+			// Subcommands log through the logger carried by their context; packages
+			// without a command at hand log through the default, which is the same.
+			logger := cliconfig.NewLogger(cmd.ErrOrStderr(), level)
+			slog.SetDefault(logger)
+			parent := cmd.Context()
+			if parent == nil {
+				parent = context.Background()
+			}
+			cmd.SetContext(cliconfig.WithLogger(parent, logger))
+			mcplogdlog.Info("command start", map[string]any{
+				"command":   cmd.Name(),
+				"version":   version,
+				"commit":    commit,
+				"buildDate": buildDate,
+			})
+
+			timeout, err := cliconfig.Timeout(cmd)
+			if err != nil {
 				return err
 			}
-			cpuProfileFile = f
-		}
-		return nil
-	},
-	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		if cpuProfileFile != nil {
-			pprof.StopCPUProfile()
-			_ = cpuProfileFile.Close()
-			cpuProfileFile = nil
-		}
-	},
+			if timeout > 0 {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(ctx)
+				cancelTimeout = cancel
+			}
+
+			if cpuProfilePath != "" {
+				f, err := os.Create(cpuProfilePath)
+				if err != nil {
+					return err
+				}
+				if err := pprof.StartCPUProfile(f); err != nil {
+					_ = f.Close()
+					return err
+				}
+				cpuProfileFile = f
+			}
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if cancelTimeout != nil {
+				cancelTimeout()
+				cancelTimeout = nil
+			}
+			if cpuProfileFile != nil {
+				pprof.StopCPUProfile()
+				_ = cpuProfileFile.Close()
+				cpuProfileFile = nil
+			}
+		},
+	}
+
+	// Register subcommands
+	root.AddCommand(show.NewCommand())
+	root.AddCommand(filescmd.NewCommand())
+	root.AddCommand(statscmd.NewCommand())
+	root.AddCommand(neighborscmd.NewCommand())
+	root.AddCommand(tuicmd.NewCommand())
+	root.AddCommand(workspacecmd.NewCommand())
+	root.AddCommand(languages.NewCommand())
+	root.AddCommand(extensionscmd.NewCommand())
+	root.AddCommand(setupcmd.NewCommand())
+	root.AddCommand(watchcmd.NewCommand())
+	root.AddCommand(trendcmd.NewCommand())
+	root.AddCommand(impactcmd.NewCommand())
+	root.AddCommand(historycmd.NewCommand())
+	root.AddCommand(checkcmd.NewCommand())
+	root.AddCommand(lintcmd.NewCommand())
+	root.AddCommand(cachecmd.NewCommand())
+	if devCommands {
+		root.AddCommand(diffcmd.NewCommand())
+		root.AddCommand(whycmd.NewCommand())
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+
+	// Global flags inherited by all subcommands.
+	root.PersistentFlags().BoolP(cliconfig.VerboseFlag, "v", false, "Enable verbose/debug output")
+	root.PersistentFlags().BoolP("version", "V", false, "Print version information and exit")
+	root.PersistentFlags().StringVar(&cpuProfilePath, "cpu-profile", "", "Write CPU profile to file")
+	cliconfig.AddGlobalFlags(root.PersistentFlags())
+
+	return root
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -95,24 +166,6 @@ func Execute() {
 }
 
 func init() {
-	// Register subcommands
-	rootCmd.AddCommand(show.Cmd)
-	rootCmd.AddCommand(workspacecmd.Cmd)
-	rootCmd.AddCommand(languages.Cmd)
-	rootCmd.AddCommand(extensionscmd.Cmd)
-	rootCmd.AddCommand(setupcmd.Cmd)
-	rootCmd.AddCommand(watchcmd.Cmd)
-	if isDevelopmentBuild(enableDevCommands) {
-		rootCmd.AddCommand(diffcmd.Cmd)
-		rootCmd.AddCommand(whycmd.Cmd)
-	}
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
-
-	// Global flags inherited by all subcommands.
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose/debug output")
-	rootCmd.PersistentFlags().BoolP("version", "V", false, "Print version information and exit")
-	rootCmd.PersistentFlags().StringVar(&cpuProfilePath, "cpu-profile", "", "Write CPU profile to file")
-
 	// Initialize annotations for version template
 	if rootCmd.Annotations == nil {
 		rootCmd.Annotations = make(map[string]string)
@@ -1,6 +1,18 @@
 package cmd
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
 
 func TestRootCommand_AlwaysRegistersWatch(t *testing.T) {
 	t.Parallel()
@@ -39,3 +51,245 @@ func TestIsDevelopmentBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestRootCommand_RepoFlagBeforeOrAfterSubcommand(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRootTestFile(t, repoDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeRootTestFile(t, repoDir, "a.go", "package app\n\nfunc A() int { return B() }\n")
+	writeRootTestFile(t, repoDir, "b.go", "package app\n\nfunc B() int { return 1 }\n")
+	rootTestGit(t, repoDir, "init")
+	rootTestGit(t, repoDir, "config", "user.name", "test")
+	rootTestGit(t, repoDir, "config", "user.email", "test@example.com")
+	rootTestGit(t, repoDir, "add", ".")
+	rootTestGit(t, repoDir, "commit", "-m", "initial")
+	writeRootTestFile(t, repoDir, "b.go", "package app\n\nfunc B() int { return 2 }\n")
+
+	tests := []struct {
+		subcommand []string
+		args       []string
+	}{
+		{subcommand: []string{"show"}, args: []string{"-i", "a.go,b.go"}},
+		{subcommand: []string{"why"}, args: []string{"a.go", "b.go"}},
+		{subcommand: []string{"diff"}},
+		{subcommand: []string{"trend"}},
+		{subcommand: []string{"impact"}, args: []string{"-c", "HEAD"}},
+		{subcommand: []string{"workspace"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.subcommand[0], func(t *testing.T) {
+			before := append(append([]string{"--repo", repoDir}, tc.subcommand...), tc.args...)
+			after := append(append(append([]string{}, tc.subcommand...), "--repo", repoDir), tc.args...)
+
+			beforeOut := executeRootCommand(t, before...)
+			afterOut := executeRootCommand(t, after...)
+
+			if beforeOut == "" {
+				t.Fatalf("expected output from %v", before)
+			}
+			if beforeOut != afterOut {
+				t.Fatalf("output differs by flag position\nbefore:\n%s\nafter:\n%s", beforeOut, afterOut)
+			}
+		})
+	}
+}
+
+func TestRootCommand_RepoFlagOutsideRepository(t *testing.T) {
+	nonRepoDir := t.TempDir()
+
+	for _, subcommand := range []string{"diff", "impact", "trend", "watch", "setup"} {
+		t.Run(subcommand, func(t *testing.T) {
+			for _, args := range [][]string{
+				{"-r", nonRepoDir, subcommand},
+				{subcommand, "-r", nonRepoDir},
+			} {
+				root := newRootCommand(true)
+				root.SetArgs(args)
+				root.SetOut(&bytes.Buffer{})
+				root.SetErr(&bytes.Buffer{})
+
+				err := root.Execute()
+				if err == nil {
+					t.Fatalf("expected %v to fail outside a repository", args)
+				}
+				if !strings.Contains(err.Error(), "not inside a git repository") || !strings.Contains(err.Error(), "--repo") {
+					t.Fatalf("expected guidance to pass --repo, got: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRootCommand_HelpPrintsUseCasesFlushLeft(t *testing.T) {
+	output := executeRootCommand(t, "--help")
+
+	if !strings.Contains(output, "\nUse cases:\n- Keep") {
+		t.Fatalf("expected the use cases without indentation, got:\n%s", output)
+	}
+}
+
+func TestRootCommand_LogLevel(t *testing.T) {
+	root := newRootCommand(false)
+	root.SetArgs([]string{"--log-level", "loud", "languages"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "unknown log level: loud") {
+		t.Fatalf("expected unknown log level error, got: %v", err)
+	}
+}
+
+func TestRootCommand_QuietHidesUnsupportedFileWarning(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRootTestFile(t, repoDir, "a.go", "package app\n")
+	writeRootTestFile(t, repoDir, "notes.txt", "notes\n")
+
+	stderr := executeRootCommandStderr(t, "show", "-r", repoDir, "-i", "a.go,notes.txt")
+	if !strings.Contains(stderr, "Warning: dependency extraction is unsupported for 1 file(s) (.txt)") {
+		t.Fatalf("expected the unsupported-file warning, got:\n%s", stderr)
+	}
+
+	stderr = executeRootCommandStderr(t, "--quiet", "show", "-r", repoDir, "-i", "a.go,notes.txt")
+	if strings.Contains(stderr, "Warning:") {
+		t.Fatalf("expected --quiet to hide warnings, got:\n%s", stderr)
+	}
+}
+
+func TestRootCommand_VerboseLogsParseTimings(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRootTestFile(t, repoDir, "a.go", "package app\n\nfunc A() int { return B() }\n")
+	writeRootTestFile(t, repoDir, "b.go", "package app\n\nfunc B() int { return 1 }\n")
+
+	stderr := executeRootCommandStderr(t, "--verbose", "show", "-r", repoDir, "-i", "a.go,b.go", "--no-cache")
+
+	for _, want := range []string{`msg="discovered files" count=2`, `msg="parsed files" language=Go files=2 duration=`} {
+		if !strings.Contains(stderr, want) {
+			t.Fatalf("expected %q in verbose output, got:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestRootCommand_TimeoutCancelsRun(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRootTestFile(t, repoDir, "a.go", "package app\n")
+	rootTestGit(t, repoDir, "init")
+	rootTestGit(t, repoDir, "config", "user.name", "test")
+	rootTestGit(t, repoDir, "config", "user.email", "test@example.com")
+	rootTestGit(t, repoDir, "add", ".")
+	rootTestGit(t, repoDir, "commit", "-m", "initial")
+
+	root := newRootCommand(false)
+	root.SetArgs([]string{"--timeout", "1ns", "impact", "-r", repoDir, "-c", "HEAD"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+
+	err := root.Execute()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the run to stop at the deadline, got: %v", err)
+	}
+}
+
+// slowGitRunner runs git after a delay, cut short when the call's context is done.
+// rev-parse, which locates the repository before the run starts, is not delayed.
+type slowGitRunner struct {
+	delay time.Duration
+}
+
+func (r slowGitRunner) Run(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, []byte, error) {
+	if args[0] != "rev-parse" {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(r.delay):
+		}
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+func TestRootCommand_TimeoutCancelsShowCommitRange(t *testing.T) {
+	repoDir := t.TempDir()
+	rootTestGit(t, repoDir, "init")
+	rootTestGit(t, repoDir, "config", "user.name", "test")
+	rootTestGit(t, repoDir, "config", "user.email", "test@example.com")
+	writeRootTestFile(t, repoDir, "a.go", "package app\n")
+	rootTestGit(t, repoDir, "add", ".")
+	rootTestGit(t, repoDir, "commit", "-m", "first")
+	writeRootTestFile(t, repoDir, "b.go", "package app\n")
+	rootTestGit(t, repoDir, "add", ".")
+	rootTestGit(t, repoDir, "commit", "-m", "second")
+	t.Cleanup(git.SetGitRunner(slowGitRunner{delay: 5 * time.Second}))
+
+	root := newRootCommand(false)
+	root.SetArgs([]string{"show", "-r", repoDir, "-c", "HEAD~1..HEAD", "--timeout", "50ms"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+
+	start := time.Now()
+	err := root.Execute()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the run to stop at the deadline, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected git to be stopped at the deadline, the run took %s", elapsed)
+	}
+}
+
+func executeRootCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	root := newRootCommand(true)
+	root.SetArgs(args)
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&bytes.Buffer{})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("%v failed: %v", args, err)
+	}
+	return stdout.String()
+}
+
+// executeRootCommandStderr runs the root command and returns what it wrote to
+// standard error.
+func executeRootCommandStderr(t *testing.T, args ...string) string {
+	t.Helper()
+
+	root := newRootCommand(false)
+	root.SetArgs(args)
+	root.SetOut(&bytes.Buffer{})
+	var stderr bytes.Buffer
+	root.SetErr(&stderr)
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("%v failed: %v", args, err)
+	}
+	return stderr.String()
+}
+
+func writeRootTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+}
+
+func rootTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
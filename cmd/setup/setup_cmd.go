@@ -9,28 +9,33 @@ import (
 
 	"github.com/spf13/cobra"
 
-	git "github.com/LegacyCodeHQ/clarity/vcs/git"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 )
 
 //go:embed SETUP.md
 var setupTemplate string
 
 // Cmd represents the setup command
-var Cmd = &cobra.Command{
-	Use:   "setup",
-	Short: "Add clarity usage instructions to AGENTS.md",
-	Long:  `Initialize AGENTS.md with instructions for AI agents to use clarity.`,
-	RunE:  runSetup,
+var Cmd = NewCommand()
+
+// NewCommand returns a new setup command instance.
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "setup",
+		Short: "Add clarity usage instructions to AGENTS.md",
+		Long:  `Initialize AGENTS.md with instructions for AI agents to use clarity.`,
+		RunE:  runSetup,
+	}
 }
 
-func runSetup(_ *cobra.Command, _ []string) error {
-	repoRoot, err := git.GetRepositoryRoot(".")
+func runSetup(cmd *cobra.Command, _ []string) error {
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
 	if err != nil {
-		return fmt.Errorf("not a git repository (use 'git init' to initialize)")
+		return fmt.Errorf("%w (use 'git init' to initialize)", err)
 	}
 
 	// Create/update AGENTS.md
-	created, updated, err := writeAgentsFile(filepath.Join(repoRoot, "AGENTS.md"))
+	created, updated, err := writeAgentsFile(filepath.Join(repo.RepoRoot, "AGENTS.md"))
 	if err != nil {
 		return err
 	}
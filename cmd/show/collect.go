@@ -0,0 +1,317 @@
+package show
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+// SelectionReason says why a file was selected for analysis.
+type SelectionReason string
+
+const (
+	// ReasonChanged marks files changed in the analyzed commit, range, or working tree.
+	ReasonChanged SelectionReason = "changed"
+	// ReasonUntracked marks new files not yet known to git.
+	ReasonUntracked SelectionReason = "untracked"
+	// ReasonIncluded marks files named by --input or --input-stdin.
+	ReasonIncluded SelectionReason = "included"
+	// ReasonContext marks files collected so --file or --between can be resolved
+	// against the whole tree.
+	ReasonContext SelectionReason = "context"
+)
+
+// fileSelection is the outcome of the collection and filtering phases of a run: the
+// files to analyze and where their content is read from.
+type fileSelection struct {
+	pathResolver  PathResolver
+	filePaths     []string
+	contentReader vcs.ContentReader
+	fromCommit    string
+	toCommit      string
+	isCommitRange bool
+	// reason is why the files were selected; uncommitted runs refine it per file with
+	// ReasonUntracked.
+	reason SelectionReason
+	// clean reports that there are no uncommitted changes to analyze.
+	clean bool
+}
+
+// addSelectionFlags registers the flags that decide which files a run analyzes, shared
+// by show and files so both select the same files for the same flags.
+func addSelectionFlags(cmd *cobra.Command, opts *graphOptions) {
+	// Add deprecated local aliases of the global repo flags
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cliconfig.AddAllowOutsideRepoAlias(cmd, &opts.allowOutside)
+	// Add commit flag
+	cmd.Flags().StringVarP(&opts.commitID, "commit", "c", "", "Git commit or range to analyze (e.g., f0459ec, HEAD~3, f0459ec...be3d11a)")
+	// Add input flag for explicit files/directories
+	cmd.Flags().StringSliceVarP(&opts.includes, "input", "i", nil, "Build graph from specific files and/or directories (comma-separated)")
+	cmd.Flags().BoolVar(&opts.inputStdin, "input-stdin", false, "Read input files and/or directories from stdin, one per line (blank lines and # comments are ignored)")
+	// Add exclude flag for removing explicit files/directories from graph inputs
+	cmd.Flags().StringSliceVar(&opts.excludes, "exclude", nil, "Exclude specific files, directories, or path patterns from graph inputs (comma-separated)")
+	// Add extension inclusion flag
+	cmd.Flags().StringVar(&opts.includeExt, "include-ext", "", "Include only files with these extensions (comma-separated, e.g. .go,.java)")
+	// Add extension exclusion flag
+	cmd.Flags().StringVar(&opts.excludeExt, "exclude-ext", "", "Exclude files with these extensions (comma-separated, e.g. .go,.java)")
+	// Add glob inclusion flag
+	cmd.Flags().StringSliceVar(&opts.includeGlobs, "include-glob", nil, "Include only files whose repo-relative path matches these patterns (comma-separated, e.g. 'src/**/*.ts')")
+	// Add between flag for finding paths between files
+	cmd.Flags().StringSliceVarP(&opts.betweenFiles, "between", "w", nil, "Find all paths between specified files or path patterns (comma-separated)")
+	// Add file flag for showing dependencies of a specific file
+	cmd.Flags().StringVarP(&opts.targetFile, "file", "p", "", "Show dependencies for a specific file")
+	cmd.Flags().BoolVar(&opts.noPreset, "no-preset", false, "Disable default exclusion of build outputs for detected project types")
+	cmd.Flags().BoolVar(&opts.hideGenerated, "hide-generated", false, "Leave out files under vendor/ and files with a \"Code generated ... DO NOT EDIT.\" header")
+	cmd.Flags().BoolVar(&opts.followSymlinks, "follow-symlinks", false, "Walk into symlinked directories when expanding input directories outside git")
+	cmd.Flags().BoolVar(&opts.noGitignore, "no-gitignore", false, "Include files ignored by .gitignore when expanding input directories")
+	cmd.Flags().IntVar(&opts.maxFiles, "max-files", defaultMaxFiles, "Abort when expanding input directories finds more files than this (0 = no limit)")
+	cmd.Flags().StringVar(&opts.uncommitted, "uncommitted", uncommittedAll, fmt.Sprintf("Uncommitted changes to analyze without --commit (%s)", supportedUncommittedSelections()))
+	cmd.Flags().BoolVar(&opts.includeUntracked, "include-untracked", true, "Include untracked files in uncommitted changes")
+	cmd.Flags().StringArrayVar(&opts.withRepos, "with-repo", nil, "Also analyze the uncommitted changes of this repository in the same graph, e.g. a sibling checkout linked by go.work (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.goModuleFlags, "go-module", nil, "Map a Go import path prefix to a directory, as prefix=dir, instead of reading go.mod files (repeatable)")
+}
+
+// addParseFlags registers the flags that control how the selected files are parsed.
+func addParseFlags(cmd *cobra.Command, opts *graphOptions) {
+	cmd.Flags().BoolVar(&opts.noCache, "no-cache", false, "Parse every file again without reading or writing the parse cache")
+	cmd.Flags().IntVar(&opts.parallelism, "parallelism", 0, "Maximum number of files parsed at once (0 = one per CPU)")
+	cmd.Flags().BoolVar(&opts.lenient, "lenient", false, "Skip files the intra-package analysis cannot read or parse, with a warning, instead of failing")
+}
+
+// collectFiles runs the collection and filtering phases of a run: it resolves the
+// repository and commit range, collects the files the flags select, and applies the
+// preset, path, and extension filters. Readers it opens are released with resources.
+func collectFiles(cmd *cobra.Command, opts *graphOptions, resources *runResources) (fileSelection, error) {
+	ctx := commandContext(cmd)
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{AllowNonRepo: true})
+	if err != nil {
+		return fileSelection{}, err
+	}
+	pathResolver, err := NewPathResolver(repo.RepoPath, repo.AllowOutsideRepo)
+	if err != nil {
+		return fileSelection{}, fmt.Errorf("failed to create path resolver: %w", err)
+	}
+	opts.repoPath = pathResolver.BaseDir()
+	opts.extraRepoRoots, err = resolveExtraRepoRoots(opts.repoPath, opts.withRepos)
+	if err != nil {
+		return fileSelection{}, err
+	}
+	opts.goModules, err = parseGoModules(opts.repoPath, opts.goModuleFlags)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	if opts.inputStdin {
+		stdinPaths, err := readInputPaths(cmd.InOrStdin())
+		if err != nil {
+			return fileSelection{}, fmt.Errorf("failed to read input paths from stdin: %w", err)
+		}
+		opts.includes = append(opts.includes, stdinPaths...)
+	}
+
+	// A bare repository has no working tree, so only commits can be analyzed, and
+	// always from git objects.
+	bare, _ := git.IsBareRepository(opts.repoPath)
+	if bare && opts.commitID == "" {
+		return fileSelection{}, fmt.Errorf("%s is a bare repository with no working tree: pass --commit to analyze a commit", opts.repoPath)
+	}
+
+	fromCommit, toCommit, isCommitRange, err := parseCommitRange(ctx, opts)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	selection := fileSelection{
+		pathResolver:  pathResolver,
+		fromCommit:    fromCommit,
+		toCommit:      toCommit,
+		isCommitRange: isCommitRange,
+		reason:        selectionReasonFor(opts),
+	}
+
+	filePaths, clean, err := determineFilePaths(ctx, opts, pathResolver, fromCommit, toCommit, isCommitRange)
+	if err != nil {
+		return fileSelection{}, err
+	}
+	if clean {
+		selection.clean = true
+		return selection, nil
+	}
+
+	filePaths, err = canonicalizeSymlinkedFiles(ctx, opts, repo.AllowOutsideRepo, toCommit, filePaths)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	// Changed files are listed with --include-ext already applied by git, so it is
+	// the first filter to report that no files remain.
+	filePaths, err = applyIncludeExtensionFilter(opts, filePaths)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	contentReader := selectContentReader(ctx, resources, opts.repoPath, toCommit)
+
+	filePaths, err = applyPresetFilter(cmd, opts, pathResolver, filePaths, contentReader)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	filePaths, err = applyGeneratedFilter(opts, filePaths, contentReader)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	filePaths, err = applyExcludePathFilter(opts, pathResolver, filePaths)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	filePaths, err = applyExcludeExtensionFilter(opts, filePaths)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	filePaths, err = applyIncludeGlobFilter(opts, pathResolver, filePaths)
+	if err != nil {
+		return fileSelection{}, err
+	}
+
+	selection.filePaths = filePaths
+	selection.contentReader = contentReader
+	return selection, nil
+}
+
+// canonicalizeSymlinkedFiles loads the repository's symlinks into opts.pathAliases
+// and replaces the paths reached through them with the paths they point at, so each
+// file is one node however the flags or its importers name it.
+func canonicalizeSymlinkedFiles(ctx context.Context, opts *graphOptions, allowOutside bool, toCommit string, filePaths []string) ([]string, error) {
+	aliases, outside, err := loadPathAliases(ctx, opts.repoPath, toCommit, allowOutside, filePaths)
+	if err != nil {
+		return nil, err
+	}
+	opts.pathAliases = aliases
+
+	var commitFiles []string
+	if toCommit != "" && len(aliases) > 0 {
+		commitFiles, err = git.GetCommitTreeFilesContext(ctx, opts.repoPath, toCommit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get files from commit tree: %w", err)
+		}
+		if commitFiles == nil {
+			commitFiles = []string{}
+		}
+	}
+	canonicalPaths, err := canonicalizeFilePaths(filePaths, aliases, outside, commitFiles, opts.walkOptions())
+	if err != nil {
+		return nil, err
+	}
+	if toCommit == "" {
+		canonicalPaths = dropDirectories(canonicalPaths)
+	}
+	if len(canonicalPaths) == 0 && len(filePaths) > 0 {
+		return nil, fmt.Errorf("no files remain after resolving symlinks; pass --allow-outside-repo to follow symlinks leading outside the repository")
+	}
+	return canonicalPaths, nil
+}
+
+// resolveExtraRepoRoots returns the root of the repository holding each --with-repo
+// path, resolving relative paths against repoPath. Roots equal to repoPath's root and
+// repeated roots are dropped.
+func resolveExtraRepoRoots(repoPath string, withRepos []string) ([]string, error) {
+	if len(withRepos) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool)
+	if root, err := git.GetRepositoryRoot(repoPath); err == nil {
+		seen[root] = true
+	}
+
+	var roots []string
+	for _, withRepo := range withRepos {
+		path := withRepo
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(repoPath, path)
+		}
+		root, err := git.GetRepositoryRoot(path)
+		if err != nil {
+			return nil, fmt.Errorf("--with-repo %s: %w", withRepo, err)
+		}
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// parseGoModules parses --go-module values of the form prefix=dir into a map of Go
+// import path prefixes to absolute directories, relative ones resolved against
+// repoPath. A prefix may be mapped only once.
+func parseGoModules(repoPath string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	modules := make(map[string]string, len(values))
+	for _, value := range values {
+		prefix, dir, ok := strings.Cut(value, "=")
+		prefix, dir = strings.TrimSuffix(strings.TrimSpace(prefix), "/"), strings.TrimSpace(dir)
+		if !ok || prefix == "" || dir == "" {
+			return nil, fmt.Errorf("invalid --go-module %q: expected an import path prefix and a directory, e.g. github.com/acme/monorepo=.", value)
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(repoPath, dir)
+		}
+		dir = filepath.Clean(dir)
+		if existing, seen := modules[prefix]; seen && existing != dir {
+			return nil, fmt.Errorf("invalid --go-module %q: %s is already mapped to %s", value, prefix, existing)
+		}
+		modules[prefix] = dir
+	}
+	return modules, nil
+}
+
+// selectionReasonFor returns why determineFilePaths selects files for opts, following
+// the same precedence.
+func selectionReasonFor(opts *graphOptions) SelectionReason {
+	switch {
+	case len(opts.includes) > 0, opts.inputStdin:
+		return ReasonIncluded
+	case len(opts.betweenFiles) > 0:
+		return ReasonContext
+	case opts.commitID != "":
+		return ReasonChanged
+	case opts.targetFile != "":
+		return ReasonContext
+	default:
+		return ReasonChanged
+	}
+}
+
+// readInputPaths reads newline-delimited paths for --input-stdin. Blank lines and
+// lines starting with # are skipped; paths are kept whole, so they may contain commas.
+func readInputPaths(r io.Reader) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
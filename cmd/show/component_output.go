@@ -0,0 +1,162 @@
+package show
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+const componentIndexFileName = "index.txt"
+
+// isOutputDirectory reports whether -o names a directory: an existing one, or a path
+// ending in a separator.
+func isOutputDirectory(outputPath string) bool {
+	if outputPath == "" {
+		return false
+	}
+	if strings.HasSuffix(outputPath, "/") || strings.HasSuffix(outputPath, string(filepath.Separator)) {
+		return true
+	}
+	info, err := os.Stat(outputPath)
+	return err == nil && info.IsDir()
+}
+
+// graphComponent is one weakly connected component written to its own file.
+type graphComponent struct {
+	fileName string
+	files    []string
+	graph    depgraph.FileDependencyGraph
+}
+
+// emitComponentOutputs writes one file per weakly connected component of fileGraph
+// into the -o directory, plus an index listing the components.
+func emitComponentOutputs(cmd *cobra.Command, opts *graphOptions, format formatters.OutputFormat, formatter formatters.Formatter, fileGraph depgraph.FileDependencyGraph, renderOpts formatters.RenderOptions) error {
+	components, err := splitComponents(fileGraph, format)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(opts.outputPath, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for i, component := range components {
+		componentOpts := renderOpts
+		componentOpts.Label = componentLabel(renderOpts.Label, i+1, len(components))
+
+		output, err := formatter.Format(component.graph, componentOpts)
+		if err != nil {
+			return fmt.Errorf("failed to format component %d: %w", i+1, err)
+		}
+		data := []byte(output + "\n")
+		if format.IsImage() {
+			data = []byte(output)
+		}
+		if err := os.WriteFile(filepath.Join(opts.outputPath, component.fileName), data, 0o644); err != nil {
+			return fmt.Errorf("failed to write component %d: %w", i+1, err)
+		}
+	}
+
+	index := componentIndex(components, renderOpts.BasePath)
+	if err := os.WriteFile(filepath.Join(opts.outputPath, componentIndexFileName), []byte(index), 0o644); err != nil {
+		return fmt.Errorf("failed to write component index: %w", err)
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d component graph(s) to %s\n", len(components), opts.outputPath)
+	return nil
+}
+
+func splitComponents(fileGraph depgraph.FileDependencyGraph, format formatters.OutputFormat) ([]graphComponent, error) {
+	nodeSets, err := depgraph.WeaklyConnectedComponents(fileGraph.Graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find connected components: %w", err)
+	}
+
+	components := make([]graphComponent, 0, len(nodeSets))
+	for i, nodes := range nodeSets {
+		sub, err := fileGraph.Subgraph(nodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract component %d: %w", i+1, err)
+		}
+		components = append(components, graphComponent{
+			fileName: fmt.Sprintf("%s-%d%s", slugBaseName(mostCentralFile(sub)), i+1, format.FileExtension()),
+			files:    nodes,
+			graph:    sub,
+		})
+	}
+	return components, nil
+}
+
+// mostCentralFile returns the file with the most edges in the component, breaking
+// ties by path so the choice is stable.
+func mostCentralFile(component depgraph.FileDependencyGraph) string {
+	degree := make(map[string]int, len(component.Meta.Files))
+	for edge := range component.Meta.Edges {
+		degree[edge.From]++
+		degree[edge.To]++
+	}
+
+	best := ""
+	for file := range component.Meta.Files {
+		if best == "" || degree[file] > degree[best] || (degree[file] == degree[best] && file < best) {
+			best = file
+		}
+	}
+	return best
+}
+
+// slugBaseName turns a file path into a file-name-safe slug of its base name without
+// extension, e.g. "src/Auth Service.go" becomes "auth_service".
+func slugBaseName(filePath string) string {
+	base := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	if base == "" {
+		base = filepath.Base(filePath)
+	}
+
+	var sb strings.Builder
+	for _, r := range strings.ToLower(base) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+
+	slug := strings.Trim(sb.String(), "_-")
+	if slug == "" {
+		return "component"
+	}
+	return slug
+}
+
+func componentLabel(label string, index, total int) string {
+	componentPart := fmt.Sprintf("component %d of %d", index, total)
+	if label == "" {
+		return componentPart
+	}
+	return label + " • " + componentPart
+}
+
+func componentIndex(components []graphComponent, basePath string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d component(s)\n", len(components))
+	for _, component := range components {
+		fileCount := "files"
+		if len(component.files) == 1 {
+			fileCount = "file"
+		}
+		fmt.Fprintf(&sb, "\n%s (%d %s)\n", component.fileName, len(component.files), fileCount)
+		for _, file := range component.files {
+			fmt.Fprintf(&sb, "  %s\n", repoRelativeSlashPath(basePath, file))
+		}
+	}
+	return sb.String()
+}
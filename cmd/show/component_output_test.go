@@ -0,0 +1,257 @@
+package show
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeThreeComponentRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	files := map[string]string{
+		"auth/auth_service.ts": "import { token } from './token';\nimport { session } from './session';\nexport const auth = token + session;\n",
+		"auth/token.ts":        "export const token = 1;\n",
+		"auth/session.ts":      "export const session = 1;\n",
+		"billing/payment.ts":   "import { card } from './card';\nimport { ledger } from './ledger';\nexport const payment = card + ledger;\n",
+		"billing/card.ts":      "export const card = 1;\n",
+		"billing/ledger.ts":    "export const ledger = 1;\n",
+		"tools/lonely_tool.ts": "export const lonely = 1;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
+
+func TestGraphOutputDirectory_WritesOneFilePerComponent(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outDir := t.TempDir()
+
+	stdout, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "-o", outDir)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if stdout != "" {
+		t.Fatalf("expected nothing on stdout, got:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "Wrote 3 component graph(s)") {
+		t.Fatalf("expected summary on stderr, got:\n%s", stderr)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatalf("os.ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{"auth_service-1.dot", "index.txt", "lonely_tool-3.dot", "payment-2.dot"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("output files = %v, want %v", names, want)
+	}
+
+	auth := readFile(t, filepath.Join(outDir, "auth_service-1.dot"))
+	if !strings.Contains(auth, `"auth/auth_service.ts" -> "auth/token.ts"`) {
+		t.Fatalf("expected auth edges in first component, got:\n%s", auth)
+	}
+	if strings.Contains(auth, "payment") || strings.Contains(auth, "lonely") {
+		t.Fatalf("expected only auth files in first component, got:\n%s", auth)
+	}
+	if !strings.Contains(auth, "component 1 of 3") {
+		t.Fatalf("expected component number in label, got:\n%s", auth)
+	}
+
+	payment := readFile(t, filepath.Join(outDir, "payment-2.dot"))
+	if !strings.Contains(payment, `"billing/payment.ts" -> "billing/ledger.ts"`) || !strings.Contains(payment, "component 2 of 3") {
+		t.Fatalf("expected billing component, got:\n%s", payment)
+	}
+
+	lonely := readFile(t, filepath.Join(outDir, "lonely_tool-3.dot"))
+	if !strings.Contains(lonely, `"lonely_tool.ts"`) || strings.Contains(lonely, "->") {
+		t.Fatalf("expected single isolated file, got:\n%s", lonely)
+	}
+
+	index := readFile(t, filepath.Join(outDir, "index.txt"))
+	wantIndex := `3 component(s)
+
+auth_service-1.dot (3 files)
+  auth/auth_service.ts
+  auth/session.ts
+  auth/token.ts
+
+payment-2.dot (3 files)
+  billing/card.ts
+  billing/ledger.ts
+  billing/payment.ts
+
+lonely_tool-3.dot (1 file)
+  tools/lonely_tool.ts
+`
+	if index != wantIndex {
+		t.Fatalf("index =\n%s\nwant\n%s", index, wantIndex)
+	}
+}
+
+func TestGraphOutputDirectory_TrailingSeparatorCreatesDirectory(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outDir := filepath.Join(t.TempDir(), "graphs") + string(filepath.Separator)
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "mermaid", "-o", outDir)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "payment-2.mmd")); err != nil {
+		t.Fatalf("expected mermaid component file: %v", err)
+	}
+}
+
+func TestGraphOutputFile_WritesHTMLReport(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outFile := filepath.Join(t.TempDir(), "report.html")
+
+	stdout, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "html", "-o", outFile)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if stdout != "" {
+		t.Fatalf("expected nothing on stdout, got:\n%s", stdout)
+	}
+	output := readFile(t, outFile)
+	if !strings.HasPrefix(output, "<!DOCTYPE html>") || !strings.Contains(output, `<pre class="mermaid">`) {
+		t.Fatalf("expected an HTML report, got:\n%s", output)
+	}
+	if !strings.Contains(output, "payment.ts") || !strings.Contains(output, " files</h1>") {
+		t.Fatalf("expected the graph and its label in the report, got:\n%s", output)
+	}
+}
+
+func TestGraphOutputFile_WritesSingleGraph(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outFile := filepath.Join(t.TempDir(), "graph.dot")
+
+	stdout, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "-o", outFile)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if stdout != "" {
+		t.Fatalf("expected nothing on stdout, got:\n%s", stdout)
+	}
+	output := readFile(t, outFile)
+	if !strings.Contains(output, "auth_service.ts") || !strings.Contains(output, "payment.ts") {
+		t.Fatalf("expected the whole graph in one file, got:\n%s", output)
+	}
+	if strings.Contains(output, "component 1 of") {
+		t.Fatalf("expected no component label in single-file output, got:\n%s", output)
+	}
+}
+
+func TestGraphOutputDirectory_WithURL_ReturnsError(t *testing.T) {
+	_, _, err := runShow(t, nil, "-u", "-o", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "--url cannot be used when --output is a directory") {
+		t.Fatalf("expected --url validation error, got %v", err)
+	}
+}
+
+func TestSlugBaseName(t *testing.T) {
+	tests := map[string]string{
+		"src/auth_service.go":  "auth_service",
+		"src/Auth Service.ts":  "auth_service",
+		"lib/.hidden":          "hidden",
+		"lib/__init__.py":      "init",
+		"lib/größe.kt":         "größe",
+		"lib/***.txt":          "component",
+		"payment-gateway.dart": "payment-gateway",
+	}
+	for input, want := range tests {
+		if got := slugBaseName(input); got != want {
+			t.Errorf("slugBaseName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	return string(content)
+}
+
+func TestGraphOutputFile_CreatesParentDirectories(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outFile := filepath.Join(t.TempDir(), "reports", "nested", "graph.dot")
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "-o", outFile)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if output := readFile(t, outFile); !strings.HasPrefix(output, "digraph") {
+		t.Fatalf("expected the graph in the nested file, got:\n%s", output)
+	}
+	entries, err := os.ReadDir(filepath.Dir(outFile))
+	if err != nil {
+		t.Fatalf("os.ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the output file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestGraphOutputFile_ExistingFileRequiresForce(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outFile := filepath.Join(t.TempDir(), "graph.dot")
+	if err := os.WriteFile(outFile, []byte("previous\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "-o", outFile)
+	if err == nil || !strings.Contains(err.Error(), "already exists (pass --force to overwrite)") {
+		t.Fatalf("expected an already-exists error, got %v", err)
+	}
+	if output := readFile(t, outFile); output != "previous\n" {
+		t.Fatalf("expected the existing file to be kept, got:\n%s", output)
+	}
+
+	_, _, err = runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "-o", outFile, "--force")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if output := readFile(t, outFile); !strings.HasPrefix(output, "digraph") {
+		t.Fatalf("expected --force to replace the file, got:\n%s", output)
+	}
+}
+
+func TestGraphOutputFile_WithURL_WritesURLAndConfirms(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	outFile := filepath.Join(t.TempDir(), "graph.url")
+
+	stdout, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "-u", "-o", outFile)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if output := readFile(t, outFile); !strings.HasPrefix(output, "https://") {
+		t.Fatalf("expected the URL in the file, got:\n%s", output)
+	}
+	if stdout != "Wrote visualization URL to "+outFile+"\n" {
+		t.Fatalf("expected a confirmation line on stdout, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,191 @@
+package show
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// defaultMaxFiles is the number of files directory expansion may collect before it
+// aborts, so a stray symlink to a large tree fails fast instead of walking it.
+const defaultMaxFiles = 200000
+
+// walkOptions controls how directories given as inputs are expanded.
+type walkOptions struct {
+	// followSymlinks descends into symlinked directories. By default they are skipped,
+	// as git does.
+	followSymlinks bool
+	// maxFiles aborts the expansion once more files than this are found; 0 means no
+	// limit.
+	maxFiles int
+	// noGitignore walks directories inside git repositories too, so files ignored by
+	// .gitignore are kept, and walks into the build output directories skipped
+	// outside git. .git directories are always skipped.
+	noGitignore bool
+}
+
+func (opts *graphOptions) walkOptions() walkOptions {
+	return walkOptions{followSymlinks: opts.followSymlinks, maxFiles: opts.maxFiles, noGitignore: opts.noGitignore}
+}
+
+// walkSkippedDirs are directories the walk leaves out, standing in for the .gitignore
+// rules git applies inside a repository.
+var walkSkippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"target":       true,
+	".dart_tool":   true,
+	"build":        true,
+	"__pycache__":  true,
+	".gradle":      true,
+	".idea":        true,
+	".vscode":      true,
+}
+
+// directoryWalker expands directories into files for one run. It counts files across
+// every directory it walks so the limit applies to the whole expansion.
+type directoryWalker struct {
+	opts  walkOptions
+	count int
+	// visited maps the identity of every directory walked so far to its path.
+	visited map[fileIdentity]string
+}
+
+func newDirectoryWalker(opts walkOptions) *directoryWalker {
+	return &directoryWalker{opts: opts, visited: make(map[fileIdentity]string)}
+}
+
+// checkLimit counts n more files, the last of which is at, and fails once the limit
+// is exceeded.
+func (w *directoryWalker) checkLimit(at string, n int) error {
+	w.count += n
+	if w.opts.maxFiles > 0 && w.count > w.opts.maxFiles {
+		return fmt.Errorf("more than %d files to analyze, stopped at %s: narrow --input or raise --max-files", w.opts.maxFiles, at)
+	}
+	return nil
+}
+
+// walk is the fallback for directories outside a git repository, and expands every
+// directory when noGitignore is set. Symlinks to files are
+// kept; symlinks to directories are skipped unless followSymlinks is set, in which case
+// a link back to a directory being walked aborts the walk.
+func (w *directoryWalker) walk(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	if err := w.walkDir(dir, info, nil, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// fileIdentity identifies a directory independently of the path it is reached by.
+type fileIdentity struct {
+	dev, ino uint64
+	// path is the resolved path, used where device and inode numbers are unavailable.
+	path string
+}
+
+// resolvedIdentity identifies a directory by its path with symlinks resolved.
+func resolvedIdentity(path string) fileIdentity {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		path = abs
+	}
+	return fileIdentity{path: path}
+}
+
+// ancestorDir is a directory on the path from the walk root to the current directory.
+type ancestorDir struct {
+	path string
+	id   fileIdentity
+}
+
+func (w *directoryWalker) walkDir(dir string, info os.FileInfo, ancestors []ancestorDir, files *[]string) error {
+	id := identityOf(dir, info)
+	for _, ancestor := range ancestors {
+		if ancestor.id == id {
+			return fmt.Errorf("symlink cycle: %s leads back to %s", dir, ancestor.path)
+		}
+	}
+	if first, ok := w.visited[id]; ok {
+		slog.Debug("skipping directory already walked through another path", "path", dir, "walked_as", first)
+		return nil
+	}
+	w.visited[id] = dir
+	ancestors = append(ancestors, ancestorDir{path: dir, id: id})
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		entryPath := filepath.Join(dir, entry.Name())
+
+		if entry.Type()&os.ModeSymlink != 0 {
+			target, err := os.Stat(entryPath)
+			if err != nil {
+				slog.Debug("skipping broken symlink", "path", entryPath, "error", err)
+				continue
+			}
+			if !target.IsDir() {
+				if err := w.addFile(entryPath, files); err != nil {
+					return err
+				}
+				continue
+			}
+			if !w.opts.followSymlinks {
+				slog.Debug("skipping symlinked directory; pass --follow-symlinks to walk it", "path", entryPath)
+				continue
+			}
+			if w.skipsDir(entry.Name()) {
+				continue
+			}
+			if err := w.walkDir(entryPath, target, ancestors, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if entry.IsDir() {
+			if w.skipsDir(entry.Name()) {
+				continue
+			}
+			entryInfo, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := w.walkDir(entryPath, entryInfo, ancestors, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := w.addFile(entryPath, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipsDir reports whether the walk leaves out directories with the given name.
+func (w *directoryWalker) skipsDir(name string) bool {
+	if w.opts.noGitignore {
+		return name == ".git"
+	}
+	return walkSkippedDirs[name]
+}
+
+func (w *directoryWalker) addFile(filePath string, files *[]string) error {
+	if err := w.checkLimit(filePath, 1); err != nil {
+		return err
+	}
+	*files = append(*files, filePath)
+	return nil
+}
@@ -0,0 +1,138 @@
+package show
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSymlinkCycleDir creates root/a/main.go and root/a/b/loop -> root/a, so
+// following symlinks would walk root/a/b/loop/b/loop/... forever.
+func writeSymlinkCycleDir(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..", "..", "a"), filepath.Join(root, "a", "b", "loop")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	return root
+}
+
+func TestDirectoryWalker_SkipsSymlinkedDirectoriesByDefault(t *testing.T) {
+	root := writeSymlinkCycleDir(t)
+
+	files, err := newDirectoryWalker(walkOptions{}).walk(root)
+	if err != nil {
+		t.Fatalf("walk() error = %v", err)
+	}
+	want := []string{filepath.Join(root, "a", "main.go")}
+	if len(files) != 1 || files[0] != want[0] {
+		t.Fatalf("walk() = %v, want %v", files, want)
+	}
+}
+
+func TestDirectoryWalker_FollowSymlinks_CycleAbortsWithPath(t *testing.T) {
+	root := writeSymlinkCycleDir(t)
+
+	_, err := newDirectoryWalker(walkOptions{followSymlinks: true}).walk(root)
+	if err == nil {
+		t.Fatal("walk() error = nil, want symlink cycle error")
+	}
+	loop := filepath.Join(root, "a", "b", "loop")
+	if !strings.Contains(err.Error(), "symlink cycle: "+loop+" leads back to "+filepath.Join(root, "a")) {
+		t.Fatalf("walk() error = %v, want cycle at %s", err, loop)
+	}
+}
+
+func TestDirectoryWalker_FollowSymlinks_WalksLinkedDirectoryOnce(t *testing.T) {
+	root := t.TempDir()
+	shared := filepath.Join(root, "shared")
+	if err := os.MkdirAll(shared, 0o755); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(shared, "util.go"), []byte("package shared\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.Symlink(shared, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	files, err := newDirectoryWalker(walkOptions{followSymlinks: true}).walk(root)
+	if err != nil {
+		t.Fatalf("walk() error = %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("walk() = %v, want the shared file once", files)
+	}
+}
+
+func TestDirectoryWalker_MaxFilesAbortsDuringWalk(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("package main\n"), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	_, err := newDirectoryWalker(walkOptions{maxFiles: 2}).walk(root)
+	if err == nil {
+		t.Fatal("walk() error = nil, want file limit error")
+	}
+	want := "more than 2 files to analyze, stopped at " + filepath.Join(root, "c.go")
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("walk() error = %v, want %q", err, want)
+	}
+}
+
+func TestGraphInput_FollowSymlinks_CycleTerminatesWithError(t *testing.T) {
+	root := writeSymlinkCycleDir(t)
+
+	_, _, err := runShow(t, nil, "-r", root, "-i", ".", "--no-stats", "--follow-symlinks")
+	if err == nil || !strings.Contains(err.Error(), "symlink cycle") {
+		t.Fatalf("expected symlink cycle error, got %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", root, "-i", ".", "--no-stats")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "main.go") || strings.Contains(output, "loop") {
+		t.Fatalf("expected only a/main.go without following the link, got:\n%s", output)
+	}
+}
+
+func TestDirectoryWalker_NoGitignoreWalksBuildDirectoriesButNotGit(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "build/gen.go", ".git/HEAD"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	files, err := newDirectoryWalker(walkOptions{}).walk(root)
+	if err != nil {
+		t.Fatalf("walk() error = %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("walk() = %v, want only main.go", files)
+	}
+
+	files, err = newDirectoryWalker(walkOptions{noGitignore: true}).walk(root)
+	if err != nil {
+		t.Fatalf("walk() error = %v", err)
+	}
+	want := []string{filepath.Join(root, "build", "gen.go"), filepath.Join(root, "main.go")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("walk() = %v, want %v", files, want)
+	}
+}
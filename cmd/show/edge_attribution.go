@@ -0,0 +1,97 @@
+package show
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+const defaultAttributeMaxCommits = 20
+
+// attributeEdges finds, for every edge of graph that is absent at fromCommit, the first
+// commit in fromCommit..toCommit whose tree contains it. The graph is rebuilt over the
+// same nodes at each commit boundary, so ranges are capped at opts.attributeMaxCommits.
+func attributeEdges(ctx context.Context, opts *graphOptions, resources *runResources, fromCommit, toCommit string, graph depgraph.DependencyGraph) (map[depgraph.FileEdge]string, error) {
+	commits, err := git.GetCommitRangeCommitsContext(ctx, opts.repoPath, fromCommit, toCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits for --attribute-edges: %w", err)
+	}
+	if len(commits) > opts.attributeMaxCommits {
+		return nil, fmt.Errorf("--attribute-edges: range %s...%s spans %d commits, more than the limit of %d (narrow the range or raise --attribute-max-commits)",
+			fromCommit, toCommit, len(commits), opts.attributeMaxCommits)
+	}
+
+	nodes, err := graphFiles(graph)
+	if err != nil {
+		return nil, err
+	}
+	finalEdges, err := edgeSet(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := edgesAtCommit(ctx, resources, opts.repoPath, fromCommit, nodes)
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[depgraph.FileEdge]bool)
+	for edge := range finalEdges {
+		if !baseline[edge] {
+			pending[edge] = true
+		}
+	}
+
+	introducedIn := make(map[depgraph.FileEdge]string, len(pending))
+	for _, commit := range commits {
+		if len(pending) == 0 {
+			break
+		}
+		edges, err := edgesAtCommit(ctx, resources, opts.repoPath, commit, nodes)
+		if err != nil {
+			return nil, err
+		}
+		for edge := range pending {
+			if edges[edge] {
+				introducedIn[edge] = commit
+				delete(pending, edge)
+			}
+		}
+	}
+
+	return introducedIn, nil
+}
+
+// edgesAtCommit builds the graph over the nodes that exist in commit's tree.
+func edgesAtCommit(ctx context.Context, resources *runResources, repoPath, commit string, nodes []string) (map[depgraph.FileEdge]bool, error) {
+	contentReader := resources.openCommitContentReader(ctx, repoPath, commit)
+
+	files := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if contentReader.Exists(node) {
+			files = append(files, node)
+		}
+	}
+
+	graph, _, err := depgraph.BuildDependencyGraphContext(ctx, files, contentReader, depgraph.BuildOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph at %s: %w", commit, err)
+	}
+	return edgeSet(graph)
+}
+
+func edgeSet(graph depgraph.DependencyGraph) (map[depgraph.FileEdge]bool, error) {
+	adjacency, err := depgraph.AdjacencyList(graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build adjacency list: %w", err)
+	}
+
+	edges := make(map[depgraph.FileEdge]bool)
+	for from, deps := range adjacency {
+		for _, to := range deps {
+			edges[depgraph.FileEdge{From: from, To: to}] = true
+		}
+	}
+	return edges, nil
+}
@@ -0,0 +1,82 @@
+package show
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAttributionRepo creates a base commit and three commits that each add one edge.
+func writeAttributionRepo(t *testing.T) (repoDir, base string, commits []string) {
+	t.Helper()
+	repoDir = t.TempDir()
+	gitInitRepo(t, repoDir)
+
+	commit := func(message string, files map[string]string) string {
+		t.Helper()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+				t.Fatalf("os.WriteFile() error = %v", err)
+			}
+		}
+		gitRun(t, repoDir, "add", ".")
+		gitRun(t, repoDir, "commit", "-m", message)
+		out, err := exec.Command("git", "-C", repoDir, "rev-parse", "--short", "HEAD").Output()
+		if err != nil {
+			t.Fatalf("git rev-parse error = %v", err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	base = commit("base", map[string]string{
+		"a.ts": "export const a = 1;\n",
+		"b.ts": "export const b = 1;\n",
+		"c.ts": "export const c = 1;\n",
+	})
+	commits = append(commits,
+		commit("a uses b", map[string]string{"a.ts": "import { b } from './b';\nexport const a = b;\n"}),
+		commit("b uses c", map[string]string{"b.ts": "import { c } from './c';\nexport const b = c;\n"}),
+		commit("c uses d", map[string]string{
+			"c.ts": "import { d } from './d';\nexport const c = d;\n",
+			"d.ts": "export const d = 1;\n",
+		}),
+	)
+	return repoDir, base, commits
+}
+
+func TestGraphCommitRange_AttributeEdges_TooltipNamesIntroducingCommit(t *testing.T) {
+	repoDir, base, commits := writeAttributionRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", base+"...HEAD", "-f", "dot", "--attribute-edges")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	for i, edge := range []string{`"a.ts" -> "b.ts"`, `"b.ts" -> "c.ts"`, `"c.ts" -> "d.ts"`} {
+		want := edge + ` [tooltip="introduced in ` + commits[i] + `"]`
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %s, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraphCommitRange_AttributeEdges_RefusesLongRange(t *testing.T) {
+	repoDir, base, _ := writeAttributionRepo(t)
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-c", base+"...HEAD", "-f", "dot", "--attribute-edges", "--attribute-max-commits", "2")
+	if err == nil {
+		t.Fatal("expected error for a range longer than --attribute-max-commits")
+	}
+	if !strings.Contains(err.Error(), "spans 3 commits, more than the limit of 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGraphCommit_AttributeEdges_RequiresRange(t *testing.T) {
+	_, _, err := runShow(t, nil, "-c", "HEAD", "--attribute-edges")
+	if err == nil || !strings.Contains(err.Error(), "--attribute-edges requires a commit range") {
+		t.Fatalf("expected range requirement error, got %v", err)
+	}
+}
@@ -0,0 +1,448 @@
+package show
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+// Cost constants for --estimate. They are rough figures meant to tell a two-second run
+// from a twenty-minute one; recalibrate them from `clarity --cpuprofile` runs when
+// parsers change.
+const (
+	defaultParseBytesPerSecond = 2 << 20
+	perFileCost                = 300 * time.Microsecond
+	gitProcessCost             = 8 * time.Millisecond
+	// baseGitProcesses covers repository discovery and file listing.
+	baseGitProcesses = 3
+	// statsGitProcesses covers the numstat and name-status calls for file statistics.
+	statsGitProcesses = 2
+	// narrowingThreshold is the projected runtime below which no cheaper invocations
+	// are suggested.
+	narrowingThreshold = 5 * time.Second
+)
+
+// languageParseBytesPerSecond holds parse throughput for languages that differ
+// noticeably from defaultParseBytesPerSecond.
+var languageParseBytesPerSecond = map[string]int64{
+	"Go":         4 << 20,
+	"Kotlin":     1 << 20,
+	"Scala":      1 << 20,
+	"Swift":      1 << 20,
+	"TypeScript": 3 << 20,
+	"JavaScript": 3 << 20,
+	"Dart":       3 << 20,
+}
+
+const otherLanguage = "other"
+
+// estimateFile is one input file and its size in bytes.
+type estimateFile struct {
+	path string
+	size int64
+}
+
+// estimateInput describes the work an invocation would do.
+type estimateInput struct {
+	files []estimateFile
+	// gitContent reports that file content is read from a commit, one git process per file.
+	gitContent bool
+	stats      bool
+	// attributeCommits is the number of commits --attribute-edges would rebuild the
+	// graph at, or 0 when edges are not attributed.
+	attributeCommits int
+}
+
+// languageCost is the projected cost of the files of one language.
+type languageCost struct {
+	language string
+	files    int
+	bytes    int64
+	duration time.Duration
+}
+
+// analysisEstimate is the projected cost of an invocation.
+type analysisEstimate struct {
+	languages    []languageCost
+	files        int
+	bytes        int64
+	gitProcesses int
+	duration     time.Duration
+}
+
+// estimateAnalysis projects the cost of building the graph for in. Languages are
+// ordered by projected time, most expensive first.
+func estimateAnalysis(in estimateInput) analysisEstimate {
+	passes := 1
+	if in.attributeCommits > 0 {
+		// The baseline and every commit in the range are rebuilt.
+		passes += in.attributeCommits + 1
+	}
+
+	byLanguage := make(map[string]*languageCost)
+	parsedFiles := 0
+	for _, file := range in.files {
+		language := otherLanguage
+		if module, ok := registry.ModuleForExtension(filepath.Ext(file.path)); ok {
+			language = module.Name()
+			parsedFiles++
+		}
+		cost, ok := byLanguage[language]
+		if !ok {
+			cost = &languageCost{language: language}
+			byLanguage[language] = cost
+		}
+		cost.files++
+		cost.bytes += file.size
+	}
+
+	var estimate analysisEstimate
+	for _, cost := range byLanguage {
+		cost.duration = time.Duration(cost.files) * perFileCost
+		if cost.language != otherLanguage {
+			throughput, ok := languageParseBytesPerSecond[cost.language]
+			if !ok {
+				throughput = defaultParseBytesPerSecond
+			}
+			cost.duration += time.Duration(float64(cost.bytes) / float64(throughput) * float64(time.Second))
+			cost.duration *= time.Duration(passes)
+		}
+		estimate.languages = append(estimate.languages, *cost)
+		estimate.files += cost.files
+		estimate.bytes += cost.bytes
+		estimate.duration += cost.duration
+	}
+	sort.Slice(estimate.languages, func(i, j int) bool {
+		a, b := estimate.languages[i], estimate.languages[j]
+		if a.duration != b.duration {
+			return a.duration > b.duration
+		}
+		return a.language < b.language
+	})
+
+	estimate.gitProcesses = baseGitProcesses
+	if in.gitContent {
+		// One ls-tree for the tree plus one git show per parsed file.
+		estimate.gitProcesses += parsedFiles + 1
+	}
+	if in.stats {
+		estimate.gitProcesses += statsGitProcesses
+	}
+	if in.attributeCommits > 0 {
+		estimate.gitProcesses += 1 + (in.attributeCommits+1)*(parsedFiles+1)
+	}
+	estimate.duration += time.Duration(estimate.gitProcesses) * gitProcessCost
+
+	return estimate
+}
+
+// narrowing is a cheaper invocation suggested by --estimate.
+type narrowing struct {
+	command  string
+	estimate analysisEstimate
+}
+
+// suggestNarrowings proposes invocations that skip the most expensive work: the costliest
+// language, the largest top-level directory, file statistics, and edge attribution. Only
+// suggestions that reduce the projected runtime are returned, cheapest first, and none
+// when the run is already quick.
+func suggestNarrowings(args commandArgs, in estimateInput, repoPath string, current analysisEstimate) []narrowing {
+	if current.duration < narrowingThreshold {
+		return nil
+	}
+
+	var suggestions []narrowing
+	add := func(args commandArgs, narrowed estimateInput) {
+		estimate := estimateAnalysis(narrowed)
+		if estimate.duration < current.duration {
+			suggestions = append(suggestions, narrowing{command: args.String(), estimate: estimate})
+		}
+	}
+
+	if language, exts := costliestLanguage(current); language != "" {
+		narrowed := in
+		narrowed.files = filterEstimateFiles(in.files, func(file estimateFile) bool {
+			return !containsString(exts, filepath.Ext(file.path))
+		})
+		add(args.appendToList("exclude-ext", strings.Join(exts, ",")), narrowed)
+	}
+
+	if dir := largestTopLevelDir(in.files, repoPath); dir != "" {
+		narrowed := in
+		narrowed.files = filterEstimateFiles(in.files, func(file estimateFile) bool {
+			return !isWithinDir(file.path, filepath.Join(repoPath, dir))
+		})
+		add(args.with("exclude", dir), narrowed)
+	}
+
+	if in.stats {
+		narrowed := in
+		narrowed.stats = false
+		add(args.with("no-stats", ""), narrowed)
+	}
+
+	if in.attributeCommits > 0 {
+		narrowed := in
+		narrowed.attributeCommits = 0
+		add(args.without("attribute-edges").without("attribute-max-commits"), narrowed)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].estimate.duration < suggestions[j].estimate.duration
+	})
+	return suggestions
+}
+
+// costliestLanguage returns the most expensive parsed language and its extensions when
+// the estimate covers more than one parsed language.
+func costliestLanguage(estimate analysisEstimate) (string, []string) {
+	var parsed []string
+	for _, cost := range estimate.languages {
+		if cost.language != otherLanguage {
+			parsed = append(parsed, cost.language)
+		}
+	}
+	if len(parsed) < 2 {
+		return "", nil
+	}
+	for _, language := range registry.SupportedLanguages() {
+		if language.Name == parsed[0] {
+			return language.Name, language.Extensions
+		}
+	}
+	return "", nil
+}
+
+// largestTopLevelDir returns the repo-relative top-level directory holding the most
+// bytes, or "" when the files do not span several top-level entries.
+func largestTopLevelDir(files []estimateFile, repoPath string) string {
+	bytesByEntry := make(map[string]int64)
+	for _, file := range files {
+		rel, err := filepath.Rel(repoPath, file.path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		entry, _, isNested := strings.Cut(filepath.ToSlash(rel), "/")
+		if !isNested {
+			entry = ""
+		}
+		bytesByEntry[entry] += file.size
+	}
+	if len(bytesByEntry) < 2 {
+		return ""
+	}
+
+	largest := ""
+	for entry, size := range bytesByEntry {
+		if entry == "" {
+			continue
+		}
+		if largest == "" || size > bytesByEntry[largest] || (size == bytesByEntry[largest] && entry < largest) {
+			largest = entry
+		}
+	}
+	return largest
+}
+
+func filterEstimateFiles(files []estimateFile, keep func(estimateFile) bool) []estimateFile {
+	kept := make([]estimateFile, 0, len(files))
+	for _, file := range files {
+		if keep(file) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// commandArg is one flag of a reconstructed command line. An empty value is a bare
+// boolean flag.
+type commandArg struct {
+	name  string
+	value string
+}
+
+// commandArgs is a `clarity show` command line rebuilt from the flags the user set.
+type commandArgs []commandArg
+
+// changedFlagArgs rebuilds the flags the user set, leaving out --estimate.
+func changedFlagArgs(cmd *cobra.Command) commandArgs {
+	var args commandArgs
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == "estimate" {
+			return
+		}
+		switch value := flag.Value.(type) {
+		case pflag.SliceValue:
+			for _, item := range value.GetSlice() {
+				args = append(args, commandArg{name: flag.Name, value: item})
+			}
+		default:
+			if flag.Value.Type() == "bool" && flag.Value.String() == "true" {
+				args = append(args, commandArg{name: flag.Name})
+				return
+			}
+			args = append(args, commandArg{name: flag.Name, value: flag.Value.String()})
+		}
+	})
+	return args
+}
+
+func (args commandArgs) with(name, value string) commandArgs {
+	return append(append(commandArgs(nil), args...), commandArg{name: name, value: value})
+}
+
+func (args commandArgs) without(name string) commandArgs {
+	var kept commandArgs
+	for _, arg := range args {
+		if arg.name != name {
+			kept = append(kept, arg)
+		}
+	}
+	return kept
+}
+
+// appendToList adds value to a comma-separated flag that only keeps its last occurrence.
+func (args commandArgs) appendToList(name, value string) commandArgs {
+	for _, arg := range args {
+		if arg.name == name && arg.value != "" {
+			value = arg.value + "," + value
+		}
+	}
+	return args.without(name).with(name, value)
+}
+
+func (args commandArgs) String() string {
+	parts := []string{"clarity", "show"}
+	for _, arg := range args {
+		if arg.value == "" {
+			parts = append(parts, "--"+arg.name)
+			continue
+		}
+		parts = append(parts, "--"+arg.name, shellQuote(arg.value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes value when it contains characters a POSIX shell would interpret.
+func shellQuote(value string) string {
+	safe := strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_./,:=@+-~^", r))
+	}) == -1
+	if safe {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// runEstimate prints the projected cost of building the graph for filePaths without
+// reading their content.
+func runEstimate(cmd *cobra.Command, opts *graphOptions, filePaths []string, fromCommit, toCommit string, isCommitRange bool) error {
+	files, err := estimateFileSizes(opts, filePaths, toCommit)
+	if err != nil {
+		return err
+	}
+
+	in := estimateInput{
+		files:      files,
+		gitContent: toCommit != "" && opts.targetFile == "",
+		stats:      !opts.noStats,
+	}
+	if opts.attributeEdges && isCommitRange {
+		commits, err := git.GetCommitRangeCommitsContext(commandContext(cmd), opts.repoPath, fromCommit, toCommit)
+		if err != nil {
+			return fmt.Errorf("failed to list commits for --attribute-edges: %w", err)
+		}
+		in.attributeCommits = len(commits)
+	}
+
+	estimate := estimateAnalysis(in)
+	suggestions := suggestNarrowings(changedFlagArgs(cmd), in, opts.repoPath, estimate)
+	return writeEstimate(cmd, estimate, suggestions)
+}
+
+// estimateFileSizes reads sizes from the commit tree when analyzing a commit, and from
+// file metadata otherwise. Missing files count as empty.
+func estimateFileSizes(opts *graphOptions, filePaths []string, toCommit string) ([]estimateFile, error) {
+	var treeSizes map[string]int64
+	if toCommit != "" {
+		sizes, err := git.GetCommitFileSizes(opts.repoPath, toCommit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file sizes: %w", err)
+		}
+		treeSizes = sizes
+	}
+
+	files := make([]estimateFile, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		file := estimateFile{path: filePath}
+		if treeSizes != nil {
+			file.size = treeSizes[filePath]
+		} else if info, err := os.Stat(filePath); err == nil {
+			file.size = info.Size()
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func writeEstimate(cmd *cobra.Command, estimate analysisEstimate, suggestions []narrowing) error {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Estimated cost for %d files (%s)\n\n", estimate.files, formatByteSize(estimate.bytes))
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "LANGUAGE\tFILES\tSIZE\tTIME")
+	for _, cost := range estimate.languages {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\n", cost.language, cost.files, formatByteSize(cost.bytes), formatEstimateDuration(cost.duration))
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nGit subprocesses: %d\n", estimate.gitProcesses)
+	fmt.Fprintf(out, "Projected runtime: %s\n", formatEstimateDuration(estimate.duration))
+
+	if len(suggestions) > 0 {
+		fmt.Fprintln(out, "\nTo reduce the cost:")
+		for _, suggestion := range suggestions {
+			fmt.Fprintf(out, "  %s  # %s\n", suggestion.command, formatEstimateDuration(suggestion.estimate.duration))
+		}
+	}
+	return nil
+}
+
+func formatByteSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+func formatEstimateDuration(d time.Duration) string {
+	if d < time.Second {
+		return "<1s"
+	}
+	return "~" + d.Round(time.Second).String()
+}
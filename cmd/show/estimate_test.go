@@ -0,0 +1,139 @@
+package show
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var estimateRepo = filepath.FromSlash("/repo")
+
+func estimateRepoFile(rel string, size int64) estimateFile {
+	return estimateFile{path: filepath.Join(estimateRepo, filepath.FromSlash(rel)), size: size}
+}
+
+func TestEstimateAnalysis_ParseTimeAndGitProcesses(t *testing.T) {
+	estimate := estimateAnalysis(estimateInput{
+		files: []estimateFile{
+			estimateRepoFile("main.go", 4<<20),
+			estimateRepoFile("README.md", 1000),
+		},
+		stats: true,
+	})
+
+	require.Len(t, estimate.languages, 2)
+	assert.Equal(t, languageCost{language: "Go", files: 1, bytes: 4 << 20, duration: time.Second + perFileCost}, estimate.languages[0])
+	assert.Equal(t, languageCost{language: otherLanguage, files: 1, bytes: 1000, duration: perFileCost}, estimate.languages[1])
+	assert.Equal(t, 2, estimate.files)
+	assert.Equal(t, baseGitProcesses+statsGitProcesses, estimate.gitProcesses)
+	assert.Equal(t, time.Second+2*perFileCost+time.Duration(estimate.gitProcesses)*gitProcessCost, estimate.duration)
+}
+
+func TestEstimateAnalysis_CommitContentNeedsOneGitProcessPerParsedFile(t *testing.T) {
+	files := []estimateFile{
+		estimateRepoFile("a.go", 100),
+		estimateRepoFile("b.go", 100),
+		estimateRepoFile("notes.txt", 100),
+	}
+
+	workingTree := estimateAnalysis(estimateInput{files: files})
+	commit := estimateAnalysis(estimateInput{files: files, gitContent: true})
+
+	assert.Equal(t, baseGitProcesses, workingTree.gitProcesses)
+	assert.Equal(t, baseGitProcesses+3, commit.gitProcesses)
+}
+
+func TestEstimateAnalysis_AttributionRebuildsEveryCommit(t *testing.T) {
+	files := []estimateFile{estimateRepoFile("a.kt", 1<<20)}
+
+	plain := estimateAnalysis(estimateInput{files: files})
+	attributed := estimateAnalysis(estimateInput{files: files, attributeCommits: 4})
+
+	// One pass for the graph, plus the baseline and four commits.
+	assert.Equal(t, 6*plain.languages[0].duration, attributed.languages[0].duration)
+	assert.Equal(t, baseGitProcesses+1+5*2, attributed.gitProcesses)
+}
+
+func TestEstimateAnalysis_OrdersLanguagesByCost(t *testing.T) {
+	estimate := estimateAnalysis(estimateInput{files: []estimateFile{
+		estimateRepoFile("a.go", 1<<20),
+		estimateRepoFile("b.kt", 1<<20),
+		estimateRepoFile("c.ts", 1<<20),
+	}})
+
+	var languages []string
+	for _, cost := range estimate.languages {
+		languages = append(languages, cost.language)
+	}
+	assert.Equal(t, []string{"Kotlin", "TypeScript", "Go"}, languages)
+}
+
+func TestSuggestNarrowings_ProposesCheaperCommands(t *testing.T) {
+	in := estimateInput{
+		files: []estimateFile{
+			estimateRepoFile("app/src/Main.kt", 40<<20),
+			estimateRepoFile("app/src/Util.kt", 20<<20),
+			estimateRepoFile("tools/gen/main.go", 8<<20),
+		},
+		gitContent: true,
+		stats:      true,
+	}
+	args := commandArgs{{name: "commit", value: "HEAD"}}
+
+	suggestions := suggestNarrowings(args, in, estimateRepo, estimateAnalysis(in))
+
+	var commands []string
+	for _, suggestion := range suggestions {
+		commands = append(commands, suggestion.command)
+	}
+	assert.Equal(t, []string{
+		"clarity show --commit HEAD --exclude-ext .kt,.kts",
+		"clarity show --commit HEAD --exclude app",
+		"clarity show --commit HEAD --no-stats",
+	}, commands)
+	assert.Less(t, suggestions[0].estimate.duration, suggestions[2].estimate.duration)
+}
+
+func TestSuggestNarrowings_QuickRunsGetNoSuggestions(t *testing.T) {
+	in := estimateInput{files: []estimateFile{
+		estimateRepoFile("a/a.go", 1000),
+		estimateRepoFile("b/b.kt", 1000),
+	}, stats: true}
+
+	assert.Empty(t, suggestNarrowings(nil, in, estimateRepo, estimateAnalysis(in)))
+}
+
+func TestCommandArgs_String(t *testing.T) {
+	args := commandArgs{
+		{name: "input", value: "src/app"},
+		{name: "exclude-ext", value: ".md"},
+		{name: "commit", value: "HEAD~3...HEAD"},
+		{name: "label", value: ""},
+		{name: "exclude", value: "my dir"},
+	}
+
+	assert.Equal(t, "clarity show --input src/app --commit HEAD~3...HEAD --label --exclude 'my dir' --exclude-ext .md,.kt",
+		args.appendToList("exclude-ext", ".kt").String())
+}
+
+func TestGraphInput_Estimate_PrintsProjectionWithoutGraph(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+
+	stdout, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "--estimate")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if strings.Contains(stdout, "digraph") {
+		t.Fatalf("expected no graph output, got:\n%s", stdout)
+	}
+	for _, want := range []string{"Estimated cost for 7 files", "TypeScript", "Git subprocesses: 13", "Projected runtime: <1s"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in estimate, got:\n%s", want, stdout)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package show
+
+import "os"
+
+// identityOf returns the resolved path of a directory, since device and inode numbers
+// are not available on this platform.
+func identityOf(path string, _ os.FileInfo) fileIdentity {
+	return resolvedIdentity(path)
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package show
+
+import (
+	"os"
+	"syscall"
+)
+
+// identityOf returns the device and inode of a directory.
+func identityOf(path string, info os.FileInfo) fileIdentity {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fileIdentity{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}
+	}
+	return resolvedIdentity(path)
+}
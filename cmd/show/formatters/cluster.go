@@ -0,0 +1,90 @@
+package formatters
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+// ClusterMode selects how file nodes are grouped into clusters.
+type ClusterMode string
+
+const (
+	// ClusterNone renders every node at the top level of the graph.
+	ClusterNone ClusterMode = ""
+	// ClusterDir groups nodes by their repo-relative directory.
+	ClusterDir ClusterMode = "dir"
+)
+
+// DefaultClusterDepth is how many directory levels ClusterDir keeps apart; deeper
+// directories join their ancestor's cluster.
+const DefaultClusterDepth = 2
+
+// ParseClusterMode converts a string to ClusterMode.
+func ParseClusterMode(s string) (ClusterMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return ClusterNone, true
+	case "dir":
+		return ClusterDir, true
+	default:
+		return ClusterNone, false
+	}
+}
+
+// SupportedClusterModes returns a list of all supported cluster modes.
+func SupportedClusterModes() string {
+	return "dir"
+}
+
+// nodeCluster is a group of file nodes rendered together under a label.
+type nodeCluster struct {
+	label string
+	files []string
+}
+
+// clusterFiles groups filePaths into clusters, ordered by label, and returns them
+// with the files that belong to no cluster. Files at the repository root and files
+// whose directory is unknown stay at the top level.
+func clusterFiles(g depgraph.FileDependencyGraph, filePaths []string, opts RenderOptions) ([]nodeCluster, []string) {
+	if opts.Cluster != ClusterDir {
+		return nil, filePaths
+	}
+	depth := opts.ClusterDepth
+	if depth <= 0 {
+		depth = DefaultClusterDepth
+	}
+
+	byLabel := make(map[string][]string)
+	var unclustered []string
+	for _, file := range filePaths {
+		label := clusterLabel(g.Meta.Files[file].Dir, depth)
+		if label == "" {
+			unclustered = append(unclustered, file)
+			continue
+		}
+		byLabel[label] = append(byLabel[label], file)
+	}
+
+	clusters := make([]nodeCluster, 0, len(byLabel))
+	for label, files := range byLabel {
+		clusters = append(clusters, nodeCluster{label: label, files: files})
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].label < clusters[j].label })
+	return clusters, unclustered
+}
+
+// clusterLabel returns dir cut to its first depth segments. Absolute directories,
+// of files outside the repository, keep their leading slash.
+func clusterLabel(dir string, depth int) string {
+	segments := strings.Split(dir, "/")
+	limit := depth
+	if strings.HasPrefix(dir, "/") {
+		limit++
+	}
+	if len(segments) > limit {
+		segments = segments[:limit]
+	}
+	return strings.Join(segments, "/")
+}
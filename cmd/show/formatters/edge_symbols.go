@@ -0,0 +1,20 @@
+package formatters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxEdgeSymbols is how many symbol names an edge annotation lists before summarizing
+// the rest.
+const maxEdgeSymbols = 10
+
+// EdgeSymbolsText lists the symbols used across an edge, keeping the first
+// maxEdgeSymbols names and counting the rest as "+N more".
+func EdgeSymbolsText(symbols []string) string {
+	if len(symbols) <= maxEdgeSymbols {
+		return strings.Join(symbols, ", ")
+	}
+	shown := append(append([]string(nil), symbols[:maxEdgeSymbols]...), fmt.Sprintf("+%d more", len(symbols)-maxEdgeSymbols))
+	return strings.Join(shown, ", ")
+}
@@ -0,0 +1,31 @@
+package formatters
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeSymbolsText(t *testing.T) {
+	var twelve []string
+	for i := 1; i <= 12; i++ {
+		twelve = append(twelve, fmt.Sprintf("S%02d", i))
+	}
+
+	tests := []struct {
+		name    string
+		symbols []string
+		want    string
+	}{
+		{"single", []string{"User"}, "User"},
+		{"several", []string{"Order", "User"}, "Order, User"},
+		{"exactly the cap", twelve[:10], "S01, S02, S03, S04, S05, S06, S07, S08, S09, S10"},
+		{"over the cap", twelve, "S01, S02, S03, S04, S05, S06, S07, S08, S09, S10, +2 more"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EdgeSymbolsText(tt.symbols))
+		})
+	}
+}
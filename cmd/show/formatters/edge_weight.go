@@ -0,0 +1,42 @@
+package formatters
+
+import (
+	"strings"
+)
+
+// EdgeWeight selects the metric edges are weighted by.
+type EdgeWeight string
+
+const (
+	// EdgeWeightNone draws every edge alike.
+	EdgeWeightNone EdgeWeight = ""
+	// EdgeWeightUsage weighs edges by how many times the source file uses symbols of
+	// the target file, as counted for Go.
+	EdgeWeightUsage EdgeWeight = "usage"
+)
+
+// ParseEdgeWeight converts a string to EdgeWeight.
+func ParseEdgeWeight(s string) (EdgeWeight, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return EdgeWeightNone, true
+	case "usage":
+		return EdgeWeightUsage, true
+	default:
+		return EdgeWeightNone, false
+	}
+}
+
+// SupportedEdgeWeights returns a list of all supported edge weight metrics.
+func SupportedEdgeWeights() string {
+	return "usage"
+}
+
+// edgeUsages returns the usage count an edge is weighted by, or 0 when edges are not
+// weighted or its count is unknown.
+func edgeUsages(opts RenderOptions, usages int) int {
+	if opts.EdgeWeight != EdgeWeightUsage {
+		return 0
+	}
+	return usages
+}
@@ -5,12 +5,6 @@ import (
 	"sort"
 )
 
-var extensionColorPalette = []string{
-	"lightblue", "lightyellow", "mistyrose", "lightsalmon",
-	"lightpink", "lavender", "peachpuff", "plum", "powderblue", "khaki",
-	"palegoldenrod", "thistle",
-}
-
 func getExtensionColors(fileNames []string) map[string]string {
 	uniqueExtensions := make(map[string]bool)
 	for _, fileName := range fileNames {
@@ -26,9 +20,10 @@ func getExtensionColors(fileNames []string) map[string]string {
 	}
 	sort.Strings(sortedExtensions)
 
+	palette := LightTheme().DOT.ExtPalette
 	extensionColors := make(map[string]string)
 	for i, ext := range sortedExtensions {
-		color := extensionColorPalette[i%len(extensionColorPalette)]
+		color := palette[i%len(palette)]
 		extensionColors[ext] = color
 	}
 
@@ -2,10 +2,16 @@ package formatters
 
 import (
 	"fmt"
+	"html/template"
 
 	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/metadata"
 )
 
+// EmptyGraphLabel labels the placeholder node rendered for a graph without files, so
+// an image rendered from the output explains itself instead of being blank.
+const EmptyGraphLabel = "no files analyzed"
+
 type dotFormatter struct {
 	extensionColors   map[string]string
 	nextColorPaletteI int
@@ -13,6 +19,18 @@ type dotFormatter struct {
 
 type mermaidFormatter struct{}
 
+type jsonFormatter struct{}
+
+type imageFormatter struct {
+	// format is OutputFormatSVG or OutputFormatPNG.
+	format OutputFormat
+}
+
+type htmlFormatter struct {
+	// mermaidScript is the markup that loads Mermaid in the report.
+	mermaidScript template.HTML
+}
+
 // Formatter is the interface that all graph formatters must implement.
 type Formatter interface {
 	// Format converts a dependency graph to a formatted string representation.
@@ -34,6 +52,12 @@ func NewFormatter(format string) (Formatter, error) {
 		return &dotFormatter{}, nil
 	case OutputFormatMermaid:
 		return mermaidFormatter{}, nil
+	case OutputFormatJSON:
+		return jsonFormatter{}, nil
+	case OutputFormatHTML:
+		return htmlFormatter{mermaidScript: mermaidScript()}, nil
+	case OutputFormatSVG, OutputFormatPNG:
+		return imageFormatter{format: f}, nil
 	case endOfSupportedFormatsMarker:
 		return nil, fmt.Errorf("unknown format: %s (valid options: %s)", format, SupportedFormats())
 	default:
@@ -51,4 +75,27 @@ type RenderOptions struct {
 	BasePath string
 	// EdgeLabels enables deterministic short labels on edges.
 	EdgeLabels bool
+	// EdgeSymbols annotates edges with the symbols the source file uses from the target.
+	EdgeSymbols bool
+	// NodeSize scales file nodes by a metric of their file, such as lines of code.
+	NodeSize NodeSize
+	// EdgeWeight weighs edges by a metric of the dependency, drawn as DOT pen width
+	// and as Mermaid and JSON counts.
+	EdgeWeight EdgeWeight
+	// Cluster groups file nodes, such as by directory, in DOT clusters and Mermaid
+	// subgraphs.
+	Cluster ClusterMode
+	// ClusterDepth is how many directory levels ClusterDir keeps apart; 0 means
+	// DefaultClusterDepth.
+	ClusterDepth int
+	// Theme holds the colors of the dot and mermaid output; the zero Theme is
+	// LightTheme.
+	Theme Theme
+	// NodeLinks maps file paths to the URLs their nodes link to, such as the file on
+	// its source hosting at the analyzed commit. Files without an entry get no link.
+	NodeLinks map[string]string
+	// Metadata records how the graph was produced. When set, it is appended to dot
+	// and mermaid output as comments and to json output as the meta object, with the
+	// file count and content hash of the rendered graph.
+	Metadata *metadata.Metadata
 }
@@ -18,6 +18,7 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 	}
 
 	explicitDirection := opts.Direction != ""
+	colors := opts.theme().DOT
 	var sb strings.Builder
 	sb.WriteString("digraph dependencies {\n")
 	dir := opts.Direction
@@ -25,11 +26,22 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 		dir = DefaultDirection
 	}
 	sb.WriteString(fmt.Sprintf("  rankdir=%s;\n", dir.String()))
-	sb.WriteString("  node [shape=box];\n")
+	if colors.Background != "" {
+		sb.WriteString(fmt.Sprintf("  bgcolor=%s;\n", dotColor(colors.Background)))
+	}
+	if colors.Text != "" {
+		sb.WriteString(fmt.Sprintf("  fontcolor=%s;\n", dotColor(colors.Text)))
+		sb.WriteString(fmt.Sprintf("  node [shape=box, fontcolor=%s];\n", dotColor(colors.Text)))
+	} else {
+		sb.WriteString("  node [shape=box];\n")
+	}
+	if colors.Edge != "" {
+		sb.WriteString(fmt.Sprintf("  edge [color=%s];\n", dotColor(colors.Edge)))
+	}
 
 	// Add label if provided
 	if opts.Label != "" {
-		sb.WriteString(fmt.Sprintf("  label=\"%s\";\n", opts.Label))
+		sb.WriteString(fmt.Sprintf("  label=%q;\n", opts.Label))
 		sb.WriteString("  labelloc=t;\n")
 		sb.WriteString("  labeljust=l;\n")
 		sb.WriteString("  fontsize=10;\n")
@@ -37,6 +49,20 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 	}
 	sb.WriteString("\n")
 
+	if len(adjacency) == 0 {
+		sb.WriteString(fmt.Sprintf("  empty [label=%q, style=dashed, color=%s, fontcolor=%s];\n", EmptyGraphLabel, dotColor(colors.Placeholder), dotColor(colors.Placeholder)))
+		sb.WriteString("}")
+		if explicitDirection {
+			sb.WriteString("\n")
+		}
+		return appendMetadataComment(sb.String(), "//", adjacency, opts), nil
+	}
+
+	// Files and their dependencies are written in sortedFiles order, so the output is
+	// the same on every run.
+	filePaths := sortedFiles(adjacency, opts.BasePath)
+	nodeNames := BuildNodeNames(filePaths)
+
 	cycleNodes := make(map[string]bool)
 	if len(g.Meta.Cycles) > 0 {
 		sb.WriteString("  // Cyclic paths:\n")
@@ -46,10 +72,10 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 			}
 			var cycleParts []string
 			for _, node := range cycle.Path {
-				cycleParts = append(cycleParts, filepath.Base(node))
+				cycleParts = append(cycleParts, nodeNames[node])
 				cycleNodes[node] = true
 			}
-			cycleParts = append(cycleParts, filepath.Base(cycle.Path[0]))
+			cycleParts = append(cycleParts, nodeNames[cycle.Path[0]])
 			sb.WriteString(fmt.Sprintf("  // C%d: %s\n", i+1, strings.Join(cycleParts, " -> ")))
 		}
 		sb.WriteString("\n")
@@ -62,20 +88,13 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 		cycleNodes[edge.To] = true
 	}
 
-	// Collect all file paths from the graph to determine extension colors
-	// Sort for deterministic output
-	filePaths := make([]string, 0, len(adjacency))
-	for source := range adjacency {
-		filePaths = append(filePaths, source)
-	}
-	sort.Strings(filePaths)
-	nodeNames := BuildNodeNames(filePaths)
-
-	extensionColors := f.assignExtensionColors(filePaths)
+	// External packages are not files, so they take no part in extension coloring.
+	files := withoutExternalNodes(filePaths)
+	extensionColors := f.assignExtensionColors(files, colors.ExtPalette)
 
 	// Count files by extension to find the majority extension
 	extensionCounts := make(map[string]int)
-	for source := range adjacency {
+	for _, source := range files {
 		ext := filepath.Ext(filepath.Base(source))
 		extensionCounts[ext]++
 	}
@@ -100,7 +119,7 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 
 	// Track all files that have the majority extension
 	filesWithMajorityExtension := make(map[string]bool)
-	for source := range adjacency {
+	for _, source := range files {
 		ext := filepath.Ext(filepath.Base(source))
 		if ext == majorityExtension {
 			filesWithMajorityExtension[source] = true
@@ -109,7 +128,7 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 
 	// Count unique file extensions to determine if we need extension-based coloring
 	uniqueExtensions := make(map[string]bool)
-	for source := range adjacency {
+	for _, source := range files {
 		ext := filepath.Ext(filepath.Base(source))
 		uniqueExtensions[ext] = true
 	}
@@ -120,8 +139,8 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 		if color, ok := extensionColors[ext]; ok {
 			return color
 		}
-		// If extension not found (e.g., empty extension), return white as default
-		return "white"
+		// If extension not found (e.g., empty extension), use the default fill
+		return colors.MajorityExt
 	}
 
 	// Track which nodes have been styled to avoid duplicates
@@ -132,67 +151,100 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 		sourceBase := filepath.Base(source)
 		sourceNodeKey := dotNodeKey(source, opts.BasePath)
 
+		if !styledNodes[sourceNodeKey] && depgraph.IsExternalNode(source) {
+			sb.WriteString(fmt.Sprintf("  %q [label=%q, shape=component, style=filled, fillcolor=%s, color=%s];\n",
+				sourceNodeKey, nodeNames[source], dotColor(colors.External), dotColor(colors.ExternalBorder)))
+			styledNodes[sourceNodeKey] = true
+			continue
+		}
 		if !styledNodes[sourceNodeKey] {
 			var color string
 
 			fileMetadata, hasFileMetadata := g.Meta.Files[source]
 
-			// Priority 1: Test files are always light green
+			manifest := fileMetadata.Kind == depgraph.NodeKindManifest
+
+			// Priority 1: Test files take the test file fill
 			if hasFileMetadata && fileMetadata.IsTest {
-				color = "lightgreen"
+				color = dotColor(colors.TestFile)
+			} else if manifest {
+				// Priority 2: Dependency manifests take the manifest fill
+				color = dotColor(colors.Manifest)
+			} else if colors.NewFile != "" && isNewFile(fileMetadata) {
+				// Priority 3: New files, when the theme colors them
+				color = dotColor(colors.NewFile)
 			} else if filesWithMajorityExtension[source] {
-				// Priority 2: Files with majority extension count are always white
-				color = "white"
+				// Priority 4: Files with majority extension count take the default fill
+				color = dotColor(colors.MajorityExt)
 			} else if hasMultipleExtensions {
-				// Priority 3: Color based on extension (only if multiple extensions exist)
+				// Priority 5: Color based on extension (only if multiple extensions exist)
 				ext := filepath.Ext(sourceBase)
-				color = getColorForExtension(ext)
+				color = dotColor(getColorForExtension(ext))
 			} else {
-				// Priority 4: Single extension - use white (no need to differentiate)
-				color = "white"
+				// Priority 6: Single extension - default fill (no need to differentiate)
+				color = dotColor(colors.MajorityExt)
 			}
 
-			// Build node label with file stats if available
-			nodeLabel := nodeNames[source]
-			if hasFileMetadata && fileMetadata.Stats != nil {
-				stats := *fileMetadata.Stats
-				labelPrefix := nodeLabel
-				if stats.IsNew {
-					labelPrefix = fmt.Sprintf("🪴 %s", labelPrefix)
-				}
-
-				if stats.Additions > 0 || stats.Deletions > 0 {
-					var statsParts []string
-					if stats.Additions > 0 {
-						statsParts = append(statsParts, fmt.Sprintf("+%d", stats.Additions))
-					}
-					if stats.Deletions > 0 {
-						statsParts = append(statsParts, fmt.Sprintf("-%d", stats.Deletions))
-					}
-					if len(statsParts) > 0 {
-						nodeLabel = fmt.Sprintf("%s\n%s", labelPrefix, strings.Join(statsParts, " "))
-					} else {
-						nodeLabel = labelPrefix
-					}
-				} else if stats.IsNew {
-					nodeLabel = labelPrefix
-				}
-			}
+			// %q below escapes the label, including the line breaks between its lines.
+			nodeLabel := strings.Join(BuildNodeLabel(nodeNames[source], fileMetadata).Lines(), "\n")
 
+			nodeStyle := "filled"
+			border := colors.Border
+			if hasFileMetadata && fileMetadata.IsTest && colors.TestFileBorder != "" {
+				border = colors.TestFileBorder
+			} else if manifest {
+				border = colors.ManifestBorder
+			}
 			if hasFileMetadata && fileMetadata.IsPruned {
-				if cycleNodes[source] {
-					sb.WriteString(fmt.Sprintf("  %q [label=%q, style=\"filled,dashed\", fillcolor=%s, color=red];\n", sourceNodeKey, nodeLabel, color))
-				} else {
-					sb.WriteString(fmt.Sprintf("  %q [label=%q, style=\"filled,dashed\", fillcolor=%s, color=gray];\n", sourceNodeKey, nodeLabel, color))
-				}
-			} else if cycleNodes[source] {
-				sb.WriteString(fmt.Sprintf("  %q [label=%q, style=filled, fillcolor=%s, color=red];\n", sourceNodeKey, nodeLabel, color))
-			} else {
-				sb.WriteString(fmt.Sprintf("  %q [label=%q, style=filled, fillcolor=%s];\n", sourceNodeKey, nodeLabel, color))
+				nodeStyle = "\"filled,dashed\""
+				border = colors.Pruned
 			}
+			if cycleNodes[source] {
+				border = colors.Cycle
+			}
+			border = dotColor(border)
+
+			// User-defined styles replace the built-in colors, but cycles keep theirs.
+			customStyle, hasCustomStyle := fileMetadata.AppliedStyle()
+			if hasCustomStyle && customStyle.Fill != "" {
+				color = fmt.Sprintf("%q", customStyle.Fill)
+			}
+			if hasCustomStyle && customStyle.Stroke != "" && !cycleNodes[source] {
+				border = fmt.Sprintf("%q", customStyle.Stroke)
+			}
+
+			attrs := fmt.Sprintf("label=%q, style=%s, fillcolor=%s", nodeLabel, nodeStyle, color)
+			if manifest {
+				attrs = fmt.Sprintf("label=%q, shape=diamond, style=%s, fillcolor=%s", nodeLabel, nodeStyle, color)
+			}
+			if border != "" {
+				attrs += ", color=" + border
+			}
+			if hasCustomStyle && customStyle.Class != "" {
+				attrs += fmt.Sprintf(", class=%q", customStyle.Class)
+			}
+			if sizeAttrs := dotNodeSizeAttrs(opts, fileMetadata); sizeAttrs != "" {
+				attrs += ", " + sizeAttrs
+			}
+			if link, ok := opts.NodeLinks[source]; ok {
+				attrs += fmt.Sprintf(", URL=%q", link)
+			}
+			sb.WriteString(fmt.Sprintf("  %q [%s];\n", sourceNodeKey, attrs))
 			styledNodes[sourceNodeKey] = true
 		}
 	}
+
+	// Clusters list nodes declared above; Graphviz moves them into the cluster and
+	// keeps edges between clusters as they are.
+	clusters, _ := clusterFiles(g, filePaths, opts)
+	for i, cluster := range clusters {
+		sb.WriteString(fmt.Sprintf("\n  subgraph \"cluster_%d\" {\n", i))
+		sb.WriteString(fmt.Sprintf("    label=%q;\n", cluster.label))
+		for _, file := range cluster.files {
+			sb.WriteString(fmt.Sprintf("    %q;\n", dotNodeKey(file, opts.BasePath)))
+		}
+		sb.WriteString("  }\n")
+	}
 	// Determine whether we have any edges before writing the section separator.
 	hasEdges := false
 	for _, deps := range adjacency {
@@ -207,10 +259,7 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 
 	// Write edges (nodes are already declared above with styling)
 	for _, source := range filePaths {
-		deps := adjacency[source]
-		sortedDeps := make([]string, len(deps))
-		copy(sortedDeps, deps)
-		sort.Strings(sortedDeps)
+		sortedDeps := sortedDependencies(adjacency[source], opts.BasePath)
 
 		sourceNodeKey := dotNodeKey(source, opts.BasePath)
 		for _, dep := range sortedDeps {
@@ -221,8 +270,35 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 			if opts.EdgeLabels {
 				attrs = append(attrs, fmt.Sprintf("label=%q", EdgeLabel(nodeNames[source], nodeNames[dep])))
 			}
-			if edgeMD.InCycle {
-				attrs = append(attrs, "color=red", "style=dashed")
+			heuristic := edgeMD.Provenance == depgraph.EdgeProvenanceHeuristic
+			manifestLink := edgeMD.Provenance == depgraph.EdgeProvenanceManifest
+			switch {
+			case edgeMD.Suppressed:
+				attrs = append(attrs, "color="+dotColor(colors.Suppressed), "fontcolor="+dotColor(colors.Suppressed))
+			case edgeMD.InCycle:
+				attrs = append(attrs, "color="+dotColor(colors.Cycle))
+			case heuristic:
+				attrs = append(attrs, "color="+dotColor(colors.Heuristic))
+			case edgeMD.Provenance == depgraph.EdgeProvenanceExpectActual:
+				attrs = append(attrs, "color="+dotColor(colors.ExpectActual), "dir=both")
+			case manifestLink:
+				attrs = append(attrs, "color="+dotColor(colors.ManifestBorder))
+			}
+			if edgeMD.InCycle || heuristic || manifestLink {
+				attrs = append(attrs, "style=dashed")
+			}
+			if usages := edgeUsages(opts, edgeMD.Usages); usages > 0 {
+				attrs = append(attrs, fmt.Sprintf("penwidth=%.2f", depgraph.EdgeUsageWeight(usages)))
+			}
+			var tooltip []string
+			if opts.EdgeSymbols && len(edgeMD.Symbols) > 0 {
+				tooltip = append(tooltip, EdgeSymbolsText(edgeMD.Symbols))
+			}
+			if edgeMD.IntroducedIn != "" {
+				tooltip = append(tooltip, "introduced in "+edgeMD.IntroducedIn)
+			}
+			if len(tooltip) > 0 {
+				attrs = append(attrs, fmt.Sprintf("tooltip=%q", strings.Join(tooltip, "\n")))
 			}
 			if len(attrs) > 0 {
 				sb.WriteString(fmt.Sprintf("  %q -> %q [%s];\n", sourceNodeKey, depNodeKey, strings.Join(attrs, ", ")))
@@ -236,7 +312,7 @@ func (f *dotFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions
 	if explicitDirection {
 		sb.WriteString("\n")
 	}
-	return sb.String(), nil
+	return appendMetadataComment(sb.String(), "//", adjacency, opts), nil
 }
 
 // GenerateURL creates a GraphvizOnline URL with the DOT graph embedded.
@@ -245,7 +321,7 @@ func (f *dotFormatter) GenerateURL(output string) (string, bool) {
 	return fmt.Sprintf("https://dreampuf.github.io/GraphvizOnline/?engine=dot#%s", encoded), true
 }
 
-func (f *dotFormatter) assignExtensionColors(filePaths []string) map[string]string {
+func (f *dotFormatter) assignExtensionColors(filePaths []string, palette []string) map[string]string {
 	if f.extensionColors == nil {
 		f.extensionColors = make(map[string]string)
 	}
@@ -268,7 +344,7 @@ func (f *dotFormatter) assignExtensionColors(filePaths []string) map[string]stri
 		if _, exists := f.extensionColors[ext]; exists {
 			continue
 		}
-		color := extensionColorPalette[f.nextColorPaletteI%len(extensionColorPalette)]
+		color := palette[f.nextColorPaletteI%len(palette)]
 		f.extensionColors[ext] = color
 		f.nextColorPaletteI++
 	}
@@ -130,6 +130,39 @@ func TestDependencyGraph_ToDOT_NewFilesUseSeedlingLabel(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestDependencyGraph_ToDOT_EmptyGraph(t *testing.T) {
+	graph := testFileGraph(t, make(map[string][]string), nil)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "abc1234"})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_StatsLabelCases(t *testing.T) {
+	graph := testFileGraph(t, labelCaseAdjacency, labelCaseStats)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_RenamedFiles(t *testing.T) {
+	graph := testFileGraph(t, renameAdjacency, renameStats)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
 func TestDependencyGraph_ToDOT_TestFilesAreLightGreen(t *testing.T) {
 	graph := testFileGraph(t, map[string][]string{
 		"/project/main.go":       {"/project/utils.go"},
@@ -318,6 +351,142 @@ func TestDependencyGraph_ToDOT_PrunedNodesHaveDashedBorder(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestDependencyGraph_ToDOT_CustomStylesLastRuleWins(t *testing.T) {
+	// The pricing rule overrides the domain rule, and the test file keeps its
+	// built-in class because neither rule sets override.
+	graph := withStyles(t, testFileGraph(t, styledTestAdjacency, nil), overlappingStyles)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_CustomStyleOverridesBuiltInClass(t *testing.T) {
+	graph := withStyles(t, testFileGraph(t, styledTestAdjacency, nil), overlappingStyles+`  - match: "*_test.go"
+    fill: "#ffec8b"
+    class: domainTest
+    override: true
+`)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_HeuristicEdgesAreDashed(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/App.vue":  {"/project/store.ts"},
+		"/project/main.ts":  {"/project/store.ts"},
+		"/project/store.ts": {},
+	}, nil)
+
+	edge := depgraph.FileEdge{From: "/project/App.vue", To: "/project/store.ts"}
+	md := graph.Meta.Edges[edge]
+	md.Provenance = depgraph.EdgeProvenanceHeuristic
+	graph.Meta.Edges[edge] = md
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_ExpectActualEdgesAreBidirectional(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/commonMain/Clock.kt":  {},
+		"/project/androidMain/Clock.kt": {"/project/commonMain/Clock.kt"},
+		"/project/iosMain/Clock.kt":     {"/project/commonMain/Clock.kt"},
+	}, nil)
+
+	for _, actual := range []string{"/project/androidMain/Clock.kt", "/project/iosMain/Clock.kt"} {
+		edge := depgraph.FileEdge{From: actual, To: "/project/commonMain/Clock.kt"}
+		md := graph.Meta.Edges[edge]
+		md.Provenance = depgraph.EdgeProvenanceExpectActual
+		graph.Meta.Edges[edge] = md
+	}
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_SuppressedEdgesAreDimmed(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/a.go": {"/project/b.go", "/project/c.go"},
+		"/project/b.go": {},
+		"/project/c.go": {},
+	}, nil)
+
+	edge := depgraph.FileEdge{From: "/project/a.go", To: "/project/b.go"}
+	md := graph.Meta.Edges[edge]
+	md.Suppressed = true
+	graph.Meta.Edges[edge] = md
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_AttributedEdgesHaveTooltips(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/a.go": {"/project/b.go", "/project/c.go"},
+		"/project/b.go": {},
+		"/project/c.go": {},
+	}, nil)
+
+	edge := depgraph.FileEdge{From: "/project/a.go", To: "/project/c.go"}
+	md := graph.Meta.Edges[edge]
+	md.IntroducedIn = "abc1234"
+	graph.Meta.Edges[edge] = md
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_EdgeSymbolsInTooltips(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/a.go": {"/project/b.go", "/project/c.go"},
+		"/project/b.go": {},
+		"/project/c.go": {},
+	}, nil)
+
+	symbolsEdge := depgraph.FileEdge{From: "/project/a.go", To: "/project/b.go"}
+	md := graph.Meta.Edges[symbolsEdge]
+	md.Symbols = []string{"Client", "NewClient"}
+	graph.Meta.Edges[symbolsEdge] = md
+
+	attributedEdge := depgraph.FileEdge{From: "/project/a.go", To: "/project/c.go"}
+	md = graph.Meta.Edges[attributedEdge]
+	md.Symbols = []string{"Config"}
+	md.IntroducedIn = "abc1234"
+	graph.Meta.Edges[attributedEdge] = md
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{EdgeSymbols: true})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
 func TestDependencyGraph_ToDOT_EdgeLabels(t *testing.T) {
 	graph := testFileGraph(t, map[string][]string{
 		"/project/a.go": {"/project/b.go", "/project/c.go"},
@@ -348,6 +517,92 @@ func TestDependencyGraph_ToDOT_DuplicateBaseNamesStayDistinct(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestDependencyGraph_ToDOT_SameBaseNameInDifferentPackages(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go":          {"/project/api/user.go"},
+		"/project/api/user.go":      {"/project/models/user.go"},
+		"/project/models/user.go":   {"/project/models/store.go"},
+		"/project/models/store.go":  {"/project/models/user.go"},
+		"/project/models/record.go": {},
+	}, map[string]vcs.FileStats{
+		"/project/api/user.go":    {Additions: 3},
+		"/project/models/user.go": {Additions: 1, Deletions: 2},
+	})
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_NodeSizeByLinesOfCode(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go":   {"/project/small.go", "/project/large.go"},
+		"/project/small.go":  {},
+		"/project/large.go":  {"/project/huge.go"},
+		"/project/huge.go":   {},
+		"/project/unread.go": {},
+	}, nil)
+	setLineCounts(graph, map[string]int{
+		"/project/main.go":  40,
+		"/project/small.go": 0,
+		"/project/large.go": 500,
+		"/project/huge.go":  20000,
+	})
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project", NodeSize: NodeSizeLOC})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_LineCountsIgnoredWithoutNodeSize(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go": {},
+	}, nil)
+	setLineCounts(graph, map[string]int{"/project/main.go": 500})
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+
+	require.NotContains(t, output, "width=")
+	require.NotContains(t, output, "height=")
+}
+
+func TestDependencyGraph_ToDOT_ClustersByDirectory(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go":               {"/project/api/handler.go"},
+		"/project/api/handler.go":        {"/project/store/db.go"},
+		"/project/api/routes.go":         {"/project/api/handler.go"},
+		"/project/store/db.go":           {},
+		"/project/store/sql/gen/gen.go":  {},
+		"/project/store/sql/schema.go":   {"/project/store/sql/gen/gen.go"},
+		"/project/store/sql/gen/more.go": {},
+	}, nil)
+	graph.AssignDirectories("/project")
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project", Cluster: ClusterDir})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+// setLineCounts records line counts on the file metadata of graph.
+func setLineCounts(graph depgraph.FileDependencyGraph, lineCounts map[string]int) {
+	for file, lineCount := range lineCounts {
+		md := graph.Meta.Files[file]
+		md.LineCount = &lineCount
+		graph.Meta.Files[file] = md
+	}
+}
+
 func TestDependencyGraph_ToDOT_ExtensionColorsRemainStableAcrossSequentialRenders(t *testing.T) {
 	formatter := dotFormatter{}
 
@@ -407,3 +662,111 @@ func findFillColorForLabel(dotOutput, label string) (string, bool) {
 	}
 	return "", false
 }
+
+func TestDependencyGraph_ToDOT_ExternalPackagesAreComponents(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go":          {"/project/util.go", "go:github.com/spf13/cobra"},
+		"/project/util.go":          {"go:github.com/spf13/cobra"},
+		"/project/main_test.go":     {"/project/main.go"},
+		"go:github.com/spf13/cobra": {},
+	}, nil)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_ManifestsAreDiamonds(t *testing.T) {
+	graph := manifestGraph(t)
+
+	formatter := &dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_NodeLinksSetURLs(t *testing.T) {
+	graph := linkedGraph(t)
+
+	formatter := &dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{NodeLinks: testNodeLinks})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestDependencyGraph_ToDOT_EdgeWeightUsageSetsPenWidth(t *testing.T) {
+	graph := usageWeightedGraph(t)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{EdgeWeight: EdgeWeightUsage})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+
+	unweighted, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, unweighted, "penwidth")
+}
+
+// usageWeightedGraph returns a graph whose main.go uses helpers.go 12 times, types.go
+// once, and config.go an unknown number of times.
+// manifestGraph is a commit touching go.mod and two .go files, with the files linked
+// to their manifest as --link-manifests does.
+func manifestGraph(t *testing.T) depgraph.FileDependencyGraph {
+	t.Helper()
+
+	graph := testFileGraph(t, map[string][]string{
+		"/project/go.mod":  {},
+		"/project/main.go": {"/project/util.go", "/project/go.mod"},
+		"/project/util.go": {"/project/go.mod"},
+	}, nil)
+	for _, from := range []string{"/project/main.go", "/project/util.go"} {
+		edge := depgraph.FileEdge{From: from, To: "/project/go.mod"}
+		md := graph.Meta.Edges[edge]
+		md.Provenance = depgraph.EdgeProvenanceManifest
+		graph.Meta.Edges[edge] = md
+	}
+	return graph
+}
+
+// testNodeLinks links the files of linkedGraph except config.go.
+var testNodeLinks = map[string]string{
+	"/project/main.go":    "https://github.com/org/repo/blob/abc123/main.go",
+	"/project/helpers.go": "https://github.com/org/repo/blob/abc123/helpers.go",
+}
+
+func linkedGraph(t *testing.T) depgraph.FileDependencyGraph {
+	t.Helper()
+
+	return testFileGraph(t, map[string][]string{
+		"/project/main.go":    {"/project/helpers.go", "/project/config.go"},
+		"/project/helpers.go": {},
+		"/project/config.go":  {},
+	}, nil)
+}
+
+func usageWeightedGraph(t *testing.T) depgraph.FileDependencyGraph {
+	t.Helper()
+
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go":    {"/project/helpers.go", "/project/types.go", "/project/config.go"},
+		"/project/helpers.go": {},
+		"/project/types.go":   {},
+		"/project/config.go":  {},
+	}, nil)
+	for to, usages := range map[string]int{"/project/helpers.go": 12, "/project/types.go": 1} {
+		edge := depgraph.FileEdge{From: "/project/main.go", To: to}
+		md := graph.Meta.Edges[edge]
+		md.Usages = usages
+		graph.Meta.Edges[edge] = md
+	}
+	return graph
+}
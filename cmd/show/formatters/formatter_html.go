@@ -0,0 +1,116 @@
+package formatters
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+// defaultHTMLTitle titles reports of graphs rendered without a label.
+const defaultHTMLTitle = "Dependency graph"
+
+//go:embed assets/report.html.tmpl
+var htmlReportTemplateSource string
+
+//go:embed assets/mermaid
+var mermaidAssets embed.FS
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(htmlReportTemplateSource))
+
+// htmlReport is the data rendered into the report template.
+type htmlReport struct {
+	Title         string
+	NewFileMarker string
+	Diagram       string
+	Links         []htmlLink
+	MermaidScript template.HTML
+}
+
+// htmlLink is an anchor to a file of the graph, listed below the diagram.
+type htmlLink struct {
+	Name string
+	URL  string
+}
+
+// Format renders the dependency graph as a single HTML page that draws the Mermaid
+// flowchart in the browser, titled with the graph label and explained by a legend.
+func (f htmlFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions) (string, error) {
+	// The label is the page heading, so the diagram itself is left untitled. Mermaid
+	// ignores clicks at the strict security level, so files are linked below it instead.
+	diagramOpts := opts
+	diagramOpts.Label = ""
+	diagramOpts.NodeLinks = nil
+	diagram, err := mermaidFormatter{}.Format(g, diagramOpts)
+	if err != nil {
+		return "", err
+	}
+	links, err := htmlLinks(g, opts)
+	if err != nil {
+		return "", err
+	}
+
+	title := opts.Label
+	if title == "" {
+		title = defaultHTMLTitle
+	}
+
+	var sb strings.Builder
+	err = htmlReportTemplate.Execute(&sb, htmlReport{
+		Title:         title,
+		NewFileMarker: newFileMarker,
+		Diagram:       diagram,
+		Links:         links,
+		MermaidScript: f.mermaidScript,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// htmlLinks returns the anchors of the linked files of g, in node order.
+func htmlLinks(g depgraph.FileDependencyGraph, opts RenderOptions) ([]htmlLink, error) {
+	if len(opts.NodeLinks) == 0 {
+		return nil, nil
+	}
+	adjacency, err := depgraph.AdjacencyList(g.Graph)
+	if err != nil {
+		return nil, err
+	}
+
+	filePaths := sortedFiles(adjacency, opts.BasePath)
+	nodeNames := BuildNodeNames(filePaths)
+	var links []htmlLink
+	for _, source := range filePaths {
+		if link, ok := opts.NodeLinks[source]; ok {
+			links = append(links, htmlLink{Name: nodeNames[source], URL: link})
+		}
+	}
+	return links, nil
+}
+
+// GenerateURL is not supported for HTML reports; they are opened as files.
+func (f htmlFormatter) GenerateURL(output string) (string, bool) {
+	return "", false
+}
+
+// mermaidScript returns the script element that loads Mermaid. The library is inlined
+// when assets/mermaid/mermaid.min.js has been vendored (make vendor-mermaid), so the
+// report opens offline; otherwise it is loaded from a CDN at the pinned version.
+func mermaidScript() template.HTML {
+	if library, err := mermaidAssets.ReadFile("assets/mermaid/mermaid.min.js"); err == nil {
+		// A closing script tag inside the library would end the element early.
+		inlined := strings.ReplaceAll(string(library), "</script", `<\/script`)
+		return template.HTML("<script>\n" + inlined + "\n</script>")
+	}
+
+	version, err := mermaidAssets.ReadFile("assets/mermaid/VERSION")
+	if err != nil {
+		panic("missing embedded Mermaid version: " + err.Error())
+	}
+	src := fmt.Sprintf("https://cdn.jsdelivr.net/npm/mermaid@%s/dist/mermaid.min.js", strings.TrimSpace(string(version)))
+	return template.HTML(fmt.Sprintf("<script src=%q></script>", src))
+}
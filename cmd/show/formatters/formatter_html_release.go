@@ -0,0 +1,12 @@
+//go:build release
+
+package formatters
+
+import _ "embed"
+
+// Release builds embed the vendored library by name, so a release built without
+// make vendor-mermaid fails to compile instead of shipping reports that load Mermaid
+// from a CDN.
+//
+//go:embed assets/mermaid/mermaid.min.js
+var vendoredMermaid []byte
@@ -0,0 +1,74 @@
+package formatters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/stretchr/testify/require"
+)
+
+// testMermaidScript stands in for the inlined Mermaid library, which is too large to
+// keep in golden files.
+const testMermaidScript = `<script src="mermaid.min.js"></script>`
+
+func TestHTMLFormatter_Report(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/main.go":      {"/project/util.go"},
+		"/project/main_test.go": {"/project/main.go"},
+		"/project/util.go":      {},
+	}, map[string]vcs.FileStats{
+		"/project/util.go": {Additions: 4, IsNew: true},
+	})
+	meta := graph.Meta.Files["/project/main_test.go"]
+	meta.IsTest = true
+	graph.Meta.Files["/project/main_test.go"] = meta
+
+	formatter := htmlFormatter{mermaidScript: testMermaidScript}
+	output, err := formatter.Format(graph, RenderOptions{Label: "project • abc1234-dirty • 3 files"})
+	require.NoError(t, err)
+
+	g := testhelpers.HTMLGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestHTMLFormatter_NodeLinksAreAnchors(t *testing.T) {
+	graph := linkedGraph(t)
+
+	formatter := htmlFormatter{mermaidScript: testMermaidScript}
+	output, err := formatter.Format(graph, RenderOptions{NodeLinks: testNodeLinks})
+	require.NoError(t, err)
+
+	require.Contains(t, output, "<li><a href=\"https://github.com/org/repo/blob/abc123/helpers.go\">helpers.go</a></li>\n"+
+		"  <li><a href=\"https://github.com/org/repo/blob/abc123/main.go\">main.go</a></li>\n</ul>")
+	require.NotContains(t, output, "click ")
+}
+
+func TestHTMLFormatter_EmptyGraphWithoutLabel(t *testing.T) {
+	graph, err := depgraph.NewFileDependencyGraph(depgraph.NewDependencyGraph(), nil, nil)
+	require.NoError(t, err)
+
+	formatter := htmlFormatter{mermaidScript: testMermaidScript}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	require.Contains(t, output, "<title>"+defaultHTMLTitle+"</title>")
+	require.Contains(t, output, EmptyGraphLabel)
+}
+
+func TestHTMLFormatter_GenerateURLIsUnsupported(t *testing.T) {
+	formatter, err := NewFormatter("html")
+	require.NoError(t, err)
+
+	_, ok := formatter.GenerateURL("<html></html>")
+	require.False(t, ok)
+}
+
+func TestMermaidScript_LoadsMermaid(t *testing.T) {
+	script := string(mermaidScript())
+
+	require.True(t, strings.HasPrefix(script, "<script"), script)
+	require.NotContains(t, strings.TrimSuffix(script, "</script>"), "</script")
+}
@@ -0,0 +1,56 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+// graphvizInstallHint lists the ways to get Graphviz's dot command, which renders
+// the svg and png formats.
+const graphvizInstallHint = `install Graphviz ("brew install graphviz" on macOS, ` +
+	`"sudo apt-get install graphviz" on Debian and Ubuntu, "sudo dnf install graphviz" on Fedora, ` +
+	`"winget install graphviz" on Windows), or write -f dot and render it elsewhere`
+
+// lookPath finds the Graphviz dot command. Tests replace it to simulate a machine
+// without Graphviz.
+var lookPath = exec.LookPath
+
+// Format renders the graph with the DOT formatter and lays the result out with
+// Graphviz. The DOT output is the single source of what the image shows.
+func (f imageFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions) (string, error) {
+	dot, err := (&dotFormatter{}).Format(g, opts)
+	if err != nil {
+		return "", err
+	}
+	return renderDOT(dot, f.format)
+}
+
+// GenerateURL returns false: images are written to a file, not shared as a URL.
+func (f imageFormatter) GenerateURL(output string) (string, bool) {
+	return "", false
+}
+
+// renderDOT runs dot -T<format> over source and returns the rendered image.
+func renderDOT(source string, format OutputFormat) (string, error) {
+	dotPath, err := lookPath("dot")
+	if err != nil {
+		return "", fmt.Errorf("-f %s needs the Graphviz dot command, which was not found on PATH: %s", format, graphvizInstallHint)
+	}
+
+	cmd := exec.Command(dotPath, "-T"+format.String())
+	cmd.Stdin = strings.NewReader(source)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("dot -T%s failed: %s", format, message)
+		}
+		return "", fmt.Errorf("dot -T%s failed: %w", format, err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,54 @@
+package formatters
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+func twoNodeGraph(t *testing.T) depgraph.FileDependencyGraph {
+	t.Helper()
+	g, err := depgraph.NewFileDependencyGraph(testGraph(map[string][]string{
+		"/project/main.go": {"/project/util.go"},
+		"/project/util.go": {},
+	}), nil, nil)
+	require.NoError(t, err)
+	return g
+}
+
+func TestImageFormatter_SVG(t *testing.T) {
+	if _, err := exec.LookPath("dot"); err != nil {
+		t.Skip("Graphviz dot is not on PATH")
+	}
+	formatter, err := NewFormatter("svg")
+	require.NoError(t, err)
+
+	output, err := formatter.Format(twoNodeGraph(t), RenderOptions{})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, "<?xml"), output)
+	assert.Contains(t, output, "<svg")
+	assert.Contains(t, output, "main.go")
+	assert.Contains(t, output, "util.go")
+}
+
+func TestImageFormatter_WithoutGraphvizListsInstallOptions(t *testing.T) {
+	restore := lookPath
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	t.Cleanup(func() { lookPath = restore })
+	formatter, err := NewFormatter("png")
+	require.NoError(t, err)
+
+	_, err = formatter.Format(twoNodeGraph(t), RenderOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-f png needs the Graphviz dot command")
+	assert.Contains(t, err.Error(), "brew install graphviz")
+	assert.Contains(t, err.Error(), "-f dot")
+}
@@ -0,0 +1,196 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/metadata"
+)
+
+// JSONGraph is the document emitted by the json output format. Its shape is covered
+// by OutputSchemaVersion like the other formats.
+type JSONGraph struct {
+	// SchemaVersion is the OutputSchemaVersion the document was written with.
+	SchemaVersion int `json:"schemaVersion"`
+	// Label is the graph title rendered by dot and mermaid: repository, commit or range,
+	// and file count.
+	Label string `json:"label,omitempty"`
+	// TransitivelyReduced reports that edges implied by longer paths were dropped.
+	TransitivelyReduced bool        `json:"transitivelyReduced"`
+	Nodes               []JSONNode  `json:"nodes"`
+	Edges               []JSONEdge  `json:"edges"`
+	Cycles              []JSONCycle `json:"cycles"`
+	// Meta records how the graph was produced, written with --metadata.
+	Meta *metadata.Metadata `json:"meta,omitempty"`
+}
+
+// JSONNode is one file in the graph.
+type JSONNode struct {
+	// ID is the repo-relative path used as the node key in dot output and in edges.
+	ID string `json:"id"`
+	// Path is the absolute path of the file.
+	Path string `json:"path"`
+	// Name is the short display name: the base name, or enough of the path to tell
+	// files with the same base name apart.
+	Name string `json:"name"`
+	// Kind is "external" for a third-party package kept with --show-external,
+	// "manifest" for a dependency manifest or lock file, and empty for other files.
+	Kind      string `json:"kind,omitempty"`
+	Extension string `json:"extension"`
+	IsTest    bool   `json:"isTest"`
+	IsPruned  bool   `json:"isPruned"`
+	// CollapsedDependents is the number of files importing a file whose incoming
+	// edges --collapse-threshold removed.
+	CollapsedDependents int `json:"collapsedDependents,omitempty"`
+	// BlobSHA is the git blob SHA of the analyzed content, when known.
+	BlobSHA string         `json:"blobSha,omitempty"`
+	Stats   *JSONNodeStats `json:"stats,omitempty"`
+	// Style is the --style-file rule matched for the file, if any.
+	Style *JSONNodeStyle `json:"style,omitempty"`
+	// Repo is the name of the repository holding the file when the graph spans
+	// several repositories.
+	Repo string `json:"repo,omitempty"`
+}
+
+// JSONNodeStats are the line counts of a changed file.
+type JSONNodeStats struct {
+	Additions   int    `json:"additions"`
+	Deletions   int    `json:"deletions"`
+	IsNew       bool   `json:"isNew"`
+	IsBinary    bool   `json:"isBinary"`
+	RenamedFrom string `json:"renamedFrom,omitempty"`
+}
+
+// JSONNodeStyle is a user-defined node style.
+type JSONNodeStyle struct {
+	Fill     string `json:"fill,omitempty"`
+	Stroke   string `json:"stroke,omitempty"`
+	Class    string `json:"class,omitempty"`
+	Override bool   `json:"override"`
+}
+
+// JSONEdge is a dependency of From on To, both node IDs.
+type JSONEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	InCycle bool   `json:"inCycle"`
+	// Provenance is how the edge was found: parsed, heuristic, expect-actual, or
+	// manifest.
+	Provenance   string `json:"provenance"`
+	Suppressed   bool   `json:"suppressed"`
+	IntroducedIn string `json:"introducedIn,omitempty"`
+	// Label is the short edge label written by --label.
+	Label   string   `json:"label,omitempty"`
+	Symbols []string `json:"symbols,omitempty"`
+	// Usages is the usage count written by --edge-weight usage.
+	Usages int `json:"usages,omitempty"`
+}
+
+// JSONCycle is a representative cycle, as node IDs in dependency order.
+type JSONCycle struct {
+	Path []string `json:"path"`
+}
+
+// Format converts the dependency graph to an indented JSON document.
+func (f jsonFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOptions) (string, error) {
+	adjacency, err := depgraph.AdjacencyList(g.Graph)
+	if err != nil {
+		return "", err
+	}
+
+	filePaths := sortedFiles(adjacency, opts.BasePath)
+	nodeNames := BuildNodeNames(filePaths)
+
+	nodes := make([]JSONNode, 0, len(filePaths))
+	for _, path := range filePaths {
+		md := g.Meta.Files[path]
+		node := JSONNode{
+			ID:                  dotNodeKey(path, opts.BasePath),
+			Path:                path,
+			Name:                nodeNames[path],
+			Kind:                string(md.Kind),
+			Extension:           md.Extension,
+			IsTest:              md.IsTest,
+			IsPruned:            md.IsPruned,
+			CollapsedDependents: md.CollapsedDependents,
+			BlobSHA:             md.BlobSHA,
+			Repo:                md.Repo,
+		}
+		if md.Style != nil {
+			node.Style = &JSONNodeStyle{
+				Fill:     md.Style.Fill,
+				Stroke:   md.Style.Stroke,
+				Class:    md.Style.Class,
+				Override: md.Style.Override,
+			}
+		}
+		if md.Stats != nil {
+			node.Stats = &JSONNodeStats{
+				Additions:   md.Stats.Additions,
+				Deletions:   md.Stats.Deletions,
+				IsNew:       md.Stats.IsNew,
+				IsBinary:    md.Stats.IsBinary,
+				RenamedFrom: md.Stats.RenamedFrom,
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	edges := []JSONEdge{}
+	for _, source := range filePaths {
+		deps := sortedDependencies(adjacency[source], opts.BasePath)
+		for _, dep := range deps {
+			md := g.Meta.Edges[depgraph.FileEdge{From: source, To: dep}]
+			edge := JSONEdge{
+				From:         dotNodeKey(source, opts.BasePath),
+				To:           dotNodeKey(dep, opts.BasePath),
+				InCycle:      md.InCycle,
+				Provenance:   jsonEdgeProvenance(md.Provenance),
+				Suppressed:   md.Suppressed,
+				IntroducedIn: md.IntroducedIn,
+				Symbols:      md.Symbols,
+				Usages:       edgeUsages(opts, md.Usages),
+			}
+			if opts.EdgeLabels {
+				edge.Label = EdgeLabel(nodeNames[source], nodeNames[dep])
+			}
+			edges = append(edges, edge)
+		}
+	}
+
+	cycles := make([]JSONCycle, 0, len(g.Meta.Cycles))
+	for _, cycle := range g.Meta.Cycles {
+		path := make([]string, 0, len(cycle.Path))
+		for _, node := range cycle.Path {
+			path = append(path, dotNodeKey(node, opts.BasePath))
+		}
+		cycles = append(cycles, JSONCycle{Path: path})
+	}
+
+	output, err := json.MarshalIndent(JSONGraph{
+		SchemaVersion:       OutputSchemaVersion,
+		Label:               opts.Label,
+		TransitivelyReduced: g.Meta.TransitivelyReduced,
+		Nodes:               nodes,
+		Edges:               edges,
+		Cycles:              cycles,
+		Meta:                graphMetadata(adjacency, opts),
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode graph: %w", err)
+	}
+	return string(output), nil
+}
+
+// GenerateURL is not supported for JSON output.
+func (f jsonFormatter) GenerateURL(output string) (string, bool) {
+	return "", false
+}
+
+func jsonEdgeProvenance(provenance depgraph.EdgeProvenance) string {
+	if provenance == depgraph.EdgeProvenanceParsed {
+		return "parsed"
+	}
+	return string(provenance)
+}
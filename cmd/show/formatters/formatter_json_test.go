@@ -0,0 +1,173 @@
+package formatters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestJSONFormatter_NodesEdgesAndStats(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/main.go":      {"/project/util/util.go", "/project/db/db.go"},
+		"/project/util/util.go": {},
+		"/project/db/db.go":     {},
+		"/project/main_test.go": {"/project/main.go"},
+		"/project/logo.png":     {},
+	}, map[string]vcs.FileStats{
+		"/project/main.go":      {Additions: 12, Deletions: 3},
+		"/project/util/util.go": {Additions: 20, IsNew: true},
+		"/project/db/db.go":     {Additions: 1, Deletions: 1, RenamedFrom: "legacy/db.go"},
+		"/project/logo.png":     {IsBinary: true},
+	})
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "project • abc1234 • 5 files", BasePath: "/project"})
+	require.NoError(t, err)
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestJSONFormatter_EdgeMetadata(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/a.go":     {"/project/b.go", "/project/c.go"},
+		"/project/b.go":     {"/project/a.go"},
+		"/project/c.go":     {},
+		"/project/App.vue":  {"/project/store.ts"},
+		"/project/store.ts": {},
+	}, nil)
+
+	setEdge := func(from, to string, update func(*depgraph.EdgeMetadata)) {
+		edge := depgraph.FileEdge{From: from, To: to}
+		md := graph.Meta.Edges[edge]
+		update(&md)
+		graph.Meta.Edges[edge] = md
+	}
+	setEdge("/project/a.go", "/project/c.go", func(md *depgraph.EdgeMetadata) {
+		md.Suppressed = true
+		md.IntroducedIn = "abc1234"
+		md.Symbols = []string{"Config", "Load"}
+	})
+	setEdge("/project/App.vue", "/project/store.ts", func(md *depgraph.EdgeMetadata) {
+		md.Provenance = depgraph.EdgeProvenanceHeuristic
+	})
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project", EdgeLabels: true})
+	require.NoError(t, err)
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestJSONFormatter_CustomStylesAndPrunedNodes(t *testing.T) {
+	graph := withStyles(t, testFileGraph(t, styledTestAdjacency, nil), overlappingStyles)
+	for path, md := range graph.Meta.Files {
+		if md.Style == nil {
+			md.IsPruned = true
+			graph.Meta.Files[path] = md
+		}
+	}
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestJSONFormatter_EmptyGraph(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{}, nil)
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestJSONFormatter_RoundTrips(t *testing.T) {
+	graph := testFileGraph(t, map[string][]string{
+		"/project/a.go": {"/project/b.go"},
+		"/project/b.go": {"/project/a.go"},
+	}, map[string]vcs.FileStats{
+		"/project/a.go": {Additions: 2, Deletions: 1, IsNew: true},
+	})
+	graph.Meta.TransitivelyReduced = true
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "project", BasePath: "/project"})
+	require.NoError(t, err)
+
+	var decoded JSONGraph
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	require.Equal(t, JSONGraph{
+		SchemaVersion:       OutputSchemaVersion,
+		Label:               "project",
+		TransitivelyReduced: true,
+		Nodes: []JSONNode{
+			{ID: "a.go", Path: "/project/a.go", Name: "a.go", Extension: ".go", Stats: &JSONNodeStats{Additions: 2, Deletions: 1, IsNew: true}},
+			{ID: "b.go", Path: "/project/b.go", Name: "b.go", Extension: ".go"},
+		},
+		Edges: []JSONEdge{
+			{From: "a.go", To: "b.go", InCycle: true, Provenance: "parsed"},
+			{From: "b.go", To: "a.go", InCycle: true, Provenance: "parsed"},
+		},
+		Cycles: []JSONCycle{{Path: []string{"a.go", "b.go"}}},
+	}, decoded)
+
+	reencoded, err := json.MarshalIndent(decoded, "", "  ")
+	require.NoError(t, err)
+	require.Equal(t, output, string(reencoded))
+}
+
+func TestJSONFormatter_GenerateURLIsUnsupported(t *testing.T) {
+	_, ok := jsonFormatter{}.GenerateURL("{}")
+	require.False(t, ok)
+}
+
+func TestJSONFormatter_ManifestKindAndProvenance(t *testing.T) {
+	graph := manifestGraph(t)
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+
+	var doc JSONGraph
+	require.NoError(t, json.Unmarshal([]byte(output), &doc))
+	kinds := make(map[string]string)
+	for _, node := range doc.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+	require.Equal(t, map[string]string{"go.mod": "manifest", "main.go": "", "util.go": ""}, kinds)
+	provenances := make(map[string]string)
+	for _, edge := range doc.Edges {
+		provenances[edge.From+" -> "+edge.To] = edge.Provenance
+	}
+	require.Equal(t, map[string]string{
+		"main.go -> go.mod":  "manifest",
+		"main.go -> util.go": "parsed",
+		"util.go -> go.mod":  "manifest",
+	}, provenances)
+}
+
+func TestJSONFormatter_EdgeWeightUsageWritesCounts(t *testing.T) {
+	graph := usageWeightedGraph(t)
+
+	formatter := jsonFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{BasePath: "/project", EdgeWeight: EdgeWeightUsage})
+	require.NoError(t, err)
+	require.Contains(t, output, `"to": "helpers.go",`)
+	require.Contains(t, output, `"usages": 12`)
+
+	unweighted, err := formatter.Format(graph, RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+	require.NotContains(t, unweighted, `"usages"`)
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/LegacyCodeHQ/clarity/depgraph"
@@ -20,12 +21,13 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 	}
 
 	explicitDirection := opts.Direction != ""
+	colors := opts.theme().Mermaid
 	var sb strings.Builder
 
 	// Add title if label provided
 	if opts.Label != "" {
 		sb.WriteString("---\n")
-		sb.WriteString(fmt.Sprintf("title: %s\n", opts.Label))
+		sb.WriteString(fmt.Sprintf("title: %s\n", mermaidTitle(opts.Label)))
 		sb.WriteString("---\n")
 	}
 
@@ -33,8 +35,27 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 	if dir == "" {
 		dir = DefaultDirection
 	}
+	if colors.Background != "" {
+		sb.WriteString(fmt.Sprintf("%%%%{init: {\"themeVariables\": {\"background\": \"%s\"}}}%%%%\n", colors.Background))
+	}
 	sb.WriteString(fmt.Sprintf("flowchart %s\n", dir.String()))
 
+	if len(adjacency) == 0 {
+		sb.WriteString(fmt.Sprintf("    empty[\"%s\"]\n", EmptyGraphLabel))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("    classDef emptyGraph %s,stroke-dasharray: 5 5\n", mermaidClassColors(colors.MajorityExt, colors.Border, colors.Placeholder)))
+		sb.WriteString("    class empty emptyGraph")
+		if explicitDirection {
+			sb.WriteString("\n")
+		}
+		return appendMetadataComment(sb.String(), "%%", adjacency, opts), nil
+	}
+
+	// Files and their dependencies are written in sortedFiles order, so the output is
+	// the same on every run.
+	filePaths := sortedFiles(adjacency, opts.BasePath)
+	nodeNames := BuildNodeNames(filePaths)
+
 	cycleNodes := make(map[string]bool)
 	if len(g.Meta.Cycles) > 0 {
 		for i, cycle := range g.Meta.Cycles {
@@ -44,10 +65,10 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 
 			var cycleParts []string
 			for _, node := range cycle.Path {
-				cycleParts = append(cycleParts, filepath.Base(node))
+				cycleParts = append(cycleParts, nodeNames[node])
 				cycleNodes[node] = true
 			}
-			cycleParts = append(cycleParts, filepath.Base(cycle.Path[0]))
+			cycleParts = append(cycleParts, nodeNames[cycle.Path[0]])
 			sb.WriteString(fmt.Sprintf("%%%% C%d: %s\n", i+1, strings.Join(cycleParts, " -> ")))
 		}
 	}
@@ -59,29 +80,20 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 		cycleNodes[edge.To] = true
 	}
 
-	// Collect and sort file paths for deterministic output
-	filePaths := make([]string, 0, len(adjacency))
-	for source := range adjacency {
-		filePaths = append(filePaths, source)
+	// Create a mapping from file paths to valid Mermaid node IDs.
+	// Mermaid node IDs can't have dots or special characters, and keying them by
+	// path keeps files that share a base name apart.
+	nodeIDs := make(map[string]string, len(filePaths))
+	for i, source := range filePaths {
+		nodeIDs[source] = fmt.Sprintf("n%d", i)
 	}
-	sort.Strings(filePaths)
-	nodeNames := BuildNodeNames(filePaths)
 
-	// Create a mapping from node keys to valid Mermaid node IDs.
-	// Mermaid node IDs can't have dots or special characters.
-	nodeIDs := make(map[string]string)
-	nodeCounter := 0
-	for _, source := range filePaths {
-		sourceNodeKey := nodeNames[source]
-		if _, exists := nodeIDs[sourceNodeKey]; !exists {
-			nodeIDs[sourceNodeKey] = fmt.Sprintf("n%d", nodeCounter)
-			nodeCounter++
-		}
-	}
+	// External packages are not files, so they take no part in extension styling.
+	files := withoutExternalNodes(filePaths)
 
 	// Count files by extension to find the majority extension
 	extensionCounts := make(map[string]int)
-	for _, source := range filePaths {
+	for _, source := range files {
 		ext := filepath.Ext(filepath.Base(source))
 		extensionCounts[ext]++
 	}
@@ -106,55 +118,36 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 
 	// Track all files that have the majority extension
 	filesWithMajorityExtension := make(map[string]bool)
-	for _, source := range filePaths {
+	for _, source := range files {
 		ext := filepath.Ext(filepath.Base(source))
 		if ext == majorityExtension {
 			filesWithMajorityExtension[source] = true
 		}
 	}
 
-	// Track which nodes have been defined
-	definedNodes := make(map[string]bool)
-
-	// Define nodes with labels and styles
-	for _, source := range filePaths {
-		sourceNodeKey := nodeNames[source]
-		nodeID := nodeIDs[sourceNodeKey]
-
-		if !definedNodes[sourceNodeKey] {
-			// Build node label with file stats if available
-			nodeLabel := nodeNames[source]
-			if fileMetadata, ok := g.Meta.Files[source]; ok && fileMetadata.Stats != nil {
-				stats := *fileMetadata.Stats
-				labelPrefix := nodeLabel
-				if stats.IsNew {
-					labelPrefix = fmt.Sprintf("🪴 %s", labelPrefix)
-				}
-
-				if stats.Additions > 0 || stats.Deletions > 0 {
-					var statsParts []string
-					if stats.Additions > 0 {
-						statsParts = append(statsParts, fmt.Sprintf("+%d", stats.Additions))
-					}
-					if stats.Deletions > 0 {
-						statsParts = append(statsParts, fmt.Sprintf("-%d", stats.Deletions))
-					}
-					if len(statsParts) > 0 {
-						nodeLabel = fmt.Sprintf("%s<br/>%s", labelPrefix, strings.Join(statsParts, " "))
-					} else {
-						nodeLabel = labelPrefix
-					}
-				} else if stats.IsNew {
-					nodeLabel = labelPrefix
-				}
-			}
-
-			// Escape quotes in labels
-			nodeLabel = strings.ReplaceAll(nodeLabel, "\"", "#quot;")
-
-			sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", nodeID, nodeLabel))
-			definedNodes[sourceNodeKey] = true
+	// Define nodes with labels and styles, inside a subgraph per cluster
+	clusters, unclustered := clusterFiles(g, filePaths, opts)
+	writeNode := func(source, indent string) {
+		if depgraph.IsExternalNode(source) {
+			sb.WriteString(fmt.Sprintf("%s%s{{\"%s\"}}\n", indent, nodeIDs[source], escapeMermaidLabel(nodeNames[source])))
+			return
 		}
+		nodeLabel := BuildNodeLabel(nodeNames[source], g.Meta.Files[source]).Join("<br/>", escapeMermaidLabel)
+		if g.Meta.Files[source].Kind == depgraph.NodeKindManifest {
+			sb.WriteString(fmt.Sprintf("%s%s{\"%s\"}\n", indent, nodeIDs[source], nodeLabel))
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%s%s[\"%s\"]\n", indent, nodeIDs[source], nodeLabel))
+	}
+	for _, source := range unclustered {
+		writeNode(source, "    ")
+	}
+	for i, cluster := range clusters {
+		sb.WriteString(fmt.Sprintf("    subgraph cluster%d[\"%s\"]\n", i, escapeMermaidLabel(cluster.label)))
+		for _, source := range cluster.files {
+			writeNode(source, "        ")
+		}
+		sb.WriteString("    end\n")
 	}
 
 	// Define edges
@@ -162,26 +155,43 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 	hasEdges := false
 	edgeIndex := 0
 	var cycleEdgeIndices []int
+	var suppressedEdgeIndices []int
 	for _, source := range filePaths {
-		deps := adjacency[source]
-		sortedDeps := make([]string, len(deps))
-		copy(sortedDeps, deps)
-		sort.Strings(sortedDeps)
+		sortedDeps := sortedDependencies(adjacency[source], opts.BasePath)
 
 		sourceNodeKey := nodeNames[source]
-		sourceID := nodeIDs[sourceNodeKey]
+		sourceID := nodeIDs[source]
 		for _, dep := range sortedDeps {
 			depNodeKey := nodeNames[dep]
-			depID := nodeIDs[depNodeKey]
+			depID := nodeIDs[dep]
 			hasEdges = true
+			edgeMD := g.Meta.Edges[depgraph.FileEdge{From: source, To: dep}]
+			arrow := "-->"
+			switch edgeMD.Provenance {
+			case depgraph.EdgeProvenanceHeuristic, depgraph.EdgeProvenanceManifest:
+				arrow = "-.->"
+			case depgraph.EdgeProvenanceExpectActual:
+				arrow = "<-->"
+			case depgraph.EdgeProvenanceParsed:
+			}
+			var labels []string
 			if opts.EdgeLabels {
-				label := EdgeLabel(sourceNodeKey, depNodeKey)
-				edgesSB.WriteString(fmt.Sprintf("    %s -->|%s| %s\n", sourceID, label, depID))
+				labels = append(labels, EdgeLabel(sourceNodeKey, depNodeKey))
+			}
+			if opts.EdgeSymbols && len(edgeMD.Symbols) > 0 {
+				labels = append(labels, EdgeSymbolsText(edgeMD.Symbols))
+			}
+			if usages := edgeUsages(opts, edgeMD.Usages); usages > 0 {
+				labels = append(labels, strconv.Itoa(usages))
+			}
+			if len(labels) > 0 {
+				edgesSB.WriteString(fmt.Sprintf("    %s %s|%s| %s\n", sourceID, arrow, escapeMermaidLabel(strings.Join(labels, ": ")), depID))
 			} else {
-				edgesSB.WriteString(fmt.Sprintf("    %s --> %s\n", sourceID, depID))
+				edgesSB.WriteString(fmt.Sprintf("    %s %s %s\n", sourceID, arrow, depID))
 			}
-			edgeMD := g.Meta.Edges[depgraph.FileEdge{From: source, To: dep}]
-			if edgeMD.InCycle {
+			if edgeMD.Suppressed {
+				suppressedEdgeIndices = append(suppressedEdgeIndices, edgeIndex)
+			} else if edgeMD.InCycle {
 				cycleEdgeIndices = append(cycleEdgeIndices, edgeIndex)
 			}
 			edgeIndex++
@@ -193,39 +203,92 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 	var testNodes []string
 	var majorityExtensionNodes []string
 	var prunedNodes []string
+	var externalNodes []string
+	var manifestNodes []string
+	var newNodes []string
+
+	// Files of the other extensions take the theme's extension palette, when it has
+	// one, in the order getExtensionColors assigns it.
+	paletteIndices := make(map[string]int)
+	extensionNodes := make([][]string, len(colors.ExtPalette))
+	if len(colors.ExtPalette) > 0 {
+		for _, ext := range sortedExtensions {
+			if ext != "" {
+				paletteIndices[ext] = len(paletteIndices) % len(colors.ExtPalette)
+			}
+		}
+	}
 
 	// Count unique file extensions to determine if majority styling is meaningful.
 	uniqueExtensions := make(map[string]bool)
-	for _, source := range filePaths {
+	for _, source := range files {
 		ext := filepath.Ext(filepath.Base(source))
 		uniqueExtensions[ext] = true
 	}
 	hasMultipleExtensions := len(uniqueExtensions) > 1
 
+	// User-defined styles, keyed by class name.
+	customStyles := make(map[string]depgraph.NodeStyle)
+	customStyleNodes := make(map[string][]string)
+
+	sizeTierNodes := make([][]string, len(mermaidSizeTiers))
 	for _, source := range filePaths {
-		sourceNodeKey := nodeNames[source]
-		nodeID := nodeIDs[sourceNodeKey]
+		nodeID := nodeIDs[source]
+		if depgraph.IsExternalNode(source) {
+			externalNodes = append(externalNodes, nodeID)
+			continue
+		}
 
 		fileMetadata, hasFileMetadata := g.Meta.Files[source]
+		if tier := mermaidSizeTierIndex(opts, fileMetadata); tier >= 0 {
+			sizeTierNodes[tier] = append(sizeTierNodes[tier], nodeID)
+		}
+		if customStyle, ok := fileMetadata.AppliedStyle(); ok {
+			// AppliedStyle only returns a style for test or pruned files when it
+			// overrides their built-in class.
+			if customStyle.Fill != "" || customStyle.Stroke != "" {
+				customStyles[customStyle.Class] = customStyle
+				customStyleNodes[customStyle.Class] = append(customStyleNodes[customStyle.Class], nodeID)
+			}
+			continue
+		}
 		if hasFileMetadata && fileMetadata.IsPruned {
 			prunedNodes = append(prunedNodes, nodeID)
 		}
 		if hasFileMetadata && fileMetadata.IsTest {
 			testNodes = append(testNodes, nodeID)
+		} else if fileMetadata.Kind == depgraph.NodeKindManifest {
+			manifestNodes = append(manifestNodes, nodeID)
+		} else if colors.NewFile != "" && isNewFile(fileMetadata) {
+			newNodes = append(newNodes, nodeID)
 		} else if hasMultipleExtensions && filesWithMajorityExtension[source] {
 			majorityExtensionNodes = append(majorityExtensionNodes, nodeID)
+		} else if i, ok := paletteIndices[filepath.Ext(source)]; ok && hasMultipleExtensions {
+			extensionNodes[i] = append(extensionNodes[i], nodeID)
 		}
 	}
 
-	hasStyles := len(testNodes) > 0 || len(majorityExtensionNodes) > 0 || len(cycleNodes) > 0 || len(cycleEdgeIndices) > 0 || len(prunedNodes) > 0
+	hasSizeTiers := false
+	for _, nodes := range sizeTierNodes {
+		hasSizeTiers = hasSizeTiers || len(nodes) > 0
+	}
+	hasExtensionNodes := false
+	for _, nodes := range extensionNodes {
+		hasExtensionNodes = hasExtensionNodes || len(nodes) > 0
+	}
+
+	hasStyles := len(testNodes) > 0 || len(majorityExtensionNodes) > 0 || len(cycleNodes) > 0 || len(cycleEdgeIndices) > 0 || len(suppressedEdgeIndices) > 0 || len(prunedNodes) > 0 || len(customStyles) > 0 || hasSizeTiers || len(externalNodes) > 0 || len(manifestNodes) > 0 || len(newNodes) > 0 || hasExtensionNodes || colors.Edge != ""
 	var stylesSB strings.Builder
 
 	// Define style classes
+	if colors.Edge != "" {
+		stylesSB.WriteString(fmt.Sprintf("    linkStyle default stroke:%s\n", colors.Edge))
+	}
 	if len(testNodes) > 0 {
-		stylesSB.WriteString("    classDef testFile fill:#90EE90,stroke:#228B22,color:#000000\n")
+		stylesSB.WriteString(fmt.Sprintf("    classDef testFile %s\n", mermaidClassColors(colors.TestFile, colors.TestFileBorder, colors.Text)))
 	}
 	if len(majorityExtensionNodes) > 0 {
-		stylesSB.WriteString("    classDef majorityExtension fill:#FFFFFF,stroke:#999999,color:#000000\n")
+		stylesSB.WriteString(fmt.Sprintf("    classDef majorityExtension %s\n", mermaidClassColors(colors.MajorityExt, colors.Border, colors.Text)))
 	}
 
 	// Apply styles to nodes
@@ -235,25 +298,82 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 	if len(majorityExtensionNodes) > 0 {
 		stylesSB.WriteString(fmt.Sprintf("    class %s majorityExtension\n", strings.Join(majorityExtensionNodes, ",")))
 	}
+	for i, nodes := range extensionNodes {
+		if len(nodes) == 0 {
+			continue
+		}
+		stylesSB.WriteString(fmt.Sprintf("    classDef extension%d %s\n", i+1, mermaidClassColors(colors.ExtPalette[i], colors.Border, colors.Text)))
+		stylesSB.WriteString(fmt.Sprintf("    class %s extension%d\n", strings.Join(nodes, ","), i+1))
+	}
+	if len(newNodes) > 0 {
+		stylesSB.WriteString(fmt.Sprintf("    classDef newFile %s\n", mermaidClassColors(colors.NewFile, colors.Border, colors.Text)))
+		stylesSB.WriteString(fmt.Sprintf("    class %s newFile\n", strings.Join(newNodes, ",")))
+	}
+	if len(manifestNodes) > 0 {
+		stylesSB.WriteString(fmt.Sprintf("    classDef manifestFile %s\n", mermaidClassColors(colors.Manifest, colors.ManifestBorder, colors.Text)))
+		stylesSB.WriteString(fmt.Sprintf("    class %s manifestFile\n", strings.Join(manifestNodes, ",")))
+	}
+	if len(externalNodes) > 0 {
+		stylesSB.WriteString(fmt.Sprintf("    classDef externalPackage %s\n", mermaidClassColors(colors.External, colors.ExternalBorder, colors.Text)))
+		stylesSB.WriteString(fmt.Sprintf("    class %s externalPackage\n", strings.Join(externalNodes, ",")))
+	}
 	if len(prunedNodes) > 0 {
-		stylesSB.WriteString("    classDef prunedFile fill:#FFFFFF,stroke:#999999,stroke-dasharray: 5 5\n")
+		stylesSB.WriteString(fmt.Sprintf("    classDef prunedFile %s,stroke-dasharray: 5 5\n", mermaidClassColors(colors.MajorityExt, colors.Pruned, "")))
 		stylesSB.WriteString(fmt.Sprintf("    class %s prunedFile\n", strings.Join(prunedNodes, ",")))
 	}
+	customClasses := make([]string, 0, len(customStyles))
+	for class := range customStyles {
+		customClasses = append(customClasses, class)
+	}
+	sort.Strings(customClasses)
+	for _, class := range customClasses {
+		style := customStyles[class]
+		var properties []string
+		if style.Fill != "" {
+			properties = append(properties, "fill:"+style.Fill)
+		}
+		if style.Stroke != "" {
+			properties = append(properties, "stroke:"+style.Stroke)
+		}
+		stylesSB.WriteString(fmt.Sprintf("    classDef %s %s\n", class, strings.Join(properties, ",")))
+		stylesSB.WriteString(fmt.Sprintf("    class %s %s\n", strings.Join(customStyleNodes[class], ","), class))
+	}
+	for i, tier := range mermaidSizeTiers {
+		if len(sizeTierNodes[i]) == 0 {
+			continue
+		}
+		stylesSB.WriteString(fmt.Sprintf("    classDef %s font-size:%s\n", tier.class, tier.fontSize))
+		stylesSB.WriteString(fmt.Sprintf("    class %s %s\n", strings.Join(sizeTierNodes[i], ","), tier.class))
+	}
 	for _, source := range filePaths {
 		if !cycleNodes[source] {
 			continue
 		}
-		sourceNodeKey := nodeNames[source]
-		stylesSB.WriteString(fmt.Sprintf("    style %s stroke:#d62728,stroke-width:3px\n", nodeIDs[sourceNodeKey]))
+		stylesSB.WriteString(fmt.Sprintf("    style %s stroke:%s,stroke-width:3px\n", nodeIDs[source], colors.Cycle))
 	}
 	for _, idx := range cycleEdgeIndices {
-		stylesSB.WriteString(fmt.Sprintf("    linkStyle %d stroke:#d62728,stroke-width:3px,stroke-dasharray: 5 5\n", idx))
+		stylesSB.WriteString(fmt.Sprintf("    linkStyle %d stroke:%s,stroke-width:3px,stroke-dasharray: 5 5\n", idx, colors.Cycle))
+	}
+	for _, idx := range suppressedEdgeIndices {
+		stylesSB.WriteString(fmt.Sprintf("    linkStyle %d stroke:%s\n", idx, colors.Suppressed))
+	}
+
+	// Link nodes to their files, in node order
+	var linksSB strings.Builder
+	for _, source := range filePaths {
+		if link, ok := opts.NodeLinks[source]; ok {
+			linksSB.WriteString(fmt.Sprintf("    click %s \"%s\"\n", nodeIDs[source], strings.ReplaceAll(link, "\"", "%22")))
+		}
 	}
 
 	if hasEdges {
 		sb.WriteString("\n")
 		sb.WriteString(edgesSB.String())
 	}
+	if linksSB.Len() > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(linksSB.String())
+	}
 	if hasStyles {
 		sb.WriteString("\n")
 		sb.WriteString(stylesSB.String())
@@ -261,9 +381,21 @@ func (f mermaidFormatter) Format(g depgraph.FileDependencyGraph, opts RenderOpti
 
 	output := strings.TrimSuffix(sb.String(), "\n")
 	if explicitDirection {
-		return output + "\n", nil
+		output += "\n"
 	}
-	return output, nil
+	return appendMetadataComment(output, "%%", adjacency, opts), nil
+}
+
+// mermaidClassColors returns the fill, stroke, and text color properties of a
+// classDef, leaving out empty colors.
+func mermaidClassColors(fill, stroke, text string) string {
+	var properties []string
+	for _, property := range []struct{ name, color string }{{"fill", fill}, {"stroke", stroke}, {"color", text}} {
+		if property.color != "" {
+			properties = append(properties, property.name+":"+property.color)
+		}
+	}
+	return strings.Join(properties, ",")
 }
 
 // GenerateURL creates a mermaid.live URL with the diagram embedded.
@@ -286,3 +418,37 @@ func (f mermaidFormatter) GenerateURL(output string) (string, bool) {
 	encoded := base64.URLEncoding.EncodeToString(jsonBytes)
 	return fmt.Sprintf("https://mermaid.live/edit#base64:%s", encoded), true
 }
+
+// mermaidLabelEscaper replaces the characters Mermaid reads as syntax inside node and
+// edge labels with entity codes. # comes first so the codes themselves are kept.
+var mermaidLabelEscaper = strings.NewReplacer(
+	"#", "#35;",
+	"\"", "#quot;",
+	"[", "#91;",
+	"]", "#93;",
+	"(", "#40;",
+	")", "#41;",
+	"{", "#123;",
+	"}", "#125;",
+	"<", "#lt;",
+	">", "#gt;",
+	"|", "#124;",
+	"`", "#96;",
+)
+
+// escapeMermaidLabel escapes Mermaid syntax characters in one line of a node or edge
+// label.
+func escapeMermaidLabel(line string) string {
+	return mermaidLabelEscaper.Replace(line)
+}
+
+// mermaidTitle returns the label as a YAML scalar for the title front-matter, quoted
+// when it contains characters YAML would otherwise read as syntax.
+func mermaidTitle(label string) string {
+	if !strings.ContainsAny(label, ":#\"'`[]{}|>&*!%@,?\\") && strings.TrimSpace(label) == label {
+		return label
+	}
+	// A JSON string is a valid YAML double-quoted scalar.
+	quoted, _ := json.Marshal(label)
+	return string(quoted)
+}
@@ -154,6 +154,28 @@ func TestMermaidFormatter_NewFilesUseSeedlingLabel(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestMermaidFormatter_StatsLabelCases(t *testing.T) {
+	graph := testFileGraphMermaid(t, labelCaseAdjacency, labelCaseStats)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_RenamedFiles(t *testing.T) {
+	graph := testFileGraphMermaid(t, renameAdjacency, renameStats)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
 func TestMermaidFormatter_TestFilesAreStyled(t *testing.T) {
 	graph := testFileGraphMermaid(t, map[string][]string{
 		"/project/main.go":       {"/project/utils.go"},
@@ -267,6 +289,17 @@ func TestMermaidFormatter_EmptyGraph(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestMermaidFormatter_EmptyGraphWithLabel(t *testing.T) {
+	graph := testFileGraphMermaid(t, make(map[string][]string), nil)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "abc1234"})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
 func TestMermaidFormatter_FileStatsWithOnlyAdditions(t *testing.T) {
 	graph := testFileGraphMermaid(t, map[string][]string{
 		"/project/modified.go": {},
@@ -375,6 +408,54 @@ func TestMermaidFormatter_PrunedNodesHaveDashedBorder(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestMermaidFormatter_CustomStylesLastRuleWins(t *testing.T) {
+	// The pricing rule overrides the domain rule, and the test file keeps its
+	// built-in class because neither rule sets override.
+	graph := withStyles(t, testFileGraphMermaid(t, styledTestAdjacency, nil), overlappingStyles)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_CustomStyleOverridesBuiltInClass(t *testing.T) {
+	graph := withStyles(t, testFileGraphMermaid(t, styledTestAdjacency, nil), overlappingStyles+`  - match: "*_test.go"
+    fill: "#ffec8b"
+    class: domainTest
+    override: true
+`)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_HeuristicEdgesAreDotted(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/App.vue":  {"/project/store.ts"},
+		"/project/main.ts":  {"/project/store.ts"},
+		"/project/store.ts": {},
+	}, nil)
+
+	edge := depgraph.FileEdge{From: "/project/App.vue", To: "/project/store.ts"}
+	md := graph.Meta.Edges[edge]
+	md.Provenance = depgraph.EdgeProvenanceHeuristic
+	graph.Meta.Edges[edge] = md
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
 func TestMermaidFormatter_EdgeLabels(t *testing.T) {
 	graph := testFileGraphMermaid(t, map[string][]string{
 		"/project/a.go": {"/project/b.go", "/project/c.go"},
@@ -390,6 +471,26 @@ func TestMermaidFormatter_EdgeLabels(t *testing.T) {
 	g.Assert(t, t.Name(), []byte(output))
 }
 
+func TestMermaidFormatter_EdgeSymbols(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/a.go": {"/project/b.go", "/project/c.go"},
+		"/project/b.go": {"/project/c.go"},
+		"/project/c.go": {},
+	}, nil)
+
+	edge := depgraph.FileEdge{From: "/project/a.go", To: "/project/b.go"}
+	md := graph.Meta.Edges[edge]
+	md.Symbols = []string{"Client", "NewClient"}
+	graph.Meta.Edges[edge] = md
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{EdgeLabels: true, EdgeSymbols: true})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
 func TestMermaidFormatter_DuplicateBaseNamesStayDistinct(t *testing.T) {
 	graph := testFileGraphMermaid(t, map[string][]string{
 		"/project/test/res.send.js":      {"/project/test/support/utils.js"},
@@ -404,3 +505,123 @@ func TestMermaidFormatter_DuplicateBaseNamesStayDistinct(t *testing.T) {
 	g := testhelpers.MermaidGoldie(t)
 	g.Assert(t, t.Name(), []byte(output))
 }
+
+func TestMermaidFormatter_DuplicateBaseNamesKeepTheirOwnEdges(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/main.go":        {"/project/pkg/a/utils.go"},
+		"/project/pkg/a/utils.go": {"/project/pkg/b/utils.go"},
+		"/project/pkg/b/utils.go": {},
+	}, nil)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_EscapesReservedCharactersInLabels(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/app/[id].tsx":           {"/project/lib/data(v2).ts"},
+		"/project/lib/data(v2).ts":        {"/project/lib/`quoted` #1 {x}.ts"},
+		"/project/lib/`quoted` #1 {x}.ts": {},
+		"/project/lib/a|b <c> \"d\".ts":   {},
+		"/project/app/layout.test.ts":     {"/project/lib/a|b <c> \"d\".ts"},
+	}, nil)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "app: feature/#42 [wip]", EdgeLabels: true})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_NodeSizeByLinesOfCode(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/main.go":   {"/project/small.go", "/project/large.go"},
+		"/project/small.go":  {},
+		"/project/medium.go": {},
+		"/project/large.go":  {},
+		"/project/unread.go": {},
+	}, nil)
+	setLineCounts(graph, map[string]int{
+		"/project/main.go":   149,
+		"/project/small.go":  3,
+		"/project/medium.go": 150,
+		"/project/large.go":  500,
+	})
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{NodeSize: NodeSizeLOC})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_ClustersByDirectory(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/main.go":              {"/project/api/handler.go"},
+		"/project/api/handler.go":       {"/project/store/db.go"},
+		"/project/store/db.go":          {},
+		"/project/store/sql/gen/gen.go": {},
+	}, nil)
+	graph.AssignDirectories("/project")
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Cluster: ClusterDir, ClusterDepth: 1})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_ExternalPackagesAreHexagons(t *testing.T) {
+	graph := testFileGraphMermaid(t, map[string][]string{
+		"/project/main.go":          {"/project/util.go", "go:github.com/spf13/cobra"},
+		"/project/util.go":          {"go:github.com/spf13/cobra"},
+		"go:github.com/spf13/cobra": {},
+	}, nil)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_ManifestsAreRhombuses(t *testing.T) {
+	graph := manifestGraph(t)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_NodeLinksAddClicks(t *testing.T) {
+	graph := linkedGraph(t)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{NodeLinks: testNodeLinks})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_EdgeWeightUsageLabelsCounts(t *testing.T) {
+	graph := usageWeightedGraph(t)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{EdgeWeight: EdgeWeightUsage})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
@@ -1,6 +1,81 @@
 package formatters
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/rules"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// overlappingStyles has a broad domain rule and a later, narrower pricing rule.
+const overlappingStyles = `styles:
+  - match: "domain/**"
+    fill: "#ffd700"
+    stroke: "#b8860b"
+    class: domain
+  - match: "domain/pricing/**"
+    fill: "#fff8dc"
+`
+
+// styledTestAdjacency has a test file and a pricing file that both match the domain rule.
+var styledTestAdjacency = map[string][]string{
+	"/project/adapters/db.go":          {"/project/domain/order.go"},
+	"/project/domain/order.go":         {"/project/domain/pricing/price.go"},
+	"/project/domain/order_test.go":    {"/project/domain/order.go"},
+	"/project/domain/pricing/price.go": {},
+}
+
+// labelCaseAdjacency and labelCaseStats cover each kind of node label: a new file
+// with and without line counts, a changed file, a binary file, and a renamed file.
+var labelCaseAdjacency = map[string][]string{
+	"/project/app.go":       {"/project/new_stats.go", "/project/new_empty.go", "/project/changed.go", "/project/logo.png", "/project/renamed.go"},
+	"/project/new_stats.go": {},
+	"/project/new_empty.go": {},
+	"/project/changed.go":   {},
+	"/project/logo.png":     {},
+	"/project/renamed.go":   {},
+}
+
+var labelCaseStats = map[string]vcs.FileStats{
+	"/project/new_stats.go": {IsNew: true, Additions: 12},
+	"/project/new_empty.go": {IsNew: true},
+	"/project/changed.go":   {Additions: 3, Deletions: 1},
+	"/project/logo.png":     {IsBinary: true},
+	"/project/renamed.go":   {Additions: 2, Deletions: 2, RenamedFrom: "legacy/\"old\".go"},
+}
+
+// renameAdjacency and renameStats cover a pure rename, which keeps its node with no
+// line counts, and a rename with edits.
+var renameAdjacency = map[string][]string{
+	"/project/app.go":     {"/project/pricing.go", "/project/invoice.go"},
+	"/project/pricing.go": {},
+	"/project/invoice.go": {},
+}
+
+var renameStats = map[string]vcs.FileStats{
+	"/project/pricing.go": {RenamedFrom: "price_calc.go"},
+	"/project/invoice.go": {Additions: 3, Deletions: 1, RenamedFrom: "billing/bill.go"},
+}
+
+// withStyles records the styles resolved from config on the files of graph, which
+// use /project as their root.
+func withStyles(t *testing.T, graph depgraph.FileDependencyGraph, config string) depgraph.FileDependencyGraph {
+	t.Helper()
+	styles, err := rules.ParseStyles([]byte(config))
+	require.NoError(t, err)
+
+	for node, md := range graph.Meta.Files {
+		if style, ok := styles.Resolve(strings.TrimPrefix(node, "/project/")); ok {
+			md.Style = &style
+			graph.Meta.Files[node] = md
+		}
+	}
+	return graph
+}
 
 func TestNewFormatter_DOT(t *testing.T) {
 	f, err := NewFormatter("dot")
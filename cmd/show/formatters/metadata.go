@@ -0,0 +1,51 @@
+package formatters
+
+import (
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/metadata"
+)
+
+// graphMetadata completes opts.Metadata with the file count and content hash of the
+// graph in adjacency. Nodes are hashed by their IDs, relative to opts.BasePath, so
+// the hash does not depend on where the repository is checked out. It returns nil
+// when metadata is off.
+func graphMetadata(adjacency map[string][]string, opts RenderOptions) *metadata.Metadata {
+	if opts.Metadata == nil {
+		return nil
+	}
+
+	files := 0
+	nodes := make([]string, 0, len(adjacency))
+	var edges [][2]string
+	for source, deps := range adjacency {
+		if !depgraph.IsExternalNode(source) {
+			files++
+		}
+		sourceKey := dotNodeKey(source, opts.BasePath)
+		nodes = append(nodes, sourceKey)
+		for _, dep := range deps {
+			edges = append(edges, [2]string{sourceKey, dotNodeKey(dep, opts.BasePath)})
+		}
+	}
+	md := opts.Metadata.WithGraph(files, nodes, edges)
+	return &md
+}
+
+// appendMetadataComment appends the metadata of the graph in adjacency to output as
+// a block of comment lines starting with prefix, keeping whether output ends in a
+// newline. Output is returned unchanged when metadata is off.
+func appendMetadataComment(output, prefix string, adjacency map[string][]string, opts RenderOptions) string {
+	md := graphMetadata(adjacency, opts)
+	if md == nil {
+		return output
+	}
+
+	lines := md.Lines()
+	for i, line := range lines {
+		lines[i] = prefix + " " + line
+	}
+	trimmed := strings.TrimSuffix(output, "\n")
+	return trimmed + "\n" + strings.Join(lines, "\n") + output[len(trimmed):]
+}
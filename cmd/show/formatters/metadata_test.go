@@ -0,0 +1,123 @@
+package formatters
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/internal/metadata"
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var contentHashPattern = regexp.MustCompile(`sha256:[0-9a-f]{64}`)
+
+// normalizeContentHash replaces content hashes, so goldens do not change with the
+// hash encoding.
+func normalizeContentHash(output string) []byte {
+	return []byte(contentHashPattern.ReplaceAllString(output, "sha256:<hash>"))
+}
+
+func testMetadata() *metadata.Metadata {
+	md := metadata.Metadata{
+		Version: "v1.2.3",
+		Repo:    "project",
+		Commit:  metadata.WorkingTree,
+		Flags:   []string{"--file=main.go", "--format=dot", "--level=2", "--metadata=true"},
+	}
+	return &md
+}
+
+func TestDependencyGraph_ToDOT_Metadata(t *testing.T) {
+	output, err := (&dotFormatter{}).Format(linkedGraph(t), RenderOptions{BasePath: "/project", Metadata: testMetadata()})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), normalizeContentHash(output))
+}
+
+func TestDependencyGraph_ToDOT_MetadataOff(t *testing.T) {
+	output, err := (&dotFormatter{}).Format(linkedGraph(t), RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+
+	assert.NotContains(t, output, "clarity-metadata")
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_Metadata(t *testing.T) {
+	output, err := mermaidFormatter{}.Format(linkedGraph(t), RenderOptions{BasePath: "/project", Direction: DirectionLR, Metadata: testMetadata()})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), normalizeContentHash(output))
+}
+
+func TestMermaidFormatter_MetadataOff(t *testing.T) {
+	output, err := mermaidFormatter{}.Format(linkedGraph(t), RenderOptions{BasePath: "/project", Direction: DirectionLR})
+	require.NoError(t, err)
+
+	assert.NotContains(t, output, "clarity-metadata")
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestJSONFormatter_Metadata(t *testing.T) {
+	output, err := jsonFormatter{}.Format(linkedGraph(t), RenderOptions{BasePath: "/project", Metadata: testMetadata()})
+	require.NoError(t, err)
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), normalizeContentHash(output))
+}
+
+func TestJSONFormatter_MetadataOff(t *testing.T) {
+	output, err := jsonFormatter{}.Format(linkedGraph(t), RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+
+	assert.NotContains(t, output, `"meta"`)
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMetadata_ContentHashIsSharedByFormatsAndIgnoresCheckoutPath(t *testing.T) {
+	opts := RenderOptions{BasePath: "/project", Metadata: testMetadata()}
+	dot, err := (&dotFormatter{}).Format(linkedGraph(t), opts)
+	require.NoError(t, err)
+	mermaid, err := mermaidFormatter{}.Format(linkedGraph(t), opts)
+	require.NoError(t, err)
+	jsonOutput, err := jsonFormatter{}.Format(linkedGraph(t), opts)
+	require.NoError(t, err)
+
+	var doc JSONGraph
+	require.NoError(t, json.Unmarshal([]byte(jsonOutput), &doc))
+	require.NotNil(t, doc.Meta)
+	hash := doc.Meta.ContentHash
+	assert.Equal(t, hash, contentHashPattern.FindString(dot))
+	assert.Equal(t, hash, contentHashPattern.FindString(mermaid))
+
+	moved := testFileGraph(t, map[string][]string{
+		"/elsewhere/main.go":    {"/elsewhere/helpers.go", "/elsewhere/config.go"},
+		"/elsewhere/helpers.go": {},
+		"/elsewhere/config.go":  {},
+	}, nil)
+	movedOutput, err := (&dotFormatter{}).Format(moved, RenderOptions{BasePath: "/elsewhere", Metadata: testMetadata()})
+	require.NoError(t, err)
+	assert.Equal(t, hash, contentHashPattern.FindString(movedOutput))
+}
+
+func TestMetadata_ContentHashChangesWithEdges(t *testing.T) {
+	changed := testFileGraph(t, map[string][]string{
+		"/project/main.go":    {"/project/helpers.go"},
+		"/project/helpers.go": {"/project/config.go"},
+		"/project/config.go":  {},
+	}, nil)
+	opts := RenderOptions{BasePath: "/project", Metadata: testMetadata()}
+
+	before, err := (&dotFormatter{}).Format(linkedGraph(t), opts)
+	require.NoError(t, err)
+	after, err := (&dotFormatter{}).Format(changed, opts)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, contentHashPattern.FindString(before), contentHashPattern.FindString(after))
+}
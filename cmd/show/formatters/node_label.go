@@ -0,0 +1,93 @@
+package formatters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+const newFileMarker = "🪴"
+
+// renamedFromMarker prefixes the old path of a renamed file.
+const renamedFromMarker = "←"
+
+// NodeLabel is the format-agnostic content of a node label. Formatters serialize it
+// with their own line breaks and escaping so every format shows the same information.
+type NodeLabel struct {
+	// Title is the display name, prefixed with 🪴 for new files.
+	Title string
+	// Details are extra lines shown under the title, such as "+12 -3".
+	Details []string
+}
+
+// BuildNodeLabel returns the label for a file shown as name, prefixed with its
+// repository as "repo:name" when the graph spans several repositories. New files get the 🪴
+// marker whether or not they have line counts; changed files show their additions
+// and deletions, binary files show "binary", and renamed files show their old path
+// after ← in place of the 🪴 marker, since a rename is not a new file. Files whose
+// incoming edges were collapsed show how many files import them.
+func BuildNodeLabel(name string, meta depgraph.FileMetadata) NodeLabel {
+	if meta.Repo != "" {
+		name = meta.Repo + ":" + name
+	}
+	label := NodeLabel{Title: name}
+	if meta.Stats != nil {
+		label = withStats(label, name, *meta.Stats)
+	}
+	if meta.CollapsedDependents > 0 {
+		label.Details = append(label.Details, fmt.Sprintf("imported by %d files", meta.CollapsedDependents))
+	}
+	return label
+}
+
+// isNewFile reports whether meta is of a file added by the change, rather than
+// renamed or modified.
+func isNewFile(meta depgraph.FileMetadata) bool {
+	return meta.Stats != nil && isNewStats(*meta.Stats)
+}
+
+func isNewStats(stats vcs.FileStats) bool {
+	return stats.IsNew && stats.RenamedFrom == ""
+}
+
+// withStats adds the new-file marker and the line counts of stats to label.
+func withStats(label NodeLabel, name string, stats vcs.FileStats) NodeLabel {
+	if isNewStats(stats) {
+		label.Title = fmt.Sprintf("%s %s", newFileMarker, name)
+	}
+
+	switch {
+	case stats.IsBinary:
+		label.Details = append(label.Details, "binary")
+	case stats.Additions > 0 || stats.Deletions > 0:
+		var statsParts []string
+		if stats.Additions > 0 {
+			statsParts = append(statsParts, fmt.Sprintf("+%d", stats.Additions))
+		}
+		if stats.Deletions > 0 {
+			statsParts = append(statsParts, fmt.Sprintf("-%d", stats.Deletions))
+		}
+		label.Details = append(label.Details, strings.Join(statsParts, " "))
+	}
+
+	if stats.RenamedFrom != "" {
+		label.Details = append(label.Details, renamedFromMarker+" "+stats.RenamedFrom)
+	}
+	return label
+}
+
+// Lines returns the title followed by the detail lines.
+func (l NodeLabel) Lines() []string {
+	return append([]string{l.Title}, l.Details...)
+}
+
+// Join renders the label with lineBreak between lines after escaping each line.
+func (l NodeLabel) Join(lineBreak string, escape func(string) string) string {
+	lines := l.Lines()
+	for i, line := range lines {
+		lines[i] = escape(line)
+	}
+	return strings.Join(lines, lineBreak)
+}
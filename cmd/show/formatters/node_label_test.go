@@ -0,0 +1,82 @@
+package formatters
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestBuildNodeLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats *vcs.FileStats
+		want  []string
+	}{
+		{"no stats", nil, []string{"a.go"}},
+		{"new file with stats", &vcs.FileStats{IsNew: true, Additions: 12}, []string{"🪴 a.go", "+12"}},
+		{"new file without stats", &vcs.FileStats{IsNew: true}, []string{"🪴 a.go"}},
+		{"stats without new", &vcs.FileStats{Additions: 3, Deletions: 1}, []string{"a.go", "+3 -1"}},
+		{"deletions only", &vcs.FileStats{Deletions: 4}, []string{"a.go", "-4"}},
+		{"binary", &vcs.FileStats{IsBinary: true}, []string{"a.go", "binary"}},
+		{"new binary", &vcs.FileStats{IsNew: true, IsBinary: true}, []string{"🪴 a.go", "binary"}},
+		{"renamed", &vcs.FileStats{Additions: 2, RenamedFrom: "old/a.go"}, []string{"a.go", "+2", "← old/a.go"}},
+		{"pure rename", &vcs.FileStats{RenamedFrom: "old/a.go"}, []string{"a.go", "← old/a.go"}},
+		{"rename is not new", &vcs.FileStats{IsNew: true, RenamedFrom: "old/a.go"}, []string{"a.go", "← old/a.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label := BuildNodeLabel("a.go", depgraph.FileMetadata{Stats: tt.stats})
+			assert.Equal(t, tt.want, label.Lines())
+		})
+	}
+}
+
+func TestBuildNodeLabel_CollapsedDependents(t *testing.T) {
+	label := BuildNodeLabel("log.go", depgraph.FileMetadata{
+		Stats:               &vcs.FileStats{Additions: 2},
+		CollapsedDependents: 37,
+	})
+
+	assert.Equal(t, []string{"log.go", "+2", "imported by 37 files"}, label.Lines())
+}
+
+var (
+	dotLabelPattern     = regexp.MustCompile(`label=("(?:[^"\\]|\\.)*"), style=`)
+	mermaidLabelPattern = regexp.MustCompile(`\["(.*)"\]`)
+)
+
+func TestNodeLabels_SameContentInDOTAndMermaid(t *testing.T) {
+	graph := testFileGraph(t, labelCaseAdjacency, labelCaseStats)
+
+	dotOutput, err := (&dotFormatter{}).Format(graph, RenderOptions{})
+	require.NoError(t, err)
+	mermaidOutput, err := (&mermaidFormatter{}).Format(graph, RenderOptions{})
+	require.NoError(t, err)
+
+	var dotLabels []string
+	for _, match := range dotLabelPattern.FindAllStringSubmatch(dotOutput, -1) {
+		label, err := strconv.Unquote(match[1])
+		require.NoError(t, err)
+		dotLabels = append(dotLabels, label)
+	}
+
+	var mermaidLabels []string
+	for _, match := range mermaidLabelPattern.FindAllStringSubmatch(mermaidOutput, -1) {
+		label := strings.ReplaceAll(match[1], "<br/>", "\n")
+		mermaidLabels = append(mermaidLabels, strings.ReplaceAll(label, "#quot;", `"`))
+	}
+
+	sort.Strings(dotLabels)
+	sort.Strings(mermaidLabels)
+	require.Len(t, dotLabels, len(labelCaseAdjacency))
+	assert.Equal(t, dotLabels, mermaidLabels)
+}
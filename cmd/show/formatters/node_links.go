@@ -0,0 +1,102 @@
+package formatters
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// LinkCommitPlaceholder is replaced in a link template by the analyzed commit.
+	LinkCommitPlaceholder = "{commit}"
+	// LinkPathPlaceholder is replaced in a link template by the repository-relative
+	// path of the file, with forward slashes.
+	LinkPathPlaceholder = "{path}"
+)
+
+// scpLikeRemote matches the scp-like SSH remotes git accepts, such as
+// "git@github.com:org/repo.git".
+var scpLikeRemote = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]{2,}):(.+)$`)
+
+// RemoteLinkTemplate returns the link template of files on the web page of the
+// repository a remote URL names, such as
+// "https://github.com/org/repo/blob/{commit}/{path}" for "git@github.com:org/repo.git".
+func RemoteLinkTemplate(remoteURL string) (string, error) {
+	webURL, err := RemoteWebURL(remoteURL)
+	if err != nil {
+		return "", err
+	}
+	return webURL + "/blob/" + LinkCommitPlaceholder + "/" + LinkPathPlaceholder, nil
+}
+
+// RemoteWebURL normalizes an SSH or HTTPS remote URL to the web address of the
+// repository, dropping credentials, SSH ports, and the .git suffix.
+func RemoteWebURL(remoteURL string) (string, error) {
+	raw := strings.TrimSpace(remoteURL)
+	scheme, host, repoPath := "https", "", ""
+
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("remote URL %q is not hosted on the web", remoteURL)
+		}
+		switch u.Scheme {
+		case "http", "https":
+			// Web ports are kept; the address is served on them.
+			scheme, host = u.Scheme, u.Host
+		case "ssh", "git", "git+ssh", "ssh+git":
+			// SSH and git daemon ports do not serve the web pages.
+			host = u.Hostname()
+		default:
+			return "", fmt.Errorf("remote URL %q is not hosted on the web", remoteURL)
+		}
+		repoPath = u.Path
+	} else if match := scpLikeRemote.FindStringSubmatch(raw); match != nil {
+		host, repoPath = match[1], match[2]
+	} else {
+		return "", fmt.Errorf("remote URL %q is not hosted on the web", remoteURL)
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if repoPath == "" {
+		return "", fmt.Errorf("remote URL %q does not name a repository", remoteURL)
+	}
+	return scheme + "://" + host + "/" + repoPath, nil
+}
+
+// ValidateLinkTemplate reports an error when template cannot link files apart.
+func ValidateLinkTemplate(template string) error {
+	if !strings.Contains(template, LinkPathPlaceholder) {
+		return fmt.Errorf("link template %q must contain %s", template, LinkPathPlaceholder)
+	}
+	return nil
+}
+
+// NodeLinks expands template for each file under repoRoot, keyed by file path. Files
+// outside repoRoot and external package nodes get no link.
+func NodeLinks(template, commit, repoRoot string, filePaths []string) map[string]string {
+	links := make(map[string]string, len(filePaths))
+	for _, filePath := range filePaths {
+		rel, err := filepath.Rel(repoRoot, filePath)
+		if err != nil || !filepath.IsAbs(filePath) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		links[filePath] = ExpandLinkTemplate(template, commit, filepath.ToSlash(rel))
+	}
+	return links
+}
+
+// ExpandLinkTemplate replaces the placeholders of template with commit and the
+// slash-separated relPath, escaping each for use in a URL path.
+func ExpandLinkTemplate(template, commit, relPath string) string {
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.NewReplacer(
+		LinkCommitPlaceholder, url.PathEscape(commit),
+		LinkPathPlaceholder, strings.Join(segments, "/"),
+	).Replace(template)
+}
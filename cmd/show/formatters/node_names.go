@@ -3,14 +3,21 @@ package formatters
 import (
 	"path/filepath"
 	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
 )
 
 // BuildNodeNames returns stable, distinct display names for file paths.
 // Paths that share the same base name are disambiguated by increasing path suffix depth.
+// External package nodes are named by their node name, such as "go:github.com/spf13/cobra".
 func BuildNodeNames(paths []string) map[string]string {
 	names := make(map[string]string, len(paths))
 	groupedByBase := make(map[string][]string, len(paths))
 	for _, path := range paths {
+		if depgraph.IsExternalNode(path) {
+			names[path] = path
+			continue
+		}
 		base := filepath.Base(path)
 		groupedByBase[base] = append(groupedByBase[base], path)
 	}
@@ -60,3 +67,14 @@ func pathSuffix(path string, depth int) string {
 	}
 	return strings.Join(parts[len(parts)-depth:], "/")
 }
+
+// withoutExternalNodes returns the file nodes of paths, dropping external packages.
+func withoutExternalNodes(paths []string) []string {
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !depgraph.IsExternalNode(path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
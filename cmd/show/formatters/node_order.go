@@ -0,0 +1,34 @@
+package formatters
+
+import "sort"
+
+// sortedFiles returns the files of adjacency in the order every formatter writes
+// them: by path relative to basePath, then by absolute path. Rendering the same graph
+// twice therefore produces byte-identical output, which snapshot tests and CI diffs
+// of graphs rely on.
+func sortedFiles(adjacency map[string][]string, basePath string) []string {
+	files := make([]string, 0, len(adjacency))
+	for file := range adjacency {
+		files = append(files, file)
+	}
+	sortFiles(files, basePath)
+	return files
+}
+
+// sortedDependencies returns a sorted copy of deps, ordered like sortedFiles, so the
+// edges of a file are written in a stable order.
+func sortedDependencies(deps []string, basePath string) []string {
+	sorted := append([]string(nil), deps...)
+	sortFiles(sorted, basePath)
+	return sorted
+}
+
+func sortFiles(files []string, basePath string) {
+	sort.Slice(files, func(i, j int) bool {
+		a, b := dotNodeKey(files[i], basePath), dotNodeKey(files[j], basePath)
+		if a != b {
+			return a < b
+		}
+		return files[i] < files[j]
+	})
+}
@@ -0,0 +1,75 @@
+package formatters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// orderAdjacency mixes directories, a cycle, stats, and a file outside /project.
+var orderAdjacency = map[string][]string{
+	"/project/z.go":           {"/project/b/util.go", "/project/a.go", "/outside/lib.go"},
+	"/project/a.go":           {"/project/b/util.go", "/project/z.go"},
+	"/project/b/util.go":      {},
+	"/project/b/util_test.go": {"/project/b/util.go"},
+	"/outside/lib.go":         {},
+}
+
+func TestFormatters_RenderingIsByteIdentical(t *testing.T) {
+	stats := map[string]vcs.FileStats{"/project/a.go": {Additions: 2, Deletions: 1}}
+	opts := RenderOptions{BasePath: "/project", Label: "HEAD"}
+
+	for _, format := range []string{"dot", "mermaid", "json", "html"} {
+		t.Run(format, func(t *testing.T) {
+			var first string
+			for i := 0; i < 50; i++ {
+				formatter, err := NewFormatter(format)
+				require.NoError(t, err)
+				output, err := formatter.Format(testFileGraph(t, orderAdjacency, stats), opts)
+				require.NoError(t, err)
+				if i == 0 {
+					first = output
+					continue
+				}
+				require.Equal(t, first, output, "render %d differs from the first", i+1)
+			}
+		})
+	}
+}
+
+func TestDependencyGraph_ToDOT_SortsNodesByRelativePathThenDependencies(t *testing.T) {
+	formatter := dotFormatter{}
+	output, err := formatter.Format(testFileGraph(t, orderAdjacency, nil), RenderOptions{BasePath: "/project"})
+	require.NoError(t, err)
+
+	var nodes, edges []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "\"") && strings.Contains(line, " -> "):
+			edges = append(edges, line)
+		case strings.Contains(line, " [label="):
+			nodes = append(nodes, line[:strings.Index(line, " [")])
+		}
+	}
+
+	assert.Equal(t, []string{
+		`"/outside/lib.go"`,
+		`"a.go"`,
+		`"b/util.go"`,
+		`"b/util_test.go"`,
+		`"z.go"`,
+	}, nodes)
+	assert.Equal(t, []string{
+		`"a.go" -> "b/util.go";`,
+		`"a.go" -> "z.go" [color=red, style=dashed];`,
+		`"b/util_test.go" -> "b/util.go";`,
+		`"z.go" -> "/outside/lib.go";`,
+		`"z.go" -> "a.go" [color=red, style=dashed];`,
+		`"z.go" -> "b/util.go";`,
+	}, edges)
+}
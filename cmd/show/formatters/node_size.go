@@ -0,0 +1,84 @@
+package formatters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+// NodeSize selects the metric node sizes are scaled by.
+type NodeSize string
+
+const (
+	// NodeSizeNone renders every node at the default size.
+	NodeSizeNone NodeSize = ""
+	// NodeSizeLOC scales nodes by the line count of their file.
+	NodeSizeLOC NodeSize = "loc"
+)
+
+// ParseNodeSize converts a string to NodeSize.
+func ParseNodeSize(s string) (NodeSize, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return NodeSizeNone, true
+	case "loc":
+		return NodeSizeLOC, true
+	default:
+		return NodeSizeNone, false
+	}
+}
+
+// SupportedNodeSizes returns a list of all supported node size metrics.
+func SupportedNodeSizes() string {
+	return "loc"
+}
+
+const (
+	// dotDefaultWidth and dotDefaultHeight are the Graphviz default node size in inches.
+	dotDefaultWidth  = 0.75
+	dotDefaultHeight = 0.5
+	// linesPerScaleStep is how many lines grow a DOT node by its default size.
+	linesPerScaleStep = 250
+	// maxNodeScale caps how many times the default size a DOT node grows.
+	maxNodeScale = 4.0
+)
+
+// dotNodeSizeAttrs returns the width and height attributes of a file node, or "" when
+// nodes are not sized or the file's line count is unknown.
+func dotNodeSizeAttrs(opts RenderOptions, md depgraph.FileMetadata) string {
+	if opts.NodeSize != NodeSizeLOC || md.LineCount == nil {
+		return ""
+	}
+	scale := min(1+float64(*md.LineCount)/linesPerScaleStep, maxNodeScale)
+	return fmt.Sprintf("width=%.2f, height=%.2f", dotDefaultWidth*scale, dotDefaultHeight*scale)
+}
+
+// mermaidSizeTier is a Mermaid class applied to nodes within a range of line counts.
+type mermaidSizeTier struct {
+	class string
+	// maxLines is the largest line count in the tier; 0 means no upper bound.
+	maxLines int
+	fontSize string
+}
+
+// mermaidSizeTiers sizes Mermaid labels in tiers, since Mermaid has no per-node size.
+var mermaidSizeTiers = []mermaidSizeTier{
+	{class: "sizeSmall", maxLines: 149, fontSize: "12px"},
+	{class: "sizeMedium", maxLines: 499, fontSize: "16px"},
+	{class: "sizeLarge", fontSize: "22px"},
+}
+
+// mermaidSizeTierIndex returns the index in mermaidSizeTiers of a file node, or -1 when
+// nodes are not sized or the file's line count is unknown.
+func mermaidSizeTierIndex(opts RenderOptions, md depgraph.FileMetadata) int {
+	if opts.NodeSize != NodeSizeLOC || md.LineCount == nil {
+		return -1
+	}
+	for i, tier := range mermaidSizeTiers {
+		if tier.maxLines == 0 || *md.LineCount <= tier.maxLines {
+			return i
+		}
+	}
+	return len(mermaidSizeTiers) - 1
+}
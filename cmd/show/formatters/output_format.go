@@ -8,6 +8,10 @@ type OutputFormat int
 const (
 	OutputFormatDOT OutputFormat = iota
 	OutputFormatMermaid
+	OutputFormatJSON
+	OutputFormatHTML
+	OutputFormatSVG
+	OutputFormatPNG
 	endOfSupportedFormatsMarker // endOfSupportedFormatsMarker for iteration
 )
 
@@ -18,6 +22,14 @@ func (f OutputFormat) String() string {
 		return "dot"
 	case OutputFormatMermaid:
 		return "mermaid"
+	case OutputFormatJSON:
+		return "json"
+	case OutputFormatHTML:
+		return "html"
+	case OutputFormatSVG:
+		return "svg"
+	case OutputFormatPNG:
+		return "png"
 	case endOfSupportedFormatsMarker:
 		return "unknown"
 	default:
@@ -25,6 +37,35 @@ func (f OutputFormat) String() string {
 	}
 }
 
+// FileExtension returns the file name extension, with its leading dot, used when the
+// format is written to a file.
+func (f OutputFormat) FileExtension() string {
+	switch f {
+	case OutputFormatDOT:
+		return ".dot"
+	case OutputFormatMermaid:
+		return ".mmd"
+	case OutputFormatJSON:
+		return ".json"
+	case OutputFormatHTML:
+		return ".html"
+	case OutputFormatSVG:
+		return ".svg"
+	case OutputFormatPNG:
+		return ".png"
+	case endOfSupportedFormatsMarker:
+		return ".txt"
+	default:
+		return ".txt"
+	}
+}
+
+// IsImage reports whether the format is an image rendered from the DOT output. Image
+// output is written as-is, without the trailing newline added to text formats.
+func (f OutputFormat) IsImage() bool {
+	return f == OutputFormatSVG || f == OutputFormatPNG
+}
+
 // ParseOutputFormat converts a string to OutputFormat
 func ParseOutputFormat(s string) (OutputFormat, bool) {
 	switch strings.ToLower(s) {
@@ -32,16 +73,33 @@ func ParseOutputFormat(s string) (OutputFormat, bool) {
 		return OutputFormatDOT, true
 	case "mermaid":
 		return OutputFormatMermaid, true
+	case "json":
+		return OutputFormatJSON, true
+	case "html":
+		return OutputFormatHTML, true
+	case "svg":
+		return OutputFormatSVG, true
+	case "png":
+		return OutputFormatPNG, true
 	default:
 		return OutputFormatDOT, false
 	}
 }
 
+// OutputFormats returns every supported output format in declaration order.
+func OutputFormats() []OutputFormat {
+	formats := make([]OutputFormat, 0, endOfSupportedFormatsMarker)
+	for i := OutputFormat(0); i < endOfSupportedFormatsMarker; i++ {
+		formats = append(formats, i)
+	}
+	return formats
+}
+
 // SupportedFormats returns a list of all supported output format names.
 func SupportedFormats() string {
 	formats := make([]string, 0, endOfSupportedFormatsMarker)
-	for i := OutputFormat(0); i < endOfSupportedFormatsMarker; i++ {
-		formats = append(formats, i.String())
+	for _, format := range OutputFormats() {
+		formats = append(formats, format.String())
 	}
 	return strings.Join(formats, ", ")
 }
@@ -9,6 +9,10 @@ func TestOutputFormat_String(t *testing.T) {
 	}{
 		{OutputFormatDOT, "dot"},
 		{OutputFormatMermaid, "mermaid"},
+		{OutputFormatJSON, "json"},
+		{OutputFormatHTML, "html"},
+		{OutputFormatSVG, "svg"},
+		{OutputFormatPNG, "png"},
 		{endOfSupportedFormatsMarker, "unknown"},
 		{OutputFormat(99), "unknown"},
 	}
@@ -22,6 +26,29 @@ func TestOutputFormat_String(t *testing.T) {
 	}
 }
 
+func TestOutputFormat_FileExtension(t *testing.T) {
+	tests := []struct {
+		format   OutputFormat
+		expected string
+	}{
+		{OutputFormatDOT, ".dot"},
+		{OutputFormatMermaid, ".mmd"},
+		{OutputFormatJSON, ".json"},
+		{OutputFormatHTML, ".html"},
+		{OutputFormatSVG, ".svg"},
+		{OutputFormatPNG, ".png"},
+		{OutputFormat(99), ".txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.format.FileExtension(); got != tt.expected {
+				t.Errorf("OutputFormat.FileExtension() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseOutputFormat(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -30,6 +57,10 @@ func TestParseOutputFormat(t *testing.T) {
 	}{
 		{"dot", OutputFormatDOT, true},
 		{"mermaid", OutputFormatMermaid, true},
+		{"json", OutputFormatJSON, true},
+		{"html", OutputFormatHTML, true},
+		{"svg", OutputFormatSVG, true},
+		{"PNG", OutputFormatPNG, true},
 		{"invalid", OutputFormatDOT, false},
 		{"", OutputFormatDOT, false},
 		{"DOT", OutputFormatDOT, true},         // case-insensitive
@@ -48,7 +79,7 @@ func TestParseOutputFormat(t *testing.T) {
 
 func TestSupportedFormats(t *testing.T) {
 	got := SupportedFormats()
-	expected := "dot, mermaid"
+	expected := "dot, mermaid, json, html, svg, png"
 
 	if got != expected {
 		t.Errorf("SupportedFormats() = %q, want %q", got, expected)
@@ -57,7 +88,7 @@ func TestSupportedFormats(t *testing.T) {
 
 func TestSupportedFormatsCount(t *testing.T) {
 	// Verify the count matches the number of formats
-	expectedCount := 2
+	expectedCount := 6
 	if int(endOfSupportedFormatsMarker) != expectedCount {
 		t.Errorf("endOfSupportedFormatsMarker = %d, want %d", endOfSupportedFormatsMarker, expectedCount)
 	}
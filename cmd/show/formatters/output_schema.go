@@ -0,0 +1,7 @@
+package formatters
+
+// OutputSchemaVersion identifies the emitted shape of every output format: node and
+// edge syntax, attribute order, class names, whitespace. Downstream projects keep
+// golden files of this output, so any change they would notice must bump the version
+// and regenerate the compatibility corpus in tests/compat (make compat-corpus).
+const OutputSchemaVersion = 2
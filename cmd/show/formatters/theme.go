@@ -0,0 +1,279 @@
+package formatters
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in theme names, and the name of a theme read from a palette file.
+const (
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeCustom = "custom"
+)
+
+// SupportedThemes returns a list of all supported theme names.
+func SupportedThemes() string {
+	return strings.Join([]string{ThemeLight, ThemeDark, ThemeCustom}, ", ")
+}
+
+// ThemeColors are the colors one output format draws with, by role. An empty color
+// leaves the format's own default, such as Graphviz's black edges, in place.
+type ThemeColors struct {
+	// Background is the canvas color.
+	Background string
+	// Text is the color of node labels and the graph title.
+	Text string
+	// Edge is the color of ordinary dependency edges.
+	Edge string
+	// Border is the outline of file nodes.
+	Border string
+	// TestFile and TestFileBorder are the fill and outline of test files.
+	TestFile       string
+	TestFileBorder string
+	// NewFile is the fill of files added by the change; empty leaves new files to
+	// their seedling label.
+	NewFile string
+	// MajorityExt is the fill of files of the most common extension, and of every
+	// file when there is one extension.
+	MajorityExt string
+	// Pruned is the dashed outline of nodes whose subtree --prune skipped.
+	Pruned string
+	// Cycle is the outline of files and the color of edges in a dependency cycle.
+	Cycle string
+	// Suppressed, Heuristic, and ExpectActual color the edges of their kind.
+	Suppressed   string
+	Heuristic    string
+	ExpectActual string
+	// External and ExternalBorder are the fill and outline of external packages.
+	External       string
+	ExternalBorder string
+	// Manifest and ManifestBorder are the fill and outline of dependency manifests;
+	// ManifestBorder also colors the edges linking files to their manifest.
+	Manifest       string
+	ManifestBorder string
+	// Placeholder colors the node drawn for a graph without files.
+	Placeholder string
+	// ExtPalette fills the files of each other extension, assigned in order.
+	ExtPalette []string
+}
+
+// Theme is the set of colors the dot and mermaid formatters draw with. Built-in
+// themes spell each color in the idiom of the format, X11 names for Graphviz and hex
+// for Mermaid; a custom palette uses the same hex colors in both.
+type Theme struct {
+	Name    string
+	DOT     ThemeColors
+	Mermaid ThemeColors
+}
+
+// LightTheme returns the default theme: dark text on white and pastel fills.
+func LightTheme() Theme {
+	return Theme{
+		Name: ThemeLight,
+		DOT: ThemeColors{
+			TestFile:       "lightgreen",
+			MajorityExt:    "white",
+			Pruned:         "gray",
+			Cycle:          "red",
+			Suppressed:     "gray80",
+			Heuristic:      "gray",
+			ExpectActual:   "purple",
+			External:       "lightyellow",
+			ExternalBorder: "goldenrod",
+			Manifest:       "orange",
+			ManifestBorder: "darkorange3",
+			Placeholder:    "gray",
+			ExtPalette: []string{
+				"lightblue", "lightyellow", "mistyrose", "lightsalmon",
+				"lightpink", "lavender", "peachpuff", "plum", "powderblue", "khaki",
+				"palegoldenrod", "thistle",
+			},
+		},
+		Mermaid: ThemeColors{
+			Text:           "#000000",
+			Border:         "#999999",
+			TestFile:       "#90EE90",
+			TestFileBorder: "#228B22",
+			MajorityExt:    "#FFFFFF",
+			Pruned:         "#999999",
+			Cycle:          "#d62728",
+			Suppressed:     "#cccccc",
+			External:       "#FFFFE0",
+			ExternalBorder: "#DAA520",
+			Manifest:       "#FFA500",
+			ManifestBorder: "#CD6600",
+			Placeholder:    "#999999",
+		},
+	}
+}
+
+// DarkTheme returns a theme for dark backgrounds: light text on muted fills.
+func DarkTheme() Theme {
+	colors := ThemeColors{
+		Background:     "#1e1e1e",
+		Text:           "#e0e0e0",
+		Edge:           "#b0b0b0",
+		Border:         "#6e6e6e",
+		TestFile:       "#2e5d32",
+		TestFileBorder: "#81c784",
+		NewFile:        "#1a4a7a",
+		MajorityExt:    "#2d2d2d",
+		Pruned:         "#8a8a8a",
+		Cycle:          "#ef5350",
+		Suppressed:     "#4a4a4a",
+		Heuristic:      "#8a8a8a",
+		ExpectActual:   "#ba68c8",
+		External:       "#4d4220",
+		ExternalBorder: "#d4a72c",
+		Manifest:       "#6b3e10",
+		ManifestBorder: "#ff9800",
+		Placeholder:    "#9e9e9e",
+		ExtPalette: []string{
+			"#1f3a5f", "#4a4020", "#5c2b2b", "#5f3a1f",
+			"#5a2d4a", "#3b3561", "#5a4632", "#4b2d5a", "#2b4f5c", "#4f4a2b",
+			"#4d4a1f", "#4a3a4a",
+		},
+	}
+	return Theme{Name: ThemeDark, DOT: colors, Mermaid: colors}
+}
+
+// themeColorPattern accepts the colors a palette file may use, which both Graphviz
+// and Mermaid understand.
+var themeColorPattern = regexp.MustCompile(`^#(?:[0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// themeRoles maps the keys of a palette file to the colors they set.
+var themeRoles = map[string]func(*ThemeColors) *string{
+	"background":     func(c *ThemeColors) *string { return &c.Background },
+	"text":           func(c *ThemeColors) *string { return &c.Text },
+	"edge":           func(c *ThemeColors) *string { return &c.Edge },
+	"border":         func(c *ThemeColors) *string { return &c.Border },
+	"testFile":       func(c *ThemeColors) *string { return &c.TestFile },
+	"testFileBorder": func(c *ThemeColors) *string { return &c.TestFileBorder },
+	"newFile":        func(c *ThemeColors) *string { return &c.NewFile },
+	"majorityExt":    func(c *ThemeColors) *string { return &c.MajorityExt },
+	"pruned":         func(c *ThemeColors) *string { return &c.Pruned },
+	"cycle":          func(c *ThemeColors) *string { return &c.Cycle },
+	"suppressed":     func(c *ThemeColors) *string { return &c.Suppressed },
+	"heuristic":      func(c *ThemeColors) *string { return &c.Heuristic },
+	"expectActual":   func(c *ThemeColors) *string { return &c.ExpectActual },
+	"external":       func(c *ThemeColors) *string { return &c.External },
+	"externalBorder": func(c *ThemeColors) *string { return &c.ExternalBorder },
+	"manifest":       func(c *ThemeColors) *string { return &c.Manifest },
+	"manifestBorder": func(c *ThemeColors) *string { return &c.ManifestBorder },
+	"placeholder":    func(c *ThemeColors) *string { return &c.Placeholder },
+}
+
+// themePaletteKey is the palette file key of ThemeColors.ExtPalette.
+const themePaletteKey = "extPalette"
+
+// LoadTheme returns the theme named name. The custom theme reads its palette from
+// paletteFile, which the built-in themes do not take.
+func LoadTheme(name, paletteFile string) (Theme, error) {
+	switch name {
+	case "", ThemeLight, ThemeDark:
+		if paletteFile != "" {
+			return Theme{}, fmt.Errorf("--theme-file requires --theme %s", ThemeCustom)
+		}
+		if name == ThemeDark {
+			return DarkTheme(), nil
+		}
+		return LightTheme(), nil
+	case ThemeCustom:
+		if paletteFile == "" {
+			return Theme{}, fmt.Errorf("--theme %s requires --theme-file", ThemeCustom)
+		}
+		data, err := os.ReadFile(paletteFile)
+		if err != nil {
+			return Theme{}, fmt.Errorf("failed to read theme file: %w", err)
+		}
+		theme, err := ParseTheme(data)
+		if err != nil {
+			return Theme{}, fmt.Errorf("invalid theme file %s: %w", paletteFile, err)
+		}
+		return theme, nil
+	default:
+		return Theme{}, fmt.Errorf("unknown theme: %s (valid options: %s)", name, SupportedThemes())
+	}
+}
+
+// ParseTheme parses a custom palette from YAML (or JSON) data, mapping roles to
+// #rgb or #rrggbb colors:
+//
+//	background: "#1e1e1e"
+//	testFile: "#2e5d32"
+//	extPalette: ["#1f3a5f", "#4a4020"]
+//
+// Roles the palette leaves out keep their light theme colors.
+func ParseTheme(data []byte) (Theme, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Theme{}, err
+	}
+	theme := LightTheme()
+	theme.Name = ThemeCustom
+	if len(doc.Content) == 0 {
+		return theme, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return Theme{}, fmt.Errorf("line %d: expected a mapping of roles to colors", root.Line)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		if keyNode.Value == themePaletteKey {
+			var palette []string
+			if valueNode.Decode(&palette) != nil || len(palette) == 0 {
+				return Theme{}, fmt.Errorf("line %d: %s must be a non-empty list of colors", valueNode.Line, themePaletteKey)
+			}
+			for _, color := range palette {
+				if !themeColorPattern.MatchString(color) {
+					return Theme{}, fmt.Errorf("line %d: invalid %s color %q: colors must be #rgb or #rrggbb", valueNode.Line, themePaletteKey, color)
+				}
+			}
+			theme.DOT.ExtPalette, theme.Mermaid.ExtPalette = palette, palette
+			continue
+		}
+
+		role, ok := themeRoles[keyNode.Value]
+		if !ok {
+			return Theme{}, fmt.Errorf("line %d: unknown role %q (valid roles: %s)", keyNode.Line, keyNode.Value, supportedThemeRoles())
+		}
+		if valueNode.Kind != yaml.ScalarNode || !themeColorPattern.MatchString(valueNode.Value) {
+			return Theme{}, fmt.Errorf("line %d: invalid %s %q: colors must be #rgb or #rrggbb", valueNode.Line, keyNode.Value, valueNode.Value)
+		}
+		*role(&theme.DOT), *role(&theme.Mermaid) = valueNode.Value, valueNode.Value
+	}
+	return theme, nil
+}
+
+func supportedThemeRoles() string {
+	roles := []string{themePaletteKey}
+	for role := range themeRoles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ", ")
+}
+
+// theme returns the theme to render with; the zero Theme is the light theme.
+func (o RenderOptions) theme() Theme {
+	if o.Theme.Name == "" {
+		return LightTheme()
+	}
+	return o.Theme
+}
+
+// dotColor returns color as a DOT attribute value, quoting hex colors.
+func dotColor(color string) string {
+	if strings.HasPrefix(color, "#") {
+		return fmt.Sprintf("%q", color)
+	}
+	return color
+}
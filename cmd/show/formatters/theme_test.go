@@ -0,0 +1,119 @@
+package formatters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestParseTheme_OverridesRolesAndKeepsLightDefaults(t *testing.T) {
+	theme, err := ParseTheme([]byte(`background: "#101010"
+testFile: "#0a0"
+extPalette: ["#111111", "#222222"]
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, ThemeCustom, theme.Name)
+	assert.Equal(t, "#101010", theme.DOT.Background)
+	assert.Equal(t, "#0a0", theme.Mermaid.TestFile)
+	assert.Equal(t, []string{"#111111", "#222222"}, theme.DOT.ExtPalette)
+	assert.Equal(t, LightTheme().DOT.Cycle, theme.DOT.Cycle)
+	assert.Equal(t, LightTheme().Mermaid.Cycle, theme.Mermaid.Cycle)
+}
+
+func TestParseTheme_AcceptsJSON(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"edge": "#abcdef", "newFile": "#123456"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "#abcdef", theme.Mermaid.Edge)
+	assert.Equal(t, "#123456", theme.DOT.NewFile)
+}
+
+func TestParseTheme_RejectsInvalidPalettes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"unknown role", "background: \"#000\"\nfill: \"#fff\"\n", `line 2: unknown role "fill"`},
+		{"named color", "testFile: lightgreen\n", `line 1: invalid testFile "lightgreen": colors must be #rgb or #rrggbb`},
+		{"list for a role", "edge: [\"#000\"]\n", `line 1: invalid edge`},
+		{"empty palette", "extPalette: []\n", "line 1: extPalette must be a non-empty list of colors"},
+		{"invalid palette color", "extPalette: [\"#000\", \"blue\"]\n", `line 1: invalid extPalette color "blue"`},
+		{"not a mapping", "- \"#000\"\n", "line 1: expected a mapping of roles to colors"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme("", "")
+	require.NoError(t, err)
+	assert.Equal(t, LightTheme(), theme)
+
+	theme, err = LoadTheme(ThemeDark, "")
+	require.NoError(t, err)
+	assert.Equal(t, DarkTheme(), theme)
+
+	paletteFile := filepath.Join(t.TempDir(), "palette.yml")
+	require.NoError(t, os.WriteFile(paletteFile, []byte("testFile: \"#zzz\"\n"), 0o644))
+	_, err = LoadTheme(ThemeCustom, paletteFile)
+	require.ErrorContains(t, err, "invalid theme file "+paletteFile+": line 1: invalid testFile")
+
+	_, err = LoadTheme(ThemeCustom, "")
+	require.EqualError(t, err, "--theme custom requires --theme-file")
+
+	_, err = LoadTheme(ThemeDark, paletteFile)
+	require.EqualError(t, err, "--theme-file requires --theme custom")
+
+	_, err = LoadTheme("solarized", "")
+	require.EqualError(t, err, "unknown theme: solarized (valid options: light, dark, custom)")
+}
+
+// themeTestGraph has a test file, a new file, a second extension, a cycle, and an
+// external package, so most roles of a theme are drawn.
+var themeTestGraph = map[string][]string{
+	"/project/main.go":          {"/project/util.go", "go:github.com/spf13/cobra"},
+	"/project/util.go":          {"/project/main.go"},
+	"/project/main_test.go":     {"/project/main.go"},
+	"/project/new.go":           {"/project/util.go"},
+	"/project/web/app.ts":       {},
+	"go:github.com/spf13/cobra": {},
+}
+
+var themeTestStats = map[string]vcs.FileStats{
+	"/project/new.go": {Additions: 4, IsNew: true},
+}
+
+func TestDependencyGraph_ToDOT_DarkTheme(t *testing.T) {
+	graph := testFileGraph(t, themeTestGraph, themeTestStats)
+
+	formatter := dotFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "repo • 5 files", Theme: DarkTheme()})
+	require.NoError(t, err)
+
+	g := testhelpers.DotGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestMermaidFormatter_DarkTheme(t *testing.T) {
+	graph := testFileGraphMermaid(t, themeTestGraph, themeTestStats)
+
+	formatter := mermaidFormatter{}
+	output, err := formatter.Format(graph, RenderOptions{Label: "repo • 5 files", Theme: DarkTheme()})
+	require.NoError(t, err)
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
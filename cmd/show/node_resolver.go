@@ -0,0 +1,201 @@
+package show
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+)
+
+const maxNodeSuggestions = 3
+
+// stdinIsTerminal reports whether in is an interactive terminal.
+var stdinIsTerminal = func(in io.Reader) bool {
+	file, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// nodeResolver maps user-supplied paths to graph nodes. When a literal path is not in
+// the graph, it falls back to matching the path as a suffix of the (already filtered)
+// graph nodes, so a bare filename works from any directory.
+type nodeResolver struct {
+	cmd          *cobra.Command
+	pathResolver PathResolver
+	repoPath     string
+	interactive  bool
+	nodes        []string
+	// aliases maps symlinks to the paths they point at, so a node can also be named
+	// through a symlink.
+	aliases map[string]string
+}
+
+func newNodeResolver(cmd *cobra.Command, opts *graphOptions, pathResolver PathResolver, graph depgraph.DependencyGraph) (nodeResolver, error) {
+	nodes, err := graphFiles(graph)
+	if err != nil {
+		return nodeResolver{}, err
+	}
+	sort.Strings(nodes)
+	return nodeResolver{
+		cmd:          cmd,
+		pathResolver: pathResolver,
+		repoPath:     opts.repoPath,
+		interactive:  opts.interactive,
+		nodes:        nodes,
+		aliases:      opts.pathAliases,
+	}, nil
+}
+
+// Resolve returns the graph node for raw or an error describing why it is unusable.
+func (r nodeResolver) Resolve(raw string) (string, error) {
+	if absPath, err := r.pathResolver.Resolve(RawPath(raw)); err == nil {
+		if node := moduleapi.CanonicalPath(r.aliases, absPath.String()); r.containsNode(node) {
+			return node, nil
+		}
+	}
+
+	candidates := r.suffixMatches(raw)
+	switch len(candidates) {
+	case 0:
+		return "", r.notFoundError(raw)
+	case 1:
+		fmt.Fprintf(r.cmd.ErrOrStderr(), "Note: resolved %s to %s\n", raw, r.displayPath(candidates[0]))
+		return candidates[0], nil
+	default:
+		if r.interactive && stdinIsTerminal(r.cmd.InOrStdin()) {
+			return r.prompt(raw, candidates)
+		}
+		return "", r.ambiguousError(raw, candidates)
+	}
+}
+
+func (r nodeResolver) containsNode(path string) bool {
+	i := sort.SearchStrings(r.nodes, path)
+	return i < len(r.nodes) && r.nodes[i] == path
+}
+
+func (r nodeResolver) suffixMatches(raw string) []string {
+	suffix := filepath.Clean(filepath.FromSlash(raw))
+	suffix = strings.TrimPrefix(suffix, "."+string(filepath.Separator))
+	if suffix == "." || suffix == "" || filepath.IsAbs(suffix) || strings.HasPrefix(suffix, "..") {
+		return nil
+	}
+
+	var matches []string
+	for _, node := range r.nodes {
+		if node == suffix || strings.HasSuffix(node, string(filepath.Separator)+suffix) {
+			matches = append(matches, node)
+		}
+	}
+	return matches
+}
+
+func (r nodeResolver) prompt(raw string, candidates []string) (string, error) {
+	out := r.cmd.ErrOrStderr()
+	fmt.Fprintf(out, "%s matches %d files:\n", raw, len(candidates))
+	for i, candidate := range candidates {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, r.displayPath(candidate))
+	}
+	fmt.Fprintf(out, "Select a file [1-%d]: ", len(candidates))
+
+	line, err := bufio.NewReader(r.cmd.InOrStdin()).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no file selected for %s", raw)
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return "", fmt.Errorf("invalid selection %q for %s", strings.TrimSpace(line), raw)
+	}
+	return candidates[choice-1], nil
+}
+
+func (r nodeResolver) ambiguousError(raw string, candidates []string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s matches %d files in graph; use one of:", raw, len(candidates))
+	for _, candidate := range candidates {
+		fmt.Fprintf(&sb, "\n  %s", r.displayPath(candidate))
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+func (r nodeResolver) notFoundError(raw string) error {
+	suggestions := r.suggestions(raw)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("file not found in graph: %s", raw)
+	}
+	return fmt.Errorf("file not found in graph: %s (did you mean %s?)", raw, strings.Join(suggestions, ", "))
+}
+
+// suggestions returns up to maxNodeSuggestions nodes whose base name is close to raw's.
+func (r nodeResolver) suggestions(raw string) []string {
+	base := strings.ToLower(filepath.Base(filepath.FromSlash(raw)))
+	maxDistance := len(base) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	type scored struct {
+		path     string
+		distance int
+	}
+	var candidates []scored
+	for _, node := range r.nodes {
+		distance := levenshtein(base, strings.ToLower(filepath.Base(node)))
+		if distance <= maxDistance {
+			candidates = append(candidates, scored{path: node, distance: distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var result []string
+	for i := 0; i < len(candidates) && i < maxNodeSuggestions; i++ {
+		result = append(result, r.displayPath(candidates[i].path))
+	}
+	return result
+}
+
+func (r nodeResolver) displayPath(path string) string {
+	rel, err := filepath.Rel(r.repoPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
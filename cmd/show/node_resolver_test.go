@@ -0,0 +1,170 @@
+package show
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeNodeResolverRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"lib/main.dart":                       "import 'services/user_service.dart';\nimport 'models/user.dart';\n",
+		"lib/services/user_service.dart":      "import '../models/user.dart';\n",
+		"lib/models/user.dart":                "class User {}\n",
+		"lib/admin/models/user.dart":          "class AdminUser {}\n",
+		"lib/admin/services/admin_panel.dart": "import '../models/user.dart';\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func runShow(t *testing.T, stdin io.Reader, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	if stdin != nil {
+		cmd.SetIn(stdin)
+	}
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestGraphFile_BareFilename_UniqueMatchResolves(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-p", "user_service.dart", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(stderr, "Note: resolved user_service.dart to lib/services/user_service.dart") {
+		t.Fatalf("expected resolution note, got stderr:\n%s", stderr)
+	}
+	if !strings.Contains(output, `"lib/services/user_service.dart" -> "lib/models/user.dart"`) {
+		t.Fatalf("expected user_service.dart dependencies, got:\n%s", output)
+	}
+}
+
+func TestGraphFile_BareFilename_AmbiguousMatchListsCandidates(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-p", "user.dart", "-f", "dot")
+	if err == nil {
+		t.Fatal("expected ambiguity error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{"user.dart matches 2 files", "lib/admin/models/user.dart", "lib/models/user.dart"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected error to contain %q, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestGraphFile_PartialPath_DisambiguatesBySuffix(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-p", "admin/models/user.dart", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"lib/admin/models/user.dart"`) {
+		t.Fatalf("expected admin user.dart node, got:\n%s", output)
+	}
+}
+
+func TestGraphFile_Interactive_PromptsForChoice(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+	restore := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return true }
+	t.Cleanup(func() { stdinIsTerminal = restore })
+
+	output, stderr, err := runShow(t, strings.NewReader("2\n"), "-r", repoDir, "-p", "user.dart", "--interactive", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(stderr, "1) lib/admin/models/user.dart") || !strings.Contains(stderr, "2) lib/models/user.dart") {
+		t.Fatalf("expected numbered prompt, got stderr:\n%s", stderr)
+	}
+	if strings.Contains(output, `"lib/admin/models/user.dart"`) {
+		t.Fatalf("expected second candidate to be selected, got:\n%s", output)
+	}
+}
+
+func TestGraphBetween_BareFilenames_Resolve(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-w", "main.dart,user_service.dart", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"lib/main.dart" -> "lib/services/user_service.dart"`) {
+		t.Fatalf("expected path between main.dart and user_service.dart, got:\n%s", output)
+	}
+}
+
+func TestGraphBetween_PatternSelectsAllMatchingFiles(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-w", "lib/admin/**", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"lib/admin/services/admin_panel.dart" -> "lib/admin/models/user.dart"`) {
+		t.Fatalf("expected path between the files under lib/admin, got:\n%s", output)
+	}
+	if strings.Contains(output, "main.dart") {
+		t.Fatalf("expected files outside lib/admin to be dropped, got:\n%s", output)
+	}
+}
+
+func TestGraphBetween_PatternWithoutMatches_ReturnsError(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-w", "main.dart,**/*.kt", "-f", "dot")
+	if err == nil || !strings.Contains(err.Error(), "no files in graph match --between pattern **/*.kt") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
+
+func TestGraphBetween_MalformedPattern_ReportsPosition(t *testing.T) {
+	_, _, err := runShow(t, nil, "-w", "main.dart,lib/[a-", "-f", "dot")
+	if err == nil {
+		t.Fatal("expected malformed pattern error")
+	}
+	want := `--between: invalid pattern "lib/[a-" at offset 4: unterminated character class`
+	if err.Error() != want {
+		t.Fatalf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestGraphFile_NoMatch_SuggestsCloseNames(t *testing.T) {
+	repoDir := writeNodeResolverRepo(t)
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-p", "user_servce.dart", "-f", "dot")
+	if err == nil {
+		t.Fatal("expected not found error")
+	}
+
+	want := "file not found in graph: user_servce.dart (did you mean lib/services/user_service.dart?)"
+	if err.Error() != want {
+		t.Fatalf("error = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,55 @@
+package show
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteOutputFile writes data to filePath, creating its parent directories. The data
+// is written to a temporary file in the same directory and renamed into place, so
+// readers never see a partly written file. An existing file is only replaced when
+// force is set.
+func WriteOutputFile(filePath string, data []byte, force bool) (err error) {
+	if err := checkOutputFileWritable(filePath, force); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// checkOutputFileWritable fails when filePath exists and force is not set. Other
+// problems with the path are left for the write to report.
+func checkOutputFileWritable(filePath string, force bool) error {
+	if force {
+		return nil
+	}
+	if _, err := os.Lstat(filePath); err == nil {
+		return fmt.Errorf("%s already exists (pass --force to overwrite)", filePath)
+	}
+	return nil
+}
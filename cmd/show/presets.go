@@ -0,0 +1,146 @@
+package show
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// projectPreset describes default exclusions for one project type. A project root is
+// any directory containing one of the marker files; excludes are relative to that root.
+type projectPreset struct {
+	name     string
+	markers  []string
+	excludes []string
+}
+
+// projectPresets is the table of known project types. Add a row to support a new one.
+var projectPresets = []projectPreset{
+	{name: "flutter", markers: []string{"pubspec.yaml"}, excludes: []string{".dart_tool", "build"}},
+	{name: "android", markers: []string{"settings.gradle", "settings.gradle.kts", "gradlew"}, excludes: []string{".gradle", "build", "app/build"}},
+	{name: "node", markers: []string{"package.json"}, excludes: []string{"node_modules", "dist", "coverage"}},
+}
+
+// presetExclusion is a directory excluded by a detected preset.
+type presetExclusion struct {
+	preset string
+	dir    string
+}
+
+// presetResult summarizes the effect of applying presets to a file list.
+type presetResult struct {
+	presets       []string
+	excludedFiles int
+}
+
+// applyProjectPresets removes build outputs of every project detected between repoPath and
+// the supplied files. Files inside an explicitly included path are always kept, even when
+// that path lies within a preset exclusion.
+func applyProjectPresets(repoPath string, filePaths []string, explicitIncludes []string, contentReader vcs.ContentReader) ([]string, presetResult) {
+	exclusions := detectPresetExclusions(repoPath, filePaths, contentReader)
+	if len(exclusions) == 0 {
+		return filePaths, presetResult{}
+	}
+
+	usedPresets := make(map[string]bool)
+	kept := make([]string, 0, len(filePaths))
+	excluded := 0
+	for _, filePath := range filePaths {
+		exclusion, ok := matchPresetExclusion(filePath, exclusions)
+		if !ok || isExplicitlyIncluded(filePath, exclusion.dir, explicitIncludes) {
+			kept = append(kept, filePath)
+			continue
+		}
+		usedPresets[exclusion.preset] = true
+		excluded++
+	}
+
+	presets := make([]string, 0, len(usedPresets))
+	for preset := range usedPresets {
+		presets = append(presets, preset)
+	}
+	sort.Strings(presets)
+
+	return kept, presetResult{presets: presets, excludedFiles: excluded}
+}
+
+// detectPresetExclusions finds project roots among repoPath and the ancestors of filePaths
+// (up to repoPath), using marker files in the analyzed tree or in the file list itself.
+func detectPresetExclusions(repoPath string, filePaths []string, contentReader vcs.ContentReader) []presetExclusion {
+	suppliedNames := make(map[string]bool, len(filePaths))
+	candidateDirs := map[string]bool{filepath.Clean(repoPath): true}
+	for _, filePath := range filePaths {
+		suppliedNames[filePath] = true
+		for dir := filepath.Dir(filePath); isWithinDir(dir, repoPath); dir = filepath.Dir(dir) {
+			if candidateDirs[dir] {
+				break
+			}
+			candidateDirs[dir] = true
+		}
+	}
+
+	dirs := make([]string, 0, len(candidateDirs))
+	for dir := range candidateDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var exclusions []presetExclusion
+	for _, dir := range dirs {
+		for _, preset := range projectPresets {
+			if !hasPresetMarker(dir, preset.markers, suppliedNames, contentReader) {
+				continue
+			}
+			for _, exclude := range preset.excludes {
+				exclusions = append(exclusions, presetExclusion{
+					preset: preset.name,
+					dir:    filepath.Join(dir, filepath.FromSlash(exclude)),
+				})
+			}
+		}
+	}
+	return exclusions
+}
+
+func hasPresetMarker(dir string, markers []string, suppliedFiles map[string]bool, contentReader vcs.ContentReader) bool {
+	for _, marker := range markers {
+		markerPath := filepath.Join(dir, marker)
+		if suppliedFiles[markerPath] {
+			return true
+		}
+		if contentReader.Exists(markerPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPresetExclusion(filePath string, exclusions []presetExclusion) (presetExclusion, bool) {
+	for _, exclusion := range exclusions {
+		if isWithinDir(filePath, exclusion.dir) && filePath != exclusion.dir {
+			return exclusion, true
+		}
+	}
+	return presetExclusion{}, false
+}
+
+// isExplicitlyIncluded reports whether filePath was reached through an include path at or
+// below the excluded directory, meaning the user asked for that location by name.
+func isExplicitlyIncluded(filePath, excludedDir string, explicitIncludes []string) bool {
+	for _, include := range explicitIncludes {
+		if isWithinDir(include, excludedDir) && isWithinDir(filePath, include) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,158 @@
+package show
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func writePresetFiles(t *testing.T, root string, names ...string) []string {
+	t.Helper()
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte("\n"), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func relPaths(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatalf("filepath.Rel() error = %v", err)
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result
+}
+
+func TestApplyProjectPresets_DetectsFlutterProject(t *testing.T) {
+	root := t.TempDir()
+	files := writePresetFiles(t, root,
+		"pubspec.yaml",
+		"lib/main.dart",
+		".dart_tool/package_config.json",
+		"build/app/intermediates/main.dart",
+	)
+
+	kept, result := applyProjectPresets(root, files, nil, vcs.FilesystemContentReader())
+
+	if got := strings.Join(relPaths(t, root, kept), ","); got != "pubspec.yaml,lib/main.dart" {
+		t.Fatalf("kept = %s, want pubspec.yaml,lib/main.dart", got)
+	}
+	if strings.Join(result.presets, ",") != "flutter" || result.excludedFiles != 2 {
+		t.Fatalf("result = %+v, want flutter preset excluding 2 files", result)
+	}
+}
+
+func TestApplyProjectPresets_ComposesPresetsInMonorepo(t *testing.T) {
+	root := t.TempDir()
+	files := writePresetFiles(t, root,
+		"mobile/pubspec.yaml",
+		"mobile/lib/main.dart",
+		"mobile/build/generated.dart",
+		"android/settings.gradle",
+		"android/app/src/Main.kt",
+		"android/app/build/Generated.kt",
+		"web/package.json",
+		"web/src/index.ts",
+		"web/dist/index.js",
+		"web/node_modules/lib/index.js",
+		"tools/build/script.ts",
+	)
+
+	kept, result := applyProjectPresets(root, files, nil, vcs.FilesystemContentReader())
+
+	want := "mobile/pubspec.yaml,mobile/lib/main.dart,android/settings.gradle,android/app/src/Main.kt,web/package.json,web/src/index.ts,tools/build/script.ts"
+	if got := strings.Join(relPaths(t, root, kept), ","); got != want {
+		t.Fatalf("kept = %s, want %s", got, want)
+	}
+	if got := strings.Join(result.presets, ","); got != "android,flutter,node" {
+		t.Fatalf("presets = %s, want android,flutter,node", got)
+	}
+}
+
+func TestApplyProjectPresets_NeverExcludesExplicitIncludes(t *testing.T) {
+	root := t.TempDir()
+	files := writePresetFiles(t, root,
+		"package.json",
+		"src/index.ts",
+		"dist/bundle.js",
+		"coverage/report.js",
+	)
+
+	kept, _ := applyProjectPresets(root, files, []string{filepath.Join(root, "dist")}, vcs.FilesystemContentReader())
+
+	if got := strings.Join(relPaths(t, root, kept), ","); got != "package.json,src/index.ts,dist/bundle.js" {
+		t.Fatalf("kept = %s, want dist/bundle.js to survive explicit -i", got)
+	}
+}
+
+func TestGraphInput_Preset_LogsAndCanBeDisabled(t *testing.T) {
+	repoDir := t.TempDir()
+	writePresetFiles(t, repoDir, "package.json", "src/index.ts", "dist/index.js")
+
+	run := func(extraArgs ...string) (string, string) {
+		t.Helper()
+		cmd := NewCommand()
+		cmd.SetArgs(append([]string{"-r", repoDir, "-i", ".", "-f", "dot"}, extraArgs...))
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute() error = %v", err)
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	output, stderr := run()
+	if strings.Contains(output, "dist/index.js") {
+		t.Fatalf("expected node preset to exclude dist/, got:\n%s", output)
+	}
+	if !strings.Contains(stderr, "Applied node preset: excluded 1 build output file(s)") {
+		t.Fatalf("expected preset log line, got:\n%s", stderr)
+	}
+
+	output, stderr = run("--no-preset")
+	if !strings.Contains(output, "dist/index.js") {
+		t.Fatalf("expected --no-preset to keep dist/, got:\n%s", output)
+	}
+	if strings.Contains(stderr, "preset") {
+		t.Fatalf("expected no preset log line with --no-preset, got:\n%s", stderr)
+	}
+}
+
+func TestGraphCommit_PresetMarkerOnlyInWorkingTree_IsIgnored(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writePresetFiles(t, repoDir, "src/index.js", "dist/index.js")
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add sources")
+
+	// package.json exists only in the working tree, so the commit is not a node project.
+	writePresetFiles(t, repoDir, "package.json")
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-i", ".", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "dist/index.js") {
+		t.Fatalf("expected dist/ to be kept for a commit without package.json, got:\n%s", output)
+	}
+	if strings.Contains(stderr, "preset") {
+		t.Fatalf("expected no preset log line, got:\n%s", stderr)
+	}
+}
@@ -0,0 +1,59 @@
+package show
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+// newCommitContentReader creates the reader used to analyze a commit. Tests replace it
+// to observe that readers are released.
+var newCommitContentReader = git.GitCommitContentReaderContext
+
+// runResources collects the close functions of resources acquired during one run, such
+// as content readers backed by git processes, so every return path releases them.
+type runResources struct {
+	closers []namedCloser
+}
+
+type namedCloser struct {
+	name  string
+	close func() error
+}
+
+// add registers close to run when the run ends.
+func (r *runResources) add(name string, close func() error) {
+	r.closers = append(r.closers, namedCloser{name: name, close: close})
+}
+
+// track registers resource when it holds something that must be closed.
+func (r *runResources) track(name string, resource any) {
+	if closer, ok := resource.(io.Closer); ok {
+		r.add(name, closer.Close)
+	}
+}
+
+// closeAll closes every registered resource, most recent first, and reports all
+// failures together. It is safe to call more than once.
+func (r *runResources) closeAll() error {
+	var errs []error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		closer := r.closers[i]
+		if err := closer.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", closer.name, err))
+		}
+	}
+	r.closers = nil
+	return errors.Join(errs...)
+}
+
+// openCommitContentReader returns a reader for commit that is released when the run ends.
+func (r *runResources) openCommitContentReader(ctx context.Context, repoPath, commit string) vcs.ContentReader {
+	contentReader := newCommitContentReader(ctx, repoPath, commit)
+	r.track("content reader for "+commit, contentReader)
+	return contentReader
+}
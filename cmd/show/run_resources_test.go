@@ -0,0 +1,106 @@
+package show
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestRunResources_CloseAllClosesInReverseOrder(t *testing.T) {
+	var closed []string
+	resources := &runResources{}
+	resources.add("first", func() error { closed = append(closed, "first"); return nil })
+	resources.add("second", func() error { closed = append(closed, "second"); return errors.New("boom") })
+	resources.add("third", func() error { closed = append(closed, "third"); return nil })
+
+	err := resources.closeAll()
+
+	if strings.Join(closed, ",") != "third,second,first" {
+		t.Fatalf("closed = %v, want third,second,first", closed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failed to close second: boom") {
+		t.Fatalf("expected close error for second, got %v", err)
+	}
+	if err := resources.closeAll(); err != nil || len(closed) != 3 {
+		t.Fatalf("expected second closeAll to do nothing, got err=%v closed=%v", err, closed)
+	}
+}
+
+func TestRunResources_TrackIgnoresResourcesWithoutClose(t *testing.T) {
+	resources := &runResources{}
+	resources.track("filesystem reader", vcs.FilesystemContentReader())
+
+	if len(resources.closers) != 0 {
+		t.Fatalf("expected no closers, got %d", len(resources.closers))
+	}
+}
+
+// closingContentReader records whether the run released it.
+type closingContentReader struct {
+	vcs.ContentReader
+	closed *int
+}
+
+func (r closingContentReader) Close() error {
+	*r.closed++
+	return nil
+}
+
+func useClosingContentReaders(t *testing.T) (opened, closed *int) {
+	t.Helper()
+	opened, closed = new(int), new(int)
+	previous := newCommitContentReader
+	newCommitContentReader = func(ctx context.Context, repoPath, commitID string) vcs.ContentReader {
+		*opened++
+		return closingContentReader{ContentReader: previous(ctx, repoPath, commitID), closed: closed}
+	}
+	t.Cleanup(func() {
+		newCommitContentReader = previous
+	})
+	return opened, closed
+}
+
+func TestGraphCommit_OutputError_ClosesContentReaders(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	opened, closed := useClosingContentReaders(t)
+	// A regular file where the parent directory should be makes the write fail.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	outFile := filepath.Join(blocker, "graph.dot")
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot", "-o", outFile)
+
+	if err == nil || !strings.Contains(err.Error(), "failed to write output file") {
+		t.Fatalf("expected output write error, got %v", err)
+	}
+	if *opened == 0 {
+		t.Fatal("expected the run to open a commit content reader")
+	}
+	if *closed != *opened {
+		t.Fatalf("closed %d of %d content readers", *closed, *opened)
+	}
+	if _, statErr := os.Stat(outFile); statErr == nil {
+		t.Fatal("expected no output file")
+	}
+}
+
+func TestGraphCommit_Success_ClosesContentReaders(t *testing.T) {
+	repoDir := writeThreeComponentRepo(t)
+	opened, closed := useClosingContentReaders(t)
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot")
+
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if *opened == 0 || *closed != *opened {
+		t.Fatalf("closed %d of %d content readers", *closed, *opened)
+	}
+}
@@ -0,0 +1,141 @@
+package show
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/analysis"
+	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// Selection holds the flags that decide which files show analyzes, for the commands
+// that analyze the same files for the same flags, such as files, stats, neighbors,
+// and tui.
+type Selection struct {
+	opts *graphOptions
+}
+
+// AddSelection registers show's file selection flags on cmd and returns the
+// selection they fill in.
+func AddSelection(cmd *cobra.Command) *Selection {
+	opts := &graphOptions{
+		direction:  formatters.DefaultDirection.StringLower(),
+		depthLevel: 1,
+		scope:      scopeDownstream,
+	}
+	addSelectionFlags(cmd, opts)
+	return &Selection{opts: opts}
+}
+
+// AddParseFlags registers --no-cache, --parallelism, and --lenient, which control how
+// the selected files are parsed.
+func (s *Selection) AddParseFlags(cmd *cobra.Command) {
+	addParseFlags(cmd, s.opts)
+}
+
+// CommitRange returns the --commit value.
+func (s *Selection) CommitRange() string {
+	return s.opts.commitID
+}
+
+// TargetFile returns the --file value.
+func (s *Selection) TargetFile() string {
+	return s.opts.targetFile
+}
+
+// Between returns the --between values.
+func (s *Selection) Between() []string {
+	return s.opts.betweenFiles
+}
+
+// Collect validates the flags and collects the files they select, as show does before
+// building its graph. Close the result to release the readers it opened.
+func (s *Selection) Collect(cmd *cobra.Command) (*SelectedFiles, error) {
+	if err := validateGraphOptions(s.opts); err != nil {
+		return nil, err
+	}
+	resources := &runResources{}
+	selection, err := collectFiles(cmd, s.opts, resources)
+	if err != nil {
+		_ = resources.closeAll()
+		return nil, err
+	}
+	return &SelectedFiles{
+		RepoPath:      s.opts.repoPath,
+		Files:         selection.filePaths,
+		ContentReader: selection.contentReader,
+		Clean:         selection.clean,
+		Reason:        selection.reason,
+		opts:          s.opts,
+		selection:     selection,
+		resources:     resources,
+	}, nil
+}
+
+// SelectedFiles are the files a Selection collected.
+type SelectedFiles struct {
+	// RepoPath is the root of the repository the files were selected from.
+	RepoPath string
+	// Files lists the absolute paths of the selected files.
+	Files []string
+	// ContentReader reads the files from the analyzed commit, or from the working tree.
+	ContentReader vcs.ContentReader
+	// Clean reports that there are no uncommitted changes to analyze, so Files is empty.
+	Clean bool
+	// Reason says why the files were selected. Uncommitted files the flags select as
+	// ReasonChanged may also be untracked.
+	Reason SelectionReason
+
+	opts      *graphOptions
+	selection fileSelection
+	resources *runResources
+}
+
+// Close releases the readers opened while collecting the files.
+func (f *SelectedFiles) Close() error {
+	return f.resources.closeAll()
+}
+
+// Analyzer returns an analyzer that builds the graph of the selected files as show
+// does, parsing them as the parse flags ask. It reads file statistics unless the
+// caller sets SkipStats.
+func (f *SelectedFiles) Analyzer(cmd *cobra.Command) analysis.Analyzer {
+	return analysis.Analyzer{
+		RepoPath:       f.opts.repoPath,
+		CommitRange:    f.opts.commitID,
+		ExplicitPaths:  append([]string{}, f.Files...),
+		Uncommitted:    &f.opts.uncommittedOpts,
+		ExtraRepoPaths: f.opts.extraRepoRoots,
+		GoModules:      f.opts.goModules,
+		PathAliases:    f.opts.pathAliases,
+		ContentReader:  f.ContentReader,
+		Cache:          parseCache(f.opts),
+		Parallelism:    f.opts.parallelism,
+		Progress:       cliconfig.Progress(cmd),
+		Lenient:        f.opts.lenient,
+	}
+}
+
+// ResolveFile returns the node of graph that raw names, resolving it the way show
+// resolves --file: as a path, through symlinks, or as a unique suffix of a node.
+func (f *SelectedFiles) ResolveFile(cmd *cobra.Command, graph depgraph.DependencyGraph, raw string) (string, error) {
+	resolver, err := newNodeResolver(cmd, f.opts, f.selection.pathResolver, graph)
+	if err != nil {
+		return "", err
+	}
+	return resolver.Resolve(raw)
+}
+
+// FileStats reads the addition and deletion counts of the analyzed commit, range, or
+// working tree. Failures are reported as a warning and yield no stats.
+func (f *SelectedFiles) FileStats(cmd *cobra.Command) map[string]vcs.FileStats {
+	return loadFileStats(cmd, f.opts, f.selection.fromCommit, f.selection.toCommit, f.selection.isCommitRange)
+}
+
+// RelativePath returns filePath relative to RepoPath using forward slashes, or the
+// slash-separated absolute path when it lies outside the repository.
+func (f *SelectedFiles) RelativePath(filePath string) string {
+	return repoRelativeSlashPath(f.RepoPath, filePath)
+}
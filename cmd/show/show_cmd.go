@@ -1,7 +1,9 @@
 package show
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
@@ -9,11 +11,18 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/LegacyCodeHQ/clarity/analysis"
 	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
 	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/depgraph/rules"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 	"github.com/LegacyCodeHQ/clarity/internal/mcplogdlog"
+	"github.com/LegacyCodeHQ/clarity/internal/metadata"
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 	"github.com/LegacyCodeHQ/clarity/vcs/git"
 
@@ -21,32 +30,123 @@ import (
 )
 
 type graphOptions struct {
-	outputFormat string
-	repoPath     string
-	commitID     string
-	generateURL  bool
-	direction    string
-	allowOutside bool
-	includeExt   string
-	includeExts  []string
-	excludeExt   string
-	excludeExts  []string
-	includes     []string
-	excludes     []string
-	betweenFiles []string
-	targetFile   string
-	depthLevel   int
-	scope        string
-	pruneFiles   []string
-	alsoPatterns []string
-	edgeLabels   bool
-	noStats      bool
+	outputFormat  string
+	outputPath    string
+	force         bool
+	repoPath      string
+	commitID      string
+	generateURL   bool
+	direction     string
+	allowOutside  bool
+	includeExt    string
+	includeExts   []string
+	excludeExt    string
+	excludeExts   []string
+	includes      []string
+	inputStdin    bool
+	excludes      []string
+	excludeSet    patterns.Set
+	includeGlobs  []string
+	includeSet    patterns.Set
+	betweenFiles  []string
+	targetFile    string
+	depthLevel    int
+	scope         string
+	pruneFiles    []string
+	alsoPatterns  []string
+	alsoSet       patterns.Set
+	edgeLabels    bool
+	edgeSymbols   bool
+	noStats       bool
+	noCache       bool
+	parallelism   int
+	lenient       bool
+	showExternal  bool
+	bestEffort    bool
+	linkManifests bool
+	suppressFile  string
+	suppressMode  string
+	styleFile     string
+	themeName     string
+	themeFile     string
+	theme         formatters.Theme
+	nodeSize      string
+	edgeWeight    string
+	links         bool
+	linkTemplate  string
+	cluster       string
+	clusterDepth  int
+	noPreset      bool
+	hideGenerated bool
+	interactive   bool
+	reduce        bool
+	renderLimit   int
+	collapseLimit int
+	collapseMode  string
+	estimate      bool
+	failOnEmpty   bool
+	metadata      bool
+
+	followSymlinks bool
+	maxFiles       int
+	noGitignore    bool
+
+	uncommitted      string
+	includeUntracked bool
+	uncommittedOpts  git.UncommittedOptions
+
+	// withRepos are the --with-repo values and extraRepoRoots the roots of the
+	// repositories they name, resolved by collectFiles.
+	withRepos      []string
+	extraRepoRoots []string
+
+	// goModuleFlags are the --go-module values and goModules the import path
+	// prefixes they map to absolute directories, resolved by collectFiles.
+	goModuleFlags []string
+	goModules     map[string]string
+
+	// pathAliases maps the symlinks in the repository to the canonical paths they
+	// point at, resolved by collectFiles.
+	pathAliases map[string]string
+
+	attributeEdges      bool
+	attributeMaxCommits int
 }
 
 const (
+	// scopeDownstream follows --file to the files it depends on.
 	scopeDownstream = "downstream"
+	// scopeUpstream follows --file to the files that depend on it.
+	scopeUpstream = "upstream"
+	// scopeBoth combines the downstream and upstream walks.
+	scopeBoth = "both"
 )
 
+// supportedScopes returns the accepted --scope values.
+func supportedScopes() string {
+	return strings.Join([]string{scopeDownstream, scopeUpstream, scopeBoth}, ", ")
+}
+
+// defaultRenderLimit is the node count above which the graph is collapsed by directory.
+const defaultRenderLimit = 400
+
+// --collapse-mode values: how a file whose incoming edges --collapse-threshold removed
+// is drawn.
+const (
+	// collapseModeBadge shows the number of importers on the file.
+	collapseModeBadge = "badge"
+	// collapseModeHide drops the incoming edges without a trace.
+	collapseModeHide = "hide"
+)
+
+const (
+	suppressModeDim  = "dim"
+	suppressModeHide = "hide"
+)
+
+// formatPathsText prints the paths found by --between as text instead of a graph.
+const formatPathsText = "text"
+
 var moduleMajorSuffix = regexp.MustCompile(`^v[0-9]+$`)
 
 // Cmd represents the graph command
@@ -59,12 +159,17 @@ func NewCommand() *cobra.Command {
 		direction:    formatters.DefaultDirection.StringLower(),
 		depthLevel:   1,
 		scope:        scopeDownstream,
+		clusterDepth: formatters.DefaultClusterDepth,
+		collapseMode: collapseModeBadge,
 	}
 
 	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Show a scoped file-based dependency graph",
 		Long:  `Show a scoped file-based dependency graph.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runGraph(cmd, opts)
 		},
@@ -76,45 +181,57 @@ func NewCommand() *cobra.Command {
 		"format",
 		"f",
 		opts.outputFormat,
-		fmt.Sprintf("Output format (%s)", formatters.SupportedFormats()))
-	// Add repo flag
-	cmd.Flags().StringVarP(&opts.repoPath, "repo", "r", "", "Git repository path (default: current directory)")
-	// Add allow outside repo flag
-	cmd.Flags().BoolVar(&opts.allowOutside, "allow-outside-repo", false, "Allow input paths outside the repo root")
-	// Add commit flag
-	cmd.Flags().StringVarP(&opts.commitID, "commit", "c", "", "Git commit or range to analyze (e.g., f0459ec, HEAD~3, f0459ec...be3d11a)")
+		fmt.Sprintf("Output format (%s; %s with --between)", formatters.SupportedFormats(), formatPathsText))
+	addSelectionFlags(cmd, opts)
 	// Add URL flag
 	cmd.Flags().BoolVarP(&opts.generateURL, "url", "u", false, "Generate visualization URL (supported formats: dot, mermaid)")
+	cmd.Flags().StringVarP(&opts.outputPath, "output", "o", "", "Write output to a file, or one file per connected component when it is a directory")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite the --output file when it already exists")
 	cmd.Flags().StringVarP(
 		&opts.direction,
 		"direction",
 		"d",
 		opts.direction,
 		fmt.Sprintf("Graph direction (%s)", formatters.SupportedDirections()))
-	// Add input flag for explicit files/directories
-	cmd.Flags().StringSliceVarP(&opts.includes, "input", "i", nil, "Build graph from specific files and/or directories (comma-separated)")
-	// Add exclude flag for removing explicit files/directories from graph inputs
-	cmd.Flags().StringSliceVar(&opts.excludes, "exclude", nil, "Exclude specific files and/or directories from graph inputs (comma-separated)")
-	// Add extension inclusion flag
-	cmd.Flags().StringVar(&opts.includeExt, "include-ext", "", "Include only files with these extensions (comma-separated, e.g. .go,.java)")
-	// Add extension exclusion flag
-	cmd.Flags().StringVar(&opts.excludeExt, "exclude-ext", "", "Exclude files with these extensions (comma-separated, e.g. .go,.java)")
-	// Add between flag for finding paths between files
-	cmd.Flags().StringSliceVarP(&opts.betweenFiles, "between", "w", nil, "Find all paths between specified files (comma-separated)")
-	// Add file flag for showing dependencies of a specific file
-	cmd.Flags().StringVarP(&opts.targetFile, "file", "p", "", "Show dependencies for a specific file")
+	cmd.Flags().BoolVar(&opts.interactive, "interactive", false, "Prompt to choose when a --file or --between name matches several files")
 	// Add level flag for limiting dependency depth
 	cmd.Flags().IntVarP(&opts.depthLevel, "level", "l", opts.depthLevel, "Depth level for dependencies (used with --file, 0 = unlimited)")
-	cmd.Flags().StringVar(&opts.scope, "scope", opts.scope, "Dependency scope for --file (downstream only)")
+	cmd.Flags().StringVar(&opts.scope, "scope", opts.scope, fmt.Sprintf("Dependency scope for --file (%s)", supportedScopes()))
 	cmd.Flags().StringSliceVar(&opts.pruneFiles, "prune", nil, "Show node but skip its subtree (requires --file; shown with dashed border)")
-	cmd.Flags().StringSliceVar(&opts.alsoPatterns, "also", nil, "Include files matching glob patterns that connect to --file graph (requires --file)")
+	cmd.Flags().StringSliceVar(&opts.alsoPatterns, "also", nil, "Include files matching path patterns that connect to --file graph (requires --file)")
 	cmd.Flags().BoolVar(&opts.edgeLabels, "label", false, "Add deterministic short labels to edges")
+	cmd.Flags().BoolVar(&opts.edgeSymbols, "edge-symbols", false, "Annotate edges with the symbols each file uses from its dependency (dot tooltips, mermaid labels)")
 	cmd.Flags().BoolVar(&opts.noStats, "no-stats", false, "Skip file addition/deletion statistics for faster rendering")
+	addParseFlags(cmd, opts)
+	cmd.Flags().BoolVar(&opts.showExternal, "show-external", false, "Show the third-party packages files import as one node per package")
+	cmd.Flags().BoolVar(&opts.bestEffort, "best-effort-edges", false, "Infer dashed edges from relative import paths in unsupported files")
+	cmd.Flags().BoolVar(&opts.linkManifests, "link-manifests", false, "Draw dashed edges from source files to the dependency manifest of their module, e.g. go.mod")
+	cmd.Flags().BoolVar(&opts.attributeEdges, "attribute-edges", false, "Annotate edges new in a commit range with the commit that introduced them")
+	cmd.Flags().IntVar(&opts.attributeMaxCommits, "attribute-max-commits", defaultAttributeMaxCommits, "Maximum commits in a range analyzed by --attribute-edges")
+	cmd.Flags().BoolVar(&opts.reduce, "transitive-reduction", false, "Drop edges implied by longer paths (edges inside cycles are kept)")
+	cmd.Flags().IntVar(&opts.renderLimit, "render-limit", defaultRenderLimit, "Collapse files by directory when the graph has more nodes than this (0 = no limit)")
+	cmd.Flags().IntVar(&opts.collapseLimit, "collapse-threshold", 0, "Drop the incoming edges of files imported by more than this many files (0 = off)")
+	cmd.Flags().StringVar(&opts.collapseMode, "collapse-mode", opts.collapseMode, fmt.Sprintf("How files collapsed by --collapse-threshold are drawn (%s, %s)", collapseModeBadge, collapseModeHide))
+	cmd.Flags().StringVar(&opts.suppressFile, "suppress-file", "", "Edge suppression rules file for known-acceptable couplings")
+	cmd.Flags().StringVar(&opts.suppressMode, "apply-suppressions", "", "Dim or hide edges matched by --suppress-file (dim, hide)")
+	cmd.Flags().Lookup("apply-suppressions").NoOptDefVal = suppressModeDim
+	cmd.Flags().StringVar(&opts.styleFile, "style-file", "", "Node styling rules file that colors files by path pattern")
+	cmd.Flags().StringVar(&opts.themeName, "theme", formatters.ThemeLight, fmt.Sprintf("Color theme of dot and mermaid output (%s)", formatters.SupportedThemes()))
+	cmd.Flags().StringVar(&opts.themeFile, "theme-file", "", "YAML or JSON palette mapping roles to colors, read by --theme custom")
+	cmd.Flags().StringVar(&opts.cluster, "cluster", "", fmt.Sprintf("Group nodes into clusters (%s)", formatters.SupportedClusterModes()))
+	cmd.Flags().IntVar(&opts.clusterDepth, "cluster-depth", opts.clusterDepth, "Directory levels --cluster=dir keeps apart; deeper directories join their ancestor's cluster")
+	cmd.Flags().StringVar(&opts.nodeSize, "node-size", "", fmt.Sprintf("Scale nodes by a metric of their file (%s)", formatters.SupportedNodeSizes()))
+	cmd.Flags().StringVar(&opts.edgeWeight, "edge-weight", "", fmt.Sprintf("Weigh edges by a metric of the dependency (%s)", formatters.SupportedEdgeWeights()))
+	cmd.Flags().BoolVar(&opts.links, "links", false, "Link nodes to their files on the source hosting of the origin remote (dot, mermaid, html)")
+	cmd.Flags().StringVar(&opts.linkTemplate, "link-template", "", fmt.Sprintf("URL of a linked file for --links, with %s and %s placeholders", formatters.LinkCommitPlaceholder, formatters.LinkPathPlaceholder))
+	cmd.Flags().BoolVar(&opts.estimate, "estimate", false, "Print the projected cost of the analysis and exit without building the graph")
+	cmd.Flags().BoolVar(&opts.failOnEmpty, "fail-on-empty", false, "Exit with an error when no files are analyzed (the placeholder graph is still written)")
+	cmd.Flags().BoolVar(&opts.metadata, "metadata", false, "Append the version, repository, commit, flags, file count and content hash of the graph (dot, mermaid, json)")
 
 	return cmd
 }
 
-func runGraph(cmd *cobra.Command, opts *graphOptions) error {
+func runGraph(cmd *cobra.Command, opts *graphOptions) (err error) {
 	mcplogdlog.Info("show: build graph", map[string]any{
 		"repo":      opts.repoPath,
 		"input":     opts.includes,
@@ -127,114 +244,320 @@ func runGraph(cmd *cobra.Command, opts *graphOptions) error {
 		return err
 	}
 
-	ensureRepoPath(opts)
-	pathResolver, err := NewPathResolver(opts.repoPath, opts.allowOutside)
+	resources := &runResources{}
+	defer func() {
+		if closeErr := resources.closeAll(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	selection, err := collectFiles(cmd, opts, resources)
 	if err != nil {
-		return fmt.Errorf("failed to create path resolver: %w", err)
+		return err
+	}
+	if selection.clean {
+		PrintCleanWorkingDirectoryHint(cmd)
+		return nil
 	}
-	opts.repoPath = pathResolver.BaseDir()
+	filePaths, contentReader := selection.filePaths, selection.contentReader
+	fromCommit, toCommit, isCommitRange := selection.fromCommit, selection.toCommit, selection.isCommitRange
 
-	fromCommit, toCommit, isCommitRange, err := parseCommitRange(opts)
+	cliconfig.Logger(cmd).Debug("discovered files", "count", len(filePaths))
+	emitUnsupportedFileWarning(cmd, filePaths, contentReader)
+
+	if opts.estimate {
+		return runEstimate(cmd, opts, filePaths, fromCommit, toCommit, isCommitRange)
+	}
+
+	pathsText := isPathsTextFormat(opts.outputFormat)
+	format, ok := formatters.ParseOutputFormat(opts.outputFormat)
+	if !ok && !pathsText {
+		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, formatters.SupportedFormats())
+	}
+
+	// The selected files are passed even when none remain, so the analyzer never falls
+	// back to selecting the changed files itself.
+	refined := &refinedGraph{filePaths: filePaths}
+	analyzer := analysis.Analyzer{
+		RepoPath:       opts.repoPath,
+		CommitRange:    opts.commitID,
+		ExplicitPaths:  append([]string{}, filePaths...),
+		Uncommitted:    &opts.uncommittedOpts,
+		ExtraRepoPaths: opts.extraRepoRoots,
+		GoModules:      opts.goModules,
+		PathAliases:    opts.pathAliases,
+		ContentReader:  contentReader,
+		Cache:          parseCache(opts),
+		Parallelism:    opts.parallelism,
+		Progress:       cliconfig.Progress(cmd),
+		Lenient:        opts.lenient,
+		ShowExternal:   opts.showExternal,
+		SkipStats:      pathsText || !needsFileStats(opts, format),
+		Refine: func(graph depgraph.DependencyGraph) (depgraph.DependencyGraph, error) {
+			return refineGraph(cmd, opts, format, selection, resources, refined, graph)
+		},
+	}
+	result, err := analyzer.Run(commandContext(cmd))
 	if err != nil {
+		mcplogdlog.Error("show: build dependency graph failed", map[string]any{"error": err.Error()})
 		return err
 	}
+	logDiagnostics(cliconfig.Logger(cmd), result.Diagnostics)
+	if result.StatsErr != nil {
+		cliconfig.Warnf(cmd, "failed to get file statistics: %v", result.StatsErr)
+	}
+	fileGraph := result.Graph
+	filePaths = refined.filePaths
 
-	filePaths, done, err := determineFilePaths(cmd, opts, pathResolver, fromCommit, toCommit, isCommitRange)
-	if err != nil {
+	if pathsText {
+		return writeOutput(cmd, opts, formatPathSummary(opts.repoPath, refined.paths))
+	}
+
+	basePath := resolveRenderBasePath(opts.repoPath, filePaths)
+	label := buildGraphLabel(commandContext(cmd), opts, format, fromCommit, toCommit, isCommitRange, filePaths, contentReader)
+	if label != "" && refined.collapseDepth > 0 {
+		label += fmt.Sprintf(" • auto-collapsed to depth %d", refined.collapseDepth)
+	}
+	emptyGraph := len(fileGraph.Meta.Files) == 0
+	if emptyGraph && format == formatters.OutputFormatJSON {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Note: no files analyzed")
+	} else if emptyGraph {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Note: no files analyzed; the graph shows a placeholder node")
+	}
+
+	for node := range refined.prunedNodes {
+		if md, ok := fileGraph.Meta.Files[node]; ok {
+			md.IsPruned = true
+			fileGraph.Meta.Files[node] = md
+		}
+	}
+	for node, dependents := range refined.collapsedDependents {
+		if md, ok := fileGraph.Meta.Files[node]; ok {
+			md.CollapsedDependents = dependents
+			fileGraph.Meta.Files[node] = md
+		}
+	}
+	for edge, provenance := range refined.edgeProvenances {
+		if md, ok := fileGraph.Meta.Edges[edge]; ok {
+			md.Provenance = provenance
+			fileGraph.Meta.Edges[edge] = md
+		}
+	}
+	for edge, symbols := range refined.edgeSymbols {
+		if md, ok := fileGraph.Meta.Edges[edge]; ok {
+			md.Symbols = symbols
+			fileGraph.Meta.Edges[edge] = md
+		}
+	}
+	for edge, usages := range refined.edgeUsages {
+		if md, ok := fileGraph.Meta.Edges[edge]; ok {
+			md.Usages = usages
+			fileGraph.Meta.Edges[edge] = md
+		}
+	}
+	for edge := range refined.suppressedEdges {
+		if md, ok := fileGraph.Meta.Edges[edge]; ok {
+			md.Suppressed = true
+			fileGraph.Meta.Edges[edge] = md
+		}
+	}
+	for edge, commit := range refined.introducedIn {
+		if md, ok := fileGraph.Meta.Edges[edge]; ok {
+			md.IntroducedIn = commit
+			fileGraph.Meta.Edges[edge] = md
+		}
+	}
+	fileGraph.Meta.TransitivelyReduced = opts.reduce
+	if err := applyStyleRules(opts, fileGraph); err != nil {
 		return err
 	}
-	if done {
-		return nil
+	nodeSize, _ := formatters.ParseNodeSize(opts.nodeSize)
+	cluster, _ := formatters.ParseClusterMode(opts.cluster)
+	edgeWeight, _ := formatters.ParseEdgeWeight(opts.edgeWeight)
+	if nodeSize == formatters.NodeSizeLOC {
+		applyLineCounts(fileGraph, contentReader)
+	}
+	for node, blobSHA := range collectBlobSHAs(cmd, opts, format, toCommit, filePaths) {
+		if md, ok := fileGraph.Meta.Files[node]; ok {
+			md.BlobSHA = blobSHA
+			fileGraph.Meta.Files[node] = md
+		}
 	}
 
-	filePaths, err = applyExcludePathFilter(opts, pathResolver, filePaths)
+	nodeLinks, err := collectNodeLinks(commandContext(cmd), opts, toCommit, filePaths)
 	if err != nil {
 		return err
 	}
 
-	filePaths, err = applyIncludeExtensionFilter(opts, filePaths)
+	formatter, err := formatters.NewFormatter(opts.outputFormat)
 	if err != nil {
 		return err
 	}
 
-	filePaths, err = applyExcludeExtensionFilter(opts, filePaths)
+	direction, _ := formatters.ParseDirection(opts.direction)
+	renderOpts := formatters.RenderOptions{
+		Label:        label,
+		Direction:    direction,
+		BasePath:     basePath,
+		EdgeLabels:   opts.edgeLabels,
+		EdgeSymbols:  opts.edgeSymbols,
+		NodeSize:     nodeSize,
+		EdgeWeight:   edgeWeight,
+		Cluster:      cluster,
+		ClusterDepth: opts.clusterDepth,
+		Theme:        opts.theme,
+		NodeLinks:    nodeLinks,
+	}
+	if opts.metadata {
+		graphMeta := buildGraphMetadata(cmd, opts, fromCommit, toCommit, isCommitRange, contentReader)
+		renderOpts.Metadata = &graphMeta
+	}
+
+	if isOutputDirectory(opts.outputPath) {
+		if err := emitComponentOutputs(cmd, opts, format, formatter, fileGraph, renderOpts); err != nil {
+			return err
+		}
+		return emptyGraphError(opts, emptyGraph)
+	}
+
+	output, err := formatter.Format(fileGraph, renderOpts)
 	if err != nil {
+		return fmt.Errorf("failed to format graph: %w", err)
+	}
+
+	if err := emitOutput(cmd, opts, format, formatter, output); err != nil {
 		return err
 	}
+	return emptyGraphError(opts, emptyGraph)
+}
+
+// refinedGraph carries what refineGraph learns about the graph while narrowing it,
+// for attaching to the file metadata afterwards.
+type refinedGraph struct {
+	filePaths       []string
+	paths           depgraph.PathSet
+	edgeProvenances map[depgraph.FileEdge]depgraph.EdgeProvenance
+	edgeSymbols     map[depgraph.FileEdge][]string
+	edgeUsages      map[depgraph.FileEdge]int
+	prunedNodes     map[string]bool
+	suppressedEdges map[depgraph.FileEdge]bool
+	introducedIn    map[depgraph.FileEdge]string
+	// collapsedDependents maps files whose incoming edges --collapse-threshold removed
+	// to their number of importers, when --collapse-mode shows it.
+	collapsedDependents map[string]int
+	collapseDepth       int
+}
 
-	emitUnsupportedFileWarning(filePaths)
+// refineGraph applies the graph filters selected by the flags, in order, recording in
+// refined the files that remain and the edge and node annotations they produce.
+func refineGraph(cmd *cobra.Command, opts *graphOptions, format formatters.OutputFormat, selection fileSelection, resources *runResources, refined *refinedGraph, graph depgraph.DependencyGraph) (depgraph.DependencyGraph, error) {
+	pathResolver, contentReader := selection.pathResolver, selection.contentReader
+	filePaths := refined.filePaths
 
-	contentReader := selectContentReader(opts, toCommit)
+	if opts.bestEffort {
+		if err := addHeuristicEdges(cmd, opts, graph, filePaths, contentReader); err != nil {
+			return nil, err
+		}
+	}
+	if opts.linkManifests {
+		if err := depgraph.LinkManifests(graph); err != nil {
+			return nil, fmt.Errorf("failed to link dependency manifests: %w", err)
+		}
+	}
 
-	graph, err := depgraph.BuildDependencyGraph(filePaths, contentReader)
+	// Filters below rebuild the graph from adjacency, so capture provenance,
+	// symbols, and usage counts first.
+	var err error
+	refined.edgeProvenances, err = depgraph.EdgeProvenances(graph)
 	if err != nil {
-		mcplogdlog.Error("show: build dependency graph failed", map[string]any{"error": err.Error()})
-		return fmt.Errorf("failed to build dependency graph: %w", err)
+		return nil, fmt.Errorf("failed to read edge provenance: %w", err)
+	}
+	refined.edgeSymbols, err = depgraph.EdgeSymbols(graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edge symbols: %w", err)
+	}
+	refined.edgeUsages, err = depgraph.EdgeUsages(graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edge usages: %w", err)
 	}
 
 	var fullAdjacency map[string][]string
 	if len(opts.alsoPatterns) > 0 {
 		fullAdjacency, err = depgraph.AdjacencyList(graph)
 		if err != nil {
-			return fmt.Errorf("failed to build adjacency list: %w", err)
+			return nil, fmt.Errorf("failed to build adjacency list: %w", err)
 		}
 	}
 
-	var prunedNodes map[string]bool
-	graph, filePaths, prunedNodes, err = applyTargetFileFilter(opts, pathResolver, graph, filePaths)
+	graph, filePaths, refined.prunedNodes, err = applyTargetFileFilter(cmd, opts, pathResolver, graph, filePaths)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(opts.alsoPatterns) > 0 && opts.targetFile != "" {
 		graph, filePaths, err = applyAlsoFilter(opts, pathResolver, graph, filePaths, fullAdjacency)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	graph, filePaths, err = applyBetweenFilter(opts, pathResolver, graph, filePaths)
+	graph, filePaths, refined.paths, err = applyBetweenFilter(cmd, opts, pathResolver, graph, filePaths)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	refined.filePaths = filePaths
 
-	format, ok := formatters.ParseOutputFormat(opts.outputFormat)
-	if !ok {
-		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, formatters.SupportedFormats())
-	}
-	fileStats := collectFileStats(cmd, opts, format, fromCommit, toCommit, isCommitRange)
-	label := buildGraphLabel(opts, format, fromCommit, toCommit, isCommitRange, filePaths)
-	fileGraph, err := depgraph.NewFileDependencyGraph(graph, fileStats, contentReader)
+	graph, refined.suppressedEdges, err = applySuppressions(cmd, opts, graph, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to build file graph metadata: %w", err)
+		return nil, err
 	}
 
-	for node := range prunedNodes {
-		if md, ok := fileGraph.Meta.Files[node]; ok {
-			md.IsPruned = true
-			fileGraph.Meta.Files[node] = md
-		}
+	graph, err = applyTransitiveReduction(cmd, opts, graph)
+	if err != nil {
+		return nil, err
 	}
 
-	formatter, err := formatters.NewFormatter(opts.outputFormat)
+	graph, refined.collapsedDependents, err = applyFanInCollapse(cmd, opts, graph)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	direction, _ := formatters.ParseDirection(opts.direction)
-	renderOpts := formatters.RenderOptions{
-		Label:      label,
-		Direction:  direction,
-		BasePath:   resolveRenderBasePath(opts.repoPath, filePaths),
-		EdgeLabels: opts.edgeLabels,
+	if opts.attributeEdges {
+		refined.introducedIn, err = attributeEdges(commandContext(cmd), opts, resources, selection.fromCommit, selection.toCommit, graph)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	output, err := formatter.Format(fileGraph, renderOpts)
+	// A text summary lists paths rather than drawing them, so it is never collapsed.
+	if isPathsTextFormat(opts.outputFormat) {
+		return graph, nil
+	}
+
+	basePath := resolveRenderBasePath(opts.repoPath, filePaths)
+	graph, refined.collapseDepth, err = applyRenderLimit(cmd, opts, format, graph, basePath)
 	if err != nil {
-		return fmt.Errorf("failed to format graph: %w", err)
+		return nil, err
+	}
+	return graph, nil
+}
+
+// commandContext returns the context cmd runs with, or a background context when it
+// was started without one.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
 	}
+	return context.Background()
+}
 
-	return emitOutput(cmd, opts, format, formatter, output)
+// emptyGraphError fails the run for an empty graph when --fail-on-empty is set. The
+// output is written first so callers still get the placeholder graph.
+func emptyGraphError(opts *graphOptions, emptyGraph bool) error {
+	if emptyGraph && opts.failOnEmpty {
+		return fmt.Errorf("no files analyzed (--fail-on-empty)")
+	}
+	return nil
 }
 
 func resolveRenderBasePath(repoPath string, filePaths []string) string {
@@ -243,6 +566,10 @@ func resolveRenderBasePath(repoPath string, filePaths []string) string {
 	}
 
 	common := commonPathPrefix(filePaths)
+	if len(filePaths) == 1 {
+		// The prefix of a single file is the file itself; key it relative to its directory.
+		common = filepath.Dir(common)
+	}
 	if common == "" || common == string(filepath.Separator) {
 		return ""
 	}
@@ -321,6 +648,38 @@ func validateGraphOptions(opts *graphOptions) error {
 	}
 	opts.direction = direction.StringLower()
 
+	if _, ok := formatters.ParseNodeSize(opts.nodeSize); !ok {
+		return fmt.Errorf("unknown node size: %s (valid options: %s)", opts.nodeSize, formatters.SupportedNodeSizes())
+	}
+	if _, ok := formatters.ParseEdgeWeight(opts.edgeWeight); !ok {
+		return fmt.Errorf("unknown edge weight: %s (valid options: %s)", opts.edgeWeight, formatters.SupportedEdgeWeights())
+	}
+	if len(opts.withRepos) > 0 && opts.commitID != "" {
+		return fmt.Errorf("--with-repo cannot be used with --commit: commits belong to one repository")
+	}
+	if opts.linkTemplate != "" {
+		if !opts.links {
+			return fmt.Errorf("--link-template requires --links")
+		}
+		if err := formatters.ValidateLinkTemplate(opts.linkTemplate); err != nil {
+			return err
+		}
+	}
+
+	cluster, ok := formatters.ParseClusterMode(opts.cluster)
+	if !ok {
+		return fmt.Errorf("unknown cluster mode: %s (valid options: %s)", opts.cluster, formatters.SupportedClusterModes())
+	}
+	if cluster != formatters.ClusterNone && opts.clusterDepth < 1 {
+		return fmt.Errorf("--cluster-depth must be at least 1")
+	}
+
+	theme, err := formatters.LoadTheme(opts.themeName, opts.themeFile)
+	if err != nil {
+		return err
+	}
+	opts.theme = theme
+
 	if opts.includeExt != "" {
 		includeExts, err := normalizeExtensions("--include-ext", opts.includeExt)
 		if err != nil {
@@ -337,17 +696,38 @@ func validateGraphOptions(opts *graphOptions) error {
 		opts.excludeExts = excludeExts
 	}
 
+	var excludeGlobs []string
+	for _, exclude := range opts.excludes {
+		if patterns.HasMeta(exclude) {
+			excludeGlobs = append(excludeGlobs, exclude)
+		}
+	}
+	excludeSet, err := patterns.CompileSet(excludeGlobs)
+	if err != nil {
+		return fmt.Errorf("--exclude: %w", err)
+	}
+	opts.excludeSet = excludeSet
+
+	includeSet, err := patterns.CompileSet(opts.includeGlobs)
+	if err != nil {
+		return fmt.Errorf("--include-glob: %w", err)
+	}
+	opts.includeSet = includeSet
+
 	scope := strings.ToLower(strings.TrimSpace(opts.scope))
 	switch scope {
-	case scopeDownstream:
+	case scopeDownstream, scopeUpstream, scopeBoth:
 		opts.scope = scope
 	default:
-		return fmt.Errorf("unknown scope: %s (valid options: %s)", opts.scope, scopeDownstream)
+		return fmt.Errorf("unknown scope: %s (valid options: %s)", opts.scope, supportedScopes())
 	}
 
 	if len(opts.betweenFiles) > 0 && len(opts.includes) > 0 {
 		return fmt.Errorf("--between cannot be used with --input flag")
 	}
+	if len(opts.betweenFiles) > 0 && opts.inputStdin {
+		return fmt.Errorf("--between cannot be used with --input-stdin flag")
+	}
 
 	if opts.targetFile != "" {
 		if len(opts.betweenFiles) > 0 {
@@ -356,6 +736,9 @@ func validateGraphOptions(opts *graphOptions) error {
 		if len(opts.includes) > 0 {
 			return fmt.Errorf("--file cannot be used with --input flag")
 		}
+		if opts.inputStdin {
+			return fmt.Errorf("--file cannot be used with --input-stdin flag")
+		}
 		if opts.depthLevel < 0 {
 			return fmt.Errorf("--level must be at least 0")
 		}
@@ -365,9 +748,96 @@ func validateGraphOptions(opts *graphOptions) error {
 		return fmt.Errorf("--prune requires --file flag")
 	}
 
+	uncommittedOpts, err := parseUncommittedSelection(opts.uncommitted, opts.includeUntracked)
+	if err != nil {
+		return err
+	}
+	if opts.commitID != "" && uncommittedOpts != git.AllUncommittedChanges() {
+		return fmt.Errorf("--uncommitted and --include-untracked cannot be used with --commit flag")
+	}
+	opts.uncommittedOpts = uncommittedOpts
+
 	if len(opts.alsoPatterns) > 0 && opts.targetFile == "" {
 		return fmt.Errorf("--also requires --file flag")
 	}
+	alsoSet, err := patterns.CompileSet(opts.alsoPatterns)
+	if err != nil {
+		return fmt.Errorf("--also: %w", err)
+	}
+	opts.alsoSet = alsoSet
+
+	if isPathsTextFormat(opts.outputFormat) {
+		if len(opts.betweenFiles) == 0 {
+			return fmt.Errorf("--format %s requires --between flag", formatPathsText)
+		}
+		if opts.generateURL {
+			return fmt.Errorf("--url cannot be used with --format %s", formatPathsText)
+		}
+		if isOutputDirectory(opts.outputPath) {
+			return fmt.Errorf("--format %s cannot be written to an --output directory", formatPathsText)
+		}
+	}
+
+	for _, betweenFile := range opts.betweenFiles {
+		if !patterns.HasMeta(betweenFile) {
+			continue
+		}
+		if _, err := patterns.Compile(betweenFile); err != nil {
+			return fmt.Errorf("--between: %w", err)
+		}
+	}
+
+	if opts.attributeEdges {
+		if _, _, isRange := git.ParseCommitRange(opts.commitID); !isRange {
+			return fmt.Errorf("--attribute-edges requires a commit range (e.g., -c main...HEAD)")
+		}
+		if opts.attributeMaxCommits < 1 {
+			return fmt.Errorf("--attribute-max-commits must be at least 1")
+		}
+	}
+
+	if opts.generateURL && isOutputDirectory(opts.outputPath) {
+		return fmt.Errorf("--url cannot be used when --output is a directory")
+	}
+
+	// Fail before the analysis rather than after it when the file cannot be written.
+	if opts.outputPath != "" && !isOutputDirectory(opts.outputPath) {
+		if err := checkOutputFileWritable(opts.outputPath, opts.force); err != nil {
+			return err
+		}
+	}
+
+	if opts.renderLimit < 0 {
+		return fmt.Errorf("--render-limit must be at least 0")
+	}
+
+	if opts.collapseLimit < 0 {
+		return fmt.Errorf("--collapse-threshold must be at least 0")
+	}
+	switch opts.collapseMode {
+	case "", collapseModeBadge, collapseModeHide:
+	default:
+		return fmt.Errorf("unknown --collapse-mode: %s (valid options: %s, %s)", opts.collapseMode, collapseModeBadge, collapseModeHide)
+	}
+
+	if opts.maxFiles < 0 {
+		return fmt.Errorf("--max-files must be at least 0")
+	}
+
+	if opts.parallelism < 0 {
+		return fmt.Errorf("--parallelism must be at least 0")
+	}
+
+	if opts.suppressMode != "" {
+		switch opts.suppressMode {
+		case suppressModeDim, suppressModeHide:
+		default:
+			return fmt.Errorf("unknown --apply-suppressions mode: %s (valid options: %s, %s)", opts.suppressMode, suppressModeDim, suppressModeHide)
+		}
+		if opts.suppressFile == "" {
+			return fmt.Errorf("--apply-suppressions requires --suppress-file flag")
+		}
+	}
 
 	return nil
 }
@@ -407,37 +877,52 @@ func normalizeExtensions(flagName, rawExts string) ([]string, error) {
 	return exts, nil
 }
 
-func ensureRepoPath(opts *graphOptions) {
-	if opts.repoPath == "" {
-		opts.repoPath = "."
-	}
-}
-
-func parseCommitRange(opts *graphOptions) (string, string, bool, error) {
-	var fromCommit, toCommit string
-	var isCommitRange bool
-
+// parseCommitRange splits --commit into the commits to compare. Two-dot ranges are
+// put in chronological order; three-dot ranges compare the right side with its
+// merge-base with the left side, as pull request diffs do, so changes made only on
+// the left branch are left out.
+func parseCommitRange(ctx context.Context, opts *graphOptions) (string, string, bool, error) {
 	if opts.commitID == "" {
 		return "", "", false, nil
 	}
+	return git.ResolveCommitRangeContext(ctx, opts.repoPath, opts.commitID)
+}
 
-	fromCommit, toCommit, isCommitRange = git.ParseCommitRange(opts.commitID)
-	if !isCommitRange {
-		return fromCommit, toCommit, isCommitRange, nil
-	}
+const (
+	uncommittedStaged   = "staged"
+	uncommittedUnstaged = "unstaged"
+	uncommittedAll      = "all"
+)
 
-	fromCommit, toCommit, _, err := git.NormalizeCommitRange(opts.repoPath, fromCommit, toCommit)
-	if err != nil {
-		return "", "", false, fmt.Errorf("failed to normalize commit range: %w", err)
-	}
+// supportedUncommittedSelections returns the accepted --uncommitted values.
+func supportedUncommittedSelections() string {
+	return strings.Join([]string{uncommittedStaged, uncommittedUnstaged, uncommittedAll}, ", ")
+}
 
-	return fromCommit, toCommit, isCommitRange, nil
+// parseUncommittedSelection converts --uncommitted and --include-untracked to the
+// changes an uncommitted run analyzes. Untracked files are never staged, so a staged
+// selection leaves them out.
+func parseUncommittedSelection(selection string, includeUntracked bool) (git.UncommittedOptions, error) {
+	switch selection {
+	case uncommittedStaged:
+		return git.UncommittedOptions{IncludeStaged: true}, nil
+	case uncommittedUnstaged:
+		return git.UncommittedOptions{IncludeUnstaged: true, IncludeUntracked: includeUntracked}, nil
+	case uncommittedAll:
+		return git.UncommittedOptions{IncludeStaged: true, IncludeUnstaged: true, IncludeUntracked: includeUntracked}, nil
+	default:
+		return git.UncommittedOptions{}, fmt.Errorf("unknown --uncommitted value: %s (valid options: %s)", selection, supportedUncommittedSelections())
+	}
 }
 
-func determineFilePaths(cmd *cobra.Command, opts *graphOptions, pathResolver PathResolver, fromCommit, toCommit string, isCommitRange bool) ([]string, bool, error) {
-	if len(opts.includes) > 0 {
+// determineFilePaths collects the files selected by the flags. It reports done, with
+// no files, when there are no uncommitted changes to show.
+func determineFilePaths(ctx context.Context, opts *graphOptions, pathResolver PathResolver, fromCommit, toCommit string, isCommitRange bool) ([]string, bool, error) {
+	// --input-stdin selects input paths even when stdin lists none, so an empty list
+	// fails like an -i that matches nothing instead of falling back to uncommitted files.
+	if len(opts.includes) > 0 || opts.inputStdin {
 		if opts.commitID != "" {
-			filePaths, err := collectCommitIncludedFilePaths(opts, pathResolver, toCommit)
+			filePaths, err := collectCommitIncludedFilePaths(ctx, opts, pathResolver, toCommit)
 			if err != nil {
 				return nil, false, err
 			}
@@ -453,7 +938,7 @@ func determineFilePaths(cmd *cobra.Command, opts *graphOptions, pathResolver Pat
 			resolvedIncludes = append(resolvedIncludes, resolvedInclude.String())
 		}
 
-		filePaths, err := expandPaths(resolvedIncludes, true)
+		filePaths, err := expandPaths(resolvedIncludes, true, opts.walkOptions())
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to expand paths: %w", err)
 		}
@@ -464,7 +949,7 @@ func determineFilePaths(cmd *cobra.Command, opts *graphOptions, pathResolver Pat
 	}
 
 	if len(opts.betweenFiles) > 0 {
-		filePaths, err := collectBetweenFilePaths(opts, toCommit)
+		filePaths, err := collectBetweenFilePaths(ctx, opts, toCommit)
 		if err != nil {
 			return nil, false, err
 		}
@@ -472,7 +957,7 @@ func determineFilePaths(cmd *cobra.Command, opts *graphOptions, pathResolver Pat
 	}
 
 	if opts.commitID != "" {
-		filePaths, err := collectCommitFilePaths(opts, fromCommit, toCommit, isCommitRange)
+		filePaths, err := collectCommitFilePaths(ctx, opts, fromCommit, toCommit, isCommitRange)
 		if err != nil {
 			return nil, false, err
 		}
@@ -480,7 +965,7 @@ func determineFilePaths(cmd *cobra.Command, opts *graphOptions, pathResolver Pat
 	}
 
 	if opts.targetFile != "" {
-		filePaths, err := expandPaths([]string{opts.repoPath}, false)
+		filePaths, err := expandPaths([]string{opts.repoPath}, false, opts.walkOptions())
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to expand working directory: %w", err)
 		}
@@ -490,27 +975,40 @@ func determineFilePaths(cmd *cobra.Command, opts *graphOptions, pathResolver Pat
 		return filePaths, false, nil
 	}
 
-	filePaths, err := git.GetUncommittedFiles(opts.repoPath)
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to get uncommitted files: %w", err)
+	var filePaths []string
+	var changed bool
+	for _, repoPath := range append([]string{opts.repoPath}, opts.extraRepoRoots...) {
+		repoFiles, repoChanged, err := analysis.ListChangedFiles(opts.includeExts, func(exts ...string) ([]string, error) {
+			return git.GetUncommittedFilesWithOptionsContext(ctx, repoPath, opts.uncommittedOpts, exts...)
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get uncommitted files: %w", err)
+		}
+		filePaths = append(filePaths, repoFiles...)
+		changed = changed || repoChanged
 	}
 
-	if len(filePaths) == 0 {
-		fmt.Fprintln(cmd.OutOrStdout(), "Working directory is clean (no uncommitted changes).")
-		fmt.Fprintln(cmd.OutOrStdout())
-		fmt.Fprintln(cmd.OutOrStdout(), "To visualize the most recent commit:")
-		fmt.Fprintln(cmd.OutOrStdout(), "  clarity show -c HEAD")
-		fmt.Fprintln(cmd.OutOrStdout())
-		fmt.Fprintln(cmd.OutOrStdout(), "To visualize a specific commit:")
-		fmt.Fprintln(cmd.OutOrStdout(), "  clarity show -c <commit-hash>")
+	if !changed {
 		return nil, true, nil
 	}
 
 	return filePaths, false, nil
 }
 
-func collectCommitIncludedFilePaths(opts *graphOptions, pathResolver PathResolver, toCommit string) ([]string, error) {
-	commitFiles, err := git.GetCommitTreeFiles(opts.repoPath, toCommit)
+// PrintCleanWorkingDirectoryHint explains what to run instead when there are no
+// uncommitted changes to show.
+func PrintCleanWorkingDirectoryHint(cmd *cobra.Command) {
+	fmt.Fprintln(cmd.OutOrStdout(), "Working directory is clean (no uncommitted changes).")
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintln(cmd.OutOrStdout(), "To visualize the most recent commit:")
+	fmt.Fprintln(cmd.OutOrStdout(), "  clarity show -c HEAD")
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintln(cmd.OutOrStdout(), "To visualize a specific commit:")
+	fmt.Fprintln(cmd.OutOrStdout(), "  clarity show -c <commit-hash>")
+}
+
+func collectCommitIncludedFilePaths(ctx context.Context, opts *graphOptions, pathResolver PathResolver, toCommit string) ([]string, error) {
+	commitFiles, err := git.GetCommitTreeFilesContext(ctx, opts.repoPath, toCommit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get files from commit tree: %w", err)
 	}
@@ -547,19 +1045,19 @@ func collectCommitIncludedFilePaths(opts *graphOptions, pathResolver PathResolve
 	return filtered, nil
 }
 
-func collectBetweenFilePaths(opts *graphOptions, toCommit string) ([]string, error) {
+func collectBetweenFilePaths(ctx context.Context, opts *graphOptions, toCommit string) ([]string, error) {
 	if opts.commitID != "" {
-		filePaths, err := git.GetCommitTreeFiles(opts.repoPath, toCommit)
+		filePaths, err := git.GetCommitTreeFilesContext(ctx, opts.repoPath, toCommit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get files from commit tree: %w", err)
 		}
 		if len(filePaths) == 0 {
-			return nil, fmt.Errorf("no files found in commit %s", toCommit)
+			return nil, fmt.Errorf("no files found in commit %s", opts.commitID)
 		}
 		return filePaths, nil
 	}
 
-	filePaths, err := expandPaths([]string{opts.repoPath}, false)
+	filePaths, err := expandPaths([]string{opts.repoPath}, false, opts.walkOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to expand working directory: %w", err)
 	}
@@ -569,47 +1067,54 @@ func collectBetweenFilePaths(opts *graphOptions, toCommit string) ([]string, err
 	return filePaths, nil
 }
 
-func collectCommitFilePaths(opts *graphOptions, fromCommit, toCommit string, isCommitRange bool) ([]string, error) {
+func collectCommitFilePaths(ctx context.Context, opts *graphOptions, fromCommit, toCommit string, isCommitRange bool) ([]string, error) {
 	if isCommitRange {
-		filePaths, err := git.GetCommitRangeFiles(opts.repoPath, fromCommit, toCommit)
+		filePaths, changed, err := analysis.ListChangedFiles(opts.includeExts, func(exts ...string) ([]string, error) {
+			return git.GetCommitRangeFilesContext(ctx, opts.repoPath, fromCommit, toCommit, exts...)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get files from commit range: %w", err)
 		}
-		if len(filePaths) == 0 {
+		if !changed {
 			return nil, fmt.Errorf("no files changed in commit range %s", opts.commitID)
 		}
 		return filePaths, nil
 	}
 
-	filePaths, err := git.GetCommitDartFiles(opts.repoPath, toCommit)
+	filePaths, changed, err := analysis.ListChangedFiles(opts.includeExts, func(exts ...string) ([]string, error) {
+		return git.GetCommitFilesContext(ctx, opts.repoPath, toCommit, exts...)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get files from commit: %w", err)
 	}
-	if len(filePaths) == 0 {
-		return nil, fmt.Errorf("no files changed in commit %s", toCommit)
+	if !changed {
+		return nil, fmt.Errorf("no files changed in commit %s", opts.commitID)
 	}
 	return filePaths, nil
 }
 
-func selectContentReader(opts *graphOptions, toCommit string) vcs.ContentReader {
-	if toCommit != "" && opts.targetFile == "" {
-		return git.GitCommitContentReader(opts.repoPath, toCommit)
+// selectContentReader returns where file content is read from: the analyzed commit
+// whenever there is one, in every mode, so a commit's files are never mixed with
+// working-tree content, and the working tree otherwise.
+func selectContentReader(ctx context.Context, resources *runResources, repoPath, toCommit string) vcs.ContentReader {
+	if toCommit != "" {
+		return resources.openCommitContentReader(ctx, repoPath, toCommit)
 	}
 	return vcs.FilesystemContentReader()
 }
 
-func applyTargetFileFilter(opts *graphOptions, pathResolver PathResolver, graph depgraph.DependencyGraph, filePaths []string) (depgraph.DependencyGraph, []string, map[string]bool, error) {
+func applyTargetFileFilter(cmd *cobra.Command, opts *graphOptions, pathResolver PathResolver, graph depgraph.DependencyGraph, filePaths []string) (depgraph.DependencyGraph, []string, map[string]bool, error) {
 	if opts.targetFile == "" {
 		return graph, filePaths, nil, nil
 	}
 
-	absTargetFile, err := pathResolver.Resolve(RawPath(opts.targetFile))
+	resolver, err := newNodeResolver(cmd, opts, pathResolver, graph)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to resolve file path: %w", err)
+		return nil, nil, nil, err
 	}
-
-	if !depgraph.ContainsNode(graph, absTargetFile.String()) {
-		return nil, nil, nil, fmt.Errorf("file not found in graph: %s", opts.targetFile)
+	targetNode, err := resolver.Resolve(opts.targetFile)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	pruneSet := make(map[string]bool, len(opts.pruneFiles))
@@ -621,8 +1126,14 @@ func applyTargetFileFilter(opts *graphOptions, pathResolver PathResolver, graph
 		pruneSet[absPrunePath.String()] = true
 	}
 
-	graph, prunedNodes := filterGraphByLevel(graph, absTargetFile.String(), opts.depthLevel, opts.scope, pruneSet)
-	filePaths = graphFiles(graph)
+	graph, prunedNodes, err := filterGraphByLevel(graph, targetNode, opts.depthLevel, opts.scope, pruneSet)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	filePaths, err = graphFiles(graph)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	return graph, filePaths, prunedNodes, nil
 }
@@ -657,11 +1168,8 @@ func applyAlsoFilter(opts *graphOptions, pathResolver PathResolver, graph depgra
 		if err != nil {
 			continue
 		}
-		for _, pattern := range opts.alsoPatterns {
-			if matchAlsoPattern(pattern, relPath) {
-				candidates[node] = true
-				break
-			}
+		if opts.alsoSet.Match(relPath) {
+			candidates[node] = true
 		}
 	}
 
@@ -722,83 +1230,352 @@ func applyAlsoFilter(opts *graphOptions, pathResolver PathResolver, graph depgra
 	return newGraph, newFilePaths, nil
 }
 
-// matchAlsoPattern matches a glob pattern against a file path.
-// If the pattern contains a path separator, it matches against the full relative path.
-// Otherwise, it matches against the basename only (so *.test.ts matches at any depth).
-func matchAlsoPattern(pattern, relPath string) bool {
-	if strings.ContainsRune(pattern, filepath.Separator) || strings.Contains(pattern, "/") {
-		matched, _ := filepath.Match(pattern, relPath)
-		return matched
+// applySuppressions loads the --suppress-file rules, reports stale entries, and
+// dims or hides matching edges according to --apply-suppressions.
+// Dimmed edges are returned so they can be styled after metadata is built.
+func applySuppressions(cmd *cobra.Command, opts *graphOptions, graph depgraph.DependencyGraph, now time.Time) (depgraph.DependencyGraph, map[depgraph.FileEdge]bool, error) {
+	if opts.suppressFile == "" {
+		return graph, nil, nil
 	}
-	matched, _ := filepath.Match(pattern, filepath.Base(relPath))
-	return matched
-}
 
-func applyBetweenFilter(opts *graphOptions, pathResolver PathResolver, graph depgraph.DependencyGraph, filePaths []string) (depgraph.DependencyGraph, []string, error) {
-	if len(opts.betweenFiles) == 0 {
-		return graph, filePaths, nil
+	suppressions, err := rules.LoadSuppressions(opts.suppressFile)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	resolvedPaths, missingPaths := resolveAndValidatePaths(opts.betweenFiles, pathResolver, graph)
-	if len(missingPaths) > 0 {
-		return nil, nil, fmt.Errorf("files not found in graph: %v", missingPaths)
-	}
-	if len(resolvedPaths) < 2 {
-		return nil, nil, fmt.Errorf("at least 2 files required for --between, found %d in graph", len(resolvedPaths))
+	active, stale := suppressions.Partition(now)
+	for _, rule := range stale {
+		cliconfig.Warnf(cmd, "stale suppression %s -> %s expired on %s and is no longer applied", rule.From, rule.To, rule.Expires)
 	}
 
-	graph = depgraph.FindPathNodes(graph, resolvedPaths)
-	filePaths = graphFiles(graph)
-
-	return graph, filePaths, nil
-}
+	if opts.suppressMode == "" || len(active) == 0 {
+		return graph, nil, nil
+	}
 
-func graphFiles(graph depgraph.DependencyGraph) []string {
 	adjacency, err := depgraph.AdjacencyList(graph)
 	if err != nil {
-		return nil
+		return nil, nil, fmt.Errorf("failed to build adjacency list: %w", err)
 	}
-	filePaths := make([]string, 0, len(adjacency))
-	for f := range adjacency {
-		filePaths = append(filePaths, f)
-	}
-	return filePaths
-}
 
-func collectFileStats(cmd *cobra.Command, opts *graphOptions, format formatters.OutputFormat, fromCommit, toCommit string, isCommitRange bool) map[string]vcs.FileStats {
-	if opts.noStats {
-		return nil
+	suppressed := make(map[depgraph.FileEdge]bool)
+	for from, deps := range adjacency {
+		kept := deps[:0]
+		for _, to := range deps {
+			if _, ok := rules.Match(active, repoRelativeSlashPath(opts.repoPath, from), repoRelativeSlashPath(opts.repoPath, to)); !ok {
+				kept = append(kept, to)
+				continue
+			}
+			suppressed[depgraph.FileEdge{From: from, To: to}] = true
+			if opts.suppressMode == suppressModeDim {
+				kept = append(kept, to)
+			}
+		}
+		adjacency[from] = kept
 	}
 
-	if format != formatters.OutputFormatDOT && format != formatters.OutputFormatMermaid {
-		return nil
+	if opts.suppressMode == suppressModeDim {
+		return graph, suppressed, nil
 	}
 
-	var (
-		fileStats map[string]vcs.FileStats
-		err       error
-	)
+	filtered, err := depgraph.NewDependencyGraphFromAdjacency(adjacency)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	return filtered, nil, nil
+}
 
-	if opts.commitID != "" {
-		if isCommitRange {
-			fileStats, err = git.GetCommitRangeFileStats(opts.repoPath, fromCommit, toCommit)
-		} else {
-			fileStats, err = git.GetCommitFileStats(opts.repoPath, toCommit)
-		}
-	} else {
-		fileStats, err = git.GetUncommittedFileStats(opts.repoPath)
+// applyStyleRules loads the --style-file rules and records the matching style on
+// each file. Formatters decide whether it wins over built-in classes.
+func applyStyleRules(opts *graphOptions, fileGraph depgraph.FileDependencyGraph) error {
+	if opts.styleFile == "" {
+		return nil
 	}
 
+	styles, err := rules.LoadStyles(opts.styleFile)
 	if err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to get file statistics: %v\n", err)
-		return nil
+		return err
 	}
 
-	return fileStats
+	for node, md := range fileGraph.Meta.Files {
+		style, ok := styles.Resolve(repoRelativeSlashPath(opts.repoPath, node))
+		if !ok {
+			continue
+		}
+		md.Style = &style
+		fileGraph.Meta.Files[node] = md
+	}
+	return nil
 }
 
-func buildGraphLabel(opts *graphOptions, format formatters.OutputFormat, fromCommit, toCommit string, isCommitRange bool, filePaths []string) string {
-	if format != formatters.OutputFormatDOT && format != formatters.OutputFormatMermaid {
+// applyLineCounts records the line count of every file node, read from the content the
+// graph was built from. Files that cannot be read, such as collapsed directories, get none.
+func applyLineCounts(fileGraph depgraph.FileDependencyGraph, contentReader vcs.ContentReader) {
+	for node, md := range fileGraph.Meta.Files {
+		content, err := contentReader.ReadFile(node)
+		if err != nil {
+			continue
+		}
+		lineCount := depgraph.CountLines(content)
+		md.LineCount = &lineCount
+		fileGraph.Meta.Files[node] = md
+	}
+}
+
+// repoRelativeSlashPath returns filePath relative to repoPath using forward slashes,
+// or the slash-separated absolute path when it lies outside the repository.
+func repoRelativeSlashPath(repoPath, filePath string) string {
+	rel, err := filepath.Rel(repoPath, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// applyBetweenFilter narrows the graph to the paths between the --between files and
+// returns those paths.
+func applyBetweenFilter(cmd *cobra.Command, opts *graphOptions, pathResolver PathResolver, graph depgraph.DependencyGraph, filePaths []string) (depgraph.DependencyGraph, []string, depgraph.PathSet, error) {
+	if len(opts.betweenFiles) == 0 {
+		return graph, filePaths, depgraph.PathSet{}, nil
+	}
+
+	resolver, err := newNodeResolver(cmd, opts, pathResolver, graph)
+	if err != nil {
+		return nil, nil, depgraph.PathSet{}, err
+	}
+	resolvedPaths := make([]string, 0, len(opts.betweenFiles))
+	seen := make(map[string]bool, len(opts.betweenFiles))
+	for _, betweenFile := range opts.betweenFiles {
+		nodes, err := resolveBetweenFile(resolver, betweenFile)
+		if err != nil {
+			return nil, nil, depgraph.PathSet{}, err
+		}
+		for _, node := range nodes {
+			if !seen[node] {
+				seen[node] = true
+				resolvedPaths = append(resolvedPaths, node)
+			}
+		}
+	}
+	if len(resolvedPaths) < 2 {
+		return nil, nil, depgraph.PathSet{}, fmt.Errorf("at least 2 files required for --between, found %d in graph", len(resolvedPaths))
+	}
+
+	paths, err := depgraph.FindPaths(graph, resolvedPaths)
+	if err != nil {
+		return nil, nil, depgraph.PathSet{}, fmt.Errorf("--between: %w", err)
+	}
+	graph, err = depgraph.FindPathNodes(graph, resolvedPaths)
+	if err != nil {
+		return nil, nil, depgraph.PathSet{}, fmt.Errorf("--between: %w", err)
+	}
+	filePaths, err = graphFiles(graph)
+	if err != nil {
+		return nil, nil, depgraph.PathSet{}, err
+	}
+
+	return graph, filePaths, paths, nil
+}
+
+// isPathsTextFormat reports whether format selects the --between text summary.
+func isPathsTextFormat(format string) bool {
+	return strings.EqualFold(strings.TrimSpace(format), formatPathsText)
+}
+
+// formatPathSummary describes the paths found by --between: a headline with the path
+// count and the shortest path, then every path on its own line, shortest first.
+func formatPathSummary(repoPath string, paths depgraph.PathSet) string {
+	if len(paths.Paths) == 0 {
+		return "No paths between the selected files."
+	}
+
+	lines := make([]string, 0, len(paths.Paths)+1)
+	for _, path := range paths.Paths {
+		files := make([]string, 0, len(path))
+		for _, file := range path {
+			files = append(files, repoRelativeSlashPath(repoPath, file))
+		}
+		lines = append(lines, strings.Join(files, " -> "))
+	}
+
+	count := fmt.Sprintf("%d paths", len(paths.Paths))
+	if len(paths.Paths) == 1 {
+		count = "1 path"
+	}
+	if !paths.Complete {
+		count = "at least " + count
+	}
+	headline := fmt.Sprintf("%s, shortest length %d: %s", count, len(paths.Paths[0])-1, lines[0])
+	return headline + "\n\n" + strings.Join(lines, "\n")
+}
+
+// resolveBetweenFile returns the graph nodes named by a --between entry. Entries using
+// pattern syntax select every node whose repo-relative path matches; other entries
+// name a single file.
+func resolveBetweenFile(resolver nodeResolver, betweenFile string) ([]string, error) {
+	if !patterns.HasMeta(betweenFile) {
+		node, err := resolver.Resolve(betweenFile)
+		if err != nil {
+			return nil, err
+		}
+		return []string{node}, nil
+	}
+
+	set, err := patterns.CompileSet([]string{betweenFile})
+	if err != nil {
+		return nil, fmt.Errorf("--between: %w", err)
+	}
+	var nodes []string
+	for _, node := range resolver.nodes {
+		for _, name := range append([]string{node}, aliasPaths(resolver.aliases, node)...) {
+			if set.Match(repoRelativeSlashPath(resolver.repoPath, name)) {
+				nodes = append(nodes, node)
+				break
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no files in graph match --between pattern %s", betweenFile)
+	}
+	return nodes, nil
+}
+
+func graphFiles(graph depgraph.DependencyGraph) ([]string, error) {
+	adjacency, err := depgraph.AdjacencyList(graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list graph files: %w", err)
+	}
+	filePaths := make([]string, 0, len(adjacency))
+	for f := range adjacency {
+		filePaths = append(filePaths, f)
+	}
+	return filePaths, nil
+}
+
+// needsFileStats reports whether the run reads addition and deletion counts: only
+// formats that render them do, unless --no-stats is set.
+func needsFileStats(opts *graphOptions, format formatters.OutputFormat) bool {
+	if opts.noStats {
+		return false
+	}
+	return format == formatters.OutputFormatDOT || format == formatters.OutputFormatMermaid || format == formatters.OutputFormatJSON ||
+		format == formatters.OutputFormatHTML || format.IsImage()
+}
+
+// loadFileStats reads addition and deletion counts for the analyzed commit, range, or
+// working tree. Failures are reported as a warning and yield no stats.
+func loadFileStats(cmd *cobra.Command, opts *graphOptions, fromCommit, toCommit string, isCommitRange bool) map[string]vcs.FileStats {
+	var (
+		fileStats map[string]vcs.FileStats
+		err       error
+	)
+
+	ctx := commandContext(cmd)
+	if opts.commitID != "" {
+		if isCommitRange {
+			fileStats, err = git.GetCommitRangeFileStatsContext(ctx, opts.repoPath, fromCommit, toCommit)
+		} else {
+			fileStats, err = git.GetCommitFileStatsContext(ctx, opts.repoPath, toCommit)
+		}
+	} else {
+		fileStats, err = uncommittedFileStats(ctx, opts)
+	}
+
+	if err != nil {
+		cliconfig.Warnf(cmd, "failed to get file statistics: %v", err)
+		return nil
+	}
+
+	return fileStats
+}
+
+// uncommittedFileStats reads the line counts of the uncommitted changes in the
+// repository and in every --with-repo repository.
+func uncommittedFileStats(ctx context.Context, opts *graphOptions) (map[string]vcs.FileStats, error) {
+	fileStats := make(map[string]vcs.FileStats)
+	for _, repoPath := range append([]string{opts.repoPath}, opts.extraRepoRoots...) {
+		repoStats, err := git.GetUncommittedFileStatsWithOptionsContext(ctx, repoPath, opts.uncommittedOpts, vcs.FilesystemContentReader())
+		if err != nil {
+			return nil, err
+		}
+		for file, stats := range repoStats {
+			fileStats[file] = stats
+		}
+	}
+	return fileStats, nil
+}
+
+// needsBlobSHAs reports whether format renders per-node blob SHAs. Only JSON does;
+// the other formats identify nodes by path, so they skip the extra git invocation.
+func needsBlobSHAs(format formatters.OutputFormat) bool {
+	return format == formatters.OutputFormatJSON
+}
+
+// collectBlobSHAs returns the blob SHA of each analyzed file using a single git
+// invocation: the commit tree for commit analyses, or the working tree otherwise.
+// Files outside the repository path get no entry.
+func collectBlobSHAs(cmd *cobra.Command, opts *graphOptions, format formatters.OutputFormat, toCommit string, filePaths []string) map[string]string {
+	if !needsBlobSHAs(format) {
+		return nil
+	}
+
+	var (
+		blobSHAs map[string]string
+		err      error
+	)
+	if opts.commitID != "" {
+		blobSHAs, err = git.GetCommitBlobSHAsContext(commandContext(cmd), opts.repoPath, toCommit)
+	} else {
+		blobSHAs, err = git.GetWorkingTreeBlobSHAsContext(commandContext(cmd), opts.repoPath, filePaths)
+	}
+	if err != nil {
+		cliconfig.Warnf(cmd, "failed to get blob SHAs: %v", err)
+		return nil
+	}
+	return blobSHAs
+}
+
+// collectNodeLinks returns the --links URL of each analyzed file: the file at the
+// analyzed commit, or at HEAD without --commit, on the source hosting of the origin
+// remote unless --link-template names another. Files outside the repository get none.
+func collectNodeLinks(ctx context.Context, opts *graphOptions, toCommit string, filePaths []string) (map[string]string, error) {
+	if !opts.links {
+		return nil, nil
+	}
+
+	repoPath := opts.repoPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+	template := opts.linkTemplate
+	if template == "" {
+		remoteURL, err := git.GetRemoteURL(repoPath, "origin")
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate node links: %w (use --link-template)", err)
+		}
+		template, err = formatters.RemoteLinkTemplate(remoteURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate node links: %w (use --link-template)", err)
+		}
+	}
+
+	commit := "HEAD"
+	if opts.commitID != "" {
+		commit = toCommit
+	}
+	commitHash, err := git.GetCommitHash(repoPath, commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate node links: %w", err)
+	}
+	repoRoot, err := git.GetRepositoryRootContext(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate node links: %w", err)
+	}
+	return formatters.NodeLinks(template, commitHash, repoRoot, filePaths), nil
+}
+
+func buildGraphLabel(ctx context.Context, opts *graphOptions, format formatters.OutputFormat, fromCommit, toCommit string, isCommitRange bool, filePaths []string, contentReader vcs.ContentReader) string {
+	if format != formatters.OutputFormatDOT && format != formatters.OutputFormatMermaid && format != formatters.OutputFormatJSON &&
+		format != formatters.OutputFormatHTML && !format.IsImage() {
 		return ""
 	}
 
@@ -807,7 +1584,7 @@ func buildGraphLabel(opts *graphOptions, format formatters.OutputFormat, fromCom
 		labelRepoPath = "."
 	}
 
-	label := fmt.Sprintf("%s • ", repoLabelName(labelRepoPath))
+	label := fmt.Sprintf("%s • ", repoLabelName(labelRepoPath, contentReader))
 	var err error
 
 	var commitLabel string
@@ -818,7 +1595,7 @@ func buildGraphLabel(opts *graphOptions, format formatters.OutputFormat, fromCom
 			commitLabel, err = git.GetShortCommitHash(labelRepoPath, toCommit)
 		}
 	} else {
-		commitLabel, err = git.GetCurrentCommitHash(labelRepoPath)
+		commitLabel, err = git.GetCurrentCommitHashContext(ctx, labelRepoPath)
 	}
 
 	if err != nil {
@@ -827,7 +1604,7 @@ func buildGraphLabel(opts *graphOptions, format formatters.OutputFormat, fromCom
 
 	label += commitLabel
 	if opts.commitID == "" {
-		isDirty, err := git.HasUncommittedChanges(labelRepoPath)
+		isDirty, err := git.HasUncommittedChangesWithOptions(labelRepoPath, opts.uncommittedOpts)
 		if err == nil && isDirty {
 			label += "-dirty"
 		}
@@ -843,8 +1620,28 @@ func buildGraphLabel(opts *graphOptions, format formatters.OutputFormat, fromCom
 	return label
 }
 
-func repoLabelName(repoPath string) string {
-	if moduleName := goModuleLabelName(repoPath); moduleName != "" {
+// buildGraphMetadata records the repository, commit, and command-line flags of the
+// run for --metadata. The formatter adds the file count and content hash.
+func buildGraphMetadata(cmd *cobra.Command, opts *graphOptions, fromCommit, toCommit string, isCommitRange bool, contentReader vcs.ContentReader) metadata.Metadata {
+	repoPath := opts.repoPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	commit := metadata.WorkingTree
+	if isCommitRange {
+		commit = fromCommit + ".." + toCommit
+	} else if opts.commitID != "" {
+		commit = toCommit
+	}
+	return metadata.New(repoLabelName(repoPath, contentReader), commit, metadata.CommandFlags(cmd.Flags()))
+}
+
+// repoLabelName names the repository after the module in its root go.mod, read with
+// contentReader so commits of bare repositories are named too, or else after its
+// root directory.
+func repoLabelName(repoPath string, contentReader vcs.ContentReader) string {
+	if moduleName := goModuleLabelName(repoPath, contentReader); moduleName != "" {
 		return moduleName
 	}
 
@@ -855,8 +1652,8 @@ func repoLabelName(repoPath string) string {
 	return name
 }
 
-func goModuleLabelName(repoPath string) string {
-	content, err := os.ReadFile(filepath.Join(repoPath, "go.mod"))
+func goModuleLabelName(repoPath string, contentReader vcs.ContentReader) string {
+	content, err := contentReader.ReadFile(filepath.Join(repoPath, "go.mod"))
 	if err != nil {
 		return ""
 	}
@@ -897,67 +1694,207 @@ func modulePathLabel(modulePath string) string {
 func emitOutput(cmd *cobra.Command, opts *graphOptions, format formatters.OutputFormat, formatter formatters.Formatter, output string) error {
 	if opts.generateURL {
 		if urlStr, ok := formatter.GenerateURL(output); ok {
-			fmt.Fprintln(cmd.OutOrStdout(), urlStr)
-		} else {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: URL generation is not supported for %s format\n\n", format)
-			fmt.Fprintln(cmd.OutOrStdout(), output)
+			if err := writeOutput(cmd, opts, urlStr); err != nil {
+				return err
+			}
+			if opts.outputPath != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Wrote visualization URL to %s\n", opts.outputPath)
+			}
+			return nil
 		}
-	} else {
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+		cliconfig.Warnf(cmd, "URL generation is not supported for %s format\n", format)
 	}
 
+	if format.IsImage() {
+		return writeImageOutput(cmd, opts, output)
+	}
+	return writeOutput(cmd, opts, output)
+}
+
+// writeOutput writes output to the --output file, or to stdout when none is set.
+func writeOutput(cmd *cobra.Command, opts *graphOptions, output string) error {
+	if opts.outputPath != "" {
+		if err := WriteOutputFile(opts.outputPath, []byte(output+"\n"), opts.force); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), output)
 	return nil
 }
 
+// writeImageOutput writes a rendered image to the --output file, or to stdout when
+// none is set, byte for byte.
+func writeImageOutput(cmd *cobra.Command, opts *graphOptions, image string) error {
+	if opts.outputPath != "" {
+		if err := WriteOutputFile(opts.outputPath, []byte(image), opts.force); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+
+	_, err := io.WriteString(cmd.OutOrStdout(), image)
+	return err
+}
+
 func applyIncludeExtensionFilter(opts *graphOptions, filePaths []string) ([]string, error) {
 	if len(opts.includeExts) == 0 {
 		return filePaths, nil
 	}
 
-	includedExts := make(map[string]struct{}, len(opts.includeExts))
-	for _, ext := range opts.includeExts {
-		includedExts[ext] = struct{}{}
+	filtered := analysis.KeepExtensions(filePaths, opts.includeExts)
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no files remain after applying --include-ext %q", opts.includeExt)
 	}
 
-	filtered := make([]string, 0, len(filePaths))
-	for _, filePath := range filePaths {
-		if _, ok := includedExts[strings.ToLower(filepath.Ext(filePath))]; ok {
-			filtered = append(filtered, filePath)
-		}
+	return filtered, nil
+}
+
+func applyExcludeExtensionFilter(opts *graphOptions, filePaths []string) ([]string, error) {
+	if len(opts.excludeExts) == 0 {
+		return filePaths, nil
 	}
 
+	filtered := analysis.DropExtensions(filePaths, opts.excludeExts)
 	if len(filtered) == 0 {
-		return nil, fmt.Errorf("no files remain after applying --include-ext %q", opts.includeExt)
+		return nil, fmt.Errorf("no files remain after applying --exclude-ext %q", opts.excludeExt)
 	}
 
 	return filtered, nil
 }
 
-func applyExcludeExtensionFilter(opts *graphOptions, filePaths []string) ([]string, error) {
-	if len(opts.excludeExts) == 0 {
+// applyTransitiveReduction drops edges implied by longer paths when --transitive-reduction is set.
+func applyTransitiveReduction(cmd *cobra.Command, opts *graphOptions, graph depgraph.DependencyGraph) (depgraph.DependencyGraph, error) {
+	if !opts.reduce {
+		return graph, nil
+	}
+
+	result, err := depgraph.ReduceTransitiveEdges(graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reduce graph: %w", err)
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Transitive reduction removed %d edge(s)", len(result.RemovedEdges))
+	if len(result.CyclicComponents) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "; edges inside %d cycle(s) were left untouched", len(result.CyclicComponents))
+	}
+	fmt.Fprintln(cmd.ErrOrStderr())
+
+	return result.Graph, nil
+}
+
+// applyFanInCollapse removes the incoming edges of files imported by more than
+// --collapse-threshold files. It returns the number of importers of each collapsed
+// file when --collapse-mode is badge, and nil otherwise.
+func applyFanInCollapse(cmd *cobra.Command, opts *graphOptions, graph depgraph.DependencyGraph) (depgraph.DependencyGraph, map[string]int, error) {
+	if opts.collapseLimit == 0 {
+		return graph, nil, nil
+	}
+
+	result, err := depgraph.CollapseHighFanIn(graph, opts.collapseLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to collapse high fan-in files: %w", err)
+	}
+	if len(result.Dependents) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Collapsed %d file(s) imported by more than %d files, removing %d edge(s)\n",
+			len(result.Dependents), opts.collapseLimit, len(result.RemovedEdges))
+	}
+
+	if opts.collapseMode == collapseModeHide {
+		return result.Graph, nil, nil
+	}
+	return result.Graph, result.Dependents, nil
+}
+
+// applyRenderLimit collapses files by directory when the graph has more nodes than
+// --render-limit, at the deepest depth that fits. It returns the applied depth, or 0
+// when the graph was left as-is.
+func applyRenderLimit(cmd *cobra.Command, opts *graphOptions, format formatters.OutputFormat, graph depgraph.DependencyGraph, basePath string) (depgraph.DependencyGraph, int, error) {
+	// JSON is read by tools, not people, so it always carries every file.
+	if format == formatters.OutputFormatJSON {
+		return graph, 0, nil
+	}
+	nodes, err := graphFiles(graph)
+	if err != nil {
+		return nil, 0, err
+	}
+	if opts.renderLimit == 0 || len(nodes) <= opts.renderLimit {
+		return graph, 0, nil
+	}
+
+	depth, fits := depgraph.CollapseDepthForLimit(nodes, basePath, opts.renderLimit)
+	collapsed, err := depgraph.CollapseByDirectory(graph, basePath, depth)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collapse graph: %w", err)
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Graph has %d nodes, more than --render-limit %d; collapsed files to directory depth %d (%d nodes)",
+		len(nodes), opts.renderLimit, depth, depgraph.CollapsedNodeCount(nodes, basePath, depth))
+	if !fits {
+		fmt.Fprint(cmd.ErrOrStderr(), ", still over the limit")
+	}
+	fmt.Fprintln(cmd.ErrOrStderr())
+	fmt.Fprintln(cmd.ErrOrStderr(), "Hint: use --render-limit 0 for the full graph, or narrow it with --input, --file or --exclude")
+
+	return collapsed, depth, nil
+}
+
+// applyPresetFilter drops build outputs of detected project types unless --no-preset is set.
+func applyPresetFilter(cmd *cobra.Command, opts *graphOptions, pathResolver PathResolver, filePaths []string, contentReader vcs.ContentReader) ([]string, error) {
+	if opts.noPreset {
 		return filePaths, nil
 	}
 
-	excludedExts := make(map[string]struct{}, len(opts.excludeExts))
-	for _, ext := range opts.excludeExts {
-		excludedExts[ext] = struct{}{}
+	explicitIncludes := make([]string, 0, len(opts.includes))
+	for _, include := range opts.includes {
+		resolvedInclude, err := pathResolver.Resolve(RawPath(include))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve input path %q: %w", include, err)
+		}
+		explicitIncludes = append(explicitIncludes, filepath.Clean(resolvedInclude.String()))
+	}
+
+	filtered, result := applyProjectPresets(opts.repoPath, filePaths, explicitIncludes, contentReader)
+	if result.excludedFiles > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Applied %s preset: excluded %d build output file(s) (use --no-preset to disable)\n",
+			strings.Join(result.presets, "+"), result.excludedFiles)
+	}
+	if len(filtered) == 0 && result.excludedFiles > 0 {
+		return nil, fmt.Errorf("no files remain after applying the %s preset (use --no-preset to disable)", strings.Join(result.presets, "+"))
+	}
+
+	return filtered, nil
+}
+
+// applyGeneratedFilter drops vendored files and files with a generated-code header
+// when --hide-generated is set. Headers are read through contentReader, so commits
+// are checked as committed.
+func applyGeneratedFilter(opts *graphOptions, filePaths []string, contentReader vcs.ContentReader) ([]string, error) {
+	if !opts.hideGenerated {
+		return filePaths, nil
 	}
 
 	filtered := make([]string, 0, len(filePaths))
 	for _, filePath := range filePaths {
-		if _, ok := excludedExts[strings.ToLower(filepath.Ext(filePath))]; ok {
+		// Only directories inside the repository count as vendor directories.
+		relPath := repoRelativeSlashPath(opts.repoPath, filePath)
+		vendored := !filepath.IsAbs(filepath.FromSlash(relPath)) && depgraph.IsVendoredPath(relPath)
+		if vendored || depgraph.IsGeneratedFile(filePath, contentReader) {
 			continue
 		}
 		filtered = append(filtered, filePath)
 	}
 
 	if len(filtered) == 0 {
-		return nil, fmt.Errorf("no files remain after applying --exclude-ext %q", opts.excludeExt)
+		return nil, fmt.Errorf("no files remain after applying --hide-generated")
 	}
-
 	return filtered, nil
 }
 
+// applyExcludePathFilter drops the files named by --exclude. Values using pattern
+// syntax are matched against repo-relative paths; other values are literal files or
+// directories.
 func applyExcludePathFilter(opts *graphOptions, pathResolver PathResolver, filePaths []string) ([]string, error) {
 	if len(opts.excludes) == 0 {
 		return filePaths, nil
@@ -965,11 +1902,15 @@ func applyExcludePathFilter(opts *graphOptions, pathResolver PathResolver, fileP
 
 	excludedPaths := make([]string, 0, len(opts.excludes))
 	for _, exclude := range opts.excludes {
+		if patterns.HasMeta(exclude) {
+			continue
+		}
 		resolvedExclude, err := pathResolver.Resolve(RawPath(exclude))
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve exclude path %q: %w", exclude, err)
 		}
-		excludedPaths = append(excludedPaths, resolveSymlinks(filepath.Clean(resolvedExclude.String())))
+		excludedPath := moduleapi.CanonicalPath(opts.pathAliases, filepath.Clean(resolvedExclude.String()))
+		excludedPaths = append(excludedPaths, resolveSymlinks(excludedPath))
 	}
 
 	filtered := make([]string, 0, len(filePaths))
@@ -978,6 +1919,9 @@ func applyExcludePathFilter(opts *graphOptions, pathResolver PathResolver, fileP
 		if isPathExcluded(cleanPath, excludedPaths) {
 			continue
 		}
+		if matchesExcludeGlob(opts, pathResolver, filePath) {
+			continue
+		}
 		filtered = append(filtered, filePath)
 	}
 
@@ -988,6 +1932,48 @@ func applyExcludePathFilter(opts *graphOptions, pathResolver PathResolver, fileP
 	return filtered, nil
 }
 
+// matchesExcludeGlob reports whether an --exclude glob matches filePath, or a path
+// it is reachable by through a symlink.
+func matchesExcludeGlob(opts *graphOptions, pathResolver PathResolver, filePath string) bool {
+	for _, name := range append([]string{filePath}, aliasPaths(opts.pathAliases, filePath)...) {
+		if relPath, ok := repoRelativeGlobPath(pathResolver.BaseDir(), name); ok && opts.excludeSet.Match(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyIncludeGlobFilter keeps the files whose repo-relative path matches --include-glob.
+// It runs after the exclude filters, so a file matched by both is excluded.
+func applyIncludeGlobFilter(opts *graphOptions, pathResolver PathResolver, filePaths []string) ([]string, error) {
+	if len(opts.includeGlobs) == 0 {
+		return filePaths, nil
+	}
+
+	filtered := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if relPath, ok := repoRelativeGlobPath(pathResolver.BaseDir(), filePath); ok && opts.includeSet.Match(relPath) {
+			filtered = append(filtered, filePath)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no files remain after applying --include-glob %q", strings.Join(opts.includeGlobs, ","))
+	}
+
+	return filtered, nil
+}
+
+// repoRelativeGlobPath returns filePath relative to baseDir for pattern matching. Files
+// outside baseDir match no pattern.
+func repoRelativeGlobPath(baseDir, filePath string) (string, bool) {
+	rel, err := filepath.Rel(baseDir, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
 func isPathExcluded(filePath string, excludedPaths []string) bool {
 	for _, excludedPath := range excludedPaths {
 		if filePath == excludedPath {
@@ -1003,9 +1989,12 @@ func isPathExcluded(filePath string, excludedPaths []string) bool {
 
 // expandPaths expands file paths and directories into individual file paths.
 // Directories are recursively walked and regular files are included based on includeUnsupportedFiles.
-// For directories inside a git repository, git ls-files is used to respect .gitignore rules.
-func expandPaths(paths []string, includeUnsupportedFiles bool) ([]string, error) {
+// For directories inside a git repository, git ls-files is used to respect .gitignore rules
+// (and .git/info/exclude). The walk options control ignored files, symlinked directories
+// and the file-count limit.
+func expandPaths(paths []string, includeUnsupportedFiles bool, walk walkOptions) ([]string, error) {
 	var result []string
+	walker := newDirectoryWalker(walk)
 
 	for _, path := range paths {
 		info, err := os.Stat(path)
@@ -1014,10 +2003,20 @@ func expandPaths(paths []string, includeUnsupportedFiles bool) ([]string, error)
 		}
 
 		if info.IsDir() {
-			files, err := listGitFiles(path)
-			if err != nil {
-				// Not a git repo or git not available; fall back to walk
-				files, err = walkDirectoryFiles(path)
+			var files []string
+			listed := false
+			if !walk.noGitignore {
+				files, err = listGitFiles(path)
+				listed = err == nil
+			}
+			if listed {
+				err = walker.checkLimit(path, len(files))
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				// Not a git repo, git not available, or --no-gitignore; fall back to walk
+				files, err = walker.walk(path)
 				if err != nil {
 					return nil, fmt.Errorf("failed to walk directory %s: %w", path, err)
 				}
@@ -1087,43 +2086,19 @@ func gitLsFiles(dir string, args ...string) ([]string, error) {
 	return paths, nil
 }
 
-var walkSkippedDirs = map[string]bool{
-	".git":         true,
-	"node_modules": true,
-	"target":       true,
-	".dart_tool":   true,
-	"build":        true,
-	"__pycache__":  true,
-	".gradle":      true,
-	".idea":        true,
-	".vscode":      true,
-}
-
-// walkDirectoryFiles is a fallback for non-git directories.
-func walkDirectoryFiles(dir string) ([]string, error) {
-	var result []string
-	err := filepath.Walk(dir, func(filePath string, fileInfo os.FileInfo, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
-		if fileInfo.IsDir() {
-			if walkSkippedDirs[fileInfo.Name()] {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		result = append(result, filePath)
-		return nil
-	})
-	return result, err
-}
-
-func emitUnsupportedFileWarning(filePaths []string) {
+// emitUnsupportedFileWarning warns when dependency extraction does not support some of
+// filePaths, naming their extensions.
+func emitUnsupportedFileWarning(cmd *cobra.Command, filePaths []string, contentReader vcs.ContentReader) {
 	unsupportedCount := 0
 	unsupportedByExt := make(map[string]bool)
+	detector := depgraph.NewLanguageDetector(contentReader)
 
 	for _, filePath := range filePaths {
-		ext := filepath.Ext(filePath)
+		// Manifests such as go.mod are nodes in their own right; they are never parsed for imports.
+		if depgraph.IsDependencyManifest(filePath) {
+			continue
+		}
+		ext := detector.Extension(filePath)
 		if registry.IsSupportedLanguageExtension(ext) {
 			continue
 		}
@@ -1146,64 +2121,96 @@ func emitUnsupportedFileWarning(filePaths []string) {
 	}
 	sort.Strings(unsupportedExts)
 
-	slog.Debug("dependency extraction is unsupported for some files; rendering standalone nodes without dependency edges",
-		"unsupported_file_count", unsupportedCount,
-		"unsupported_extensions", unsupportedExts)
+	cliconfig.Warnf(cmd, "dependency extraction is unsupported for %d file(s) (%s); they are shown as standalone nodes without dependency edges",
+		unsupportedCount, strings.Join(unsupportedExts, ", "))
 }
 
-// resolveAndValidatePaths resolves file paths to absolute paths and validates they exist in the graph.
-// Returns the list of resolved paths that exist in the graph and the list of paths that were not found.
-func resolveAndValidatePaths(paths []string, pathResolver PathResolver, graph depgraph.DependencyGraph) (resolved []string, missing []string) {
-	for _, p := range paths {
-		absPath, err := pathResolver.Resolve(RawPath(p))
-		if err != nil {
-			missing = append(missing, p)
-			continue
-		}
+// parseCache returns the cache show reuses parse results from, or nil when --no-cache
+// is set or there is no user cache directory.
+func parseCache(opts *graphOptions) *depgraph.ParseCache {
+	if opts.noCache {
+		return nil
+	}
+	dir, err := depgraph.DefaultParseCacheDir()
+	if err != nil {
+		slog.Debug("parse cache disabled", "error", err)
+		return nil
+	}
+	return depgraph.NewParseCache(dir, depgraph.DefaultParseCacheMaxBytes)
+}
 
-		if depgraph.ContainsNode(graph, absPath.String()) {
-			resolved = append(resolved, absPath.String())
-		} else {
-			missing = append(missing, p)
+// logDiagnostics logs notable imports found while building the graph, such as
+// relative imports that cross package boundaries.
+func logDiagnostics(logger *slog.Logger, diagnostics []moduleapi.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		logger.Debug("import diagnostic",
+			"category", diagnostic.Category,
+			"file", diagnostic.File,
+			"target", diagnostic.Target,
+			"linked", diagnostic.Linked)
+	}
+}
+
+// addHeuristicEdges adds best-effort edges for unsupported files and reports the
+// import statements that could not be resolved to a supplied file: a warning counts
+// them per file, and --verbose lists each statement.
+func addHeuristicEdges(cmd *cobra.Command, opts *graphOptions, graph depgraph.DependencyGraph, filePaths []string, contentReader vcs.ContentReader) error {
+	report, err := depgraph.AddHeuristicEdges(graph, filePaths, contentReader)
+	if err != nil {
+		return fmt.Errorf("failed to extract best-effort edges: %w", err)
+	}
+
+	logger := cliconfig.Logger(cmd)
+	files := make([]string, 0, len(report.Unclassified))
+	for file := range report.Unclassified {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	total := 0
+	counts := make([]string, 0, len(files))
+	for _, file := range files {
+		relPath := repoRelativeSlashPath(opts.repoPath, file)
+		for _, imp := range report.Unclassified[file] {
+			logger.Info("best-effort edges: import not classified",
+				"file", relPath,
+				"statement", imp.Statement)
 		}
+		total += len(report.Unclassified[file])
+		counts = append(counts, fmt.Sprintf("%s: %d", relPath, len(report.Unclassified[file])))
+	}
+	if total > 0 {
+		cliconfig.Warnf(cmd, "best-effort edges: %d import statement(s) could not be classified (%s); pass --verbose to list them",
+			total, strings.Join(counts, ", "))
 	}
-	return
+
+	for _, file := range report.CappedFiles {
+		logger.Warn("best-effort edges: per-file limit reached; remaining imports ignored",
+			"file", repoRelativeSlashPath(opts.repoPath, file),
+			"limit", depgraph.MaxHeuristicEdgesPerFile)
+	}
+
+	return nil
 }
 
 // filterGraphByLevel filters the dependency graph to include only nodes within
-// the specified number of levels from the target file, according to scope.
+// the specified number of levels from the target file, according to scope: along
+// dependencies (downstream), along dependents (upstream), or both.
 // A level of 0 means unlimited traversal depth.
 // Nodes in pruneSet are included in the graph but their subtrees are not traversed.
 // Returns the filtered graph and the set of pruned nodes that were actually visited.
-func filterGraphByLevel(graph depgraph.DependencyGraph, targetFile string, level int, scope string, pruneSet map[string]bool) (depgraph.DependencyGraph, map[string]bool) {
+func filterGraphByLevel(graph depgraph.DependencyGraph, targetFile string, level int, scope string, pruneSet map[string]bool) (depgraph.DependencyGraph, map[string]bool, error) {
 	adjacency, err := depgraph.AdjacencyList(graph)
 	if err != nil {
-		return depgraph.NewDependencyGraph(), nil
+		return nil, nil, fmt.Errorf("failed to filter graph around %s: %w", targetFile, err)
 	}
 
-	// BFS to find all nodes within the specified level (or all reachable nodes when level=0)
 	visited := make(map[string]bool)
 	visited[targetFile] = true
-
-	currentLevel := []string{targetFile}
-	for l := 0; (level == 0 || l < level) && len(currentLevel) > 0; l++ {
-		nextLevel := []string{}
-		for _, file := range currentLevel {
-			// Pruned nodes stay in the graph but their subtrees are not explored.
-			if pruneSet[file] {
-				continue
-			}
-			if scope == scopeDownstream {
-				// Add direct dependencies (files this file imports).
-				for _, dep := range adjacency[file] {
-					if !visited[dep] {
-						visited[dep] = true
-						nextLevel = append(nextLevel, dep)
-					}
-				}
-			}
-		}
-		currentLevel = nextLevel
+	if scope == scopeDownstream || scope == scopeBoth {
+		walkLevels(adjacency, targetFile, level, pruneSet, visited)
+	}
+	if scope == scopeUpstream || scope == scopeBoth {
+		walkLevels(reverseAdjacency(adjacency), targetFile, level, pruneSet, visited)
 	}
 
 	// Build filtered graph with only visited nodes
@@ -1227,5 +2234,45 @@ func filterGraphByLevel(graph depgraph.DependencyGraph, targetFile string, level
 		}
 	}
 
-	return depgraph.MustDependencyGraph(filtered), actuallyPruned
+	return depgraph.MustDependencyGraph(filtered), actuallyPruned, nil
+}
+
+// walkLevels marks in visited the nodes reachable from start along adjacency within
+// level steps (all of them when level is 0), without expanding nodes in pruneSet.
+// Each walk keeps its own frontier, so a node reached by an earlier walk is still
+// expanded.
+func walkLevels(adjacency map[string][]string, start string, level int, pruneSet map[string]bool, visited map[string]bool) {
+	seen := map[string]bool{start: true}
+	currentLevel := []string{start}
+	for l := 0; (level == 0 || l < level) && len(currentLevel) > 0; l++ {
+		nextLevel := []string{}
+		for _, file := range currentLevel {
+			// Pruned nodes stay in the graph but their subtrees are not explored.
+			if pruneSet[file] {
+				continue
+			}
+			for _, next := range adjacency[file] {
+				if !seen[next] {
+					seen[next] = true
+					visited[next] = true
+					nextLevel = append(nextLevel, next)
+				}
+			}
+		}
+		currentLevel = nextLevel
+	}
+}
+
+// reverseAdjacency maps each file to the files that depend on it.
+func reverseAdjacency(adjacency map[string][]string) map[string][]string {
+	reversed := make(map[string][]string, len(adjacency))
+	for source, deps := range adjacency {
+		for _, dep := range deps {
+			reversed[dep] = append(reversed[dep], source)
+		}
+	}
+	for _, sources := range reversed {
+		sort.Strings(sources)
+	}
+	return reversed
 }
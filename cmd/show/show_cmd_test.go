@@ -2,14 +2,22 @@ package show
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
 	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
 func TestGraphInputDirectory_WithJavaFiles_RendersDependencyEdges(t *testing.T) {
@@ -89,6 +97,422 @@ func TestGraphInput_WithMJSFiles_RendersDependencyEdges(t *testing.T) {
 	}
 }
 
+func TestGraphInput_BestEffortEdges_LinksVueFileToTypeScript(t *testing.T) {
+	repoDir := t.TempDir()
+	vueFile := filepath.Join(repoDir, "App.vue")
+	storeFile := filepath.Join(repoDir, "store.ts")
+
+	vueContent := `<script setup lang="ts">
+import { useStore } from './store'
+import { ref } from 'vue'
+</script>
+`
+	if err := os.WriteFile(vueFile, []byte(vueContent), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(storeFile, []byte("export function useStore() {}\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	args := []string{"-r", repoDir, "-i", vueFile + "," + storeFile, "-f", "dot", "--allow-outside-repo"}
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(stdout.String(), "->") {
+		t.Fatalf("expected no edges without --best-effort-edges, got:\n%s", stdout.String())
+	}
+
+	cmd = NewCommand()
+	cmd.SetArgs(append(args, "--best-effort-edges"))
+	stdout.Reset()
+	cmd.SetOut(&stdout)
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, `"App.vue" -> "store.ts" [color=gray, style=dashed];`) {
+		t.Fatalf("expected dashed heuristic edge App.vue -> store.ts, got:\n%s", output)
+	}
+	// The bare 'vue' import names no supplied file.
+	if !strings.Contains(stderr.String(), "best-effort edges: 1 import statement(s) could not be classified (App.vue: 1)") {
+		t.Fatalf("expected the unclassified import to be reported, got:\n%s", stderr.String())
+	}
+}
+
+func TestGraphInput_ApplySuppressions(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	suppressFile := filepath.Join(repoDir, "suppressions.yml")
+	suppressContent := `suppressions:
+  - from: "main.go"
+    to: "legacy/**"
+    reason: "grandfathered"
+  - from: "main.go"
+    to: "util/**"
+    reason: "expired shortcut"
+    expires: 2000-01-01
+`
+	if err := os.WriteFile(suppressFile, []byte(suppressContent), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	run := func(extraArgs ...string) (string, string) {
+		t.Helper()
+		cmd := NewCommand()
+		args := []string{"-r", repoDir, "-i", ".", "-f", "dot", "--suppress-file", suppressFile}
+		cmd.SetArgs(append(args, extraArgs...))
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute() error = %v", err)
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	output, stderr := run("--apply-suppressions")
+	if !strings.Contains(output, `"main.go" -> "legacy/db.go" [color=gray80, fontcolor=gray80];`) {
+		t.Fatalf("expected suppressed edge to be dimmed, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"main.go" -> "util/util.go";`) {
+		t.Fatalf("expected stale suppression not to apply, got:\n%s", output)
+	}
+	if !strings.Contains(stderr, "stale suppression main.go -> util/** expired on 2000-01-01") {
+		t.Fatalf("expected stale suppression warning, got:\n%s", stderr)
+	}
+
+	output, _ = run("--apply-suppressions=hide")
+	if strings.Contains(output, `"main.go" -> "legacy/db.go"`) {
+		t.Fatalf("expected suppressed edge to be hidden, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"legacy/db.go"`) {
+		t.Fatalf("expected hidden edge to keep its nodes, got:\n%s", output)
+	}
+}
+
+func TestGraphInput_ApplySuppressions_RequiresSuppressFile(t *testing.T) {
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"--apply-suppressions"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--apply-suppressions requires --suppress-file") {
+		t.Fatalf("expected --suppress-file requirement error, got %v", err)
+	}
+}
+
+func TestGraphInput_StyleFile_ColorsMatchingNodes(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	styleFile := filepath.Join(repoDir, "styles.yml")
+	styleContent := `styles:
+  - match: "legacy/**"
+    fill: "#cccccc"
+    class: legacy
+`
+	if err := os.WriteFile(styleFile, []byte(styleContent), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	stdout, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--style-file", styleFile)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(stdout, `"legacy/db.go" [label="db.go", style=filled, fillcolor="#cccccc", class="legacy"];`) {
+		t.Fatalf("expected legacy node to use the custom style, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, `"util/util.go" [label="util.go", style=filled, fillcolor="#cccccc"`) {
+		t.Fatalf("expected unmatched node to keep its built-in style, got:\n%s", stdout)
+	}
+}
+
+func TestGraphInput_StyleFile_InvalidColorReportsPosition(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	styleFile := filepath.Join(repoDir, "styles.yml")
+	if err := os.WriteFile(styleFile, []byte("styles:\n  - match: \"legacy/**\"\n    fill: gold\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	_, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "--style-file", styleFile)
+	if err == nil || !strings.Contains(err.Error(), `line 3, column 11: style 1: invalid fill "gold"`) {
+		t.Fatalf("expected invalid color error with position, got %v", err)
+	}
+}
+
+func TestGraphInput_EdgeSymbols_AnnotatesEdgesWithUsedSymbols(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--no-stats", "--edge-symbols")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"main.go" -> "legacy/db.go" [tooltip="Open"]`) {
+		t.Fatalf("expected used symbol in dot tooltip, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "mermaid", "--no-stats", "--edge-symbols")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "-->|Do|") {
+		t.Fatalf("expected used symbol in mermaid edge label, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--no-stats")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(output, "tooltip") {
+		t.Fatalf("expected no tooltips without --edge-symbols, got:\n%s", output)
+	}
+}
+
+func TestGraphInput_EdgeWeightUsage_WeighsEdgesByUsageCount(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--no-stats", "--edge-weight", "usage")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"main.go" -> "legacy/db.go" [penwidth=1.00]`) {
+		t.Fatalf("expected usage weight as dot pen width, got:\n%s", output)
+	}
+
+	_, _, err = runShow(t, nil, "--edge-weight", "calls")
+	if err == nil || !strings.Contains(err.Error(), "unknown edge weight: calls (valid options: usage)") {
+		t.Fatalf("expected unknown edge weight error, got %v", err)
+	}
+}
+
+func TestGraphCommit_Links_PointNodesAtOriginFilesAtCommit(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeSuppressionTestRepo(t, repoDir)
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	gitRun(t, repoDir, "remote", "add", "origin", "git@github.com:org/app.git")
+	out, err := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse error = %v", err)
+	}
+	sha := strings.TrimSpace(string(out))
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "mermaid", "--no-stats", "--links")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if want := `"https://github.com/org/app/blob/` + sha + `/legacy/db.go"`; !strings.Contains(output, want) {
+		t.Fatalf("expected click line with %s, got:\n%s", want, output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot", "--no-stats", "--links",
+		"--link-template", "https://git.example.com/app/src/{commit}/{path}")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if want := `URL="https://git.example.com/app/src/` + sha + `/main.go"`; !strings.Contains(output, want) {
+		t.Fatalf("expected node URL %s, got:\n%s", want, output)
+	}
+
+	_, _, err = runShow(t, nil, "--link-template", "https://git.example.com/{path}")
+	if err == nil || !strings.Contains(err.Error(), "--link-template requires --links") {
+		t.Fatalf("expected --link-template without --links error, got %v", err)
+	}
+}
+
+func TestGraphCommit_LinkManifests_DrawsFilesToTheirManifest(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeSuppressionTestRepo(t, repoDir)
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	for name, content := range map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.22\n",
+		"main.go":      "package main\n\nimport \"example.com/app/util\"\n\nfunc main() { util.Do() }\n",
+		"util/util.go": "package util\n\nfunc Do() { _ = 1 }\n",
+	} {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "bump go")
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-f", "json", "-c", "HEAD", "--no-stats", "--link-manifests")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	var graph formatters.JSONGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+
+	kinds := make(map[string]string)
+	for _, node := range graph.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+	if len(kinds) != 3 || kinds["go.mod"] != "manifest" || kinds["main.go"] != "" || kinds["util/util.go"] != "" {
+		t.Fatalf("expected go.mod as the only manifest of 3 nodes, got %v", kinds)
+	}
+	var manifestEdges []string
+	for _, edge := range graph.Edges {
+		if edge.Provenance == string(depgraph.EdgeProvenanceManifest) {
+			manifestEdges = append(manifestEdges, edge.From+" -> "+edge.To)
+		}
+	}
+	if strings.Join(manifestEdges, ", ") != "main.go -> go.mod, util/util.go -> go.mod" {
+		t.Fatalf("expected manifest edges from both .go files, got %v", manifestEdges)
+	}
+}
+
+func TestGraphCommit_Metadata_RecordsCommitFlagsAndHash(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeSuppressionTestRepo(t, repoDir)
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	out, err := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse error = %v", err)
+	}
+	sha := strings.TrimSpace(string(out))
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-f", "json", "-c", "HEAD", "--no-stats", "--metadata")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	var graph formatters.JSONGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+
+	meta := graph.Meta
+	if meta == nil {
+		t.Fatalf("expected a meta object, got %s", output)
+	}
+	if meta.Version != "dev" || meta.Repo != "app" || meta.Commit != sha || meta.Files != len(graph.Nodes) {
+		t.Fatalf("unexpected metadata %+v for %d nodes at %s", meta, len(graph.Nodes), sha)
+	}
+	wantFlags := []string{"--commit=HEAD", "--format=json", "--metadata=true", "--no-stats=true", "--repo=" + repoDir}
+	if strings.Join(meta.Flags, " ") != strings.Join(wantFlags, " ") {
+		t.Fatalf("expected flags %v, got %v", wantFlags, meta.Flags)
+	}
+
+	again, _, err := runShow(t, nil, "-r", repoDir, "-f", "dot", "-c", "HEAD", "--no-stats", "--metadata")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(again, "// content-hash: "+meta.ContentHash) {
+		t.Fatalf("expected the dot output to repeat content hash %s, got\n%s", meta.ContentHash, again)
+	}
+}
+
+func TestGraphCommit_GoModule_ResolvesImportsWithoutGoMod(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	for name, content := range map[string]string{
+		"cmd/app/main.go":   "package main\n\nimport \"github.com/acme/monorepo/store\"\n\nfunc main() { store.Open() }\n",
+		"store/store.go":    "package store\n\nfunc Open() {}\n",
+		"store/BUILD.bazel": "go_library(name = \"store\")\n",
+	} {
+		path := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+
+	edges := func(args ...string) []string {
+		t.Helper()
+		output, _, err := runShow(t, nil, append([]string{"-r", repoDir, "-f", "json", "-c", "HEAD", "--no-stats", "--include-ext", ".go"}, args...)...)
+		if err != nil {
+			t.Fatalf("cmd.Execute() error = %v", err)
+		}
+		var graph formatters.JSONGraph
+		if err := json.Unmarshal([]byte(output), &graph); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+		}
+		var edges []string
+		for _, edge := range graph.Edges {
+			edges = append(edges, edge.From+" -> "+edge.To)
+		}
+		return edges
+	}
+
+	if got := edges(); len(got) != 0 {
+		t.Fatalf("expected no edges without go.mod or --go-module, got %v", got)
+	}
+	if got := strings.Join(edges("--go-module", "github.com/acme/monorepo=."), ", "); got != "cmd/app/main.go -> store/store.go" {
+		t.Fatalf("expected --go-module to resolve the store import, got %v", got)
+	}
+}
+
+func TestParseGoModules(t *testing.T) {
+	repoDir := filepath.FromSlash("/repo")
+	modules, err := parseGoModules(repoDir, []string{"github.com/acme/monorepo=.", "github.com/acme/lib/=third_party/lib", "example.com/abs=" + filepath.FromSlash("/opt/abs")})
+	if err != nil {
+		t.Fatalf("parseGoModules() error = %v", err)
+	}
+	want := map[string]string{
+		"github.com/acme/monorepo": repoDir,
+		"github.com/acme/lib":      filepath.Join(repoDir, "third_party", "lib"),
+		"example.com/abs":          filepath.FromSlash("/opt/abs"),
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("parseGoModules() = %v, want %v", modules, want)
+	}
+	for prefix, dir := range want {
+		if modules[prefix] != dir {
+			t.Fatalf("parseGoModules()[%q] = %q, want %q", prefix, modules[prefix], dir)
+		}
+	}
+
+	for _, invalid := range [][]string{{"github.com/acme/monorepo"}, {"=."}, {"github.com/acme/monorepo="}, {"a.com/x=one", "a.com/x=two"}} {
+		if _, err := parseGoModules(repoDir, invalid); err == nil || !strings.Contains(err.Error(), "invalid --go-module") {
+			t.Fatalf("parseGoModules(%q) error = %v, want an invalid --go-module error", invalid, err)
+		}
+	}
+}
+
+func writeSuppressionTestRepo(t *testing.T, repoDir string) {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.21\n",
+		"main.go":      "package main\n\nimport (\n\t\"example.com/app/legacy\"\n\t\"example.com/app/util\"\n)\n\nfunc main() { legacy.Open(); util.Do() }\n",
+		"legacy/db.go": "package legacy\n\nfunc Open() {}\n",
+		"util/util.go": "package util\n\nfunc Do() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+}
+
 func TestGraphCommit_WithJavaFiles_RendersDependencyEdges(t *testing.T) {
 	repoDir := t.TempDir()
 	gitInitRepo(t, repoDir)
@@ -183,6 +607,97 @@ func TestGraphCommit_WithInput_UsesCommitTreePaths(t *testing.T) {
 	}
 }
 
+func TestGraphCommit_GoModDeletedInWorkingTree_ResolvesFromCommit(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+
+	files := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.22\n",
+		"main.go":      "package main\n\nimport \"example.com/app/util\"\n\nfunc main() { util.Run() }\n",
+		"util/util.go": "package util\n\nfunc Run() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add go module")
+
+	if err := os.Remove(filepath.Join(repoDir, "go.mod")); err != nil {
+		t.Fatalf("os.Remove() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-i", ".", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"main.go" -> "util/util.go"`) {
+		t.Fatalf("expected module import edge from the commit's go.mod, got:\n%s", output)
+	}
+}
+
+// writeCommittedThenEditedRepo commits a.ts importing b.ts, plus c.ts, and then edits
+// a.ts in the working tree to import c.ts instead.
+func writeCommittedThenEditedRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	for name, content := range map[string]string{
+		"a.ts": "import { b } from './b';\nexport const a = b;\n",
+		"b.ts": "export const b = 1;\n",
+		"c.ts": "export const c = 1;\n",
+	} {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add a, b and c")
+
+	edited := "import { c } from './c';\nexport const a = c;\n"
+	if err := os.WriteFile(filepath.Join(repoDir, "a.ts"), []byte(edited), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	return repoDir
+}
+
+func TestGraphCommit_WithFile_ReadsCommitContentNotWorkingTree(t *testing.T) {
+	repoDir := writeCommittedThenEditedRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-p", "a.ts", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"a.ts" -> "b.ts"`) {
+		t.Fatalf("expected the committed import of b.ts, got:\n%s", output)
+	}
+	if strings.Contains(output, `"c.ts"`) {
+		t.Fatalf("expected the working-tree import of c.ts to be ignored, got:\n%s", output)
+	}
+}
+
+func TestGraphCommit_WithInput_ReadsCommitContentNotWorkingTree(t *testing.T) {
+	repoDir := writeCommittedThenEditedRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-i", ".", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"a.ts" -> "b.ts"`) {
+		t.Fatalf("expected the committed import of b.ts, got:\n%s", output)
+	}
+	if strings.Contains(output, `"a.ts" -> "c.ts"`) {
+		t.Fatalf("expected the working-tree import of c.ts to be ignored, got:\n%s", output)
+	}
+}
+
 func TestGraphInput_WithSupportedFiles_RendersNode(t *testing.T) {
 	repoDir := t.TempDir()
 	supportedFile := filepath.Join(repoDir, "main.go")
@@ -205,7 +720,23 @@ func TestGraphInput_WithSupportedFiles_RendersNode(t *testing.T) {
 	}
 }
 
-func TestGraphInput_WithJSONFormat_ReturnsError(t *testing.T) {
+func TestGraphInput_ManifestsAreNotReportedAsUnsupported(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--no-stats")
+	if err != nil {
+		t.Fatalf("show error = %v", err)
+	}
+	if !strings.Contains(output, `"go.mod"`) {
+		t.Fatalf("expected a go.mod node, got:\n%s", output)
+	}
+	if strings.Contains(stderr, "unsupported") {
+		t.Fatalf("expected no unsupported-file warning for go.mod, got:\n%s", stderr)
+	}
+}
+
+func TestGraphInput_WithJSONFormat_KeysNodesByFileName(t *testing.T) {
 	repoDir := t.TempDir()
 	supportedFile := filepath.Join(repoDir, "main.go")
 	if err := os.WriteFile(supportedFile, []byte("package main\n"), 0o644); err != nil {
@@ -214,25 +745,96 @@ func TestGraphInput_WithJSONFormat_ReturnsError(t *testing.T) {
 
 	cmd := NewCommand()
 	cmd.SetArgs([]string{"-i", supportedFile, "-f", "json", "--allow-outside-repo"})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
 
-	err := cmd.Execute()
-	if err == nil {
-		t.Fatalf("cmd.Execute() expected error for json format, got nil")
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
 	}
-	if !strings.Contains(err.Error(), "unknown format: json (valid options: dot, mermaid)") {
-		t.Fatalf("expected unknown format error including input value, got: %v", err)
+	if !strings.Contains(stdout.String(), `"id": "main.go"`) {
+		t.Fatalf("expected main.go node keyed by file name, got:\n%s", stdout.String())
 	}
 }
 
-func TestGraphInput_Exclude_RemovesSpecificFile(t *testing.T) {
+func TestGraphInput_WithUnknownFormat_ReturnsError(t *testing.T) {
 	repoDir := t.TempDir()
-	goFile := filepath.Join(repoDir, "main.go")
-	javaFile := filepath.Join(repoDir, "Helper.java")
-
-	if err := os.WriteFile(goFile, []byte("package main\n"), 0o644); err != nil {
-		t.Fatalf("os.WriteFile() error = %v", err)
-	}
-	if err := os.WriteFile(javaFile, []byte("public class Helper {}\n"), 0o644); err != nil {
+	supportedFile := filepath.Join(repoDir, "main.go")
+	if err := os.WriteFile(supportedFile, []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"-i", supportedFile, "-f", "yaml", "--allow-outside-repo"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatalf("cmd.Execute() expected error for yaml format, got nil")
+	}
+	if !strings.Contains(err.Error(), "unknown format: yaml (valid options: dot, mermaid, json, html, svg, png)") {
+		t.Fatalf("expected unknown format error including input value, got: %v", err)
+	}
+}
+
+func TestGraphInputStdin_ReadsNewlineDelimitedPaths(t *testing.T) {
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"main.go":      "package main\n",
+		"a,b.go":       "package main\n",
+		"unrelated.go": "package main\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	stdin := strings.NewReader("# changed files\nmain.go\n\n  a,b.go  \n")
+	output, _, err := runShow(t, stdin, "-r", repoDir, "--input-stdin", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	for _, want := range []string{`"main.go"`, `"a,b.go"`} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected graph output to include %s node, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "unrelated.go") {
+		t.Fatalf("expected only stdin paths in graph output, got:\n%s", output)
+	}
+}
+
+func TestGraphInputStdin_EmptyInput_ReturnsError(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	_, _, err := runShow(t, strings.NewReader("# nothing changed\n\n"), "-r", repoDir, "--input-stdin", "-f", "dot")
+	if err == nil {
+		t.Fatalf("expected error for empty stdin")
+	}
+	if !strings.Contains(err.Error(), "no files found in specified paths") {
+		t.Fatalf("expected no files error, got: %v", err)
+	}
+}
+
+func TestGraphInputStdin_WithFile_ReturnsError(t *testing.T) {
+	_, _, err := runShow(t, strings.NewReader("main.go\n"), "--input-stdin", "-p", "main.go")
+	if err == nil || !strings.Contains(err.Error(), "--file cannot be used with --input-stdin flag") {
+		t.Fatalf("expected --file conflict error, got: %v", err)
+	}
+}
+
+func TestGraphInput_Exclude_RemovesSpecificFile(t *testing.T) {
+	repoDir := t.TempDir()
+	goFile := filepath.Join(repoDir, "main.go")
+	javaFile := filepath.Join(repoDir, "Helper.java")
+
+	if err := os.WriteFile(goFile, []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(javaFile, []byte("public class Helper {}\n"), 0o644); err != nil {
 		t.Fatalf("os.WriteFile() error = %v", err)
 	}
 
@@ -323,6 +925,120 @@ func TestGraphInput_Exclude_AllFiles_ReturnsError(t *testing.T) {
 	}
 }
 
+// writeGitignoredRepo creates a git repository with main.go and generated/types.go,
+// which .gitignore ignores.
+func writeGitignoredRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	for name, content := range map[string]string{
+		".gitignore":         "generated/\n",
+		"main.go":            "package main\n",
+		"generated/types.go": "package generated\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestGraphInput_Directory_SkipsGitignoredFiles(t *testing.T) {
+	repoDir := writeGitignoredRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", repoDir, "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"main.go"`) {
+		t.Fatalf("expected graph output to include main.go node, got:\n%s", output)
+	}
+	if strings.Contains(output, "types.go") {
+		t.Fatalf("expected graph output to skip the ignored types.go, got:\n%s", output)
+	}
+}
+
+func TestGraphInput_Directory_NoGitignoreKeepsIgnoredFiles(t *testing.T) {
+	repoDir := writeGitignoredRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", repoDir, "-f", "dot", "--no-gitignore")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"main.go"`) || !strings.Contains(output, "types.go") {
+		t.Fatalf("expected graph output to include main.go and the ignored types.go, got:\n%s", output)
+	}
+	if strings.Contains(output, ".git/") {
+		t.Fatalf("expected graph output to skip the .git directory, got:\n%s", output)
+	}
+}
+
+func TestGraphInput_ClusterDir_GroupsPackages(t *testing.T) {
+	repoDir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.21\n",
+		"api/handler.go":   "package api\n\nimport \"example.com/app/store\"\n\nvar Handler = store.DB\n",
+		"store/db.go":      "package store\n\nvar DB = 1\n",
+		"store/db_test.go": "package store\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", "api,store", "-f", "dot", "--cluster", "dir")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"subgraph \"cluster_0\" {\n    label=\"api\";\n    \"api/handler.go\";\n  }",
+		"subgraph \"cluster_1\" {\n    label=\"store\";\n    \"store/db.go\";\n    \"store/db_test.go\";\n  }",
+		`"api/handler.go" -> "store/db.go";`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraph_ClusterRejectsUnknownMode(t *testing.T) {
+	_, _, err := runShow(t, nil, "--cluster", "package")
+	if err == nil || err.Error() != "unknown cluster mode: package (valid options: dir)" {
+		t.Fatalf("expected unknown cluster mode error, got %v", err)
+	}
+}
+
+func TestApplyIncludeGlobFilter_MatchesWindowsSeparators(t *testing.T) {
+	baseDir := t.TempDir()
+	includeSet, err := patterns.CompileSet([]string{"src/**/*.ts"})
+	if err != nil {
+		t.Fatalf("CompileSet() error = %v", err)
+	}
+
+	// Relative paths are matched with forward slashes whatever the platform separator.
+	if !includeSet.Match(`src\app\index.ts`) {
+		t.Fatalf("expected a backslash-separated path to match")
+	}
+	relPath, ok := repoRelativeGlobPath(baseDir, filepath.Join(baseDir, "src", "app", "index.ts"))
+	if !ok || relPath != "src/app/index.ts" {
+		t.Fatalf("repoRelativeGlobPath() = %q, %t", relPath, ok)
+	}
+	if _, ok := repoRelativeGlobPath(baseDir, filepath.Join(filepath.Dir(baseDir), "outside.ts")); ok {
+		t.Fatalf("expected a path outside the base directory not to be matched")
+	}
+}
+
 func TestGraphInput_IncludeExt_KeepsOnlyMatchingExtension(t *testing.T) {
 	repoDir := t.TempDir()
 	goFile := filepath.Join(repoDir, "main.go")
@@ -581,6 +1297,77 @@ func TestGraphBetweenRelativePaths_WithRepo_ResolvesFromRepoRoot(t *testing.T) {
 	}
 }
 
+// writeDiamondRepo creates a.ts importing b.ts and c.ts, which both import d.ts, and
+// d.ts importing b.ts back, plus e.ts, which nothing imports.
+func writeDiamondRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	files := map[string]string{
+		"a.ts": "import { b } from './b';\nimport { c } from './c';\nexport const a = b + c;\n",
+		"b.ts": "import { d } from './d';\nexport const b = d;\n",
+		"c.ts": "import { d } from './d';\nexport const c = d;\n",
+		"d.ts": "import { b } from './b';\nexport const d = 1;\nexport const e = b;\n",
+		"e.ts": "export const e = 1;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestGraphBetween_DropsEdgesOffPaths(t *testing.T) {
+	repoDir := writeDiamondRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-w", "a.ts,d.ts", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"a.ts" -> "b.ts"`) || !strings.Contains(output, `"c.ts" -> "d.ts"`) {
+		t.Fatalf("expected both diamond paths, got:\n%s", output)
+	}
+	if strings.Contains(output, `"d.ts" -> "b.ts"`) {
+		t.Fatalf("expected the back edge from d.ts to be dropped, got:\n%s", output)
+	}
+}
+
+func TestGraphBetween_TextFormatSummarizesPaths(t *testing.T) {
+	repoDir := writeDiamondRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-w", "a.ts,d.ts", "-f", "text")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	want := "2 paths, shortest length 2: a.ts -> b.ts -> d.ts\n\na.ts -> b.ts -> d.ts\na.ts -> c.ts -> d.ts\n"
+	if output != want {
+		t.Fatalf("output = %q, want %q", output, want)
+	}
+}
+
+func TestGraphBetween_TextFormatDisconnectedFiles(t *testing.T) {
+	repoDir := writeDiamondRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-w", "a.ts,e.ts", "-f", "text")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if output != "No paths between the selected files.\n" {
+		t.Fatalf("output = %q, want no paths", output)
+	}
+}
+
+func TestGraph_TextFormatRequiresBetween(t *testing.T) {
+	_, _, err := runShow(t, nil, "-f", "text")
+	if err == nil || err.Error() != "--format text requires --between flag" {
+		t.Fatalf("expected --between requirement error, got %v", err)
+	}
+}
+
 func TestGraphFileRelativePath_WithRepo_ResolvesFromRepoRoot(t *testing.T) {
 	repoDir := t.TempDir()
 	targetRelativePath := filepath.Join("pkg", "main.go")
@@ -716,6 +1503,98 @@ func TestGraphFile_InvalidScope_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestFilterGraphByLevel_DiamondByScope(t *testing.T) {
+	// top imports left and right, which both import bottom
+	diamond := map[string][]string{
+		"top":    {"left", "right"},
+		"left":   {"bottom"},
+		"right":  {"bottom"},
+		"bottom": nil,
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		scope  string
+		level  int
+		want   map[string][]string
+	}{
+		{
+			name: "downstream from top", target: "top", scope: scopeDownstream, level: 0,
+			want: diamond,
+		},
+		{
+			name: "downstream from left", target: "left", scope: scopeDownstream, level: 0,
+			want: map[string][]string{"left": {"bottom"}, "bottom": nil},
+		},
+		{
+			name: "upstream from bottom", target: "bottom", scope: scopeUpstream, level: 0,
+			want: diamond,
+		},
+		{
+			name: "upstream from bottom at level 1", target: "bottom", scope: scopeUpstream, level: 1,
+			want: map[string][]string{"left": {"bottom"}, "right": {"bottom"}, "bottom": nil},
+		},
+		{
+			name: "upstream from left", target: "left", scope: scopeUpstream, level: 0,
+			want: map[string][]string{"top": {"left"}, "left": nil},
+		},
+		{
+			name: "both from left", target: "left", scope: scopeBoth, level: 0,
+			want: map[string][]string{"top": {"left"}, "left": {"bottom"}, "bottom": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, _, err := filterGraphByLevel(depgraph.MustDependencyGraph(diamond), tt.target, tt.level, tt.scope, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := depgraph.AdjacencyList(filtered)
+			if err != nil {
+				t.Fatalf("AdjacencyList() error = %v", err)
+			}
+			for node, deps := range got {
+				sort.Strings(deps)
+				if len(deps) == 0 {
+					got[node] = nil
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("filterGraphByLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphFile_UpstreamScope_ShowsDependentsPointingAtTarget(t *testing.T) {
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"a.ts": "import { b } from './b';\nexport const a = b;\n",
+		"b.ts": "export const b = 1;\n",
+		"x.ts": "import { a } from './a';\nexport const x = a;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-p", "a.ts", "--scope", "upstream", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"x.ts" -> "a.ts"`) {
+		t.Fatalf("expected dependent x.ts with its edge into a.ts, got:\n%s", output)
+	}
+	if strings.Contains(output, `"b.ts"`) {
+		t.Fatalf("expected upstream scope to exclude dependency b.ts, got:\n%s", output)
+	}
+}
+
 func TestGraphFile_PruneStopsTraversal(t *testing.T) {
 	repoDir := t.TempDir()
 	aFile := filepath.Join(repoDir, "a.ts")
@@ -835,7 +1714,7 @@ func TestRepoLabelName_UsesGoModuleNameWhenPresent(t *testing.T) {
 		t.Fatalf("os.WriteFile() error = %v", err)
 	}
 
-	got := repoLabelName(repoDir)
+	got := repoLabelName(repoDir, vcs.FilesystemContentReader())
 	if got != "clarity" {
 		t.Fatalf("repoLabelName() = %q, want %q", got, "clarity")
 	}
@@ -847,7 +1726,7 @@ func TestRepoLabelName_FallsBackToRootDirectoryName(t *testing.T) {
 		t.Fatalf("os.MkdirAll() error = %v", err)
 	}
 
-	got := repoLabelName(repoDir)
+	got := repoLabelName(repoDir, vcs.FilesystemContentReader())
 	if got != "my-service" {
 		t.Fatalf("repoLabelName() = %q, want %q", got, "my-service")
 	}
@@ -871,7 +1750,7 @@ func TestBuildGraphLabel_UsesGoModuleNamePrefix(t *testing.T) {
 	gitRun(t, repoDir, "add", ".")
 	gitRun(t, repoDir, "commit", "-m", "add main.go")
 
-	label := buildGraphLabel(&graphOptions{repoPath: repoDir}, formatters.OutputFormatMermaid, "", "", false, []string{filePath})
+	label := buildGraphLabel(context.Background(), &graphOptions{repoPath: repoDir}, formatters.OutputFormatMermaid, "", "", false, []string{filePath}, vcs.FilesystemContentReader())
 
 	if !strings.HasPrefix(label, "clarity • ") {
 		t.Fatalf("buildGraphLabel() = %q, want prefix %q", label, "clarity • ")
@@ -881,6 +1760,69 @@ func TestBuildGraphLabel_UsesGoModuleNamePrefix(t *testing.T) {
 	}
 }
 
+// writeBareCloneRepo commits a two-package Go module and returns the path of a bare
+// clone of it.
+func writeBareCloneRepo(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	repoDir := filepath.Join(baseDir, "src")
+	files := map[string]string{
+		"go.mod":       "module github.com/acme/shop\n\ngo 1.25\n",
+		"cmd/main.go":  "package main\n\nimport \"github.com/acme/shop/cart\"\n\nfunc main() { cart.New() }\n",
+		"cart/cart.go": "package cart\n\nfunc New() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitInitRepo(t, repoDir)
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add shop")
+	gitRun(t, baseDir, "clone", "--bare", "src", "shop.git")
+	return filepath.Join(baseDir, "shop.git")
+}
+
+func TestGraphCommit_BareRepository_ReadsFromGitObjects(t *testing.T) {
+	bareDir := writeBareCloneRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", bareDir, "-c", "HEAD", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"cmd/main.go" -> "cart/cart.go"`) {
+		t.Fatalf("expected edge resolved through go.mod in the commit tree, got:\n%s", output)
+	}
+	if !strings.Contains(output, `label="shop • `) {
+		t.Fatalf("expected label named after the committed go.mod module, got:\n%s", output)
+	}
+}
+
+func TestGraphCommit_BareRepository_WithFile(t *testing.T) {
+	bareDir := writeBareCloneRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", bareDir, "-c", "HEAD", "-p", "cmd/main.go", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"cmd/main.go" -> "cart/cart.go"`) {
+		t.Fatalf("expected dependencies of cmd/main.go, got:\n%s", output)
+	}
+}
+
+func TestGraph_BareRepositoryWithoutCommit_ReturnsError(t *testing.T) {
+	bareDir := writeBareCloneRepo(t)
+
+	_, _, err := runShow(t, nil, "-r", bareDir, "-f", "dot")
+	if err == nil || !strings.Contains(err.Error(), "is a bare repository with no working tree: pass --commit") {
+		t.Fatalf("expected bare repository error, got: %v", err)
+	}
+}
+
 func gitInitRepo(t *testing.T, repoDir string) {
 	t.Helper()
 
@@ -1033,6 +1975,39 @@ func TestGraphFile_Also_MultiplePatterns(t *testing.T) {
 	}
 }
 
+func TestGraphFile_Also_LaterNegationWins(t *testing.T) {
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"a.ts":      "export const a = 1;\n",
+		"a.test.ts": "import { a } from './a';\nconsole.log(a);\n",
+		"a.spec.ts": "import { a } from './a';\nconsole.log(a);\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-p", "a.ts", "-l", "0", "--also", "*.*.ts,!*.spec.ts", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"a.test.ts"`) {
+		t.Fatalf("expected a.test.ts in output, got:\n%s", output)
+	}
+	if strings.Contains(output, `"a.spec.ts"`) {
+		t.Fatalf("expected a.spec.ts to be excluded by the later negation, got:\n%s", output)
+	}
+}
+
+func TestGraphFile_Also_MalformedPattern_ReturnsError(t *testing.T) {
+	_, _, err := runShow(t, nil, "-p", "a.ts", "--also", "[a-")
+	if err == nil || !strings.Contains(err.Error(), `--also: invalid pattern "[a-" at offset 0`) {
+		t.Fatalf("expected malformed pattern error, got %v", err)
+	}
+}
+
 func TestGraphFile_Also_IncludesConnectedTestFiles_ExcludesUnconnected(t *testing.T) {
 	repoDir := t.TempDir()
 
@@ -1120,3 +2095,874 @@ func gitRun(t *testing.T, repoDir string, args ...string) {
 		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
 	}
 }
+
+func TestGraphInput_TransitiveReduction_DropsImpliedEdge(t *testing.T) {
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"a.ts": "import { b } from './b';\nimport { c } from './c';\nexport const a = b + c;\n",
+		"b.ts": "import { c } from './c';\nexport const b = c;\n",
+		"c.ts": "export const c = 1;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--transitive-reduction")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if strings.Contains(output, `"a.ts" -> "c.ts"`) {
+		t.Fatalf("expected implied edge a.ts -> c.ts to be removed, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"a.ts" -> "b.ts"`) || !strings.Contains(output, `"b.ts" -> "c.ts"`) {
+		t.Fatalf("expected path a.ts -> b.ts -> c.ts to remain, got:\n%s", output)
+	}
+	if !strings.Contains(stderr, "Transitive reduction removed 1 edge(s)") {
+		t.Fatalf("expected reduction summary, got stderr:\n%s", stderr)
+	}
+}
+
+func TestGraphInput_RenderLimit_CollapsesByDirectory(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	files := map[string]string{
+		"main.ts":       "import { a } from './lib/a';\nimport { b } from './lib/b';\nexport const main = a + b;\n",
+		"lib/a.ts":      "import { c } from './core/c';\nexport const a = c;\n",
+		"lib/b.ts":      "export const b = 1;\n",
+		"lib/core/c.ts": "import { d } from './d';\nexport const c = d;\n",
+		"lib/core/d.ts": "export const d = 1;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--render-limit", "4")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"lib/a.ts" -> "lib/core"`) {
+		t.Fatalf("expected lib/core to be collapsed into one node, got:\n%s", output)
+	}
+	if strings.Contains(output, "c.ts") || strings.Contains(output, "d.ts") {
+		t.Fatalf("expected files under lib/core to be hidden, got:\n%s", output)
+	}
+	if !strings.Contains(output, "auto-collapsed to depth 2") {
+		t.Fatalf("expected collapse depth in label, got:\n%s", output)
+	}
+	if !strings.Contains(stderr, "Graph has 5 nodes, more than --render-limit 4; collapsed files to directory depth 2 (4 nodes)") {
+		t.Fatalf("expected collapse note, got stderr:\n%s", stderr)
+	}
+	if !strings.Contains(stderr, "--render-limit 0") {
+		t.Fatalf("expected hint for the full graph, got stderr:\n%s", stderr)
+	}
+}
+
+func TestGraphInput_RenderLimitZero_KeepsFullGraph(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, "lib"), 0o755); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "main.ts"), []byte("import { a } from './lib/a';\nexport const main = a;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "lib", "a.ts"), []byte("export const a = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--render-limit", "0")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"main.ts" -> "lib/a.ts"`) {
+		t.Fatalf("expected full graph, got:\n%s", output)
+	}
+	if strings.Contains(stderr, "collapsed") {
+		t.Fatalf("expected no collapse, got stderr:\n%s", stderr)
+	}
+}
+
+func TestGraph_NegativeRenderLimit_ReturnsError(t *testing.T) {
+	_, _, err := runShow(t, nil, "--render-limit", "-1")
+	if err == nil || !strings.Contains(err.Error(), "--render-limit must be at least 0") {
+		t.Fatalf("expected --render-limit validation error, got %v", err)
+	}
+}
+
+// writeFanInRepo writes three TypeScript files that all import log.ts.
+func writeFanInRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"a.ts":   "import { log } from './log';\nexport const a = log;\n",
+		"b.ts":   "import { log } from './log';\nimport { a } from './a';\nexport const b = log + a;\n",
+		"c.ts":   "import { log } from './log';\nexport const c = log;\n",
+		"log.ts": "export const log = 1;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestGraphInput_CollapseThreshold_BadgesHighFanInFile(t *testing.T) {
+	repoDir := writeFanInRepo(t)
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--collapse-threshold", "2")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if strings.Contains(output, `-> "log.ts"`) {
+		t.Fatalf("expected the incoming edges of log.ts to be removed, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"b.ts" -> "a.ts"`) {
+		t.Fatalf("expected other edges to remain, got:\n%s", output)
+	}
+	if !strings.Contains(output, `imported by 3 files`) {
+		t.Fatalf("expected a badge on log.ts, got:\n%s", output)
+	}
+	if !strings.Contains(stderr, "Collapsed 1 file(s) imported by more than 2 files, removing 3 edge(s)") {
+		t.Fatalf("expected collapse summary, got stderr:\n%s", stderr)
+	}
+}
+
+func TestGraphInput_CollapseThreshold_HideModeDropsBadge(t *testing.T) {
+	repoDir := writeFanInRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--collapse-threshold", "2", "--collapse-mode", "hide")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if strings.Contains(output, `-> "log.ts"`) || strings.Contains(output, "imported by") {
+		t.Fatalf("expected log.ts without incoming edges or badge, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"log.ts" [label="log.ts"`) {
+		t.Fatalf("expected log.ts to stay in the graph, got:\n%s", output)
+	}
+}
+
+func TestGraphInput_CollapseThreshold_AtHubDegreeKeepsEdges(t *testing.T) {
+	repoDir := writeFanInRepo(t)
+
+	output, stderr, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--collapse-threshold", "3")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"a.ts" -> "log.ts"`) || strings.Contains(output, "imported by") {
+		t.Fatalf("expected the full graph, got:\n%s", output)
+	}
+	if strings.Contains(stderr, "Collapsed") {
+		t.Fatalf("expected no collapse, got stderr:\n%s", stderr)
+	}
+}
+
+func TestGraph_UnknownCollapseMode_ReturnsError(t *testing.T) {
+	_, _, err := runShow(t, nil, "--collapse-mode", "fold")
+	if err == nil || !strings.Contains(err.Error(), "unknown --collapse-mode: fold") {
+		t.Fatalf("expected --collapse-mode validation error, got %v", err)
+	}
+}
+
+func TestGraphInput_MelosCrossPackageRelativeImport(t *testing.T) {
+	repoDir := t.TempDir()
+	copyDir(t, filepath.Join("..", "..", "depgraph", "languages", "dart", "testdata", "melos"), repoDir)
+	gitInitRepo(t, repoDir)
+
+	crossPackageEdge := `"packages/a/test/a_test.dart" -> "packages/b/test/utils/helpers.dart"`
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", "packages", "-f", "dot")
+	if err != nil {
+		t.Fatalf("unscoped run error = %v", err)
+	}
+	if !strings.Contains(output, crossPackageEdge) {
+		t.Fatalf("expected cross-package edge in unscoped run, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"packages/b/test/utils/helpers.dart" -> "packages/b/lib/b.dart"`) {
+		t.Fatalf("expected helper's own dependency in unscoped run, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-i", filepath.Join("packages", "a"), "-f", "dot")
+	if err != nil {
+		t.Fatalf("scoped run error = %v", err)
+	}
+	if strings.Contains(output, "helpers.dart") {
+		t.Fatalf("expected scoped run to leave package b out, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"packages/a/test/a_test.dart" -> "packages/a/lib/a.dart"`) {
+		t.Fatalf("expected in-package edge in scoped run, got:\n%s", output)
+	}
+}
+
+func copyDir(t *testing.T, src, dst string) {
+	t.Helper()
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0o644)
+	})
+	if err != nil {
+		t.Fatalf("failed to copy %s: %v", src, err)
+	}
+}
+
+func TestGraphCommitRange_ThreeDotOnDivergedBranches_DiffsFromMergeBase(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	gitRun(t, repoDir, "checkout", "-q", "-b", "main")
+	writeFiles := func(files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+				t.Fatalf("os.WriteFile() error = %v", err)
+			}
+		}
+		gitRun(t, repoDir, "add", ".")
+	}
+	writeFiles(map[string]string{"shared.go": "package app\n"})
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	gitRun(t, repoDir, "checkout", "-q", "-b", "feature")
+	writeFiles(map[string]string{"feature.go": "package app\n"})
+	gitRun(t, repoDir, "commit", "-m", "add feature")
+	gitRun(t, repoDir, "checkout", "-q", "main")
+	writeFiles(map[string]string{"mainline.go": "package app\n", "shared.go": "package app\n\nvar Changed = true\n"})
+	gitRun(t, repoDir, "commit", "-m", "change main")
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-f", "dot", "-c", "main...feature")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, `"feature.go"`) {
+		t.Fatalf("expected feature.go in graph, got:\n%s", output)
+	}
+	for _, unwanted := range []string{"mainline.go", "shared.go"} {
+		if strings.Contains(output, unwanted) {
+			t.Fatalf("expected %s, changed only on main, to be left out, got:\n%s", unwanted, output)
+		}
+	}
+
+	out, err := exec.Command("git", "-C", repoDir, "rev-parse", "--short", "main~1").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse error = %v", err)
+	}
+	mergeBase := strings.TrimSpace(string(out))
+	if !strings.Contains(output, mergeBase+"...") {
+		t.Fatalf("expected label to show merge-base %s, got:\n%s", mergeBase, output)
+	}
+}
+
+func TestGraphCommitRange_JSON_SerializesNodesEdgesAndStats(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeSuppressionTestRepo(t, repoDir)
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	if err := os.WriteFile(filepath.Join(repoDir, "util", "util.go"), []byte("package util\n\nfunc Do() { Helper() }\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "util", "helper.go"), []byte("package util\n\nfunc Helper() {}\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add helper")
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-f", "json", "-c", "HEAD~1...HEAD")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	var graph formatters.JSONGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+
+	if graph.SchemaVersion != formatters.OutputSchemaVersion || !strings.Contains(graph.Label, "2 files") {
+		t.Fatalf("unexpected header: version %d, label %q", graph.SchemaVersion, graph.Label)
+	}
+	nodes := make(map[string]formatters.JSONNode)
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+	helper, ok := nodes["util/helper.go"]
+	if !ok || helper.Path != filepath.Join(repoDir, "util", "helper.go") || helper.Extension != ".go" {
+		t.Fatalf("unexpected helper node %+v in %+v", helper, graph.Nodes)
+	}
+	if helper.Stats == nil || !helper.Stats.IsNew || helper.Stats.Additions != 3 {
+		t.Fatalf("expected new-file stats for helper.go, got %+v", helper.Stats)
+	}
+	if len(helper.BlobSHA) != 40 {
+		t.Fatalf("expected blob SHA for helper.go, got %q", helper.BlobSHA)
+	}
+	util := nodes["util/util.go"]
+	if util.Stats == nil || util.Stats.Additions != 1 || util.Stats.Deletions != 1 {
+		t.Fatalf("expected +1 -1 stats for util.go, got %+v", util.Stats)
+	}
+	wantEdge := formatters.JSONEdge{From: "util/util.go", To: "util/helper.go", Provenance: "parsed", Symbols: []string{"Helper"}}
+	if len(graph.Edges) != 1 || !reflect.DeepEqual(graph.Edges[0], wantEdge) {
+		t.Fatalf("edges = %+v, want [%+v]", graph.Edges, wantEdge)
+	}
+}
+
+func TestGraphFile_JSON_AppliesFileFilter(t *testing.T) {
+	repoDir := t.TempDir()
+	writeSuppressionTestRepo(t, repoDir)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-p", "main.go", "-f", "json", "--no-stats")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	var graph formatters.JSONGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+
+	var ids []string
+	for _, node := range graph.Nodes {
+		ids = append(ids, node.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"legacy/db.go", "main.go", "util/util.go"}) {
+		t.Fatalf("nodes = %v, want [legacy/db.go main.go util/util.go]", ids)
+	}
+	if len(graph.Edges) != 2 || graph.Edges[0].From != "main.go" || graph.Edges[0].To != "legacy/db.go" {
+		t.Fatalf("edges = %+v, want main.go -> legacy/db.go, util/util.go", graph.Edges)
+	}
+}
+
+func TestGraph_CachedRunMatchesFreshBuild(t *testing.T) {
+	t.Setenv(depgraph.CacheDirEnv, t.TempDir())
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.22\n",
+		"main.go":      "package main\n\nimport \"example.com/app/util\"\n\nfunc main() { util.Helper() }\n",
+		"util/util.go": "package util\n\nfunc Helper() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+
+	fresh, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "json", "--no-cache")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	cacheDir, err := depgraph.DefaultParseCacheDir()
+	if err != nil {
+		t.Fatalf("DefaultParseCacheDir() error = %v", err)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Fatalf("expected --no-cache to leave %s unwritten, stat error = %v", cacheDir, err)
+	}
+
+	for run := 0; run < 2; run++ {
+		cached, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "json")
+		if err != nil {
+			t.Fatalf("cmd.Execute() error = %v", err)
+		}
+		if cached != fresh {
+			t.Fatalf("run %d: cached output differs from a fresh build:\n%s\nwant:\n%s", run, cached, fresh)
+		}
+	}
+	if entries, err := os.ReadDir(cacheDir); err != nil || len(entries) == 0 {
+		t.Fatalf("expected cache entries in %s, got %d (error %v)", cacheDir, len(entries), err)
+	}
+}
+
+func TestGraphCommit_NodeSizeLOC_CountsCommittedContent(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	bigFile := strings.Repeat("export const x = 1;\n", 500)
+	if err := os.WriteFile(filepath.Join(repoDir, "a.ts"), []byte("import { b } from './b';\nexport const a = b;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "b.ts"), []byte(bigFile), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add files")
+	// The working tree copy shrinks b.ts; sizes must come from the commit.
+	if err := os.WriteFile(filepath.Join(repoDir, "b.ts"), []byte("export const b = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot", "--node-size", "loc")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(output, "width=2.25, height=1.50") {
+		t.Fatalf("expected b.ts to be sized by its 500 committed lines, got:\n%s", output)
+	}
+	if !strings.Contains(output, "width=0.76, height=0.50") {
+		t.Fatalf("expected a.ts to be sized by its 2 lines, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(output, "width=") {
+		t.Fatalf("expected no node sizes without --node-size, got:\n%s", output)
+	}
+}
+
+func TestGraphInput_NodeSize_RejectsUnknownMetric(t *testing.T) {
+	_, _, err := runShow(t, nil, "--node-size", "bytes")
+	if err == nil || !strings.Contains(err.Error(), "unknown node size: bytes (valid options: loc)") {
+		t.Fatalf("expected unknown node size error, got %v", err)
+	}
+}
+
+func TestGraphUncommitted_StagedSelection(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, "a.ts"), []byte("export const a = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "b.ts"), []byte("export const b = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add files")
+	if err := os.WriteFile(filepath.Join(repoDir, "a.ts"), []byte("export const a = 2;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	gitRun(t, repoDir, "add", "a.ts")
+	if err := os.WriteFile(filepath.Join(repoDir, "b.ts"), []byte("export const b = 2;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "junk.ts"), []byte("export const junk = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "--uncommitted", "staged", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"a.ts"`) || strings.Contains(output, `"b.ts"`) || strings.Contains(output, `"junk.ts"`) {
+		t.Fatalf("expected only the staged a.ts, got:\n%s", output)
+	}
+	if !strings.Contains(output, "-dirty") {
+		t.Fatalf("expected the staged change to mark the label dirty, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "--uncommitted", "unstaged", "--include-untracked=false", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"b.ts"`) || strings.Contains(output, `"a.ts"`) || strings.Contains(output, `"junk.ts"`) {
+		t.Fatalf("expected only the unstaged b.ts, got:\n%s", output)
+	}
+}
+
+func TestGraphUncommitted_UntrackedFilesDoNotMarkLabelDirtyWhenExcluded(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, "a.ts"), []byte("export const a = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "add a")
+	if err := os.WriteFile(filepath.Join(repoDir, "scratch.txt"), []byte("notes\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", "a.ts", "--include-untracked=false", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(output, "-dirty") {
+		t.Fatalf("expected an untracked file not to mark the label dirty, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-i", "a.ts", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "-dirty") {
+		t.Fatalf("expected the untracked file to mark the label dirty by default, got:\n%s", output)
+	}
+}
+
+func TestGraphUncommitted_RejectsInvalidSelection(t *testing.T) {
+	_, _, err := runShow(t, nil, "--uncommitted", "tracked")
+	if err == nil || !strings.Contains(err.Error(), "unknown --uncommitted value: tracked (valid options: staged, unstaged, all)") {
+		t.Fatalf("expected unknown --uncommitted value error, got %v", err)
+	}
+
+	_, _, err = runShow(t, nil, "-c", "HEAD", "--uncommitted", "staged")
+	if err == nil || !strings.Contains(err.Error(), "cannot be used with --commit") {
+		t.Fatalf("expected --commit conflict error, got %v", err)
+	}
+}
+
+func writeConfigRepo(t *testing.T, config string) string {
+	t.Helper()
+	repoDir := writeDiamondRepo(t)
+	if err := os.MkdirAll(filepath.Join(repoDir, "generated"), 0o755); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "generated", "g.ts"), []byte("export const g = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, ".clarity.yml"), []byte(config), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	return repoDir
+}
+
+func TestGraphConfig_AppliesDefaults(t *testing.T) {
+	repoDir := writeConfigRepo(t, "exclude: [generated/]\nshow:\n  format: mermaid\n")
+
+	output, _, err := runShow(t, nil, "-r", repoDir)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(output, "flowchart") {
+		t.Fatalf("expected the config format mermaid, got:\n%s", output)
+	}
+	if strings.Contains(output, "g.ts") {
+		t.Fatalf("expected the config exclude to drop generated/g.ts, got:\n%s", output)
+	}
+}
+
+func TestGraphConfig_CommandLineFlagsOverride(t *testing.T) {
+	repoDir := writeConfigRepo(t, "exclude: [generated/]\nshow:\n  format: mermaid\n")
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-f", "dot", "--exclude", "e.ts")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(output, "digraph") {
+		t.Fatalf("expected --format dot to override the config, got:\n%s", output)
+	}
+	if !strings.Contains(output, "g.ts") || strings.Contains(output, `"e.ts"`) {
+		t.Fatalf("expected --exclude to replace the config exclude, got:\n%s", output)
+	}
+}
+
+func TestGraphConfig_InvalidKeyIsReported(t *testing.T) {
+	repoDir := writeConfigRepo(t, "show:\n  formats: mermaid\n")
+
+	_, _, err := runShow(t, nil, "-r", repoDir)
+	if err == nil || !strings.Contains(err.Error(), `unknown key "show.formats"`) {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestGraphCommit_ResolvesTSConfigPathAliasesFromCommit(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	files := map[string]string{
+		"tsconfig.json":          `{"compilerOptions": {"baseUrl": ".", "paths": {"@app/*": ["src/app/*"]}}}`,
+		"src/app/models/user.ts": "export const user = 1;\n",
+		"src/main.ts":            "import { user } from '@app/models/user';\nexport const main = user;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	// The alias must be read from the commit, not the working tree.
+	if err := os.Remove(filepath.Join(repoDir, "tsconfig.json")); err != nil {
+		t.Fatalf("os.Remove() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"src/main.ts" -> "src/app/models/user.ts"`) {
+		t.Fatalf("expected the aliased import to become an edge, got:\n%s", output)
+	}
+}
+
+func writeGeneratedCodeRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	files := map[string]string{
+		"go.mod":                            "module example.com/app\n\ngo 1.22\n\nrequire github.com/acme/lib v1.0.0\n",
+		"main.go":                           "package main\n\nimport (\n\t\"example.com/app/api\"\n\t\"github.com/acme/lib\"\n)\n\nfunc main() { api.Serve(); lib.Run() }\n",
+		"api/server.go":                     "package api\n\nfunc Serve() { handle(Request{}) }\n",
+		"api/request.pb.go":                 "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\ntype Request struct{}\n",
+		"api/handler.go":                    "package api\n\nfunc handle(r Request) {}\n",
+		"vendor/github.com/acme/lib/lib.go": "package lib\n\nfunc Run() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestGraphHideGenerated_DropsVendoredAndGeneratedFiles(t *testing.T) {
+	repoDir := writeGeneratedCodeRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--no-stats")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "request.pb.go") || !strings.Contains(output, "lib.go") {
+		t.Fatalf("expected generated and vendored files without --hide-generated, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot", "--hide-generated")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(output, "request.pb.go") || strings.Contains(output, "lib.go") {
+		t.Fatalf("expected --hide-generated to drop generated and vendored files, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"main.go" -> "api/server.go"`) || !strings.Contains(output, `"api/server.go" -> "api/handler.go"`) {
+		t.Fatalf("expected edges among the remaining files, got:\n%s", output)
+	}
+}
+
+func TestGraphHideGenerated_ReadsHeadersFromCommit(t *testing.T) {
+	repoDir := writeGeneratedCodeRepo(t)
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	// The committed file is generated even though the working tree copy no longer is.
+	if err := os.WriteFile(filepath.Join(repoDir, "api", "request.pb.go"), []byte("package api\n\ntype Request struct{}\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-c", "HEAD", "-f", "dot", "--hide-generated")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(output, "request.pb.go") {
+		t.Fatalf("expected the committed generated file to be hidden, got:\n%s", output)
+	}
+	if !strings.Contains(output, "api/handler.go") {
+		t.Fatalf("expected the other committed files, got:\n%s", output)
+	}
+}
+
+func TestGraph_FileNamesWithSpacesAndNonASCII(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	appFile := filepath.Join(repoDir, "my  app.ts")
+	configFile := filepath.Join(repoDir, "日本語.ts")
+	if err := os.WriteFile(appFile, []byte("import { config } from './日本語';\nexport const app = config;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte("export const config = 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	if err := os.WriteFile(configFile, []byte("export const config = 2;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(appFile, []byte("import { config } from './日本語';\nexport const app = config + 1;\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"-r", repoDir, "-f", "dot"},
+		{"-r", repoDir, "-c", "HEAD", "-f", "dot"},
+	} {
+		output, _, err := runShow(t, nil, args...)
+		if err != nil {
+			t.Fatalf("%v: cmd.Execute() error = %v", args, err)
+		}
+		if !strings.Contains(output, `"my  app.ts" -> "日本語.ts"`) {
+			t.Fatalf("%v: expected an edge between the unquoted file names, got:\n%s", args, output)
+		}
+	}
+}
+
+func TestGraph_WithRepoLinksModulesThroughGoWork(t *testing.T) {
+	workDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("filepath.EvalSymlinks() error = %v", err)
+	}
+	write := func(relPath, content string) {
+		t.Helper()
+		path := filepath.Join(workDir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	write("go.work", "go 1.25\n\nuse (\n\t./api\n\t./shared\n)\n")
+	write("api/go.mod", "module example.com/api\n\ngo 1.25\n")
+	write("shared/go.mod", "module example.com/shared\n\ngo 1.25\n")
+	for _, repo := range []string{"api", "shared"} {
+		repoDir := filepath.Join(workDir, repo)
+		gitInitRepo(t, repoDir)
+		gitRun(t, repoDir, "add", ".")
+		gitRun(t, repoDir, "commit", "-m", "initial")
+	}
+	write("api/handler/handler.go", "package handler\n\nimport \"example.com/shared/money\"\n\nfunc Total() money.Amount { return 1 }\n")
+	write("shared/money/money.go", "package money\n\ntype Amount int\n")
+
+	output, _, err := runShow(t, nil, "-r", filepath.Join(workDir, "api"), "--with-repo", "../shared", "-f", "json")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	var graph formatters.JSONGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+	repos := make(map[string]string)
+	stats := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		repos[node.ID] = node.Repo
+		stats[node.ID] = node.Stats != nil && node.Stats.IsNew
+	}
+	wantRepos := map[string]string{"api/handler/handler.go": "api", "shared/money/money.go": "shared"}
+	if !reflect.DeepEqual(repos, wantRepos) {
+		t.Fatalf("node repositories = %v, want %v", repos, wantRepos)
+	}
+	if !stats["api/handler/handler.go"] || !stats["shared/money/money.go"] {
+		t.Fatalf("expected stats from both repositories, got %v", stats)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].From != "api/handler/handler.go" || graph.Edges[0].To != "shared/money/money.go" {
+		t.Fatalf("expected a cross-repository edge, got %+v", graph.Edges)
+	}
+
+	output, _, err = runShow(t, nil, "-r", filepath.Join(workDir, "api"), "--with-repo", "../shared", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "api:handler.go") || !strings.Contains(output, "shared:money.go") {
+		t.Fatalf("expected node labels prefixed with the repository name, got:\n%s", output)
+	}
+
+	_, _, err = runShow(t, nil, "-r", filepath.Join(workDir, "api"), "--with-repo", "../shared", "-c", "HEAD")
+	if err == nil || !strings.Contains(err.Error(), "--with-repo cannot be used with --commit") {
+		t.Fatalf("expected --with-repo to be rejected with --commit, got %v", err)
+	}
+}
+
+func TestGraph_ShowExternalDrawsPackagesAsDistinctNodes(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	writeFile("a.ts", "import { map } from 'lodash';\nexport const a = map;\n")
+	writeFile("b.ts", "import fp from 'lodash/fp';\nimport { a } from './a';\nexport const b = [fp, a];\n")
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "--show-external", "-f", "json")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	var graph formatters.JSONGraph
+	if err := json.Unmarshal([]byte(output), &graph); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, output)
+	}
+	kinds := make(map[string]string)
+	for _, node := range graph.Nodes {
+		kinds[node.ID] = node.Kind
+		if node.Kind == "external" && node.Stats != nil {
+			t.Fatalf("expected no stats on external node %s, got %+v", node.ID, node.Stats)
+		}
+	}
+	wantKinds := map[string]string{"a.ts": "", "b.ts": "", "npm:lodash": "external"}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Fatalf("node kinds = %v, want %v", kinds, wantKinds)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "--show-external", "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"npm:lodash" [label="npm:lodash", shape=component`) {
+		t.Fatalf("expected a distinct node for the external package, got:\n%s", output)
+	}
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-f", "dot")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if strings.Contains(output, "npm:lodash") {
+		t.Fatalf("expected external packages to be dropped without --show-external, got:\n%s", output)
+	}
+}
+
+func TestGraph_ThemeSelectsColors(t *testing.T) {
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, "a.go"), []byte("package a\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "--theme", "dark")
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `bgcolor="#1e1e1e"`) {
+		t.Fatalf("expected the dark background, got:\n%s", output)
+	}
+
+	paletteFile := filepath.Join(t.TempDir(), "palette.json")
+	if err := os.WriteFile(paletteFile, []byte(`{"majorityExt": "#abcdef"}`), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	output, _, err = runShow(t, nil, "-r", repoDir, "--theme", "custom", "--theme-file", paletteFile)
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `fillcolor="#abcdef"`) {
+		t.Fatalf("expected the custom palette fill, got:\n%s", output)
+	}
+
+	_, _, err = runShow(t, nil, "-r", repoDir, "--theme", "custom")
+	if err == nil || err.Error() != "--theme custom requires --theme-file" {
+		t.Fatalf("expected a missing palette error, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+// maxSymlinkHops bounds how many links resolving one target may pass through, so a
+// cycle of links ends instead of looping.
+const maxSymlinkHops = 40
+
+// loadPathAliases maps the symlinks git tracks in the analyzed tree, the commit
+// toCommit or the working tree when it is empty, to the canonical paths they point
+// at, following links through other links. In the working tree, the untracked links
+// among filePaths are mapped too, as git lists them without their target. Broken
+// links are left out, and so are links leading outside the repository unless
+// allowOutside is set; those are returned in outside instead. Paths outside a git
+// repository have no aliases.
+func loadPathAliases(ctx context.Context, repoPath, toCommit string, allowOutside bool, filePaths []string) (aliases, outside map[string]string, err error) {
+	repoRoot, err := git.GetRepositoryRootContext(ctx, repoPath)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return nil, nil, nil
+	}
+	links, err := git.ListSymlinksContext(ctx, repoPath, toCommit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list symlinks: %w", err)
+	}
+	if toCommit == "" {
+		addUntrackedSymlinks(links, filePaths)
+	}
+
+	aliases = make(map[string]string, len(links))
+	outside = make(map[string]string)
+	for link, target := range links {
+		for hop := 0; hop < maxSymlinkHops; hop++ {
+			next := moduleapi.CanonicalPath(links, target)
+			if next == target {
+				break
+			}
+			target = next
+		}
+		if toCommit == "" {
+			resolved, err := filepath.EvalSymlinks(target)
+			if err != nil {
+				slog.Debug("skipping broken symlink", "path", link, "target", target, "error", err)
+				continue
+			}
+			target = resolved
+		}
+		if !allowOutside {
+			within, err := isWithinBase(repoRoot, target)
+			if err != nil || !within {
+				outside[link] = target
+				continue
+			}
+		}
+		aliases[link] = target
+	}
+	return aliases, outside, nil
+}
+
+// addUntrackedSymlinks adds each path in filePaths that is a symlink on disk, but not
+// yet in links, with the absolute path it points at.
+func addUntrackedSymlinks(links map[string]string, filePaths []string) {
+	for _, filePath := range filePaths {
+		filePath = filepath.Clean(filePath)
+		if _, tracked := links[filePath]; tracked {
+			continue
+		}
+		info, err := os.Lstat(filePath)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := os.Readlink(filePath)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(filePath), target)
+		}
+		links[filePath] = filepath.Clean(target)
+	}
+}
+
+// canonicalizeFilePaths replaces each path reached through a symlink in aliases with
+// the path it points at, so a file linked under several names is analyzed once. A
+// symlink to a directory stands for the files in that directory: those in the commit
+// tree, or on disk when commitFiles is nil. Paths reached through a symlink in
+// outside are dropped. Paths are deduplicated in input order.
+func canonicalizeFilePaths(filePaths []string, aliases, outside map[string]string, commitFiles []string, walk walkOptions) ([]string, error) {
+	if len(aliases) == 0 && len(outside) == 0 {
+		return filePaths, nil
+	}
+
+	canonicalPaths := make([]string, 0, len(filePaths))
+	seen := make(map[string]bool, len(filePaths))
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			canonicalPaths = append(canonicalPaths, path)
+		}
+	}
+
+	for _, filePath := range filePaths {
+		filePath = filepath.Clean(filePath)
+		if target := moduleapi.CanonicalPath(outside, filePath); target != filePath {
+			slog.Debug("skipping symlink outside the repository; pass --allow-outside-repo to follow it", "path", filePath, "target", target)
+			continue
+		}
+		canonical := moduleapi.CanonicalPath(aliases, filePath)
+		if _, isLink := aliases[filePath]; !isLink {
+			add(canonical)
+			continue
+		}
+
+		if commitFiles != nil {
+			found := false
+			for _, commitFile := range commitFiles {
+				if commitFile == canonical || strings.HasPrefix(commitFile, canonical+string(filepath.Separator)) {
+					add(commitFile)
+					found = true
+				}
+			}
+			if !found {
+				slog.Debug("skipping symlink whose target is not in the commit", "path", filePath, "target", canonical)
+			}
+			continue
+		}
+
+		info, err := os.Stat(canonical)
+		if err != nil {
+			slog.Debug("skipping broken symlink", "path", filePath, "target", canonical, "error", err)
+			continue
+		}
+		if !info.IsDir() {
+			add(canonical)
+			continue
+		}
+		files, err := expandPaths([]string{canonical}, true, walk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand symlinked directory %s: %w", filePath, err)
+		}
+		for _, file := range files {
+			add(file)
+		}
+	}
+	return canonicalPaths, nil
+}
+
+// dropDirectories leaves out the working-tree paths that are directories or broken
+// symlinks, such as a link git lists but whose target could not be resolved, so the
+// builder is only handed files. Paths missing from disk, like deleted files, are kept.
+func dropDirectories(filePaths []string) []string {
+	files := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		info, err := os.Stat(filePath)
+		if err == nil && info.IsDir() {
+			slog.Debug("skipping directory among the files to analyze", "path", filePath)
+			continue
+		}
+		if err != nil {
+			if linkInfo, lerr := os.Lstat(filePath); lerr == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+				slog.Debug("skipping broken symlink", "path", filePath, "error", err)
+				continue
+			}
+		}
+		files = append(files, filePath)
+	}
+	return files
+}
+
+// aliasPaths returns the paths path is also reachable by through the symlinks in
+// aliases, as in /repo/lib/util/util.go for /repo/shared/util/util.go when
+// /repo/lib/util links to /repo/shared/util.
+func aliasPaths(aliases map[string]string, path string) []string {
+	var paths []string
+	for alias, canonical := range aliases {
+		if path == canonical || strings.HasPrefix(path, canonical+string(filepath.Separator)) {
+			paths = append(paths, alias+strings.TrimPrefix(path, canonical))
+		}
+	}
+	return paths
+}
@@ -0,0 +1,143 @@
+package show
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSymlinkedPackageRepo commits a Go module where lib/util is a symlink to the
+// shared/util package, main.go imports the package through the link, and
+// other/other.go imports it directly.
+func writeSymlinkedPackageRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitInitRepo(t, repoDir)
+	for name, content := range map[string]string{
+		"go.mod":              "module example.com/app\n\ngo 1.21\n",
+		"shared/util/util.go": "package util\n\nfunc Helper() int { return 1 }\n",
+		"main.go":             "package main\n\nimport \"example.com/app/lib/util\"\n\nfunc main() { _ = util.Helper() }\n",
+		"other/other.go":      "package other\n\nimport \"example.com/app/shared/util\"\n\nvar Value = util.Helper()\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "lib"), 0o755))
+	require.NoError(t, os.Symlink(filepath.Join("..", "shared", "util"), filepath.Join(repoDir, "lib", "util")))
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
+
+func TestSymlinkedPackage_ImportsThroughLinkReachTheCanonicalFile(t *testing.T) {
+	repoDir := writeSymlinkedPackageRepo(t)
+
+	for name, args := range map[string][]string{
+		"working tree": {"-r", repoDir, "-i", ".", "-f", "dot"},
+		"commit":       {"-r", repoDir, "-c", "HEAD", "-i", ".", "-f", "dot"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			output, _, err := runShow(t, nil, args...)
+			require.NoError(t, err)
+
+			assert.Contains(t, output, `"main.go" -> "shared/util/util.go"`)
+			assert.Contains(t, output, `"other/other.go" -> "shared/util/util.go"`)
+			assert.NotContains(t, output, `"lib/util`)
+			assert.Equal(t, 1, strings.Count(output, `"shared/util/util.go" [`))
+		})
+	}
+}
+
+func TestSymlinkedPackage_InputThroughLinkSelectsTheCanonicalFile(t *testing.T) {
+	repoDir := writeSymlinkedPackageRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", "lib/util,main.go", "-f", "dot")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, `"main.go" -> "shared/util/util.go"`)
+	assert.NotContains(t, output, `"lib/util`)
+}
+
+func TestSymlinkedPackage_ExcludeThroughLinkRemovesTheCanonicalFile(t *testing.T) {
+	repoDir := writeSymlinkedPackageRepo(t)
+
+	for _, exclude := range []string{"lib/util", "lib/util/*.go"} {
+		t.Run(exclude, func(t *testing.T) {
+			output, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "--exclude", exclude, "-f", "dot")
+			require.NoError(t, err)
+
+			assert.NotContains(t, output, "util.go")
+			assert.Contains(t, output, `"main.go"`)
+		})
+	}
+}
+
+func TestSymlinkedPackage_BetweenAndFileAcceptTheLinkPath(t *testing.T) {
+	repoDir := writeSymlinkedPackageRepo(t)
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "--between", "main.go,lib/util/util.go", "-f", "dot")
+	require.NoError(t, err)
+	assert.Contains(t, output, `"main.go" -> "shared/util/util.go"`)
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "--file", "lib/util/util.go", "-f", "dot")
+	require.NoError(t, err)
+	assert.Contains(t, output, `"shared/util/util.go" [`)
+}
+
+func TestSymlinkOutsideRepo_FollowedOnlyWithAllowOutsideRepo(t *testing.T) {
+	repoDir := writeSymlinkedPackageRepo(t)
+	outsideDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outsideDir, "vendored.go"), []byte("package vendored\n"), 0o644))
+	require.NoError(t, os.Symlink(outsideDir, filepath.Join(repoDir, "vendored")))
+	gitRun(t, repoDir, "add", "vendored")
+	gitRun(t, repoDir, "commit", "-m", "link outside")
+
+	output, _, err := runShow(t, nil, "-r", repoDir, "-i", ".", "-f", "dot")
+	require.NoError(t, err)
+	assert.NotContains(t, output, "vendored")
+
+	output, _, err = runShow(t, nil, "-r", repoDir, "-i", ".", "--allow-outside-repo", "-f", "dot")
+	require.NoError(t, err)
+	assert.Contains(t, output, "vendored.go")
+	assert.NotContains(t, output, `"vendored" [`)
+}
+
+func TestUntrackedSymlinkedDirectory_ResolvesToTheLinkedFiles(t *testing.T) {
+	repoDir := writeSymlinkedPackageRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "lnk"), 0o755))
+	require.NoError(t, os.Symlink(filepath.Join("..", "shared", "util"), filepath.Join(repoDir, "lnk", "u")))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "uses_link.go"),
+		[]byte("package main\n\nimport \"example.com/app/lnk/u\"\n\nvar _ = u.Helper()\n"), 0o644))
+
+	for name, args := range map[string][]string{
+		"uncommitted": {"-r", repoDir, "-f", "dot"},
+		"input":       {"-r", repoDir, "-i", ".", "-f", "dot"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			output, stderr, err := runShow(t, nil, args...)
+			require.NoError(t, err)
+
+			assert.Contains(t, output, `"uses_link.go" -> "shared/util/util.go"`)
+			assert.NotContains(t, output, `"lnk/u`)
+			assert.NotContains(t, stderr, "unsupported")
+		})
+	}
+}
+
+func TestDropDirectories_KeepsFilesAndMissingPaths(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.go")
+	require.NoError(t, os.WriteFile(file, []byte("package a\n"), 0o644))
+	subdir := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(subdir, 0o755))
+	broken := filepath.Join(dir, "broken")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing"), broken))
+	deleted := filepath.Join(dir, "deleted.go")
+
+	assert.Equal(t, []string{file, deleted}, dropDirectories([]string{file, subdir, broken, deleted}))
+}
@@ -0,0 +1,190 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+)
+
+const (
+	statsFormatText = "text"
+	statsFormatJSON = "json"
+)
+
+// statsTopFiles is how many files the fan-in and fan-out lists name.
+const statsTopFiles = 5
+
+// graphStats is the output of the stats command.
+type graphStats struct {
+	Nodes            int              `json:"nodes"`
+	Edges            int              `json:"edges"`
+	Cycles           int              `json:"cycles"`
+	IsolatedNodes    int              `json:"isolated_nodes"`
+	TestFiles        int              `json:"test_files"`
+	NonTestFiles     int              `json:"non_test_files"`
+	LargestComponent int              `json:"largest_component"`
+	TopFanIn         []statsFileCount `json:"top_fan_in"`
+	TopFanOut        []statsFileCount `json:"top_fan_out"`
+}
+
+// statsFileCount is a repo-relative file and its number of edges in one direction.
+type statsFileCount struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// NewCommand returns a new stats command instance.
+func NewCommand() *cobra.Command {
+	var selection *show.Selection
+	format := statsFormatText
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize the dependency graph of the files show would analyze",
+		Long: `Summarize the dependency graph of the files show would analyze for the same
+selection flags: node and edge counts, cycles, isolated files, test and non-test
+files, the size of the largest connected component, and the files with the most
+dependents (fan-in) and dependencies (fan-out).`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(cmd, selection, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", format, fmt.Sprintf("Output format (%s, %s)", statsFormatText, statsFormatJSON))
+	selection = show.AddSelection(cmd)
+	selection.AddParseFlags(cmd)
+
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, selection *show.Selection, format string) (err error) {
+	if format != statsFormatText && format != statsFormatJSON {
+		return fmt.Errorf("unknown format: %s (valid options: %s, %s)", format, statsFormatText, statsFormatJSON)
+	}
+
+	selected, err := selection.Collect(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := selected.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	if selected.Clean {
+		show.PrintCleanWorkingDirectoryHint(cmd)
+		return nil
+	}
+
+	analyzer := selected.Analyzer(cmd)
+	analyzer.SkipStats = true
+	result, err := analyzer.Run(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	stats, err := computeGraphStats(selected, result.Graph)
+	if err != nil {
+		return fmt.Errorf("failed to compute graph stats: %w", err)
+	}
+
+	if format == statsFormatJSON {
+		output, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode stats: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	}
+	return writeStats(cmd, stats)
+}
+
+// computeGraphStats gathers the stats of fileGraph, naming files relative to the
+// repository they were selected from.
+func computeGraphStats(selected *show.SelectedFiles, fileGraph depgraph.FileDependencyGraph) (graphStats, error) {
+	metrics, err := depgraph.ComputeMetrics(fileGraph.Graph)
+	if err != nil {
+		return graphStats{}, err
+	}
+	isolated, err := depgraph.IsolatedNodes(fileGraph.Graph)
+	if err != nil {
+		return graphStats{}, err
+	}
+	largest, err := depgraph.LargestComponentSize(fileGraph.Graph)
+	if err != nil {
+		return graphStats{}, err
+	}
+	fanIn, err := depgraph.TopFanIn(fileGraph.Graph, statsTopFiles)
+	if err != nil {
+		return graphStats{}, err
+	}
+	fanOut, err := depgraph.TopFanOut(fileGraph.Graph, statsTopFiles)
+	if err != nil {
+		return graphStats{}, err
+	}
+	tests, nonTests := depgraph.CountTestFiles(fileGraph)
+
+	return graphStats{
+		Nodes:            metrics.Nodes,
+		Edges:            metrics.Edges,
+		Cycles:           metrics.Cycles,
+		IsolatedNodes:    len(isolated),
+		TestFiles:        tests,
+		NonTestFiles:     nonTests,
+		LargestComponent: largest,
+		TopFanIn:         statsFileCounts(selected, fanIn),
+		TopFanOut:        statsFileCounts(selected, fanOut),
+	}, nil
+}
+
+func statsFileCounts(selected *show.SelectedFiles, degrees []depgraph.FileDegree) []statsFileCount {
+	counts := make([]statsFileCount, 0, len(degrees))
+	for _, degree := range degrees {
+		counts = append(counts, statsFileCount{Path: selected.RelativePath(degree.File), Count: degree.Degree})
+	}
+	return counts
+}
+
+func writeStats(cmd *cobra.Command, stats graphStats) error {
+	out := cmd.OutOrStdout()
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Nodes:\t%d\n", stats.Nodes)
+	fmt.Fprintf(writer, "Edges:\t%d\n", stats.Edges)
+	fmt.Fprintf(writer, "Cycles:\t%d\n", stats.Cycles)
+	fmt.Fprintf(writer, "Isolated nodes:\t%d\n", stats.IsolatedNodes)
+	fmt.Fprintf(writer, "Test files:\t%d\n", stats.TestFiles)
+	fmt.Fprintf(writer, "Non-test files:\t%d\n", stats.NonTestFiles)
+	fmt.Fprintf(writer, "Largest component:\t%d\n", stats.LargestComponent)
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	for _, list := range []struct {
+		title  string
+		counts []statsFileCount
+	}{
+		{"Top fan-in (dependents)", stats.TopFanIn},
+		{"Top fan-out (dependencies)", stats.TopFanOut},
+	} {
+		fmt.Fprintf(out, "\n%s:\n", list.title)
+		if len(list.counts) == 0 {
+			fmt.Fprintln(out, "  (none)")
+			continue
+		}
+		// Lists are sorted by count, so the first count is the widest.
+		width := len(fmt.Sprint(list.counts[0].Count))
+		for _, count := range list.counts {
+			fmt.Fprintf(out, "  %*d  %s\n", width, count.Count, count.Path)
+		}
+	}
+	return nil
+}
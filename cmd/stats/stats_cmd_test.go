@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+)
+
+func runStatsCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+// writeStatsRepo creates TypeScript sources where util.ts has the most dependents,
+// app.ts the most dependencies, a.ts and b.ts form a cycle, and lonely.ts is isolated.
+func writeStatsRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+	for name, content := range map[string]string{
+		"src/util.ts":      "export const util = 1;\n",
+		"src/config.ts":    "import { util } from './util';\nexport const config = util;\n",
+		"src/app.ts":       "import { util } from './util';\nimport { config } from './config';\nimport { a } from './a';\nexport const app = util + config + a;\n",
+		"src/a.ts":         "import { b } from './b';\nimport { util } from './util';\nexport const a = 1;\n",
+		"src/b.ts":         "import { a } from './a';\nexport const b = 1;\n",
+		"src/lonely.ts":    "export const lonely = 1;\n",
+		"src/util.test.ts": "import { util } from './util';\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestStatsCommand_Text(t *testing.T) {
+	repoDir := writeStatsRepo(t)
+
+	output, err := runStatsCommand(t, "-r", repoDir, "-i", "src")
+	if err != nil {
+		t.Fatalf("stats error = %v", err)
+	}
+
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestStatsCommand_JSON(t *testing.T) {
+	repoDir := writeStatsRepo(t)
+
+	output, err := runStatsCommand(t, "-r", repoDir, "-i", "src", "-f", "json", "--exclude", "src/*.test.ts")
+	if err != nil {
+		t.Fatalf("stats error = %v", err)
+	}
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestStatsCommand_UnknownFormat(t *testing.T) {
+	_, err := runStatsCommand(t, "-f", "dot")
+	if err == nil || err.Error() != "unknown format: dot (valid options: text, json)" {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
@@ -0,0 +1,99 @@
+package trend
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+var csvHeader = []string{"commit", "date", "files", "nodes", "edges", "cycles", "average_degree"}
+
+func supportedFormats() string {
+	return strings.Join([]string{formatCSV, formatJSON}, ", ")
+}
+
+// trendRow is one sample in JSON output, written as a line of its own.
+type trendRow struct {
+	Commit        string  `json:"commit"`
+	Date          string  `json:"date"`
+	Files         int     `json:"files"`
+	Nodes         int     `json:"nodes"`
+	Edges         int     `json:"edges"`
+	Cycles        int     `json:"cycles"`
+	AverageDegree float64 `json:"average_degree"`
+}
+
+// rowWriter streams samples as they are computed.
+type rowWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func newRowWriter(format string, w io.Writer) (*rowWriter, error) {
+	switch format {
+	case formatCSV:
+		return &rowWriter{csv: csv.NewWriter(w)}, nil
+	case formatJSON:
+		return &rowWriter{json: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s (valid options: %s)", format, supportedFormats())
+	}
+}
+
+func (w *rowWriter) writeHeader() error {
+	if w.csv == nil {
+		return nil
+	}
+	return w.flushCSV(csvHeader)
+}
+
+func (w *rowWriter) writeRow(sample git.CommitInfo, metrics treeMetrics) error {
+	row := trendRow{
+		Commit:        sample.SHA,
+		Date:          sample.Date.Format(time.RFC3339),
+		Files:         metrics.Files,
+		Nodes:         metrics.Nodes,
+		Edges:         metrics.Edges,
+		Cycles:        metrics.Cycles,
+		AverageDegree: math.Round(metrics.AverageDegree*100) / 100,
+	}
+
+	if w.json != nil {
+		if err := w.json.Encode(row); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+		return nil
+	}
+	return w.flushCSV([]string{
+		row.Commit,
+		row.Date,
+		strconv.Itoa(row.Files),
+		strconv.Itoa(row.Nodes),
+		strconv.Itoa(row.Edges),
+		strconv.Itoa(row.Cycles),
+		strconv.FormatFloat(row.AverageDegree, 'f', 2, 64),
+	})
+}
+
+func (w *rowWriter) flushCSV(record []string) error {
+	if err := w.csv.Write(record); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package trend
+
+import "github.com/LegacyCodeHQ/clarity/vcs/git"
+
+// sampleEveryNth returns every step-th commit starting with the oldest, plus the
+// newest commit so the series always ends at the branch tip.
+func sampleEveryNth(commits []git.CommitInfo, step int) []git.CommitInfo {
+	var samples []git.CommitInfo
+	for i := 0; i < len(commits); i += step {
+		samples = append(samples, commits[i])
+	}
+	if (len(commits)-1)%step != 0 {
+		samples = append(samples, commits[len(commits)-1])
+	}
+	return samples
+}
+
+// sampleWeekly returns the last commit of each ISO week, in UTC, that has commits.
+func sampleWeekly(commits []git.CommitInfo) []git.CommitInfo {
+	var samples []git.CommitInfo
+	for i, commit := range commits {
+		if i+1 < len(commits) && sameWeek(commit, commits[i+1]) {
+			continue
+		}
+		samples = append(samples, commit)
+	}
+	return samples
+}
+
+func sameWeek(a, b git.CommitInfo) bool {
+	aYear, aWeek := a.Date.UTC().ISOWeek()
+	bYear, bWeek := b.Date.UTC().ISOWeek()
+	return aYear == bYear && aWeek == bWeek
+}
@@ -0,0 +1,183 @@
+package trend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+type trendOptions struct {
+	repoPath     string
+	branch       string
+	since        string
+	step         int
+	weekly       bool
+	outputFormat string
+}
+
+// Cmd represents the trend command.
+var Cmd = NewCommand()
+
+// NewCommand returns a new trend command instance.
+func NewCommand() *cobra.Command {
+	opts := &trendOptions{
+		branch:       "HEAD",
+		step:         1,
+		outputFormat: formatCSV,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Report dependency graph metrics across a branch's history",
+		Long: `Sample commits on a branch and report dependency graph metrics for the full tree
+at each sample, oldest first. Each row is written as soon as it is computed, so long
+runs can be charted while they progress and interrupted without losing finished rows.
+
+Examples:
+  clarity trend --branch main --since 2024-01-01 --step 10
+  clarity trend --branch main --weekly --format json > trend.jsonl`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrend(cmd, opts)
+		},
+	}
+
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", opts.branch, "Branch whose first-parent history is sampled")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only sample commits made on or after this date (e.g. 2024-01-01)")
+	cmd.Flags().IntVar(&opts.step, "step", opts.step, "Sample every Nth commit; the branch tip is always included")
+	cmd.Flags().BoolVar(&opts.weekly, "weekly", false, "Sample the last commit of each week instead of every Nth commit")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", opts.outputFormat, fmt.Sprintf("Output format (%s)", supportedFormats()))
+
+	return cmd
+}
+
+func runTrend(cmd *cobra.Command, opts *trendOptions) error {
+	if opts.step < 1 {
+		return fmt.Errorf("--step must be at least 1, got %d", opts.step)
+	}
+	if opts.weekly && cmd.Flags().Changed("step") {
+		return fmt.Errorf("--weekly and --step cannot be used together")
+	}
+	rows, err := newRowWriter(opts.outputFormat, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	repoRoot := repo.RepoRoot
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	commits, err := git.ListFirstParentCommitsContext(ctx, repoRoot, opts.branch, opts.since)
+	if err != nil {
+		return fmt.Errorf("failed to list commits on %s: %w", opts.branch, err)
+	}
+	if len(commits) == 0 {
+		if opts.since != "" {
+			return fmt.Errorf("no commits on %s since %s", opts.branch, opts.since)
+		}
+		return fmt.Errorf("no commits on %s", opts.branch)
+	}
+
+	var samples []git.CommitInfo
+	if opts.weekly {
+		samples = sampleWeekly(commits)
+	} else {
+		samples = sampleEveryNth(commits, opts.step)
+	}
+
+	if err := rows.writeHeader(); err != nil {
+		return err
+	}
+
+	// Samples whose tree matches an earlier one, such as merges that change no files,
+	// reuse its metrics instead of analyzing the same content again.
+	metricsByTree := make(map[string]treeMetrics)
+	for i, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("trend interrupted after %d of %d samples: %w", i, len(samples), err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "[%d/%d] %s %s\n", i+1, len(samples), shortSHA(sample.SHA), sample.Date.Format("2006-01-02"))
+
+		metrics, ok := metricsByTree[sample.Tree]
+		if !ok {
+			metrics, err = computeTreeMetrics(ctx, repoRoot, sample.SHA)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fmt.Errorf("trend interrupted after %d of %d samples: %w", i, len(samples), ctxErr)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to analyze commit %s: %w", shortSHA(sample.SHA), err)
+			}
+			metricsByTree[sample.Tree] = metrics
+		}
+
+		if err := rows.writeRow(sample, metrics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treeMetrics are the metrics reported for one commit's tree.
+type treeMetrics struct {
+	// Files counts every file in the tree, including those Clarity cannot analyze.
+	Files int
+	depgraph.GraphMetrics
+}
+
+// computeTreeMetrics builds the dependency graph of every supported file in the
+// commit's tree and measures it, stopping git and the build when ctx is done.
+func computeTreeMetrics(ctx context.Context, repoRoot, commitID string) (treeMetrics, error) {
+	files, err := git.GetCommitTreeFilesContext(ctx, repoRoot, commitID)
+	if err != nil {
+		return treeMetrics{}, err
+	}
+
+	supported := make([]string, 0, len(files))
+	for _, file := range files {
+		if registry.IsSupportedLanguageExtension(filepath.Ext(file)) {
+			supported = append(supported, file)
+		}
+	}
+
+	metrics := treeMetrics{Files: len(files)}
+	if len(supported) == 0 {
+		return metrics, nil
+	}
+
+	contentReader := git.GitCommitContentReaderContext(ctx, repoRoot, commitID)
+	if closer, ok := contentReader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	graph, _, err := depgraph.BuildDependencyGraphContext(ctx, supported, contentReader, depgraph.BuildOptions{})
+	if err != nil {
+		return treeMetrics{}, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	metrics.GraphMetrics, err = depgraph.ComputeMetrics(graph)
+	if err != nil {
+		return treeMetrics{}, err
+	}
+	return metrics, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
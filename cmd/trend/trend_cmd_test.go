@@ -0,0 +1,204 @@
+package trend
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+func TestTrendCommand_WritesCSVRowPerSample(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, stderr, err := runTrendCommand(t, "-r", repoDir, "--branch", "main")
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"1", "1", "0", "0", "0.00"}, records[1][2:])
+	assert.Equal(t, []string{"2", "2", "1", "0", "1.00"}, records[2][2:])
+	assert.Equal(t, []string{"3", "3", "3", "1", "2.00"}, records[3][2:])
+	assert.Equal(t, []string{"4", "3", "3", "1", "2.00"}, records[4][2:])
+	assert.Contains(t, stderr, "[4/4]")
+}
+
+func TestTrendCommand_JSONLinesWithStep(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, _, err := runTrendCommand(t, "-r", repoDir, "--branch", "main", "--step", "2", "--format", "json")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3, "commits 1 and 3 plus the branch tip")
+
+	var rows []trendRow
+	for _, line := range lines {
+		var row trendRow
+		require.NoError(t, json.Unmarshal([]byte(line), &row))
+		rows = append(rows, row)
+	}
+	assert.Equal(t, 1, rows[0].Nodes)
+	assert.Equal(t, 3, rows[1].Nodes)
+	assert.Equal(t, 1, rows[1].Cycles)
+	assert.Equal(t, 4, rows[2].Files)
+	assert.Equal(t, gitOutput(t, repoDir, "rev-parse", "main"), rows[2].Commit)
+}
+
+func TestTrendCommand_SinceFiltersCommits(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	stdout, _, err := runTrendCommand(t, "-r", repoDir, "--branch", "main", "--since", "2024-01-15")
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+}
+
+func TestTrendCommand_RejectsInvalidOptions(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	_, _, err := runTrendCommand(t, "-r", repoDir, "--step", "0")
+	require.ErrorContains(t, err, "--step must be at least 1")
+
+	_, _, err = runTrendCommand(t, "-r", repoDir, "--step", "2", "--weekly")
+	require.ErrorContains(t, err, "cannot be used together")
+
+	_, _, err = runTrendCommand(t, "-r", repoDir, "--format", "xml")
+	require.ErrorContains(t, err, "unknown format")
+
+	_, _, err = runTrendCommand(t, "-r", repoDir, "--branch", "main", "--since", "2030-01-01")
+	require.ErrorContains(t, err, "no commits on main since 2030-01-01")
+}
+
+func TestTrendCommand_StopsWhenCanceled(t *testing.T) {
+	repoDir := writeHistoryRepo(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"-r", repoDir, "--branch", "main"})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.ExecuteContext(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NotContains(t, stdout.String(), gitOutput(t, repoDir, "rev-parse", "main"))
+}
+
+func TestSampleEveryNth_AlwaysIncludesTip(t *testing.T) {
+	commits := testCommits("2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04", "2024-01-05")
+
+	assert.Equal(t, shas(commits), shas(sampleEveryNth(commits, 1)))
+	assert.Equal(t, []string{"c0", "c2", "c4"}, shas(sampleEveryNth(commits, 2)))
+	assert.Equal(t, []string{"c0", "c3", "c4"}, shas(sampleEveryNth(commits, 3)))
+	assert.Equal(t, []string{"c0", "c4"}, shas(sampleEveryNth(commits, 10)))
+}
+
+func TestSampleWeekly_TakesLastCommitOfEachWeek(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	commits := testCommits("2024-01-01", "2024-01-03", "2024-01-07", "2024-01-08", "2024-01-22", "2024-01-23")
+
+	assert.Equal(t, []string{"c2", "c3", "c5"}, shas(sampleWeekly(commits)))
+}
+
+func runTrendCommand(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+// writeHistoryRepo creates a repository with four commits on main, one week apart:
+// a single file, a dependency, a cycle, and a README that no graph counts.
+func writeHistoryRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "", "init", "-b", "main")
+	gitRun(t, repoDir, "", "config", "user.name", "test")
+	gitRun(t, repoDir, "", "config", "user.email", "test@example.com")
+
+	steps := []struct {
+		date  string
+		files map[string]string
+	}{
+		{"2024-01-01", map[string]string{"a.ts": "export const a = 1;\n"}},
+		{"2024-01-08", map[string]string{"b.ts": "import { a } from './a';\nexport const b = a;\n"}},
+		{"2024-01-15", map[string]string{
+			"a.ts": "import { c } from './c';\nexport const a = c;\n",
+			"c.ts": "import { b } from './b';\nexport const c = b;\n",
+		}},
+		{"2024-01-22", map[string]string{"README.md": "# history\n"}},
+	}
+	for _, step := range steps {
+		for name, content := range step.files {
+			require.NoError(t, os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644))
+		}
+		gitRun(t, repoDir, "", "add", ".")
+		gitRun(t, repoDir, step.date+"T12:00:00Z", "commit", "-m", "commit on "+step.date)
+	}
+	return repoDir
+}
+
+func gitRun(t *testing.T, repoDir, date string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	if date != "" {
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
+
+func gitOutput(t *testing.T, repoDir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+func testCommits(dates ...string) []git.CommitInfo {
+	commits := make([]git.CommitInfo, 0, len(dates))
+	for i, date := range dates {
+		parsed, _ := time.Parse("2006-01-02", date)
+		commits = append(commits, git.CommitInfo{SHA: "c" + string(rune('0'+i)), Date: parsed})
+	}
+	return commits
+}
+
+func shas(commits []git.CommitInfo) []string {
+	result := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, commit.SHA)
+	}
+	return result
+}
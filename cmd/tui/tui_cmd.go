@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show"
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
+	"github.com/LegacyCodeHQ/clarity/internal/tui"
+)
+
+// runTUI shows the model on the terminal; tests replace it to inspect the model.
+var runTUI = func(in io.Reader, out io.Writer, model tui.Model) error {
+	file, ok := in.(*os.File)
+	if !ok {
+		return tui.ErrNotTerminal
+	}
+	return tui.Run(file, out, model)
+}
+
+// NewCommand returns a new tui command instance.
+func NewCommand() *cobra.Command {
+	var selection *show.Selection
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Explore the dependency graph in the terminal",
+		Long: `Explore the graph show would draw one file at a time. The view starts on the --file
+file, or the file with the most dependencies and dependents, and shows it beside
+the files it imports and the files that import it.
+
+Keys:
+  up/down       select a file in the active list
+  left/right    switch between the imports and imported-by lists
+  enter         focus the selected file
+  backspace     focus the previously focused file again
+  /             search file names; enter focuses the selected match
+  t             hide or show test files
+  w             show the shortest dependency path each way between the
+                previously focused file and the selected file
+  q, ctrl-c     quit`,
+		Example: `  clarity tui
+  clarity tui --file src/app.ts -c HEAD`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTUICommand(cmd, selection)
+		},
+	}
+
+	selection = show.AddSelection(cmd)
+	selection.AddParseFlags(cmd)
+
+	return cmd
+}
+
+func runTUICommand(cmd *cobra.Command, selection *show.Selection) (err error) {
+	if len(selection.Between()) > 0 {
+		return fmt.Errorf("--between cannot be used with tui")
+	}
+
+	selected, err := selection.Collect(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := selected.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	analyzer := selected.Analyzer(cmd)
+	analyzer.SkipStats = true
+	result, err := analyzer.Run(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	focus := ""
+	if selection.TargetFile() != "" {
+		if focus, err = selected.ResolveFile(cmd, result.Graph.Graph, selection.TargetFile()); err != nil {
+			return err
+		}
+	}
+
+	adjacency, err := depgraph.AdjacencyList(result.Graph.Graph)
+	if err != nil {
+		return fmt.Errorf("failed to list graph files: %w", err)
+	}
+	graph := tui.Graph{
+		Graph: result.Graph.Graph,
+		Names: make(map[string]string, len(adjacency)),
+		Tests: make(map[string]bool),
+	}
+	for file := range adjacency {
+		graph.Names[file] = selected.RelativePath(file)
+		if registry.IsTestFile(file, selected.ContentReader) {
+			graph.Tests[file] = true
+		}
+	}
+	model, err := tui.New(graph, focus)
+	if err != nil {
+		return err
+	}
+
+	err = runTUI(cmd.InOrStdin(), cmd.OutOrStdout(), model)
+	if errors.Is(err, tui.ErrNotTerminal) {
+		return fmt.Errorf("tui needs an interactive terminal on stdin; use show or neighbors to print the graph instead")
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/internal/tui"
+)
+
+// runTUIModel runs the tui command with the terminal replaced, and returns the model
+// it would have shown.
+func runTUIModel(t *testing.T, args ...string) (tui.Model, error) {
+	t.Helper()
+	restore := runTUI
+	t.Cleanup(func() { runTUI = restore })
+	var shown tui.Model
+	runTUI = func(in io.Reader, out io.Writer, model tui.Model) error {
+		shown = model
+		return nil
+	}
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+	return shown, err
+}
+
+// writeTUIRepo commits TypeScript sources where index.ts imports main.ts, main.ts
+// imports app.ts, app.ts imports config.ts and util.ts, config.ts imports util.ts,
+// and util.ts imports log.ts.
+func writeTUIRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitRun(t, repoDir, "init")
+	gitRun(t, repoDir, "config", "user.name", "test")
+	gitRun(t, repoDir, "config", "user.email", "test@example.com")
+	for name, content := range map[string]string{
+		"src/index.ts":  "import { main } from './main';\nmain();\n",
+		"src/main.ts":   "import { app } from './app';\nexport const main = () => app;\n",
+		"src/app.ts":    "import { util } from './util';\nimport { config } from './config';\nexport const app = util + config;\n",
+		"src/config.ts": "import { util } from './util';\nexport const config = util;\n",
+		"src/util.ts":   "import { log } from './log';\nexport const util = log;\n",
+		"src/log.ts":    "export const log = 1;\n",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	gitRun(t, repoDir, "add", ".")
+	gitRun(t, repoDir, "commit", "-m", "initial")
+	return repoDir
+}
+
+func TestTUICommand_FocusesTheBusiestFile(t *testing.T) {
+	repoDir := writeTUIRepo(t)
+
+	model, err := runTUIModel(t, "-r", repoDir, "-c", "HEAD")
+	require.NoError(t, err)
+
+	// app.ts and util.ts each have three dependencies and dependents; app.ts sorts first.
+	assert.Equal(t, filepath.Join(repoDir, "src", "app.ts"), model.Focus())
+	assert.Contains(t, model.View(120, 12), "src/app.ts")
+}
+
+func TestTUICommand_FocusesTheFileFlag(t *testing.T) {
+	repoDir := writeTUIRepo(t)
+
+	model, err := runTUIModel(t, "-r", repoDir, "-c", "HEAD", "--file", "src/log.ts")
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(repoDir, "src", "log.ts"), model.Focus())
+}
+
+func TestTUICommand_NeedsATerminal(t *testing.T) {
+	repoDir := writeTUIRepo(t)
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"-r", repoDir, "-c", "HEAD"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+
+	require.ErrorContains(t, err, "tui needs an interactive terminal on stdin")
+}
+
+func gitRun(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %v failed: %v\nstderr: %s", args, err, strings.TrimSpace(stderr.String()))
+	}
+}
@@ -66,3 +66,14 @@ func TestJSONGraphFormatter_Format_Cycles(t *testing.T) {
 	g := testhelpers.JSONGoldie(t)
 	g.Assert(t, t.Name(), []byte(output))
 }
+
+func TestJSONGraphFormatter_Format_EmptyGraph(t *testing.T) {
+	graph := testJSONFileGraph(t, map[string][]string{}, nil)
+
+	formatter := jsonGraphFormatter{}
+	output, err := formatter.Format(graph, "test-label")
+	require.NoError(t, err)
+
+	g := testhelpers.JSONGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
@@ -8,10 +8,10 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 
 	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 	"github.com/LegacyCodeHQ/clarity/internal/mcplogdlog"
 	"github.com/spf13/cobra"
 )
@@ -34,7 +34,7 @@ func NewCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repoPath, "repo", "r", "", "Git repository path (default: current directory)")
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
 	cmd.Flags().IntVarP(&opts.port, "port", "P", opts.port, "HTTP server port")
 	cmd.Flags().StringSliceVarP(&opts.includes, "input", "i", nil, "Watch specific files and/or directories (comma-separated)")
 	cmd.Flags().StringSliceVar(&opts.excludes, "exclude", nil, "Exclude specific files and/or directories (comma-separated)")
@@ -58,16 +58,11 @@ func runWatch(cmd *cobra.Command, opts *watchOptions) error {
 		"exclude":   opts.excludes,
 		"direction": opts.direction,
 	})
-	repoPath := opts.repoPath
-	if repoPath == "" {
-		repoPath = "."
-	}
-
-	absRepoPath, err := filepath.Abs(repoPath)
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to resolve repo path: %w", err)
+		return err
 	}
-	repoPath = absRepoPath
+	repoPath := repo.RepoPath
 
 	if direction, ok := formatters.ParseDirection(opts.direction); !ok {
 		return fmt.Errorf("unknown direction: %s (valid options: %s)", opts.direction, formatters.SupportedDirections())
@@ -0,0 +1,66 @@
+package why
+
+import (
+	"os"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/languages/kotlin"
+)
+
+func isKotlinFile(ext string) bool {
+	return ext == ".kt" || ext == ".kts"
+}
+
+// findKotlinReferencedMembers reports the top-level types and functions of toPath
+// that fromPath references, with the function each reference is in. Private
+// top-level declarations cannot be referenced from another file, so every member
+// found is exported.
+func findKotlinReferencedMembers(fromPath, toPath string) ([]memberUsage, error) {
+	target, err := os.ReadFile(toPath)
+	if err != nil {
+		return nil, err
+	}
+	source, err := os.ReadFile(fromPath)
+	if err != nil {
+		return nil, err
+	}
+
+	targetMembers := make(map[string]SymbolMeta)
+	for _, name := range kotlin.ExtractTopLevelTypeNames(target) {
+		targetMembers[name] = SymbolMeta{Kind: SymbolKindType, Exported: true}
+	}
+	for _, name := range kotlin.ExtractTopLevelFunctionNames(target) {
+		targetMembers[name] = SymbolMeta{Kind: SymbolKindFunc, Exported: true}
+	}
+	if len(targetMembers) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[string]bool, len(targetMembers))
+	for name := range targetMembers {
+		names[name] = true
+	}
+
+	var calls []memberUsage
+	seen := make(map[memberUsage]bool)
+	for _, reference := range kotlin.FindMemberReferences(source, names) {
+		call := memberUsage{
+			Caller: callerOrFileScope(reference.Function),
+			Callee: memberSymbol{Name: reference.Name, Meta: targetMembers[reference.Name]},
+			Line:   reference.Line,
+		}
+		if seen[call] {
+			continue
+		}
+		seen[call] = true
+		calls = append(calls, call)
+	}
+	sortMemberUsages(calls)
+	return calls, nil
+}
+
+func callerOrFileScope(function string) string {
+	if function == "" {
+		return fileScopeCaller
+	}
+	return function
+}
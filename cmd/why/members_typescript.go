@@ -0,0 +1,117 @@
+package why
+
+import (
+	"os"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/languages/typescript"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func isTypeScriptFile(ext string) bool {
+	return ext == ".ts" || ext == ".tsx"
+}
+
+// findTypeScriptReferencedMembers reports the imports from toPath that fromPath
+// uses, with the function each use is in. Members read through a namespace import
+// (import * as api) are reported by the name toPath exports them under, and a
+// default import by its local name.
+func findTypeScriptReferencedMembers(fromPath, toPath string) ([]memberUsage, error) {
+	target, err := os.ReadFile(toPath)
+	if err != nil {
+		return nil, err
+	}
+	source, err := os.ReadFile(fromPath)
+	if err != nil {
+		return nil, err
+	}
+	fromTSX := strings.HasSuffix(fromPath, ".tsx")
+
+	reader := vcs.FilesystemContentReader()
+	bindings := make(map[string]typescript.NamedImport)
+	names := make(map[string]bool)
+	for _, binding := range typescript.ParseNamedImports(source, fromTSX) {
+		if !typescript.ImportResolvesTo(fromPath, binding.Source, toPath, reader) {
+			continue
+		}
+		bindings[binding.Local] = binding
+		names[binding.Local] = true
+	}
+	if len(bindings) == 0 {
+		return nil, nil
+	}
+
+	exports := make(map[string]SymbolKind)
+	for _, declaration := range typescript.ExtractExportedDeclarations(target, strings.HasSuffix(toPath, ".tsx")) {
+		exports[declaration.Name] = typeScriptSymbolKind(declaration.Keyword)
+	}
+
+	var calls []memberUsage
+	seen := make(map[memberUsage]bool)
+	for _, reference := range typescript.FindMemberReferences(source, fromTSX, names) {
+		symbol, ok := typeScriptMemberSymbol(reference.Name, bindings, exports)
+		if !ok {
+			continue
+		}
+		call := memberUsage{
+			Caller: callerOrFileScope(reference.Function),
+			Callee: symbol,
+			Line:   reference.Line,
+		}
+		if seen[call] {
+			continue
+		}
+		seen[call] = true
+		calls = append(calls, call)
+	}
+	sortMemberUsages(calls)
+	return calls, nil
+}
+
+// typeScriptMemberSymbol returns the member of the target file that a reference
+// through an import binding names. References are a local name or, for a property
+// read, "local.property".
+func typeScriptMemberSymbol(reference string, bindings map[string]typescript.NamedImport, exports map[string]SymbolKind) (memberSymbol, bool) {
+	local, property, _ := strings.Cut(reference, ".")
+	binding, ok := bindings[local]
+	if !ok {
+		return memberSymbol{}, false
+	}
+
+	name := binding.Imported
+	switch binding.Imported {
+	case typescript.NamespaceImportName:
+		if property == "" {
+			return memberSymbol{}, false
+		}
+		name = property
+	case typescript.DefaultImportName:
+		kind, ok := exports[typescript.DefaultImportName]
+		if !ok {
+			kind = SymbolKindSymbol
+		}
+		return memberSymbol{Name: binding.Local, Meta: SymbolMeta{Kind: kind, Exported: true}}, true
+	}
+
+	kind, ok := exports[name]
+	if !ok {
+		kind = SymbolKindSymbol
+	}
+	return memberSymbol{Name: name, Meta: SymbolMeta{Kind: kind, Exported: true}}, true
+}
+
+// typeScriptSymbolKind maps a TypeScript declaring keyword to a symbol kind.
+func typeScriptSymbolKind(keyword string) SymbolKind {
+	switch keyword {
+	case "function":
+		return SymbolKindFunc
+	case "class", "interface", "type", "enum":
+		return SymbolKindType
+	case "const":
+		return SymbolKindConst
+	case "let", "var":
+		return SymbolKindVar
+	default:
+		return SymbolKindSymbol
+	}
+}
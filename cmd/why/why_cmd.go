@@ -1,6 +1,7 @@
 package why
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -13,6 +14,7 @@ import (
 	"github.com/LegacyCodeHQ/clarity/cmd/show"
 	"github.com/LegacyCodeHQ/clarity/depgraph"
 	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 	"github.com/spf13/cobra"
 )
@@ -21,12 +23,17 @@ const (
 	formatText    = "text"
 	formatDOT     = "dot"
 	formatMermaid = "mermaid"
+	formatJSON    = "json"
 )
 
 type whyOptions struct {
-	outputFormat string
-	repoPath     string
-	allowOutside bool
+	outputFormat  string
+	outputPath    string
+	force         bool
+	repoPath      string
+	allowOutside  bool
+	transitive    bool
+	contentReader vcs.ContentReader
 }
 
 type directConnection struct {
@@ -43,6 +50,9 @@ type memberUsage struct {
 	Line   int          `json:"line"`
 }
 
+// fileScopeCaller is the caller of usages outside any function.
+const fileScopeCaller = "<file-scope>"
+
 type memberSymbol struct {
 	Name string     `json:"name"`
 	Meta SymbolMeta `json:"meta"`
@@ -56,6 +66,9 @@ const (
 	SymbolKindType   SymbolKind = "type"
 	SymbolKindVar    SymbolKind = "var"
 	SymbolKindConst  SymbolKind = "const"
+	// SymbolKindSymbol is a member whose declaration is not in the target file, such
+	// as a TypeScript re-export.
+	SymbolKindSymbol SymbolKind = "symbol"
 )
 
 type SymbolMeta struct {
@@ -69,15 +82,29 @@ var Cmd = NewCommand()
 
 // NewCommand returns a new why command instance.
 func NewCommand() *cobra.Command {
+	return newCommand(vcs.FilesystemContentReader())
+}
+
+// newCommand returns a why command that reads the analyzed files through
+// contentReader.
+func newCommand(contentReader vcs.ContentReader) *cobra.Command {
 	opts := &whyOptions{
-		outputFormat: formatText,
+		outputFormat:  formatText,
+		contentReader: contentReader,
 	}
 
 	cmd := &cobra.Command{
 		Use:   "why <from> <to>",
 		Short: "Show direct dependency direction(s) between two files",
-		Long:  "Show immediate dependency edge(s) between two files, including referenced members when available.",
-		Args:  cobra.ExactArgs(2),
+		Long: `Show immediate dependency edge(s) between two files, including referenced members when available.
+
+Only the two files and the files in their directories are parsed. Pass --transitive to
+build the graph of the whole repository and also report the shortest path between
+the files in each direction.`,
+		Args: cobra.ExactArgs(2),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return cliconfig.ApplyProjectConfig(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runWhy(cmd, opts, args[0], args[1])
 		},
@@ -89,8 +116,11 @@ func NewCommand() *cobra.Command {
 		"f",
 		opts.outputFormat,
 		fmt.Sprintf("Output format (%s)", supportedFormats()))
-	cmd.Flags().StringVarP(&opts.repoPath, "repo", "r", "", "Git repository path (default: current directory)")
-	cmd.Flags().BoolVar(&opts.allowOutside, "allow-outside-repo", false, "Allow input paths outside the repo root")
+	cmd.Flags().StringVarP(&opts.outputPath, "output", "o", "", "Write output to a file instead of stdout")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite the --output file when it already exists")
+	cmd.Flags().BoolVar(&opts.transitive, "transitive", false, "Analyze the whole repository and report the shortest path between the files")
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
+	cliconfig.AddAllowOutsideRepoAlias(cmd, &opts.allowOutside)
 
 	return cmd
 }
@@ -100,16 +130,15 @@ func runWhy(cmd *cobra.Command, opts *whyOptions, fromArg, toArg string) error {
 		return fmt.Errorf("unknown format: %s (valid options: %s)", opts.outputFormat, supportedFormats())
 	}
 
-	repoPath := opts.repoPath
-	if repoPath == "" {
-		repoPath = "."
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{AllowNonRepo: true})
+	if err != nil {
+		return err
 	}
-
-	pathResolver, err := show.NewPathResolver(repoPath, opts.allowOutside)
+	pathResolver, err := show.NewPathResolver(repo.RepoPath, repo.AllowOutsideRepo)
 	if err != nil {
 		return fmt.Errorf("failed to create path resolver: %w", err)
 	}
-	repoPath = pathResolver.BaseDir()
+	repoPath := pathResolver.BaseDir()
 
 	fromPath, err := pathResolver.Resolve(show.RawPath(fromArg))
 	if err != nil {
@@ -120,15 +149,20 @@ func runWhy(cmd *cobra.Command, opts *whyOptions, fromArg, toArg string) error {
 		return fmt.Errorf("failed to resolve to file %q: %w", toArg, err)
 	}
 
-	filePaths, err := collectSupportedFiles(repoPath)
-	if err != nil {
-		return fmt.Errorf("failed to collect files from repository: %w", err)
+	var filePaths []string
+	if opts.transitive {
+		filePaths, err = collectSupportedFiles(repoPath)
+		if err != nil {
+			return fmt.Errorf("failed to collect files from repository: %w", err)
+		}
+	} else {
+		filePaths = collectNeighborhoodFiles(opts.contentReader, fromPath.String(), toPath.String())
 	}
 	if len(filePaths) == 0 {
 		return fmt.Errorf("no supported files found in repository")
 	}
 
-	graphData, err := depgraph.BuildDependencyGraph(filePaths, vcs.FilesystemContentReader())
+	graphData, err := depgraph.BuildDependencyGraph(filePaths, opts.contentReader)
 	if err != nil {
 		return fmt.Errorf("failed to build dependency graph: %w", err)
 	}
@@ -146,11 +180,25 @@ func runWhy(cmd *cobra.Command, opts *whyOptions, fromArg, toArg string) error {
 	}
 	enrichMembers(connections)
 
-	output, err := formatOutput(opts.outputFormat, repoPath, fromPath.String(), toPath.String(), connections)
+	var paths [][]string
+	if opts.transitive {
+		paths, err = findShortestPaths(graphData, fromPath.String(), toPath.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	output, err := formatOutput(opts.outputFormat, repoPath, fromPath.String(), toPath.String(), connections, paths)
 	if err != nil {
 		return err
 	}
 
+	if opts.outputPath != "" {
+		if err := show.WriteOutputFile(opts.outputPath, []byte(output+"\n"), opts.force); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
 	fmt.Fprintln(cmd.OutOrStdout(), output)
 	return nil
 }
@@ -179,6 +227,55 @@ func collectSupportedFiles(root string) ([]string, error) {
 	return files, nil
 }
 
+// collectNeighborhoodFiles returns the supported files in the directories of fromPath
+// and toPath, which is all a direct dependency between the two can depend on: the
+// files themselves, and the files of their package for languages such as Go and
+// Kotlin that resolve names across a package.
+func collectNeighborhoodFiles(contentReader vcs.ContentReader, fromPath, toPath string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		if !seen[path] && registry.IsSupportedLanguageExtension(filepath.Ext(path)) {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	add(fromPath)
+	add(toPath)
+	listed := make(map[string]bool)
+	for _, dir := range []string{filepath.Dir(fromPath), filepath.Dir(toPath)} {
+		if listed[dir] {
+			continue
+		}
+		listed[dir] = true
+		names, err := contentReader.ListDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			add(filepath.Join(dir, name))
+		}
+	}
+	return files
+}
+
+// findShortestPaths returns the shortest path from fromPath to toPath and the one
+// from toPath to fromPath, leaving out directions without a path.
+func findShortestPaths(g depgraph.DependencyGraph, fromPath, toPath string) ([][]string, error) {
+	paths := [][]string{}
+	for _, pair := range [][2]string{{fromPath, toPath}, {toPath, fromPath}} {
+		path, err := depgraph.ShortestPath(g, pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
 func findDirectConnections(g depgraph.DependencyGraph, fromPath, toPath string) ([]directConnection, error) {
 	var connections []directConnection
 
@@ -218,25 +315,31 @@ func containsPath(paths []string, target string) bool {
 	return false
 }
 
-func formatOutput(format, repoRoot, fromPath, toPath string, connections []directConnection) (string, error) {
+// formatOutput renders the connections between fromPath and toPath. paths holds the
+// shortest paths found with --transitive and is nil without it; DOT and Mermaid
+// output show the direct connections only.
+func formatOutput(format, repoRoot, fromPath, toPath string, connections []directConnection, paths [][]string) (string, error) {
 	switch strings.ToLower(format) {
 	case formatText:
-		return formatTextOutput(repoRoot, fromPath, toPath, connections), nil
+		return formatTextOutput(repoRoot, fromPath, toPath, connections, paths), nil
 	case formatDOT:
 		return formatDOTOutput(repoRoot, fromPath, toPath, connections), nil
 	case formatMermaid:
 		return formatMermaidOutput(repoRoot, fromPath, toPath, connections), nil
+	case formatJSON:
+		return formatJSONOutput(repoRoot, fromPath, toPath, connections, paths)
 	default:
 		return "", fmt.Errorf("unknown format: %s (valid options: %s)", format, supportedFormats())
 	}
 }
 
-func formatTextOutput(repoRoot, fromPath, toPath string, connections []directConnection) string {
+func formatTextOutput(repoRoot, fromPath, toPath string, connections []directConnection, paths [][]string) string {
 	fromDisplay := displayPath(repoRoot, fromPath)
 	toDisplay := displayPath(repoRoot, toPath)
 
 	if len(connections) == 0 {
-		return fmt.Sprintf("No immediate dependency between %s and %s.", fromDisplay, toDisplay)
+		lines := []string{fmt.Sprintf("No immediate dependency between %s and %s.", fromDisplay, toDisplay)}
+		return strings.Join(append(lines, formatPathLines(repoRoot, fromDisplay, toDisplay, paths)...), "\n")
 	}
 
 	lines := []string{
@@ -258,9 +361,78 @@ func formatTextOutput(repoRoot, fromPath, toPath string, connections []directCon
 			}
 		}
 	}
+	lines = append(lines, formatPathLines(repoRoot, fromDisplay, toDisplay, paths)...)
 	return strings.Join(lines, "\n")
 }
 
+// formatPathLines lists the shortest paths found with --transitive. It returns nothing
+// when paths is nil, which means --transitive was not passed.
+func formatPathLines(repoRoot, fromDisplay, toDisplay string, paths [][]string) []string {
+	if paths == nil {
+		return nil
+	}
+	if len(paths) == 0 {
+		return []string{fmt.Sprintf("No dependency path between %s and %s.", fromDisplay, toDisplay)}
+	}
+
+	lines := []string{"Shortest path(s):"}
+	for _, path := range paths {
+		files := make([]string, 0, len(path))
+		for _, file := range path {
+			files = append(files, displayPath(repoRoot, file))
+		}
+		lines = append(lines, "- "+strings.Join(files, " -> "))
+	}
+	return lines
+}
+
+// whyJSONOutput is the top-level object written by -f json. Paths are relative to
+// RepoRoot and use forward slashes.
+type whyJSONOutput struct {
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	RepoRoot    string             `json:"repoRoot"`
+	Connections []directConnection `json:"connections"`
+	// ShortestPaths holds the shortest path in each direction that has one. It is
+	// only written with --transitive.
+	ShortestPaths *[][]string `json:"shortestPaths,omitempty"`
+}
+
+func formatJSONOutput(repoRoot, fromPath, toPath string, connections []directConnection, paths [][]string) (string, error) {
+	output := whyJSONOutput{
+		From:        jsonPath(repoRoot, fromPath),
+		To:          jsonPath(repoRoot, toPath),
+		RepoRoot:    repoRoot,
+		Connections: make([]directConnection, 0, len(connections)),
+	}
+	for _, c := range connections {
+		c.From = jsonPath(repoRoot, c.From)
+		c.To = jsonPath(repoRoot, c.To)
+		output.Connections = append(output.Connections, c)
+	}
+	if paths != nil {
+		shortestPaths := make([][]string, 0, len(paths))
+		for _, path := range paths {
+			files := make([]string, 0, len(path))
+			for _, file := range path {
+				files = append(files, jsonPath(repoRoot, file))
+			}
+			shortestPaths = append(shortestPaths, files)
+		}
+		output.ShortestPaths = &shortestPaths
+	}
+
+	jsonBytes, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+func jsonPath(repoRoot, absolutePath string) string {
+	return filepath.ToSlash(displayPath(repoRoot, absolutePath))
+}
+
 func formatDOTOutput(repoRoot, fromPath, toPath string, connections []directConnection) string {
 	var b strings.Builder
 	b.WriteString("digraph G {\n")
@@ -443,11 +615,23 @@ func enrichMembers(connections []directConnection) {
 	}
 }
 
+// findReferencedMembers returns the members of toPath that fromPath uses. Go pairs
+// report calls, Kotlin pairs the types and functions used, and TypeScript pairs the
+// imports from toPath that are used. Other pairs have no member details.
 func findReferencedMembers(fromPath, toPath string) ([]memberUsage, error) {
-	if filepath.Ext(fromPath) != ".go" || filepath.Ext(toPath) != ".go" {
+	switch fromExt, toExt := filepath.Ext(fromPath), filepath.Ext(toPath); {
+	case fromExt == ".go" && toExt == ".go":
+		return findGoReferencedMembers(fromPath, toPath)
+	case isKotlinFile(fromExt) && isKotlinFile(toExt):
+		return findKotlinReferencedMembers(fromPath, toPath)
+	case isTypeScriptFile(fromExt) && isTypeScriptFile(toExt):
+		return findTypeScriptReferencedMembers(fromPath, toPath)
+	default:
 		return nil, nil
 	}
+}
 
+func findGoReferencedMembers(fromPath, toPath string) ([]memberUsage, error) {
 	targetMembers, err := parseGoTopLevelMembers(toPath)
 	if err != nil {
 		return nil, err
@@ -524,6 +708,12 @@ func parseGoCalledIdentifiers(path string, targetMembers map[string]SymbolMeta)
 		targetMembers: targetMembers,
 		calls:         &calls,
 	}, file)
+	sortMemberUsages(calls)
+	return calls, nil
+}
+
+// sortMemberUsages orders usages by caller, callee and line.
+func sortMemberUsages(calls []memberUsage) {
 	sort.Slice(calls, func(i, j int) bool {
 		if calls[i].Caller != calls[j].Caller {
 			return calls[i].Caller < calls[j].Caller
@@ -533,7 +723,6 @@ func parseGoCalledIdentifiers(path string, targetMembers map[string]SymbolMeta)
 		}
 		return calls[i].Line < calls[j].Line
 	})
-	return calls, nil
 }
 
 type goCallVisitor struct {
@@ -580,7 +769,7 @@ func (v *goCallVisitor) Visit(node ast.Node) ast.Visitor {
 		return v
 	}
 
-	caller := "<file-scope>"
+	caller := fileScopeCaller
 	if len(v.funcStack) > 0 {
 		caller = v.funcStack[len(v.funcStack)-1]
 	}
@@ -703,7 +892,7 @@ func displayPath(repoRoot, absolutePath string) string {
 
 func isSupportedFormat(format string) bool {
 	switch strings.ToLower(format) {
-	case formatText, formatDOT, formatMermaid:
+	case formatText, formatDOT, formatMermaid, formatJSON:
 		return true
 	default:
 		return false
@@ -711,5 +900,5 @@ func isSupportedFormat(format string) bool {
 }
 
 func supportedFormats() string {
-	return strings.Join([]string{formatText, formatDOT, formatMermaid}, ", ")
+	return strings.Join([]string{formatText, formatDOT, formatMermaid, formatJSON}, ", ")
 }
@@ -2,12 +2,16 @@ package why
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
 func TestWhyCommand_TextDirectDependency(t *testing.T) {
@@ -461,3 +465,406 @@ func TestX() {
 	g := testhelpers.MermaidGoldie(t)
 	g.Assert(t, t.Name(), stdout.Bytes())
 }
+
+func TestWhyCommand_JSONFormat_IncludesCallDetails(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, "pkg"), 0o755); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	targetPath := filepath.Join(repoDir, "pkg", "target.go")
+	sourcePath := filepath.Join(repoDir, "pkg", "source_test.go")
+
+	target := `package why
+const Pi = 3.14
+type Graph struct{}
+func ParseSwiftImports() {}
+func (g *Graph) Resolve() {}
+`
+	source := `package why
+func TestX() {
+	ParseSwiftImports()
+	var g Graph
+	g.Resolve()
+	_ = Pi
+}
+`
+	if err := os.WriteFile(targetPath, []byte(target), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	output := runWhyJSON(t, repoDir, "pkg/source_test.go", "pkg/target.go")
+
+	if output.From != "pkg/source_test.go" || output.To != "pkg/target.go" {
+		t.Fatalf("expected repo-relative from/to, got %q and %q", output.From, output.To)
+	}
+	if output.RepoRoot == "" {
+		t.Fatal("expected repoRoot to be set")
+	}
+	if len(output.Connections) != 1 {
+		t.Fatalf("expected one connection, got %#v", output.Connections)
+	}
+	connection := output.Connections[0]
+	if connection.From != "pkg/source_test.go" || connection.To != "pkg/target.go" {
+		t.Fatalf("expected repo-relative connection paths, got %#v", connection)
+	}
+
+	want := map[string]struct {
+		line     int
+		kind     SymbolKind
+		receiver string
+	}{
+		"ParseSwiftImports": {line: sourceLine(t, source, "ParseSwiftImports()"), kind: SymbolKindFunc},
+		"Resolve":           {line: sourceLine(t, source, "g.Resolve()"), kind: SymbolKindMethod, receiver: "*Graph"},
+	}
+	for _, call := range connection.Calls {
+		expected, ok := want[call.Callee.Name]
+		if !ok {
+			continue
+		}
+		if call.Caller != "TestX" {
+			t.Errorf("call to %s: expected caller TestX, got %q", call.Callee.Name, call.Caller)
+		}
+		if call.Line != expected.line {
+			t.Errorf("call to %s: expected line %d, got %d", call.Callee.Name, expected.line, call.Line)
+		}
+		if call.Callee.Meta.Kind != expected.kind || call.Callee.Meta.Receiver != expected.receiver || !call.Callee.Meta.Exported {
+			t.Errorf("call to %s: unexpected symbol metadata %#v", call.Callee.Name, call.Callee.Meta)
+		}
+		delete(want, call.Callee.Name)
+	}
+	if len(want) > 0 {
+		t.Fatalf("missing calls %v in %#v", want, connection.Calls)
+	}
+}
+
+func TestWhyCommand_JSONFormat_NoDirectDependencyHasEmptyConnections(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "a.js"), []byte("export const a = 1\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "b.js"), []byte("export const b = 2\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"-r", repoDir, "-f", "json", "a.js", "b.js"})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), `"connections": []`) {
+		t.Fatalf("expected an empty connections array, got:\n%s", stdout.String())
+	}
+}
+
+func runWhyJSON(t *testing.T, repoDir, from, to string) whyJSONOutput {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"-r", repoDir, "-f", "json", from, to})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	var output whyJSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\noutput:\n%s", err, stdout.String())
+	}
+	return output
+}
+
+// sourceLine returns the 1-based line of the first line in source containing text.
+func sourceLine(t *testing.T, source, text string) int {
+	t.Helper()
+
+	for i, line := range strings.Split(source, "\n") {
+		if strings.Contains(line, text) {
+			return i + 1
+		}
+	}
+	t.Fatalf("%q not found in source", text)
+	return 0
+}
+
+func TestWhyCommand_OutputFile(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "from.js"), []byte("import { x } from './to.js'\nexport const y = x\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "to.js"), []byte("export const x = 1\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	outFile := filepath.Join(t.TempDir(), "out", "why.txt")
+
+	run := func(args ...string) (string, error) {
+		cmd := NewCommand()
+		cmd.SetArgs(append([]string{"-r", repoDir, "from.js", "to.js", "-o", outFile}, args...))
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	stdout, err := run()
+	if err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	if stdout != "" {
+		t.Fatalf("expected nothing on stdout, got:\n%s", stdout)
+	}
+	content, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(content), "from.js depends on to.js") {
+		t.Fatalf("expected the output in the file, got:\n%s", content)
+	}
+
+	if _, err := run(); err == nil || !strings.Contains(err.Error(), "already exists (pass --force to overwrite)") {
+		t.Fatalf("expected an already-exists error, got %v", err)
+	}
+	if _, err := run("--force", "-f", "json"); err != nil {
+		t.Fatalf("cmd.Execute() with --force error = %v", err)
+	}
+}
+
+const kotlinPricingSource = `package app
+
+class Price(val amount: Int)
+
+fun formatPrice(price: Price): String = "${price.amount}"
+
+private fun round(value: Int) = value
+`
+
+const kotlinCheckoutSource = `package app
+
+class Checkout {
+    fun total(): String {
+        val price = Price(3)
+        return formatPrice(price)
+    }
+}
+
+val banner = formatPrice(Price(1))
+`
+
+const typeScriptPricingSource = `export interface Price { amount: number }
+export function formatPrice(price: Price): string { return String(price.amount) }
+export const currency = 'EUR';
+export const unused = 0;
+`
+
+const typeScriptCheckoutSource = `import { formatPrice, Price, currency as defaultCurrency } from './pricing';
+import * as pricing from './pricing';
+
+export function total(price: Price): string {
+  return formatPrice(price) + defaultCurrency;
+}
+
+export const label = (price: Price) => pricing.formatPrice(price);
+`
+
+func writeWhyFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func runWhyCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+	return stdout.String()
+}
+
+func TestWhyCommand_KotlinPair_TextShowsReferencedTypesAndFunctions(t *testing.T) {
+	repoDir := writeWhyFiles(t, map[string]string{"Checkout.kt": kotlinCheckoutSource, "Pricing.kt": kotlinPricingSource})
+
+	output := runWhyCommand(t, "-r", repoDir, "Checkout.kt", "Pricing.kt")
+
+	for _, want := range []string{
+		"- Checkout.kt depends on Pricing.kt",
+		"  members: type Price (type), formatPrice() (func)",
+		fmt.Sprintf("    - total:%d -> type Price (type)", sourceLine(t, kotlinCheckoutSource, "Price(3)")),
+		fmt.Sprintf("    - total:%d -> formatPrice() (func)", sourceLine(t, kotlinCheckoutSource, "return formatPrice")),
+		fmt.Sprintf("    - <file-scope>:%d -> formatPrice() (func)", sourceLine(t, kotlinCheckoutSource, "val banner")),
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "round") {
+		t.Fatalf("did not expect unreferenced private function, got:\n%s", output)
+	}
+}
+
+func TestWhyCommand_KotlinPair_MermaidGolden(t *testing.T) {
+	repoDir := writeWhyFiles(t, map[string]string{"Checkout.kt": kotlinCheckoutSource, "Pricing.kt": kotlinPricingSource})
+
+	output := runWhyCommand(t, "-r", repoDir, "-f", "mermaid", "Checkout.kt", "Pricing.kt")
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+func TestWhyCommand_TypeScriptPair_TextShowsUsedImports(t *testing.T) {
+	repoDir := writeWhyFiles(t, map[string]string{"checkout.ts": typeScriptCheckoutSource, "pricing.ts": typeScriptPricingSource})
+
+	output := runWhyCommand(t, "-r", repoDir, "checkout.ts", "pricing.ts")
+
+	for _, want := range []string{
+		"- checkout.ts depends on pricing.ts",
+		"  members: type Price (type), const currency (const), formatPrice() (func)",
+		fmt.Sprintf("    - total:%d -> const currency (const)", sourceLine(t, typeScriptCheckoutSource, "defaultCurrency;")),
+		fmt.Sprintf("    - label:%d -> formatPrice() (func)", sourceLine(t, typeScriptCheckoutSource, "pricing.formatPrice")),
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "unused") {
+		t.Fatalf("did not expect exports that are not imported, got:\n%s", output)
+	}
+}
+
+func TestWhyCommand_TypeScriptPair_MermaidGolden(t *testing.T) {
+	repoDir := writeWhyFiles(t, map[string]string{"checkout.ts": typeScriptCheckoutSource, "pricing.ts": typeScriptPricingSource})
+
+	output := runWhyCommand(t, "-r", repoDir, "-f", "mermaid", "checkout.ts", "pricing.ts")
+
+	g := testhelpers.MermaidGoldie(t)
+	g.Assert(t, t.Name(), []byte(output))
+}
+
+// recordingContentReader records the files read through it.
+type recordingContentReader struct {
+	vcs.ContentReader
+	mu    sync.Mutex
+	reads map[string]bool
+}
+
+func (r *recordingContentReader) ReadFile(filePath string) ([]byte, error) {
+	r.mu.Lock()
+	r.reads[filePath] = true
+	r.mu.Unlock()
+	return r.ContentReader.ReadFile(filePath)
+}
+
+// writeGoChainRepo writes a module where a imports b, b imports c, and an unrelated
+// package d has files of its own.
+func writeGoChainRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":    "module example.com/m\n\ngo 1.22\n",
+		"a/a.go":    "package a\n\nimport \"example.com/m/b\"\n\nfunc A() { b.B() }\n",
+		"a/util.go": "package a\n\nfunc helper() {}\n",
+		"b/b.go":    "package b\n\nimport \"example.com/m/c\"\n\nfunc B() { c.C() }\n",
+		"c/c.go":    "package c\n\nfunc C() {}\n",
+		"d/d1.go":   "package d\n\nfunc D1() {}\n",
+		"d/d2.go":   "package d\n\nfunc D2() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	return repoDir
+}
+
+func TestWhyCommand_DirectCaseReadsOnlyTheTwoDirectories(t *testing.T) {
+	repoDir := writeGoChainRepo(t)
+	reader := &recordingContentReader{ContentReader: vcs.FilesystemContentReader(), reads: map[string]bool{}}
+
+	cmd := newCommand(reader)
+	cmd.SetArgs([]string{"-r", repoDir, "a/a.go", "b/b.go"})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error = %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "a/a.go depends on b/b.go") {
+		t.Fatalf("expected direct dependency in output, got:\n%s", stdout.String())
+	}
+	for path := range reader.reads {
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil {
+			t.Fatalf("filepath.Rel() error = %v", err)
+		}
+		switch filepath.ToSlash(filepath.Dir(rel)) {
+		case "a", "b", ".":
+		default:
+			t.Fatalf("expected only files in a/, b/ and the module root to be read, read %s", rel)
+		}
+	}
+	for _, want := range []string{"a/a.go", "a/util.go", "b/b.go"} {
+		if !reader.reads[filepath.Join(repoDir, filepath.FromSlash(want))] {
+			t.Fatalf("expected %s to be read, read %v", want, reader.reads)
+		}
+	}
+}
+
+func TestWhyCommand_TransitiveReportsShortestPath(t *testing.T) {
+	repoDir := writeGoChainRepo(t)
+
+	output := runWhyCommand(t, "-r", repoDir, "--transitive", "c/c.go", "a/a.go")
+
+	want := "No immediate dependency between c/c.go and a/a.go.\nShortest path(s):\n- a/a.go -> b/b.go -> c/c.go\n"
+	if output != want {
+		t.Fatalf("expected output:\n%s\ngot:\n%s", want, output)
+	}
+}
+
+func TestWhyCommand_TransitiveWithoutPath(t *testing.T) {
+	repoDir := writeGoChainRepo(t)
+
+	output := runWhyCommand(t, "-r", repoDir, "--transitive", "a/a.go", "d/d1.go")
+
+	if !strings.Contains(output, "No dependency path between a/a.go and d/d1.go.") {
+		t.Fatalf("expected no-path message, got:\n%s", output)
+	}
+}
+
+func TestWhyCommand_JSONShortestPathsOnlyWithTransitive(t *testing.T) {
+	repoDir := writeGoChainRepo(t)
+
+	direct := runWhyCommand(t, "-r", repoDir, "-f", "json", "a/a.go", "c/c.go")
+	if strings.Contains(direct, "shortestPaths") {
+		t.Fatalf("did not expect shortestPaths without --transitive, got:\n%s", direct)
+	}
+
+	var output whyJSONOutput
+	transitive := runWhyCommand(t, "-r", repoDir, "-f", "json", "--transitive", "a/a.go", "c/c.go")
+	if err := json.Unmarshal([]byte(transitive), &output); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if output.ShortestPaths == nil || fmt.Sprint(*output.ShortestPaths) != "[[a/a.go b/b.go c/c.go]]" {
+		t.Fatalf("expected the path from a to c, got:\n%s", transitive)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
 	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/cliconfig"
 	"github.com/spf13/cobra"
 )
 
@@ -59,7 +60,7 @@ func NewCommand() *cobra.Command {
 		"f",
 		opts.outputFormat,
 		fmt.Sprintf("Output format (%s)", formatters.SupportedFormats()))
-	cmd.Flags().StringVarP(&opts.repoPath, "repo", "r", "", "Repository path (default: current directory)")
+	cliconfig.AddRepoAlias(cmd, &opts.repoPath)
 	cmd.Flags().BoolVarP(&opts.generateURL, "url", "u", false, "Generate visualization URL (supported formats: dot, mermaid)")
 	cmd.Flags().StringVarP(
 		&opts.direction,
@@ -78,17 +79,11 @@ func runWorkspace(cmd *cobra.Command, opts *workspaceOptions) error {
 		return err
 	}
 
-	if opts.repoPath == "" {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to determine current working directory: %w", err)
-		}
-		opts.repoPath = cwd
-	}
-	repoPath, err := filepath.Abs(opts.repoPath)
+	repo, err := cliconfig.Resolve(cmd, cliconfig.ResolveOptions{AllowNonRepo: true})
 	if err != nil {
-		return fmt.Errorf("failed to resolve repo path: %w", err)
+		return err
 	}
+	repoPath := repo.RepoPath
 
 	adjacency := make(map[string][]string)
 	manifestNodeByPath := make(map[string]string)
@@ -168,7 +163,7 @@ func runWorkspace(cmd *cobra.Command, opts *workspaceOptions) error {
 			fmt.Fprintln(cmd.OutOrStdout(), urlStr)
 			return nil
 		}
-		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: URL generation is not supported for %s format\n\n", opts.outputFormat)
+		cliconfig.Warnf(cmd, "URL generation is not supported for %s format\n", opts.outputFormat)
 	}
 
 	fmt.Fprintln(cmd.OutOrStdout(), output)
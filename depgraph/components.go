@@ -0,0 +1,120 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+
+	graphlib "github.com/dominikbraun/graph"
+)
+
+// WeaklyConnectedComponents groups the nodes of g into components connected by edges
+// in either direction. Each component is sorted, and components are ordered by size
+// (largest first), then by their first node.
+func WeaklyConnectedComponents(g DependencyGraph) ([][]string, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbors := make(map[string][]string, len(adjacency))
+	for from, deps := range adjacency {
+		for _, to := range deps {
+			neighbors[from] = append(neighbors[from], to)
+			neighbors[to] = append(neighbors[to], from)
+		}
+	}
+
+	nodes := make([]string, 0, len(adjacency))
+	for node := range adjacency {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	visited := make(map[string]bool, len(nodes))
+	var components [][]string
+	for _, start := range nodes {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		component := []string{start}
+		for queue := []string{start}; len(queue) > 0; queue = queue[1:] {
+			for _, next := range neighbors[queue[0]] {
+				if !visited[next] {
+					visited[next] = true
+					component = append(component, next)
+					queue = append(queue, next)
+				}
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		if len(components[i]) != len(components[j]) {
+			return len(components[i]) > len(components[j])
+		}
+		return components[i][0] < components[j][0]
+	})
+	return components, nil
+}
+
+// Subgraph returns the part of fg induced by nodes, keeping edge attributes and the
+// metadata of the retained files, edges and cycles.
+func (fg FileDependencyGraph) Subgraph(nodes []string) (FileDependencyGraph, error) {
+	keep := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		keep[node] = true
+	}
+
+	sub := NewDependencyGraph()
+	sorted := append([]string(nil), nodes...)
+	sort.Strings(sorted)
+	for _, node := range sorted {
+		if err := sub.AddVertex(node); err != nil {
+			return FileDependencyGraph{}, fmt.Errorf("failed to add node %s: %w", node, err)
+		}
+	}
+
+	edges, err := fg.Graph.Edges()
+	if err != nil {
+		return FileDependencyGraph{}, err
+	}
+	for _, edge := range edges {
+		if !keep[edge.Source] || !keep[edge.Target] {
+			continue
+		}
+		attributes := make(map[string]string, len(edge.Properties.Attributes))
+		for key, value := range edge.Properties.Attributes {
+			attributes[key] = value
+		}
+		if err := sub.AddEdge(edge.Source, edge.Target, graphlib.EdgeAttributes(attributes)); err != nil {
+			return FileDependencyGraph{}, fmt.Errorf("failed to add edge %s -> %s: %w", edge.Source, edge.Target, err)
+		}
+	}
+
+	meta := FileGraphMetadata{
+		Files:               make(map[string]FileMetadata),
+		Edges:               make(map[FileEdge]EdgeMetadata),
+		TransitivelyReduced: fg.Meta.TransitivelyReduced,
+	}
+	for node, md := range fg.Meta.Files {
+		if keep[node] {
+			meta.Files[node] = md
+		}
+	}
+	for edge, md := range fg.Meta.Edges {
+		if keep[edge.From] && keep[edge.To] {
+			meta.Edges[edge] = md
+		}
+	}
+	for _, cycle := range fg.Meta.Cycles {
+		// A cycle never spans components, so its first node decides.
+		if len(cycle.Path) > 0 && keep[cycle.Path[0]] {
+			meta.Cycles = append(meta.Cycles, cycle)
+		}
+	}
+
+	return FileDependencyGraph{Graph: sub, Meta: meta}, nil
+}
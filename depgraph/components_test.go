@@ -0,0 +1,88 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+)
+
+func TestWeaklyConnectedComponents_OrdersBySizeThenFirstNode(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"b/app.go":    {"b/db.go"},
+		"b/db.go":     {},
+		"a/main.go":   {"a/util.go"},
+		"a/util.go":   {},
+		"z/entry.go":  {"z/left.go", "z/right.go"},
+		"z/left.go":   {},
+		"z/right.go":  {},
+		"lonely.go":   {},
+		"c/source.go": {},
+		"c/target.go": {"c/source.go"},
+	})
+
+	components, err := WeaklyConnectedComponents(graph)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"z/entry.go", "z/left.go", "z/right.go"},
+		{"a/main.go", "a/util.go"},
+		{"b/app.go", "b/db.go"},
+		{"c/source.go", "c/target.go"},
+		{"lonely.go"},
+	}, components)
+}
+
+func TestWeaklyConnectedComponents_JoinsThroughIncomingEdges(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"a.go":      {"shared.go"},
+		"b.go":      {"shared.go"},
+		"shared.go": {},
+	})
+
+	components, err := WeaklyConnectedComponents(graph)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"a.go", "b.go", "shared.go"}}, components)
+}
+
+func TestFileDependencyGraph_Subgraph_KeepsMetadataOfRetainedNodes(t *testing.T) {
+	graph := NewDependencyGraph()
+	for _, node := range []string{"a.go", "b.go", "c.go", "x.go", "y.go"} {
+		require.NoError(t, graph.AddVertex(node))
+	}
+	require.NoError(t, graph.AddEdge("a.go", "b.go", moduleapi.WithEdgeProvenance(string(EdgeProvenanceHeuristic))))
+	require.NoError(t, graph.AddEdge("b.go", "a.go"))
+	require.NoError(t, graph.AddEdge("b.go", "c.go"))
+	require.NoError(t, graph.AddEdge("x.go", "y.go"))
+
+	fileGraph, err := NewFileDependencyGraph(graph, nil, nil)
+	require.NoError(t, err)
+	fileGraph.Meta.TransitivelyReduced = true
+
+	sub, err := fileGraph.Subgraph([]string{"a.go", "b.go", "c.go"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {"a.go", "c.go"},
+		"c.go": {},
+	}, mustAdjacencyList(t, sub.Graph))
+	assert.Len(t, sub.Meta.Files, 3)
+	assert.NotContains(t, sub.Meta.Files, "x.go")
+	assert.NotContains(t, sub.Meta.Edges, FileEdge{From: "x.go", To: "y.go"})
+	assert.True(t, sub.Meta.Edges[FileEdge{From: "a.go", To: "b.go"}].InCycle)
+	assert.Equal(t, EdgeProvenanceHeuristic, sub.Meta.Edges[FileEdge{From: "a.go", To: "b.go"}].Provenance)
+	assert.Len(t, sub.Meta.Cycles, 1)
+	assert.True(t, sub.Meta.TransitivelyReduced)
+
+	provenances, err := EdgeProvenances(sub.Graph)
+	require.NoError(t, err)
+	assert.Equal(t, EdgeProvenanceHeuristic, provenances[FileEdge{From: "a.go", To: "b.go"}])
+
+	other, err := fileGraph.Subgraph([]string{"x.go", "y.go"})
+	require.NoError(t, err)
+	assert.Empty(t, other.Meta.Cycles)
+}
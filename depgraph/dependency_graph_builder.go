@@ -1,14 +1,19 @@
 package depgraph
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"path/filepath"
-	"runtime"
-	"sync"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	graphlib "github.com/dominikbraun/graph"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
@@ -18,18 +23,121 @@ import (
 // Only dependencies that are in the supplied file list are included in the graph.
 // The contentReader function is used to read file contents (from filesystem, git commit, etc.)
 func BuildDependencyGraph(filePaths []string, contentReader vcs.ContentReader) (DependencyGraph, error) {
-	ctx, err := buildDependencyGraphContext(filePaths, contentReader)
+	graph, _, err := BuildDependencyGraphWithDiagnostics(filePaths, contentReader)
+	return graph, err
+}
+
+// BuildDependencyGraphWithDiagnostics builds the graph like BuildDependencyGraph and
+// also returns the diagnostics language resolvers reported, such as cross-package
+// relative imports.
+func BuildDependencyGraphWithDiagnostics(filePaths []string, contentReader vcs.ContentReader) (DependencyGraph, []moduleapi.Diagnostic, error) {
+	return buildDependencyGraph(context.Background(), filePaths, contentReader, BuildOptions{})
+}
+
+// BuildOptions tunes how BuildDependencyGraphWithOptions builds a graph.
+type BuildOptions struct {
+	// Cache reuses what parsers extracted from files whose content is unchanged. A nil
+	// cache parses every file.
+	Cache *ParseCache
+	// Parallelism bounds how many files are parsed at once; 0 means GOMAXPROCS. It does
+	// not change the graph.
+	Parallelism int
+	// Lenient skips files the intra-package analysis cannot read or parse, logging a
+	// warning for each, instead of failing the build.
+	Lenient bool
+	// ShowExternal keeps the third-party packages files import as external nodes (see
+	// IsExternalNode), one per package, for the languages whose resolvers report them.
+	ShowExternal bool
+	// Progress, when set, is called after each file is resolved with the number
+	// resolved so far and the total. It is called from the resolving goroutines, so it
+	// must be safe for concurrent use.
+	Progress func(parsed, total int)
+	// GoModules maps Go import path prefixes to the absolute directories holding
+	// their packages, replacing go.mod discovery; see moduleapi.Context.GoModules.
+	GoModules map[string]string
+	// PathAliases maps symlinks to the canonical paths they point at, so the files
+	// behind a symlink are one node however they are reached; see
+	// moduleapi.Context.PathAliases.
+	PathAliases map[string]string
+}
+
+// BuildDependencyGraphWithOptions builds the graph like BuildDependencyGraphWithDiagnostics,
+// tuned by opts.
+func BuildDependencyGraphWithOptions(filePaths []string, contentReader vcs.ContentReader, opts BuildOptions) (DependencyGraph, []moduleapi.Diagnostic, error) {
+	return BuildDependencyGraphContext(context.Background(), filePaths, contentReader, opts)
+}
+
+// BuildDependencyGraphContext builds the graph like BuildDependencyGraphWithOptions and
+// stops resolving files once ctx is done, returning ctx's error. Cache failures are
+// logged and never fail the build.
+func BuildDependencyGraphContext(ctx context.Context, filePaths []string, contentReader vcs.ContentReader, opts BuildOptions) (DependencyGraph, []moduleapi.Diagnostic, error) {
+	graph, diagnostics, err := buildDependencyGraph(ctx, filePaths, contentReader, opts)
+	if opts.Cache != nil {
+		opts.Cache.prune()
+	}
+	return graph, diagnostics, err
+}
+
+func buildDependencyGraph(ctx context.Context, filePaths []string, contentReader vcs.ContentReader, opts BuildOptions) (DependencyGraph, []moduleapi.Diagnostic, error) {
+	graphContext, err := buildDependencyGraphContext(filePaths, contentReader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	graphContext.Diagnostics = &moduleapi.Diagnostics{}
+	graphContext.Parallelism = opts.Parallelism
+	graphContext.Lenient = opts.Lenient
+	graphContext.GoModules = opts.GoModules
+	graphContext.PathAliases = opts.PathAliases
+	if opts.Cache != nil {
+		graphContext.ParseCache = opts.Cache
+	}
+	if opts.ShowExternal {
+		graphContext.ExternalImports = &moduleapi.ExternalImports{}
 	}
 
-	return BuildDependencyGraphWithResolver(filePaths, NewDefaultDependencyResolver(ctx, contentReader))
+	graph, err := buildDependencyGraphWithResolver(ctx, filePaths, NewDefaultDependencyResolver(graphContext, contentReader), opts.Parallelism, opts.Progress)
+	if err == nil {
+		err = addExternalImports(graph, graphContext.ExternalImports.All())
+	}
+	return graph, graphContext.Diagnostics.All(), err
+}
+
+// addExternalImports adds a node for every imported external package and an edge to
+// it from each importing file. External nodes have no dependencies of their own.
+func addExternalImports(graph DependencyGraph, imports []moduleapi.ExternalImport) error {
+	for _, imp := range imports {
+		if _, err := graph.Vertex(imp.From); err != nil {
+			continue
+		}
+		if err := graph.AddVertex(imp.Node); err != nil && !errors.Is(err, graphlib.ErrVertexAlreadyExists) {
+			return fmt.Errorf("failed to add external package vertex %s: %w", imp.Node, err)
+		}
+		if err := graph.AddEdge(imp.From, imp.Node); err != nil && !errors.Is(err, graphlib.ErrEdgeAlreadyExists) {
+			return fmt.Errorf("failed to add graph edge %s -> %s: %w", imp.From, imp.Node, err)
+		}
+	}
+	return nil
 }
 
 // BuildDependencyGraphWithResolver builds a graph using the provided DependencyResolver implementation.
 func BuildDependencyGraphWithResolver(
 	filePaths []string,
 	dependencyResolver DependencyResolver,
+) (DependencyGraph, error) {
+	return buildDependencyGraphWithResolver(context.Background(), filePaths, dependencyResolver, 0, nil)
+}
+
+// buildDependencyGraphWithResolver resolves files on up to parallelism goroutines (0
+// means GOMAXPROCS) and then adds them to the graph in input order, so the graph and
+// the error reported for the first failing file do not depend on scheduling. Files
+// not yet resolved when ctx is done are skipped and ctx's error is returned. When
+// progress is set, it is called after each file with the count done so far.
+func buildDependencyGraphWithResolver(
+	ctx context.Context,
+	filePaths []string,
+	dependencyResolver DependencyResolver,
+	parallelism int,
+	progress func(parsed, total int),
 ) (DependencyGraph, error) {
 	graph := NewDependencyGraph()
 
@@ -37,74 +145,61 @@ func BuildDependencyGraphWithResolver(
 		return nil, fmt.Errorf("dependency resolver is required")
 	}
 
-	type resolveResult struct {
-		absPath        string
-		projectImports []string
-		supported      bool
-		err            error
-	}
-
-	workerCount := runtime.GOMAXPROCS(0)
-	if workerCount < 1 {
-		workerCount = 1
-	}
-	if workerCount > len(filePaths) {
-		workerCount = len(filePaths)
-	}
-	if workerCount < 1 {
-		workerCount = 1
-	}
+	extensionDetector, _ := dependencyResolver.(ExtensionDetector)
 
 	results := make([]resolveResult, len(filePaths))
-	jobs := make(chan int)
-	var wg sync.WaitGroup
-
-	for range workerCount {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for idx := range jobs {
-				filePath := filePaths[idx]
-				absPath, err := filepath.Abs(filePath)
-				if err != nil {
-					results[idx] = resolveResult{
-						err: fmt.Errorf("failed to resolve path %s: %w", filePath, err),
-					}
-					continue
-				}
-
-				ext := filepath.Ext(absPath)
-				if !dependencyResolver.SupportsFileExtension(ext) {
-					results[idx] = resolveResult{
-						absPath:   absPath,
-						supported: false,
-					}
-					continue
-				}
-
-				projectImports, err := dependencyResolver.ResolveProjectImports(absPath, filePath, ext)
-				if err != nil {
-					results[idx] = resolveResult{err: err}
-					continue
-				}
-
-				if len(projectImports) > 0 {
-					projectImports = deduplicatePaths(projectImports)
-				}
-				results[idx] = resolveResult{
-					absPath:        absPath,
-					projectImports: projectImports,
-					supported:      true,
-				}
+	var parsed atomic.Int64
+	moduleapi.ParallelFor(len(filePaths), parallelism, func(idx int) {
+		if ctx.Err() != nil {
+			return
+		}
+		if progress != nil {
+			defer func() { progress(int(parsed.Add(1)), len(filePaths)) }()
+		}
+		filePath := filePaths[idx]
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			results[idx] = resolveResult{
+				err: fmt.Errorf("failed to resolve path %s: %w", filePath, err),
 			}
-		}()
-	}
+			return
+		}
+
+		ext := filepath.Ext(absPath)
+		if ext == "" && extensionDetector != nil {
+			ext = extensionDetector.DetectExtension(absPath)
+		}
+		if !dependencyResolver.SupportsFileExtension(ext) {
+			results[idx] = resolveResult{
+				absPath:   absPath,
+				supported: false,
+			}
+			return
+		}
 
-	for i := range filePaths {
-		jobs <- i
+		start := time.Now()
+		projectImports, err := dependencyResolver.ResolveProjectImports(absPath, filePath, ext)
+		elapsed := time.Since(start)
+		if err != nil {
+			results[idx] = resolveResult{err: err}
+			return
+		}
+
+		if len(projectImports) > 0 {
+			projectImports = sortedUniquePaths(projectImports)
+		}
+		results[idx] = resolveResult{
+			absPath:        absPath,
+			ext:            ext,
+			projectImports: projectImports,
+			supported:      true,
+			elapsed:        elapsed,
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	close(jobs)
-	wg.Wait()
+	logParseTimings(results)
 
 	for _, result := range results {
 		if result.err != nil {
@@ -134,11 +229,86 @@ func BuildDependencyGraphWithResolver(
 		return graph, fmt.Errorf("failed to add intra-package dependencies: %w", err)
 	}
 
+	if source, ok := dependencyResolver.(EdgeSymbolSource); ok {
+		if err := applyEdgeSymbols(graph, source.EdgeSymbols()); err != nil {
+			return graph, err
+		}
+	}
+
 	return graph, nil
 }
 
-// deduplicatePaths removes duplicate entries while preserving insertion order
-func deduplicatePaths(paths []string) []string {
+type resolveResult struct {
+	absPath        string
+	ext            string
+	projectImports []string
+	supported      bool
+	elapsed        time.Duration
+	err            error
+}
+
+// logParseTimings logs, at debug level, how many files of each language were parsed
+// and the time spent parsing them, summed over files parsed in parallel.
+func logParseTimings(results []resolveResult) {
+	if !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	type languageTiming struct {
+		files   int
+		elapsed time.Duration
+	}
+	timings := make(map[string]*languageTiming)
+	for _, result := range results {
+		if !result.supported {
+			continue
+		}
+		language := result.ext
+		if module, ok := registry.ModuleForExtension(result.ext); ok {
+			language = module.Name()
+		}
+		timing := timings[language]
+		if timing == nil {
+			timing = &languageTiming{}
+			timings[language] = timing
+		}
+		timing.files++
+		timing.elapsed += result.elapsed
+	}
+
+	languages := make([]string, 0, len(timings))
+	for language := range timings {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		timing := timings[language]
+		slog.Debug("parsed files", "language", language, "files", timing.files, "duration", timing.elapsed)
+	}
+}
+
+// applyEdgeSymbols records the symbols, and the usage counts, resolvers saw on the
+// graph edges they describe. Symbols recorded for dependencies that did not become
+// edges are dropped.
+func applyEdgeSymbols(graph DependencyGraph, symbols *moduleapi.EdgeSymbols) error {
+	for _, edge := range symbols.All() {
+		var options []func(*graphlib.EdgeProperties)
+		if len(edge.Symbols) > 0 {
+			options = append(options, moduleapi.WithEdgeSymbols(edge.Symbols))
+		}
+		if edge.Usages > 0 {
+			options = append(options, moduleapi.WithEdgeUsages(edge.Usages))
+		}
+		err := graph.UpdateEdge(edge.From, edge.To, options...)
+		if err != nil && !errors.Is(err, graphlib.ErrEdgeNotFound) {
+			return fmt.Errorf("failed to record symbols on edge %s -> %s: %w", edge.From, edge.To, err)
+		}
+	}
+	return nil
+}
+
+// sortedUniquePaths removes duplicate entries and sorts the rest, so the edges of a
+// file are added in the same order whatever order its resolver reported them in.
+func sortedUniquePaths(paths []string) []string {
 	seen := make(map[string]bool)
 	result := make([]string, 0, len(paths))
 	for _, p := range paths {
@@ -147,5 +317,6 @@ func deduplicatePaths(paths []string) []string {
 			result = append(result, p)
 		}
 	}
+	sort.Strings(result)
 	return result
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
@@ -22,6 +23,7 @@ func buildDependencyGraphContext(filePaths []string, contentReader vcs.ContentRe
 		JavaFiles:     javaFiles,
 		KotlinFiles:   kotlinFiles,
 		GoFiles:       goFiles,
+		EdgeSymbols:   &moduleapi.EdgeSymbols{},
 	}, nil
 }
 
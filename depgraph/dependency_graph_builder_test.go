@@ -1,9 +1,18 @@
 package depgraph
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
 type stubDependencyResolver struct {
@@ -61,3 +70,329 @@ func TestBuildDependencyGraphWithResolver_UsesResolverExtensionSupport(t *testin
 		t.Fatalf("expected resolver to process only supported file, got %v", resolver.resolvedFiles)
 	}
 }
+
+func TestBuildDependencyGraphWithDiagnostics_MelosCrossPackageRelativeImport(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("languages", "dart", "testdata", "melos"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aTest := filepath.Join(root, "packages", "a", "test", "a_test.dart")
+	helpers := filepath.Join(root, "packages", "b", "test", "utils", "helpers.dart")
+
+	tests := []struct {
+		name       string
+		scope      string
+		wantEdge   bool
+		wantLinked bool
+	}{
+		{name: "unscoped", scope: "packages", wantEdge: true, wantLinked: true},
+		{name: "scoped to package a", scope: filepath.Join("packages", "a"), wantEdge: false, wantLinked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := dartFilesUnder(t, filepath.Join(root, tt.scope))
+
+			graph, diagnostics, err := BuildDependencyGraphWithDiagnostics(files, vcs.FilesystemContentReader())
+			if err != nil {
+				t.Fatalf("BuildDependencyGraphWithDiagnostics() error = %v", err)
+			}
+
+			_, edgeErr := graph.Edge(aTest, helpers)
+			if hasEdge := edgeErr == nil; hasEdge != tt.wantEdge {
+				t.Fatalf("edge a_test.dart -> helpers.dart present = %v, want %v", hasEdge, tt.wantEdge)
+			}
+
+			want := []moduleapi.Diagnostic{{
+				Category: moduleapi.DiagnosticCrossPackageRelativeImport,
+				File:     aTest,
+				Target:   helpers,
+				Linked:   tt.wantLinked,
+			}}
+			if !reflect.DeepEqual(diagnostics, want) {
+				t.Fatalf("diagnostics = %+v, want %+v", diagnostics, want)
+			}
+		})
+	}
+}
+
+func dartFilesUnder(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".dart" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestBuildDependencyGraph_ShebangScriptUsesInterpreterLanguage(t *testing.T) {
+	dir := t.TempDir()
+	toolPath := filepath.Join(dir, "tool")
+	helperPath := filepath.Join(dir, "helper.py")
+	deployPath := filepath.Join(dir, "deploy")
+	writeTestFile(t, toolPath, "#!/usr/bin/env python3\nimport helper\n\nhelper.run()\n")
+	writeTestFile(t, helperPath, "def run():\n    pass\n")
+	writeTestFile(t, deployPath, "#!/bin/bash\nsource ./helper.py\n")
+
+	graph, err := BuildDependencyGraph([]string{toolPath, helperPath, deployPath}, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	adjacency, err := graph.AdjacencyMap()
+	if err != nil {
+		t.Fatalf("AdjacencyMap() error = %v", err)
+	}
+	if _, ok := adjacency[toolPath][helperPath]; !ok {
+		t.Fatalf("expected python shebang script to import %s, got %v", helperPath, adjacency[toolPath])
+	}
+	if len(adjacency[deployPath]) != 0 {
+		t.Fatalf("expected shell script to stay unsupported, got %v", adjacency[deployPath])
+	}
+}
+
+// writeSyntheticTree writes a Go module and a Kotlin source set of n files in total,
+// where each file imports a few of the files before it, and returns their paths.
+func writeSyntheticTree(tb testing.TB, dir string, n int) []string {
+	tb.Helper()
+	writeTestFile(tb, filepath.Join(dir, "go.mod"), "module example.com/synthetic\n\ngo 1.22\n")
+
+	var files []string
+	for i := range n {
+		var path, content string
+		if i%2 == 0 {
+			path = filepath.Join(dir, "pkg", fmt.Sprintf("p%d", i), "file.go")
+			var imports, calls strings.Builder
+			for _, dep := range []int{i - 2, i - 6, i - 20} {
+				if dep >= 0 {
+					fmt.Fprintf(&imports, "\t\"example.com/synthetic/pkg/p%d\"\n", dep)
+					fmt.Fprintf(&calls, "\tp%d.F%d()\n", dep, dep)
+				}
+			}
+			content = fmt.Sprintf("package p%d\n\nimport (\n%s)\n\nfunc F%d() {\n%s}\n", i, imports.String(), i, calls.String())
+		} else {
+			path = filepath.Join(dir, "kotlin", "app", fmt.Sprintf("T%d.kt", i))
+			var uses strings.Builder
+			for _, dep := range []int{i - 2, i - 6} {
+				if dep >= 0 {
+					fmt.Fprintf(&uses, "  val t%d: T%d? = null\n", dep, dep)
+				}
+			}
+			content = fmt.Sprintf("package app\n\nclass T%d {\n%s}\n", i, uses.String())
+		}
+		writeTestFile(tb, path, content)
+		files = append(files, path)
+	}
+	return files
+}
+
+func TestBuildDependencyGraph_ParallelBuildMatchesSequential(t *testing.T) {
+	files := writeSyntheticTree(t, t.TempDir(), 200)
+
+	sequential, _, err := buildDependencyGraph(context.Background(), files, vcs.FilesystemContentReader(), BuildOptions{Parallelism: 1})
+	if err != nil {
+		t.Fatalf("sequential build error = %v", err)
+	}
+	want, err := AdjacencyList(sequential)
+	if err != nil {
+		t.Fatalf("AdjacencyList() error = %v", err)
+	}
+	if len(want[files[10]]) == 0 || len(want[files[11]]) == 0 {
+		t.Fatalf("expected synthetic files to have dependencies, got %v and %v", want[files[10]], want[files[11]])
+	}
+
+	for _, parallelism := range []int{0, 4, 64} {
+		parallel, _, err := buildDependencyGraph(context.Background(), files, vcs.FilesystemContentReader(), BuildOptions{Parallelism: parallelism})
+		if err != nil {
+			t.Fatalf("parallelism %d: build error = %v", parallelism, err)
+		}
+		got, err := AdjacencyList(parallel)
+		if err != nil {
+			t.Fatalf("AdjacencyList() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("parallelism %d: graph differs from the sequential build", parallelism)
+		}
+	}
+}
+
+func TestBuildDependencyGraphWithResolver_ReportsFirstFailingFileInInputOrder(t *testing.T) {
+	files := []string{"a.go", "b.go", "c.go", "d.go"}
+	resolver := &failingDependencyResolver{failing: map[string]bool{"b.go": true, "d.go": true}}
+
+	for _, parallelism := range []int{1, 4} {
+		_, err := buildDependencyGraphWithResolver(context.Background(), files, resolver, parallelism, nil)
+		if err == nil || err.Error() != "failed to parse b.go" {
+			t.Fatalf("parallelism %d: error = %v, want failure for b.go", parallelism, err)
+		}
+	}
+}
+
+func TestBuildDependencyGraphContext_CanceledContextStopsTheBuild(t *testing.T) {
+	files := writeSyntheticTree(t, t.TempDir(), 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	graph, _, err := BuildDependencyGraphContext(ctx, files, vcs.FilesystemContentReader(), BuildOptions{})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if graph != nil {
+		t.Fatalf("graph = %v, want nil", graph)
+	}
+}
+
+type failingDependencyResolver struct {
+	failing map[string]bool
+}
+
+func (f *failingDependencyResolver) SupportsFileExtension(string) bool { return true }
+
+func (f *failingDependencyResolver) ResolveProjectImports(_, filePath, _ string) ([]string, error) {
+	if f.failing[filePath] {
+		return nil, fmt.Errorf("failed to parse %s", filePath)
+	}
+	return nil, nil
+}
+
+func (f *failingDependencyResolver) FinalizeGraph(DependencyGraph) error { return nil }
+
+// BenchmarkBuildDependencyGraph_Parallelism builds a synthetic 1000-file tree parsing
+// one file at a time and one per CPU:
+//
+//	go test ./depgraph -run '^$' -bench BuildDependencyGraph_Parallelism
+func BenchmarkBuildDependencyGraph_Parallelism(b *testing.B) {
+	files := writeSyntheticTree(b, b.TempDir(), 1000)
+
+	for _, bc := range []struct {
+		name        string
+		parallelism int
+	}{
+		{name: "sequential", parallelism: 1},
+		{name: "gomaxprocs", parallelism: 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for range b.N {
+				if _, _, err := buildDependencyGraph(context.Background(), files, vcs.FilesystemContentReader(), BuildOptions{Parallelism: bc.parallelism}); err != nil {
+					b.Fatalf("build error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDependencyGraph_ShowExternalAddsOneNodePerPackage(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "go module",
+			files: map[string]string{
+				"go.mod": "module example.com/app\n\ngo 1.22\n",
+				"a/a.go": "package a\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/spf13/cobra\"\n)\n\nvar _ = fmt.Sprint\nvar _ cobra.Command\n",
+				"b/b.go": "package b\n\nimport \"github.com/spf13/cobra\"\n\nvar _ cobra.Command\n",
+			},
+			want: "go:github.com/spf13/cobra",
+		},
+		{
+			name: "dart package",
+			files: map[string]string{
+				"pubspec.yaml": "name: app\n",
+				"lib/a.dart":   "import 'dart:io';\nimport 'package:flutter/material.dart';\n",
+				"lib/b.dart":   "import 'package:flutter/material.dart';\nimport 'package:app/a.dart';\n",
+			},
+			want: "pkg:flutter/material",
+		},
+		{
+			name: "npm bare specifiers",
+			files: map[string]string{
+				"src/a.ts": "import { map } from 'lodash';\nimport { readFileSync } from 'node:fs';\nexport const a = map;\n",
+				"src/b.ts": "import fp from 'lodash/fp';\nimport { a } from './a';\nexport const b = [fp, a];\n",
+			},
+			want: "npm:lodash",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var files []string
+			for name, content := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				writeTestFile(t, path, content)
+				if ext := filepath.Ext(name); ext == ".go" || ext == ".dart" || ext == ".ts" {
+					files = append(files, path)
+				}
+			}
+
+			graph, _, err := BuildDependencyGraphContext(context.Background(), files, vcs.FilesystemContentReader(), BuildOptions{ShowExternal: true})
+			if err != nil {
+				t.Fatalf("BuildDependencyGraphContext() error = %v", err)
+			}
+			adjacency, err := AdjacencyList(graph)
+			if err != nil {
+				t.Fatalf("AdjacencyList() error = %v", err)
+			}
+
+			var external []string
+			importers := 0
+			for node, deps := range adjacency {
+				if IsExternalNode(node) {
+					external = append(external, node)
+				}
+				for _, dep := range deps {
+					if dep == tt.want {
+						importers++
+					}
+				}
+			}
+			if !reflect.DeepEqual(external, []string{tt.want}) {
+				t.Fatalf("external nodes = %v, want [%s]", external, tt.want)
+			}
+			if importers != 2 {
+				t.Fatalf("importers of %s = %d, want 2", tt.want, importers)
+			}
+			if len(adjacency[tt.want]) != 0 {
+				t.Fatalf("external node has dependencies %v, want none", adjacency[tt.want])
+			}
+
+			fileGraph, err := NewFileDependencyGraph(graph, nil, nil)
+			if err != nil {
+				t.Fatalf("NewFileDependencyGraph() error = %v", err)
+			}
+			if kind := fileGraph.Meta.Files[tt.want].Kind; kind != NodeKindExternal {
+				t.Fatalf("kind = %q, want %q", kind, NodeKindExternal)
+			}
+		})
+	}
+}
+
+func TestBuildDependencyGraph_ExternalPackagesAreDroppedByDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "src", "a.ts")
+	writeTestFile(t, path, "import { map } from 'lodash';\nexport const a = map;\n")
+
+	graph, err := BuildDependencyGraph([]string{path}, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+	adjacency, err := AdjacencyList(graph)
+	if err != nil {
+		t.Fatalf("AdjacencyList() error = %v", err)
+	}
+	if want := map[string][]string{path: {}}; !reflect.DeepEqual(adjacency, want) {
+		t.Fatalf("adjacency = %v, want %v", adjacency, want)
+	}
+}
@@ -1,6 +1,7 @@
 package depgraph_test
 
 import (
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
@@ -148,6 +149,46 @@ data class ActivateLicenseResponse(val license: String)
 	assert.Empty(t, adj[responsePath])
 }
 
+func TestBuildDependencyGraph_KotlinUnicodeIdentifiers(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	unitPath := filepath.Join(tmpDir, "Ünit.kt")
+	require.NoError(t, os.WriteFile(unitPath, []byte(`
+package com.例え.モデル
+
+data class Ünit(val name: String)
+`), 0644))
+
+	factoryPath := filepath.Join(tmpDir, "クラス.kt")
+	require.NoError(t, os.WriteFile(factoryPath, []byte(`
+package com.例え.モデル
+
+class クラス {
+  fun create(): Ünit = Ünit("x")
+}
+`), 0644))
+
+	appPath := filepath.Join(tmpDir, "App.kt")
+	require.NoError(t, os.WriteFile(appPath, []byte(`
+package com.例え.app
+
+import com.例え.モデル.クラス
+
+fun main() {
+  println(クラス().create())
+}
+`), 0644))
+
+	files := []string{unitPath, factoryPath, appPath}
+	graph, err := depgraph.BuildDependencyGraph(files, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+	adj := mustAdjacency(t, graph)
+
+	assert.Equal(t, []string{unitPath}, adj[factoryPath])
+	assert.Equal(t, []string{factoryPath}, adj[appPath])
+	assert.Empty(t, adj[unitPath])
+}
+
 func TestBuildDependencyGraph_EmptyFileList(t *testing.T) {
 	graph, err := depgraph.BuildDependencyGraph([]string{}, vcs.FilesystemContentReader())
 
@@ -588,6 +629,97 @@ func main() {
 	// Check output_format.go dependencies (should have none)
 	typesDeps := adj[typesPath]
 	assert.Empty(t, typesDeps, "output_format.go has no dependencies")
+
+	// Each edge records the symbols used across it
+	symbols, err := depgraph.EdgeSymbols(graph)
+	require.NoError(t, err)
+	assert.Equal(t, map[depgraph.FileEdge][]string{
+		{From: mainPath, To: typesPath}:    {"Product", "User"},
+		{From: mainPath, To: helpersPath}:  {"FormatUser"},
+		{From: helpersPath, To: typesPath}: {"User"},
+	}, symbols)
+}
+
+func TestBuildDependencyGraph_GoEdgeUsagesCountReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module usagetest\n\ngo 1.25\n",
+		"types.go": `package main
+
+type User struct {
+	Name string
+}
+`,
+		"helpers.go": `package main
+
+func FormatUser(u User) string {
+	return u.Name
+}
+`,
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"usagetest/util"
+)
+
+func main() {
+	u := User{Name: "Alice"}
+	fmt.Println(FormatUser(u), FormatUser(u), FormatUser(u))
+	fmt.Println(util.Shout(u.Name), util.Shout("again"))
+}
+`,
+		"util/shout.go": `package util
+
+func Shout(s string) string {
+	return s + "!"
+}
+`,
+	}
+	paths := make(map[string]string, len(files))
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		paths[name] = path
+	}
+
+	graph, err := depgraph.BuildDependencyGraph(
+		[]string{paths["types.go"], paths["helpers.go"], paths["main.go"], paths["util/shout.go"]},
+		vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	usages, err := depgraph.EdgeUsages(graph)
+	require.NoError(t, err)
+	assert.Equal(t, map[depgraph.FileEdge]int{
+		{From: paths["main.go"], To: paths["helpers.go"]}:    3,
+		{From: paths["main.go"], To: paths["types.go"]}:      1,
+		{From: paths["main.go"], To: paths["util/shout.go"]}: 2,
+		{From: paths["helpers.go"], To: paths["types.go"]}:   1,
+	}, usages)
+
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+	assert.Equal(t, 3, fileGraph.Meta.Edges[depgraph.FileEdge{From: paths["main.go"], To: paths["helpers.go"]}].Usages)
+}
+
+func TestEdgeUsageWeight(t *testing.T) {
+	tests := []struct {
+		usages int
+		want   float64
+	}{
+		{usages: 0, want: 1},
+		{usages: 1, want: 1},
+		{usages: 2, want: 2},
+		{usages: 8, want: 4},
+		{usages: 12, want: 1 + math.Log2(12)},
+		{usages: 1000, want: depgraph.MaxEdgeUsageWeight},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, depgraph.EdgeUsageWeight(tt.usages), 1e-9, "usages %d", tt.usages)
+	}
 }
 
 func TestBuildDependencyGraph_KotlinFiles(t *testing.T) {
@@ -761,6 +893,120 @@ data class Order(val id: Int, val userId: Int)`
 	assert.Empty(t, adj[orderPath])
 }
 
+func TestBuildDependencyGraph_KotlinExpectActualPairs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pkgPath := filepath.Join("kotlin", "com", "example", "time")
+	commonFile := filepath.Join(tmpDir, "src", "commonMain", pkgPath, "PlatformClock.kt")
+	androidFile := filepath.Join(tmpDir, "src", "androidMain", pkgPath, "PlatformClock.android.kt")
+	iosFile := filepath.Join(tmpDir, "src", "iosMain", pkgPath, "PlatformClock.ios.kt")
+
+	files := map[string]string{
+		commonFile: `package com.example.time
+
+expect class PlatformClock() {
+    fun now(): Long
+}
+`,
+		androidFile: `package com.example.time
+
+actual class PlatformClock actual constructor() {
+    actual fun now(): Long = System.currentTimeMillis()
+}
+`,
+		iosFile: `package com.example.time
+
+actual class PlatformClock actual constructor() {
+    actual fun now(): Long = 0L
+}
+`,
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	graph, err := depgraph.BuildDependencyGraph([]string{commonFile, androidFile, iosFile}, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.Equal(t, []string{commonFile}, adj[androidFile])
+	assert.Equal(t, []string{commonFile}, adj[iosFile])
+	assert.Empty(t, adj[commonFile])
+
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, nil)
+	require.NoError(t, err)
+	for _, actual := range []string{androidFile, iosFile} {
+		md := fileGraph.Meta.Edges[depgraph.FileEdge{From: actual, To: commonFile}]
+		assert.Equal(t, depgraph.EdgeProvenanceExpectActual, md.Provenance)
+		assert.False(t, md.InCycle)
+	}
+}
+
+func TestBuildDependencyGraph_ScalaSbtProject(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	srcDir := filepath.Join(tmpDir, "src", "main", "scala", "com", "acme")
+	appFile := filepath.Join(srcDir, "app", "App.scala")
+	billingFile := filepath.Join(srcDir, "service", "Billing.scala")
+	invoiceFile := filepath.Join(srcDir, "model", "Invoice.scala")
+	statusFile := filepath.Join(srcDir, "model", "Status.scala")
+	configFile := filepath.Join(srcDir, "Config.scala")
+
+	files := map[string]string{
+		filepath.Join(tmpDir, "build.sbt"): `scalaVersion := "2.13.12"
+`,
+		appFile: `package com.acme
+package app
+
+import service.Billing
+import com.acme.{Config, Missing}
+
+object App {
+  def main(args: Array[String]): Unit = Billing.run(Config.default)
+}
+`,
+		billingFile: `package com.acme.service
+
+import com.acme.model._
+
+object Billing {
+  def run(config: com.acme.Config): Invoice = Invoice("1")
+}
+`,
+		invoiceFile: `package com.acme.model
+
+case class Invoice(id: String) {
+  def status: Status = Open
+}
+`,
+		statusFile: `package com.acme.model
+
+sealed trait Status
+case object Open extends Status
+`,
+		configFile: `package com.acme
+
+case class Config(name: String)
+object Config { val default = Config("x") }
+`,
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	graph, err := depgraph.BuildDependencyGraph([]string{appFile, billingFile, invoiceFile, statusFile, configFile}, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.ElementsMatch(t, []string{billingFile, configFile}, adj[appFile])
+	assert.ElementsMatch(t, []string{invoiceFile, configFile}, adj[billingFile])
+	assert.ElementsMatch(t, []string{statusFile}, adj[invoiceFile])
+	assert.Empty(t, adj[statusFile])
+	assert.Empty(t, adj[configFile])
+}
+
 func TestBuildDependencyGraph_TypeScriptFiles(t *testing.T) {
 	// Create temporary directory with test TypeScript files
 	tmpDir := t.TempDir()
@@ -1004,6 +1250,66 @@ export function helper() {}
 	assert.Contains(t, indexDeps, utilsPath)
 }
 
+func TestBuildDependencyGraph_MixedTypeScriptAndJavaScript(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "components"), 0o755))
+
+	files := map[string]string{
+		"App.jsx":              "import Button from './components/Button'\nimport { format } from './format'\n",
+		"components/Button.js": "const legacy = require('../legacy')\nmodule.exports = function Button() {}\n",
+		"format.ts":            "import legacy = require('./legacy')\nexport function format() {}\n",
+		"legacy.js":            "module.exports = {}\n",
+		"main.ts":              "import App from './App'\nconst { format } = require('./format')\n",
+	}
+	paths := make(map[string]string, len(files))
+	var supplied []string
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		paths[name] = path
+		supplied = append(supplied, path)
+	}
+
+	graph, err := depgraph.BuildDependencyGraph(supplied, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.ElementsMatch(t, []string{paths["components/Button.js"], paths["format.ts"]}, adj[paths["App.jsx"]])
+	assert.Equal(t, []string{paths["legacy.js"]}, adj[paths["components/Button.js"]])
+	assert.Equal(t, []string{paths["legacy.js"]}, adj[paths["format.ts"]])
+	assert.ElementsMatch(t, []string{paths["App.jsx"], paths["format.ts"]}, adj[paths["main.ts"]])
+}
+
+func TestBuildDependencyGraph_DartExportsPartsAndConditionalImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "src"), 0o755))
+
+	files := map[string]string{
+		"main.dart":        "import 'shapes.dart';\nimport 'platform_io.dart' if (dart.library.html) 'platform_web.dart';\n",
+		"shapes.dart":      "library shapes;\nexport 'src/square.dart';\npart 'src/circle.dart';\n",
+		"src/circle.dart":  "part of '../shapes.dart';\n",
+		"src/square.dart":  "class Square {}\n",
+		"platform_io.dart": "void platform() {}\n",
+	}
+	paths := make(map[string]string, len(files))
+	var supplied []string
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		paths[name] = path
+		supplied = append(supplied, path)
+	}
+
+	graph, err := depgraph.BuildDependencyGraph(supplied, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	// platform_web.dart is not supplied, so only the io branch is linked
+	assert.ElementsMatch(t, []string{paths["shapes.dart"], paths["platform_io.dart"]}, adj[paths["main.dart"]])
+	assert.ElementsMatch(t, []string{paths["src/square.dart"], paths["src/circle.dart"]}, adj[paths["shapes.dart"]])
+	assert.Equal(t, []string{paths["shapes.dart"]}, adj[paths["src/circle.dart"]])
+}
+
 func TestBuildDependencyGraph_GoEmbed(t *testing.T) {
 	// Create temporary directory with Go files using //go:embed
 	tmpDir := t.TempDir()
@@ -1,6 +1,7 @@
 package depgraph
 
 import (
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
@@ -12,15 +13,31 @@ type DependencyResolver interface {
 	FinalizeGraph(graph DependencyGraph) error
 }
 
+// ExtensionDetector is implemented by dependency resolvers that can recognize the
+// language of files without an extension.
+type ExtensionDetector interface {
+	DetectExtension(absPath string) string
+}
+
+// EdgeSymbolSource is implemented by dependency resolvers that record which symbols
+// each file uses from its dependencies.
+type EdgeSymbolSource interface {
+	EdgeSymbols() *moduleapi.EdgeSymbols
+}
+
 type defaultDependencyResolver struct {
 	extensionResolvers map[string]registry.Resolver
 	resolvers          []registry.Resolver
+	detector           *LanguageDetector
+	symbols            *moduleapi.EdgeSymbols
 }
 
 // NewDefaultDependencyResolver creates the built-in language-aware dependency resolver.
 func NewDefaultDependencyResolver(ctx *dependencyGraphContext, contentReader vcs.ContentReader) DependencyResolver {
 	resolver := &defaultDependencyResolver{
 		extensionResolvers: make(map[string]registry.Resolver),
+		detector:           NewLanguageDetector(contentReader),
+		symbols:            ctx.EdgeSymbols,
 	}
 
 	for _, module := range registry.Modules() {
@@ -43,6 +60,14 @@ func (b *defaultDependencyResolver) SupportsFileExtension(ext string) bool {
 	return ok
 }
 
+func (b *defaultDependencyResolver) DetectExtension(absPath string) string {
+	return b.detector.Extension(absPath)
+}
+
+func (b *defaultDependencyResolver) EdgeSymbols() *moduleapi.EdgeSymbols {
+	return b.symbols
+}
+
 func (b *defaultDependencyResolver) ResolveProjectImports(absPath, filePath, ext string) ([]string, error) {
 	resolver, ok := b.extensionResolvers[ext]
 	if !ok {
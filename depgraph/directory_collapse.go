@@ -0,0 +1,133 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	graphlib "github.com/dominikbraun/graph"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+)
+
+// CollapseByDirectory merges every file nested depth or more directories below basePath
+// into one node named after its ancestor directory at that depth. Files in shallower
+// directories and files outside basePath are kept as-is. Edges between merged nodes are
+// deduplicated and edges inside a merged node are dropped. A merged edge keeps a
+// provenance only when every edge it stands for shares it.
+func CollapseByDirectory(g DependencyGraph, basePath string, depth int) (DependencyGraph, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("collapse depth must be at least 1, got %d", depth)
+	}
+
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, 0, len(adjacency))
+	for node := range adjacency {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	collapsed := NewDependencyGraph()
+	for _, node := range nodes {
+		if err := collapsed.AddVertex(collapsedNode(node, basePath, depth)); err != nil && !errors.Is(err, graphlib.ErrVertexAlreadyExists) {
+			return nil, err
+		}
+	}
+
+	provenances, err := EdgeProvenances(g)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedProvenance := make(map[FileEdge]EdgeProvenance)
+	var merged []FileEdge
+	for _, from := range nodes {
+		for _, to := range adjacency[from] {
+			edge := FileEdge{From: collapsedNode(from, basePath, depth), To: collapsedNode(to, basePath, depth)}
+			if edge.From == edge.To {
+				continue
+			}
+			provenance := provenances[FileEdge{From: from, To: to}]
+			if existing, ok := mergedProvenance[edge]; ok {
+				if existing != provenance {
+					mergedProvenance[edge] = EdgeProvenanceParsed
+				}
+				continue
+			}
+			mergedProvenance[edge] = provenance
+			merged = append(merged, edge)
+		}
+	}
+
+	for _, edge := range merged {
+		var options []func(*graphlib.EdgeProperties)
+		if provenance := mergedProvenance[edge]; provenance != EdgeProvenanceParsed {
+			options = append(options, moduleapi.WithEdgeProvenance(string(provenance)))
+		}
+		if err := collapsed.AddEdge(edge.From, edge.To, options...); err != nil {
+			return nil, err
+		}
+	}
+
+	return collapsed, nil
+}
+
+// CollapseDepthForLimit picks the collapse depth for a graph over nodes that exceeds
+// limit. It returns the deepest depth at which CollapseByDirectory leaves at most limit
+// nodes, so as much structure as possible survives. When even depth 1 is over the
+// limit, it returns 1 and false.
+func CollapseDepthForLimit(nodes []string, basePath string, limit int) (int, bool) {
+	for depth := maxDirectoryDepth(nodes, basePath); depth >= 1; depth-- {
+		if CollapsedNodeCount(nodes, basePath, depth) <= limit {
+			return depth, true
+		}
+	}
+	return 1, false
+}
+
+// CollapsedNodeCount returns how many nodes remain after collapsing nodes at depth.
+func CollapsedNodeCount(nodes []string, basePath string, depth int) int {
+	seen := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		seen[collapsedNode(node, basePath, depth)] = true
+	}
+	return len(seen)
+}
+
+// collapsedNode returns the node that filePath is merged into at depth: its ancestor
+// directory depth levels below basePath, or filePath itself when it is not nested
+// that deep.
+func collapsedNode(filePath, basePath string, depth int) string {
+	dirs := relativeDirectories(filePath, basePath)
+	if len(dirs) < depth {
+		return filePath
+	}
+	return filepath.Join(append([]string{basePath}, dirs[:depth]...)...)
+}
+
+// maxDirectoryDepth returns the deepest directory nesting below basePath among nodes.
+func maxDirectoryDepth(nodes []string, basePath string) int {
+	deepest := 0
+	for _, node := range nodes {
+		if dirs := relativeDirectories(node, basePath); len(dirs) > deepest {
+			deepest = len(dirs)
+		}
+	}
+	return deepest
+}
+
+// relativeDirectories returns the directories between basePath and filePath. Files
+// outside basePath have none, so they are never collapsed.
+func relativeDirectories(filePath, basePath string) []string {
+	rel, err := filepath.Rel(basePath, filepath.Dir(filePath))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	return strings.Split(rel, string(filepath.Separator))
+}
@@ -0,0 +1,124 @@
+package depgraph
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+)
+
+var collapseBase = filepath.FromSlash("/repo")
+
+func collapseRepoPath(rel string) string {
+	return filepath.Join(collapseBase, filepath.FromSlash(rel))
+}
+
+// fanOutTree returns 13 files: one at the root, 3 in a/, 4 in a/b/ and 5 in c/d/e/.
+func fanOutTree() []string {
+	nodes := []string{collapseRepoPath("main.go")}
+	for i := 0; i < 3; i++ {
+		nodes = append(nodes, collapseRepoPath(fmt.Sprintf("a/x%d.go", i)))
+	}
+	for i := 0; i < 4; i++ {
+		nodes = append(nodes, collapseRepoPath(fmt.Sprintf("a/b/y%d.go", i)))
+	}
+	for i := 0; i < 5; i++ {
+		nodes = append(nodes, collapseRepoPath(fmt.Sprintf("c/d/e/z%d.go", i)))
+	}
+	return nodes
+}
+
+func TestCollapsedNodeCount_FanOutTree(t *testing.T) {
+	nodes := fanOutTree()
+
+	assert.Equal(t, 3, CollapsedNodeCount(nodes, collapseBase, 1))
+	assert.Equal(t, 6, CollapsedNodeCount(nodes, collapseBase, 2))
+	assert.Equal(t, 9, CollapsedNodeCount(nodes, collapseBase, 3))
+	assert.Equal(t, 13, CollapsedNodeCount(nodes, collapseBase, 4))
+}
+
+func TestCollapseDepthForLimit_PicksDeepestDepthUnderLimit(t *testing.T) {
+	nodes := fanOutTree()
+
+	tests := []struct {
+		limit     int
+		wantDepth int
+		wantFits  bool
+	}{
+		{limit: 12, wantDepth: 3, wantFits: true},
+		{limit: 9, wantDepth: 3, wantFits: true},
+		{limit: 8, wantDepth: 2, wantFits: true},
+		{limit: 6, wantDepth: 2, wantFits: true},
+		{limit: 5, wantDepth: 1, wantFits: true},
+		{limit: 3, wantDepth: 1, wantFits: true},
+		{limit: 2, wantDepth: 1, wantFits: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("limit %d", tt.limit), func(t *testing.T) {
+			depth, fits := CollapseDepthForLimit(nodes, collapseBase, tt.limit)
+			assert.Equal(t, tt.wantDepth, depth)
+			assert.Equal(t, tt.wantFits, fits)
+		})
+	}
+}
+
+func TestCollapseDepthForLimit_FlatTreeCannotFit(t *testing.T) {
+	nodes := []string{collapseRepoPath("a.go"), collapseRepoPath("b.go"), collapseRepoPath("c.go")}
+
+	depth, fits := CollapseDepthForLimit(nodes, collapseBase, 2)
+
+	assert.Equal(t, 1, depth)
+	assert.False(t, fits)
+}
+
+func TestCollapseByDirectory_MergesEdgesAndDropsInternalOnes(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		collapseRepoPath("main.go"):  {collapseRepoPath("a/x.go"), collapseRepoPath("a/b/y.go")},
+		collapseRepoPath("a/x.go"):   {collapseRepoPath("a/b/y.go"), collapseRepoPath("c/z.go")},
+		collapseRepoPath("a/b/y.go"): {collapseRepoPath("c/z.go")},
+		collapseRepoPath("c/z.go"):   {},
+		"/elsewhere/outside.go":      {collapseRepoPath("a/x.go")},
+	})
+
+	collapsed, err := CollapseByDirectory(graph, collapseBase, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		collapseRepoPath("main.go"): {collapseRepoPath("a")},
+		collapseRepoPath("a"):       {collapseRepoPath("c")},
+		collapseRepoPath("c"):       {},
+		"/elsewhere/outside.go":     {collapseRepoPath("a")},
+	}, mustAdjacencyList(t, collapsed))
+}
+
+func TestCollapseByDirectory_KeepsSharedProvenanceOnly(t *testing.T) {
+	graph := NewDependencyGraph()
+	for _, node := range []string{"a/1.go", "a/2.go", "b/1.go", "c/1.go"} {
+		require.NoError(t, graph.AddVertex(collapseRepoPath(node)))
+	}
+	heuristic := moduleapi.WithEdgeProvenance(string(EdgeProvenanceHeuristic))
+	require.NoError(t, graph.AddEdge(collapseRepoPath("a/1.go"), collapseRepoPath("b/1.go"), heuristic))
+	require.NoError(t, graph.AddEdge(collapseRepoPath("a/2.go"), collapseRepoPath("b/1.go"), heuristic))
+	require.NoError(t, graph.AddEdge(collapseRepoPath("a/1.go"), collapseRepoPath("c/1.go"), heuristic))
+	require.NoError(t, graph.AddEdge(collapseRepoPath("a/2.go"), collapseRepoPath("c/1.go")))
+
+	collapsed, err := CollapseByDirectory(graph, collapseBase, 1)
+
+	require.NoError(t, err)
+	provenances, err := EdgeProvenances(collapsed)
+	require.NoError(t, err)
+	assert.Equal(t, map[FileEdge]EdgeProvenance{
+		{From: collapseRepoPath("a"), To: collapseRepoPath("b")}: EdgeProvenanceHeuristic,
+	}, provenances)
+}
+
+func TestCollapseByDirectory_RejectsDepthBelowOne(t *testing.T) {
+	_, err := CollapseByDirectory(NewDependencyGraph(), collapseBase, 0)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package depgraph
+
+import "math"
+
+// MaxEdgeUsageWeight caps EdgeUsageWeight, so a file calling another hundreds of times
+// does not drown out the rest of the graph.
+const MaxEdgeUsageWeight = 5.0
+
+// EdgeUsageWeight scales the usage count of an edge for rendering, such as a line
+// width: 1 for a single usage, growing by 1 each time the count doubles, up to
+// MaxEdgeUsageWeight. Edges without a count weigh 1.
+func EdgeUsageWeight(usages int) float64 {
+	if usages <= 1 {
+		return 1
+	}
+	return min(1+math.Log2(float64(usages)), MaxEdgeUsageWeight)
+}
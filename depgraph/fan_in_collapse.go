@@ -0,0 +1,81 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FanInCollapse is the outcome of CollapseHighFanIn.
+type FanInCollapse struct {
+	// Graph is the input graph without the incoming edges of the collapsed files.
+	Graph DependencyGraph
+	// Dependents maps each collapsed file to the number of files that imported it.
+	Dependents map[string]int
+	// RemovedEdges lists the incoming edges dropped from the collapsed files.
+	RemovedEdges []FileEdge
+}
+
+// CollapseHighFanIn removes the incoming edges of every file imported by more than
+// threshold files, so that widely shared files such as logging or constants do not
+// turn the drawing into a hairball. All files are kept, and the number of importers
+// of each collapsed file is reported so it can be shown on the file instead. A
+// threshold of 0 or less leaves the graph as-is. Edge attributes are preserved.
+func CollapseHighFanIn(g DependencyGraph, threshold int) (FanInCollapse, error) {
+	if threshold <= 0 {
+		return FanInCollapse{Graph: g}, nil
+	}
+
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return FanInCollapse{}, err
+	}
+	importers := reverseAdjacencyList(adjacency)
+
+	dependents := make(map[string]int)
+	for file, from := range importers {
+		if len(from) > threshold {
+			dependents[file] = len(from)
+		}
+	}
+	if len(dependents) == 0 {
+		return FanInCollapse{Graph: g}, nil
+	}
+
+	collapsed, err := g.Clone()
+	if err != nil {
+		return FanInCollapse{}, fmt.Errorf("failed to clone graph: %w", err)
+	}
+
+	var removed []FileEdge
+	for file := range dependents {
+		for _, from := range importers[file] {
+			if err := collapsed.RemoveEdge(from, file); err != nil {
+				return FanInCollapse{}, fmt.Errorf("failed to remove edge %s -> %s: %w", from, file, err)
+			}
+			removed = append(removed, FileEdge{From: from, To: file})
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].From != removed[j].From {
+			return removed[i].From < removed[j].From
+		}
+		return removed[i].To < removed[j].To
+	})
+
+	return FanInCollapse{
+		Graph:        collapsed,
+		Dependents:   dependents,
+		RemovedEdges: removed,
+	}, nil
+}
+
+// reverseAdjacencyList maps each file to the files that depend on it.
+func reverseAdjacencyList(adjacency map[string][]string) map[string][]string {
+	reversed := make(map[string][]string, len(adjacency))
+	for from, deps := range adjacency {
+		for _, to := range deps {
+			reversed[to] = append(reversed[to], from)
+		}
+	}
+	return reversed
+}
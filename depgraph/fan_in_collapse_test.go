@@ -0,0 +1,62 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// starGraph has four files importing hub.go, which imports nothing.
+func starGraph() DependencyGraph {
+	return testGraph(map[string][]string{
+		"a.go":   {"hub.go"},
+		"b.go":   {"hub.go"},
+		"c.go":   {"hub.go", "a.go"},
+		"d.go":   {"hub.go"},
+		"hub.go": {},
+	})
+}
+
+func TestCollapseHighFanIn_ThresholdBelowHubDegree(t *testing.T) {
+	result, err := CollapseHighFanIn(starGraph(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"hub.go": 4}, result.Dependents)
+	assert.Equal(t, []FileEdge{
+		{From: "a.go", To: "hub.go"},
+		{From: "b.go", To: "hub.go"},
+		{From: "c.go", To: "hub.go"},
+		{From: "d.go", To: "hub.go"},
+	}, result.RemovedEdges)
+	assert.Equal(t, map[string][]string{
+		"a.go":   {},
+		"b.go":   {},
+		"c.go":   {"a.go"},
+		"d.go":   {},
+		"hub.go": {},
+	}, mustAdjacencyList(t, result.Graph))
+}
+
+func TestCollapseHighFanIn_ThresholdAtOrAboveHubDegree(t *testing.T) {
+	for _, threshold := range []int{4, 10} {
+		graph := starGraph()
+
+		result, err := CollapseHighFanIn(graph, threshold)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Dependents)
+		assert.Empty(t, result.RemovedEdges)
+		assert.Equal(t, mustAdjacencyList(t, graph), mustAdjacencyList(t, result.Graph))
+	}
+}
+
+func TestCollapseHighFanIn_ZeroThresholdIsOff(t *testing.T) {
+	graph := starGraph()
+
+	result, err := CollapseHighFanIn(graph, 0)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Dependents)
+	assert.Equal(t, mustAdjacencyList(t, graph), mustAdjacencyList(t, result.Graph))
+}
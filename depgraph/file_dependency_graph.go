@@ -3,7 +3,10 @@ package depgraph
 import (
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
@@ -19,14 +22,83 @@ type FileGraphMetadata struct {
 	Files  map[string]FileMetadata
 	Edges  map[FileEdge]EdgeMetadata
 	Cycles []FileCycle
+	// TransitivelyReduced reports that edges implied by longer paths were removed, so
+	// Edges is not the raw dependency set.
+	TransitivelyReduced bool
+}
+
+// NodeKind tells file nodes from the other nodes a graph may hold.
+type NodeKind string
+
+const (
+	// NodeKindFile marks a file of the analyzed tree. It is the zero value.
+	NodeKindFile NodeKind = ""
+	// NodeKindExternal marks a third-party package kept with BuildOptions.ShowExternal.
+	// External nodes have no stats, test flag, or extension, and no dependencies.
+	NodeKindExternal NodeKind = "external"
+	// NodeKindManifest marks a file of the analyzed tree that is a dependency manifest
+	// or lock file, such as go.mod or package.json (see IsDependencyManifest).
+	NodeKindManifest NodeKind = "manifest"
+)
+
+// IsExternalNode reports whether node names an external package, such as
+// "go:github.com/spf13/cobra", rather than a file.
+func IsExternalNode(node string) bool {
+	return moduleapi.IsExternalNode(node)
 }
 
 // FileMetadata holds metadata for a single file node.
 type FileMetadata struct {
+	Kind      NodeKind
 	Stats     *vcs.FileStats
 	IsTest    bool
 	IsPruned  bool
 	Extension string
+	// CollapsedDependents is the number of files that import this file when its
+	// incoming edges were removed by CollapseHighFanIn and the count is to be shown,
+	// and 0 otherwise.
+	CollapsedDependents int
+	// Dir is the directory of the file relative to the repository root, with forward
+	// slashes, or "" for files at the root. Files outside the repository keep their
+	// absolute directory. It is empty until AssignDirectories is called.
+	Dir string
+	// BlobSHA is the git blob SHA of the analyzed file content. It is empty for files
+	// outside the repository, files missing from the analyzed tree, and when the
+	// output format does not need it.
+	BlobSHA string
+	// LineCount is the number of lines in the analyzed file content. It is nil unless
+	// the output sizes nodes by lines of code, and for files that could not be read.
+	LineCount *int
+	// Style is the user-defined style matched for the file, if any. Render it through
+	// AppliedStyle so built-in classes keep their precedence.
+	Style *NodeStyle
+	// Repo is the name of the repository holding the file when files from several
+	// repositories are analyzed together, and empty otherwise. It is set by
+	// AssignRepositories.
+	Repo string
+}
+
+// NodeStyle is a user-defined look for a file node, set by a style rule.
+type NodeStyle struct {
+	// Fill and Stroke are hex colors; an empty value keeps the built-in color.
+	Fill   string
+	Stroke string
+	// Class names the style in outputs that support classes.
+	Class string
+	// Override makes the style win over the built-in test and pruned file classes.
+	Override bool
+}
+
+// AppliedStyle returns the user-defined style to render for the file. The built-in
+// test and pruned file classes win over it unless the style sets Override.
+func (md FileMetadata) AppliedStyle() (NodeStyle, bool) {
+	if md.Style == nil {
+		return NodeStyle{}, false
+	}
+	if (md.IsTest || md.IsPruned) && !md.Style.Override {
+		return NodeStyle{}, false
+	}
+	return *md.Style, true
 }
 
 // FileEdge identifies a directed edge between two files.
@@ -35,9 +107,38 @@ type FileEdge struct {
 	To   string
 }
 
+// EdgeProvenance describes how an edge was discovered.
+type EdgeProvenance string
+
+const (
+	// EdgeProvenanceParsed marks edges found by a language parser. It is the zero value.
+	EdgeProvenanceParsed EdgeProvenance = ""
+	// EdgeProvenanceHeuristic marks edges found by best-effort import extraction.
+	EdgeProvenanceHeuristic EdgeProvenance = "heuristic"
+	// EdgeProvenanceExpectActual links a Kotlin actual declaration to its expect declaration.
+	EdgeProvenanceExpectActual EdgeProvenance = moduleapi.ExpectActualProvenance
+	// EdgeProvenanceManifest links a source file to the dependency manifest of its
+	// module, added by LinkManifests.
+	EdgeProvenanceManifest EdgeProvenance = "manifest"
+)
+
 // EdgeMetadata holds metadata for a graph edge.
 type EdgeMetadata struct {
-	InCycle bool
+	InCycle    bool
+	Provenance EdgeProvenance
+	// Suppressed marks edges covered by an accepted-coupling suppression rule.
+	Suppressed bool
+	// IntroducedIn is the short SHA of the first commit in an analyzed range whose tree
+	// contains the edge. It is empty for edges that predate the range or when edges
+	// were not attributed.
+	IntroducedIn string
+	// Symbols lists, sorted, the symbols the source file uses from the target file. It
+	// is empty when the language resolver does not track symbols.
+	Symbols []string
+	// Usages counts the places the source file uses symbols of the target file, such
+	// as each call of a function. It is 0 when the language resolver does not count
+	// them; only Go does.
+	Usages int
 }
 
 // FileCycle describes a representative cycle path for a cyclic SCC.
@@ -63,10 +164,17 @@ func NewFileDependencyGraph(g DependencyGraph, fileStats map[string]vcs.FileStat
 	sort.Strings(nodes)
 
 	for _, node := range nodes {
+		if IsExternalNode(node) {
+			files[node] = FileMetadata{Kind: NodeKindExternal}
+			continue
+		}
 		md := FileMetadata{
 			IsTest:    registry.IsTestFile(node, contentReader),
 			Extension: filepath.Ext(filepath.Base(node)),
 		}
+		if IsDependencyManifest(node) {
+			md.Kind = NodeKindManifest
+		}
 
 		if fileStats != nil {
 			if stats, ok := fileStats[node]; ok {
@@ -82,6 +190,39 @@ func NewFileDependencyGraph(g DependencyGraph, fileStats map[string]vcs.FileStat
 		}
 	}
 
+	provenances, err := EdgeProvenances(g)
+	if err != nil {
+		return FileDependencyGraph{}, err
+	}
+	for edge, provenance := range provenances {
+		if edgeMetadata, ok := edges[edge]; ok {
+			edgeMetadata.Provenance = provenance
+			edges[edge] = edgeMetadata
+		}
+	}
+
+	symbols, err := EdgeSymbols(g)
+	if err != nil {
+		return FileDependencyGraph{}, err
+	}
+	for edge, edgeSymbols := range symbols {
+		if edgeMetadata, ok := edges[edge]; ok {
+			edgeMetadata.Symbols = edgeSymbols
+			edges[edge] = edgeMetadata
+		}
+	}
+
+	usages, err := EdgeUsages(g)
+	if err != nil {
+		return FileDependencyGraph{}, err
+	}
+	for edge, count := range usages {
+		if edgeMetadata, ok := edges[edge]; ok {
+			edgeMetadata.Usages = count
+			edges[edge] = edgeMetadata
+		}
+	}
+
 	cycles, cycleEdges := findCyclesAndCycleEdges(adjacency)
 	for edge := range cycleEdges {
 		edgeMetadata := edges[edge]
@@ -99,6 +240,115 @@ func NewFileDependencyGraph(g DependencyGraph, fileStats map[string]vcs.FileStat
 	}, nil
 }
 
+// AssignDirectories records in the metadata of every file its directory relative to
+// root, so outputs can group files without re-deriving directories from node names.
+// External nodes are left without a directory.
+func (fg FileDependencyGraph) AssignDirectories(root string) {
+	for file, md := range fg.Meta.Files {
+		if md.Kind == NodeKindExternal {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if rel, err := filepath.Rel(root, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			dir = rel
+		}
+		if dir == "." {
+			dir = ""
+		}
+		md.Dir = filepath.ToSlash(dir)
+		fg.Meta.Files[file] = md
+	}
+}
+
+// AssignRepositories records, for graphs spanning several repositories, the name of
+// the repository root holding each file and the file's directory relative to that
+// root. The innermost root wins for nested roots. Files outside every root keep their
+// absolute directory and no repository name.
+func (fg FileDependencyGraph) AssignRepositories(roots []string) {
+	for file, md := range fg.Meta.Files {
+		if md.Kind == NodeKindExternal {
+			continue
+		}
+		dir := filepath.Dir(file)
+		owner := ""
+		for _, root := range roots {
+			if rel, err := filepath.Rel(root, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				if len(root) > len(owner) {
+					owner = root
+				}
+			}
+		}
+		md.Repo = ""
+		md.Dir = filepath.ToSlash(dir)
+		if owner != "" {
+			rel, _ := filepath.Rel(owner, dir)
+			if rel == "." {
+				rel = ""
+			}
+			md.Repo = filepath.Base(owner)
+			md.Dir = filepath.ToSlash(rel)
+		}
+		fg.Meta.Files[file] = md
+	}
+}
+
+// EdgeProvenances returns the provenance recorded on edges of g. Edges found by
+// regular import resolution carry no provenance and are omitted.
+func EdgeProvenances(g DependencyGraph) (map[FileEdge]EdgeProvenance, error) {
+	edges, err := g.Edges()
+	if err != nil {
+		return nil, err
+	}
+
+	provenances := make(map[FileEdge]EdgeProvenance)
+	for _, edge := range edges {
+		provenance, ok := edge.Properties.Attributes[moduleapi.EdgeProvenanceAttribute]
+		if !ok || provenance == "" {
+			continue
+		}
+		provenances[FileEdge{From: edge.Source, To: edge.Target}] = EdgeProvenance(provenance)
+	}
+	return provenances, nil
+}
+
+// EdgeSymbols returns the symbols recorded on edges of g. Edges without recorded
+// symbols are omitted.
+func EdgeSymbols(g DependencyGraph) (map[FileEdge][]string, error) {
+	edges, err := g.Edges()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[FileEdge][]string)
+	for _, edge := range edges {
+		value, ok := edge.Properties.Attributes[moduleapi.EdgeSymbolsAttribute]
+		if !ok || value == "" {
+			continue
+		}
+		symbols[FileEdge{From: edge.Source, To: edge.Target}] = strings.Split(value, ",")
+	}
+	return symbols, nil
+}
+
+// EdgeUsages returns the usage counts recorded on edges of g. Edges without a recorded
+// count are omitted.
+func EdgeUsages(g DependencyGraph) (map[FileEdge]int, error) {
+	edges, err := g.Edges()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[FileEdge]int)
+	for _, edge := range edges {
+		count, err := strconv.Atoi(edge.Properties.Attributes[moduleapi.EdgeUsagesAttribute])
+		if err != nil || count <= 0 {
+			continue
+		}
+		usages[FileEdge{From: edge.Source, To: edge.Target}] = count
+	}
+	return usages, nil
+}
+
 func findCyclesAndCycleEdges(adjacency map[string][]string) ([]FileCycle, map[FileEdge]bool) {
 	sccs := stronglyConnectedComponents(adjacency)
 	cycleEdges := make(map[FileEdge]bool)
@@ -80,3 +80,41 @@ func TestNewFileDependencyGraph_MarksAllEdgesInCyclicSCC(t *testing.T) {
 	assert.True(t, fileGraph.Meta.Edges[depgraph.FileEdge{From: "/project/a.go", To: "/project/c.go"}].InCycle)
 	assert.True(t, fileGraph.Meta.Edges[depgraph.FileEdge{From: "/project/c.go", To: "/project/a.go"}].InCycle)
 }
+
+func TestFileDependencyGraph_AssignDirectories(t *testing.T) {
+	graph := depgraph.MustDependencyGraph(map[string][]string{
+		"/project/main.go":            {"/project/pkg/api/handler.go"},
+		"/project/pkg/api/handler.go": {"/elsewhere/lib/lib.go"},
+		"/elsewhere/lib/lib.go":       {},
+	})
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, nil)
+	require.NoError(t, err)
+
+	fileGraph.AssignDirectories("/project")
+
+	assert.Equal(t, "", fileGraph.Meta.Files["/project/main.go"].Dir)
+	assert.Equal(t, "pkg/api", fileGraph.Meta.Files["/project/pkg/api/handler.go"].Dir)
+	assert.Equal(t, "/elsewhere/lib", fileGraph.Meta.Files["/elsewhere/lib/lib.go"].Dir)
+}
+
+func TestFileDependencyGraph_AssignRepositories(t *testing.T) {
+	graph := depgraph.MustDependencyGraph(map[string][]string{
+		"/work/api/main.go":            {"/work/shared/money/money.go"},
+		"/work/shared/money/money.go":  {},
+		"/work/shared/vendored/x/x.go": {},
+		"/elsewhere/lib/lib.go":        {},
+	})
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, nil)
+	require.NoError(t, err)
+
+	fileGraph.AssignRepositories([]string{"/work/api", "/work/shared", "/work/shared/vendored"})
+
+	repoAndDir := func(file string) []string {
+		meta := fileGraph.Meta.Files[file]
+		return []string{meta.Repo, meta.Dir}
+	}
+	assert.Equal(t, []string{"api", ""}, repoAndDir("/work/api/main.go"))
+	assert.Equal(t, []string{"shared", "money"}, repoAndDir("/work/shared/money/money.go"))
+	assert.Equal(t, []string{"vendored", "x"}, repoAndDir("/work/shared/vendored/x/x.go"), "the innermost repository wins")
+	assert.Equal(t, []string{"", "/elsewhere/lib"}, repoAndDir("/elsewhere/lib/lib.go"))
+}
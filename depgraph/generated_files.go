@@ -0,0 +1,78 @@
+package depgraph
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// generatedHeaderScanLimit is how much of a file is read to find its generated-code
+// header. The header must precede the first non-comment line, so it is near the top.
+const generatedHeaderScanLimit = 16 * 1024
+
+// generatedHeaderRE matches the standard generated-code header
+// (https://go.dev/s/generatedcode) once comment markers are stripped. Other
+// generators for other languages write the same line.
+var generatedHeaderRE = regexp.MustCompile(`^Code generated .* DO NOT EDIT\.?$`)
+
+// IsVendoredPath reports whether a repo-relative path lies under a vendor directory.
+func IsVendoredPath(relPath string) bool {
+	for _, segment := range strings.Split(filepath.ToSlash(relPath), "/") {
+		if segment == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGeneratedFile reports whether the file at filePath starts with a "Code generated
+// ... DO NOT EDIT." header: a comment line before the first line that is not a
+// comment. The file is read through contentReader, so commits are checked as they
+// were committed. Unreadable files are not generated.
+func IsGeneratedFile(filePath string, contentReader vcs.ContentReader) bool {
+	prefix, err := vcs.ReadFilePrefix(contentReader, filePath, generatedHeaderScanLimit)
+	if err != nil {
+		return false
+	}
+	return hasGeneratedHeader(prefix)
+}
+
+func hasGeneratedHeader(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	inBlockComment := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var text string
+		switch {
+		case inBlockComment:
+			text, inBlockComment = trimBlockCommentLine(line)
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "//"):
+			text = strings.TrimPrefix(line, "//")
+		case strings.HasPrefix(line, "#"):
+			text = strings.TrimPrefix(line, "#")
+		case strings.HasPrefix(line, "/*"):
+			text, inBlockComment = trimBlockCommentLine(strings.TrimPrefix(line, "/*"))
+		default:
+			return false
+		}
+		if generatedHeaderRE.MatchString(strings.TrimSpace(text)) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimBlockCommentLine strips the markers of a line inside a /* */ comment and reports
+// whether the comment continues past it.
+func trimBlockCommentLine(line string) (string, bool) {
+	if before, _, closed := strings.Cut(line, "*/"); closed {
+		return strings.TrimPrefix(strings.TrimSpace(before), "*"), false
+	}
+	return strings.TrimPrefix(line, "*"), true
+}
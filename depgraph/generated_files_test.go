@@ -0,0 +1,64 @@
+package depgraph
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestIsGeneratedFile_ProtobufFixture(t *testing.T) {
+	assert.True(t, IsGeneratedFile(filepath.Join("testdata", "generated", "user.pb.go"), vcs.FilesystemContentReader()))
+	assert.False(t, IsGeneratedFile(filepath.Join("testdata", "generated", "missing.go"), vcs.FilesystemContentReader()))
+}
+
+func TestHasGeneratedHeader(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    bool
+	}{
+		"go header after build tag": {
+			content: "//go:build linux\n\n// Code generated by mockgen. DO NOT EDIT.\n\npackage mocks\n",
+			want:    true,
+		},
+		"header after license block comment": {
+			content: "/*\n * Copyright 2024 Example\n */\n\n// Code generated by stringer; DO NOT EDIT.\npackage kind\n",
+			want:    true,
+		},
+		"header inside block comment": {
+			content: "/*\n * Code generated by a tool. DO NOT EDIT.\n */\nexport const x = 1;\n",
+			want:    true,
+		},
+		"python header after shebang": {
+			content: "#!/usr/bin/env python\n# Code generated by protoc. DO NOT EDIT.\nimport os\n",
+			want:    true,
+		},
+		"header after code": {
+			content: "package main\n\n// Code generated by hand. DO NOT EDIT.\n",
+			want:    false,
+		},
+		"mention in prose": {
+			content: "// This file is not generated, but says Code generated ... DO NOT EDIT. in passing.\npackage main\n",
+			want:    false,
+		},
+		"empty": {
+			content: "",
+			want:    false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hasGeneratedHeader([]byte(tt.content)))
+		})
+	}
+}
+
+func TestIsVendoredPath(t *testing.T) {
+	assert.True(t, IsVendoredPath("vendor/github.com/pkg/errors/errors.go"))
+	assert.True(t, IsVendoredPath("services/api/vendor/lib.go"))
+	assert.False(t, IsVendoredPath("internal/vendoring/lib.go"))
+	assert.False(t, IsVendoredPath("vendor.go"))
+}
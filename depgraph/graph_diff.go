@@ -0,0 +1,122 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GraphDiff is the structural difference between a base and a target graph, such as
+// the graphs of two commits. Every list is sorted.
+type GraphDiff struct {
+	// AddedNodes are the files only in the target graph.
+	AddedNodes []string
+	// RemovedNodes are the files only in the base graph.
+	RemovedNodes []string
+	// AddedEdges are the dependencies only in the target graph.
+	AddedEdges []FileEdge
+	// RemovedEdges are the dependencies only in the base graph.
+	RemovedEdges []FileEdge
+	// UnchangedEdges are the dependencies in both graphs.
+	UnchangedEdges []FileEdge
+}
+
+// DiffGraphs compares the nodes and edges of base and target. Files are matched by
+// path, so a renamed file shows up as one removed and one added node.
+func DiffGraphs(base, target DependencyGraph) (GraphDiff, error) {
+	baseAdjacency, err := AdjacencyList(base)
+	if err != nil {
+		return GraphDiff{}, fmt.Errorf("failed to read base adjacency: %w", err)
+	}
+	targetAdjacency, err := AdjacencyList(target)
+	if err != nil {
+		return GraphDiff{}, fmt.Errorf("failed to read target adjacency: %w", err)
+	}
+
+	diff := GraphDiff{
+		AddedNodes:     []string{},
+		RemovedNodes:   []string{},
+		AddedEdges:     []FileEdge{},
+		RemovedEdges:   []FileEdge{},
+		UnchangedEdges: []FileEdge{},
+	}
+
+	baseNodes, targetNodes := adjacencyNodes(baseAdjacency), adjacencyNodes(targetAdjacency)
+	for node := range targetNodes {
+		if !baseNodes[node] {
+			diff.AddedNodes = append(diff.AddedNodes, node)
+		}
+	}
+	for node := range baseNodes {
+		if !targetNodes[node] {
+			diff.RemovedNodes = append(diff.RemovedNodes, node)
+		}
+	}
+
+	baseEdges, targetEdges := adjacencyEdges(baseAdjacency), adjacencyEdges(targetAdjacency)
+	for edge := range targetEdges {
+		if baseEdges[edge] {
+			diff.UnchangedEdges = append(diff.UnchangedEdges, edge)
+		} else {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+	for edge := range baseEdges {
+		if !targetEdges[edge] {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+
+	sort.Strings(diff.AddedNodes)
+	sort.Strings(diff.RemovedNodes)
+	sortFileEdges(diff.AddedEdges)
+	sortFileEdges(diff.RemovedEdges)
+	sortFileEdges(diff.UnchangedEdges)
+	return diff, nil
+}
+
+// Summary counts the changed edges and files, as in "+4 edges, -2 edges; +1 file, -0
+// files".
+func (d GraphDiff) Summary() string {
+	return fmt.Sprintf("+%d %s, -%d %s; +%d %s, -%d %s",
+		len(d.AddedEdges), plural(len(d.AddedEdges), "edge", "edges"),
+		len(d.RemovedEdges), plural(len(d.RemovedEdges), "edge", "edges"),
+		len(d.AddedNodes), plural(len(d.AddedNodes), "file", "files"),
+		len(d.RemovedNodes), plural(len(d.RemovedNodes), "file", "files"))
+}
+
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+func adjacencyNodes(adjacency map[string][]string) map[string]bool {
+	nodes := make(map[string]bool, len(adjacency))
+	for from, deps := range adjacency {
+		nodes[from] = true
+		for _, to := range deps {
+			nodes[to] = true
+		}
+	}
+	return nodes
+}
+
+func adjacencyEdges(adjacency map[string][]string) map[FileEdge]bool {
+	edges := make(map[FileEdge]bool)
+	for from, deps := range adjacency {
+		for _, to := range deps {
+			edges[FileEdge{From: from, To: to}] = true
+		}
+	}
+	return edges
+}
+
+func sortFileEdges(edges []FileEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+}
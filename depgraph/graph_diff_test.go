@@ -0,0 +1,63 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffGraphs_AddedRemovedAndUnchanged(t *testing.T) {
+	base := testGraph(map[string][]string{
+		"a.go": {"b.go", "c.go"},
+		"b.go": {"c.go"},
+		"c.go": {},
+		"d.go": {"c.go"},
+	})
+	target := testGraph(map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {"c.go", "e.go"},
+		"c.go": {},
+		"e.go": {},
+	})
+
+	diff, err := DiffGraphs(base, target)
+
+	require.NoError(t, err)
+	assert.Equal(t, GraphDiff{
+		AddedNodes:   []string{"e.go"},
+		RemovedNodes: []string{"d.go"},
+		AddedEdges:   []FileEdge{{From: "b.go", To: "e.go"}},
+		RemovedEdges: []FileEdge{{From: "a.go", To: "c.go"}, {From: "d.go", To: "c.go"}},
+		UnchangedEdges: []FileEdge{
+			{From: "a.go", To: "b.go"},
+			{From: "b.go", To: "c.go"},
+		},
+	}, diff)
+	assert.Equal(t, "+1 edge, -2 edges; +1 file, -1 file", diff.Summary())
+}
+
+func TestDiffGraphs_IdenticalGraphs(t *testing.T) {
+	graph := testGraph(map[string][]string{"a.go": {"b.go"}, "b.go": {}})
+
+	diff, err := DiffGraphs(graph, graph)
+
+	require.NoError(t, err)
+	assert.Empty(t, diff.AddedNodes)
+	assert.Empty(t, diff.RemovedNodes)
+	assert.Empty(t, diff.AddedEdges)
+	assert.Empty(t, diff.RemovedEdges)
+	assert.Equal(t, []FileEdge{{From: "a.go", To: "b.go"}}, diff.UnchangedEdges)
+	assert.Equal(t, "+0 edges, -0 edges; +0 files, -0 files", diff.Summary())
+}
+
+func TestDiffGraphs_EmptyBase(t *testing.T) {
+	target := testGraph(map[string][]string{"a.go": {"b.go"}, "b.go": {}})
+
+	diff, err := DiffGraphs(NewDependencyGraph(), target)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go", "b.go"}, diff.AddedNodes)
+	assert.Equal(t, []FileEdge{{From: "a.go", To: "b.go"}}, diff.AddedEdges)
+	assert.Empty(t, diff.UnchangedEdges)
+}
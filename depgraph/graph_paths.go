@@ -1,62 +1,254 @@
 package depgraph
 
-// FindPathNodes returns all nodes on any path between specified files.
-// Treats the graph bidirectionally (paths from A to B OR from B to A).
-// A node X is included if it lies on any directed path between any pair of target files.
-// If a file isn't in the graph, it's skipped.
-func FindPathNodes(graph DependencyGraph, targetFiles []string) DependencyGraph {
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxEnumeratedPaths caps how many simple paths FindPaths lists. Graphs with cycles
+// or many parallel routes can have exponentially many paths between two files.
+const maxEnumeratedPaths = 1000
+
+// maxPathSearchSteps caps how many nodes the path enumeration visits in total, so
+// dead ends in dense graphs cannot stall it before the path cap is reached.
+const maxPathSearchSteps = 200000
+
+// PathSet lists the simple directed paths between files.
+type PathSet struct {
+	// Paths are the enumerated paths, each a list of files from one target file to
+	// another, ordered by length and then by file names.
+	Paths [][]string
+	// Complete is false when enumeration stopped at its cap, so more paths exist.
+	Complete bool
+}
+
+// FindPathNodes returns the subgraph of the simple directed paths between any two of
+// the specified files, in either direction. Target files are always kept; other nodes
+// and edges are kept only when they lie on such a path, so edges that merely connect
+// two kept nodes are dropped. When there are too many paths to enumerate, edges are
+// kept when they lie on any walk between two targets instead. Files not in the
+// graph are skipped.
+func FindPathNodes(graph DependencyGraph, targetFiles []string) (DependencyGraph, error) {
 	adjacency, err := AdjacencyList(graph)
 	if err != nil {
-		return NewDependencyGraph()
+		return nil, fmt.Errorf("failed to find path nodes: %w", err)
 	}
 
-	// Filter target files to only those that exist in the graph
-	var validTargets []string
-	for _, f := range targetFiles {
-		if _, ok := adjacency[f]; ok {
-			validTargets = append(validTargets, f)
-		}
+	validTargets := targetsInGraph(adjacency, targetFiles)
+	result := make(map[string][]string)
+	for _, f := range validTargets {
+		result[f] = []string{}
 	}
-
 	if len(validTargets) < 2 {
 		// Not enough targets to find paths
-		// Return subgraph containing just the valid targets (if any)
-		result := make(map[string][]string)
-		for _, f := range validTargets {
-			result[f] = []string{}
+		return MustDependencyGraph(result), nil
+	}
+
+	forward, reverse := buildAdjacencyLists(adjacency)
+	paths := enumeratePaths(forward, reverse, validTargets)
+
+	edges := make(map[FileEdge]bool)
+	if paths.Complete {
+		for _, path := range paths.Paths {
+			for i := 0; i+1 < len(path); i++ {
+				edges[FileEdge{From: path[i], To: path[i+1]}] = true
+			}
 		}
-		return MustDependencyGraph(result)
+	} else {
+		forEachTargetPair(validTargets, func(source, target string) {
+			for edge := range walkEdges(forward, reverse, source, target) {
+				edges[edge] = true
+			}
+		})
+	}
+
+	for edge := range edges {
+		result[edge.From] = append(result[edge.From], edge.To)
+		if _, ok := result[edge.To]; !ok {
+			result[edge.To] = []string{}
+		}
+	}
+	for node := range result {
+		sort.Strings(result[node])
+	}
+	return MustDependencyGraph(result), nil
+}
+
+// FindPaths returns the simple directed paths between any two of the specified files,
+// in either direction, shortest first. Files not in the graph are skipped.
+func FindPaths(graph DependencyGraph, targetFiles []string) (PathSet, error) {
+	adjacency, err := AdjacencyList(graph)
+	if err != nil {
+		return PathSet{}, fmt.Errorf("failed to find paths: %w", err)
+	}
+	validTargets := targetsInGraph(adjacency, targetFiles)
+	if len(validTargets) < 2 {
+		return PathSet{Complete: true}, nil
 	}
 
-	// Build forward and reverse adjacency lists from directed graph
 	forward, reverse := buildAdjacencyLists(adjacency)
+	return enumeratePaths(forward, reverse, validTargets), nil
+}
 
-	// Find all nodes on any path between all pairs of targets
-	nodesToKeep := make(map[string]bool)
+// ShortestPath returns a shortest directed path from source to target, both included,
+// or nil when target is not reachable from source or either file is not in the graph.
+// Among paths of equal length, the one visiting lexically smaller files first wins.
+func ShortestPath(graph DependencyGraph, source, target string) ([]string, error) {
+	adjacency, err := AdjacencyList(graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shortest path: %w", err)
+	}
+	if _, ok := adjacency[source]; !ok {
+		return nil, nil
+	}
+	if _, ok := adjacency[target]; !ok {
+		return nil, nil
+	}
 
-	// Always include target nodes
-	for _, f := range validTargets {
-		nodesToKeep[f] = true
+	previous := map[string]string{source: ""}
+	queue := []string{source}
+	for len(queue) > 0 && target != source {
+		current := queue[0]
+		queue = queue[1:]
+
+		neighbors := append([]string{}, adjacency[current]...)
+		sort.Strings(neighbors)
+		for _, next := range neighbors {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = current
+			if next == target {
+				queue = nil
+				break
+			}
+			queue = append(queue, next)
+		}
+	}
+	if _, ok := previous[target]; !ok {
+		return nil, nil
 	}
 
-	// For each pair of targets, find nodes on directed paths (both directions)
-	for i := 0; i < len(validTargets); i++ {
-		for j := i + 1; j < len(validTargets); j++ {
-			// Find paths from i to j
-			pathNodes := findDirectedPathNodes(forward, reverse, validTargets[i], validTargets[j])
-			for node := range pathNodes {
-				nodesToKeep[node] = true
+	path := []string{target}
+	for node := target; node != source; {
+		node = previous[node]
+		path = append(path, node)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// targetsInGraph returns the target files that are nodes of the graph, without
+// duplicates, in their original order.
+func targetsInGraph(adjacency map[string][]string, targetFiles []string) []string {
+	var validTargets []string
+	seen := make(map[string]bool, len(targetFiles))
+	for _, f := range targetFiles {
+		if _, ok := adjacency[f]; ok && !seen[f] {
+			seen[f] = true
+			validTargets = append(validTargets, f)
+		}
+	}
+	return validTargets
+}
+
+// forEachTargetPair calls fn for every ordered pair of distinct targets.
+func forEachTargetPair(targets []string, fn func(source, target string)) {
+	for i := 0; i < len(targets); i++ {
+		for j := 0; j < len(targets); j++ {
+			if i != j {
+				fn(targets[i], targets[j])
 			}
-			// Find paths from j to i
-			pathNodes = findDirectedPathNodes(forward, reverse, validTargets[j], validTargets[i])
-			for node := range pathNodes {
-				nodesToKeep[node] = true
+		}
+	}
+}
+
+// enumeratePaths lists the simple paths between every ordered pair of targets, up to
+// maxEnumeratedPaths paths and maxPathSearchSteps visited nodes.
+func enumeratePaths(forward, reverse map[string][]string, targets []string) PathSet {
+	for node := range forward {
+		sort.Strings(forward[node])
+	}
+
+	result := PathSet{Complete: true}
+	steps := 0
+	forEachTargetPair(targets, func(source, target string) {
+		if !result.Complete {
+			return
+		}
+		allowed := findDirectedPathNodes(forward, reverse, source, target)
+		if !allowed[target] {
+			return
+		}
+
+		onPath := map[string]bool{source: true}
+		path := []string{source}
+		var visit func(node string)
+		visit = func(node string) {
+			for _, next := range forward[node] {
+				if !result.Complete {
+					return
+				}
+				if !allowed[next] || onPath[next] {
+					continue
+				}
+				steps++
+				if steps > maxPathSearchSteps {
+					result.Complete = false
+					return
+				}
+				if next == target {
+					if len(result.Paths) == maxEnumeratedPaths {
+						result.Complete = false
+						return
+					}
+					result.Paths = append(result.Paths, append(append([]string{}, path...), target))
+					continue
+				}
+				onPath[next] = true
+				path = append(path, next)
+				visit(next)
+				path = path[:len(path)-1]
+				onPath[next] = false
 			}
 		}
+		visit(source)
+	})
+
+	sort.SliceStable(result.Paths, func(i, j int) bool {
+		a, b := result.Paths[i], result.Paths[j]
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return strings.Join(a, "\x00") < strings.Join(b, "\x00")
+	})
+	return result
+}
+
+// walkEdges returns the edges on any directed walk from source to target: those whose
+// start is reachable from source and whose end reaches target.
+func walkEdges(forward, reverse map[string][]string, source, target string) map[FileEdge]bool {
+	fromSource := bfsReachable(forward, source)
+	toTarget := bfsReachable(reverse, target)
+	if !fromSource[target] {
+		return nil
 	}
 
-	// Extract subgraph with only the nodes to keep
-	return extractSubgraph(adjacency, nodesToKeep)
+	edges := make(map[FileEdge]bool)
+	for node := range fromSource {
+		if !toTarget[node] {
+			continue
+		}
+		for _, next := range forward[node] {
+			if toTarget[next] {
+				edges[FileEdge{From: node, To: next}] = true
+			}
+		}
+	}
+	return edges
 }
 
 // buildAdjacencyLists creates forward and reverse adjacency lists from the graph.
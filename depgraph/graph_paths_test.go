@@ -1,6 +1,8 @@
 package depgraph
 
 import (
+	"fmt"
+	"reflect"
 	"sort"
 	"testing"
 )
@@ -16,7 +18,10 @@ func TestFindPathNodes_Linear(t *testing.T) {
 		"C": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "C"})
+	result, err := FindPathNodes(graph, []string{"A", "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "B", "C"})
 }
 
@@ -28,7 +33,10 @@ func TestFindPathNodes_Diamond(t *testing.T) {
 		"D": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "D"})
+	result, err := FindPathNodes(graph, []string{"A", "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "B", "C", "D"})
 }
 
@@ -40,7 +48,10 @@ func TestFindPathNodes_Disconnected(t *testing.T) {
 		"D": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "C"})
+	result, err := FindPathNodes(graph, []string{"A", "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "C"})
 }
 
@@ -52,7 +63,10 @@ func TestFindPathNodes_MultiFile(t *testing.T) {
 		"D": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "C", "D"})
+	result, err := FindPathNodes(graph, []string{"A", "C", "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "B", "C", "D"})
 }
 
@@ -65,7 +79,10 @@ func TestFindPathNodes_AllPaths(t *testing.T) {
 		"E": {"C"},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "C"})
+	result, err := FindPathNodes(graph, []string{"A", "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "B", "C", "D", "E"})
 }
 
@@ -75,7 +92,10 @@ func TestFindPathNodes_Bidirectional(t *testing.T) {
 		"B": {},
 	})
 
-	result := FindPathNodes(graph, []string{"B", "A"})
+	result, err := FindPathNodes(graph, []string{"B", "A"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "B"})
 }
 
@@ -85,7 +105,10 @@ func TestFindPathNodes_SingleTarget(t *testing.T) {
 		"B": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A"})
+	result, err := FindPathNodes(graph, []string{"A"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A"})
 
 	adjacency, err := AdjacencyList(result)
@@ -103,7 +126,10 @@ func TestFindPathNodes_NoTargets(t *testing.T) {
 		"B": {},
 	})
 
-	result := FindPathNodes(graph, []string{})
+	result, err := FindPathNodes(graph, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	adjacency, err := AdjacencyList(result)
 	if err != nil {
 		t.Fatalf("AdjacencyList() error = %v", err)
@@ -119,7 +145,10 @@ func TestFindPathNodes_InvalidTarget(t *testing.T) {
 		"B": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "X"})
+	result, err := FindPathNodes(graph, []string{"A", "X"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A"})
 }
 
@@ -130,7 +159,10 @@ func TestFindPathNodes_PreservesEdges(t *testing.T) {
 		"C": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "C"})
+	result, err := FindPathNodes(graph, []string{"A", "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	deps, ok, err := DependenciesOf(result, "A")
 	if err != nil {
@@ -158,10 +190,132 @@ func TestFindPathNodes_ComplexGraph(t *testing.T) {
 		"E": {},
 	})
 
-	result := FindPathNodes(graph, []string{"A", "E"})
+	result, err := FindPathNodes(graph, []string{"A", "E"})
+	if err != nil {
+		t.Fatal(err)
+	}
 	assertGraphContainsNodes(t, result, []string{"A", "B", "C", "D", "E"})
 }
 
+func TestFindPathNodes_DropsEdgesOffPaths(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"B", "C"},
+		"B": {"D"},
+		"C": {"D"},
+		"D": {"B"},
+	})
+
+	result, err := FindPathNodes(graph, []string{"A", "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adjacency, err := AdjacencyList(result)
+	if err != nil {
+		t.Fatalf("AdjacencyList() error = %v", err)
+	}
+	// D -> B connects two kept nodes but closes a cycle, so no simple path uses it.
+	if deps := adjacency["D"]; len(deps) != 0 {
+		t.Errorf("Expected D to have no deps, got %v", deps)
+	}
+	if deps := adjacency["A"]; len(deps) != 2 {
+		t.Errorf("Expected A -> B and A -> C edges, got %v", deps)
+	}
+}
+
+func TestFindPaths_Diamond(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"C", "B"},
+		"B": {"D"},
+		"C": {"D"},
+		"D": {"B"},
+		"E": {"A"},
+	})
+
+	paths, err := FindPaths(graph, []string{"A", "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !paths.Complete {
+		t.Error("Expected enumeration to be complete")
+	}
+	expected := [][]string{{"A", "B", "D"}, {"A", "C", "D"}}
+	if !reflect.DeepEqual(paths.Paths, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, paths.Paths)
+	}
+}
+
+func TestFindPaths_Disconnected(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"B"},
+		"B": {},
+		"C": {"D"},
+		"D": {},
+	})
+
+	paths, err := FindPaths(graph, []string{"A", "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !paths.Complete || len(paths.Paths) != 0 {
+		t.Errorf("Expected no paths, got %v (complete=%v)", paths.Paths, paths.Complete)
+	}
+}
+
+func TestFindPaths_BothDirections(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"B"},
+		"B": {"C"},
+		"C": {"A"},
+	})
+
+	paths, err := FindPaths(graph, []string{"A", "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"C", "A"}, {"A", "B", "C"}}
+	if !reflect.DeepEqual(paths.Paths, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, paths.Paths)
+	}
+}
+
+func TestFindPaths_CapsEnumeration(t *testing.T) {
+	// A ladder of 12 rungs with two parallel edges each has 2^12 paths end to end.
+	adjacency := map[string][]string{}
+	for i := 0; i < 12; i++ {
+		from, to := fmt.Sprintf("n%02d", i), fmt.Sprintf("n%02d", i+1)
+		adjacency[from] = []string{from + "a", from + "b"}
+		adjacency[from+"a"] = []string{to}
+		adjacency[from+"b"] = []string{to}
+	}
+	adjacency["n12"] = []string{"n00"}
+	graph := testGraph(adjacency)
+
+	paths, err := FindPaths(graph, []string{"n00", "n12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paths.Complete {
+		t.Error("Expected enumeration to stop at its cap")
+	}
+	if len(paths.Paths) != maxEnumeratedPaths {
+		t.Errorf("Expected %d paths, got %d", maxEnumeratedPaths, len(paths.Paths))
+	}
+
+	// The subgraph falls back to every edge between the endpoints.
+	result, err := FindPathNodes(graph, []string{"n00", "n12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edges, err := result.Edges(); err != nil || len(edges) == 0 {
+		t.Error("Expected edges between the endpoints")
+	}
+}
+
 func TestExtractSubgraph(t *testing.T) {
 	original := map[string][]string{
 		"A": {"B", "C"},
@@ -229,3 +383,38 @@ func assertGraphContainsNodes(t *testing.T, graph DependencyGraph, expectedNodes
 		t.Errorf("Expected %d nodes %v, got %d nodes %v", len(expectedNodes), expectedNodes, len(actualNodes), actualNodes)
 	}
 }
+
+func TestShortestPath(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"C", "B"},
+		"B": {"D"},
+		"C": {"D"},
+		"D": {"E"},
+		"E": {},
+		"F": {},
+	})
+
+	tests := []struct {
+		name           string
+		source, target string
+		want           []string
+	}{
+		{"ties go to smaller files", "A", "E", []string{"A", "B", "D", "E"}},
+		{"direct edge", "A", "B", []string{"A", "B"}},
+		{"same file", "A", "A", []string{"A"}},
+		{"against edge direction", "E", "A", nil},
+		{"unreachable", "A", "F", nil},
+		{"not in graph", "A", "Z", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ShortestPath(graph, tt.source, tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ShortestPath(%s, %s) = %v, want %v", tt.source, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	graphlib "github.com/dominikbraun/graph"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// MaxHeuristicEdgesPerFile caps how many heuristic edges a single file may contribute.
+const MaxHeuristicEdgesPerFile = 25
+
+// HeuristicImport is an import-like statement found by best-effort extraction.
+type HeuristicImport struct {
+	// Statement is the matched source text, trimmed of surrounding whitespace.
+	Statement string
+	// Specifier is the module or path referenced by the statement.
+	Specifier string
+	// IsPath reports whether Specifier is a path that may be resolved against the
+	// supplied files. Bare module names are never resolved heuristically.
+	IsPath bool
+}
+
+// HeuristicEdgeReport describes the outcome of best-effort import extraction.
+type HeuristicEdgeReport struct {
+	// Edges contains the heuristic edges added to the graph.
+	Edges map[FileEdge]bool
+	// Unclassified maps a file to the import statements that could not be resolved
+	// to a supplied file.
+	Unclassified map[string][]HeuristicImport
+	// CappedFiles lists files whose heuristic edges were truncated at MaxHeuristicEdgesPerFile.
+	CappedFiles []string
+}
+
+type heuristicImportPattern struct {
+	re *regexp.Regexp
+	// relativeOnly limits resolution to specifiers starting with ./ or ../.
+	relativeOnly bool
+	// literal reports whether the specifier is a quoted string literal.
+	literal bool
+}
+
+var heuristicImportPatterns = []heuristicImportPattern{
+	// import x from './x'; import { a, b } from "../y"; import './side-effect'
+	{re: regexp.MustCompile(`(?m)^\s*import\s+(?:[\w*\s{},$]+\s+from\s+)?['"]([^'"\n]+)['"]`), relativeOnly: true, literal: true},
+	// export { x } from './x'
+	{re: regexp.MustCompile(`(?m)^\s*export\s+[\w*\s{},$]+\s+from\s+['"]([^'"\n]+)['"]`), relativeOnly: true, literal: true},
+	// require('./x'), require "x"
+	{re: regexp.MustCompile(`\brequire(?:_relative)?\s*\(?\s*['"]([^'"\n]+)['"]`), relativeOnly: true, literal: true},
+	// #include "x.h"
+	{re: regexp.MustCompile(`(?m)^\s*#\s*include\s+"([^"\n]+)"`), literal: true},
+	// import x.y.z
+	{re: regexp.MustCompile(`(?m)^\s*import\s+([A-Za-z_][\w]*(?:\.[\w*]+)+)\s*;?\s*$`)},
+}
+
+// ExtractHeuristicImports finds import-like statements in content using a fixed set of
+// language-agnostic patterns. Results are ordered by their position in content.
+func ExtractHeuristicImports(content []byte) []HeuristicImport {
+	type positioned struct {
+		offset int
+		imp    HeuristicImport
+	}
+
+	var found []positioned
+	seen := make(map[int]bool)
+	for _, pattern := range heuristicImportPatterns {
+		for _, match := range pattern.re.FindAllSubmatchIndex(content, -1) {
+			if seen[match[0]] {
+				continue
+			}
+			seen[match[0]] = true
+
+			specifier := string(content[match[2]:match[3]])
+			isPath := pattern.literal
+			if pattern.relativeOnly {
+				isPath = isRelativeSpecifier(specifier)
+			}
+
+			found = append(found, positioned{
+				offset: match[0],
+				imp: HeuristicImport{
+					Statement: strings.TrimSpace(string(content[match[0]:match[1]])),
+					Specifier: specifier,
+					IsPath:    isPath,
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].offset < found[j].offset
+	})
+
+	imports := make([]HeuristicImport, len(found))
+	for i, f := range found {
+		imports[i] = f.imp
+	}
+	return imports
+}
+
+// AddHeuristicEdges extracts imports from files with unsupported extensions and adds
+// edges for path specifiers that resolve to other nodes in the graph.
+func AddHeuristicEdges(graph DependencyGraph, filePaths []string, contentReader vcs.ContentReader) (HeuristicEdgeReport, error) {
+	report := HeuristicEdgeReport{
+		Edges:        make(map[FileEdge]bool),
+		Unclassified: make(map[string][]HeuristicImport),
+	}
+
+	suppliedFiles := make(map[string]bool, len(filePaths))
+	absPaths := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return report, fmt.Errorf("failed to resolve path %s: %w", filePath, err)
+		}
+		suppliedFiles[absPath] = true
+		absPaths = append(absPaths, absPath)
+	}
+	sort.Strings(absPaths)
+
+	for _, absPath := range absPaths {
+		if registry.IsSupportedLanguageExtension(filepath.Ext(absPath)) {
+			continue
+		}
+
+		content, err := contentReader.ReadFile(absPath)
+		if err != nil {
+			return report, fmt.Errorf("failed to read %s: %w", absPath, err)
+		}
+
+		edgeCount := 0
+		for _, imp := range ExtractHeuristicImports(content) {
+			target, ok := resolveHeuristicImport(absPath, imp, suppliedFiles)
+			if !ok {
+				report.Unclassified[absPath] = append(report.Unclassified[absPath], imp)
+				continue
+			}
+
+			edge := FileEdge{From: absPath, To: target}
+			if report.Edges[edge] {
+				continue
+			}
+			if edgeCount == MaxHeuristicEdgesPerFile {
+				report.CappedFiles = append(report.CappedFiles, absPath)
+				break
+			}
+
+			if err := graph.AddEdge(absPath, target, moduleapi.WithEdgeProvenance(string(EdgeProvenanceHeuristic))); err != nil && !errors.Is(err, graphlib.ErrEdgeAlreadyExists) {
+				return report, fmt.Errorf("failed to add graph edge %s -> %s: %w", absPath, target, err)
+			}
+			report.Edges[edge] = true
+			edgeCount++
+		}
+	}
+
+	return report, nil
+}
+
+func resolveHeuristicImport(sourcePath string, imp HeuristicImport, suppliedFiles map[string]bool) (string, bool) {
+	if !imp.IsPath {
+		return "", false
+	}
+
+	candidate := filepath.Clean(filepath.Join(filepath.Dir(sourcePath), filepath.FromSlash(imp.Specifier)))
+	if candidate == sourcePath {
+		return "", false
+	}
+	if suppliedFiles[candidate] {
+		return candidate, true
+	}
+
+	// Extensionless specifiers are resolved only when exactly one supplied file matches,
+	// so an ambiguous "./x" never picks between x.ts and x.vue.
+	if filepath.Ext(candidate) != "" {
+		return "", false
+	}
+	var matches []string
+	for _, base := range []string{candidate, filepath.Join(candidate, "index")} {
+		for file := range suppliedFiles {
+			if strings.TrimSuffix(file, filepath.Ext(file)) == base && filepath.Ext(file) != "" {
+				matches = append(matches, file)
+			}
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+	if len(matches) != 1 {
+		return "", false
+	}
+	return matches[0], true
+}
+
+func isRelativeSpecifier(specifier string) bool {
+	return strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../")
+}
@@ -0,0 +1,159 @@
+package depgraph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestExtractHeuristicImports_ClassifiesPathsAndBareNames(t *testing.T) {
+	content := []byte(`<script lang="ts">
+import { formatDate } from './utils/date'
+import Vue from 'vue'
+import "../styles/reset.css"
+const legacy = require('./legacy')
+</script>
+#include "config.h"
+import com.example.Widget
+`)
+
+	imports := ExtractHeuristicImports(content)
+
+	want := []HeuristicImport{
+		{Statement: `import { formatDate } from './utils/date'`, Specifier: "./utils/date", IsPath: true},
+		{Statement: `import Vue from 'vue'`, Specifier: "vue", IsPath: false},
+		{Statement: `import "../styles/reset.css"`, Specifier: "../styles/reset.css", IsPath: true},
+		{Statement: `require('./legacy'`, Specifier: "./legacy", IsPath: true},
+		{Statement: `#include "config.h"`, Specifier: "config.h", IsPath: true},
+		{Statement: `import com.example.Widget`, Specifier: "com.example.Widget", IsPath: false},
+	}
+	if len(imports) != len(want) {
+		t.Fatalf("ExtractHeuristicImports() returned %d imports, want %d: %+v", len(imports), len(want), imports)
+	}
+	for i := range want {
+		if imports[i] != want[i] {
+			t.Errorf("import[%d] = %+v, want %+v", i, imports[i], want[i])
+		}
+	}
+}
+
+func TestAddHeuristicEdges_VueImportingTypeScript(t *testing.T) {
+	dir := t.TempDir()
+	vueFile := filepath.Join(dir, "App.vue")
+	tsFile := filepath.Join(dir, "store.ts")
+	writeTestFile(t, vueFile, `<script setup lang="ts">
+import { useStore } from './store'
+import { ref } from 'vue'
+</script>
+`)
+	writeTestFile(t, tsFile, "export function useStore() {}\n")
+
+	filePaths := []string{vueFile, tsFile}
+	graph, err := BuildDependencyGraph(filePaths, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	report, err := AddHeuristicEdges(graph, filePaths, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("AddHeuristicEdges() error = %v", err)
+	}
+
+	deps, _, err := DependenciesOf(graph, vueFile)
+	if err != nil {
+		t.Fatalf("DependenciesOf() error = %v", err)
+	}
+	if len(deps) != 1 || deps[0] != tsFile {
+		t.Fatalf("expected App.vue -> store.ts, got %v", deps)
+	}
+	if !report.Edges[FileEdge{From: vueFile, To: tsFile}] {
+		t.Fatalf("expected App.vue -> store.ts to be reported as heuristic, got %v", report.Edges)
+	}
+
+	unclassified := report.Unclassified[vueFile]
+	if len(unclassified) != 1 || unclassified[0].Specifier != "vue" {
+		t.Fatalf("expected bare 'vue' import to be unclassified, got %+v", unclassified)
+	}
+}
+
+func TestAddHeuristicEdges_SkipsSupportedFiles(t *testing.T) {
+	dir := t.TempDir()
+	tsFile := filepath.Join(dir, "main.ts")
+	otherFile := filepath.Join(dir, "other.ts")
+	writeTestFile(t, tsFile, "// require('./other')\n")
+	writeTestFile(t, otherFile, "export {}\n")
+
+	filePaths := []string{tsFile, otherFile}
+	graph, err := BuildDependencyGraph(filePaths, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	report, err := AddHeuristicEdges(graph, filePaths, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("AddHeuristicEdges() error = %v", err)
+	}
+	if len(report.Edges) != 0 {
+		t.Fatalf("expected no heuristic edges for supported files, got %v", report.Edges)
+	}
+}
+
+func TestAddHeuristicEdges_CapsEdgesPerFile(t *testing.T) {
+	dir := t.TempDir()
+	vueFile := filepath.Join(dir, "Big.vue")
+
+	var sb strings.Builder
+	filePaths := []string{vueFile}
+	for i := 0; i <= MaxHeuristicEdgesPerFile; i++ {
+		name := fmt.Sprintf("dep%02d.ts", i)
+		sb.WriteString(fmt.Sprintf("import './%s'\n", name))
+		depFile := filepath.Join(dir, name)
+		writeTestFile(t, depFile, "export {}\n")
+		filePaths = append(filePaths, depFile)
+	}
+	writeTestFile(t, vueFile, sb.String())
+
+	graph, err := BuildDependencyGraph(filePaths, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	report, err := AddHeuristicEdges(graph, filePaths, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("AddHeuristicEdges() error = %v", err)
+	}
+	if len(report.Edges) != MaxHeuristicEdgesPerFile {
+		t.Fatalf("expected %d heuristic edges, got %d", MaxHeuristicEdgesPerFile, len(report.Edges))
+	}
+	if len(report.CappedFiles) != 1 || report.CappedFiles[0] != vueFile {
+		t.Fatalf("expected Big.vue to be reported as capped, got %v", report.CappedFiles)
+	}
+}
+
+func TestResolveHeuristicImport_AmbiguousExtensionlessSpecifierIsUnresolved(t *testing.T) {
+	supplied := map[string]bool{
+		"/project/App.vue":    true,
+		"/project/widget.ts":  true,
+		"/project/widget.vue": true,
+	}
+
+	_, ok := resolveHeuristicImport("/project/App.vue", HeuristicImport{Specifier: "./widget", IsPath: true}, supplied)
+
+	if ok {
+		t.Fatal("expected ambiguous ./widget to stay unresolved")
+	}
+}
+
+func writeTestFile(t testing.TB, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("os.MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+}
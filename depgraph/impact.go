@@ -0,0 +1,108 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Impact is the reverse-transitive closure of a set of changed files: every file that
+// depends on a changed file, directly or through other files.
+type Impact struct {
+	// Distances maps each affected file to the fewest dependency hops from a changed
+	// file. Changed files have distance 0.
+	Distances map[string]int
+	// Unbounded lists, sorted, the files at the depth limit that have dependents the
+	// traversal did not visit. Their further dependents are an unbounded risk.
+	Unbounded []string
+}
+
+// ReverseClosure walks dependents of changed breadth-first, stopping maxDepth hops
+// from the changed files. A maxDepth of 0 or less walks the whole closure. Changed
+// files missing from the graph are kept at distance 0.
+func ReverseClosure(g DependencyGraph, changed []string, maxDepth int) (Impact, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return Impact{}, fmt.Errorf("failed to read graph: %w", err)
+	}
+	_, reverse := buildAdjacencyLists(adjacency)
+
+	impact := Impact{Distances: make(map[string]int, len(changed))}
+	queue := make([]string, 0, len(changed))
+	for _, file := range changed {
+		if _, seen := impact.Distances[file]; seen {
+			continue
+		}
+		impact.Distances[file] = 0
+		queue = append(queue, file)
+	}
+
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		distance := impact.Distances[file]
+
+		dependents := reverse[file]
+		if maxDepth > 0 && distance == maxDepth {
+			for _, dependent := range dependents {
+				if _, seen := impact.Distances[dependent]; !seen {
+					impact.Unbounded = append(impact.Unbounded, file)
+					break
+				}
+			}
+			continue
+		}
+		for _, dependent := range dependents {
+			if _, seen := impact.Distances[dependent]; seen {
+				continue
+			}
+			impact.Distances[dependent] = distance + 1
+			queue = append(queue, dependent)
+		}
+	}
+
+	sort.Strings(impact.Unbounded)
+	return impact, nil
+}
+
+// Files returns the affected files sorted by distance, then path.
+func (i Impact) Files() []string {
+	files := make([]string, 0, len(i.Distances))
+	for file := range i.Distances {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(a, b int) bool {
+		if i.Distances[files[a]] != i.Distances[files[b]] {
+			return i.Distances[files[a]] < i.Distances[files[b]]
+		}
+		return files[a] < files[b]
+	})
+	return files
+}
+
+// TestSelection is the set of test files chosen to cover an Impact.
+type TestSelection struct {
+	// Selected lists the affected test files to run, sorted.
+	Selected []string
+	// SkippedByPolicy lists, sorted, the affected test files excluded by policy, such
+	// as known-flaky tests.
+	SkippedByPolicy []string
+}
+
+// SelectTests picks the affected files that isTest reports as tests, moving those
+// skip matches to SkippedByPolicy. skip may be nil.
+func (i Impact) SelectTests(isTest func(string) bool, skip func(string) bool) TestSelection {
+	var selection TestSelection
+	for file := range i.Distances {
+		if !isTest(file) {
+			continue
+		}
+		if skip != nil && skip(file) {
+			selection.SkippedByPolicy = append(selection.SkippedByPolicy, file)
+			continue
+		}
+		selection.Selected = append(selection.Selected, file)
+	}
+	sort.Strings(selection.Selected)
+	sort.Strings(selection.SkippedByPolicy)
+	return selection
+}
@@ -0,0 +1,85 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impactGraph: util is used by a and util_test; a by b and a_test; b by c; c by c_test.
+func impactGraph() DependencyGraph {
+	return MustDependencyGraph(map[string][]string{
+		"util.go":      {},
+		"util_test.go": {"util.go"},
+		"a.go":         {"util.go"},
+		"a_test.go":    {"a.go"},
+		"b.go":         {"a.go"},
+		"c.go":         {"b.go"},
+		"c_test.go":    {"c.go"},
+		"other.go":     {},
+	})
+}
+
+func TestReverseClosure_Unlimited(t *testing.T) {
+	impact, err := ReverseClosure(impactGraph(), []string{"util.go"}, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		"util.go":      0,
+		"util_test.go": 1,
+		"a.go":         1,
+		"a_test.go":    2,
+		"b.go":         2,
+		"c.go":         3,
+		"c_test.go":    4,
+	}, impact.Distances)
+	assert.Empty(t, impact.Unbounded)
+	assert.Equal(t, []string{"util.go", "a.go", "util_test.go", "a_test.go", "b.go", "c.go", "c_test.go"}, impact.Files())
+}
+
+func TestReverseClosure_MaxDepthReportsUnboundedFiles(t *testing.T) {
+	impact, err := ReverseClosure(impactGraph(), []string{"util.go"}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		"util.go":      0,
+		"util_test.go": 1,
+		"a.go":         1,
+		"a_test.go":    2,
+		"b.go":         2,
+	}, impact.Distances)
+	assert.Equal(t, []string{"b.go"}, impact.Unbounded)
+}
+
+func TestReverseClosure_KeepsChangedFilesOutsideGraph(t *testing.T) {
+	impact, err := ReverseClosure(impactGraph(), []string{"deleted.go", "c.go", "c.go"}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"deleted.go": 0, "c.go": 0, "c_test.go": 1}, impact.Distances)
+	assert.Empty(t, impact.Unbounded)
+}
+
+func TestImpact_SelectTests(t *testing.T) {
+	impact, err := ReverseClosure(impactGraph(), []string{"util.go"}, 0)
+	require.NoError(t, err)
+	isTest := func(file string) bool { return strings.HasSuffix(file, "_test.go") }
+
+	selection := impact.SelectTests(isTest, func(file string) bool { return file == "a_test.go" })
+
+	assert.Equal(t, []string{"c_test.go", "util_test.go"}, selection.Selected)
+	assert.Equal(t, []string{"a_test.go"}, selection.SkippedByPolicy)
+}
+
+func TestImpact_SelectTestsWithinDepth(t *testing.T) {
+	impact, err := ReverseClosure(impactGraph(), []string{"util.go"}, 1)
+	require.NoError(t, err)
+	isTest := func(file string) bool { return file == "util_test.go" || file == "a_test.go" || file == "c_test.go" }
+
+	selection := impact.SelectTests(isTest, nil)
+
+	assert.Equal(t, []string{"util_test.go"}, selection.Selected)
+	assert.Empty(t, selection.SkippedByPolicy)
+	assert.Equal(t, []string{"a.go"}, impact.Unbounded)
+}
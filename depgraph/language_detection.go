@@ -0,0 +1,80 @@
+package depgraph
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/registry"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// shebangPrefixSize is how much of an extensionless file is read to find its shebang.
+const shebangPrefixSize = 128
+
+// LanguageDetector maps files to the extension of the language that analyzes them.
+// Files with an extension keep it. Extensionless scripts are recognized by the
+// interpreter in their shebang line, so "#!/usr/bin/env python3" maps to ".py".
+// Results are cached per file.
+type LanguageDetector struct {
+	contentReader vcs.ContentReader
+	cache         sync.Map
+}
+
+// NewLanguageDetector creates a detector that reads shebang lines through contentReader.
+func NewLanguageDetector(contentReader vcs.ContentReader) *LanguageDetector {
+	return &LanguageDetector{contentReader: contentReader}
+}
+
+// Extension returns the extension of the file's language, or "" when an extensionless
+// file has no shebang naming a supported interpreter.
+func (d *LanguageDetector) Extension(filePath string) string {
+	if ext := filepath.Ext(filePath); ext != "" {
+		return ext
+	}
+	if d == nil || d.contentReader == nil {
+		return ""
+	}
+	if cached, ok := d.cache.Load(filePath); ok {
+		return cached.(string)
+	}
+
+	ext := ""
+	if prefix, err := vcs.ReadFilePrefix(d.contentReader, filePath, shebangPrefixSize); err == nil {
+		if module, ok := registry.ModuleForInterpreter(shebangInterpreter(prefix)); ok {
+			ext = module.Extensions()[0]
+		}
+	}
+	d.cache.Store(filePath, ext)
+	return ext
+}
+
+// shebangInterpreter returns the interpreter named by a "#!" first line, looking past
+// env and its options: "#!/usr/bin/env -S python3 -u" names "python3".
+func shebangInterpreter(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+	line := content[2:]
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter != "env" {
+		return interpreter
+	}
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+			continue
+		}
+		return path.Base(field)
+	}
+	return ""
+}
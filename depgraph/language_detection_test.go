@@ -0,0 +1,101 @@
+package depgraph
+
+import (
+	"io/fs"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestShebangInterpreter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "env", content: "#!/usr/bin/env python3\nimport os\n", want: "python3"},
+		{name: "env with options", content: "#!/usr/bin/env -S PYTHONPATH=. python3 -u\n", want: "python3"},
+		{name: "direct path", content: "#!/bin/bash\n", want: "bash"},
+		{name: "space after marker", content: "#! /usr/bin/ruby -w\r\n", want: "ruby"},
+		{name: "no shebang", content: "import os\n", want: ""},
+		{name: "empty shebang", content: "#!\n", want: ""},
+		{name: "env without interpreter", content: "#!/usr/bin/env\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, shebangInterpreter([]byte(tt.content)))
+		})
+	}
+}
+
+func TestLanguageDetector_Extension(t *testing.T) {
+	reader := &countingContentReader{files: map[string]string{
+		"/repo/tool":   "#!/usr/bin/env python3\nimport helper\n",
+		"/repo/server": "#!/usr/bin/env node\nrequire('./app')\n",
+		"/repo/deploy": "#!/bin/sh\necho deploy\n",
+		"/repo/README": "plain text\n",
+	}}
+	detector := NewLanguageDetector(reader)
+
+	assert.Equal(t, ".py", detector.Extension("/repo/tool"))
+	assert.Equal(t, ".js", detector.Extension("/repo/server"))
+	assert.Equal(t, "", detector.Extension("/repo/deploy"))
+	assert.Equal(t, "", detector.Extension("/repo/README"))
+	assert.Equal(t, ".go", detector.Extension("/repo/main.go"))
+	assert.Equal(t, "", detector.Extension("/repo/missing"))
+}
+
+func TestLanguageDetector_CachesPerFileAndReadsOnlyThePrefix(t *testing.T) {
+	reader := &countingContentReader{files: map[string]string{
+		"/repo/tool": "#!/usr/bin/env python3\n" + string(make([]byte, 4096)),
+	}}
+	detector := NewLanguageDetector(reader)
+
+	assert.Equal(t, ".py", detector.Extension("/repo/tool"))
+	assert.Equal(t, ".py", detector.Extension("/repo/tool"))
+	assert.Equal(t, int32(1), reader.reads.Load())
+	assert.Equal(t, shebangPrefixSize, reader.lastPrefixSize)
+}
+
+// countingContentReader serves files from memory and counts prefix reads.
+type countingContentReader struct {
+	files          map[string]string
+	reads          atomic.Int32
+	lastPrefixSize int
+}
+
+var _ vcs.PrefixReader = (*countingContentReader)(nil)
+
+func (r *countingContentReader) ReadFile(filePath string) ([]byte, error) {
+	content, ok := r.files[filePath]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return []byte(content), nil
+}
+
+func (r *countingContentReader) ReadFilePrefix(filePath string, n int) ([]byte, error) {
+	r.reads.Add(1)
+	r.lastPrefixSize = n
+	content, err := r.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > n {
+		content = content[:n]
+	}
+	return content, nil
+}
+
+func (r *countingContentReader) Exists(filePath string) bool {
+	_, ok := r.files[filePath]
+	return ok
+}
+
+func (r *countingContentReader) ListDir(string) ([]string, error) {
+	return nil, nil
+}
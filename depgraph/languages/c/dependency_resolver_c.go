@@ -3,23 +3,29 @@ package c
 import (
 	"fmt"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// includesCacheKind names the include lists ResolveCProjectIncludes caches per file.
+const includesCacheKind = "c-includes/v1"
+
 func ResolveCProjectIncludes(
 	absPath string,
 	filePath string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	includes, err := moduleapi.ParseFile(cache, contentReader, includesCacheKind, absPath, func(content []byte) ([]Include, error) {
+		includes, parseErr := ParseCIncludes(content)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse includes in %s: %w", filePath, parseErr)
+		}
+		return includes, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
-
-	includes, parseErr := ParseCIncludes(content)
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse includes in %s: %w", filePath, parseErr)
+		return nil, err
 	}
 
 	var projectIncludes []string
@@ -33,7 +33,7 @@ type resolver struct {
 }
 
 func (r resolver) ResolveProjectImports(absPath, filePath, ext string) ([]string, error) {
-	return ResolveCProjectIncludes(absPath, filePath, r.ctx.SuppliedFiles, r.contentReader)
+	return ResolveCProjectIncludes(absPath, filePath, r.ctx.SuppliedFiles, r.contentReader, r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
@@ -3,23 +3,29 @@ package cpp
 import (
 	"fmt"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// includesCacheKind names the include lists ResolveCppProjectIncludes caches per file.
+const includesCacheKind = "cpp-includes/v1"
+
 func ResolveCppProjectIncludes(
 	absPath string,
 	filePath string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	includes, err := moduleapi.ParseFile(cache, contentReader, includesCacheKind, absPath, func(content []byte) ([]Include, error) {
+		includes, parseErr := ParseCppIncludes(content)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse includes in %s: %w", filePath, parseErr)
+		}
+		return includes, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
-
-	includes, parseErr := ParseCppIncludes(content)
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse includes in %s: %w", filePath, parseErr)
+		return nil, err
 	}
 
 	var projectIncludes []string
@@ -1,21 +1,47 @@
 package csharp
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// fileFactsCacheKind names the csharpFileFacts the resolver caches per file.
+const fileFactsCacheKind = "csharp-file-facts/v1"
+
+// csharpFileFacts is what indexing and resolving need from a C# file's content.
+type csharpFileFacts struct {
+	Namespace      string                  `json:"namespace,omitempty"`
+	Declarations   []CSharpTypeDeclaration `json:"declarations,omitempty"`
+	TopLevelTypes  []string                `json:"topLevelTypes,omitempty"`
+	Imports        []CSharpImport          `json:"imports,omitempty"`
+	TypeReferences []string                `json:"typeReferences,omitempty"`
+}
+
+func parseCSharpFileFacts(content []byte) (csharpFileFacts, error) {
+	source := string(content)
+	return csharpFileFacts{
+		Namespace:      ParseCSharpNamespace(source),
+		Declarations:   ParseCSharpTypeDeclarations(source),
+		TopLevelTypes:  ParseTopLevelCSharpTypeNames(source),
+		Imports:        ParseCSharpImports(source),
+		TypeReferences: ExtractCSharpTypeIdentifiers(source),
+	}, nil
+}
+
+// BuildCSharpIndices indexes the supplied C# files by project scope and namespace. It
+// returns the files and top-level types of each scoped namespace, the namespaces each
+// file declares types in, and each file's project scope.
 func BuildCSharpIndices(
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
-) (map[string][]string, map[string]map[string][]string, map[string]string, map[string]string) {
+	cache moduleapi.ParseCache,
+) (map[string][]string, map[string]map[string][]string, map[string][]string, map[string]string) {
 	namespaceToFiles := make(map[string][]string)
 	namespaceToTypes := make(map[string]map[string][]string)
-	fileToNamespace := make(map[string]string)
+	fileToNamespaces := make(map[string][]string)
 	fileToScope := make(map[string]string)
 
 	for filePath := range suppliedFiles {
@@ -23,35 +49,39 @@ func BuildCSharpIndices(
 			continue
 		}
 
-		content, err := contentReader(filePath)
+		facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, filePath, parseCSharpFileFacts)
 		if err != nil {
 			continue
 		}
 
-		source := string(content)
-		namespace := ParseCSharpNamespace(source)
-		fileToNamespace[filePath] = namespace
-		scope := inferCSharpFileScope(filePath)
+		scope := inferCSharpFileScope(filePath, contentReader)
 		fileToScope[filePath] = scope
-		scopedNamespace := scopeKey(scope, namespace)
-		namespaceToFiles[scopedNamespace] = append(namespaceToFiles[scopedNamespace], filePath)
 
-		typeNames := ParseTopLevelCSharpTypeNames(source)
-		if len(typeNames) == 0 {
-			continue
+		declarations := facts.Declarations
+		namespaces := []string{facts.Namespace}
+		for _, declaration := range declarations {
+			if !containsString(namespaces, declaration.Namespace) {
+				namespaces = append(namespaces, declaration.Namespace)
+			}
 		}
-
-		typeMap, ok := namespaceToTypes[scopedNamespace]
-		if !ok {
-			typeMap = make(map[string][]string)
-			namespaceToTypes[scopedNamespace] = typeMap
+		fileToNamespaces[filePath] = namespaces
+		for _, namespace := range namespaces {
+			scopedNamespace := scopeKey(scope, namespace)
+			namespaceToFiles[scopedNamespace] = append(namespaceToFiles[scopedNamespace], filePath)
 		}
-		for _, typeName := range typeNames {
-			typeMap[typeName] = append(typeMap[typeName], filePath)
+
+		for _, declaration := range declarations {
+			scopedNamespace := scopeKey(scope, declaration.Namespace)
+			typeMap, ok := namespaceToTypes[scopedNamespace]
+			if !ok {
+				typeMap = make(map[string][]string)
+				namespaceToTypes[scopedNamespace] = typeMap
+			}
+			typeMap[declaration.Name] = append(typeMap[declaration.Name], filePath)
 		}
 	}
 
-	return namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope
+	return namespaceToFiles, namespaceToTypes, fileToNamespaces, fileToScope
 }
 
 func ResolveCSharpProjectImports(
@@ -59,21 +89,21 @@ func ResolveCSharpProjectImports(
 	_ string,
 	namespaceToFiles map[string][]string,
 	namespaceToTypes map[string]map[string][]string,
-	fileToNamespace map[string]string,
+	fileToNamespaces map[string][]string,
 	fileToScope map[string]string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, absPath, parseCSharpFileFacts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+		return nil, err
 	}
 
-	source := string(content)
-	imports := ParseCSharpImports(source)
-	referencedTypes := ExtractCSharpTypeIdentifiers(source)
+	imports := facts.Imports
+	referencedTypes := facts.TypeReferences
 	declaredTypes := make(map[string]bool)
-	for _, name := range ParseTopLevelCSharpTypeNames(source) {
+	for _, name := range facts.TopLevelTypes {
 		declaredTypes[name] = true
 	}
 
@@ -96,6 +126,23 @@ func ResolveCSharpProjectImports(
 			continue
 		}
 
+		// "using static A.B.TypeName;" brings the type's members into scope, so the
+		// type itself is usually never named.
+		if imp.Static {
+			lastDot := strings.LastIndex(path, ".")
+			if lastDot <= 0 || lastDot >= len(path)-1 {
+				continue
+			}
+			typeName := path[lastDot+1:]
+			importedTypeNames[typeName] = true
+			files := namespaceToTypes[scopeKey(scope, path[:lastDot])][typeName]
+			if len(files) == 1 {
+				addDep(files[0])
+				resolvedTypes[typeName] = true
+			}
+			continue
+		}
+
 		// "using A.B;" form imports a namespace.
 		if typeMap, ok := namespaceToTypes[scopeKey(scope, path)]; ok {
 			for _, ref := range referencedTypes {
@@ -132,20 +179,23 @@ func ResolveCSharpProjectImports(
 		resolvedTypes[typeName] = true
 	}
 
-	// Same-namespace references do not require using directives in C#.
-	if namespace, ok := fileToNamespace[absPath]; ok {
-		if typeMap, ok := namespaceToTypes[scopeKey(scope, namespace)]; ok {
-			for _, ref := range referencedTypes {
-				if declaredTypes[ref] || importedTypeNames[ref] {
-					continue
-				}
-				files := typeMap[ref]
-				if len(files) != 1 {
-					continue
-				}
-				addDep(files[0])
-				resolvedTypes[ref] = true
+	// References to types in the same namespace, or in a namespace enclosing it, do
+	// not require using directives in C#.
+	for _, namespace := range enclosingCSharpNamespaces(fileToNamespaces[absPath]) {
+		typeMap, ok := namespaceToTypes[scopeKey(scope, namespace)]
+		if !ok {
+			continue
+		}
+		for _, ref := range referencedTypes {
+			if declaredTypes[ref] || importedTypeNames[ref] || resolvedTypes[ref] {
+				continue
 			}
+			files := typeMap[ref]
+			if len(files) != 1 {
+				continue
+			}
+			addDep(files[0])
+			resolvedTypes[ref] = true
 		}
 	}
 
@@ -173,17 +223,37 @@ func ResolveCSharpProjectImports(
 	return resolved, nil
 }
 
-func inferCSharpFileScope(filePath string) string {
+// enclosingCSharpNamespaces returns the namespaces and every namespace enclosing them,
+// innermost first, so "A.B.C" yields "A.B.C", "A.B", and "A". The global namespace is
+// kept only when a file declares types in it.
+func enclosingCSharpNamespaces(namespaces []string) []string {
+	var enclosing []string
+	for _, namespace := range namespaces {
+		if namespace == "" && !containsString(enclosing, namespace) {
+			enclosing = append(enclosing, namespace)
+		}
+		for namespace != "" {
+			if !containsString(enclosing, namespace) {
+				enclosing = append(enclosing, namespace)
+			}
+			lastDot := strings.LastIndex(namespace, ".")
+			if lastDot < 0 {
+				break
+			}
+			namespace = namespace[:lastDot]
+		}
+	}
+	return enclosing
+}
+
+func inferCSharpFileScope(filePath string, contentReader vcs.ContentReader) string {
 	dir := filepath.Dir(filePath)
 	for {
-		entries, err := os.ReadDir(dir)
+		names, err := contentReader.ListDir(dir)
 		if err == nil {
-			for _, entry := range entries {
-				if entry.IsDir() {
-					continue
-				}
-				if strings.HasSuffix(entry.Name(), ".csproj") {
-					return filepath.Join(dir, entry.Name())
+			for _, name := range names {
+				if strings.HasSuffix(name, ".csproj") {
+					return filepath.Join(dir, name)
 				}
 			}
 		}
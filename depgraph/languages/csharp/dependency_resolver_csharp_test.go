@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,7 +54,7 @@ public class Helper {}
 		helperPath:     true,
 	}
 	reader := vcs.FilesystemContentReader()
-	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader)
+	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader, nil)
 
 	imports, err := ResolveCSharpProjectImports(
 		programPath,
@@ -63,7 +64,8 @@ public class Helper {}
 		fileToNamespace,
 		fileToScope,
 		supplied,
-		reader)
+		reader,
+		nil)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []string{loggerPath, fileLoggerPath}, imports)
 }
@@ -95,7 +97,7 @@ public class Service {}
 		serviceBPath: true,
 	}
 	reader := vcs.FilesystemContentReader()
-	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader)
+	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader, nil)
 
 	imports, err := ResolveCSharpProjectImports(
 		programPath,
@@ -105,7 +107,8 @@ public class Service {}
 		fileToNamespace,
 		fileToScope,
 		supplied,
-		reader)
+		reader,
+		nil)
 	require.NoError(t, err)
 	assert.Empty(t, imports)
 }
@@ -142,7 +145,7 @@ public interface IRoomGrain {}
 		iRoomPath:   true,
 	}
 	reader := vcs.FilesystemContentReader()
-	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader)
+	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader, nil)
 
 	imports, err := ResolveCSharpProjectImports(
 		monsterPath,
@@ -152,7 +155,8 @@ public interface IRoomGrain {}
 		fileToNamespace,
 		fileToScope,
 		supplied,
-		reader)
+		reader,
+		nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, iRoomPath)
 	assert.NotContains(t, imports, roomPath)
@@ -199,7 +203,7 @@ public class Car { public int Passengers { get; set; } }
 		finishedExternalPath:   true,
 	}
 	reader := vcs.FilesystemContentReader()
-	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader)
+	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader, nil)
 
 	imports, err := ResolveCSharpProjectImports(
 		startProgramPath,
@@ -209,7 +213,8 @@ public class Car { public int Passengers { get; set; } }
 		fileToNamespace,
 		fileToScope,
 		supplied,
-		reader)
+		reader,
+		nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, startCalculatorPath)
 	assert.Contains(t, imports, startExternalPath)
@@ -241,7 +246,7 @@ public class SharedType {}
 		targetPath: true,
 	}
 	reader := vcs.FilesystemContentReader()
-	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader)
+	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(supplied, reader, nil)
 
 	imports, err := ResolveCSharpProjectImports(
 		sourcePath,
@@ -251,7 +256,119 @@ public class SharedType {}
 		fileToNamespace,
 		fileToScope,
 		supplied,
-		reader)
+		reader,
+		nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, targetPath)
 }
+
+func TestBuildCSharpIndices_ProjectScopeComesFromContentReader(t *testing.T) {
+	// Nothing exists on disk: the .csproj is only visible through the reader, as with a
+	// project file that is present in the analyzed commit but deleted from the working tree.
+	root := filepath.Join(t.TempDir(), "repo")
+	projectPath := filepath.Join(root, "App", "App.csproj")
+	programPath := filepath.Join(root, "App", "Src", "Program.cs")
+	reader := testhelpers.MapContentReader(map[string]string{
+		projectPath: `<Project Sdk="Microsoft.NET.Sdk"></Project>`,
+		programPath: "namespace App;\npublic class Program {}\n",
+	})
+
+	_, _, _, fileToScope := BuildCSharpIndices(map[string]bool{programPath: true}, reader, nil)
+
+	assert.Equal(t, projectPath, fileToScope[programPath])
+}
+
+// resolveCSharpFile indexes files, served from memory, and resolves the imports of path.
+func resolveCSharpFile(t *testing.T, files map[string]string, path string) []string {
+	t.Helper()
+	supplied := make(map[string]bool, len(files))
+	for file := range files {
+		supplied[file] = true
+	}
+	reader := testhelpers.MapContentReader(files)
+	namespaceToFiles, namespaceToTypes, fileToNamespaces, fileToScope := BuildCSharpIndices(supplied, reader, nil)
+
+	imports, err := ResolveCSharpProjectImports(path, path, namespaceToFiles, namespaceToTypes, fileToNamespaces, fileToScope, supplied, reader, nil)
+	require.NoError(t, err)
+	return imports
+}
+
+func TestResolveCSharpProjectImports_FileScopedNamespaceWithoutUsing(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Game")
+	playerPath := filepath.Join(root, "Player.cs")
+	healthPath := filepath.Join(root, "Health.cs")
+	files := map[string]string{
+		filepath.Join(root, "Game.csproj"): `<Project Sdk="Microsoft.NET.Sdk"></Project>`,
+		playerPath: `using System;
+using UnityEngine;
+
+namespace Game.Actors;
+
+public class Player : MonoBehaviour
+{
+    private Health health = new Health();
+}
+`,
+		healthPath: "namespace Game.Actors;\npublic class Health {}\n",
+	}
+
+	assert.Equal(t, []string{healthPath}, resolveCSharpFile(t, files, playerPath))
+}
+
+func TestResolveCSharpProjectImports_NestedNamespacesSeeEnclosingTypes(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Game")
+	enemyPath := filepath.Join(root, "Enemy.cs")
+	spawnerPath := filepath.Join(root, "Spawner.cs")
+	files := map[string]string{
+		filepath.Join(root, "Game.csproj"): `<Project Sdk="Microsoft.NET.Sdk"></Project>`,
+		enemyPath: `namespace Game {
+    namespace Ai {
+        public class Enemy
+        {
+            private Spawner spawner;
+        }
+    }
+}
+`,
+		spawnerPath: `namespace Game {
+    public class Spawner {}
+    namespace Ai {
+        public class Brain {}
+    }
+}
+`,
+		// A second Spawner in an unrelated namespace rules out the unique-type fallback.
+		filepath.Join(root, "Editor", "Spawner.cs"): "namespace Editor;\npublic class Spawner {}\n",
+	}
+
+	assert.Equal(t, []string{spawnerPath}, resolveCSharpFile(t, files, enemyPath))
+}
+
+func TestResolveCSharpProjectImports_UsingStaticLinksTypeWithoutNamingIt(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Game")
+	playerPath := filepath.Join(root, "Player.cs")
+	mathPath := filepath.Join(root, "Util", "MathUtil.cs")
+	files := map[string]string{
+		filepath.Join(root, "Game.csproj"): `<Project Sdk="Microsoft.NET.Sdk"></Project>`,
+		playerPath: `using static Game.Util.MathUtil;
+using static System.Math;
+
+namespace Game;
+
+public class Player
+{
+    public float Speed() => Clamp01(Abs(-1f));
+}
+`,
+		mathPath: `namespace Game.Util
+{
+    public static class MathUtil
+    {
+        public static float Clamp01(float value) => value;
+    }
+}
+`,
+	}
+
+	assert.Equal(t, []string{mathPath}, resolveCSharpFile(t, files, playerPath))
+}
@@ -20,13 +20,13 @@ func (Module) Maturity() moduleapi.MaturityLevel {
 }
 
 func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReader) moduleapi.Resolver {
-	namespaceToFiles, namespaceToTypes, fileToNamespace, fileToScope := BuildCSharpIndices(ctx.SuppliedFiles, contentReader)
+	namespaceToFiles, namespaceToTypes, fileToNamespaces, fileToScope := BuildCSharpIndices(ctx.SuppliedFiles, contentReader, ctx.ParseCache)
 	return resolver{
 		ctx:              ctx,
 		contentReader:    contentReader,
 		namespaceToFiles: namespaceToFiles,
 		namespaceToTypes: namespaceToTypes,
-		fileToNamespace:  fileToNamespace,
+		fileToNamespaces: fileToNamespaces,
 		fileToScope:      fileToScope,
 	}
 }
@@ -40,7 +40,7 @@ type resolver struct {
 	contentReader    vcs.ContentReader
 	namespaceToFiles map[string][]string
 	namespaceToTypes map[string]map[string][]string
-	fileToNamespace  map[string]string
+	fileToNamespaces map[string][]string
 	fileToScope      map[string]string
 }
 
@@ -50,10 +50,11 @@ func (r resolver) ResolveProjectImports(absPath, filePath, ext string) ([]string
 		filePath,
 		r.namespaceToFiles,
 		r.namespaceToTypes,
-		r.fileToNamespace,
+		r.fileToNamespaces,
 		r.fileToScope,
 		r.ctx.SuppliedFiles,
-		r.contentReader)
+		r.contentReader,
+		r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
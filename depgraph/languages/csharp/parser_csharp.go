@@ -26,6 +26,8 @@ var (
 // CSharpImport represents a using directive.
 type CSharpImport struct {
 	Path string
+	// Static reports a "using static" directive, which imports the members of a type.
+	Static bool
 }
 
 // CSharpImports parses a C# file and returns its imports.
@@ -82,7 +84,7 @@ func extractUsingDirectives(root *sitter.Node, sourceCode []byte) []CSharpImport
 		if node.Type() == "using_directive" {
 			path := extractUsingPath(node, sourceCode)
 			if path != "" {
-				imports = append(imports, CSharpImport{Path: path})
+				imports = append(imports, CSharpImport{Path: path, Static: isStaticUsing(node)})
 			}
 			return
 		}
@@ -95,6 +97,16 @@ func extractUsingDirectives(root *sitter.Node, sourceCode []byte) []CSharpImport
 	return imports
 }
 
+// isStaticUsing reports whether a using_directive carries the static keyword.
+func isStaticUsing(usingNode *sitter.Node) bool {
+	for i := 0; i < int(usingNode.ChildCount()); i++ {
+		if child := usingNode.Child(i); child != nil && child.Type() == "static" {
+			return true
+		}
+	}
+	return false
+}
+
 func extractUsingPath(usingNode *sitter.Node, sourceCode []byte) string {
 	if usingNode == nil {
 		return ""
@@ -130,6 +142,7 @@ func parseCSharpImportsFallback(source string) []CSharpImport {
 		}
 		statement := strings.TrimSpace(strings.TrimSuffix(trimmed, ";"))
 		statement = strings.TrimPrefix(statement, "using ")
+		static := strings.HasPrefix(statement, "static ")
 		statement = strings.TrimPrefix(statement, "static ")
 		if eq := strings.Index(statement, "="); eq >= 0 {
 			statement = strings.TrimSpace(statement[eq+1:])
@@ -138,7 +151,7 @@ func parseCSharpImportsFallback(source string) []CSharpImport {
 		if statement == "" || strings.HasPrefix(statement, "(") {
 			continue
 		}
-		imports = append(imports, CSharpImport{Path: statement})
+		imports = append(imports, CSharpImport{Path: statement, Static: static})
 	}
 	return imports
 }
@@ -205,6 +218,75 @@ func ParseTopLevelCSharpTypeNames(source string) []string {
 	return names
 }
 
+// CSharpTypeDeclaration is a top-level type and the namespace it is declared in.
+type CSharpTypeDeclaration struct {
+	Namespace string
+	Name      string
+}
+
+// ParseCSharpTypeDeclarations extracts the top-level types declared in a file with
+// their full namespace, so files with several or nested namespace blocks index each
+// type under the namespace that encloses it.
+func ParseCSharpTypeDeclarations(source string) []CSharpTypeDeclaration {
+	sourceCode := []byte(source)
+	tree, cleanup, err := parseCSharpTree(sourceCode)
+	if err != nil {
+		namespace := ParseCSharpNamespace(source)
+		var declarations []CSharpTypeDeclaration
+		for _, name := range ParseTopLevelCSharpTypeNames(source) {
+			declarations = append(declarations, CSharpTypeDeclaration{Namespace: namespace, Name: name})
+		}
+		return declarations
+	}
+	defer cleanup()
+
+	var declarations []CSharpTypeDeclaration
+	seen := make(map[CSharpTypeDeclaration]bool)
+	var walk func(node *sitter.Node, namespace string)
+	walk = func(node *sitter.Node, namespace string) {
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if child == nil {
+				continue
+			}
+			switch child.Type() {
+			case "namespace_declaration":
+				walk(child, joinCSharpNamespace(namespace, declaredNamespaceName(child, sourceCode)))
+			case "file_scoped_namespace_declaration":
+				// Declarations after a file-scoped namespace are its siblings.
+				namespace = joinCSharpNamespace(namespace, declaredNamespaceName(child, sourceCode))
+			case "declaration_list":
+				walk(child, namespace)
+			case "class_declaration", "interface_declaration", "struct_declaration", "enum_declaration", "record_declaration", "delegate_declaration":
+				declaration := CSharpTypeDeclaration{Namespace: namespace, Name: extractDeclarationName(child, sourceCode)}
+				if declaration.Name != "" && !seen[declaration] {
+					seen[declaration] = true
+					declarations = append(declarations, declaration)
+				}
+			}
+		}
+	}
+	walk(tree.RootNode(), "")
+	return declarations
+}
+
+func declaredNamespaceName(node *sitter.Node, sourceCode []byte) string {
+	if name := node.ChildByFieldName("name"); name != nil {
+		return strings.TrimSpace(name.Content(sourceCode))
+	}
+	return ""
+}
+
+func joinCSharpNamespace(outer, inner string) string {
+	if outer == "" {
+		return inner
+	}
+	if inner == "" {
+		return outer
+	}
+	return outer + "." + inner
+}
+
 func extractNamespace(root *sitter.Node, sourceCode []byte) string {
 	if root == nil {
 		return ""
@@ -28,6 +28,7 @@ namespace App {
 	assert.Equal(t, "System.Collections.Generic", imports[1].Path)
 	assert.Equal(t, "System.Math", imports[2].Path)
 	assert.Equal(t, "MyApp.Core", imports[3].Path)
+	assert.Equal(t, []bool{false, false, true, false}, []bool{imports[0].Static, imports[1].Static, imports[2].Static, imports[3].Static})
 }
 
 func TestCSharpImports_ValidFile(t *testing.T) {
@@ -98,3 +99,36 @@ public class RoomGrain {}
 	assert.Contains(t, identifiers, "IRoomGrain")
 	assert.NotContains(t, identifiers, "RoomGrain")
 }
+
+func TestParseCSharpTypeDeclarations_NestedAndFileScopedNamespaces(t *testing.T) {
+	nested := `
+namespace Acme {
+    public class Root {}
+    namespace Tools.Io {
+        public class Reader
+        {
+            public class Nested {}
+        }
+    }
+}
+namespace Other {
+    internal enum Mode {}
+}
+`
+	assert.Equal(t, []CSharpTypeDeclaration{
+		{Namespace: "Acme", Name: "Root"},
+		{Namespace: "Acme.Tools.Io", Name: "Reader"},
+		{Namespace: "Other", Name: "Mode"},
+	}, ParseCSharpTypeDeclarations(nested))
+
+	fileScoped := `
+namespace Acme.Tools;
+
+public record Settings(string Name);
+public static class Defaults {}
+`
+	assert.Equal(t, []CSharpTypeDeclaration{
+		{Namespace: "Acme.Tools", Name: "Settings"},
+		{Namespace: "Acme.Tools", Name: "Defaults"},
+	}, ParseCSharpTypeDeclarations(fileScoped))
+}
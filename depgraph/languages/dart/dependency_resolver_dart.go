@@ -4,10 +4,41 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+const pubspecFileName = "pubspec.yaml"
+
+// importsCacheKind names the dartImports the resolver caches per file.
+const importsCacheKind = "dart-imports/v1"
+
+// dartImport is the cached form of an Import.
+type dartImport struct {
+	URI  string   `json:"uri"`
+	Show []string `json:"show,omitempty"`
+}
+
+func parseDartImports(content []byte) ([]dartImport, error) {
+	imports, err := ParseImports(content)
+	if err != nil {
+		return nil, err
+	}
+	cached := make([]dartImport, 0, len(imports))
+	for _, imp := range imports {
+		entry := dartImport{URI: imp.URI()}
+		if projImp, ok := imp.(ProjectImport); ok {
+			entry.Show = projImp.Show()
+		}
+		cached = append(cached, entry)
+	}
+	return cached, nil
+}
+
 func ResolveDartProjectImports(
 	absPath string,
 	filePath string,
@@ -15,22 +46,73 @@ func ResolveDartProjectImports(
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	return resolveDartProjectImports(absPath, filePath, ext, suppliedFiles, contentReader, nil, nil, nil, nil, nil)
+}
+
+func resolveDartProjectImports(
+	absPath string,
+	filePath string,
+	ext string,
+	suppliedFiles map[string]bool,
+	contentReader vcs.ContentReader,
+	packageRoots *packageRootCache,
+	diagnostics *moduleapi.Diagnostics,
+	edgeSymbols *moduleapi.EdgeSymbols,
+	externalImports *moduleapi.ExternalImports,
+	cache moduleapi.ParseCache,
+) ([]string, error) {
+	cachedImports, err := moduleapi.ParseFile(cache, contentReader, importsCacheKind, absPath, func(content []byte) ([]dartImport, error) {
+		imports, err := parseDartImports(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, err)
+		}
+		return imports, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+		return nil, err
+	}
+	imports := make([]Import, 0, len(cachedImports))
+	for _, imp := range cachedImports {
+		imports = append(imports, classifyImport(imp.URI, imp.Show))
 	}
 
-	imports, err := ParseImports(content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, err)
+	if packageRoots == nil {
+		packageRoots = &packageRootCache{}
 	}
 
 	var projectImports []string
+	seen := make(map[string]bool)
 	for _, imp := range imports {
+		if pkgImp, ok := imp.(PackageImport); ok {
+			// A package: import of the file's own package names a file under its lib/.
+			resolvedPath, ok := resolvePackageSelfImport(absPath, pkgImp.URI(), packageRoots, contentReader)
+			if ok && suppliedFiles[resolvedPath] && !seen[resolvedPath] {
+				seen[resolvedPath] = true
+				projectImports = append(projectImports, resolvedPath)
+			}
+			if !ok {
+				recordExternalPackage(absPath, pkgImp.URI(), externalImports)
+			}
+			continue
+		}
 		if projImp, ok := imp.(ProjectImport); ok {
 			resolvedPath := resolveImportPath(absPath, projImp.URI(), ext)
-			if suppliedFiles[resolvedPath] {
-				projectImports = append(projectImports, resolvedPath)
+			linked := suppliedFiles[resolvedPath]
+			if linked {
+				if !seen[resolvedPath] {
+					seen[resolvedPath] = true
+					projectImports = append(projectImports, resolvedPath)
+				}
+				edgeSymbols.Record(absPath, resolvedPath, projImp.Show()...)
+			}
+
+			if diagnostics != nil && isCrossPackageImport(absPath, resolvedPath, packageRoots, contentReader) {
+				diagnostics.Report(moduleapi.Diagnostic{
+					Category: moduleapi.DiagnosticCrossPackageRelativeImport,
+					File:     absPath,
+					Target:   resolvedPath,
+					Linked:   linked,
+				})
 			}
 		}
 	}
@@ -38,6 +120,17 @@ func ResolveDartProjectImports(
 	return projectImports, nil
 }
 
+// recordExternalPackage reports a package: import of another package as the external
+// package named by the URI without its scheme and .dart suffix, as in
+// pkg:flutter/material. SDK dart: imports are not reported.
+func recordExternalPackage(sourceFile, uri string, externalImports *moduleapi.ExternalImports) {
+	library, ok := strings.CutPrefix(uri, "package:")
+	if !ok {
+		return
+	}
+	externalImports.Record(sourceFile, moduleapi.EcosystemDart, strings.TrimSuffix(library, ".dart"))
+}
+
 // resolveImportPath converts a relative import URI to an absolute path
 func resolveImportPath(sourceFile, importURI, fileExt string) string {
 	// Get directory of source file
@@ -53,3 +146,86 @@ func resolveImportPath(sourceFile, importURI, fileExt string) string {
 
 	return filepath.Clean(absImport)
 }
+
+// resolvePackageSelfImport returns the file a package: URI names when its package is
+// the one containing sourceFile, as in package:myapp/models/user.dart imported from
+// inside myapp. The package name is read from the nearest pubspec.yaml, and the rest
+// of the URI is resolved against the package's lib/ directory.
+func resolvePackageSelfImport(sourceFile, uri string, packageRoots *packageRootCache, contentReader vcs.ContentReader) (string, bool) {
+	rest, ok := strings.CutPrefix(uri, "package:")
+	if !ok {
+		return "", false
+	}
+	name, libPath, ok := strings.Cut(rest, "/")
+	if !ok || name == "" || libPath == "" {
+		return "", false
+	}
+	root, ok := packageRoots.lookup(filepath.Dir(sourceFile), contentReader)
+	if !ok || packageRoots.packageName(root, contentReader) != name {
+		return "", false
+	}
+	return filepath.Join(root, "lib", filepath.FromSlash(libPath)), true
+}
+
+// isCrossPackageImport reports whether a relative import leaves the Dart package of
+// the importing file for another package, as when tests in a melos workspace share
+// helpers through paths like ../../b/test/utils/helpers.dart.
+func isCrossPackageImport(sourceFile, targetFile string, packageRoots *packageRootCache, contentReader vcs.ContentReader) bool {
+	sourceRoot, ok := packageRoots.lookup(filepath.Dir(sourceFile), contentReader)
+	if !ok {
+		return false
+	}
+	targetRoot, ok := packageRoots.lookup(filepath.Dir(targetFile), contentReader)
+	return ok && targetRoot != sourceRoot
+}
+
+// packageRootCache remembers the nearest directory with a pubspec.yaml for each
+// directory looked up, and the package name each of those pubspec.yaml files declares.
+// It is safe for concurrent use.
+type packageRootCache struct {
+	roots sync.Map // dir -> string, "" when no package contains dir
+	names sync.Map // package root -> string, "" when the pubspec.yaml has no name
+}
+
+func (c *packageRootCache) lookup(dir string, contentReader vcs.ContentReader) (string, bool) {
+	var visited []string
+	root := ""
+	for current := dir; ; current = filepath.Dir(current) {
+		if cached, ok := c.roots.Load(current); ok {
+			root = cached.(string)
+			break
+		}
+		visited = append(visited, current)
+		if contentReader.Exists(filepath.Join(current, pubspecFileName)) {
+			root = current
+			break
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	for _, visitedDir := range visited {
+		c.roots.Store(visitedDir, root)
+	}
+	return root, root != ""
+}
+
+// packageName returns the name declared by the pubspec.yaml in root, or "" when it
+// cannot be read or declares none.
+func (c *packageRootCache) packageName(root string, contentReader vcs.ContentReader) string {
+	if cached, ok := c.names.Load(root); ok {
+		return cached.(string)
+	}
+	name := ""
+	if content, err := contentReader.ReadFile(filepath.Join(root, pubspecFileName)); err == nil {
+		var pubspec struct {
+			Name string `yaml:"name"`
+		}
+		if yaml.Unmarshal(content, &pubspec) == nil {
+			name = pubspec.Name
+		}
+	}
+	c.names.Store(root, name)
+	return name
+}
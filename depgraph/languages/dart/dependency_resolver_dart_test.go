@@ -0,0 +1,91 @@
+package dart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// selfImportFixture returns the root of the myapp fixture, whose files import each
+// other through package:myapp URIs, and the supplied-file set of its Dart files.
+func selfImportFixture(t *testing.T) (string, map[string]bool) {
+	t.Helper()
+
+	root, err := filepath.Abs(filepath.Join("testdata", "selfimport"))
+	require.NoError(t, err)
+	supplied := make(map[string]bool)
+	for _, file := range []string{"lib/main.dart", "lib/models/user.dart", "lib/src/widgets/button.dart", "test/user_test.dart"} {
+		supplied[filepath.Join(root, filepath.FromSlash(file))] = true
+	}
+	return root, supplied
+}
+
+func TestResolveDartProjectImports_PackageSelfImportResolvesUnderLib(t *testing.T) {
+	root, supplied := selfImportFixture(t)
+	testFile := filepath.Join(root, "test", "user_test.dart")
+
+	imports, err := ResolveDartProjectImports(testFile, testFile, ".dart", supplied, vcs.FilesystemContentReader())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "lib", "models", "user.dart")}, imports)
+}
+
+func TestResolveDartProjectImports_PackageSelfImportOfNestedSrcPath(t *testing.T) {
+	root, supplied := selfImportFixture(t)
+	mainFile := filepath.Join(root, "lib", "main.dart")
+
+	imports, err := ResolveDartProjectImports(mainFile, mainFile, ".dart", supplied, vcs.FilesystemContentReader())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "lib", "models", "user.dart"),
+		filepath.Join(root, "lib", "src", "widgets", "button.dart"),
+	}, imports, "package and relative imports of user.dart give one edge")
+}
+
+func TestResolveDartProjectImports_OtherPackagesStayExternal(t *testing.T) {
+	root, supplied := selfImportFixture(t)
+	buttonFile := filepath.Join(root, "lib", "src", "widgets", "button.dart")
+
+	imports, err := ResolveDartProjectImports(buttonFile, buttonFile, ".dart", supplied, vcs.FilesystemContentReader())
+
+	require.NoError(t, err)
+	assert.Empty(t, imports)
+}
+
+func TestResolveDartProjectImports_PackageSelfImportFromCommitContent(t *testing.T) {
+	root, supplied := selfImportFixture(t)
+	contents := make(map[string]string)
+	for _, file := range []string{"pubspec.yaml", "lib/models/user.dart", "test/user_test.dart"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		contents[path] = string(content)
+	}
+	testFile := filepath.Join(root, "test", "user_test.dart")
+
+	imports, err := ResolveDartProjectImports(testFile, testFile, ".dart", supplied, testhelpers.MapContentReader(contents))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "lib", "models", "user.dart")}, imports)
+}
+
+func TestResolveDartProjectImports_PackageImportWithoutPubspecStaysExternal(t *testing.T) {
+	mainFile := filepath.Clean("/repo/lib/main.dart")
+	userFile := filepath.Clean("/repo/lib/models/user.dart")
+	reader := testhelpers.MapContentReader(map[string]string{
+		mainFile: "import 'package:myapp/models/user.dart';\n",
+		userFile: "class User {}\n",
+	})
+
+	imports, err := ResolveDartProjectImports(mainFile, mainFile, ".dart", map[string]bool{mainFile: true, userFile: true}, reader)
+
+	require.NoError(t, err)
+	assert.Empty(t, imports)
+}
@@ -20,7 +20,7 @@ func (Module) Maturity() moduleapi.MaturityLevel {
 }
 
 func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReader) moduleapi.Resolver {
-	return resolver{ctx: ctx, contentReader: contentReader}
+	return resolver{ctx: ctx, contentReader: contentReader, packageRoots: &packageRootCache{}}
 }
 
 func (Module) IsTestFile(filePath string, _ vcs.ContentReader) bool {
@@ -30,10 +30,11 @@ func (Module) IsTestFile(filePath string, _ vcs.ContentReader) bool {
 type resolver struct {
 	ctx           *moduleapi.Context
 	contentReader vcs.ContentReader
+	packageRoots  *packageRootCache
 }
 
 func (r resolver) ResolveProjectImports(absPath, filePath, ext string) ([]string, error) {
-	return ResolveDartProjectImports(absPath, filePath, ext, r.ctx.SuppliedFiles, r.contentReader)
+	return resolveDartProjectImports(absPath, filePath, ext, r.ctx.SuppliedFiles, r.contentReader, r.packageRoots, r.ctx.Diagnostics, r.ctx.EdgeSymbols, r.ctx.ExternalImports, r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
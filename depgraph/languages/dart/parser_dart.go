@@ -27,18 +27,25 @@ func (p PackageImport) URI() string {
 
 // ProjectImport represents an internal project file (relative paths)
 type ProjectImport struct {
-	uri string
+	uri  string
+	show []string
 }
 
 func (p ProjectImport) URI() string {
 	return p.uri
 }
 
-func classifyImport(uri string) Import {
+// Show returns the names listed in the directive's show combinators, or nil when it
+// has none.
+func (p ProjectImport) Show() []string {
+	return p.show
+}
+
+func classifyImport(uri string, show []string) Import {
 	if strings.HasPrefix(uri, "dart:") || strings.HasPrefix(uri, "package:") {
 		return PackageImport{uri: uri}
 	}
-	return ProjectImport{uri: uri}
+	return ProjectImport{uri: uri, show: show}
 }
 
 func Imports(filePath string) ([]Import, error) {
@@ -80,6 +87,10 @@ func ParseImports(sourceCode []byte) ([]Import, error) {
 	return []Import{}, nil
 }
 
+// primaryQueryPattern captures the URIs of every directive that makes a file depend on
+// another: imports and exports, including each branch of a conditional import, parts,
+// and the library a part belongs to. "part of" directives that name the library
+// instead of its URI have no URI to capture.
 const primaryQueryPattern = `
 (import_or_export
   (library_import
@@ -87,6 +98,35 @@ const primaryQueryPattern = `
       (configurable_uri
         (uri
           (string_literal) @import.uri)))))
+
+(import_or_export
+  (library_import
+    (import_specification
+      (configurable_uri
+        (configuration_uri
+          (uri
+            (string_literal) @import.uri))))))
+
+(import_or_export
+  (library_export
+    (configurable_uri
+      (uri
+        (string_literal) @import.uri))))
+
+(import_or_export
+  (library_export
+    (configurable_uri
+      (configuration_uri
+        (uri
+          (string_literal) @import.uri)))))
+
+(part_directive
+  (uri
+    (string_literal) @import.uri))
+
+(part_of_directive
+  (uri
+    (string_literal) @import.uri))
 `
 
 var fallbackQueryPatterns = []string{
@@ -149,13 +189,39 @@ func runQueryImports(rootNode *sitter.Node, sourceCode []byte, query *sitter.Que
 			content := capture.Node.Content(sourceCode)
 			// Remove quotes from string literal
 			importURI := cleanImportURI(content)
-			imports = append(imports, classifyImport(importURI))
+			imports = append(imports, classifyImport(importURI, shownNames(capture.Node, sourceCode)))
 		}
 	}
 
 	return imports, nil
 }
 
+// shownNames returns the names in the show combinators of the import or export
+// directive containing uriNode.
+func shownNames(uriNode *sitter.Node, sourceCode []byte) []string {
+	directive := uriNode.Parent()
+	for directive != nil && directive.Type() != "import_specification" && directive.Type() != "library_export" {
+		directive = directive.Parent()
+	}
+	if directive == nil {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < int(directive.NamedChildCount()); i++ {
+		combinator := directive.NamedChild(i)
+		if combinator.Type() != "combinator" || combinator.ChildCount() == 0 || combinator.Child(0).Type() != "show" {
+			continue
+		}
+		for j := 0; j < int(combinator.NamedChildCount()); j++ {
+			if name := combinator.NamedChild(j); name.Type() == "identifier" {
+				names = append(names, name.Content(sourceCode))
+			}
+		}
+	}
+	return names
+}
+
 // cleanImportURI removes quotes and trims whitespace from import URIs
 func cleanImportURI(raw string) string {
 	// Remove single or double quotes
@@ -68,9 +68,9 @@ func TestParseImports_RelativePaths(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, imports, 3)
 
-	assert.Contains(t, imports, ProjectImport{"src/helper.dart"})
-	assert.Contains(t, imports, ProjectImport{"../utils/common.dart"})
-	assert.Contains(t, imports, ProjectImport{"models/user.dart"})
+	assert.Contains(t, imports, ProjectImport{uri: "src/helper.dart"})
+	assert.Contains(t, imports, ProjectImport{uri: "../utils/common.dart"})
+	assert.Contains(t, imports, ProjectImport{uri: "models/user.dart"})
 }
 
 func TestParseImports_EmptyFile(t *testing.T) {
@@ -180,7 +180,82 @@ func TestParseImports_ComplexExample(t *testing.T) {
 	assert.Contains(t, imports, PackageImport{"dart:async"})
 	assert.Contains(t, imports, PackageImport{"package:flutter/material.dart"})
 	assert.Contains(t, imports, PackageImport{"package:provider/provider.dart"})
-	assert.Contains(t, imports, ProjectImport{"src/models/user.dart"})
-	assert.Contains(t, imports, ProjectImport{"../utils/helper.dart"})
-	assert.Contains(t, imports, ProjectImport{"services/api.dart"})
+	assert.Contains(t, imports, ProjectImport{uri: "src/models/user.dart"})
+	assert.Contains(t, imports, ProjectImport{uri: "../utils/helper.dart", show: []string{"formatDate", "formatTime"}})
+	assert.Contains(t, imports, ProjectImport{uri: "services/api.dart"})
+}
+
+func TestParseImports_ShowCombinators(t *testing.T) {
+	source := `
+		import 'src/models.dart' show User, Order hide Cart;
+		import 'src/api.dart' as api show Client;
+		import 'src/helpers.dart';
+`
+	imports, err := ParseImports([]byte(source))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Import{
+		ProjectImport{uri: "src/models.dart", show: []string{"User", "Order"}},
+		ProjectImport{uri: "src/api.dart", show: []string{"Client"}},
+		ProjectImport{uri: "src/helpers.dart"},
+	}, imports)
+}
+
+func TestParseImports_ExportsAlongsideImports(t *testing.T) {
+	source := `
+		import 'src/helpers.dart';
+		export 'src/foo.dart' show Foo;
+		export 'package:meta/meta.dart';
+`
+	imports, err := ParseImports([]byte(source))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Import{
+		ProjectImport{uri: "src/helpers.dart"},
+		ProjectImport{uri: "src/foo.dart", show: []string{"Foo"}},
+		PackageImport{"package:meta/meta.dart"},
+	}, imports)
+}
+
+func TestParseImports_PartDirectives(t *testing.T) {
+	library := `
+		library shapes;
+		part 'src/circle.dart';
+`
+	imports, err := ParseImports([]byte(library))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Import{ProjectImport{uri: "src/circle.dart"}}, imports)
+
+	part := `
+		part of '../shapes.dart';
+`
+	imports, err = ParseImports([]byte(part))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Import{ProjectImport{uri: "../shapes.dart"}}, imports)
+}
+
+func TestParseImports_PartOfLibraryNameHasNoURI(t *testing.T) {
+	source := `
+		part of shapes;
+`
+	imports, err := ParseImports([]byte(source))
+
+	require.NoError(t, err)
+	assert.Empty(t, imports)
+}
+
+func TestParseImports_ConditionalImportIncludesEveryBranch(t *testing.T) {
+	source := `
+		import 'io.dart' if (dart.library.html) 'html.dart' if (dart.library.js) 'js.dart';
+`
+	imports, err := ParseImports([]byte(source))
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Import{
+		ProjectImport{uri: "io.dart"},
+		ProjectImport{uri: "html.dart"},
+		ProjectImport{uri: "js.dart"},
+	}, imports)
 }
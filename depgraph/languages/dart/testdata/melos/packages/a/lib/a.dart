@@ -0,0 +1,3 @@
+class Greeter {
+  String greet(String name) => 'Hello, $name';
+}
@@ -0,0 +1,10 @@
+import 'package:test/test.dart';
+
+import '../lib/a.dart';
+import '../../b/test/utils/helpers.dart';
+
+void main() {
+  test('greets', () {
+    expect(Greeter().greet(fixtureName()), 'Hello, fixture');
+  });
+}
@@ -0,0 +1 @@
+const defaultName = 'fixture';
@@ -0,0 +1,3 @@
+import '../../lib/b.dart';
+
+String fixtureName() => defaultName;
@@ -0,0 +1,8 @@
+import 'package:flutter/material.dart';
+import 'package:myapp/models/user.dart';
+import 'models/user.dart';
+import 'package:myapp/src/widgets/button.dart';
+
+void main() {
+  runApp(AppButton(label: User('ada').name));
+}
@@ -0,0 +1,5 @@
+class User {
+  User(this.name);
+
+  final String name;
+}
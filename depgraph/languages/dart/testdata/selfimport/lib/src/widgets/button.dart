@@ -0,0 +1,11 @@
+import 'package:flutter/material.dart';
+import 'package:otherapp/models/user.dart';
+
+class AppButton extends StatelessWidget {
+  const AppButton({super.key, required this.label});
+
+  final String label;
+
+  @override
+  Widget build(BuildContext context) => Text(label);
+}
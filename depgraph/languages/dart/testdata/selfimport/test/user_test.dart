@@ -0,0 +1,8 @@
+import 'package:test/test.dart';
+import 'package:myapp/models/user.dart';
+
+void main() {
+  test('keeps the name', () {
+    expect(User('ada').name, 'ada');
+  });
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
@@ -19,8 +20,18 @@ type ProjectImportResolver struct {
 	contentReader          vcs.ContentReader
 	moduleRootCache        sync.Map // source dir -> module root (or "")
 	moduleInfoCache        sync.Map // module root -> goModuleInfo
+	workspaceCache         sync.Map // module root -> []goWorkspaceModule
 	importPathCache        sync.Map // source file + import path -> resolved package dir (or "")
 	analysisCache          sync.Map // absolute file path -> *GoFileAnalysis
+	parseCache             moduleapi.ParseCache
+	edgeSymbols            *moduleapi.EdgeSymbols
+	externalImports        *moduleapi.ExternalImports
+	// goModules maps import path prefixes to package directories in place of go.mod
+	// discovery; see moduleapi.Context.GoModules.
+	goModules map[string]string
+	// pathAliases maps symlinks to the canonical paths they point at; see
+	// moduleapi.Context.PathAliases.
+	pathAliases map[string]string
 }
 
 type goModuleInfo struct {
@@ -33,13 +44,27 @@ func NewProjectImportResolver(
 	dirToFiles map[string][]string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+) *ProjectImportResolver {
+	return newProjectImportResolver(dirToFiles, suppliedFiles, contentReader, 0, nil)
+}
+
+// newProjectImportResolver creates the resolver like NewProjectImportResolver, parsing
+// up to parallelism files at once (0 means GOMAXPROCS) to build the export indices and
+// reusing the analyses parseCache holds.
+func newProjectImportResolver(
+	dirToFiles map[string][]string,
+	suppliedFiles map[string]bool,
+	contentReader vcs.ContentReader,
+	parallelism int,
+	parseCache moduleapi.ParseCache,
 ) *ProjectImportResolver {
 	resolver := &ProjectImportResolver{
 		dirToFiles:    dirToFiles,
 		suppliedFiles: suppliedFiles,
 		contentReader: contentReader,
+		parseCache:    parseCache,
 	}
-	resolver.goPackageExportIndices = resolver.buildGoPackageExportIndices()
+	resolver.goPackageExportIndices = resolver.buildGoPackageExportIndices(parallelism)
 	return resolver
 }
 
@@ -49,7 +74,7 @@ func (r *ProjectImportResolver) ResolveProjectImports(absPath, filePath string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, err)
 	}
-	return resolveGoProjectImportsFromAnalysis(
+	projectImports := resolveGoProjectImportsFromAnalysis(
 		absPath,
 		r.dirToFiles,
 		r.goPackageExportIndices,
@@ -57,7 +82,10 @@ func (r *ProjectImportResolver) ResolveProjectImports(absPath, filePath string)
 		analysis.Imports,
 		analysis.Embeds,
 		analysis.ExportInfo,
-		r.resolveImportPath), nil
+		r.resolveCanonicalImportPath,
+		r.edgeSymbols,
+		r.externalImports)
+	return append(projectImports, resolveGoLocalFileReferences(absPath, analysis, r.suppliedFiles)...), nil
 }
 
 func BuildGoPackageExportIndices(dirToFiles map[string][]string, contentReader vcs.ContentReader) map[string]GoPackageExportIndex {
@@ -73,7 +101,7 @@ func BuildGoPackageExportIndices(dirToFiles map[string][]string, contentReader v
 			}
 		}
 		if hasGoFiles {
-			exportIndex, err := BuildPackageExportIndex(goFilesInDir, vcs.ContentReader(contentReader))
+			exportIndex, err := BuildPackageExportIndex(goFilesInDir, contentReader)
 			if err != nil {
 				continue
 			}
@@ -92,27 +120,65 @@ func ResolveGoProjectImports(
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
 ) ([]string, error) {
-	sourceContent, err := contentReader(absPath)
+	sourceContent, err := contentReader.ReadFile(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
 	}
 
-	imports, embeds, _, exportInfo, err := AnalyzeGoFileFromContent(absPath, sourceContent)
+	analysis, err := AnalyzeGoFileDetailsFromContent(absPath, sourceContent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, err)
 	}
-	return resolveGoProjectImportsFromAnalysis(
+	projectImports := resolveGoProjectImportsFromAnalysis(
 		absPath,
 		dirToFiles,
 		goPackageExportIndices,
 		suppliedFiles,
-		imports,
-		embeds,
-		exportInfo,
+		analysis.Imports,
+		analysis.Embeds,
+		analysis.ExportInfo,
 		func(sourceFile, importPath string) string {
 			return resolveGoImportPath(sourceFile, importPath, contentReader)
 		},
-	), nil
+		nil,
+		nil,
+	)
+	return append(projectImports, resolveGoLocalFileReferences(absPath, analysis, suppliedFiles)...), nil
+}
+
+// resolveGoLocalFileReferences returns the supplied files a Go file names outside its
+// imports: headers and sources its cgo preamble includes with quotes, and files such
+// as generator sources or scripts that its //go:generate directives mention. Paths
+// are relative to the file's directory.
+func resolveGoLocalFileReferences(absPath string, analysis *GoFileAnalysis, suppliedFiles map[string]bool) []string {
+	sourceDir := filepath.Dir(absPath)
+	var references []string
+	addIfSupplied := func(reference string) {
+		candidate := filepath.Clean(filepath.Join(sourceDir, filepath.FromSlash(reference)))
+		if candidate != absPath && suppliedFiles[candidate] {
+			references = append(references, candidate)
+		}
+	}
+
+	for _, include := range analysis.CgoIncludes {
+		addIfSupplied(include)
+	}
+	for _, arg := range analysis.GenerateArgs {
+		// Flags may carry a file as their value, as in -template=tmpl.go.
+		if strings.HasPrefix(arg, "-") {
+			_, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				continue
+			}
+			arg = value
+		}
+		arg = strings.Trim(arg, `"'`)
+		if arg == "" || strings.Contains(arg, "$") || filepath.IsAbs(arg) {
+			continue
+		}
+		addIfSupplied(arg)
+	}
+	return references
 }
 
 func resolveGoProjectImportsFromAnalysis(
@@ -124,6 +190,8 @@ func resolveGoProjectImportsFromAnalysis(
 	embeds []GoEmbed,
 	exportInfo *GoExportInfo,
 	importPathResolver func(sourceFile, importPath string) string,
+	edgeSymbols *moduleapi.EdgeSymbols,
+	externalImports *moduleapi.ExternalImports,
 ) []string {
 	projectImports := make([]string, 0, len(imports))
 
@@ -145,6 +213,9 @@ func resolveGoProjectImportsFromAnalysis(
 
 		packageDir := importPathResolver(absPath, importPath)
 		if packageDir == "" {
+			if _, external := imp.(ExternalImport); external {
+				externalImports.Record(absPath, moduleapi.EcosystemGo, importPath)
+			}
 			continue
 		}
 
@@ -156,37 +227,72 @@ func resolveGoProjectImportsFromAnalysis(
 		if exportInfo != nil {
 			usedSymbols = GetUsedSymbolsFromPackage(exportInfo, importPath)
 		}
+		filterBySymbols := (!sameDir || isTestFile) && hasExportIndex && len(usedSymbols) > 0
 
-		if files, ok := dirToFiles[packageDir]; ok {
-			for _, depFile := range files {
-				if depFile == absPath {
-					continue
-				}
-				if strings.HasSuffix(depFile, "_test.go") && !sameDir {
-					continue
-				}
-				if filepath.Ext(depFile) != ".go" {
-					continue
-				}
-				if (!sameDir || isTestFile) && hasExportIndex && usedSymbols != nil && len(usedSymbols) > 0 {
-					if !fileDefinesAnyUsedSymbol(depFile, usedSymbols, exportIndex) {
-						continue
-					}
-				}
-				projectImports = append(projectImports, depFile)
+		var packageFiles, usingFiles []string
+		for _, depFile := range dirToFiles[packageDir] {
+			if depFile == absPath {
+				continue
+			}
+			if strings.HasSuffix(depFile, "_test.go") && !sameDir {
+				continue
+			}
+			if filepath.Ext(depFile) != ".go" {
+				continue
+			}
+			packageFiles = append(packageFiles, depFile)
+			if !filterBySymbols {
+				continue
+			}
+			definedSymbols := usedSymbolsDefinedIn(depFile, usedSymbols, exportIndex)
+			if len(definedSymbols) == 0 {
+				continue
 			}
+			edgeSymbols.Record(absPath, depFile, definedSymbols...)
+			edgeSymbols.RecordUsages(absPath, depFile, countUsages(definedSymbols, symbolUsageCounts(exportInfo, importPath)))
+			usingFiles = append(usingFiles, depFile)
+		}
+
+		switch {
+		case !filterBySymbols:
+			projectImports = append(projectImports, packageFiles...)
+		case len(usingFiles) == 0 && exportInfo.DotImports[importPath]:
+			// None of the unqualified references could be attributed to the dot-imported
+			// package, so keep the package-level edges rather than none.
+			projectImports = append(projectImports, packageFiles...)
+		default:
+			projectImports = append(projectImports, usingFiles...)
 		}
 	}
 
 	return projectImports
 }
 
+// resolveCanonicalImportPath resolves importPath like resolveImportPath, and maps a
+// package directory reached through a symlink to the directory it points at, where
+// the supplied files are.
+func (r *ProjectImportResolver) resolveCanonicalImportPath(sourceFile, importPath string) string {
+	packageDir := r.resolveImportPath(sourceFile, importPath)
+	if packageDir == "" {
+		return ""
+	}
+	return moduleapi.CanonicalPath(r.pathAliases, packageDir)
+}
+
 func (r *ProjectImportResolver) resolveImportPath(sourceFile, importPath string) string {
 	cacheKey := sourceFile + "\x00" + importPath
 	if cached, ok := r.importPathCache.Load(cacheKey); ok {
 		return cached.(string)
 	}
 
+	// An explicit mapping replaces go.mod files entirely, so none are read; its
+	// prefixes match like replace directives, the longest first.
+	if len(r.goModules) > 0 {
+		resolved := resolveViaReplace(importPath, r.goModules)
+		r.importPathCache.Store(cacheKey, resolved)
+		return resolved
+	}
+
 	sourceDir := filepath.Dir(sourceFile)
 	moduleRoot := r.findModuleRootCached(sourceDir)
 	if moduleRoot == "" {
@@ -208,6 +314,11 @@ func (r *ProjectImportResolver) resolveImportPath(sourceFile, importPath string)
 		return resolved
 	}
 
+	if workspacePath := resolveViaWorkspace(importPath, r.getWorkspaceModulesCached(moduleRoot)); workspacePath != "" {
+		r.importPathCache.Store(cacheKey, workspacePath)
+		return workspacePath
+	}
+
 	if replacedPath := resolveViaReplace(importPath, moduleInfo.replacePaths); replacedPath != "" {
 		r.importPathCache.Store(cacheKey, replacedPath)
 		return replacedPath
@@ -217,6 +328,16 @@ func (r *ProjectImportResolver) resolveImportPath(sourceFile, importPath string)
 	return ""
 }
 
+func (r *ProjectImportResolver) getWorkspaceModulesCached(moduleRoot string) []goWorkspaceModule {
+	if cached, ok := r.workspaceCache.Load(moduleRoot); ok {
+		return cached.([]goWorkspaceModule)
+	}
+
+	modules := findGoWorkspaceModules(moduleRoot, r.contentReader)
+	r.workspaceCache.Store(moduleRoot, modules)
+	return modules
+}
+
 func (r *ProjectImportResolver) findModuleRootCached(startDir string) string {
 	if cached, ok := r.moduleRootCache.Load(startDir); ok {
 		return cached.(string)
@@ -235,7 +356,7 @@ func (r *ProjectImportResolver) findModuleRootCached(startDir string) string {
 		}
 
 		goModPath := filepath.Join(dir, "go.mod")
-		if _, err := r.contentReader(goModPath); err == nil {
+		if r.contentReader.Exists(goModPath) {
 			for _, path := range visited {
 				r.moduleRootCache.Store(path, dir)
 			}
@@ -267,7 +388,22 @@ func (r *ProjectImportResolver) getModuleInfoCached(moduleRoot string) goModuleI
 	return info
 }
 
-func (r *ProjectImportResolver) buildGoPackageExportIndices() map[string]GoPackageExportIndex {
+// buildGoPackageExportIndices indexes the exports of every non-test Go file by package
+// directory. Files are analyzed concurrently up front; the analyses are cached for
+// ResolveProjectImports, and the indices are then assembled in input order.
+func (r *ProjectImportResolver) buildGoPackageExportIndices(parallelism int) map[string]GoPackageExportIndex {
+	var sourceFiles []string
+	for _, files := range r.dirToFiles {
+		for _, filePath := range files {
+			if filepath.Ext(filePath) == ".go" && !strings.HasSuffix(filePath, "_test.go") {
+				sourceFiles = append(sourceFiles, filePath)
+			}
+		}
+	}
+	moduleapi.ParallelFor(len(sourceFiles), parallelism, func(i int) {
+		_, _ = r.getOrAnalyzeFile(sourceFiles[i])
+	})
+
 	goPackageExportIndices := make(map[string]GoPackageExportIndex)
 	for dir, files := range r.dirToFiles {
 		exportIndex := make(GoPackageExportIndex)
@@ -298,18 +434,72 @@ func (r *ProjectImportResolver) getOrAnalyzeFile(filePath string) (*GoFileAnalys
 		}
 	}
 
-	content, err := r.contentReader(filePath)
-	if err != nil {
-		return nil, err
-	}
-	analysis, err := AnalyzeGoFileDetailsFromContent(filePath, content)
+	entry, err := moduleapi.ParseFile(r.parseCache, r.contentReader, analysisCacheKind, filePath, func(content []byte) (goFileAnalysisEntry, error) {
+		analysis, err := AnalyzeGoFileDetailsFromContent(filePath, content)
+		if err != nil {
+			return goFileAnalysisEntry{}, err
+		}
+		return newGoFileAnalysisEntry(analysis), nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	analysis := entry.analysis(filePath)
 	r.analysisCache.Store(filePath, analysis)
 	return analysis, nil
 }
 
+// analysisCacheKind names the goFileAnalysisEntry values ProjectImportResolver caches
+// per file in its ParseCache.
+const analysisCacheKind = "go-file-analysis/v1"
+
+// goFileAnalysisEntry is the cached form of a GoFileAnalysis. Imports are kept as
+// paths and classified again, and the file paths of the symbol and export info are
+// filled in on load, because files with the same content share an entry.
+type goFileAnalysisEntry struct {
+	ImportPaths  []string      `json:"importPaths,omitempty"`
+	Embeds       []GoEmbed     `json:"embeds,omitempty"`
+	SymbolInfo   *GoSymbolInfo `json:"symbolInfo,omitempty"`
+	ExportInfo   *GoExportInfo `json:"exportInfo,omitempty"`
+	CgoIncludes  []string      `json:"cgoIncludes,omitempty"`
+	GenerateArgs []string      `json:"generateArgs,omitempty"`
+}
+
+func newGoFileAnalysisEntry(analysis *GoFileAnalysis) goFileAnalysisEntry {
+	entry := goFileAnalysisEntry{
+		Embeds:       analysis.Embeds,
+		SymbolInfo:   analysis.SymbolInfo,
+		ExportInfo:   analysis.ExportInfo,
+		CgoIncludes:  analysis.CgoIncludes,
+		GenerateArgs: analysis.GenerateArgs,
+	}
+	for _, imp := range analysis.Imports {
+		entry.ImportPaths = append(entry.ImportPaths, imp.Path())
+	}
+	return entry
+}
+
+func (e goFileAnalysisEntry) analysis(filePath string) *GoFileAnalysis {
+	analysis := &GoFileAnalysis{
+		Imports:      make([]GoImport, 0, len(e.ImportPaths)),
+		Embeds:       e.Embeds,
+		SymbolInfo:   e.SymbolInfo,
+		ExportInfo:   e.ExportInfo,
+		CgoIncludes:  e.CgoIncludes,
+		GenerateArgs: e.GenerateArgs,
+	}
+	for _, importPath := range e.ImportPaths {
+		analysis.Imports = append(analysis.Imports, classifyGoImport(importPath))
+	}
+	if analysis.SymbolInfo != nil {
+		analysis.SymbolInfo.FilePath = filePath
+	}
+	if analysis.ExportInfo != nil {
+		analysis.ExportInfo.FilePath = filePath
+	}
+	return analysis
+}
+
 func (r *ProjectImportResolver) getSymbolInfo(filePath string) (*GoSymbolInfo, bool) {
 	cached, ok := r.analysisCache.Load(filePath)
 	if !ok {
@@ -322,18 +512,19 @@ func (r *ProjectImportResolver) getSymbolInfo(filePath string) (*GoSymbolInfo, b
 	return analysis.SymbolInfo, true
 }
 
-func fileDefinesAnyUsedSymbol(depFile string, usedSymbols map[string]bool, exportIndex GoPackageExportIndex) bool {
+// usedSymbolsDefinedIn returns the used symbols that depFile defines.
+func usedSymbolsDefinedIn(depFile string, usedSymbols map[string]bool, exportIndex GoPackageExportIndex) []string {
+	var defined []string
 	for symbol := range usedSymbols {
-		if definingFiles, ok := exportIndex[symbol]; ok {
-			for _, defFile := range definingFiles {
-				if defFile == depFile {
-					return true
-				}
+		for _, defFile := range exportIndex[symbol] {
+			if defFile == depFile {
+				defined = append(defined, symbol)
+				break
 			}
 		}
 	}
 
-	return false
+	return defined
 }
 
 // resolveGoImportPath resolves a Go import path to an absolute file path
@@ -367,6 +558,12 @@ func resolveGoImportPath(sourceFile, importPath string, contentReader vcs.Conten
 		return filepath.Clean(absPath)
 	}
 
+	// Modules used by a go.work file above the module come before go.mod replace
+	// directives, as they do for the go command.
+	if workspacePath := resolveViaWorkspace(importPath, findGoWorkspaceModules(moduleRoot, contentReader)); workspacePath != "" {
+		return workspacePath
+	}
+
 	// Check go.mod replace directives for local replacement targets.
 	replacedPath := resolveViaReplace(importPath, replacePaths)
 	if replacedPath != "" {
@@ -382,7 +579,7 @@ func findModuleRootWithReader(startDir string, contentReader vcs.ContentReader)
 	dir := startDir
 	for {
 		goModPath := filepath.Join(dir, "go.mod")
-		if _, err := contentReader(goModPath); err == nil {
+		if contentReader.Exists(goModPath) {
 			return dir
 		}
 
@@ -398,7 +595,7 @@ func findModuleRootWithReader(startDir string, contentReader vcs.ContentReader)
 // getModuleInfo reads module metadata from go.mod using the content reader.
 func getModuleInfo(moduleRoot string, contentReader vcs.ContentReader) (string, map[string]string) {
 	goModPath := filepath.Join(moduleRoot, "go.mod")
-	content, err := contentReader(goModPath)
+	content, err := contentReader.ReadFile(goModPath)
 	if err != nil {
 		return "", make(map[string]string)
 	}
@@ -531,3 +728,27 @@ func resolveGoEmbedPaths(sourceFile, pattern string, suppliedFiles map[string]bo
 
 	return matches
 }
+
+// PackageImportPath returns the import path of the package containing filePath, as
+// "go test" expects it, from the nearest go.mod. It reports false when the file is
+// not inside a module.
+func PackageImportPath(filePath string, contentReader vcs.ContentReader) (string, bool) {
+	dir := filepath.Dir(filePath)
+	moduleRoot := findModuleRootWithReader(dir, contentReader)
+	if moduleRoot == "" {
+		return "", false
+	}
+	moduleName, _ := getModuleInfo(moduleRoot, contentReader)
+	if moduleName == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(moduleRoot, dir)
+	if err != nil {
+		return "", false
+	}
+	if rel == "." {
+		return moduleName, true
+	}
+	return moduleName + "/" + filepath.ToSlash(rel), true
+}
@@ -3,24 +3,17 @@ package golang_test
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/depgraph/languages/golang"
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func mapContentReader(contents map[string]string) vcs.ContentReader {
-	return func(filePath string) ([]byte, error) {
-		content, ok := contents[filePath]
-		if !ok {
-			return nil, os.ErrNotExist
-		}
-		return []byte(content), nil
-	}
-}
-
 func mustAdjacency(t *testing.T, g depgraph.DependencyGraph) map[string][]string {
 	t.Helper()
 	adj, err := depgraph.AdjacencyList(g)
@@ -64,12 +57,80 @@ var templates string
 	assert.Contains(t, mainDeps, aboutPath)
 }
 
+func TestBuildDependencyGraph_GoCgoPreambleIncludesLocalHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module cgoinclude\n\ngo 1.25\n"), 0644))
+
+	wrapperPath := filepath.Join(tmpDir, "wrapper.go")
+	wrapperContent := `package codec
+
+/*
+#cgo CFLAGS: -O2
+#include <stdlib.h>
+#include "codec.h"
+# include "internal/tables.h"
+*/
+import "C"
+
+func Encode() { C.encode() }
+`
+	require.NoError(t, os.WriteFile(wrapperPath, []byte(wrapperContent), 0644))
+
+	headerPath := filepath.Join(tmpDir, "codec.h")
+	require.NoError(t, os.WriteFile(headerPath, []byte("void encode(void);\n"), 0644))
+	sourcePath := filepath.Join(tmpDir, "codec.c")
+	require.NoError(t, os.WriteFile(sourcePath, []byte("#include \"codec.h\"\nvoid encode(void) {}\n"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "internal"), 0755))
+	tablesPath := filepath.Join(tmpDir, "internal", "tables.h")
+	require.NoError(t, os.WriteFile(tablesPath, []byte("extern int table[];\n"), 0644))
+
+	files := []string{wrapperPath, headerPath, sourcePath, tablesPath}
+	graph, err := depgraph.BuildDependencyGraph(files, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.ElementsMatch(t, []string{headerPath, tablesPath}, adj[wrapperPath])
+	assert.Contains(t, adj[sourcePath], headerPath)
+}
+
+func TestBuildDependencyGraph_GoGenerateReferencesSiblingTool(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module gogenerate\n\ngo 1.25\n"), 0644))
+
+	modelsPath := filepath.Join(tmpDir, "models.go")
+	modelsContent := `package models
+
+//go:generate go run gen.go -template=model.tmpl -out models_gen.go
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Kind
+
+type Kind int
+`
+	require.NoError(t, os.WriteFile(modelsPath, []byte(modelsContent), 0644))
+
+	genPath := filepath.Join(tmpDir, "gen.go")
+	genContent := `//go:build ignore
+
+package main
+
+func main() {}
+`
+	require.NoError(t, os.WriteFile(genPath, []byte(genContent), 0644))
+
+	files := []string{modelsPath, genPath}
+	graph, err := depgraph.BuildDependencyGraph(files, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.Equal(t, []string{genPath}, adj[modelsPath])
+	assert.Empty(t, adj[genPath])
+}
+
 func TestBuildDependencyGraph_GoVirtualReaderResolvesModuleRoot(t *testing.T) {
 	mainPath := filepath.Clean("/virtual/main.go")
 	libPath := filepath.Clean("/virtual/pkg/lib.go")
 	goModPath := filepath.Clean("/virtual/go.mod")
 
-	reader := mapContentReader(map[string]string{
+	reader := testhelpers.MapContentReader(map[string]string{
 		goModPath: "module virtualmod\n\ngo 1.25\n",
 		mainPath: `package main
 
@@ -142,6 +203,35 @@ func Version() string {
 	assert.Contains(t, mainDeps, sharedPath)
 }
 
+func TestBuildDependencyGraph_GoWorkLinksModulesInSeparateRepositories(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(relPath, content string) string {
+		path := filepath.Join(tmpDir, filepath.FromSlash(relPath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	write("go.work", "go 1.25\n\nuse (\n\t./api // the service\n\t./shared\n)\n")
+	write("api/go.mod", "module example.com/api\n\ngo 1.25\n\nrequire example.com/shared v0.1.0\n")
+	write("shared/go.mod", "module example.com/shared\n\ngo 1.25\n")
+	handlerPath := write("api/handler/handler.go", `package handler
+
+import "example.com/shared/money"
+
+func Total() money.Amount { return money.Amount(1) }
+`)
+	moneyPath := write("shared/money/money.go", "package money\n\ntype Amount int\n")
+	unrelatedPath := write("shared/version.go", "package shared\n\nconst Version = 1\n")
+
+	graph, err := depgraph.BuildDependencyGraph([]string{handlerPath, moneyPath, unrelatedPath}, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.Equal(t, []string{moneyPath}, adj[handlerPath], "go.work should resolve the import into the other module")
+	assert.Empty(t, adj[moneyPath])
+}
+
 func TestBuildDependencyGraph_GoDotImportResolvesUsedSymbolsOnly(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -187,4 +277,265 @@ func main() {
 	assert.Len(t, mainDeps, 1, "dot import should link only symbols actually used")
 	assert.Contains(t, mainDeps, fooPath)
 	assert.NotContains(t, mainDeps, barPath)
+
+	symbols, err := depgraph.EdgeSymbols(graph)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Foo"}, symbols[depgraph.FileEdge{From: mainPath, To: fooPath}])
+}
+
+// writeTwoPackageModule writes module twopkg, whose models package defines User in
+// user.go and Order in order.go, plus main.go with mainContent. It returns the paths
+// of main.go, user.go and order.go.
+func writeTwoPackageModule(t *testing.T, mainContent string) (string, string, string) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module twopkg\n\ngo 1.25\n"), 0644))
+	modelsDir := filepath.Join(tmpDir, "models")
+	require.NoError(t, os.Mkdir(modelsDir, 0755))
+
+	userPath := filepath.Join(modelsDir, "user.go")
+	require.NoError(t, os.WriteFile(userPath, []byte("package models\n\ntype User struct{ Name string }\n"), 0644))
+	orderPath := filepath.Join(modelsDir, "order.go")
+	require.NoError(t, os.WriteFile(orderPath, []byte("package models\n\ntype Order struct{ ID int }\n"), 0644))
+	mainPath := filepath.Join(tmpDir, "main.go")
+	require.NoError(t, os.WriteFile(mainPath, []byte(mainContent), 0644))
+
+	return mainPath, userPath, orderPath
+}
+
+func buildTwoPackageGraph(t *testing.T, mainPath, userPath, orderPath string) depgraph.DependencyGraph {
+	t.Helper()
+
+	graph, err := depgraph.BuildDependencyGraph([]string{mainPath, userPath, orderPath}, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+	return graph
+}
+
+func TestBuildDependencyGraph_GoAliasedImportResolvesUsedSymbolsOnly(t *testing.T) {
+	mainPath, userPath, orderPath := writeTwoPackageModule(t, `package main
+
+import m "twopkg/models"
+
+func main() {
+	_ = m.User{}
+}
+`)
+
+	graph := buildTwoPackageGraph(t, mainPath, userPath, orderPath)
+
+	assert.Equal(t, []string{userPath}, mustAdjacency(t, graph)[mainPath])
+	symbols, err := depgraph.EdgeSymbols(graph)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"User"}, symbols[depgraph.FileEdge{From: mainPath, To: userPath}])
+}
+
+func TestBuildDependencyGraph_GoAliasShadowedByLocalIsNotAnImportUse(t *testing.T) {
+	mainPath, userPath, orderPath := writeTwoPackageModule(t, `package main
+
+import m "twopkg/models"
+
+func main() {
+	_ = m.User{}
+	build := func() { m := struct{ Order int }{}; _ = m.Order }
+	build()
+}
+`)
+
+	graph := buildTwoPackageGraph(t, mainPath, userPath, orderPath)
+
+	assert.Equal(t, []string{userPath}, mustAdjacency(t, graph)[mainPath])
+}
+
+func TestBuildDependencyGraph_GoDotImportIgnoresFieldNames(t *testing.T) {
+	mainPath, userPath, orderPath := writeTwoPackageModule(t, `package main
+
+import . "twopkg/models"
+
+type Report struct{ User string }
+
+func main() {
+	r := Report{}
+	_ = r.User
+	_ = Order{}
+}
+`)
+
+	graph := buildTwoPackageGraph(t, mainPath, userPath, orderPath)
+
+	assert.Equal(t, []string{orderPath}, mustAdjacency(t, graph)[mainPath])
+}
+
+func TestBuildDependencyGraph_GoDotImportFallsBackToPackageEdgesWhenUnattributed(t *testing.T) {
+	mainPath, userPath, orderPath := writeTwoPackageModule(t, `package main
+
+import . "twopkg/models"
+
+func main() {
+	run()
+}
+`)
+
+	graph := buildTwoPackageGraph(t, mainPath, userPath, orderPath)
+
+	assert.ElementsMatch(t, []string{userPath, orderPath}, mustAdjacency(t, graph)[mainPath])
+}
+
+func TestBuildDependencyGraph_GoBlankImportKeepsPackageEdges(t *testing.T) {
+	mainPath, userPath, orderPath := writeTwoPackageModule(t, `package main
+
+import _ "twopkg/models"
+
+func main() {}
+`)
+
+	graph := buildTwoPackageGraph(t, mainPath, userPath, orderPath)
+
+	assert.ElementsMatch(t, []string{userPath, orderPath}, mustAdjacency(t, graph)[mainPath])
+}
+
+func TestBuildDependencyGraph_GoUnicodeExportedIdentifiers(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	goModPath := filepath.Join(tmpDir, "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte("module unimod\n\ngo 1.25\n"), 0644))
+
+	pkgDir := filepath.Join(tmpDir, "größe")
+	require.NoError(t, os.Mkdir(pkgDir, 0755))
+
+	unitsPath := filepath.Join(pkgDir, "units.go")
+	require.NoError(t, os.WriteFile(unitsPath, []byte(`package größe
+
+type Ünits struct{}
+`), 0644))
+
+	otherPath := filepath.Join(pkgDir, "other.go")
+	require.NoError(t, os.WriteFile(otherPath, []byte(`package größe
+
+func Other() {}
+`), 0644))
+
+	mainPath := filepath.Join(tmpDir, "main.go")
+	require.NoError(t, os.WriteFile(mainPath, []byte(`package main
+
+import . "unimod/größe"
+
+func main() {
+	_ = Ünits{}
+}
+`), 0644))
+
+	files := []string{mainPath, unitsPath, otherPath}
+	graph, err := depgraph.BuildDependencyGraph(files, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.Equal(t, []string{unitsPath}, adj[mainPath])
+}
+
+func TestBuildDependencyGraph_GoModuleMappingResolvesImportsWithoutGoMod(t *testing.T) {
+	mainPath := filepath.Clean("/mono/cmd/app/main.go")
+	storePath := filepath.Clean("/mono/store/store.go")
+	vendoredPath := filepath.Clean("/third_party/lib/lib.go")
+
+	reader := testhelpers.MapContentReader(map[string]string{
+		mainPath: `package main
+
+import (
+	"github.com/acme/monorepo/store"
+	"github.com/acme/monorepo/vendored/lib"
+)
+
+func main() {
+	_ = store.Open()
+	_ = lib.Version()
+}
+`,
+		storePath:    "package store\n\nfunc Open() string { return \"ok\" }\n",
+		vendoredPath: "package lib\n\nfunc Version() string { return \"v1\" }\n",
+	})
+
+	files := []string{mainPath, storePath, vendoredPath}
+	graph, _, err := depgraph.BuildDependencyGraphWithOptions(files, reader, depgraph.BuildOptions{
+		GoModules: map[string]string{
+			"github.com/acme/monorepo":              filepath.Clean("/mono"),
+			"github.com/acme/monorepo/vendored/lib": filepath.Clean("/third_party/lib"),
+		},
+	})
+	require.NoError(t, err)
+
+	adj := mustAdjacency(t, graph)
+	assert.ElementsMatch(t, []string{storePath, vendoredPath}, adj[mainPath], "the longest mapped prefix should win")
+}
+
+func TestBuildDependencyGraph_GoModuleMappingSkipsGoModReads(t *testing.T) {
+	mainPath := filepath.Clean("/repo/main.go")
+	libPath := filepath.Clean("/repo/pkg/lib.go")
+
+	reader := &goModRecordingReader{ContentReader: testhelpers.MapContentReader(map[string]string{
+		filepath.Clean("/repo/go.mod"): "module unrelated\n",
+		mainPath:                       "package main\n\nimport \"example.com/repo/pkg\"\n\nfunc main() { _ = pkg.Helper() }\n",
+		libPath:                        "package pkg\n\nfunc Helper() string { return \"ok\" }\n",
+	})}
+
+	graph, _, err := depgraph.BuildDependencyGraphWithOptions([]string{mainPath, libPath}, reader, depgraph.BuildOptions{
+		GoModules: map[string]string{"example.com/repo": filepath.Clean("/repo")},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{libPath}, mustAdjacency(t, graph)[mainPath])
+	assert.Empty(t, reader.goModPaths, "go.mod files should not be read when a module mapping is given")
+}
+
+// goModRecordingReader records the go.mod and go.work paths looked up through it.
+type goModRecordingReader struct {
+	vcs.ContentReader
+	mu         sync.Mutex
+	goModPaths []string
+}
+
+func (r *goModRecordingReader) ReadFile(filePath string) ([]byte, error) {
+	r.record(filePath)
+	return r.ContentReader.ReadFile(filePath)
+}
+
+func (r *goModRecordingReader) Exists(filePath string) bool {
+	r.record(filePath)
+	return r.ContentReader.Exists(filePath)
+}
+
+func (r *goModRecordingReader) record(filePath string) {
+	if base := filepath.Base(filePath); base == "go.mod" || base == "go.work" {
+		r.mu.Lock()
+		r.goModPaths = append(r.goModPaths, filePath)
+		r.mu.Unlock()
+	}
+}
+
+func TestPackageImportPath(t *testing.T) {
+	reader := testhelpers.MapContentReader(map[string]string{
+		filepath.Clean("/repo/go.mod"):                  "module example.com/app\n\ngo 1.25\n",
+		filepath.Clean("/repo/main_test.go"):            "package main\n",
+		filepath.Clean("/repo/internal/db/db_test.go"):  "package db_test\n",
+		filepath.Clean("/repo/tools/go.mod"):            "module example.com/tools\n",
+		filepath.Clean("/repo/tools/lint/lint_test.go"): "package lint\n",
+		filepath.Clean("/elsewhere/x_test.go"):          "package x\n",
+	})
+
+	tests := []struct {
+		file   string
+		want   string
+		wantOK bool
+	}{
+		{file: "/repo/main_test.go", want: "example.com/app", wantOK: true},
+		{file: "/repo/internal/db/db_test.go", want: "example.com/app/internal/db", wantOK: true},
+		{file: "/repo/tools/lint/lint_test.go", want: "example.com/tools/lint", wantOK: true},
+		{file: "/elsewhere/x_test.go", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := golang.PackageImportPath(filepath.Clean(tt.file), reader)
+		assert.Equal(t, tt.wantOK, ok, tt.file)
+		assert.Equal(t, tt.want, got, tt.file)
+	}
 }
@@ -23,10 +23,15 @@ func (Module) Maturity() moduleapi.MaturityLevel {
 }
 
 func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReader) moduleapi.Resolver {
+	projectResolver := newProjectImportResolver(ctx.DirToFiles, ctx.SuppliedFiles, contentReader, ctx.Parallelism, ctx.ParseCache)
+	projectResolver.edgeSymbols = ctx.EdgeSymbols
+	projectResolver.externalImports = ctx.ExternalImports
+	projectResolver.goModules = ctx.GoModules
+	projectResolver.pathAliases = ctx.PathAliases
 	return resolver{
 		ctx:             ctx,
 		contentReader:   contentReader,
-		projectResolver: NewProjectImportResolver(ctx.DirToFiles, ctx.SuppliedFiles, contentReader),
+		projectResolver: projectResolver,
 	}
 }
 
@@ -45,7 +50,7 @@ func (r resolver) ResolveProjectImports(absPath, filePath, _ string) ([]string,
 }
 
 func (r resolver) FinalizeGraph(graph moduleapi.Graph) error {
-	return addGoIntraPackageDependencies(graph, r.ctx.GoFiles, r.contentReader, r.projectResolver)
+	return addGoIntraPackageDependencies(graph, r.ctx.GoFiles, r.contentReader, r.projectResolver, r.ctx.EdgeSymbols, r.ctx.Parallelism, r.ctx.Lenient)
 }
 
 func addGoIntraPackageDependencies(
@@ -53,6 +58,9 @@ func addGoIntraPackageDependencies(
 	goFiles []string,
 	contentReader vcs.ContentReader,
 	projectResolver *ProjectImportResolver,
+	edgeSymbols *moduleapi.EdgeSymbols,
+	parallelism int,
+	lenient bool,
 ) error {
 	if len(goFiles) == 0 {
 		return nil
@@ -63,7 +71,7 @@ func addGoIntraPackageDependencies(
 		symbolLookup = projectResolver.getSymbolInfo
 	}
 
-	intraDeps, err := BuildIntraPackageDependenciesWithSymbolLookup(goFiles, vcs.ContentReader(contentReader), symbolLookup)
+	intraDeps, err := buildIntraPackageDependencies(goFiles, contentReader, symbolLookup, edgeSymbols, parallelism, lenient)
 	if err != nil {
 		return err
 	}
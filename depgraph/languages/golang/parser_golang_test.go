@@ -422,3 +422,37 @@ func main() {
 	require.NoError(t, err)
 	assert.Empty(t, embeds)
 }
+
+func TestExtractGoExportInfo_TracksImportLocalNames(t *testing.T) {
+	info, err := ExtractGoExportInfoFromContent("main.go", []byte(`package main
+
+import (
+	m "example.com/app/models"
+	. "example.com/app/views"
+	_ "example.com/app/drivers"
+	"example.com/app/store/v2"
+	"gopkg.in/yaml.v3"
+	"github.com/mattn/go-sqlite3"
+)
+
+func main() {
+	_ = m.User{}
+	_ = store.Open()
+	_ = Render()
+}
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"example.com/app/models":      "m",
+		"example.com/app/store/v2":    "store",
+		"gopkg.in/yaml.v3":            "yaml",
+		"github.com/mattn/go-sqlite3": "sqlite3",
+	}, info.ImportAliases)
+	assert.Equal(t, map[string]bool{"example.com/app/views": true}, info.DotImports)
+	assert.Equal(t, map[string]bool{"example.com/app/drivers": true}, info.BlankImports)
+	assert.Equal(t, map[string]bool{"User": true}, GetUsedSymbolsFromPackage(info, "example.com/app/models"))
+	assert.Equal(t, map[string]bool{"Open": true}, GetUsedSymbolsFromPackage(info, "example.com/app/store/v2"))
+	assert.True(t, GetUsedSymbolsFromPackage(info, "example.com/app/views")["Render"])
+	assert.Nil(t, GetUsedSymbolsFromPackage(info, "example.com/app/drivers"))
+}
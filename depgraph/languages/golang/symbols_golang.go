@@ -1,14 +1,19 @@
 package golang
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"log/slog"
+	"path"
 	"path/filepath"
-	"runtime"
+	"regexp"
 	"strings"
 	"sync"
+	"unicode"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
@@ -18,6 +23,8 @@ type GoSymbolInfo struct {
 	Package    string
 	Defined    map[string]bool // Symbols defined in this file
 	Referenced map[string]bool // Symbols referenced in this file
+	// ReferenceCounts counts the identifiers naming each symbol of Referenced.
+	ReferenceCounts map[string]int
 }
 
 // GoExportInfo tracks exported symbols and import usage in a Go file
@@ -25,10 +32,15 @@ type GoExportInfo struct {
 	FilePath      string
 	Package       string
 	Exports       map[string]bool            // Exported symbols (capitalized) defined in this file
-	ImportAliases map[string]string          // Maps import path to alias used (or package name if no alias)
+	ImportAliases map[string]string          // Maps import path to the local name it binds (alias, or assumed package name)
 	DotImports    map[string]bool            // Tracks import paths imported via dot import
+	BlankImports  map[string]bool            // Tracks import paths imported only for their side effects
 	QualifiedRefs map[string]map[string]bool // Maps package alias -> set of symbols accessed
 	UnqualRefs    map[string]bool            // Unqualified refs, used for dot-import symbol filtering
+	// QualifiedRefCounts and UnqualRefCounts count the references of QualifiedRefs and
+	// UnqualRefs.
+	QualifiedRefCounts map[string]map[string]int
+	UnqualRefCounts    map[string]int
 }
 
 // GoPackageExportIndex maps exported symbols to their defining files within a package directory
@@ -40,6 +52,11 @@ type GoFileAnalysis struct {
 	Embeds     []GoEmbed
 	SymbolInfo *GoSymbolInfo
 	ExportInfo *GoExportInfo
+	// CgoIncludes are the quoted #include paths in the preamble of import "C".
+	CgoIncludes []string
+	// GenerateArgs are the words of //go:generate directives, any of which may name a
+	// local file such as the generator's source.
+	GenerateArgs []string
 }
 
 // AnalyzeGoFileFromContent parses a Go file once and extracts import paths,
@@ -68,9 +85,14 @@ func AnalyzeGoFileDetailsFromContent(filePath string, content []byte) (*GoFileAn
 	}
 
 	var embeds []GoEmbed
+	var generateArgs []string
 	for _, group := range node.Comments {
 		for _, comment := range group.List {
 			content := strings.TrimSpace(comment.Text)
+			if args, ok := strings.CutPrefix(content, "//go:generate "); ok {
+				generateArgs = append(generateArgs, strings.Fields(args)...)
+				continue
+			}
 			if !strings.HasPrefix(content, "//go:embed ") {
 				continue
 			}
@@ -93,13 +115,50 @@ func AnalyzeGoFileDetailsFromContent(filePath string, content []byte) (*GoFileAn
 	}
 
 	return &GoFileAnalysis{
-		Imports:    imports,
-		Embeds:     embeds,
-		SymbolInfo: symbolInfo,
-		ExportInfo: exportInfo,
+		Imports:      imports,
+		Embeds:       embeds,
+		SymbolInfo:   symbolInfo,
+		ExportInfo:   exportInfo,
+		CgoIncludes:  cgoIncludes(node),
+		GenerateArgs: generateArgs,
 	}, nil
 }
 
+// cgoIncludeRE matches a quoted #include line; angle-bracket includes name system
+// headers and are not project files.
+var cgoIncludeRE = regexp.MustCompile(`^\s*#\s*include\s*"([^"]+)"`)
+
+// cgoIncludes returns the quoted #include paths in the cgo preamble, the comment
+// directly above import "C".
+func cgoIncludes(node *ast.File) []string {
+	var includes []string
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			importSpec, ok := spec.(*ast.ImportSpec)
+			if !ok || importSpec.Path.Value != `"C"` {
+				continue
+			}
+			preamble := importSpec.Doc
+			if preamble == nil && !genDecl.Lparen.IsValid() {
+				preamble = genDecl.Doc
+			}
+			if preamble == nil {
+				continue
+			}
+			for _, line := range strings.Split(preamble.Text(), "\n") {
+				if match := cgoIncludeRE.FindStringSubmatch(line); match != nil {
+					includes = append(includes, match[1])
+				}
+			}
+		}
+	}
+	return includes
+}
+
 // ExtractGoSymbols analyzes a Go file and extracts defined and referenced symbols
 func ExtractGoSymbols(filePath string) (*GoSymbolInfo, error) {
 	fset := token.NewFileSet()
@@ -126,10 +185,11 @@ func ExtractGoSymbolsFromContent(filePath string, content []byte) (*GoSymbolInfo
 func extractSymbolsFromAST(filePath string, node *ast.File) (*GoSymbolInfo, error) {
 
 	info := &GoSymbolInfo{
-		FilePath:   filePath,
-		Package:    node.Name.Name,
-		Defined:    make(map[string]bool),
-		Referenced: make(map[string]bool),
+		FilePath:        filePath,
+		Package:         node.Name.Name,
+		Defined:         make(map[string]bool),
+		Referenced:      make(map[string]bool),
+		ReferenceCounts: make(map[string]int),
 	}
 
 	// Extract defined symbols (top-level declarations)
@@ -190,6 +250,7 @@ func extractSymbolsFromAST(filePath string, node *ast.File) (*GoSymbolInfo, erro
 			// 5. Are not already defined in this file (checked via x.Obj == nil)
 			if x.Obj == nil && x.Name != "_" && x.Name != info.Package && !builtins[x.Name] {
 				info.Referenced[x.Name] = true
+				info.ReferenceCounts[x.Name]++
 			}
 		case *ast.SelectorExpr:
 			// For qualified identifiers like fmt.Println, we only care about
@@ -232,38 +293,35 @@ func ExtractGoExportInfoFromContent(filePath string, content []byte) (*GoExportI
 // extractExportInfoFromAST extracts export information from a parsed AST
 func extractExportInfoFromAST(filePath string, node *ast.File) (*GoExportInfo, error) {
 	info := &GoExportInfo{
-		FilePath:      filePath,
-		Package:       node.Name.Name,
-		Exports:       make(map[string]bool),
-		ImportAliases: make(map[string]string),
-		DotImports:    make(map[string]bool),
-		QualifiedRefs: make(map[string]map[string]bool),
-		UnqualRefs:    make(map[string]bool),
+		FilePath:           filePath,
+		Package:            node.Name.Name,
+		Exports:            make(map[string]bool),
+		ImportAliases:      make(map[string]string),
+		DotImports:         make(map[string]bool),
+		BlankImports:       make(map[string]bool),
+		QualifiedRefs:      make(map[string]map[string]bool),
+		UnqualRefs:         make(map[string]bool),
+		QualifiedRefCounts: make(map[string]map[string]int),
+		UnqualRefCounts:    make(map[string]int),
 	}
 
 	// Extract import aliases
 	for _, imp := range node.Imports {
 		importPath := strings.Trim(imp.Path.Value, "\"")
 
-		// Determine the alias (explicit or derived from package path)
-		var alias string
+		// Determine the local name the import binds (explicit or derived from package path)
+		alias := goImportName(importPath)
 		if imp.Name != nil {
 			alias = imp.Name.Name
-			if alias == "." {
-				info.DotImports[importPath] = true
-				continue
-			}
-			if alias == "_" {
-				// Dot imports or blank imports - skip for now
-				continue
-			}
-		} else {
-			// Use last component of import path as alias
-			parts := strings.Split(importPath, "/")
-			alias = parts[len(parts)-1]
 		}
-
-		info.ImportAliases[importPath] = alias
+		switch alias {
+		case ".":
+			info.DotImports[importPath] = true
+		case "_":
+			info.BlankImports[importPath] = true
+		default:
+			info.ImportAliases[importPath] = alias
+		}
 	}
 
 	// Extract exported symbols (capitalized top-level declarations)
@@ -306,16 +364,18 @@ func extractExportInfoFromAST(filePath string, node *ast.File) (*GoExportInfo, e
 	// Extract qualified references (e.g., formatters.NewFormatter)
 	ast.Inspect(node, func(n ast.Node) bool {
 		if sel, ok := n.(*ast.SelectorExpr); ok {
-			// Check if the X is an identifier (package alias)
-			if ident, ok := sel.X.(*ast.Ident); ok {
+			// Check if the X is an identifier (package alias) not shadowed by a local
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
 				alias := ident.Name
 				// Check if this alias is an imported package (not a local variable)
 				if _, isImport := aliasToPath[alias]; isImport {
 					// This is a qualified reference to an imported package
 					if info.QualifiedRefs[alias] == nil {
 						info.QualifiedRefs[alias] = make(map[string]bool)
+						info.QualifiedRefCounts[alias] = make(map[string]int)
 					}
 					info.QualifiedRefs[alias][sel.Sel.Name] = true
+					info.QualifiedRefCounts[alias][sel.Sel.Name]++
 				}
 			}
 		}
@@ -336,10 +396,15 @@ func extractExportInfoFromAST(filePath string, node *ast.File) (*GoExportInfo, e
 		"init": true, "main": true,
 	}
 
-	// Extract unqualified references used for dot-import resolution.
+	// Extract unqualified references used for dot-import resolution. Field and method
+	// names after a selector are never package-level references.
+	selected := make(map[*ast.Ident]bool)
 	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			selected[sel.Sel] = true
+		}
 		ident, ok := n.(*ast.Ident)
-		if !ok {
+		if !ok || selected[ident] {
 			return true
 		}
 		if ident.Obj != nil {
@@ -349,6 +414,7 @@ func extractExportInfoFromAST(filePath string, node *ast.File) (*GoExportInfo, e
 			return true
 		}
 		info.UnqualRefs[ident.Name] = true
+		info.UnqualRefCounts[ident.Name]++
 		return true
 	})
 
@@ -367,7 +433,7 @@ func BuildPackageExportIndex(filePaths []string, contentReader vcs.ContentReader
 			continue
 		}
 
-		content, err := contentReader(filePath)
+		content, err := contentReader.ReadFile(filePath)
 		if err != nil {
 			continue
 		}
@@ -386,7 +452,42 @@ func BuildPackageExportIndex(filePaths []string, contentReader vcs.ContentReader
 	return index, nil
 }
 
-// GetUsedSymbolsFromPackage extracts which symbols from a specific import path are actually used
+// goImportName returns the name an unaliased import is assumed to bind: the last path
+// element, skipping a major version suffix such as /v2 and dropping a go- prefix and
+// anything from the first character that cannot appear in an identifier, as in
+// gopkg.in/yaml.v3 or github.com/mattn/go-sqlite3.
+func goImportName(importPath string) string {
+	base := path.Base(importPath)
+	if isMajorVersionSuffix(base) {
+		if dir := path.Dir(importPath); dir != "." {
+			base = path.Base(dir)
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+func isMajorVersionSuffix(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	for _, r := range element[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GetUsedSymbolsFromPackage extracts which symbols from a specific import path are actually used.
+// For a dot import it returns every unqualified reference, which may include symbols
+// from elsewhere. It returns nil when usage cannot be attributed, as for blank imports,
+// so callers keep package-level edges.
 func GetUsedSymbolsFromPackage(exportInfo *GoExportInfo, importPath string) map[string]bool {
 	if exportInfo.DotImports[importPath] {
 		return exportInfo.UnqualRefs
@@ -400,6 +501,28 @@ func GetUsedSymbolsFromPackage(exportInfo *GoExportInfo, importPath string) map[
 	return exportInfo.QualifiedRefs[alias]
 }
 
+// symbolUsageCounts returns how many times each symbol GetUsedSymbolsFromPackage
+// reports for importPath is referenced.
+func symbolUsageCounts(exportInfo *GoExportInfo, importPath string) map[string]int {
+	if exportInfo.DotImports[importPath] {
+		return exportInfo.UnqualRefCounts
+	}
+	alias, ok := exportInfo.ImportAliases[importPath]
+	if !ok {
+		return nil
+	}
+	return exportInfo.QualifiedRefCounts[alias]
+}
+
+// countUsages sums the usage counts of symbols, counting a symbol without a count once.
+func countUsages(symbols []string, counts map[string]int) int {
+	usages := 0
+	for _, symbol := range symbols {
+		usages += max(counts[symbol], 1)
+	}
+	return usages
+}
+
 // BuildIntraPackageDependencies builds dependencies between files in the same Go package.
 // The contentReader function is used to read file contents, allowing the caller to control
 // whether files are read from the filesystem, a git commit, or another source.
@@ -413,6 +536,19 @@ func BuildIntraPackageDependenciesWithSymbolLookup(
 	filePaths []string,
 	contentReader vcs.ContentReader,
 	symbolLookup func(filePath string) (*GoSymbolInfo, bool),
+) (map[string][]string, error) {
+	return buildIntraPackageDependencies(filePaths, contentReader, symbolLookup, nil, 0, false)
+}
+
+// buildIntraPackageDependencies builds intra-package dependencies and records on
+// edgeSymbols which symbols each file uses from the others.
+func buildIntraPackageDependencies(
+	filePaths []string,
+	contentReader vcs.ContentReader,
+	symbolLookup func(filePath string) (*GoSymbolInfo, bool),
+	edgeSymbols *moduleapi.EdgeSymbols,
+	parallelism int,
+	lenient bool,
 ) (map[string][]string, error) {
 	// Group files by package
 	packageFiles := make(map[string][]string)
@@ -437,42 +573,33 @@ func BuildIntraPackageDependenciesWithSymbolLookup(
 	}
 
 	dependencies := make(map[string][]string)
-	workerCount := runtime.GOMAXPROCS(0)
-	if workerCount < 1 {
-		workerCount = 1
-	}
-	if workerCount > len(packageGroups) {
-		workerCount = len(packageGroups)
-	}
-	if workerCount < 1 {
-		workerCount = 1
-	}
-
-	jobs := make(chan []string)
-	var wg sync.WaitGroup
+	errs := make([]error, len(packageGroups))
 	var mu sync.Mutex
+	moduleapi.ParallelFor(len(packageGroups), parallelism, func(i int) {
+		packageDeps, err := buildPackageDependencies(packageGroups[i], contentReader, symbolLookup, edgeSymbols, lenient)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		mu.Lock()
+		for file, deps := range packageDeps {
+			dependencies[file] = deps
+		}
+		mu.Unlock()
+	})
 
-	for range workerCount {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for files := range jobs {
-				packageDeps := buildPackageDependencies(files, contentReader, symbolLookup)
-				mu.Lock()
-				for file, deps := range packageDeps {
-					dependencies[file] = deps
-				}
-				mu.Unlock()
-			}
-		}()
+	// Report the error of the first package in path order, so the error does not
+	// depend on map order or scheduling.
+	var firstErr error
+	firstPath := ""
+	for i, err := range errs {
+		if err != nil && (firstErr == nil || packageGroups[i][0] < firstPath) {
+			firstErr, firstPath = err, packageGroups[i][0]
+		}
 	}
-
-	for _, files := range packageGroups {
-		jobs <- files
+	if firstErr != nil {
+		return nil, firstErr
 	}
-	close(jobs)
-	wg.Wait()
-
 	return dependencies, nil
 }
 
@@ -480,7 +607,9 @@ func buildPackageDependencies(
 	files []string,
 	contentReader vcs.ContentReader,
 	symbolLookup func(filePath string) (*GoSymbolInfo, bool),
-) map[string][]string {
+	edgeSymbols *moduleapi.EdgeSymbols,
+	lenient bool,
+) (map[string][]string, error) {
 	// Separate test and non-test files.
 	var testFiles, nonTestFiles []*GoSymbolInfo
 
@@ -493,14 +622,20 @@ func buildPackageDependencies(
 			}
 		}
 		if info == nil {
-			content, err := contentReader(file)
+			content, err := contentReader.ReadFile(file)
 			if err != nil {
-				// Skip files that can't be read.
+				if !lenient {
+					return nil, fmt.Errorf("failed to read %s during intra-package analysis: %w", file, err)
+				}
+				slog.Warn("skipping unreadable file in intra-package analysis", "file", file, "error", err)
 				continue
 			}
 			parsed, err := ExtractGoSymbolsFromContent(file, content)
 			if err != nil {
-				// Skip files that can't be parsed.
+				if !lenient {
+					return nil, fmt.Errorf("failed to parse %s during intra-package analysis: %w", file, err)
+				}
+				slog.Warn("skipping unparsable file in intra-package analysis", "file", file, "error", err)
 				continue
 			}
 			info = parsed
@@ -532,40 +667,33 @@ func buildPackageDependencies(
 
 	// For non-test files, only allow dependencies on other non-test files.
 	for _, info := range nonTestFiles {
-		deps := make(map[string]bool)
-		for symbol := range info.Referenced {
-			if definingFiles, ok := nonTestSymbolToFiles[symbol]; ok {
-				for _, defFile := range definingFiles {
-					if defFile != info.FilePath {
-						deps[defFile] = true
-					}
-				}
-			}
-		}
-		dependencies[info.FilePath] = dependencySetToSlice(deps)
+		dependencies[info.FilePath] = resolvePackageSymbols(info, nonTestSymbolToFiles, edgeSymbols)
 	}
 
 	// For test files, allow dependencies on all files (test and non-test).
 	for _, info := range testFiles {
-		deps := make(map[string]bool)
-		for symbol := range info.Referenced {
-			if definingFiles, ok := allSymbolToFiles[symbol]; ok {
-				for _, defFile := range definingFiles {
-					if defFile != info.FilePath {
-						deps[defFile] = true
-					}
-				}
-			}
-		}
-		dependencies[info.FilePath] = dependencySetToSlice(deps)
+		dependencies[info.FilePath] = resolvePackageSymbols(info, allSymbolToFiles, edgeSymbols)
 	}
 
-	return dependencies
+	return dependencies, nil
 }
 
-func dependencySetToSlice(deps map[string]bool) []string {
+// resolvePackageSymbols returns the files defining symbols that info references,
+// recording on edgeSymbols which symbols come from each file.
+func resolvePackageSymbols(info *GoSymbolInfo, symbolToFiles map[string][]string, edgeSymbols *moduleapi.EdgeSymbols) []string {
+	deps := make(map[string][]string)
+	for symbol := range info.Referenced {
+		for _, defFile := range symbolToFiles[symbol] {
+			if defFile != info.FilePath {
+				deps[defFile] = append(deps[defFile], symbol)
+			}
+		}
+	}
+
 	depSlice := make([]string, 0, len(deps))
-	for dep := range deps {
+	for dep, symbols := range deps {
+		edgeSymbols.Record(info.FilePath, dep, symbols...)
+		edgeSymbols.RecordUsages(info.FilePath, dep, countUsages(symbols, info.ReferenceCounts))
 		depSlice = append(depSlice, dep)
 	}
 	return depSlice
@@ -0,0 +1,57 @@
+package golang
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// intraPackageFiles returns a package of two files where main.go uses a helper that
+// helper.go defines, with a reader that serves only main.go.
+func intraPackageFiles() (mainPath, helperPath string, files []string, reader vcs.ContentReader) {
+	mainPath = filepath.Clean("/repo/main.go")
+	helperPath = filepath.Clean("/repo/helper.go")
+	reader = testhelpers.MapContentReader(map[string]string{
+		mainPath: "package main\n\nfunc main() { helper() }\n",
+	})
+	return mainPath, helperPath, []string{mainPath, helperPath}, reader
+}
+
+func TestBuildIntraPackageDependencies_UnreadableFileFailsByDefault(t *testing.T) {
+	_, helperPath, files, reader := intraPackageFiles()
+
+	_, err := buildIntraPackageDependencies(files, reader, nil, nil, 0, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read "+helperPath+" during intra-package analysis")
+}
+
+func TestBuildIntraPackageDependencies_UnparsableFileFailsByDefault(t *testing.T) {
+	mainPath := filepath.Clean("/repo/main.go")
+	brokenPath := filepath.Clean("/repo/broken.go")
+	reader := testhelpers.MapContentReader(map[string]string{
+		mainPath:   "package main\n\nfunc main() {}\n",
+		brokenPath: "package main\n\nfunc broken( {\n",
+	})
+
+	_, err := buildIntraPackageDependencies([]string{mainPath, brokenPath}, reader, nil, nil, 0, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse "+brokenPath+" during intra-package analysis")
+}
+
+func TestBuildIntraPackageDependencies_LenientSkipsUnreadableFile(t *testing.T) {
+	mainPath, helperPath, files, reader := intraPackageFiles()
+
+	deps, err := buildIntraPackageDependencies(files, reader, nil, nil, 0, true)
+
+	require.NoError(t, err)
+	assert.Contains(t, deps, mainPath)
+	assert.Empty(t, deps[mainPath])
+	assert.NotContains(t, deps, helperPath)
+}
@@ -0,0 +1,105 @@
+package golang
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// goWorkspaceModule is a module a go.work file uses.
+type goWorkspaceModule struct {
+	name string
+	root string
+}
+
+// findGoWorkspaceModules returns the modules used by the go.work file nearest to
+// moduleRoot, longest module path first, or nil when there is none. Modules checked
+// out in separate repositories side by side, and linked by a go.work file above them,
+// resolve each other's imports this way.
+func findGoWorkspaceModules(moduleRoot string, contentReader vcs.ContentReader) []goWorkspaceModule {
+	for dir := moduleRoot; ; {
+		goWorkPath := filepath.Join(dir, "go.work")
+		if contentReader.Exists(goWorkPath) {
+			content, err := contentReader.ReadFile(goWorkPath)
+			if err != nil {
+				return nil
+			}
+			return readGoWorkspaceModules(dir, content, contentReader)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// readGoWorkspaceModules reads the module path of each directory the use directives
+// of a go.work file in workDir list. Directories without a readable go.mod are skipped.
+func readGoWorkspaceModules(workDir string, content []byte, contentReader vcs.ContentReader) []goWorkspaceModule {
+	var modules []goWorkspaceModule
+	for _, useDir := range parseGoWorkUses(content) {
+		root := useDir
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(workDir, filepath.FromSlash(root))
+		}
+		root = filepath.Clean(root)
+		name, _ := getModuleInfo(root, contentReader)
+		if name == "" {
+			continue
+		}
+		modules = append(modules, goWorkspaceModule{name: name, root: root})
+	}
+	sort.SliceStable(modules, func(i, j int) bool {
+		return len(modules[i].name) > len(modules[j].name)
+	})
+	return modules
+}
+
+// parseGoWorkUses returns the directories listed by the use directives of a go.work
+// file, in both the single-line and the block form.
+func parseGoWorkUses(content []byte) []string {
+	var uses []string
+	inUseBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inUseBlock && line == ")":
+			inUseBlock = false
+		case inUseBlock:
+			if line != "" {
+				uses = append(uses, strings.Trim(line, "\"`"))
+			}
+		case line == "use (" || line == "use(":
+			inUseBlock = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), "\"`"))
+		}
+	}
+	return uses
+}
+
+// resolveViaWorkspace maps importPath to a package directory in the workspace module
+// whose path it starts with.
+func resolveViaWorkspace(importPath string, modules []goWorkspaceModule) string {
+	for _, module := range modules {
+		if importPath == module.name {
+			return module.root
+		}
+		if suffix, ok := strings.CutPrefix(importPath, module.name+"/"); ok {
+			return filepath.Join(module.root, filepath.FromSlash(suffix))
+		}
+	}
+	return ""
+}
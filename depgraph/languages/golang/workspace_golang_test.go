@@ -0,0 +1,33 @@
+package golang
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+)
+
+func TestParseGoWorkUses(t *testing.T) {
+	content := "go 1.25\n\nuse ./tools\n\nuse (\n\t./api // service\n\t\"../billing\"\n\n)\n\nreplace example.com/x => ./x\n"
+
+	assert.Equal(t, []string{"./tools", "./api", "../billing"}, parseGoWorkUses([]byte(content)))
+}
+
+func TestResolveViaWorkspace_PrefersLongestModulePath(t *testing.T) {
+	root := filepath.Clean("/work")
+	reader := testhelpers.MapContentReader(map[string]string{
+		filepath.Join(root, "app", "go.mod"):       "module example.com/app\n",
+		filepath.Join(root, "app-extra", "go.mod"): "module example.com/app/extra\n",
+		filepath.Join(root, "appendix", "go.mod"):  "module example.com/appendix\n",
+		filepath.Join(root, "missing-mod", "x.go"): "package x\n",
+	})
+	modules := readGoWorkspaceModules(root, []byte("use (\n\t./app\n\t./app-extra\n\t./appendix\n\t./missing-mod\n)\n"), reader)
+
+	assert.Len(t, modules, 3)
+	assert.Equal(t, filepath.Join(root, "app-extra", "money"), resolveViaWorkspace("example.com/app/extra/money", modules))
+	assert.Equal(t, filepath.Join(root, "app", "billing"), resolveViaWorkspace("example.com/app/billing", modules))
+	assert.Equal(t, filepath.Join(root, "appendix"), resolveViaWorkspace("example.com/appendix", modules))
+	assert.Empty(t, resolveViaWorkspace("example.com/other", modules))
+}
@@ -1,16 +1,37 @@
 package java
 
 import (
-	"fmt"
 	"path/filepath"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// fileFactsCacheKind names the javaFileFacts the resolver caches per file.
+const fileFactsCacheKind = "java-file-facts/v1"
+
+// javaFileFacts is what indexing and resolving need from a Java file's content.
+type javaFileFacts struct {
+	Package        string           `json:"package,omitempty"`
+	DeclaredTypes  []string         `json:"declaredTypes,omitempty"`
+	Imports        []javaImportDecl `json:"imports,omitempty"`
+	TypeReferences []string         `json:"typeReferences,omitempty"`
+}
+
+func parseJavaFileFacts(content []byte) (javaFileFacts, error) {
+	return javaFileFacts{
+		Package:        ParsePackageDeclaration(content),
+		DeclaredTypes:  ParseTopLevelTypeNames(content),
+		Imports:        parseJavaImportDecls(content),
+		TypeReferences: ExtractTypeIdentifiers(content),
+	}, nil
+}
+
 // BuildJavaIndices builds package and type indices for supplied Java files.
 func BuildJavaIndices(
 	javaFiles []string,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) (map[string][]string, map[string]map[string][]string, map[string]string) {
 	if len(javaFiles) == 0 {
 		return nil, nil, make(map[string]string)
@@ -26,12 +47,12 @@ func BuildJavaIndices(
 			continue
 		}
 
-		content, err := contentReader(absPath)
+		facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, absPath, parseJavaFileFacts)
 		if err != nil {
 			continue
 		}
 
-		pkg := ParsePackageDeclaration(content)
+		pkg := facts.Package
 		if pkg == "" {
 			continue
 		}
@@ -39,7 +60,7 @@ func BuildJavaIndices(
 
 		packageToFiles[pkg] = append(packageToFiles[pkg], absPath)
 
-		declaredTypes := ParseTopLevelTypeNames(content)
+		declaredTypes := facts.DeclaredTypes
 		if len(declaredTypes) == 0 {
 			continue
 		}
@@ -69,10 +90,11 @@ func ResolveJavaProjectImports(
 	javaFilePackages map[string]string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, absPath, parseJavaFileFacts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+		return nil, err
 	}
 
 	projectPackages := make(map[string]bool, len(javaPackageIndex))
@@ -80,10 +102,10 @@ func ResolveJavaProjectImports(
 		projectPackages[pkg] = true
 	}
 
-	imports := ParseJavaImports(content, projectPackages)
-	typeReferences := ExtractTypeIdentifiers(content)
+	imports := classifyJavaImports(facts.Imports, projectPackages)
+	typeReferences := facts.TypeReferences
 	declaredNames := make(map[string]bool)
-	for _, name := range ParseTopLevelTypeNames(content) {
+	for _, name := range facts.DeclaredTypes {
 		if name != "" {
 			declaredNames[name] = true
 		}
@@ -106,7 +128,7 @@ func ResolveJavaProjectImports(
 
 	samePackageDeps := resolveJavaSamePackageDependencies(
 		absPath,
-		content,
+		facts,
 		javaFilePackages,
 		javaPackageTypes,
 		imports,
@@ -137,6 +159,16 @@ func resolveJavaImportPath(
 		resolved = append(resolved, path)
 	}
 
+	if imp.IsStatic() {
+		// A static import uses members of one type, so it links to the file declaring
+		// that type.
+		_, typeName := staticImportType(imp.Path())
+		for _, file := range packageTypeIndex[pkg][typeName] {
+			addFile(file)
+		}
+		return resolved
+	}
+
 	if imp.IsWildcard() {
 		typeMap, ok := packageTypeIndex[pkg]
 		if !ok {
@@ -173,7 +205,7 @@ func resolveJavaImportPath(
 
 func resolveJavaSamePackageDependencies(
 	sourceFile string,
-	sourceContent []byte,
+	sourceFacts javaFileFacts,
 	filePackages map[string]string,
 	packageTypeIndex map[string]map[string][]string,
 	imports []JavaImport,
@@ -189,7 +221,7 @@ func resolveJavaSamePackageDependencies(
 		return []string{}
 	}
 
-	typeReferences := ExtractTypeIdentifiers(sourceContent)
+	typeReferences := sourceFacts.TypeReferences
 	if len(typeReferences) == 0 {
 		return []string{}
 	}
@@ -206,7 +238,7 @@ func resolveJavaSamePackageDependencies(
 	}
 
 	declaredNames := make(map[string]bool)
-	for _, name := range ParseTopLevelTypeNames(sourceContent) {
+	for _, name := range sourceFacts.DeclaredTypes {
 		if name != "" {
 			declaredNames[name] = true
 		}
@@ -226,6 +258,11 @@ func resolveJavaSamePackageDependencies(
 			if depFile == sourceFile || !suppliedFiles[depFile] || seen[depFile] {
 				continue
 			}
+			// Same-package references stay inside the source sets the file compiles
+			// against, so main never links to a test file of its package.
+			if !moduleapi.SourceSetSees(sourceFile, depFile) {
+				continue
+			}
 			seen[depFile] = true
 			deps = append(deps, depFile)
 		}
@@ -33,13 +33,13 @@ public class Helper {}
 
 	reader := vcs.FilesystemContentReader()
 	indicesFiles := []string{appPath, helperPath}
-	pkgIndex, typeIndex, filePackages := BuildJavaIndices(indicesFiles, reader)
+	pkgIndex, typeIndex, filePackages := BuildJavaIndices(indicesFiles, reader, nil)
 	supplied := map[string]bool{
 		appPath:    true,
 		helperPath: true,
 	}
 
-	imports, err := ResolveJavaProjectImports(appPath, appPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveJavaProjectImports(appPath, appPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.Equal(t, []string{helperPath}, imports)
 }
@@ -65,13 +65,220 @@ public class PaymentMethod {}
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{cartPath, paymentPath}
-	pkgIndex, typeIndex, filePackages := BuildJavaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildJavaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		cartPath:    true,
 		paymentPath: true,
 	}
 
-	imports, err := ResolveJavaProjectImports(cartPath, cartPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveJavaProjectImports(cartPath, cartPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, paymentPath)
 }
+
+// writeJavaFiles writes Java sources below src/main/java of a temporary directory and
+// returns their absolute paths keyed by the relative name used in files.
+func writeJavaFiles(t *testing.T, files map[string]string) map[string]string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "src", "main", "java")
+	paths := make(map[string]string, len(files))
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		paths[name] = path
+	}
+	return paths
+}
+
+func resolveJavaFile(t *testing.T, paths map[string]string, name string) []string {
+	t.Helper()
+
+	reader := vcs.FilesystemContentReader()
+	files := make([]string, 0, len(paths))
+	supplied := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		files = append(files, path)
+		supplied[path] = true
+	}
+	pkgIndex, typeIndex, filePackages := BuildJavaIndices(files, reader, nil)
+
+	imports, err := ResolveJavaProjectImports(paths[name], paths[name], pkgIndex, typeIndex, filePackages, supplied, reader, nil)
+	require.NoError(t, err)
+	return imports
+}
+
+func TestResolveJavaProjectImports_WildcardImportResolvesOnlyUsedSymbols(t *testing.T) {
+	paths := writeJavaFiles(t, map[string]string{
+		"com/example/Main.java": `package com.example;
+
+import com.example.internal.*;
+
+public class Main {
+    void run() {
+        DarwinFormatter.getFormattedMessage();
+    }
+}
+`,
+		"com/example/internal/DarwinFormatter.java": `package com.example.internal;
+
+public final class DarwinFormatter {
+    public static String getFormattedMessage() { return "ok"; }
+}
+`,
+		"com/example/internal/UnusedType.java": `package com.example.internal;
+
+public class UnusedType {}
+`,
+	})
+
+	imports := resolveJavaFile(t, paths, "com/example/Main.java")
+
+	assert.Equal(t, []string{paths["com/example/internal/DarwinFormatter.java"]}, imports)
+}
+
+func TestResolveJavaProjectImports_SamePackageStaticReference(t *testing.T) {
+	paths := writeJavaFiles(t, map[string]string{
+		"com/example/model/Cart.java": `package com.example.model;
+
+public class Cart {
+    int limit() {
+        return Limits.MAX_ITEMS + Pricing.discount();
+    }
+}
+`,
+		"com/example/model/Limits.java": `package com.example.model;
+
+public final class Limits {
+    public static final int MAX_ITEMS = 10;
+}
+`,
+		"com/example/model/Pricing.java": `package com.example.model;
+
+public final class Pricing {
+    public static int discount() { return 1; }
+}
+`,
+		"com/example/model/Unused.java": `package com.example.model;
+
+public class Unused {}
+`,
+	})
+
+	imports := resolveJavaFile(t, paths, "com/example/model/Cart.java")
+
+	assert.ElementsMatch(t, []string{
+		paths["com/example/model/Limits.java"],
+		paths["com/example/model/Pricing.java"],
+	}, imports)
+}
+
+func TestResolveJavaProjectImports_StaticImportsResolveToDeclaringFile(t *testing.T) {
+	paths := writeJavaFiles(t, map[string]string{
+		"com/example/App.java": `package com.example;
+
+import static com.example.util.Strings.capitalize;
+import static com.example.util.Numbers.*;
+
+public class App {
+    String run() {
+        return capitalize("x") + ZERO;
+    }
+}
+`,
+		"com/example/util/Strings.java": `package com.example.util;
+
+public final class Strings {
+    public static String capitalize(String s) { return s; }
+}
+`,
+		"com/example/util/Numbers.java": `package com.example.util;
+
+public final class Numbers {
+    public static final int ZERO = 0;
+}
+`,
+		"com/example/util/Unused.java": `package com.example.util;
+
+public class Unused {}
+`,
+	})
+
+	imports := resolveJavaFile(t, paths, "com/example/App.java")
+
+	assert.Equal(t, []string{
+		paths["com/example/util/Strings.java"],
+		paths["com/example/util/Numbers.java"],
+	}, imports)
+}
+
+func TestResolveJavaProjectImports_PackageInfoLinksToAnnotationTypes(t *testing.T) {
+	paths := writeJavaFiles(t, map[string]string{
+		"com/example/api/package-info.java": `@NonNullApi
+@ApiVersion("2")
+package com.example.api;
+
+import com.example.annotations.ApiVersion;
+`,
+		"com/example/api/NonNullApi.java": `package com.example.api;
+
+public @interface NonNullApi {}
+`,
+		"com/example/annotations/ApiVersion.java": `package com.example.annotations;
+
+public @interface ApiVersion {
+    String value();
+}
+`,
+	})
+
+	imports := resolveJavaFile(t, paths, "com/example/api/package-info.java")
+
+	assert.ElementsMatch(t, []string{
+		paths["com/example/annotations/ApiVersion.java"],
+		paths["com/example/api/NonNullApi.java"],
+	}, imports)
+}
+
+func TestResolveJavaProjectImports_SamePackageStaysInSourceSet(t *testing.T) {
+	moduleDir := t.TempDir()
+	write := func(set, name, content string) string {
+		t.Helper()
+		path := filepath.Join(moduleDir, "src", set, "java", "com", "example", "model", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	cartPath := write("main", "Cart.java", `package com.example.model;
+
+import java.time.*;
+
+public class Cart {
+    private Clock clock;
+    private Item item;
+}
+`)
+	itemPath := write("main", "Item.java", "package com.example.model;\n\npublic class Item {}\n")
+	fakeClockPath := write("test", "Clock.java", "package com.example.model;\n\nclass Clock {}\n")
+	cartTestPath := write("test", "CartTest.java", `package com.example.model;
+
+class CartTest {
+    private final Cart cart = new Cart();
+    private final Clock clock = new Clock();
+}
+`)
+
+	reader := vcs.FilesystemContentReader()
+	files := []string{cartPath, itemPath, fakeClockPath, cartTestPath}
+	pkgIndex, typeIndex, filePackages := BuildJavaIndices(files, reader, nil)
+	supplied := map[string]bool{cartPath: true, itemPath: true, fakeClockPath: true, cartTestPath: true}
+
+	imports, err := ResolveJavaProjectImports(cartPath, cartPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{itemPath}, imports)
+
+	imports, err = ResolveJavaProjectImports(cartTestPath, cartTestPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{cartPath, fakeClockPath}, imports)
+}
@@ -20,7 +20,7 @@ func (Module) Maturity() moduleapi.MaturityLevel {
 }
 
 func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReader) moduleapi.Resolver {
-	packageIndex, packageTypes, filePackages := BuildJavaIndices(ctx.JavaFiles, contentReader)
+	packageIndex, packageTypes, filePackages := BuildJavaIndices(ctx.JavaFiles, contentReader, ctx.ParseCache)
 	return resolver{
 		ctx:           ctx,
 		contentReader: contentReader,
@@ -50,7 +50,8 @@ func (r resolver) ResolveProjectImports(absPath, filePath, _ string) ([]string,
 		r.packageTypes,
 		r.filePackages,
 		r.ctx.SuppliedFiles,
-		r.contentReader)
+		r.contentReader,
+		r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
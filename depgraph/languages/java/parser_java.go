@@ -36,6 +36,11 @@ func ensureJavaQueries() {
 type JavaImport interface {
 	Path() string
 	IsWildcard() bool
+	// IsStatic reports an import static declaration, whose path names members of a
+	// type rather than a type.
+	IsStatic() bool
+	// Package returns the imported package, or for a static import the package of
+	// the type declaring the members.
 	Package() string
 }
 
@@ -43,6 +48,7 @@ type JavaImport interface {
 type StandardLibraryImport struct {
 	path       string
 	isWildcard bool
+	isStatic   bool
 }
 
 func (s StandardLibraryImport) Path() string {
@@ -53,7 +59,15 @@ func (s StandardLibraryImport) IsWildcard() bool {
 	return s.isWildcard
 }
 
+func (s StandardLibraryImport) IsStatic() bool {
+	return s.isStatic
+}
+
 func (s StandardLibraryImport) Package() string {
+	if s.isStatic {
+		pkg, _ := staticImportType(s.path)
+		return pkg
+	}
 	return javaImportPackage(s.path)
 }
 
@@ -61,6 +75,7 @@ func (s StandardLibraryImport) Package() string {
 type ExternalImport struct {
 	path       string
 	isWildcard bool
+	isStatic   bool
 }
 
 func (e ExternalImport) Path() string {
@@ -71,7 +86,15 @@ func (e ExternalImport) IsWildcard() bool {
 	return e.isWildcard
 }
 
+func (e ExternalImport) IsStatic() bool {
+	return e.isStatic
+}
+
 func (e ExternalImport) Package() string {
+	if e.isStatic {
+		pkg, _ := staticImportType(e.path)
+		return pkg
+	}
 	return javaImportPackage(e.path)
 }
 
@@ -79,6 +102,7 @@ func (e ExternalImport) Package() string {
 type InternalImport struct {
 	path       string
 	isWildcard bool
+	isStatic   bool
 }
 
 func (i InternalImport) Path() string {
@@ -89,7 +113,15 @@ func (i InternalImport) IsWildcard() bool {
 	return i.isWildcard
 }
 
+func (i InternalImport) IsStatic() bool {
+	return i.isStatic
+}
+
 func (i InternalImport) Package() string {
+	if i.isStatic {
+		pkg, _ := staticImportType(i.path)
+		return pkg
+	}
 	return javaImportPackage(i.path)
 }
 
@@ -163,18 +195,25 @@ func ParseTopLevelTypeNames(sourceCode []byte) []string {
 
 // ParseJavaImports parses Java source code and classifies imports.
 func ParseJavaImports(sourceCode []byte, projectPackages map[string]bool) []JavaImport {
+	return classifyJavaImports(parseJavaImportDecls(sourceCode), projectPackages)
+}
+
+// javaImportDecl is an import declaration before it is classified against the
+// project's packages. Wildcard paths end in ".*".
+type javaImportDecl struct {
+	Path   string `json:"path"`
+	Static bool   `json:"static,omitempty"`
+}
+
+func parseJavaImportDecls(sourceCode []byte) []javaImportDecl {
 	tree, err := parseJava(sourceCode)
 	if err != nil {
-		return []JavaImport{}
+		return nil
 	}
 	defer tree.Close()
 
 	importDecls := findNodesOfType(tree.RootNode(), "import_declaration")
-	if len(importDecls) == 0 {
-		return []JavaImport{}
-	}
-
-	imports := make([]JavaImport, 0, len(importDecls))
+	decls := make([]javaImportDecl, 0, len(importDecls))
 	for _, node := range importDecls {
 		path, isWildcard := extractImportPath(node, sourceCode)
 		if path == "" {
@@ -183,23 +222,30 @@ func ParseJavaImports(sourceCode []byte, projectPackages map[string]bool) []Java
 		if isWildcard && !strings.HasSuffix(path, ".*") {
 			path += ".*"
 		}
-		imports = append(imports, classifyJavaImport(path, projectPackages))
+		decls = append(decls, javaImportDecl{Path: path, Static: hasAnonymousChild(node, "static")})
 	}
+	return decls
+}
 
+func classifyJavaImports(decls []javaImportDecl, projectPackages map[string]bool) []JavaImport {
+	imports := make([]JavaImport, 0, len(decls))
+	for _, decl := range decls {
+		imports = append(imports, classifyJavaImport(decl.Path, decl.Static, projectPackages))
+	}
 	return imports
 }
 
-func classifyJavaImport(importPath string, projectPackages map[string]bool) JavaImport {
+func classifyJavaImport(importPath string, isStatic bool, projectPackages map[string]bool) JavaImport {
 	isWildcard := strings.HasSuffix(importPath, ".*")
 	if isStandardLibraryImport(importPath) {
-		return StandardLibraryImport{path: importPath, isWildcard: isWildcard}
+		return StandardLibraryImport{path: importPath, isWildcard: isWildcard, isStatic: isStatic}
 	}
 
 	if isInternalJavaImport(importPath, projectPackages) {
-		return InternalImport{path: importPath, isWildcard: isWildcard}
+		return InternalImport{path: importPath, isWildcard: isWildcard, isStatic: isStatic}
 	}
 
-	return ExternalImport{path: importPath, isWildcard: isWildcard}
+	return ExternalImport{path: importPath, isWildcard: isWildcard, isStatic: isStatic}
 }
 
 func isStandardLibraryImport(path string) bool {
@@ -257,6 +303,25 @@ func javaImportPackage(path string) string {
 	return trimmed
 }
 
+// staticImportType splits the path of a static import, such as
+// com.example.Util.helper or com.example.Outer.Inner.*, into the package and the
+// top-level type declaring the members, following the convention that package
+// segments are lower case and type names start with an upper-case letter.
+func staticImportType(path string) (pkg, typeName string) {
+	parts := strings.Split(strings.TrimSuffix(path, ".*"), ".")
+	for i, part := range parts {
+		if part != "" && part[0] >= 'A' && part[0] <= 'Z' {
+			return strings.Join(parts[:i], "."), part
+		}
+	}
+	// Without an upper-case segment, take the last segment as the member and the one
+	// before it as the type.
+	if len(parts) < 3 {
+		return "", ""
+	}
+	return strings.Join(parts[:len(parts)-2], "."), parts[len(parts)-2]
+}
+
 func simpleTypeName(path string) string {
 	trimmed := strings.TrimSuffix(path, ".*")
 	parts := strings.Split(trimmed, ".")
@@ -313,9 +378,18 @@ func ExtractTypeIdentifiers(sourceCode []byte) []string {
 	return result
 }
 
+// javaTypeIdentifierQuery captures type names: type positions, the receivers of
+// static calls and field reads such as Helper.run() and Limits.MAX, and annotations,
+// which is how package-info.java files use types. Receivers and annotations are plain
+// identifiers, so only capitalized ones are taken.
 const javaTypeIdentifierQuery = `
 ((type_identifier) @type.name)
 ((scoped_type_identifier) @type.name)
+((method_invocation object: (identifier) @type.name) (#match? @type.name "^[A-Z]"))
+((field_access object: (identifier) @type.name) (#match? @type.name "^[A-Z]"))
+((method_reference . (identifier) @type.name) (#match? @type.name "^[A-Z]"))
+((marker_annotation name: (identifier) @type.name) (#match? @type.name "^[A-Z]"))
+((annotation name: (identifier) @type.name) (#match? @type.name "^[A-Z]"))
 `
 
 func parseJava(sourceCode []byte) (*sitter.Tree, error) {
@@ -367,6 +441,17 @@ func extractImportPath(node *sitter.Node, sourceCode []byte) (string, bool) {
 	return strings.TrimSpace(nameNode.Content(sourceCode)), hasChildOfType(node, "asterisk")
 }
 
+// hasAnonymousChild reports whether node has a direct child token of nodeType, such
+// as the static keyword of an import declaration.
+func hasAnonymousChild(node *sitter.Node, nodeType string) bool {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child != nil && !child.IsNamed() && child.Type() == nodeType {
+			return true
+		}
+	}
+	return false
+}
+
 func hasChildOfType(node *sitter.Node, nodeType string) bool {
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(i)
@@ -74,3 +74,47 @@ public class Cart {
 	assert.NotContains(t, identifiers, "DeliveryOption")
 	assert.NotContains(t, identifiers, "Money")
 }
+
+func TestParseJavaImports_StaticImports(t *testing.T) {
+	src := []byte(`package com.example;
+
+import static com.example.util.Strings.capitalize;
+import static com.example.util.Outer.Inner.*;
+import com.example.util.*;
+`)
+
+	imports := ParseJavaImports(src, map[string]bool{"com.example.util": true})
+	require.Len(t, imports, 3)
+
+	assert.True(t, imports[0].IsStatic())
+	assert.False(t, imports[0].IsWildcard())
+	assert.Equal(t, "com.example.util", imports[0].Package())
+	assert.True(t, imports[1].IsStatic())
+	assert.True(t, imports[1].IsWildcard())
+	assert.Equal(t, "com.example.util", imports[1].Package())
+	assert.False(t, imports[2].IsStatic())
+	assert.Equal(t, "com.example.util", imports[2].Package())
+}
+
+func TestExtractTypeIdentifiers_StaticReceiversAndAnnotations(t *testing.T) {
+	src := []byte(`package com.example;
+
+@Service
+class App {
+    @Inject Repo repo;
+
+    void run() {
+        Helper.run();
+        int max = Limits.MAX;
+        items.forEach(Printer::print);
+        repo.save();
+    }
+}
+`)
+
+	names := ExtractTypeIdentifiers(src)
+
+	assert.Subset(t, names, []string{"Service", "Inject", "Repo", "Helper", "Limits", "Printer", "App"})
+	assert.NotContains(t, names, "repo")
+	assert.NotContains(t, names, "items")
+}
@@ -3,33 +3,55 @@ package javascript
 import (
 	"fmt"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// internalImportsCacheKind names the relative import paths
+// ResolveJavaScriptProjectImports caches per file; JSX files get their own kind.
+const internalImportsCacheKind = "javascript-internal-imports/v1"
+
 func ResolveJavaScriptProjectImports(
 	absPath string,
 	filePath string,
 	ext string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	jsx := ext == ".jsx"
+	kind := internalImportsCacheKind
+	if jsx {
+		kind += "+jsx"
+	}
+	importPaths, err := moduleapi.ParseFile(cache, contentReader, kind, absPath, func(content []byte) ([]string, error) {
+		imports, parseErr := ParseJavaScriptImports(content, jsx)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		}
+		return InternalImportPaths(imports), nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+		return nil, err
 	}
 
-	imports, parseErr := ParseJavaScriptImports(content, ext == ".jsx")
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+	var projectImports []string
+	for _, importPath := range importPaths {
+		resolvedFiles := ResolveJavaScriptImportPath(absPath, importPath, suppliedFiles)
+		projectImports = append(projectImports, resolvedFiles...)
 	}
 
-	var projectImports []string
+	return projectImports, nil
+}
+
+// InternalImportPaths returns the paths of the relative imports among imports, in
+// order.
+func InternalImportPaths(imports []JavaScriptImport) []string {
+	var paths []string
 	for _, imp := range imports {
 		if internalImp, ok := imp.(InternalImport); ok {
-			resolvedFiles := ResolveJavaScriptImportPath(absPath, internalImp.Path(), suppliedFiles)
-			projectImports = append(projectImports, resolvedFiles...)
+			paths = append(paths, internalImp.Path())
 		}
 	}
-
-	return projectImports, nil
+	return paths
 }
@@ -416,7 +416,17 @@ func cleanImportPath(raw string) string {
 	return strings.TrimSpace(cleaned)
 }
 
-// ResolveJavaScriptImportPath resolves a JavaScript import path to possible file paths
+// resolutionExtensions is the order in which an extensionless import is tried against
+// the project's files: JavaScript first, then TypeScript sources that mixed projects
+// import from JavaScript, then the ES module and CommonJS variants.
+var resolutionExtensions = []string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs"}
+
+// ResolveJavaScriptImportPath resolves a relative JavaScript import path to the project
+// file it names. An import with an extension must match exactly; otherwise the path is
+// tried with each of resolutionExtensions, then as a directory with an index file in
+// the same order, so './util' resolves to util.js, util.jsx, util.ts, ..., then
+// util/index.js, util/index.jsx, util/index.ts, ..., whichever exists first. It returns
+// nil when no supplied file matches.
 func ResolveJavaScriptImportPath(sourceFile, importPath string, suppliedFiles map[string]bool) []string {
 	sourceDir := filepath.Dir(sourceFile)
 
@@ -424,42 +434,25 @@ func ResolveJavaScriptImportPath(sourceFile, importPath string, suppliedFiles ma
 	basePath := filepath.Join(sourceDir, importPath)
 	basePath = filepath.Clean(basePath)
 
-	var resolvedPaths []string
-
-	// JavaScript extension resolution order
-	extensions := []string{".js", ".jsx", ".mjs", ".cjs"}
-
-	// Try direct path with extensions
-	for _, ext := range extensions {
-		candidate := basePath + ext
-		if suppliedFiles[candidate] {
-			resolvedPaths = append(resolvedPaths, candidate)
-		}
-	}
-
-	// Try index file resolution (./utils -> ./utils/index.js)
-	indexPaths := []string{
-		filepath.Join(basePath, "index.js"),
-		filepath.Join(basePath, "index.jsx"),
-		filepath.Join(basePath, "index.mjs"),
-		filepath.Join(basePath, "index.cjs"),
+	// If import already has an extension, try the exact path
+	if hasJavaScriptExtension(importPath) && suppliedFiles[basePath] {
+		return []string{basePath}
 	}
 
-	for _, indexPath := range indexPaths {
-		if suppliedFiles[indexPath] {
-			resolvedPaths = append(resolvedPaths, indexPath)
+	for _, ext := range resolutionExtensions {
+		if candidate := basePath + ext; suppliedFiles[candidate] {
+			return []string{candidate}
 		}
 	}
 
-	// If import already has an extension, try the exact path
-	if hasJavaScriptExtension(importPath) {
-		exactPath := basePath
-		if suppliedFiles[exactPath] {
-			resolvedPaths = append(resolvedPaths, exactPath)
+	// Try index file resolution (./utils -> ./utils/index.js)
+	for _, ext := range resolutionExtensions {
+		if candidate := filepath.Join(basePath, "index"+ext); suppliedFiles[candidate] {
+			return []string{candidate}
 		}
 	}
 
-	return resolvedPaths
+	return nil
 }
 
 // hasJavaScriptExtension checks if a path already has a JavaScript extension
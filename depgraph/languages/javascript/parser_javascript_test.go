@@ -156,6 +156,41 @@ func TestResolveJavaScriptImportPath_CJS(t *testing.T) {
 	assert.Contains(t, resolved, "/project/src/legacy/index.cjs")
 }
 
+func TestResolveJavaScriptImportPath_FollowsDocumentedOrder(t *testing.T) {
+	candidates := []string{
+		"/project/src/util.js",
+		"/project/src/util.jsx",
+		"/project/src/util.ts",
+		"/project/src/util/index.js",
+	}
+
+	for i, want := range candidates {
+		suppliedFiles := make(map[string]bool)
+		for _, candidate := range candidates[i:] {
+			suppliedFiles[candidate] = true
+		}
+
+		resolved := ResolveJavaScriptImportPath("/project/src/app.js", "./util", suppliedFiles)
+		assert.Equal(t, []string{want}, resolved)
+	}
+}
+
+func TestResolveJavaScriptImportPath_TypeScriptSources(t *testing.T) {
+	suppliedFiles := map[string]bool{
+		"/project/src/format.ts":           true,
+		"/project/src/Button.tsx":          true,
+		"/project/src/services/index.ts":   true,
+		"/project/src/services/client.cjs": true,
+	}
+
+	sourceFile := "/project/src/app.jsx"
+
+	assert.Equal(t, []string{"/project/src/format.ts"}, ResolveJavaScriptImportPath(sourceFile, "./format", suppliedFiles))
+	assert.Equal(t, []string{"/project/src/Button.tsx"}, ResolveJavaScriptImportPath(sourceFile, "./Button", suppliedFiles))
+	assert.Equal(t, []string{"/project/src/services/index.ts"}, ResolveJavaScriptImportPath(sourceFile, "./services", suppliedFiles))
+	assert.Empty(t, ResolveJavaScriptImportPath(sourceFile, "./missing", suppliedFiles))
+}
+
 func TestJavaScriptImports_MJSFile(t *testing.T) {
 	filePath := filepath.Join("testdata", "sample.test.mjs")
 	imports, err := JavaScriptImports(filePath)
@@ -174,7 +209,7 @@ func TestResolveJavaScriptProjectImports_MJSFile(t *testing.T) {
 	resolved, err := ResolveJavaScriptProjectImports(testFile, testFile, ".mjs", map[string]bool{
 		testFile:   true,
 		sampleFile: true,
-	}, vcs.FilesystemContentReader())
+	}, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, resolved, sampleFile)
 }
@@ -1,13 +1,68 @@
 package kotlin
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	graphlib "github.com/dominikbraun/graph"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// Kinds of the facts the resolver caches per file: declarationsCacheKind for
+// kotlinDeclarations, which every indexed file needs, and referencesCacheKind for
+// kotlinReferences, which only resolved files need.
+const (
+	declarationsCacheKind = "kotlin-declarations/v1"
+	referencesCacheKind   = "kotlin-references/v1"
+)
+
+// kotlinDeclarations is what a Kotlin file declares.
+type kotlinDeclarations struct {
+	Package              string                `json:"package,omitempty"`
+	TopLevelTypes        []string              `json:"topLevelTypes,omitempty"`
+	CompanionMembers     []string              `json:"companionMembers,omitempty"`
+	PlatformDeclarations []PlatformDeclaration `json:"platformDeclarations,omitempty"`
+}
+
+func parseKotlinDeclarations(content []byte) (kotlinDeclarations, error) {
+	return kotlinDeclarations{
+		Package:              ExtractPackageDeclaration(content),
+		TopLevelTypes:        ExtractTopLevelTypeNames(content),
+		CompanionMembers:     ExtractCompanionMemberNames(content),
+		PlatformDeclarations: ExtractPlatformDeclarations(content),
+	}, nil
+}
+
+// kotlinReferences is what a Kotlin file refers to.
+type kotlinReferences struct {
+	Imports        []kotlinImportDecl `json:"imports,omitempty"`
+	TypeReferences []string           `json:"typeReferences,omitempty"`
+}
+
+// kotlinImportDecl is an import before it is classified against the project's
+// packages.
+type kotlinImportDecl struct {
+	Path     string `json:"path"`
+	Wildcard bool   `json:"wildcard,omitempty"`
+}
+
+func parseKotlinReferences(content []byte) (kotlinReferences, error) {
+	imports, err := ParseKotlinImports(content)
+	if err != nil {
+		return kotlinReferences{}, err
+	}
+	refs := kotlinReferences{TypeReferences: ExtractTypeIdentifiers(content)}
+	for _, imp := range imports {
+		refs.Imports = append(refs.Imports, kotlinImportDecl{Path: imp.Path(), Wildcard: imp.IsWildcard()})
+	}
+	return refs, nil
+}
+
 func ResolveKotlinProjectImports(
 	absPath string,
 	filePath string,
@@ -17,14 +72,29 @@ func ResolveKotlinProjectImports(
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
+	return resolveKotlinProjectImports(absPath, filePath, kotlinPackageIndex, kotlinPackageTypes, kotlinFilePackages, suppliedFiles, contentReader, nil, nil)
+}
 
-	imports, err := ParseKotlinImports(content)
+func resolveKotlinProjectImports(
+	absPath string,
+	filePath string,
+	kotlinPackageIndex map[string][]string,
+	kotlinPackageTypes map[string]map[string][]string,
+	kotlinFilePackages map[string]string,
+	suppliedFiles map[string]bool,
+	contentReader vcs.ContentReader,
+	edgeSymbols *moduleapi.EdgeSymbols,
+	cache moduleapi.ParseCache,
+) ([]string, error) {
+	refs, err := moduleapi.ParseFile(cache, contentReader, referencesCacheKind, absPath, func(content []byte) (kotlinReferences, error) {
+		refs, err := parseKotlinReferences(content)
+		if err != nil {
+			return kotlinReferences{}, fmt.Errorf("failed to parse imports in %s: %w", filePath, err)
+		}
+		return refs, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, err)
+		return nil, err
 	}
 
 	projectPackages := make(map[string]bool)
@@ -32,8 +102,11 @@ func ResolveKotlinProjectImports(
 		projectPackages[pkg] = true
 	}
 
-	imports = ClassifyWithProjectPackages(imports, projectPackages)
-	typeReferences := ExtractTypeIdentifiers(content)
+	imports := make([]KotlinImport, 0, len(refs.Imports))
+	for _, imp := range refs.Imports {
+		imports = append(imports, classifyKotlinImport(imp.Path, imp.Wildcard, projectPackages))
+	}
+	typeReferences := refs.TypeReferences
 	referencedTypes := make(map[string]bool, len(typeReferences))
 	for _, ref := range typeReferences {
 		if ref != "" {
@@ -44,7 +117,7 @@ func ResolveKotlinProjectImports(
 	var projectImports []string
 	for _, imp := range imports {
 		if internalImp, ok := imp.(InternalImport); ok {
-			resolvedFiles := resolveKotlinImportPath(absPath, internalImp, kotlinPackageTypes, referencedTypes, suppliedFiles)
+			resolvedFiles := resolveKotlinImportPath(absPath, internalImp, kotlinPackageTypes, referencedTypes, suppliedFiles, edgeSymbols)
 			projectImports = append(projectImports, resolvedFiles...)
 		}
 	}
@@ -52,7 +125,9 @@ func ResolveKotlinProjectImports(
 	if len(kotlinPackageTypes) > 0 {
 		samePackageDeps := resolveKotlinSamePackageDependencies(
 			absPath,
+			typeReferences,
 			contentReader,
+			cache,
 			kotlinFilePackages,
 			kotlinPackageTypes,
 			imports,
@@ -66,12 +141,23 @@ func ResolveKotlinProjectImports(
 func BuildKotlinIndices(
 	kotlinFiles []string,
 	contentReader vcs.ContentReader,
+) (map[string][]string, map[string]map[string][]string, map[string]string) {
+	return buildKotlinIndices(kotlinFiles, contentReader, 0, nil)
+}
+
+// buildKotlinIndices builds the indices like BuildKotlinIndices, parsing up to
+// parallelism files at once (0 means GOMAXPROCS).
+func buildKotlinIndices(
+	kotlinFiles []string,
+	contentReader vcs.ContentReader,
+	parallelism int,
+	cache moduleapi.ParseCache,
 ) (map[string][]string, map[string]map[string][]string, map[string]string) {
 	if len(kotlinFiles) == 0 {
 		return nil, nil, make(map[string]string)
 	}
 
-	kotlinPackageIndex, kotlinPackageTypes := buildKotlinPackageIndex(kotlinFiles, contentReader)
+	kotlinPackageIndex, kotlinPackageTypes := buildKotlinPackageIndex(kotlinFiles, contentReader, parallelism, cache)
 	kotlinFilePackages := make(map[string]string)
 	for pkg, files := range kotlinPackageIndex {
 		for _, file := range files {
@@ -82,68 +168,93 @@ func BuildKotlinIndices(
 	return kotlinPackageIndex, kotlinPackageTypes, kotlinFilePackages
 }
 
-func buildKotlinPackageIndex(filePaths []string, contentReader vcs.ContentReader) (map[string][]string, map[string]map[string][]string) {
-	packageToFiles := make(map[string][]string)
-	packageToTypes := make(map[string]map[string][]string)
+// kotlinFileDeclarations is what the package index needs from one Kotlin file.
+type kotlinFileDeclarations struct {
+	absPath       string
+	pkg           string
+	declaredTypes []string
+}
 
-	for _, filePath := range filePaths {
-		absPath, err := filepath.Abs(filePath)
+// buildKotlinPackageIndex parses files concurrently and then indexes them in input
+// order, so the file lists in the index do not depend on scheduling.
+func buildKotlinPackageIndex(filePaths []string, contentReader vcs.ContentReader, parallelism int, cache moduleapi.ParseCache) (map[string][]string, map[string]map[string][]string) {
+	declarations := make([]kotlinFileDeclarations, len(filePaths))
+	moduleapi.ParallelFor(len(filePaths), parallelism, func(i int) {
+		absPath, err := filepath.Abs(filePaths[i])
 		if err != nil {
-			continue
+			return
 		}
 
-		content, err := contentReader(absPath)
+		decls, err := moduleapi.ParseFile(cache, contentReader, declarationsCacheKind, absPath, parseKotlinDeclarations)
 		if err != nil {
-			continue
+			return
 		}
 
-		pkg := ExtractPackageDeclaration(content)
+		pkg := decls.Package
 		if pkg == "" {
+			return
+		}
+
+		// Companion members are indexed as "Owner.member" next to the type names, so
+		// Keys.KEY references and member imports resolve to the file declaring Keys.
+		declarations[i] = kotlinFileDeclarations{
+			absPath:       absPath,
+			pkg:           pkg,
+			declaredTypes: append(append([]string(nil), decls.TopLevelTypes...), decls.CompanionMembers...),
+		}
+	})
+
+	packageToFiles := make(map[string][]string)
+	packageToTypes := make(map[string]map[string][]string)
+
+	for _, decl := range declarations {
+		if decl.pkg == "" {
 			continue
 		}
 
-		packageToFiles[pkg] = append(packageToFiles[pkg], absPath)
+		packageToFiles[decl.pkg] = append(packageToFiles[decl.pkg], decl.absPath)
 
-		declaredTypes := ExtractTopLevelTypeNames(content)
-		if len(declaredTypes) == 0 {
+		if len(decl.declaredTypes) == 0 {
 			continue
 		}
 
-		typeMap, ok := packageToTypes[pkg]
+		typeMap, ok := packageToTypes[decl.pkg]
 		if !ok {
 			typeMap = make(map[string][]string)
-			packageToTypes[pkg] = typeMap
+			packageToTypes[decl.pkg] = typeMap
 		}
 
-		for _, typeName := range declaredTypes {
+		for _, typeName := range decl.declaredTypes {
 			if typeName == "" {
 				continue
 			}
-			typeMap[typeName] = append(typeMap[typeName], absPath)
+			typeMap[typeName] = append(typeMap[typeName], decl.absPath)
 		}
 	}
 
 	return packageToFiles, packageToTypes
 }
 
-// resolveKotlinImportPath resolves Kotlin imports strictly by referenced symbols.
+// resolveKotlinImportPath resolves Kotlin imports strictly by referenced symbols and
+// records the imported simple names used from each resolved file.
 func resolveKotlinImportPath(
 	sourceFile string,
 	imp KotlinImport,
 	packageTypeIndex map[string]map[string][]string,
 	referencedTypes map[string]bool,
 	suppliedFiles map[string]bool,
+	edgeSymbols *moduleapi.EdgeSymbols,
 ) []string {
-	if len(referencedTypes) == 0 {
-		return nil
-	}
-
 	var resolvedFiles []string
 	seen := make(map[string]bool)
 
-	appendResolvedFiles := func(files []string) {
+	appendResolvedFiles := func(files []string, symbol string) {
 		for _, file := range files {
-			if file == sourceFile || !suppliedFiles[file] || seen[file] {
+			if file == sourceFile || !suppliedFiles[file] {
+				continue
+			}
+			edgeSymbols.Record(sourceFile, file, symbol)
+			if seen[file] {
 				continue
 			}
 			seen[file] = true
@@ -160,7 +271,7 @@ func resolveKotlinImportPath(
 			if len(files) != 1 {
 				continue
 			}
-			appendResolvedFiles(files)
+			appendResolvedFiles(files, ref)
 		}
 	}
 
@@ -172,6 +283,14 @@ func resolveKotlinImportPath(
 	} else {
 		pkg := imp.Package()
 		symbol := extractSimpleName(imp.Path())
+		// A member import names a single companion member, so the import itself is
+		// taken as its use.
+		if memberKey, ok := companionMemberKey(imp.Path(), pkg); ok {
+			if files := packageTypeIndex[pkg][memberKey]; len(files) == 1 {
+				appendResolvedFiles(files, symbol)
+				return resolvedFiles
+			}
+		}
 		if !referencedTypes[symbol] {
 			return resolvedFiles
 		}
@@ -180,7 +299,7 @@ func resolveKotlinImportPath(
 				if len(files) != 1 {
 					return resolvedFiles
 				}
-				appendResolvedFiles(files)
+				appendResolvedFiles(files, symbol)
 			}
 		}
 	}
@@ -191,7 +310,9 @@ func resolveKotlinImportPath(
 // resolveKotlinSamePackageDependencies finds Kotlin dependencies that are referenced without imports (same-package references)
 func resolveKotlinSamePackageDependencies(
 	sourceFile string,
+	typeReferences []string,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 	filePackages map[string]string,
 	packageTypeIndex map[string]map[string][]string,
 	imports []KotlinImport,
@@ -207,16 +328,14 @@ func resolveKotlinSamePackageDependencies(
 		return []string{}
 	}
 
-	sourceCode, err := contentReader(sourceFile)
-	if err != nil {
+	if len(typeReferences) == 0 {
 		return []string{}
 	}
-
-	typeReferences := ExtractTypeIdentifiers(sourceCode)
-	if len(typeReferences) == 0 {
+	decls, err := moduleapi.ParseFile(cache, contentReader, declarationsCacheKind, sourceFile, parseKotlinDeclarations)
+	if err != nil {
 		return []string{}
 	}
-	declaredTypes := ExtractTopLevelTypeNames(sourceCode)
+	declaredTypes := decls.TopLevelTypes
 	declaredTypeSet := make(map[string]bool, len(declaredTypes))
 	for _, typeName := range declaredTypes {
 		if typeName != "" {
@@ -247,10 +366,9 @@ func resolveKotlinSamePackageDependencies(
 		if importedNames[ref] {
 			continue
 		}
-		files, ok := typeIndex[ref]
-		if !ok {
-			continue
-		}
+		// Only declarations of a source set the file compiles against count, so a
+		// main file never resolves to a same-named type of the test source set.
+		files := visibleSamePackageFiles(sourceFile, typeIndex[ref])
 		if len(files) != 1 {
 			continue
 		}
@@ -271,6 +389,32 @@ func resolveKotlinSamePackageDependencies(
 	return deps
 }
 
+// visibleSamePackageFiles keeps the files whose declarations sourceFile sees without
+// an import, as decided by moduleapi.SourceSetSees.
+func visibleSamePackageFiles(sourceFile string, files []string) []string {
+	visible := make([]string, 0, len(files))
+	for _, file := range files {
+		if moduleapi.SourceSetSees(sourceFile, file) {
+			visible = append(visible, file)
+		}
+	}
+	return visible
+}
+
+// companionMemberKey returns the "Owner.member" index key of an import of a companion
+// member, such as app.Keys.KEY or app.Keys.Companion.KEY for package app.
+func companionMemberKey(importPath, pkg string) (string, bool) {
+	rest, ok := strings.CutPrefix(importPath, pkg+".")
+	if !ok {
+		return "", false
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+	return parts[0] + "." + parts[len(parts)-1], true
+}
+
 // extractSimpleName returns the trailing identifier from a dot-delimited path
 func extractSimpleName(path string) string {
 	if path == "" {
@@ -279,3 +423,80 @@ func extractSimpleName(path string) string {
 	parts := strings.Split(path, ".")
 	return parts[len(parts)-1]
 }
+
+// expectActualFiles lists the files declaring one package-qualified name with expect and actual.
+type expectActualFiles struct {
+	expects []string
+	actuals []string
+}
+
+// buildKotlinExpectActualIndex groups expect/actual declarations by package-qualified name.
+func buildKotlinExpectActualIndex(
+	kotlinFiles []string,
+	filePackages map[string]string,
+	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
+) map[string]*expectActualFiles {
+	index := make(map[string]*expectActualFiles)
+	for _, file := range kotlinFiles {
+		decls, err := moduleapi.ParseFile(cache, contentReader, declarationsCacheKind, file, parseKotlinDeclarations)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range decls.PlatformDeclarations {
+			key := decl.Name
+			if pkg := filePackages[file]; pkg != "" {
+				key = pkg + "." + decl.Name
+			}
+
+			files, ok := index[key]
+			if !ok {
+				files = &expectActualFiles{}
+				index[key] = files
+			}
+			switch decl.Modifier {
+			case PlatformExpect:
+				files.expects = appendUnique(files.expects, file)
+			case PlatformActual:
+				files.actuals = appendUnique(files.actuals, file)
+			}
+		}
+	}
+	return index
+}
+
+// linkExpectActualDeclarations adds an edge from every actual declaration file to the
+// file declaring the matching expect, so each source set's implementation is linked.
+func linkExpectActualDeclarations(graph moduleapi.Graph, index map[string]*expectActualFiles) error {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		files := index[key]
+		for _, actual := range files.actuals {
+			for _, expect := range files.expects {
+				if actual == expect {
+					continue
+				}
+				err := graph.AddEdge(actual, expect, moduleapi.WithEdgeProvenance(moduleapi.ExpectActualProvenance))
+				if err != nil && !errors.Is(err, graphlib.ErrEdgeAlreadyExists) {
+					return fmt.Errorf("failed to link actual %s to expect %s: %w", actual, expect, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -179,6 +180,55 @@ class UnusedType
 	assert.NotContains(t, deps, unusedType)
 }
 
+func TestResolveKotlinProjectImports_RecordsImportedSimpleNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	modelDir := filepath.Join(tmpDir, "src", "main", "kotlin", "com", "example", "model")
+	mainDir := filepath.Join(tmpDir, "src", "main", "kotlin", "com", "example")
+	require.NoError(t, os.MkdirAll(modelDir, 0o755))
+
+	mainFile := filepath.Join(mainDir, "Main.kt")
+	modelsFile := filepath.Join(modelDir, "Models.kt")
+
+	require.NoError(t, os.WriteFile(mainFile, []byte(`
+package com.example
+
+import com.example.model.User
+import com.example.model.Order
+
+class Main {
+  fun run(user: User, order: Order) {}
+}
+`), 0o644))
+	require.NoError(t, os.WriteFile(modelsFile, []byte(`
+package com.example.model
+
+class User
+class Order
+class Invoice
+`), 0o644))
+
+	contentReader := vcs.FilesystemContentReader()
+	packageIndex, packageTypes, filePackages := BuildKotlinIndices([]string{mainFile, modelsFile}, contentReader)
+	suppliedFiles := map[string]bool{mainFile: true, modelsFile: true}
+	edgeSymbols := &moduleapi.EdgeSymbols{}
+
+	deps, err := resolveKotlinProjectImports(
+		mainFile,
+		mainFile,
+		packageIndex,
+		packageTypes,
+		filePackages,
+		suppliedFiles,
+		contentReader,
+		edgeSymbols,
+		nil)
+	require.NoError(t, err)
+	assert.Contains(t, deps, modelsFile)
+	assert.Equal(t, []moduleapi.SymbolEdge{
+		{From: mainFile, To: modelsFile, Symbols: []string{"Order", "User"}},
+	}, edgeSymbols.All())
+}
+
 func TestResolveKotlinProjectImports_SkipsAmbiguousTypeDefinitions(t *testing.T) {
 	tmpDir := t.TempDir()
 	commonDir := filepath.Join(tmpDir, "src", "commonMain", "kotlin", "com", "example")
@@ -235,3 +285,147 @@ actual object KotlinLoggingConfiguration {
 	assert.NotContains(t, deps, commonConfig)
 	assert.NotContains(t, deps, jvmConfig)
 }
+
+func TestResolveKotlinSamePackageDependencies_TypealiasInSiblingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	callbacks := filepath.Join(tmpDir, "Callbacks.kt")
+	user := filepath.Join(tmpDir, "User.kt")
+
+	require.NoError(t, os.WriteFile(callbacks, []byte(`
+package app
+
+typealias Callback = (String) -> Unit
+`), 0o644))
+	require.NoError(t, os.WriteFile(user, []byte(`
+package app
+
+fun register(callback: Callback) {}
+`), 0o644))
+
+	contentReader := vcs.FilesystemContentReader()
+	packageIndex, packageTypes, filePackages := BuildKotlinIndices([]string{callbacks, user}, contentReader)
+	suppliedFiles := map[string]bool{callbacks: true, user: true}
+
+	deps, err := ResolveKotlinProjectImports(user, user, packageIndex, packageTypes, filePackages, suppliedFiles, contentReader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{callbacks}, deps)
+}
+
+func TestResolveKotlinSamePackageDependencies_QualifiedCompanionMember(t *testing.T) {
+	tmpDir := t.TempDir()
+	keys := filepath.Join(tmpDir, "Keys.kt")
+	user := filepath.Join(tmpDir, "User.kt")
+
+	require.NoError(t, os.WriteFile(keys, []byte(`
+package app
+
+class Keys {
+  companion object {
+    const val KEY = "key"
+  }
+}
+`), 0o644))
+	require.NoError(t, os.WriteFile(user, []byte(`
+package app
+
+fun key() = Keys.KEY
+`), 0o644))
+
+	contentReader := vcs.FilesystemContentReader()
+	packageIndex, packageTypes, filePackages := BuildKotlinIndices([]string{keys, user}, contentReader)
+	suppliedFiles := map[string]bool{keys: true, user: true}
+
+	deps, err := ResolveKotlinProjectImports(user, user, packageIndex, packageTypes, filePackages, suppliedFiles, contentReader)
+	require.NoError(t, err)
+	assert.Contains(t, deps, keys)
+}
+
+func TestResolveKotlinProjectImports_ImportedCompanionMembers(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "app")
+	featureDir := filepath.Join(tmpDir, "feature")
+	require.NoError(t, os.MkdirAll(appDir, 0o755))
+	require.NoError(t, os.MkdirAll(featureDir, 0o755))
+
+	keys := filepath.Join(appDir, "Keys.kt")
+	factory := filepath.Join(appDir, "Factory.kt")
+	user := filepath.Join(featureDir, "User.kt")
+
+	require.NoError(t, os.WriteFile(keys, []byte(`
+package app
+
+class Keys {
+  companion object {
+    const val KEY = "key"
+  }
+}
+`), 0o644))
+	require.NoError(t, os.WriteFile(factory, []byte(`
+package app
+
+class Factory {
+  companion object Builder {
+    fun make() = Factory()
+  }
+}
+`), 0o644))
+	require.NoError(t, os.WriteFile(user, []byte(`
+package feature
+
+import app.Keys.Companion.KEY
+import app.Factory.make
+
+fun build() = KEY + make()
+`), 0o644))
+
+	contentReader := vcs.FilesystemContentReader()
+	packageIndex, packageTypes, filePackages := BuildKotlinIndices([]string{keys, factory, user}, contentReader)
+	suppliedFiles := map[string]bool{keys: true, factory: true, user: true}
+	edgeSymbols := &moduleapi.EdgeSymbols{}
+
+	deps, err := resolveKotlinProjectImports(user, user, packageIndex, packageTypes, filePackages, suppliedFiles, contentReader, edgeSymbols, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{keys, factory}, deps)
+	assert.Equal(t, []moduleapi.SymbolEdge{
+		{From: user, To: factory, Symbols: []string{"make"}},
+		{From: user, To: keys, Symbols: []string{"KEY"}},
+	}, edgeSymbols.All())
+}
+
+func TestResolveKotlinSamePackageDependencies_StaysInSourceSet(t *testing.T) {
+	moduleDir := t.TempDir()
+	write := func(set, name, content string) string {
+		t.Helper()
+		path := filepath.Join(moduleDir, "src", set, "kotlin", "com", "example", "app", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	appPath := write("main", "App.kt", `package com.example.app
+
+import java.time.*
+
+class App(val clock: Clock, val config: Config)
+`)
+	configPath := write("main", "Config.kt", "package com.example.app\n\nclass Config\n")
+	fakeClockPath := write("test", "Clock.kt", "package com.example.app\n\nclass Clock\n")
+	appTestPath := write("test", "AppTest.kt", `package com.example.app
+
+class AppTest {
+  val app = App(Clock(), Config())
+}
+`)
+
+	contentReader := vcs.FilesystemContentReader()
+	kotlinFiles := []string{appPath, configPath, fakeClockPath, appTestPath}
+	packageIndex, packageTypes, filePackages := BuildKotlinIndices(kotlinFiles, contentReader)
+	suppliedFiles := map[string]bool{appPath: true, configPath: true, fakeClockPath: true, appTestPath: true}
+
+	deps, err := ResolveKotlinProjectImports(appPath, appPath, packageIndex, packageTypes, filePackages, suppliedFiles, contentReader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{configPath}, deps)
+
+	deps, err = ResolveKotlinProjectImports(appTestPath, appTestPath, packageIndex, packageTypes, filePackages, suppliedFiles, contentReader)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{appPath, configPath, fakeClockPath}, deps)
+}
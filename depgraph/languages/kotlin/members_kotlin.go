@@ -0,0 +1,105 @@
+package kotlin
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// MemberReference is a use of a name in a Kotlin file.
+type MemberReference struct {
+	Name string
+	Line int
+	// Function is the name of the innermost function containing the reference, or
+	// empty at file scope.
+	Function string
+}
+
+// ExtractTopLevelFunctionNames returns the names of the functions declared at the top
+// level of the file, including extension functions.
+func ExtractTopLevelFunctionNames(sourceCode []byte) []string {
+	parser := kotlinParserPool.Get().(*sitter.Parser)
+	defer kotlinParserPool.Put(parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, sourceCode)
+	if err != nil {
+		return nil
+	}
+	defer tree.Close()
+
+	var names []string
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		decl := root.NamedChild(i)
+		if decl.Type() != "function_declaration" {
+			continue
+		}
+		if name := extractDeclarationIdentifier(decl, sourceCode); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// FindMemberReferences returns the uses of names in the file, in source order. Package
+// and import headers are skipped, as are member accesses such as the of in
+// PriceList.of, which name members of another declaration.
+func FindMemberReferences(sourceCode []byte, names map[string]bool) []MemberReference {
+	if len(names) == 0 {
+		return nil
+	}
+
+	parser := kotlinParserPool.Get().(*sitter.Parser)
+	defer kotlinParserPool.Put(parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, sourceCode)
+	if err != nil {
+		return nil
+	}
+	defer tree.Close()
+
+	var references []MemberReference
+	var walk func(node *sitter.Node, function string)
+	walk = func(node *sitter.Node, function string) {
+		switch node.Type() {
+		case "package_header", "import_list", "navigation_suffix":
+			return
+		case "function_declaration":
+			if name := extractDeclarationIdentifier(node, sourceCode); name != "" {
+				function = name
+			}
+		case "simple_identifier", "type_identifier":
+			name := strings.TrimSpace(node.Content(sourceCode))
+			if names[name] && !isDeclarationName(node) {
+				references = append(references, MemberReference{
+					Name:     name,
+					Line:     int(node.StartPoint().Row) + 1,
+					Function: function,
+				})
+			}
+			return
+		}
+
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i), function)
+		}
+	}
+	walk(tree.RootNode(), "")
+	return references
+}
+
+// isDeclarationName reports whether an identifier node is the name of the declaration
+// that contains it rather than a use.
+func isDeclarationName(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil {
+		return false
+	}
+	switch parent.Type() {
+	case "function_declaration", "class_declaration", "object_declaration", "interface_declaration", "type_alias", "variable_declaration", "parameter":
+		return true
+	default:
+		return false
+	}
+}
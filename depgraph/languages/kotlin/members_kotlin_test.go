@@ -0,0 +1,50 @@
+package kotlin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractTopLevelFunctionNames(t *testing.T) {
+	source := []byte(`package app
+
+fun formatPrice(amount: Int): String = "$amount"
+fun String.shout(): String = uppercase()
+private fun helper() = 1
+
+class Cart {
+    fun add() {}
+}
+`)
+
+	assert.Equal(t, []string{"formatPrice", "shout", "helper"}, ExtractTopLevelFunctionNames(source))
+}
+
+func TestFindMemberReferences(t *testing.T) {
+	source := []byte(`package app
+
+import app.pricing.Price
+
+val banner = formatPrice(Price(1))
+
+fun total(price: Price): String {
+    return formatPrice(price) + Catalog.formatPrice
+}
+
+class Cart {
+    fun add(): Price = Price(2)
+}
+`)
+
+	references := FindMemberReferences(source, map[string]bool{"Price": true, "formatPrice": true})
+
+	assert.Equal(t, []MemberReference{
+		{Name: "formatPrice", Line: 5},
+		{Name: "Price", Line: 5},
+		{Name: "Price", Line: 7, Function: "total"},
+		{Name: "formatPrice", Line: 8, Function: "total"},
+		{Name: "Price", Line: 12, Function: "add"},
+		{Name: "Price", Line: 12, Function: "add"},
+	}, references)
+}
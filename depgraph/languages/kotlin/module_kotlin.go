@@ -20,13 +20,14 @@ func (Module) Maturity() moduleapi.MaturityLevel {
 }
 
 func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReader) moduleapi.Resolver {
-	packageIndex, packageTypes, filePackages := BuildKotlinIndices(ctx.KotlinFiles, contentReader)
+	packageIndex, packageTypes, filePackages := buildKotlinIndices(ctx.KotlinFiles, contentReader, ctx.Parallelism, ctx.ParseCache)
 	return resolver{
 		ctx:           ctx,
 		contentReader: contentReader,
 		packageIndex:  packageIndex,
 		packageTypes:  packageTypes,
 		filePackages:  filePackages,
+		expectActual:  buildKotlinExpectActualIndex(ctx.KotlinFiles, filePackages, contentReader, ctx.ParseCache),
 	}
 }
 
@@ -40,19 +41,22 @@ type resolver struct {
 	packageIndex  map[string][]string
 	packageTypes  map[string]map[string][]string
 	filePackages  map[string]string
+	expectActual  map[string]*expectActualFiles
 }
 
 func (r resolver) ResolveProjectImports(absPath, filePath, _ string) ([]string, error) {
-	return ResolveKotlinProjectImports(
+	return resolveKotlinProjectImports(
 		absPath,
 		filePath,
 		r.packageIndex,
 		r.packageTypes,
 		r.filePackages,
 		r.ctx.SuppliedFiles,
-		r.contentReader)
+		r.contentReader,
+		r.ctx.EdgeSymbols,
+		r.ctx.ParseCache)
 }
 
-func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
-	return nil
+func (r resolver) FinalizeGraph(graph moduleapi.Graph) error {
+	return linkExpectActualDeclarations(graph, r.expectActual)
 }
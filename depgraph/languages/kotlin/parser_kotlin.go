@@ -1,6 +1,7 @@
 package kotlin
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -36,7 +37,7 @@ func (s StandardLibraryImport) IsWildcard() bool {
 }
 
 func (s StandardLibraryImport) Package() string {
-	return extractPackageFromPath(s.path)
+	return importPackage(s.path, s.isWildcard)
 }
 
 // ExternalImport represents an external library import
@@ -54,7 +55,7 @@ func (e ExternalImport) IsWildcard() bool {
 }
 
 func (e ExternalImport) Package() string {
-	return extractPackageFromPath(e.path)
+	return importPackage(e.path, e.isWildcard)
 }
 
 // InternalImport represents an internal project import
@@ -72,7 +73,19 @@ func (i InternalImport) IsWildcard() bool {
 }
 
 func (i InternalImport) Package() string {
-	return extractPackageFromPath(i.path)
+	return importPackage(i.path, i.isWildcard)
+}
+
+// importPackage returns the package an import refers to. A non-wildcard import always
+// names a declaration, so its last segment is never part of the package, even when the
+// name has no case to tell it apart (e.g. CJK type names).
+func importPackage(importPath string, isWildcard bool) string {
+	if !isWildcard {
+		if idx := strings.LastIndex(importPath, "."); idx >= 0 {
+			importPath = importPath[:idx]
+		}
+	}
+	return extractPackageFromPath(importPath)
 }
 
 // extractPackageFromPath extracts the package name from an import path
@@ -81,10 +94,14 @@ func (i InternalImport) Package() string {
 func extractPackageFromPath(importPath string) string {
 	parts := strings.Split(importPath, ".")
 
-	// Find the last lowercase segment (package boundary)
-	// Class names typically start with uppercase in Kotlin
+	// Find the last segment that does not start with an uppercase letter (package boundary)
+	// Class names typically start with uppercase in Kotlin; package segments may use
+	// letters without case (e.g. CJK), so anything not uppercase counts as a package.
 	for i := len(parts) - 1; i >= 0; i-- {
-		if parts[i] != "" && len(parts[i]) > 0 && unicode.IsLower(rune(parts[i][0])) {
+		if parts[i] == "" {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(parts[i]); !unicode.IsUpper(r) {
 			return strings.Join(parts[:i+1], ".")
 		}
 	}
@@ -224,6 +241,7 @@ var (
 	kotlinCompiledTypeIdQuery      *sitter.Query
 	kotlinCompiledConstructorQuery *sitter.Query
 	kotlinCompiledSymbolQuery      *sitter.Query
+	kotlinCompiledMemberQuery      *sitter.Query
 )
 
 func ensureKotlinQueries() {
@@ -264,6 +282,11 @@ func ensureKotlinQueries() {
 		if err != nil {
 			panic(fmt.Sprintf("failed to compile kotlin symbol query: %v", err))
 		}
+
+		kotlinCompiledMemberQuery, err = sitter.NewQuery([]byte("(navigation_expression (simple_identifier) @owner.name (navigation_suffix (simple_identifier) @member.name))"), lang)
+		if err != nil {
+			panic(fmt.Sprintf("failed to compile kotlin member query: %v", err))
+		}
 	})
 }
 
@@ -322,7 +345,7 @@ func runQueryKotlinImports(rootNode *sitter.Node, sourceCode []byte, query *sitt
 	return imports, nil
 }
 
-var kotlinImportFullTextPattern = regexp.MustCompile(`import\s+([\w.*]+)(?:\s+as\s+\w+)?`)
+var kotlinImportFullTextPattern = regexp.MustCompile(`import\s+([\p{L}\p{N}_.*]+)(?:\s+as\s+[\p{L}\p{N}_]+)?`)
 
 // extractImportFromFullText extracts the import path from a full import statement
 func extractImportFromFullText(text string) string {
@@ -385,7 +408,7 @@ func runQueryPackageName(rootNode *sitter.Node, sourceCode []byte) (string, erro
 	return "", fmt.Errorf("no package name captured")
 }
 
-var kotlinPackagePattern = regexp.MustCompile(`package\s+([\w.]+)`)
+var kotlinPackagePattern = regexp.MustCompile(`package\s+([\p{L}\p{N}_.]+)`)
 
 // extractPackageWithRegex extracts package declaration using regex as fallback
 func extractPackageWithRegex(sourceCode []byte) string {
@@ -517,15 +540,132 @@ func ExtractTypeIdentifiers(sourceCode []byte) []string {
 		}
 	}
 
+	// Qualified member references such as Keys.KEY are kept as "Owner.member" so they
+	// can be matched against the companion members in the package type index.
+	memberCursor := sitter.NewQueryCursor()
+	defer memberCursor.Close()
+	memberCursor.Exec(kotlinCompiledMemberQuery, tree.RootNode())
+
+	for {
+		match, ok := memberCursor.NextMatch()
+		if !ok {
+			break
+		}
+		var owner, member string
+		for _, capture := range match.Captures {
+			switch kotlinCompiledMemberQuery.CaptureNameForId(capture.Index) {
+			case "owner.name":
+				owner = strings.TrimSpace(capture.Node.Content(sourceCode))
+			case "member.name":
+				member = strings.TrimSpace(capture.Node.Content(sourceCode))
+			}
+		}
+		if !isUpperCamelIdentifier(owner) || member == "" {
+			continue
+		}
+		name := owner + "." + member
+		if !seen[name] {
+			seen[name] = true
+			identifiers = append(identifiers, name)
+		}
+	}
+
 	return identifiers
 }
 
+// ExtractCompanionMemberNames returns the properties and functions declared in the
+// companion objects of top-level types, as "Owner.member". Kotlin code references them
+// through the owner (Keys.KEY) or imports them directly (import app.Keys.Companion.KEY).
+func ExtractCompanionMemberNames(sourceCode []byte) []string {
+	parser := kotlinParserPool.Get().(*sitter.Parser)
+	defer kotlinParserPool.Put(parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, sourceCode)
+	if err != nil {
+		return nil
+	}
+	defer tree.Close()
+
+	var names []string
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		decl := root.NamedChild(i)
+		switch decl.Type() {
+		case "class_declaration", "interface_declaration":
+		default:
+			continue
+		}
+		owner := extractDeclarationIdentifier(decl, sourceCode)
+		if owner == "" {
+			continue
+		}
+		for _, member := range companionMembers(decl, sourceCode) {
+			if member != "" {
+				names = append(names, owner+"."+member)
+			}
+		}
+	}
+	return names
+}
+
+// companionMembers returns the names of the properties and functions declared in the
+// companion object of a class declaration.
+func companionMembers(decl *sitter.Node, sourceCode []byte) []string {
+	var members []string
+	for i := 0; i < int(decl.NamedChildCount()); i++ {
+		body := decl.NamedChild(i)
+		if body.Type() != "class_body" {
+			continue
+		}
+		for j := 0; j < int(body.NamedChildCount()); j++ {
+			companion := body.NamedChild(j)
+			if companion.Type() != "companion_object" {
+				continue
+			}
+			for k := 0; k < int(companion.NamedChildCount()); k++ {
+				companionBody := companion.NamedChild(k)
+				if companionBody.Type() != "class_body" {
+					continue
+				}
+				for m := 0; m < int(companionBody.NamedChildCount()); m++ {
+					members = append(members, declaredMemberNames(companionBody.NamedChild(m), sourceCode)...)
+				}
+			}
+		}
+	}
+	return members
+}
+
+// declaredMemberNames returns the names a property or function declaration introduces.
+func declaredMemberNames(node *sitter.Node, sourceCode []byte) []string {
+	var names []string
+	switch node.Type() {
+	case "function_declaration":
+		names = append(names, extractDeclarationIdentifier(node, sourceCode))
+	case "property_declaration":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			switch child.Type() {
+			case "variable_declaration":
+				names = append(names, extractDeclarationIdentifier(child, sourceCode))
+			case "multi_variable_declaration":
+				for j := 0; j < int(child.NamedChildCount()); j++ {
+					names = append(names, extractDeclarationIdentifier(child.NamedChild(j), sourceCode))
+				}
+			}
+		}
+	}
+	return names
+}
+
+// isUpperCamelIdentifier reports whether name looks like a type name. Letters without
+// case (e.g. CJK) are accepted too, since such type names cannot be told apart by case.
 func isUpperCamelIdentifier(name string) bool {
 	if name == "" {
 		return false
 	}
 	r, _ := utf8.DecodeRuneInString(name)
-	return unicode.IsUpper(r)
+	return unicode.IsUpper(r) || (unicode.IsLetter(r) && !unicode.IsLower(r))
 }
 
 // isTopLevelDeclaration checks if a node is declared directly in the source file
@@ -553,3 +693,92 @@ func extractDeclarationIdentifier(node *sitter.Node, sourceCode []byte) string {
 	}
 	return ""
 }
+
+// PlatformModifier is the Kotlin Multiplatform modifier on a declaration.
+type PlatformModifier string
+
+const (
+	PlatformExpect PlatformModifier = "expect"
+	PlatformActual PlatformModifier = "actual"
+)
+
+// PlatformDeclaration is a top-level expect or actual declaration.
+type PlatformDeclaration struct {
+	Name     string
+	Modifier PlatformModifier
+}
+
+// ExtractPlatformDeclarations returns the top-level class, interface, object, typealias,
+// fun and val/var declarations marked with expect or actual.
+func ExtractPlatformDeclarations(sourceCode []byte) []PlatformDeclaration {
+	if !bytes.Contains(sourceCode, []byte("expect")) && !bytes.Contains(sourceCode, []byte("actual")) {
+		return nil
+	}
+
+	parser := kotlinParserPool.Get().(*sitter.Parser)
+	defer kotlinParserPool.Put(parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, sourceCode)
+	if err != nil {
+		return nil
+	}
+	defer tree.Close()
+
+	var declarations []PlatformDeclaration
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		node := root.NamedChild(i)
+
+		var name string
+		switch node.Type() {
+		case "class_declaration", "object_declaration", "interface_declaration", "type_alias", "function_declaration":
+			name = extractDeclarationIdentifier(node, sourceCode)
+		case "property_declaration":
+			name = extractPropertyIdentifier(node, sourceCode)
+		default:
+			continue
+		}
+
+		modifier := platformModifierOf(node, sourceCode)
+		if name == "" || modifier == "" {
+			continue
+		}
+		declarations = append(declarations, PlatformDeclaration{Name: name, Modifier: modifier})
+	}
+
+	return declarations
+}
+
+// platformModifierOf returns the expect/actual modifier declared directly on node.
+func platformModifierOf(node *sitter.Node, sourceCode []byte) PlatformModifier {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "modifiers" {
+			continue
+		}
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			modifier := child.NamedChild(j)
+			if modifier.Type() != "platform_modifier" {
+				continue
+			}
+			switch PlatformModifier(strings.TrimSpace(modifier.Content(sourceCode))) {
+			case PlatformExpect:
+				return PlatformExpect
+			case PlatformActual:
+				return PlatformActual
+			}
+		}
+	}
+	return ""
+}
+
+// extractPropertyIdentifier returns the name of a top-level val/var declaration.
+func extractPropertyIdentifier(node *sitter.Node, sourceCode []byte) string {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() == "variable_declaration" {
+			return extractDeclarationIdentifier(child, sourceCode)
+		}
+	}
+	return ""
+}
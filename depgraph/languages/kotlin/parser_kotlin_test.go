@@ -37,6 +37,25 @@ class MainActivity {
 	assert.Contains(t, paths, "com.example.models.User")
 }
 
+func TestParseKotlinImports_UnicodeIdentifiers(t *testing.T) {
+	source := []byte(`
+package com.例え.app
+
+import com.例え.モデル.Ünit
+import com.例え.モデル.Größe as Size
+import com.例え.サービス.*
+`)
+
+	imports, err := ParseKotlinImports(source)
+
+	require.NoError(t, err)
+	paths := make([]string, len(imports))
+	for i, imp := range imports {
+		paths[i] = imp.Path()
+	}
+	assert.Equal(t, []string{"com.例え.モデル.Ünit", "com.例え.モデル.Größe", "com.例え.サービス"}, paths)
+}
+
 func TestParseKotlinImports_WildcardImports(t *testing.T) {
 	source := []byte(`
 package com.example.app
@@ -191,6 +210,13 @@ import kotlin.collections.List
 class User`,
 			expected: "com.example.app.models",
 		},
+		{
+			name: "non-ASCII package",
+			source: `package com.例え.モデル
+
+class Ünit`,
+			expected: "com.例え.モデル",
+		},
 		{
 			name: "no package",
 			source: `import kotlin.collections.List
@@ -239,6 +265,16 @@ func TestExtractPackageFromPath(t *testing.T) {
 			importPath: "kotlin",
 			expected:   "kotlin",
 		},
+		{
+			name:       "non-ASCII class name",
+			importPath: "com.example.units.Ünit",
+			expected:   "com.example.units",
+		},
+		{
+			name:       "package segment without case",
+			importPath: "com.例え.モデル.Ünit",
+			expected:   "com.例え.モデル",
+		},
 	}
 
 	for _, tt := range tests {
@@ -268,6 +304,12 @@ func TestKotlinImportPackageMethod(t *testing.T) {
 			isWildcard: false,
 			expected:   "com.example.models",
 		},
+		{
+			name:       "class import without case",
+			importPath: "com.例え.モデル.クラス",
+			isWildcard: false,
+			expected:   "com.例え.モデル",
+		},
 	}
 
 	for _, tt := range tests {
@@ -374,6 +416,23 @@ fun demo(request: ActivateLicenseRequest): ActivateLicenseResponse {
 	assert.Contains(t, identifiers, "Machine")
 }
 
+func TestExtractTypeIdentifiers_UnicodeTypeNames(t *testing.T) {
+	source := []byte(`
+package com.例え.モデル
+
+fun demo(unit: Ünit): Größe {
+  val 値 = クラス()
+  return Größe(unit)
+}
+`)
+
+	identifiers := ExtractTypeIdentifiers(source)
+	assert.Contains(t, identifiers, "Ünit")
+	assert.Contains(t, identifiers, "Größe")
+	assert.Contains(t, identifiers, "クラス")
+	assert.NotContains(t, identifiers, "demo")
+}
+
 func TestExtractTypeIdentifiers_ConstructorInvocation(t *testing.T) {
 	source := []byte(`
 package com.example
@@ -405,3 +464,80 @@ fun demo() {
 	assert.Contains(t, identifiers, "DarwinFormatter")
 	assert.NotContains(t, identifiers, "println")
 }
+
+func TestExtractTypeIdentifiers_QualifiedMemberReference(t *testing.T) {
+	source := []byte(`
+package com.example
+
+fun demo() = Keys.KEY + config.value
+`)
+
+	identifiers := ExtractTypeIdentifiers(source)
+	assert.Contains(t, identifiers, "Keys")
+	assert.Contains(t, identifiers, "Keys.KEY")
+	assert.NotContains(t, identifiers, "config.value")
+}
+
+func TestExtractCompanionMemberNames(t *testing.T) {
+	source := []byte(`
+package com.example
+
+class Keys {
+  val instanceKey = "instance"
+
+  companion object Factory {
+    const val KEY = "key"
+    val (first, second) = 1 to 2
+    fun make(): Keys = Keys()
+  }
+}
+
+object Registry {
+  val entries = listOf<String>()
+}
+`)
+
+	assert.Equal(t, []string{"Keys.KEY", "Keys.first", "Keys.second", "Keys.make"}, ExtractCompanionMemberNames(source))
+}
+
+func TestExtractPlatformDeclarations(t *testing.T) {
+	source := `package com.example
+
+expect class PlatformClock {
+    fun now(): Long
+}
+actual class Widget actual constructor()
+public expect abstract class Base
+expect interface Logger
+expect object Platform
+actual typealias Lock = java.util.concurrent.locks.ReentrantLock
+expect fun platformName(): String
+actual val isDebug: Boolean = false
+class Regular {
+    actual fun nested() {}
+}
+fun regularFun() {}
+`
+
+	declarations := ExtractPlatformDeclarations([]byte(source))
+
+	assert.Equal(t, []PlatformDeclaration{
+		{Name: "PlatformClock", Modifier: PlatformExpect},
+		{Name: "Widget", Modifier: PlatformActual},
+		{Name: "Base", Modifier: PlatformExpect},
+		{Name: "Logger", Modifier: PlatformExpect},
+		{Name: "Platform", Modifier: PlatformExpect},
+		{Name: "Lock", Modifier: PlatformActual},
+		{Name: "platformName", Modifier: PlatformExpect},
+		{Name: "isDebug", Modifier: PlatformActual},
+	}, declarations)
+}
+
+func TestExtractPlatformDeclarations_NoPlatformModifiers(t *testing.T) {
+	source := `package com.example
+
+class Regular
+`
+
+	assert.Empty(t, ExtractPlatformDeclarations([]byte(source)))
+}
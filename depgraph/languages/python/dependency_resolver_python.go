@@ -3,31 +3,42 @@ package python
 import (
 	"fmt"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// importPathsCacheKind names the import paths ResolvePythonProjectImports caches per
+// file.
+const importPathsCacheKind = "python-import-paths/v1"
+
 func ResolvePythonProjectImports(
 	absPath string,
 	filePath string,
 	ext string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	importPaths, err := moduleapi.ParseFile(cache, contentReader, importPathsCacheKind, absPath, func(content []byte) ([]string, error) {
+		imports, parseErr := ParsePythonImports(content)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		}
+		paths := make([]string, 0, len(imports))
+		for _, imp := range imports {
+			paths = append(paths, imp.Path())
+		}
+		return paths, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
-
-	imports, parseErr := ParsePythonImports(content)
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		return nil, err
 	}
 
 	var projectImports []string
-	for _, imp := range imports {
-		resolvedFiles := ResolvePythonImportPath(absPath, imp.Path(), suppliedFiles)
+	for _, importPath := range importPaths {
+		resolvedFiles := ResolvePythonImportPath(absPath, importPath, suppliedFiles)
 		projectImports = append(projectImports, resolvedFiles...)
-		resolvedFiles = ResolvePythonAbsoluteImportPath(imp.Path(), suppliedFiles)
+		resolvedFiles = ResolvePythonAbsoluteImportPath(importPath, suppliedFiles)
 		projectImports = append(projectImports, resolvedFiles...)
 	}
 
@@ -15,6 +15,10 @@ func (Module) Extensions() []string {
 	return []string{".py"}
 }
 
+func (Module) Interpreters() []string {
+	return []string{"python", "python2", "python3"}
+}
+
 func (Module) Maturity() moduleapi.MaturityLevel {
 	return moduleapi.MaturityBasicTests
 }
@@ -33,7 +37,7 @@ type resolver struct {
 }
 
 func (r resolver) ResolveProjectImports(absPath, filePath, ext string) ([]string, error) {
-	return ResolvePythonProjectImports(absPath, filePath, ext, r.ctx.SuppliedFiles, r.contentReader)
+	return ResolvePythonProjectImports(absPath, filePath, ext, r.ctx.SuppliedFiles, r.contentReader, r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
@@ -4,29 +4,60 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// referencesCacheKind names the rubyReferences ResolveRubyProjectImports caches per
+// file.
+const referencesCacheKind = "ruby-references/v1"
+
+// rubyReferences is what ResolveRubyProjectImports needs from a file's content.
+type rubyReferences struct {
+	Requires     []rubyRequire `json:"requires,omitempty"`
+	ConstantRefs []string      `json:"constantRefs,omitempty"`
+}
+
+type rubyRequire struct {
+	Path     string `json:"path"`
+	Relative bool   `json:"relative,omitempty"`
+}
+
+func parseRubyReferences(content []byte) (rubyReferences, error) {
+	imports, err := ParseRubyImports(content)
+	if err != nil {
+		return rubyReferences{}, err
+	}
+	refs := rubyReferences{ConstantRefs: ParseRubyConstantReferences(content)}
+	for _, imp := range imports {
+		refs.Requires = append(refs.Requires, rubyRequire{Path: imp.Path(), Relative: imp.IsRelative()})
+	}
+	return refs, nil
+}
+
 func ResolveRubyProjectImports(
 	absPath string,
 	filePath string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	refs, err := moduleapi.ParseFile(cache, contentReader, referencesCacheKind, absPath, func(content []byte) (rubyReferences, error) {
+		refs, parseErr := parseRubyReferences(content)
+		if parseErr != nil {
+			return rubyReferences{}, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		}
+		return refs, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
-
-	imports, parseErr := ParseRubyImports(content)
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		return nil, err
 	}
 
 	var projectImports []string
 	seen := make(map[string]struct{})
 
-	for _, imp := range imports {
+	for _, require := range refs.Requires {
+		imp := RubyImport{path: require.Path, isRelative: require.Relative}
 		resolvedFiles := ResolveRubyImportPath(absPath, imp, suppliedFiles)
 		for _, file := range resolvedFiles {
 			if file == absPath {
@@ -40,7 +71,7 @@ func ResolveRubyProjectImports(
 		}
 	}
 
-	constantRefs := ParseRubyConstantReferences(content)
+	constantRefs := refs.ConstantRefs
 	if len(constantRefs) > 0 {
 		pathComponentsCache := make(map[string][]string, len(suppliedFiles))
 		for fp, exists := range suppliedFiles {
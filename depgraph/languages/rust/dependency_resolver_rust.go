@@ -7,12 +7,18 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// importsCacheKind names the imports ProjectImportResolver caches per file in its
+// ParseCache.
+const importsCacheKind = "rust-imports/v1"
+
 type ProjectImportResolver struct {
 	suppliedFiles map[string]bool
 	contentReader vcs.ContentReader
+	parseCache    moduleapi.ParseCache
 
 	crateRootCache     sync.Map // directory path -> crate root (or "")
 	crateNameCache     sync.Map // crate root -> map[string]bool
@@ -21,10 +27,11 @@ type ProjectImportResolver struct {
 	importsCache       sync.Map // file path -> []RustImport
 }
 
-func NewProjectImportResolver(suppliedFiles map[string]bool, contentReader vcs.ContentReader) *ProjectImportResolver {
+func NewProjectImportResolver(suppliedFiles map[string]bool, contentReader vcs.ContentReader, parseCache moduleapi.ParseCache) *ProjectImportResolver {
 	return &ProjectImportResolver{
 		suppliedFiles: suppliedFiles,
 		contentReader: contentReader,
+		parseCache:    parseCache,
 	}
 }
 
@@ -55,17 +62,25 @@ func ResolveRustProjectImports(
 	filePath string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	resolver := NewProjectImportResolver(suppliedFiles, contentReader)
+	resolver := NewProjectImportResolver(suppliedFiles, contentReader, cache)
 	return resolver.ResolveProjectImports(absPath, filePath)
 }
 
+// resolveRustModDecl resolves `mod name;` to name.rs or name/mod.rs in the declaring
+// module's directory: the file's own directory for mod.rs, lib.rs and main.rs, and a
+// directory named after the file otherwise, so `mod http;` in src/net.rs is
+// src/net/http.rs.
 func resolveRustModDecl(sourceFile, moduleName string, suppliedFiles map[string]bool) []string {
 	if moduleName == "" {
 		return nil
 	}
 
 	sourceDir := filepath.Dir(sourceFile)
+	if !isRustDirectoryModuleFile(sourceFile) {
+		sourceDir = filepath.Join(sourceDir, strings.TrimSuffix(filepath.Base(sourceFile), ".rs"))
+	}
 	candidates := []string{
 		filepath.Join(sourceDir, moduleName+".rs"),
 		filepath.Join(sourceDir, moduleName, "mod.rs"),
@@ -261,13 +276,11 @@ func (r *ProjectImportResolver) findRustCrateRoot(sourceFile string) (string, bo
 			}
 			return current, true
 		}
-		if r.contentReader != nil {
-			if _, err := r.contentReader(candidate); err == nil {
-				for _, d := range visited {
-					r.crateRootCache.Store(d, current)
-				}
-				return current, true
+		if r.contentReader != nil && r.contentReader.Exists(candidate) {
+			for _, d := range visited {
+				r.crateRootCache.Store(d, current)
 			}
+			return current, true
 		}
 
 		parent := filepath.Dir(current)
@@ -294,7 +307,7 @@ func (r *ProjectImportResolver) isLocalRustCrateImport(firstSegment, crateRoot s
 	names := make(map[string]bool)
 	cargoToml := filepath.Join(crateRoot, "Cargo.toml")
 	if r.contentReader != nil {
-		if content, err := r.contentReader(cargoToml); err == nil {
+		if content, err := r.contentReader.ReadFile(cargoToml); err == nil {
 			names = parseRustCrateNamesFromCargoToml(string(content))
 		}
 	}
@@ -324,7 +337,7 @@ func (r *ProjectImportResolver) dependencyCrateRoots(crateRoot string) map[strin
 	}
 
 	cargoTomlPath := filepath.Join(crateRoot, "Cargo.toml")
-	content, err := r.contentReader(cargoTomlPath)
+	content, err := r.contentReader.ReadFile(cargoTomlPath)
 	if err != nil {
 		r.depCrateRootsCache.Store(crateRoot, result)
 		return result
@@ -344,7 +357,7 @@ func (r *ProjectImportResolver) dependencyCrateRoots(crateRoot string) map[strin
 		}
 
 		depCargoTomlPath := filepath.Join(depRoot, "Cargo.toml")
-		depContent, depErr := r.contentReader(depCargoTomlPath)
+		depContent, depErr := r.contentReader.ReadFile(depCargoTomlPath)
 		if depErr != nil {
 			continue
 		}
@@ -411,13 +424,9 @@ func (r *ProjectImportResolver) importsForFile(path string) ([]RustImport, error
 		return nil, fmt.Errorf("content reader is required")
 	}
 
-	content, err := r.contentReader(path)
+	imports, err := moduleapi.ParseFile(r.parseCache, r.contentReader, importsCacheKind, path, ParseRustImports)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", path, err)
-	}
-	imports, parseErr := ParseRustImports(content)
-	if parseErr != nil {
-		return nil, parseErr
+		return nil, err
 	}
 
 	r.importsCache.Store(path, imports)
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/LegacyCodeHQ/clarity/vcs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -29,7 +30,7 @@ func TestResolveRustProjectImports_ModDecl(t *testing.T) {
 		fooFile:   true,
 	}
 
-	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, fooFile)
 }
@@ -54,7 +55,7 @@ func TestResolveRustProjectImports_UseCratePath(t *testing.T) {
 		fooFile:   true,
 	}
 
-	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, fooFile)
 }
@@ -78,7 +79,7 @@ func TestResolveRustProjectImports_UseCratePathWithoutSuppliedCargoToml(t *testi
 		fooFile: true,
 	}
 
-	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, fooFile)
 }
@@ -103,7 +104,7 @@ func TestResolveRustProjectImports_UseLocalCrateNamePathResolvesToLib(t *testing
 		libFile:   true,
 	}
 
-	imports, err := ResolveRustProjectImports(mainFile, mainFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(mainFile, mainFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, libFile)
 }
@@ -132,7 +133,7 @@ func TestResolveRustProjectImports_UsePathThroughModRs(t *testing.T) {
 		barFile:   true,
 	}
 
-	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, barFile)
 	assert.NotContains(t, imports, modFile)
@@ -169,7 +170,7 @@ func TestResolveRustProjectImports_UsePathDoesNotExpandParentMod(t *testing.T) {
 		entityFile:      true,
 	}
 
-	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, constraintsFile)
 	assert.NotContains(t, imports, entityFile)
@@ -200,7 +201,7 @@ func TestResolveRustProjectImports_DoesNotReturnSelfDependency(t *testing.T) {
 		astgrepFile: true,
 	}
 
-	imports, err := ResolveRustProjectImports(astgrepFile, astgrepFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(astgrepFile, astgrepFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.NotContains(t, imports, astgrepFile)
 }
@@ -253,7 +254,7 @@ version = "0.1.0"
 		crateBFoo:   true,
 	}
 
-	imports, err := ResolveRustProjectImports(crateAMain, crateAMain, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(crateAMain, crateAMain, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, crateBFoo)
 }
@@ -287,7 +288,7 @@ func TestResolveRustProjectImports_UseSiblingSubmoduleFromNonModFile(t *testing.
 		childFile: true,
 	}
 
-	imports, err := ResolveRustProjectImports(appFile, appFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(appFile, appFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, childFile, "pub use clarity_desktop::run_app from app.rs should resolve to app/clarity_desktop.rs")
 }
@@ -319,7 +320,7 @@ func TestResolveRustProjectImports_UseSuperFromNonModFile(t *testing.T) {
 		realFsFile:  true,
 	}
 
-	imports, err := ResolveRustProjectImports(realFsFile, realFsFile, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(realFsFile, realFsFile, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, traitFsFile, "use super::trait_fs::Fs from real_fs.rs should resolve to trait_fs.rs")
 }
@@ -370,7 +371,89 @@ version = "0.1.0"
 		crateBFoo:   true,
 	}
 
-	imports, err := ResolveRustProjectImports(crateAMain, crateAMain, supplied, os.ReadFile)
+	imports, err := ResolveRustProjectImports(crateAMain, crateAMain, supplied, vcs.FilesystemContentReader(), nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, crateBFoo)
 }
+
+func TestResolveRustProjectImports_NestedModDeclsAndCratePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	crateRoot := filepath.Join(tmpDir, "mycrate")
+	srcDir := filepath.Join(crateRoot, "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "net", "http"), 0755))
+
+	cargoToml := filepath.Join(crateRoot, "Cargo.toml")
+	libFile := filepath.Join(srcDir, "lib.rs")
+	netFile := filepath.Join(srcDir, "net.rs")
+	httpModFile := filepath.Join(srcDir, "net", "http", "mod.rs")
+	clientFile := filepath.Join(srcDir, "net", "http", "client.rs")
+
+	require.NoError(t, os.WriteFile(cargoToml, []byte("[package]\nname = \"mycrate\"\n"), 0644))
+	require.NoError(t, os.WriteFile(libFile, []byte("mod net;\nuse crate::net::http::client::Client;\n"), 0644))
+	require.NoError(t, os.WriteFile(netFile, []byte("pub mod http;\n"), 0644))
+	require.NoError(t, os.WriteFile(httpModFile, []byte("pub mod client;\n"), 0644))
+	require.NoError(t, os.WriteFile(clientFile, []byte("pub struct Client;\n"), 0644))
+
+	supplied := map[string]bool{
+		cargoToml:   true,
+		libFile:     true,
+		netFile:     true,
+		httpModFile: true,
+		clientFile:  true,
+	}
+	reader := vcs.FilesystemContentReader()
+
+	imports, err := ResolveRustProjectImports(libFile, libFile, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.Contains(t, imports, netFile)
+	assert.Contains(t, imports, clientFile)
+
+	imports, err = ResolveRustProjectImports(netFile, netFile, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{httpModFile}, imports)
+
+	imports, err = ResolveRustProjectImports(httpModFile, httpModFile, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{clientFile}, imports)
+}
+
+// Workspace members only see each other through path dependencies; without one, a
+// path naming another member is an external crate, like any crates.io dependency.
+func TestResolveRustProjectImports_WorkspaceMemberWithoutPathDependencyIsExternal(t *testing.T) {
+	tmpDir := t.TempDir()
+	workspaceRoot := filepath.Join(tmpDir, "workspace")
+	crateASrc := filepath.Join(workspaceRoot, "crate-a", "src")
+	crateBSrc := filepath.Join(workspaceRoot, "crate-b", "src")
+	require.NoError(t, os.MkdirAll(crateASrc, 0755))
+	require.NoError(t, os.MkdirAll(crateBSrc, 0755))
+
+	workspaceCargo := filepath.Join(workspaceRoot, "Cargo.toml")
+	crateACargo := filepath.Join(workspaceRoot, "crate-a", "Cargo.toml")
+	crateALib := filepath.Join(crateASrc, "lib.rs")
+	crateAUtil := filepath.Join(crateASrc, "util.rs")
+	crateBCargo := filepath.Join(workspaceRoot, "crate-b", "Cargo.toml")
+	crateBLib := filepath.Join(crateBSrc, "lib.rs")
+	crateBUtil := filepath.Join(crateBSrc, "util.rs")
+
+	require.NoError(t, os.WriteFile(workspaceCargo, []byte("[workspace]\nmembers = [\"crate-a\", \"crate-b\"]\n"), 0644))
+	require.NoError(t, os.WriteFile(crateACargo, []byte("[package]\nname = \"crate-a\"\n\n[dependencies]\nserde = \"1\"\n"), 0644))
+	require.NoError(t, os.WriteFile(crateALib, []byte("mod util;\nuse crate_b::util::helper;\nuse serde::Serialize;\n"), 0644))
+	require.NoError(t, os.WriteFile(crateAUtil, []byte("pub fn local() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(crateBCargo, []byte("[package]\nname = \"crate-b\"\n"), 0644))
+	require.NoError(t, os.WriteFile(crateBLib, []byte("pub mod util;\n"), 0644))
+	require.NoError(t, os.WriteFile(crateBUtil, []byte("pub fn helper() {}\n"), 0644))
+
+	supplied := map[string]bool{
+		workspaceCargo: true,
+		crateACargo:    true,
+		crateALib:      true,
+		crateAUtil:     true,
+		crateBCargo:    true,
+		crateBLib:      true,
+		crateBUtil:     true,
+	}
+
+	imports, err := ResolveRustProjectImports(crateALib, crateALib, supplied, vcs.FilesystemContentReader(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{crateAUtil}, imports)
+}
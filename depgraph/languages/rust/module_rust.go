@@ -23,7 +23,7 @@ func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReade
 	return resolver{
 		ctx:             ctx,
 		contentReader:   contentReader,
-		projectResolver: NewProjectImportResolver(ctx.SuppliedFiles, contentReader),
+		projectResolver: NewProjectImportResolver(ctx.SuppliedFiles, contentReader, ctx.ParseCache),
 	}
 }
 
@@ -41,7 +41,7 @@ func (r resolver) ResolveProjectImports(absPath, filePath, ext string) ([]string
 	if r.projectResolver != nil {
 		return r.projectResolver.ResolveProjectImports(absPath, filePath)
 	}
-	return ResolveRustProjectImports(absPath, filePath, r.ctx.SuppliedFiles, r.contentReader)
+	return ResolveRustProjectImports(absPath, filePath, r.ctx.SuppliedFiles, r.contentReader, r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
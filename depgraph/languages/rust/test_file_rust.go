@@ -34,7 +34,7 @@ func IsTestFileWithContent(filePath string, contentReader vcs.ContentReader) boo
 		return true
 	}
 
-	content, err := contentReader(filePath)
+	content, err := contentReader.ReadFile(filePath)
 	if err != nil {
 		return true
 	}
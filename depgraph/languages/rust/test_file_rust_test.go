@@ -1,6 +1,10 @@
 package rust
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+)
 
 func TestIsTestFileWithContent(t *testing.T) {
 	tests := []struct {
@@ -37,12 +41,7 @@ func TestIsTestFileWithContent(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			contentReader := func(path string) ([]byte, error) {
-				if path != tc.filePath {
-					t.Fatalf("unexpected content read for %s", path)
-				}
-				return []byte(tc.content), nil
-			}
+			contentReader := testhelpers.MapContentReader(map[string]string{tc.filePath: tc.content})
 
 			got := IsTestFileWithContent(tc.filePath, contentReader)
 			if got != tc.want {
@@ -1,17 +1,40 @@
 package scala
 
 import (
-	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// fileFactsCacheKind names the scalaFileFacts the resolver caches per file.
+const fileFactsCacheKind = "scala-file-facts/v1"
+
+// scalaFileFacts is what indexing and resolving need from a Scala file's content.
+type scalaFileFacts struct {
+	Package         string           `json:"package,omitempty"`
+	DeclaredTypes   []string         `json:"declaredTypes,omitempty"`
+	IsPackageObject bool             `json:"isPackageObject,omitempty"`
+	Imports         scalaImportDecls `json:"imports"`
+	TypeReferences  []string         `json:"typeReferences,omitempty"`
+}
+
+func parseScalaFileFacts(content []byte) (scalaFileFacts, error) {
+	return scalaFileFacts{
+		Package:         ParsePackageDeclaration(content),
+		DeclaredTypes:   ParseTopLevelTypeNames(content),
+		IsPackageObject: IsPackageObject(content),
+		Imports:         parseScalaImportDecls(content),
+		TypeReferences:  ExtractTypeIdentifiers(content),
+	}, nil
+}
+
 // BuildScalaIndices builds package and type indices for supplied Scala files.
 func BuildScalaIndices(
 	scalaFiles []string,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) (map[string][]string, map[string]map[string][]string, map[string]string) {
 	if len(scalaFiles) == 0 {
 		return nil, nil, make(map[string]string)
@@ -27,12 +50,12 @@ func BuildScalaIndices(
 			continue
 		}
 
-		content, err := contentReader(absPath)
+		facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, absPath, parseScalaFileFacts)
 		if err != nil {
 			continue
 		}
 
-		pkg := ParsePackageDeclaration(content)
+		pkg := facts.Package
 		if pkg == "" {
 			continue
 		}
@@ -40,8 +63,8 @@ func BuildScalaIndices(
 
 		packageToFiles[pkg] = append(packageToFiles[pkg], absPath)
 
-		declaredTypes := ParseTopLevelTypeNames(content)
-		if IsPackageObject(content) {
+		declaredTypes := facts.DeclaredTypes
+		if facts.IsPackageObject {
 			declaredTypes = append(declaredTypes, packageObjectTypeName)
 		}
 		if len(declaredTypes) == 0 {
@@ -73,10 +96,11 @@ func ResolveScalaProjectImports(
 	scalaFilePackages map[string]string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, absPath, parseScalaFileFacts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+		return nil, err
 	}
 
 	projectPackages := make(map[string]bool, len(scalaPackageIndex))
@@ -84,10 +108,10 @@ func ResolveScalaProjectImports(
 		projectPackages[pkg] = true
 	}
 
-	imports := ParseScalaImports(content, projectPackages)
-	typeReferences := ExtractTypeIdentifiers(content)
+	imports := classifyScalaImports(facts.Imports, projectPackages)
+	typeReferences := facts.TypeReferences
 	declaredNames := make(map[string]bool)
-	for _, name := range ParseTopLevelTypeNames(content) {
+	for _, name := range facts.DeclaredTypes {
 		if name != "" {
 			declaredNames[name] = true
 		}
@@ -110,7 +134,7 @@ func ResolveScalaProjectImports(
 
 	samePackageDeps := resolveScalaSamePackageDependencies(
 		absPath,
-		content,
+		facts,
 		scalaFilePackages,
 		scalaPackageTypes,
 		imports,
@@ -174,7 +198,7 @@ func resolveScalaImportPath(
 
 func resolveScalaSamePackageDependencies(
 	sourceFile string,
-	sourceContent []byte,
+	sourceFacts scalaFileFacts,
 	filePackages map[string]string,
 	packageTypeIndex map[string]map[string][]string,
 	imports []ScalaImport,
@@ -190,7 +214,7 @@ func resolveScalaSamePackageDependencies(
 		return []string{}
 	}
 
-	typeReferences := ExtractTypeIdentifiers(sourceContent)
+	typeReferences := sourceFacts.TypeReferences
 	if len(typeReferences) == 0 {
 		return []string{}
 	}
@@ -207,7 +231,7 @@ func resolveScalaSamePackageDependencies(
 	}
 
 	declaredNames := make(map[string]bool)
-	for _, name := range ParseTopLevelTypeNames(sourceContent) {
+	for _, name := range sourceFacts.DeclaredTypes {
 		if name != "" {
 			declaredNames[name] = true
 		}
@@ -33,13 +33,13 @@ object Helper
 
 	reader := vcs.FilesystemContentReader()
 	indicesFiles := []string{appPath, helperPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(indicesFiles, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(indicesFiles, reader, nil)
 	supplied := map[string]bool{
 		appPath:    true,
 		helperPath: true,
 	}
 
-	imports, err := ResolveScalaProjectImports(appPath, appPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(appPath, appPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.Equal(t, []string{helperPath}, imports)
 }
@@ -68,13 +68,13 @@ class PaymentMethod
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{cartPath, paymentPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		cartPath:    true,
 		paymentPath: true,
 	}
 
-	imports, err := ResolveScalaProjectImports(cartPath, cartPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(cartPath, cartPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, paymentPath)
 }
@@ -113,14 +113,14 @@ object KernelCheck
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{sortedMapPath, kernelPackagePath, lawTestsPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		sortedMapPath:     true,
 		kernelPackagePath: true,
 		lawTestsPath:      true,
 	}
 
-	imports, err := ResolveScalaProjectImports(sortedMapPath, sortedMapPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(sortedMapPath, sortedMapPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.NotContains(t, imports, lawTestsPath, "production file should not depend on split-package test file")
 }
@@ -160,7 +160,7 @@ final case class NonEmptyLazyList[A](value: LazyList[A])
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{sortedMapPath, nonEmptyListPath, nonEmptyVectorPath, nonEmptyLazyListPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		sortedMapPath:        true,
 		nonEmptyListPath:     true,
@@ -168,7 +168,7 @@ final case class NonEmptyLazyList[A](value: LazyList[A])
 		nonEmptyLazyListPath: true,
 	}
 
-	imports, err := ResolveScalaProjectImports(sortedMapPath, sortedMapPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(sortedMapPath, sortedMapPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.NotContains(t, imports, nonEmptyListPath, "should not link unrelated cats.data file on unresolved symbol")
 	assert.NotContains(t, imports, nonEmptyVectorPath, "should not link unrelated cats.data file on unresolved symbol")
@@ -199,13 +199,13 @@ trait SortedMapInstances
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{nonEmptyListPath, sortedMapPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		nonEmptyListPath: true,
 		sortedMapPath:    true,
 	}
 
-	imports, err := ResolveScalaProjectImports(nonEmptyListPath, nonEmptyListPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(nonEmptyListPath, nonEmptyListPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.NotContains(t, imports, sortedMapPath, "should not link arbitrary package peer when imported symbol cannot be resolved")
 }
@@ -233,13 +233,13 @@ trait SortedMapInstances
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{sortedMapPath, packageObjectPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		sortedMapPath:     true,
 		packageObjectPath: true,
 	}
 
-	imports, err := ResolveScalaProjectImports(sortedMapPath, sortedMapPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(sortedMapPath, sortedMapPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, packageObjectPath, "wildcard cats import should resolve to package object cats")
 }
@@ -271,13 +271,13 @@ trait OrderTests[A] {
 
 	reader := vcs.FilesystemContentReader()
 	files := []string{orderLawsPath, orderTestsPath}
-	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader)
+	pkgIndex, typeIndex, filePackages := BuildScalaIndices(files, reader, nil)
 	supplied := map[string]bool{
 		orderLawsPath:  true,
 		orderTestsPath: true,
 	}
 
-	imports, err := ResolveScalaProjectImports(orderTestsPath, orderTestsPath, pkgIndex, typeIndex, filePackages, supplied, reader)
+	imports, err := ResolveScalaProjectImports(orderTestsPath, orderTestsPath, pkgIndex, typeIndex, filePackages, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.Contains(t, imports, orderLawsPath, "reference to parent-package type should resolve")
 }
@@ -29,7 +29,7 @@ func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReade
 		}
 	}
 
-	packageIndex, packageTypes, filePackages := BuildScalaIndices(scalaFiles, contentReader)
+	packageIndex, packageTypes, filePackages := BuildScalaIndices(scalaFiles, contentReader, ctx.ParseCache)
 	return resolver{
 		ctx:           ctx,
 		contentReader: contentReader,
@@ -59,7 +59,8 @@ func (r resolver) ResolveProjectImports(absPath, filePath, _ string) ([]string,
 		r.packageTypes,
 		r.filePackages,
 		r.ctx.SuppliedFiles,
-		r.contentReader)
+		r.contentReader,
+		r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
@@ -215,31 +215,108 @@ func ParseTopLevelTypeNames(sourceCode []byte) []string {
 
 // ParseScalaImports parses Scala source code and classifies imports.
 func ParseScalaImports(sourceCode []byte, projectPackages map[string]bool) []ScalaImport {
+	return classifyScalaImports(parseScalaImportDecls(sourceCode), projectPackages)
+}
+
+// scalaImportDecls are a file's import paths before they are qualified and classified
+// against the project's packages, with the packages enclosing them.
+type scalaImportDecls struct {
+	Paths     []string `json:"paths,omitempty"`
+	Enclosing []string `json:"enclosing,omitempty"`
+}
+
+func parseScalaImportDecls(sourceCode []byte) scalaImportDecls {
 	tree, err := parseScala(sourceCode)
 	if err != nil {
-		return []ScalaImport{}
+		return scalaImportDecls{}
 	}
 	defer tree.Close()
 
 	importDecls := findNodesOfType(tree.RootNode(), "import_declaration")
 	if len(importDecls) == 0 {
-		return []ScalaImport{}
+		return scalaImportDecls{}
 	}
 
-	imports := []ScalaImport{}
+	decls := scalaImportDecls{Enclosing: enclosingPackages(tree.RootNode(), sourceCode)}
 	for _, node := range importDecls {
-		paths := extractImportPaths(node, sourceCode)
-		for _, path := range paths {
-			if path == "" {
-				continue
+		for _, path := range extractImportPaths(node, sourceCode) {
+			if path != "" {
+				decls.Paths = append(decls.Paths, path)
 			}
-			imports = append(imports, classifyScalaImport(path, projectPackages))
 		}
 	}
+	return decls
+}
 
+func classifyScalaImports(decls scalaImportDecls, projectPackages map[string]bool) []ScalaImport {
+	imports := []ScalaImport{}
+	for _, path := range decls.Paths {
+		path = qualifyRelativeImport(path, decls.Enclosing, projectPackages)
+		imports = append(imports, classifyScalaImport(path, projectPackages))
+	}
 	return imports
 }
 
+// enclosingPackages returns the packages whose members are in scope at the top level,
+// innermost first. Each package clause opens a new scope, so
+//
+//	package com.acme
+//	package service
+//
+// makes both com.acme.service and com.acme visible, while a single
+// "package com.acme.service" clause makes only com.acme.service visible.
+func enclosingPackages(root *sitter.Node, sourceCode []byte) []string {
+	var scopes []string
+	current := ""
+	seenPackageClause := false
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		child := root.NamedChild(i)
+		if child == nil || child.Type() == "comment" {
+			continue
+		}
+		if child.Type() != "package_clause" {
+			if seenPackageClause {
+				break
+			}
+			continue
+		}
+		seenPackageClause = true
+
+		pkg := findFirstNodeOfType(child, "package_identifier")
+		if pkg == nil {
+			continue
+		}
+		name := strings.TrimSpace(pkg.Content(sourceCode))
+		if name == "" {
+			continue
+		}
+		if current != "" {
+			name = current + "." + name
+		}
+		current = name
+		scopes = append([]string{current}, scopes...)
+	}
+	return scopes
+}
+
+// qualifyRelativeImport rewrites an import relative to an enclosing package (e.g.
+// "service.Billing" inside package com.acme) to its fully qualified form when that
+// names a project package. Nested scopes shadow the root, matching Scala's lookup order;
+// a _root_ prefix forces the absolute reading.
+func qualifyRelativeImport(path string, enclosing []string, projectPackages map[string]bool) string {
+	if strings.HasPrefix(path, "_root_.") {
+		return strings.TrimPrefix(path, "_root_.")
+	}
+
+	for _, pkg := range enclosing {
+		candidate := pkg + "." + path
+		if projectPackages[scalaImportPackage(candidate)] || projectPackages[strings.TrimSuffix(candidate, "._")] {
+			return candidate
+		}
+	}
+	return path
+}
+
 func classifyScalaImport(importPath string, projectPackages map[string]bool) ScalaImport {
 	isWildcard := strings.HasSuffix(importPath, "._")
 	if isStandardLibraryImport(importPath) {
@@ -111,3 +111,81 @@ class Cart {
 	assert.Contains(t, identifiers, "PaymentMethod")
 	assert.NotContains(t, identifiers, "Helper")
 }
+
+func TestParseScalaImports_BraceSelectors(t *testing.T) {
+	src := []byte(`package com.acme.app
+
+import com.acme.{Foo, Bar => Baz}
+`)
+	imports := ParseScalaImports(src, map[string]bool{"com.acme": true})
+
+	require.Len(t, imports, 2)
+	assert.Equal(t, "com.acme.Foo", imports[0].Path())
+	assert.Equal(t, "com.acme.Bar", imports[1].Path())
+	assert.False(t, imports[0].IsWildcard())
+	assert.Equal(t, "com.acme", imports[1].Package())
+}
+
+func TestParseScalaImports_Wildcard(t *testing.T) {
+	src := []byte(`package com.acme.app
+
+import com.acme._
+`)
+	imports := ParseScalaImports(src, map[string]bool{"com.acme": true})
+
+	require.Len(t, imports, 1)
+	assert.Equal(t, "com.acme._", imports[0].Path())
+	assert.True(t, imports[0].IsWildcard())
+	assert.Equal(t, "com.acme", imports[0].Package())
+}
+
+func TestParseScalaImports_RelativeToEnclosingPackage(t *testing.T) {
+	src := []byte(`package com.acme
+package app
+
+import service.Billing
+import model._
+import _root_.service.Legacy
+`)
+	projectPackages := map[string]bool{
+		"com.acme.service": true,
+		"com.acme.model":   true,
+		"service":          true,
+	}
+
+	imports := ParseScalaImports(src, projectPackages)
+
+	require.Len(t, imports, 3)
+	assert.Equal(t, "com.acme.service.Billing", imports[0].Path())
+	assert.Equal(t, "com.acme.model._", imports[1].Path())
+	assert.Equal(t, "service.Legacy", imports[2].Path())
+	for _, imp := range imports {
+		_, isInternal := imp.(InternalImport)
+		assert.True(t, isInternal, imp.Path())
+	}
+}
+
+func TestParseScalaImports_SinglePackageClauseDoesNotOpenParentScope(t *testing.T) {
+	src := []byte(`package com.acme.app
+
+import service.Billing
+`)
+
+	imports := ParseScalaImports(src, map[string]bool{"com.acme.service": true})
+
+	require.Len(t, imports, 1)
+	assert.Equal(t, "service.Billing", imports[0].Path())
+	_, isExternal := imports[0].(ExternalImport)
+	assert.True(t, isExternal)
+}
+
+func TestParseTopLevelTypeNames_CaseClassesAndCaseObjects(t *testing.T) {
+	src := []byte(`package com.acme
+
+case class Invoice(id: String)
+case object Empty
+sealed trait Status
+`)
+	types := ParseTopLevelTypeNames(src)
+	assert.ElementsMatch(t, []string{"Invoice", "Empty", "Status"}, types)
+}
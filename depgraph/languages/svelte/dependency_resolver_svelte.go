@@ -5,31 +5,36 @@ import (
 	"path/filepath"
 
 	"github.com/LegacyCodeHQ/clarity/depgraph/languages/javascript"
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// internalImportsCacheKind names the relative import paths
+// ResolveSvelteProjectImports caches per file.
+const internalImportsCacheKind = "svelte-internal-imports/v1"
+
 func ResolveSvelteProjectImports(
 	absPath string,
 	filePath string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	importPaths, err := moduleapi.ParseFile(cache, contentReader, internalImportsCacheKind, absPath, func(content []byte) ([]string, error) {
+		imports, parseErr := ParseSvelteImports(content)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		}
+		return javascript.InternalImportPaths(imports), nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
-
-	imports, parseErr := ParseSvelteImports(content)
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		return nil, err
 	}
 
 	var projectImports []string
-	for _, imp := range imports {
-		if internalImp, ok := imp.(javascript.InternalImport); ok {
-			resolvedFiles := ResolveSvelteImportPath(absPath, internalImp.Path(), suppliedFiles)
-			projectImports = append(projectImports, resolvedFiles...)
-		}
+	for _, importPath := range importPaths {
+		resolvedFiles := ResolveSvelteImportPath(absPath, importPath, suppliedFiles)
+		projectImports = append(projectImports, resolvedFiles...)
 	}
 
 	return projectImports, nil
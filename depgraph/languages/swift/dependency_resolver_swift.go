@@ -6,27 +6,53 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// fileFactsCacheKind names the swiftFileFacts the resolver caches per file.
+const fileFactsCacheKind = "swift-file-facts/v1"
+
+// swiftFileFacts is what resolving needs from a Swift file's content: its own imports
+// and type references, and the types it declares for files referencing them.
+type swiftFileFacts struct {
+	Imports        []SwiftImport `json:"imports,omitempty"`
+	TypeReferences []string      `json:"typeReferences,omitempty"`
+	DeclaredTypes  []string      `json:"declaredTypes,omitempty"`
+}
+
+func parseSwiftFileFacts(content []byte) (swiftFileFacts, error) {
+	imports, err := ParseSwiftImports(content)
+	if err != nil {
+		return swiftFileFacts{}, err
+	}
+	return swiftFileFacts{
+		Imports:        imports,
+		TypeReferences: ExtractSwiftTypeIdentifiers(content),
+		DeclaredTypes:  ParseSwiftTopLevelTypeNames(content),
+	}, nil
+}
+
 func ResolveSwiftProjectImports(
 	absPath string,
 	filePath string,
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
+	facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, absPath, func(content []byte) (swiftFileFacts, error) {
+		facts, parseErr := parseSwiftFileFacts(content)
+		if parseErr != nil {
+			return swiftFileFacts{}, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		}
+		return facts, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
-	}
-
-	imports, parseErr := ParseSwiftImports(content)
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		return nil, err
 	}
 
 	moduleIndex := buildSwiftModuleIndex(suppliedFiles)
-	typeReferences := ExtractSwiftTypeIdentifiers(content)
+	typeReferences := facts.TypeReferences
 	if len(typeReferences) == 0 {
 		return []string{}, nil
 	}
@@ -50,17 +76,19 @@ func ResolveSwiftProjectImports(
 			moduleIndex,
 			typeReferenceSet,
 			typeIndex,
-			contentReader)...)
+			contentReader,
+			cache)...)
 	} else {
 		projectImports = append(projectImports, resolveSwiftCandidatesByTypeReferences(
 			absPath,
 			allSwiftCandidates(suppliedFiles),
 			typeReferenceSet,
 			typeIndex,
-			contentReader)...)
+			contentReader,
+			cache)...)
 	}
 
-	for _, imp := range imports {
+	for _, imp := range facts.Imports {
 		moduleName := strings.TrimSpace(imp.Path)
 		if moduleName == "" || visitedModules[moduleName] {
 			continue
@@ -72,7 +100,8 @@ func ResolveSwiftProjectImports(
 			moduleIndex,
 			typeReferenceSet,
 			typeIndex,
-			contentReader)...)
+			contentReader,
+			cache)...)
 	}
 
 	return deduplicateSwiftPaths(projectImports), nil
@@ -103,6 +132,7 @@ func resolveSwiftModuleImport(
 	typeReferences map[string]bool,
 	typeIndex map[string][]string,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) []string {
 	if moduleName == "" {
 		return nil
@@ -126,7 +156,8 @@ func resolveSwiftModuleImport(
 		candidates,
 		typeReferences,
 		typeIndex,
-		contentReader)
+		contentReader,
+		cache)
 }
 
 func resolveSwiftCandidatesByTypeReferences(
@@ -135,13 +166,14 @@ func resolveSwiftCandidatesByTypeReferences(
 	typeReferences map[string]bool,
 	typeIndex map[string][]string,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) []string {
 	var resolved []string
 	for _, path := range candidates {
 		if path == sourceFile {
 			continue
 		}
-		if fileDeclaresReferencedType(path, typeReferences, typeIndex, contentReader) {
+		if fileDeclaresReferencedType(path, typeReferences, typeIndex, contentReader, cache) {
 			resolved = append(resolved, path)
 		}
 	}
@@ -153,13 +185,14 @@ func fileDeclaresReferencedType(
 	typeReferences map[string]bool,
 	typeIndex map[string][]string,
 	contentReader vcs.ContentReader,
+	cache moduleapi.ParseCache,
 ) bool {
 	if _, ok := typeIndex[filePath]; !ok {
-		content, err := contentReader(filePath)
+		facts, err := moduleapi.ParseFile(cache, contentReader, fileFactsCacheKind, filePath, parseSwiftFileFacts)
 		if err != nil {
 			typeIndex[filePath] = nil
 		} else {
-			typeIndex[filePath] = ParseSwiftTopLevelTypeNames(content)
+			typeIndex[filePath] = facts.DeclaredTypes
 		}
 	}
 
@@ -29,7 +29,7 @@ func TestResolveSwiftProjectImports_SwiftPMModule(t *testing.T) {
 		fooPath: true,
 	}
 
-	imports, err := ResolveSwiftProjectImports(appPath, appPath, supplied, reader)
+	imports, err := ResolveSwiftProjectImports(appPath, appPath, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []string{fooPath}, imports)
 }
@@ -53,7 +53,7 @@ func TestResolveSwiftProjectImports_TestsModuleImportsMain(t *testing.T) {
 		testPath: true,
 	}
 
-	imports, err := ResolveSwiftProjectImports(testPath, testPath, supplied, reader)
+	imports, err := ResolveSwiftProjectImports(testPath, testPath, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []string{mainPath}, imports)
 }
@@ -75,7 +75,7 @@ func TestResolveSwiftProjectImports_FlatLayoutResolvesTypeReference(t *testing.T
 		featurePath: true,
 	}
 
-	imports, err := ResolveSwiftProjectImports(viewPath, viewPath, supplied, reader)
+	imports, err := ResolveSwiftProjectImports(viewPath, viewPath, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []string{featurePath}, imports)
 }
@@ -153,7 +153,34 @@ struct DependencyGraphView: View {
 		viewPath:        true,
 	}
 
-	imports, err := ResolveSwiftProjectImports(contentViewPath, contentViewPath, supplied, reader)
+	imports, err := ResolveSwiftProjectImports(contentViewPath, contentViewPath, supplied, reader, nil)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []string{modelsPath, viewPath}, imports)
 }
+
+func TestResolveSwiftProjectImports_ExtensionDependsOnExtendedStruct(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir := filepath.Join(tmpDir, "Sources", "App")
+	require.NoError(t, os.MkdirAll(appDir, 0o755))
+
+	userPath := filepath.Join(appDir, "User.swift")
+	require.NoError(t, os.WriteFile(userPath, []byte("struct User {\n    let name: String\n}\n"), 0o644))
+
+	formattingPath := filepath.Join(appDir, "User+Formatting.swift")
+	require.NoError(t, os.WriteFile(formattingPath, []byte("extension User {\n    var greeting: String { \"Hello, \\(name)\" }\n}\n"), 0o644))
+
+	reader := vcs.FilesystemContentReader()
+	supplied := map[string]bool{
+		userPath:       true,
+		formattingPath: true,
+	}
+
+	imports, err := ResolveSwiftProjectImports(formattingPath, formattingPath, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{userPath}, imports)
+
+	// The struct does not know about its extensions.
+	imports, err = ResolveSwiftProjectImports(userPath, userPath, supplied, reader, nil)
+	require.NoError(t, err)
+	assert.Empty(t, imports)
+}
@@ -3,10 +3,27 @@ package typescript
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
+	"strings"
 
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// Kinds of the import lists the resolver caches per file. Files without relative or
+// @/ specifiers have no local imports, so when neither aliases nor external packages
+// matter they are cached under their own kind without being parsed.
+const (
+	importsCacheKind      = "typescript-imports/v1"
+	localImportsCacheKind = "typescript-local-imports/v1"
+)
+
+// tsImport is the cached form of a TypeScriptImport.
+type tsImport struct {
+	Path     string `json:"path"`
+	TypeOnly bool   `json:"typeOnly,omitempty"`
+}
+
 func ResolveTypeScriptProjectImports(
 	absPath string,
 	filePath string,
@@ -14,35 +31,115 @@ func ResolveTypeScriptProjectImports(
 	suppliedFiles map[string]bool,
 	contentReader vcs.ContentReader,
 ) ([]string, error) {
-	content, err := contentReader(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+	return resolveTypeScriptProjectImports(absPath, filePath, ext, suppliedFiles, contentReader, nil, newPackageJSONResolver(contentReader), nil, nil)
+}
+
+// resolveTypeScriptProjectImports resolves the project imports of a file. When
+// tsconfigs is set, the paths and baseUrl options of the file's nearest tsconfig.json
+// resolve non-relative specifiers before the built-in rules are tried. Imports of
+// directories resolve through packages to the entries their package.json declares.
+// Bare specifiers that resolve to no file are reported to externalImports as npm
+// packages.
+func resolveTypeScriptProjectImports(
+	absPath string,
+	filePath string,
+	ext string,
+	suppliedFiles map[string]bool,
+	contentReader vcs.ContentReader,
+	tsconfigs *tsConfigResolver,
+	packages *packageJSONResolver,
+	externalImports *moduleapi.ExternalImports,
+	cache moduleapi.ParseCache,
+) ([]string, error) {
+	config := tsconfigs.forSourceFile(absPath)
+	localOnly := !config.hasAliases() && externalImports == nil
+	kind := importsCacheKind
+	if localOnly {
+		kind = localImportsCacheKind
 	}
-	if !bytes.Contains(content, []byte("./")) &&
-		!bytes.Contains(content, []byte("../")) &&
-		!bytes.Contains(content, []byte("@/")) {
-		return nil, nil
+	if ext == ".tsx" {
+		kind += "+tsx"
 	}
 
-	imports, parseErr := ParseTypeScriptImports(content, ext == ".tsx")
-	if parseErr != nil {
-		return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+	cachedImports, err := moduleapi.ParseFile(cache, contentReader, kind, absPath, func(content []byte) ([]tsImport, error) {
+		if localOnly &&
+			!bytes.Contains(content, []byte("./")) &&
+			!bytes.Contains(content, []byte("../")) &&
+			!bytes.Contains(content, []byte("@/")) {
+			return nil, nil
+		}
+		imports, parseErr := ParseTypeScriptImports(content, ext == ".tsx")
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse imports in %s: %w", filePath, parseErr)
+		}
+		cached := make([]tsImport, 0, len(imports))
+		for _, imp := range imports {
+			cached = append(cached, tsImport{Path: imp.Path(), TypeOnly: imp.IsTypeOnly()})
+		}
+		return cached, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	imports := make([]TypeScriptImport, 0, len(cachedImports))
+	for _, imp := range cachedImports {
+		imports = append(imports, classifyTypeScriptImport(imp.Path, imp.TypeOnly))
 	}
 
 	var projectImports []string
 	seen := make(map[string]bool, len(imports))
 	for _, imp := range imports {
-		if internalImp, ok := imp.(InternalImport); ok {
-			resolvedFiles := ResolveTypeScriptImportPath(absPath, internalImp.Path(), suppliedFiles)
-			for _, resolvedFile := range resolvedFiles {
-				if seen[resolvedFile] {
-					continue
-				}
-				seen[resolvedFile] = true
-				projectImports = append(projectImports, resolvedFile)
+		var resolvedFiles []string
+		switch imp := imp.(type) {
+		case InternalImport:
+			resolvedFiles = resolveConfiguredImport(config, imp.Path(), suppliedFiles, packages)
+			if len(resolvedFiles) == 0 {
+				resolvedFiles = resolveTypeScriptImportPath(absPath, imp.Path(), suppliedFiles, packages)
+			}
+		case ExternalImport:
+			resolvedFiles = resolveConfiguredImport(config, imp.Path(), suppliedFiles, packages)
+			if len(resolvedFiles) == 0 {
+				externalImports.Record(absPath, moduleapi.EcosystemNPM, npmPackageName(imp.Path()))
 			}
 		}
+		for _, resolvedFile := range resolvedFiles {
+			if seen[resolvedFile] {
+				continue
+			}
+			seen[resolvedFile] = true
+			projectImports = append(projectImports, resolvedFile)
+		}
 	}
 
 	return projectImports, nil
 }
+
+// npmPackageName returns the package a bare specifier names, dropping any subpath:
+// "lodash/fp" is lodash and "@angular/core/testing" is @angular/core.
+func npmPackageName(specifier string) string {
+	parts := strings.SplitN(specifier, "/", 3)
+	if strings.HasPrefix(specifier, "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// resolveConfiguredImport resolves a non-relative importPath through the paths option
+// of config, trying the targets of the matching alias in declared order and stopping
+// at the first that names supplied files, then against baseUrl. Relative imports and
+// imports config does not map resolve to nothing.
+func resolveConfiguredImport(config *tsConfig, importPath string, suppliedFiles map[string]bool, packages *packageJSONResolver) []string {
+	if !config.hasAliases() || strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		return nil
+	}
+	for _, basePath := range config.aliasBasePaths(importPath) {
+		if resolved := resolveTypeScriptBasePathCandidates(basePath, importPath, suppliedFiles, packages); len(resolved) > 0 {
+			return resolved
+		}
+	}
+	if config.baseURL != "" {
+		basePath := filepath.Clean(filepath.Join(config.baseURL, importPath))
+		return resolveTypeScriptBasePathCandidates(basePath, importPath, suppliedFiles, packages)
+	}
+	return nil
+}
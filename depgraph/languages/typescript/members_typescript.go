@@ -0,0 +1,332 @@
+package typescript
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// Import names used by NamedImport for bindings that are not named exports.
+const (
+	DefaultImportName   = "default"
+	NamespaceImportName = "*"
+)
+
+// NamedImport is one binding an import statement brings into scope.
+type NamedImport struct {
+	// Source is the module specifier, such as ./pricing.
+	Source string
+	// Imported is the exported name, DefaultImportName for a default import or
+	// NamespaceImportName for import * as.
+	Imported string
+	// Local is the name the binding has in the importing file.
+	Local string
+}
+
+// ExportedDeclaration is a declaration a file exports.
+type ExportedDeclaration struct {
+	// Name is the exported name, DefaultImportName for the default export.
+	Name string
+	// Keyword is the declaring keyword: function, class, interface, type, enum,
+	// const, let or var. It is empty when the declaration is not in the file.
+	Keyword string
+}
+
+// MemberReference is a use of a name in a TypeScript file.
+type MemberReference struct {
+	// Name is the name used or, for a property read from a name such as api.fetch,
+	// "api.fetch".
+	Name string
+	Line int
+	// Function is the name of the innermost named function or method containing the
+	// reference, or empty at file scope.
+	Function string
+}
+
+func parseTypeScriptTree(sourceCode []byte, isTSX bool) (*sitter.Tree, func()) {
+	pool := &tsParserPoolTS
+	if isTSX {
+		pool = &tsParserPoolTSX
+	}
+	parser := pool.Get().(*sitter.Parser)
+	tree, err := parser.ParseCtx(context.Background(), nil, sourceCode)
+	pool.Put(parser)
+	if err != nil {
+		return nil, func() {}
+	}
+	return tree, tree.Close
+}
+
+// ParseNamedImports returns the bindings of the file's import statements. Side-effect
+// imports bring no bindings and are left out.
+func ParseNamedImports(sourceCode []byte, isTSX bool) []NamedImport {
+	tree, closeTree := parseTypeScriptTree(sourceCode, isTSX)
+	defer closeTree()
+	if tree == nil {
+		return nil
+	}
+
+	var imports []NamedImport
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		statement := root.NamedChild(i)
+		if statement.Type() != "import_statement" {
+			continue
+		}
+		sourceNode := statement.ChildByFieldName("source")
+		if sourceNode == nil {
+			continue
+		}
+		source := cleanImportPath(sourceNode.Content(sourceCode))
+
+		for j := 0; j < int(statement.NamedChildCount()); j++ {
+			clause := statement.NamedChild(j)
+			if clause.Type() != "import_clause" {
+				continue
+			}
+			imports = append(imports, importClauseBindings(clause, source, sourceCode)...)
+		}
+	}
+	return imports
+}
+
+func importClauseBindings(clause *sitter.Node, source string, sourceCode []byte) []NamedImport {
+	var bindings []NamedImport
+	for i := 0; i < int(clause.NamedChildCount()); i++ {
+		child := clause.NamedChild(i)
+		switch child.Type() {
+		case "identifier":
+			bindings = append(bindings, NamedImport{Source: source, Imported: DefaultImportName, Local: child.Content(sourceCode)})
+		case "namespace_import":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				if local := child.NamedChild(j); local.Type() == "identifier" {
+					bindings = append(bindings, NamedImport{Source: source, Imported: NamespaceImportName, Local: local.Content(sourceCode)})
+				}
+			}
+		case "named_imports":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				specifier := child.NamedChild(j)
+				if specifier.Type() != "import_specifier" {
+					continue
+				}
+				name := specifier.ChildByFieldName("name")
+				if name == nil {
+					continue
+				}
+				binding := NamedImport{Source: source, Imported: name.Content(sourceCode), Local: name.Content(sourceCode)}
+				if alias := specifier.ChildByFieldName("alias"); alias != nil {
+					binding.Local = alias.Content(sourceCode)
+				}
+				bindings = append(bindings, binding)
+			}
+		}
+	}
+	return bindings
+}
+
+// ExtractExportedDeclarations returns the declarations the file exports, either with
+// an export keyword on the declaration or by name in an export { ... } list.
+// Re-exports from other modules are left out.
+func ExtractExportedDeclarations(sourceCode []byte, isTSX bool) []ExportedDeclaration {
+	tree, closeTree := parseTypeScriptTree(sourceCode, isTSX)
+	defer closeTree()
+	if tree == nil {
+		return nil
+	}
+
+	root := tree.RootNode()
+	keywords := make(map[string]string)
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		declaration := root.NamedChild(i)
+		if declaration.Type() == "export_statement" {
+			declaration = declaration.ChildByFieldName("declaration")
+		}
+		if declaration == nil {
+			continue
+		}
+		for _, name := range declaredNames(declaration, sourceCode) {
+			keywords[name] = declarationKeyword(declaration)
+		}
+	}
+
+	var declarations []ExportedDeclaration
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		statement := root.NamedChild(i)
+		if statement.Type() != "export_statement" || statement.ChildByFieldName("source") != nil {
+			continue
+		}
+		if declaration := statement.ChildByFieldName("declaration"); declaration != nil {
+			if isDefaultExport(statement) {
+				declarations = append(declarations, ExportedDeclaration{Name: DefaultImportName, Keyword: declarationKeyword(declaration)})
+				continue
+			}
+			for _, name := range declaredNames(declaration, sourceCode) {
+				declarations = append(declarations, ExportedDeclaration{Name: name, Keyword: keywords[name]})
+			}
+			continue
+		}
+		if value := statement.ChildByFieldName("value"); value != nil {
+			keyword := declarationKeyword(value)
+			if value.Type() == "identifier" {
+				keyword = keywords[value.Content(sourceCode)]
+			}
+			declarations = append(declarations, ExportedDeclaration{Name: DefaultImportName, Keyword: keyword})
+			continue
+		}
+		for j := 0; j < int(statement.NamedChildCount()); j++ {
+			clause := statement.NamedChild(j)
+			if clause.Type() != "export_clause" {
+				continue
+			}
+			for k := 0; k < int(clause.NamedChildCount()); k++ {
+				specifier := clause.NamedChild(k)
+				name := specifier.ChildByFieldName("name")
+				if specifier.Type() != "export_specifier" || name == nil {
+					continue
+				}
+				exported := name.Content(sourceCode)
+				if alias := specifier.ChildByFieldName("alias"); alias != nil {
+					exported = alias.Content(sourceCode)
+				}
+				declarations = append(declarations, ExportedDeclaration{Name: exported, Keyword: keywords[name.Content(sourceCode)]})
+			}
+		}
+	}
+	return declarations
+}
+
+func isDefaultExport(statement *sitter.Node) bool {
+	for i := 0; i < int(statement.ChildCount()); i++ {
+		if statement.Child(i).Type() == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+// declarationKeyword returns the keyword that declares node.
+func declarationKeyword(node *sitter.Node) string {
+	switch node.Type() {
+	case "function_declaration", "generator_function_declaration", "function_signature", "function_expression", "arrow_function":
+		return "function"
+	case "class_declaration", "abstract_class_declaration", "class":
+		return "class"
+	case "interface_declaration":
+		return "interface"
+	case "type_alias_declaration":
+		return "type"
+	case "enum_declaration":
+		return "enum"
+	case "lexical_declaration":
+		if kind := node.ChildByFieldName("kind"); kind != nil {
+			return kind.Type()
+		}
+		if node.ChildCount() > 0 {
+			return node.Child(0).Type()
+		}
+		return "const"
+	case "variable_declaration":
+		return "var"
+	default:
+		return ""
+	}
+}
+
+// declaredNames returns the names a declaration introduces.
+func declaredNames(node *sitter.Node, sourceCode []byte) []string {
+	switch node.Type() {
+	case "lexical_declaration", "variable_declaration":
+		var names []string
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			declarator := node.NamedChild(i)
+			if declarator.Type() != "variable_declarator" {
+				continue
+			}
+			if name := declarator.ChildByFieldName("name"); name != nil && name.Type() == "identifier" {
+				names = append(names, name.Content(sourceCode))
+			}
+		}
+		return names
+	default:
+		if name := node.ChildByFieldName("name"); name != nil {
+			return []string{name.Content(sourceCode)}
+		}
+		return nil
+	}
+}
+
+// FindMemberReferences returns the uses of names in the file outside import
+// statements, in source order.
+func FindMemberReferences(sourceCode []byte, isTSX bool, names map[string]bool) []MemberReference {
+	if len(names) == 0 {
+		return nil
+	}
+	tree, closeTree := parseTypeScriptTree(sourceCode, isTSX)
+	defer closeTree()
+	if tree == nil {
+		return nil
+	}
+
+	var references []MemberReference
+	var walk func(node *sitter.Node, function string)
+	walk = func(node *sitter.Node, function string) {
+		switch node.Type() {
+		case "import_statement":
+			return
+		case "function_declaration", "generator_function_declaration", "method_definition":
+			if name := node.ChildByFieldName("name"); name != nil {
+				function = name.Content(sourceCode)
+			}
+		case "variable_declarator":
+			name, value := node.ChildByFieldName("name"), node.ChildByFieldName("value")
+			if name != nil && value != nil && (value.Type() == "arrow_function" || value.Type() == "function_expression" || value.Type() == "function") {
+				function = name.Content(sourceCode)
+			}
+		case "member_expression":
+			object, property := node.ChildByFieldName("object"), node.ChildByFieldName("property")
+			if object != nil && property != nil && object.Type() == "identifier" && names[object.Content(sourceCode)] {
+				references = append(references, MemberReference{
+					Name:     object.Content(sourceCode) + "." + property.Content(sourceCode),
+					Line:     int(node.StartPoint().Row) + 1,
+					Function: function,
+				})
+				return
+			}
+		case "identifier", "type_identifier", "shorthand_property_identifier":
+			if name := node.Content(sourceCode); names[name] {
+				references = append(references, MemberReference{
+					Name:     name,
+					Line:     int(node.StartPoint().Row) + 1,
+					Function: function,
+				})
+			}
+			return
+		}
+
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i), function)
+		}
+	}
+	walk(tree.RootNode(), "")
+	return references
+}
+
+// ImportResolvesTo reports whether importPath, imported by sourceFile, names
+// targetFile. Relative and @/ specifiers resolve as in the dependency graph, and
+// other specifiers through the paths and baseUrl options of the nearest
+// tsconfig.json.
+func ImportResolvesTo(sourceFile, importPath, targetFile string, contentReader vcs.ContentReader) bool {
+	targets := map[string]bool{filepath.Clean(targetFile): true}
+	packages := newPackageJSONResolver(contentReader)
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") || strings.HasPrefix(importPath, "@/") {
+		if len(resolveTypeScriptImportPath(sourceFile, importPath, targets, packages)) > 0 {
+			return true
+		}
+	}
+	config := newTSConfigResolver(contentReader).forSourceFile(sourceFile)
+	return len(resolveConfiguredImport(config, importPath, targets, packages)) > 0
+}
@@ -0,0 +1,101 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestParseNamedImports(t *testing.T) {
+	source := []byte(`import Default, { formatPrice, Price as P } from './pricing';
+import * as api from './api';
+import './polyfills';
+`)
+
+	assert.Equal(t, []NamedImport{
+		{Source: "./pricing", Imported: DefaultImportName, Local: "Default"},
+		{Source: "./pricing", Imported: "formatPrice", Local: "formatPrice"},
+		{Source: "./pricing", Imported: "Price", Local: "P"},
+		{Source: "./api", Imported: NamespaceImportName, Local: "api"},
+	}, ParseNamedImports(source, false))
+}
+
+func TestExtractExportedDeclarations(t *testing.T) {
+	source := []byte(`export function total() {}
+export class Cart {}
+export interface Item {}
+export type Id = string;
+export enum Currency { EUR }
+export const rate = 1, fee = 2;
+export let counter = 0;
+function helper() {}
+const internal = 1;
+export { helper, internal as exposed };
+export { shared } from './shared';
+export default function main() {}
+`)
+
+	assert.Equal(t, []ExportedDeclaration{
+		{Name: "total", Keyword: "function"},
+		{Name: "Cart", Keyword: "class"},
+		{Name: "Item", Keyword: "interface"},
+		{Name: "Id", Keyword: "type"},
+		{Name: "Currency", Keyword: "enum"},
+		{Name: "rate", Keyword: "const"},
+		{Name: "fee", Keyword: "const"},
+		{Name: "counter", Keyword: "let"},
+		{Name: "helper", Keyword: "function"},
+		{Name: "exposed", Keyword: "const"},
+		{Name: DefaultImportName, Keyword: "function"},
+	}, ExtractExportedDeclarations(source, false))
+}
+
+func TestFindMemberReferences(t *testing.T) {
+	source := []byte(`import { formatPrice, Price } from './pricing';
+import * as api from './api';
+
+const banner = formatPrice(new Price());
+
+export function total(p: Price): string {
+  return formatPrice(p);
+}
+
+export const load = () => api.fetchAll();
+`)
+
+	references := FindMemberReferences(source, false, map[string]bool{"formatPrice": true, "Price": true, "api": true})
+
+	assert.Equal(t, []MemberReference{
+		{Name: "formatPrice", Line: 4},
+		{Name: "Price", Line: 4},
+		{Name: "Price", Line: 6, Function: "total"},
+		{Name: "formatPrice", Line: 7, Function: "total"},
+		{Name: "api.fetchAll", Line: 10, Function: "load"},
+	}, references)
+}
+
+func TestImportResolvesTo(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"tsconfig.json":       `{"compilerOptions": {"paths": {"@lib/*": ["lib/*"]}}}`,
+		"lib/pricing.ts":      "export const price = 1;\n",
+		"src/checkout/app.ts": "",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	source := filepath.Join(dir, "src", "checkout", "app.ts")
+	target := filepath.Join(dir, "lib", "pricing.ts")
+	reader := vcs.FilesystemContentReader()
+
+	assert.True(t, ImportResolvesTo(source, "../../lib/pricing", target, reader))
+	assert.True(t, ImportResolvesTo(source, "@lib/pricing", target, reader))
+	assert.False(t, ImportResolvesTo(source, "./pricing", target, reader))
+	assert.False(t, ImportResolvesTo(source, "lodash", target, reader))
+}
@@ -20,7 +20,12 @@ func (Module) Maturity() moduleapi.MaturityLevel {
 }
 
 func (Module) NewResolver(ctx *moduleapi.Context, contentReader vcs.ContentReader) moduleapi.Resolver {
-	return resolver{ctx: ctx, contentReader: contentReader}
+	return resolver{
+		ctx:           ctx,
+		contentReader: contentReader,
+		tsconfigs:     newTSConfigResolver(contentReader),
+		packages:      newPackageJSONResolver(contentReader),
+	}
 }
 
 func (Module) IsTestFile(filePath string, _ vcs.ContentReader) bool {
@@ -30,10 +35,12 @@ func (Module) IsTestFile(filePath string, _ vcs.ContentReader) bool {
 type resolver struct {
 	ctx           *moduleapi.Context
 	contentReader vcs.ContentReader
+	tsconfigs     *tsConfigResolver
+	packages      *packageJSONResolver
 }
 
 func (r resolver) ResolveProjectImports(absPath, filePath, ext string) ([]string, error) {
-	return ResolveTypeScriptProjectImports(absPath, filePath, ext, r.ctx.SuppliedFiles, r.contentReader)
+	return resolveTypeScriptProjectImports(absPath, filePath, ext, r.ctx.SuppliedFiles, r.contentReader, r.tsconfigs, r.packages, r.ctx.ExternalImports, r.ctx.ParseCache)
 }
 
 func (resolver) FinalizeGraph(_ moduleapi.Graph) error {
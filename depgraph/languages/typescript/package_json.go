@@ -0,0 +1,143 @@
+package typescript
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// exportConditions is the order the conditions of a package.json "exports" entry are
+// tried in, so type and ES module sources win over CommonJS builds.
+var exportConditions = []string{"types", "import", "module", "default", "require", "node"}
+
+// packageJSONFile is the part of a package.json file that is read.
+type packageJSONFile struct {
+	Main    string          `json:"main"`
+	Module  string          `json:"module"`
+	Exports json.RawMessage `json:"exports"`
+}
+
+// packageJSONResolver reads the entry points of the package.json files of imported
+// directories, caching them so every package.json is read once per graph build.
+type packageJSONResolver struct {
+	contentReader vcs.ContentReader
+	entries       sync.Map // package.json path -> []string entry base paths (nil when unreadable)
+}
+
+func newPackageJSONResolver(contentReader vcs.ContentReader) *packageJSONResolver {
+	return &packageJSONResolver{contentReader: contentReader}
+}
+
+// resolveEntry resolves an import of the directory dir through the "main", "module",
+// and "exports" entries of its package.json, each as a file or a directory with an
+// index file. When entries name different supplied files, the first TypeScript
+// source wins over JavaScript ones.
+func (r *packageJSONResolver) resolveEntry(dir string, suppliedFiles map[string]bool) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	var resolved []string
+	for _, entry := range r.entryPaths(filepath.Join(dir, "package.json")) {
+		if file, ok := resolveTypeScriptFile(entry, entry, suppliedFiles); ok {
+			resolved = append(resolved, file)
+		} else if file, ok := resolveTypeScriptIndex(entry, suppliedFiles); ok {
+			resolved = append(resolved, file)
+		}
+	}
+	if len(resolved) == 0 {
+		return "", false
+	}
+	for _, file := range resolved {
+		if ext := filepath.Ext(file); ext == ".ts" || ext == ".tsx" {
+			return file, true
+		}
+	}
+	return resolved[0], true
+}
+
+func (r *packageJSONResolver) entryPaths(packagePath string) []string {
+	if cached, ok := r.entries.Load(packagePath); ok {
+		return cached.([]string)
+	}
+	entries := loadPackageJSONEntries(packagePath, r.contentReader)
+	r.entries.Store(packagePath, entries)
+	return entries
+}
+
+// loadPackageJSONEntries returns the absolute base paths of the entry points the
+// package.json at packagePath declares, in the order main, module, exports. It
+// returns nil when the file cannot be read or parsed.
+func loadPackageJSONEntries(packagePath string, contentReader vcs.ContentReader) []string {
+	content, err := contentReader.ReadFile(packagePath)
+	if err != nil {
+		return nil
+	}
+	var file packageJSONFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil
+	}
+
+	targets := []string{file.Main, file.Module}
+	targets = append(targets, rootExportTargets(file.Exports)...)
+
+	packageDir := filepath.Dir(packagePath)
+	var entries []string
+	seen := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		if target == "" || filepath.IsAbs(target) {
+			continue
+		}
+		entry := filepath.Clean(filepath.Join(packageDir, filepath.FromSlash(target)))
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// rootExportTargets returns the targets an "exports" value maps the package root to.
+// The value is a target string, an array of fallbacks, a map of conditions, or a map
+// of subpaths whose "." key holds any of those.
+func rootExportTargets(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var subpaths map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &subpaths); err == nil {
+		if root, ok := subpaths["."]; ok {
+			return exportTargets(root)
+		}
+	}
+	return exportTargets(raw)
+}
+
+// exportTargets returns the target strings of an export value, trying fallbacks in
+// order and conditions in the order of exportConditions.
+func exportTargets(raw json.RawMessage) []string {
+	var target string
+	if err := json.Unmarshal(raw, &target); err == nil {
+		return []string{target}
+	}
+	var fallbacks []json.RawMessage
+	if err := json.Unmarshal(raw, &fallbacks); err == nil {
+		var targets []string
+		for _, fallback := range fallbacks {
+			targets = append(targets, exportTargets(fallback)...)
+		}
+		return targets
+	}
+	var conditions map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &conditions); err == nil {
+		var targets []string
+		for _, condition := range exportConditions {
+			if value, ok := conditions[condition]; ok {
+				targets = append(targets, exportTargets(value)...)
+			}
+		}
+		return targets
+	}
+	return nil
+}
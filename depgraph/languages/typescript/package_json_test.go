@@ -0,0 +1,59 @@
+package typescript
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTypeScriptProjectImports_DirectoryResolvesToPackageJSONMain(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"lib/shared/package.json": `{"name": "shared", "main": "src/entry.ts"}`,
+		"lib/shared/src/entry.ts": "export const shared = 1;\n",
+		"app/main.ts":             "import { shared } from '../lib/shared';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "app/main.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "lib", "shared", "src", "entry.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_PackageJSONBuildEntryResolvesToTypeScriptSource(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"lib/shared/package.json": `{"main": "dist/index.js", "exports": {".": {"require": "./dist/index.cjs", "import": "./src/index.ts"}}}`,
+		"lib/shared/src/index.ts": "export const shared = 1;\n",
+		"app/main.ts":             "import { shared } from '../lib/shared';\n",
+	})
+	supplied[filepath.Join(dir, "lib", "shared", "dist", "index.js")] = true
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "app/main.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "lib", "shared", "src", "index.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_WorkspacePackageResolvesThroughExports(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"tsconfig.json":            `{"compilerOptions": {"baseUrl": ".", "paths": {"@acme/*": ["packages/*"]}}}`,
+		"packages/ui/package.json": `{"name": "@acme/ui", "exports": "./lib/main.ts"}`,
+		"packages/ui/lib/main.ts":  "export const Button = 1;\n",
+		"packages/web/src/app.ts":  "import { Button } from '@acme/ui';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "packages/web/src/app.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "packages", "ui", "lib", "main.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_PackageJSONEntryOutsideSuppliedFilesIsIgnored(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"lib/shared/package.json": `{"main": "src/entry.ts"}`,
+		"lib/shared/src/entry.ts": "export const shared = 1;\n",
+		"app/main.ts":             "import { shared } from '../lib/shared';\n",
+	})
+	delete(supplied, filepath.Join(dir, "lib", "shared", "src", "entry.ts"))
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "app/main.ts")
+
+	assert.Empty(t, resolved)
+}
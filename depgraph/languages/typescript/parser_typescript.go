@@ -13,6 +13,8 @@ import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/typescript/tsx"
 	"github.com/smacker/go-tree-sitter/typescript/typescript"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
 // TypeScriptImport represents an import in a TypeScript/TSX file
@@ -157,6 +159,8 @@ var (
 	importFromRE     = regexp.MustCompile(`(?ms)^\s*import\b[\s\S]*?\bfrom\s*['"]([^'"]+)['"]`)
 	sideEffectRE     = regexp.MustCompile(`(?m)^\s*import\s*['"]([^'"]+)['"]`)
 	exportFromRE     = regexp.MustCompile(`(?ms)^\s*export\b[\s\S]*?\bfrom\s*['"]([^'"]+)['"]`)
+	// requireRE matches CommonJS require('x') calls, including import x = require('x').
+	requireRE = regexp.MustCompile(`\brequire\s*\(\s*['"]([^'"]+)['"]\s*\)`)
 )
 
 // classifyTypeScriptImport classifies a TypeScript import path
@@ -227,7 +231,9 @@ func ParseTypeScriptImports(sourceCode []byte, isTSX bool) ([]TypeScriptImport,
 }
 
 func extractImportsFast(sourceCode []byte) []TypeScriptImport {
-	if !bytes.Contains(sourceCode, []byte("import")) && !bytes.Contains(sourceCode, []byte("export")) {
+	if !bytes.Contains(sourceCode, []byte("import")) &&
+		!bytes.Contains(sourceCode, []byte("export")) &&
+		!bytes.Contains(sourceCode, []byte("require")) {
 		return []TypeScriptImport{}
 	}
 
@@ -280,6 +286,17 @@ func extractImportsFast(sourceCode []byte) []TypeScriptImport {
 		imports = append(imports, classifyTypeScriptImport(importPath, false))
 	}
 
+	for _, m := range requireRE.FindAllSubmatch(sourceCode, -1) {
+		if len(m) < 2 {
+			continue
+		}
+		importPath := cleanImportPath(string(m[1]))
+		if importPath == "" {
+			continue
+		}
+		imports = append(imports, classifyTypeScriptImport(importPath, false))
+	}
+
 	return imports
 }
 
@@ -434,59 +451,72 @@ func cleanImportPath(raw string) string {
 	return strings.TrimSpace(cleaned)
 }
 
-// ResolveTypeScriptImportPath resolves a TypeScript import path to possible file paths
+// ResolveTypeScriptImportPath resolves a TypeScript import path to possible file paths.
+// The package.json of an imported directory is read from the filesystem.
 func ResolveTypeScriptImportPath(sourceFile, importPath string, suppliedFiles map[string]bool) []string {
+	return resolveTypeScriptImportPath(sourceFile, importPath, suppliedFiles, newPackageJSONResolver(vcs.FilesystemContentReader()))
+}
+
+func resolveTypeScriptImportPath(sourceFile, importPath string, suppliedFiles map[string]bool, packages *packageJSONResolver) []string {
 	basePath, ok := resolveTypeScriptBasePath(sourceFile, importPath)
 	if !ok {
 		return nil
 	}
+	return resolveTypeScriptBasePathCandidates(basePath, importPath, suppliedFiles, packages)
+}
 
-	var resolvedPaths []string
-
-	// TypeScript extension resolution order
-	extensions := []string{".ts", ".tsx", ".js", ".jsx"}
+// typeScriptExtensions is the order extensionless specifiers are tried in, so a .ts
+// file wins over a .js file of the same name.
+var typeScriptExtensions = []string{".ts", ".tsx", ".js", ".jsx"}
 
-	// In TS/TSX source, explicit runtime .js/.jsx specifiers often point to .ts/.tsx files.
-	// Try source-file variants first to avoid missing project edges in mixed module setups.
-	if sourceCandidates := sourceCandidatesForJSImport(basePath); len(sourceCandidates) > 0 {
-		for _, candidate := range sourceCandidates {
-			if suppliedFiles[candidate] {
-				resolvedPaths = append(resolvedPaths, candidate)
-			}
-		}
+// resolveTypeScriptBasePathCandidates returns the supplied file an import of
+// importPath resolving to basePath names, trying in order the path as a file with
+// each extension, the index files of a directory at the path, and the entry points
+// its package.json declares. It returns nil when no stage matches a supplied file.
+func resolveTypeScriptBasePathCandidates(basePath, importPath string, suppliedFiles map[string]bool, packages *packageJSONResolver) []string {
+	if resolved, ok := resolveTypeScriptFile(basePath, importPath, suppliedFiles); ok {
+		return []string{resolved}
 	}
-
-	// Try direct path with extensions
-	for _, ext := range extensions {
-		candidate := basePath + ext
-		if suppliedFiles[candidate] {
-			resolvedPaths = append(resolvedPaths, candidate)
-		}
+	if resolved, ok := resolveTypeScriptIndex(basePath, suppliedFiles); ok {
+		return []string{resolved}
 	}
+	if resolved, ok := packages.resolveEntry(basePath, suppliedFiles); ok {
+		return []string{resolved}
+	}
+	return nil
+}
 
-	// Try index file resolution (./utils -> ./utils/index.ts)
-	indexPaths := []string{
-		filepath.Join(basePath, "index.ts"),
-		filepath.Join(basePath, "index.tsx"),
-		filepath.Join(basePath, "index.js"),
-		filepath.Join(basePath, "index.jsx"),
+// resolveTypeScriptFile resolves basePath as a file: in TS/TSX source, explicit
+// runtime .js/.jsx specifiers often point to .ts/.tsx files, so those are tried
+// before the exact path, then the path with each extension.
+func resolveTypeScriptFile(basePath, importPath string, suppliedFiles map[string]bool) (string, bool) {
+	candidates := sourceCandidatesForJSImport(basePath)
+	if hasTypeScriptExtension(importPath) {
+		candidates = append(candidates, basePath)
+	}
+	for _, ext := range typeScriptExtensions {
+		candidates = append(candidates, basePath+ext)
 	}
+	return firstSupplied(candidates, suppliedFiles)
+}
 
-	for _, indexPath := range indexPaths {
-		if suppliedFiles[indexPath] {
-			resolvedPaths = append(resolvedPaths, indexPath)
-		}
+// resolveTypeScriptIndex resolves basePath as a directory holding an index file
+// (./utils -> ./utils/index.ts).
+func resolveTypeScriptIndex(basePath string, suppliedFiles map[string]bool) (string, bool) {
+	candidates := make([]string, 0, len(typeScriptExtensions))
+	for _, ext := range typeScriptExtensions {
+		candidates = append(candidates, filepath.Join(basePath, "index"+ext))
 	}
+	return firstSupplied(candidates, suppliedFiles)
+}
 
-	// If import already has an extension, try the exact path
-	if hasTypeScriptExtension(importPath) {
-		exactPath := basePath
-		if suppliedFiles[exactPath] {
-			resolvedPaths = append(resolvedPaths, exactPath)
+func firstSupplied(candidates []string, suppliedFiles map[string]bool) (string, bool) {
+	for _, candidate := range candidates {
+		if suppliedFiles[candidate] {
+			return candidate, true
 		}
 	}
-
-	return resolvedPaths
+	return "", false
 }
 
 // hasTypeScriptExtension checks if a path already has a TypeScript/JavaScript extension
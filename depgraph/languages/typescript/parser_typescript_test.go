@@ -34,6 +34,21 @@ const x = 1;
 	assertImportType(t, imports, "fs", NodeBuiltinImport{})
 }
 
+func TestParseTypeScriptImports_CommonJSRequire(t *testing.T) {
+	source := `
+const fs = require('fs');
+const legacy = require("./legacy");
+const config = require( '../config' );
+`
+	imports, err := ParseTypeScriptImports([]byte(source), false)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"fs", "./legacy", "../config"}, extractPaths(imports))
+	assertImportType(t, imports, "fs", NodeBuiltinImport{})
+	assertImportType(t, imports, "./legacy", InternalImport{})
+	assertImportType(t, imports, "../config", InternalImport{})
+}
+
 func TestParseTypeScriptImports_DefaultImports(t *testing.T) {
 	source := `
 import React from 'react';
@@ -344,6 +359,30 @@ func TestResolveTypeScriptImportPath_WithExtension(t *testing.T) {
 	assert.Contains(t, resolved, "/project/src/utils.ts")
 }
 
+func TestResolveTypeScriptImportPath_DirectoryResolvesToIndexFile(t *testing.T) {
+	suppliedFiles := map[string]bool{
+		"/project/src/shared/index.js": true,
+		"/project/src/shared/index.ts": true,
+		"/project/src/app.ts":          true,
+	}
+
+	resolved := ResolveTypeScriptImportPath("/project/src/app.ts", "./shared", suppliedFiles)
+
+	assert.Equal(t, []string{"/project/src/shared/index.ts"}, resolved)
+}
+
+func TestResolveTypeScriptImportPath_FilePreferredOverDirectoryAndTSOverJS(t *testing.T) {
+	suppliedFiles := map[string]bool{
+		"/project/src/utils.js":       true,
+		"/project/src/utils.ts":       true,
+		"/project/src/utils/index.ts": true,
+	}
+
+	resolved := ResolveTypeScriptImportPath("/project/src/app.ts", "./utils", suppliedFiles)
+
+	assert.Equal(t, []string{"/project/src/utils.ts"}, resolved)
+}
+
 func TestResolveTypeScriptImportPath_NotFound(t *testing.T) {
 	suppliedFiles := map[string]bool{
 		"/project/src/utils.ts": true,
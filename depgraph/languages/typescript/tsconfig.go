@@ -0,0 +1,304 @@
+package typescript
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// maxTSConfigExtendsDepth bounds extends chains so a cycle cannot loop forever.
+const maxTSConfigExtendsDepth = 16
+
+// tsConfig holds the module resolution options of a tsconfig.json that affect
+// project imports.
+type tsConfig struct {
+	// baseURL is the absolute directory non-relative specifiers are resolved against,
+	// or empty when the config does not set one.
+	baseURL string
+	// pathsBase is the absolute directory path alias targets are resolved against:
+	// baseURL when set, otherwise the directory of the config that declares paths.
+	pathsBase string
+	paths     map[string][]string
+}
+
+// tsConfigFile is the part of a tsconfig.json file that is read.
+type tsConfigFile struct {
+	Extends         json.RawMessage `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string             `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// tsConfigResolver finds and parses the tsconfig.json governing each source file,
+// caching results so every directory and config is read once per graph build.
+type tsConfigResolver struct {
+	contentReader vcs.ContentReader
+	configPaths   sync.Map // source dir -> nearest tsconfig.json path (or "")
+	configs       sync.Map // tsconfig.json path -> *tsConfig (nil when unreadable)
+}
+
+func newTSConfigResolver(contentReader vcs.ContentReader) *tsConfigResolver {
+	return &tsConfigResolver{contentReader: contentReader}
+}
+
+// forSourceFile returns the config of the nearest tsconfig.json above sourceFile, or
+// nil when there is none or it cannot be parsed.
+func (r *tsConfigResolver) forSourceFile(sourceFile string) *tsConfig {
+	if r == nil {
+		return nil
+	}
+	configPath := r.findConfigPath(filepath.Dir(sourceFile))
+	if configPath == "" {
+		return nil
+	}
+	if cached, ok := r.configs.Load(configPath); ok {
+		return cached.(*tsConfig)
+	}
+	config := loadTSConfig(configPath, r.contentReader)
+	r.configs.Store(configPath, config)
+	return config
+}
+
+func (r *tsConfigResolver) findConfigPath(startDir string) string {
+	dir := startDir
+	visited := make([]string, 0, 8)
+	for {
+		if cached, ok := r.configPaths.Load(dir); ok {
+			return r.remember(visited, cached.(string))
+		}
+		visited = append(visited, dir)
+
+		configPath := filepath.Join(dir, "tsconfig.json")
+		if r.contentReader.Exists(configPath) {
+			return r.remember(visited, configPath)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return r.remember(visited, "")
+		}
+		dir = parent
+	}
+}
+
+func (r *tsConfigResolver) remember(dirs []string, configPath string) string {
+	for _, dir := range dirs {
+		r.configPaths.Store(dir, configPath)
+	}
+	return configPath
+}
+
+// loadTSConfig parses the config at configPath and the configs it extends. Options
+// set by a config override those it extends. It returns nil when the config cannot be
+// read or parsed, so resolution falls back to the behavior without a tsconfig.
+func loadTSConfig(configPath string, contentReader vcs.ContentReader) *tsConfig {
+	config, ok := loadTSConfigChain(configPath, contentReader, 0)
+	if !ok {
+		return nil
+	}
+	return &config
+}
+
+func loadTSConfigChain(configPath string, contentReader vcs.ContentReader, depth int) (tsConfig, bool) {
+	if depth > maxTSConfigExtendsDepth {
+		return tsConfig{}, false
+	}
+	content, err := contentReader.ReadFile(configPath)
+	if err != nil {
+		return tsConfig{}, false
+	}
+	var file tsConfigFile
+	if err := json.Unmarshal(stripJSONComments(content), &file); err != nil {
+		return tsConfig{}, false
+	}
+
+	configDir := filepath.Dir(configPath)
+	var config tsConfig
+	for _, parent := range extendedConfigPaths(file.Extends, configDir) {
+		if parentConfig, ok := loadTSConfigChain(parent, contentReader, depth+1); ok {
+			config = mergeTSConfig(config, parentConfig)
+		}
+	}
+
+	var own tsConfig
+	if file.CompilerOptions.BaseURL != nil {
+		own.baseURL = filepath.Clean(filepath.Join(configDir, *file.CompilerOptions.BaseURL))
+	}
+	if file.CompilerOptions.Paths != nil {
+		own.paths = file.CompilerOptions.Paths
+		own.pathsBase = configDir
+	}
+	config = mergeTSConfig(config, own)
+	if config.baseURL != "" {
+		config.pathsBase = config.baseURL
+	}
+	return config, true
+}
+
+// mergeTSConfig returns base with the options set in override replacing its own.
+func mergeTSConfig(base, override tsConfig) tsConfig {
+	if override.baseURL != "" {
+		base.baseURL = override.baseURL
+	}
+	if override.paths != nil {
+		base.paths = override.paths
+		base.pathsBase = override.pathsBase
+	}
+	return base
+}
+
+// extendedConfigPaths returns the config files named by an extends value, which is a
+// string or, since TypeScript 5.0, an array of strings applied in order. Only
+// relative and absolute paths are followed; package names are skipped.
+func extendedConfigPaths(raw json.RawMessage, configDir string) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var names []string
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		names = []string{single}
+	} else if err := json.Unmarshal(raw, &names); err != nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, "./") && !strings.HasPrefix(name, "../") && !filepath.IsAbs(name) {
+			continue
+		}
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(configDir, name)
+		}
+		if filepath.Ext(path) != ".json" {
+			path += ".json"
+		}
+		paths = append(paths, filepath.Clean(path))
+	}
+	return paths
+}
+
+// aliasBasePaths returns the base paths importPath maps to through the paths option,
+// in the declared order of the matching pattern's targets. Following TypeScript, an
+// exact pattern wins over wildcard patterns, and the wildcard pattern with the
+// longest prefix wins over shorter ones.
+func (c *tsConfig) aliasBasePaths(importPath string) []string {
+	if c == nil || len(c.paths) == 0 {
+		return nil
+	}
+
+	targets, exact := c.paths[importPath]
+	matched, bestPattern := "", ""
+	for pattern, patternTargets := range c.paths {
+		if exact {
+			break
+		}
+		star := strings.Index(pattern, "*")
+		if star < 0 {
+			continue
+		}
+		prefix, suffix := pattern[:star], pattern[star+1:]
+		if len(importPath) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(importPath, prefix) ||
+			!strings.HasSuffix(importPath, suffix) {
+			continue
+		}
+		// Ties between equally long prefixes go to the smaller pattern, so the choice
+		// does not depend on map order.
+		if bestPattern != "" {
+			bestPrefix := strings.Index(bestPattern, "*")
+			if star < bestPrefix || (star == bestPrefix && pattern > bestPattern) {
+				continue
+			}
+		}
+		targets, bestPattern = patternTargets, pattern
+		matched = importPath[len(prefix) : len(importPath)-len(suffix)]
+	}
+
+	basePaths := make([]string, 0, len(targets))
+	for _, target := range targets {
+		target = strings.Replace(target, "*", matched, 1)
+		basePaths = append(basePaths, filepath.Clean(filepath.Join(c.pathsBase, target)))
+	}
+	return basePaths
+}
+
+// hasAliases reports whether non-relative specifiers may resolve to project files.
+func (c *tsConfig) hasAliases() bool {
+	return c != nil && (len(c.paths) > 0 || c.baseURL != "")
+}
+
+// stripJSONComments removes the // and /* */ comments and trailing commas that
+// tsconfig.json files may contain, leaving strings untouched.
+func stripJSONComments(content []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(content))
+	inString := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if inString {
+			out.WriteByte(c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				out.WriteByte(content[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			if i < len(content) {
+				out.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := bytes.Index(content[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(content)
+			} else {
+				i += end + 3
+			}
+		case c == ',' && nextSignificantByte(content, i+1) == '}', c == ',' && nextSignificantByte(content, i+1) == ']':
+			// Trailing comma: drop it.
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
+
+// nextSignificantByte returns the next byte at or after i that is not whitespace or
+// part of a comment, or 0 at the end of content.
+func nextSignificantByte(content []byte, i int) byte {
+	for i < len(content) {
+		switch c := content[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := bytes.Index(content[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0
+			}
+			i += end + 4
+		default:
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,149 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// writeTSProject writes files under a temporary directory and returns the directory
+// with the supplied-file set of every .ts file written.
+func writeTSProject(t *testing.T, files map[string]string) (string, map[string]bool) {
+	t.Helper()
+
+	dir := t.TempDir()
+	supplied := make(map[string]bool)
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		if filepath.Ext(path) == ".ts" {
+			supplied[path] = true
+		}
+	}
+	return dir, supplied
+}
+
+func resolveWithTSConfig(t *testing.T, dir string, supplied map[string]bool, file string) []string {
+	t.Helper()
+
+	reader := vcs.FilesystemContentReader()
+	absPath := filepath.Join(dir, filepath.FromSlash(file))
+	resolved, err := resolveTypeScriptProjectImports(absPath, file, ".ts", supplied, reader, newTSConfigResolver(reader), newPackageJSONResolver(reader), nil, nil)
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestResolveTypeScriptProjectImports_PathAliasToFile(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"tsconfig.json": `{
+  // Aliases used across the app.
+  "compilerOptions": {
+    "baseUrl": ".",
+    "paths": { "@app/*": ["src/app/*"], },
+  },
+}`,
+		"src/app/models/user.ts": "export const user = 1;\n",
+		"src/main.ts":            "import { user } from '@app/models/user';\nimport React from 'react';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "src/main.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "src", "app", "models", "user.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_PathAliasToIndexDirectory(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"tsconfig.json":               `{"compilerOptions": {"paths": {"@shared": ["lib/shared"]}}}`,
+		"lib/shared/index.ts":         "export const shared = 1;\n",
+		"packages/web/src/feature.ts": "import { shared } from '@shared';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "packages/web/src/feature.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "lib", "shared", "index.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_PathAliasTriesTargetsInOrder(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"tsconfig.json":       `{"compilerOptions": {"baseUrl": ".", "paths": {"~/*": ["generated/*", "src/*"]}}}`,
+		"src/util.ts":         "export const util = 1;\n",
+		"src/config.ts":       "export const config = 1;\n",
+		"generated/config.ts": "export const config = 2;\n",
+		"src/main.ts":         "import { util } from '~/util';\nimport { config } from '~/config';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "src/main.ts")
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "src", "util.ts"),
+		filepath.Join(dir, "generated", "config.ts"),
+	}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_PathAliasFromExtendedConfig(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"tsconfig.base.json":        `{"compilerOptions": {"baseUrl": ".", "paths": {"@core/*": ["libs/core/*"]}}}`,
+		"apps/web/tsconfig.json":    `{"extends": "../../tsconfig.base", "compilerOptions": {"strict": true}}`,
+		"libs/core/logger.ts":       "export const logger = 1;\n",
+		"apps/web/src/bootstrap.ts": "import { logger } from '@core/logger';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "apps/web/src/bootstrap.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "libs", "core", "logger.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_ExtendingConfigOverridesPaths(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"tsconfig.base.json": `{"compilerOptions": {"paths": {"@lib/*": ["old/*"]}}}`,
+		"app/tsconfig.json":  `{"extends": "../tsconfig.base.json", "compilerOptions": {"paths": {"@lib/*": ["lib/*"]}}}`,
+		"old/a.ts":           "export const a = 1;\n",
+		"app/lib/a.ts":       "export const a = 2;\n",
+		"app/main.ts":        "import { a } from '@lib/a';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "app/main.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "app", "lib", "a.ts")}, resolved)
+}
+
+func TestResolveTypeScriptProjectImports_WithoutTSConfigKeepsBuiltInRules(t *testing.T) {
+	dir, supplied := writeTSProject(t, map[string]string{
+		"src/components/button.ts": "export const button = 1;\n",
+		"src/app/models/user.ts":   "export const user = 1;\n",
+		"src/main.ts":              "import { button } from '@/components/button';\nimport { user } from '@app/models/user';\n",
+	})
+
+	resolved := resolveWithTSConfig(t, dir, supplied, "src/main.ts")
+
+	assert.Equal(t, []string{filepath.Join(dir, "src", "components", "button.ts")}, resolved)
+}
+
+func TestTSConfigAliasBasePaths_LongestPrefixWins(t *testing.T) {
+	config := &tsConfig{
+		pathsBase: "/repo",
+		paths: map[string][]string{
+			"@app/*":        {"src/*"},
+			"@app/models/*": {"models/*"},
+			"@app/exact":    {"exact/index"},
+		},
+	}
+
+	assert.Equal(t, []string{filepath.Clean("/repo/models/user")}, config.aliasBasePaths("@app/models/user"))
+	assert.Equal(t, []string{filepath.Clean("/repo/src/views/home")}, config.aliasBasePaths("@app/views/home"))
+	assert.Equal(t, []string{filepath.Clean("/repo/exact/index")}, config.aliasBasePaths("@app/exact"))
+	assert.Empty(t, config.aliasBasePaths("lodash"))
+}
+
+func TestStripJSONComments_KeepsStrings(t *testing.T) {
+	input := `{"a": "http://example.com/*x*/", /* note */ "b": [1, 2,], // end
+}`
+
+	assert.JSONEq(t, `{"a": "http://example.com/*x*/", "b": [1, 2]}`, string(stripJSONComments([]byte(input))))
+}
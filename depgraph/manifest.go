@@ -0,0 +1,114 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	graphlib "github.com/dominikbraun/graph"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+)
+
+var (
+	goSourceExtensions     = []string{".go"}
+	jsSourceExtensions     = []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts"}
+	dartSourceExtensions   = []string{".dart"}
+	gradleSourceExtensions = []string{".kt", ".kts", ".java"}
+	rustSourceExtensions   = []string{".rs"}
+)
+
+// dependencyManifests maps the base names of well-known dependency manifests and lock
+// files to the extensions of the source files whose dependencies they declare.
+var dependencyManifests = map[string][]string{
+	"go.mod":              goSourceExtensions,
+	"go.sum":              goSourceExtensions,
+	"package.json":        jsSourceExtensions,
+	"package-lock.json":   jsSourceExtensions,
+	"npm-shrinkwrap.json": jsSourceExtensions,
+	"yarn.lock":           jsSourceExtensions,
+	"pnpm-lock.yaml":      jsSourceExtensions,
+	"pubspec.yaml":        dartSourceExtensions,
+	"pubspec.lock":        dartSourceExtensions,
+	"build.gradle":        gradleSourceExtensions,
+	"build.gradle.kts":    gradleSourceExtensions,
+	"Cargo.toml":          rustSourceExtensions,
+	"Cargo.lock":          rustSourceExtensions,
+}
+
+// IsDependencyManifest reports whether path names a well-known dependency manifest or
+// lock file, such as go.mod, package.json, or Cargo.lock.
+func IsDependencyManifest(path string) bool {
+	_, ok := dependencyManifests[filepath.Base(path)]
+	return ok
+}
+
+// ManifestEdges returns an edge from each source file in files to the manifests that
+// declare its dependencies: the manifests of its language in files that sit in the
+// nearest directory, at or above the file, holding any. Edges are sorted by source
+// then manifest.
+func ManifestEdges(files []string) []FileEdge {
+	manifestsByDir := make(map[string][]string)
+	for _, file := range files {
+		if IsDependencyManifest(file) {
+			dir := filepath.Dir(file)
+			manifestsByDir[dir] = append(manifestsByDir[dir], file)
+		}
+	}
+	if len(manifestsByDir) == 0 {
+		return nil
+	}
+
+	var edges []FileEdge
+	for _, file := range files {
+		if IsDependencyManifest(file) || IsExternalNode(file) {
+			continue
+		}
+		ext := filepath.Ext(file)
+		for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+			var manifests []string
+			for _, manifest := range manifestsByDir[dir] {
+				if slices.Contains(dependencyManifests[filepath.Base(manifest)], ext) {
+					manifests = append(manifests, manifest)
+				}
+			}
+			for _, manifest := range manifests {
+				edges = append(edges, FileEdge{From: file, To: manifest})
+			}
+			if len(manifests) > 0 || filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// LinkManifests adds the ManifestEdges of the nodes of g to it, marked with
+// EdgeProvenanceManifest, so a changed manifest shows the files it affects.
+func LinkManifests(g DependencyGraph) error {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return err
+	}
+	nodes := make([]string, 0, len(adjacency))
+	for node := range adjacency {
+		nodes = append(nodes, node)
+	}
+
+	for _, edge := range ManifestEdges(nodes) {
+		err := g.AddEdge(edge.From, edge.To, moduleapi.WithEdgeProvenance(string(EdgeProvenanceManifest)))
+		if err != nil && !errors.Is(err, graphlib.ErrEdgeAlreadyExists) {
+			return fmt.Errorf("failed to add graph edge %s -> %s: %w", edge.From, edge.To, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package depgraph
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+func TestIsDependencyManifest(t *testing.T) {
+	tests := map[string]bool{
+		"/repo/go.mod":                true,
+		"/repo/go.sum":                true,
+		"/repo/web/package.json":      true,
+		"/repo/web/package-lock.json": true,
+		"/repo/web/yarn.lock":         true,
+		"/repo/web/pnpm-lock.yaml":    true,
+		"/repo/app/pubspec.yaml":      true,
+		"/repo/app/build.gradle":      true,
+		"/repo/app/build.gradle.kts":  true,
+		"/repo/crate/Cargo.toml":      true,
+		"/repo/main.go":               false,
+		"/repo/config/package.yaml":   false,
+		"/repo/docs/go.mod.md":        false,
+		"/repo/settings.gradle.kts":   false,
+	}
+
+	for path, want := range tests {
+		if got := IsDependencyManifest(path); got != want {
+			t.Errorf("IsDependencyManifest(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestManifestEdges_LinksFilesToNearestManifestOfTheirLanguage(t *testing.T) {
+	files := []string{
+		"/repo/go.mod",
+		"/repo/go.sum",
+		"/repo/main.go",
+		"/repo/internal/util/util.go",
+		"/repo/web/package.json",
+		"/repo/web/src/app.ts",
+		"/repo/tools/go.mod",
+		"/repo/tools/gen.go",
+		"/repo/README.md",
+	}
+
+	edges := ManifestEdges(files)
+
+	want := []FileEdge{
+		{From: "/repo/internal/util/util.go", To: "/repo/go.mod"},
+		{From: "/repo/internal/util/util.go", To: "/repo/go.sum"},
+		{From: "/repo/main.go", To: "/repo/go.mod"},
+		{From: "/repo/main.go", To: "/repo/go.sum"},
+		{From: "/repo/tools/gen.go", To: "/repo/tools/go.mod"},
+		{From: "/repo/web/src/app.ts", To: "/repo/web/package.json"},
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Fatalf("ManifestEdges() = %v, want %v", edges, want)
+	}
+}
+
+func TestLinkManifests_MarksEdgesAndManifestNodes(t *testing.T) {
+	dir := t.TempDir()
+	goMod := filepath.Join(dir, "go.mod")
+	mainFile := filepath.Join(dir, "main.go")
+	writeTestFile(t, goMod, "module example.com/app\n\ngo 1.21\n")
+	writeTestFile(t, mainFile, "package main\n\nfunc main() {}\n")
+
+	graph, err := BuildDependencyGraph([]string{goMod, mainFile}, vcs.FilesystemContentReader())
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+	if err := LinkManifests(graph); err != nil {
+		t.Fatalf("LinkManifests() error = %v", err)
+	}
+
+	fileGraph, err := NewFileDependencyGraph(graph, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFileDependencyGraph() error = %v", err)
+	}
+	if kind := fileGraph.Meta.Files[goMod].Kind; kind != NodeKindManifest {
+		t.Fatalf("go.mod kind = %q, want %q", kind, NodeKindManifest)
+	}
+	if kind := fileGraph.Meta.Files[mainFile].Kind; kind != NodeKindFile {
+		t.Fatalf("main.go kind = %q, want a file", kind)
+	}
+	edge, ok := fileGraph.Meta.Edges[FileEdge{From: mainFile, To: goMod}]
+	if !ok || edge.Provenance != EdgeProvenanceManifest {
+		t.Fatalf("expected main.go -> go.mod with manifest provenance, got %+v (present %v)", edge, ok)
+	}
+}
@@ -0,0 +1,180 @@
+package depgraph
+
+import (
+	"bytes"
+	"sort"
+)
+
+// GraphMetrics summarizes the size and coupling of a dependency graph.
+type GraphMetrics struct {
+	Nodes int
+	Edges int
+	// Cycles counts groups of files that depend on each other, one per strongly
+	// connected component with a cycle.
+	Cycles int
+	// AverageDegree is the mean number of edges touching a file, counting both
+	// incoming and outgoing edges.
+	AverageDegree float64
+}
+
+// ComputeMetrics returns the metrics for g. Commands that report graph metrics share
+// this computation so their numbers are comparable.
+func ComputeMetrics(g DependencyGraph) (GraphMetrics, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return GraphMetrics{}, err
+	}
+
+	metrics := GraphMetrics{Nodes: len(adjacency)}
+	for _, deps := range adjacency {
+		metrics.Edges += len(deps)
+	}
+
+	cycles, _ := findCyclesAndCycleEdges(adjacency)
+	metrics.Cycles = len(cycles)
+
+	if metrics.Nodes > 0 {
+		metrics.AverageDegree = float64(2*metrics.Edges) / float64(metrics.Nodes)
+	}
+	return metrics, nil
+}
+
+// FileDegree is a file and the number of its edges in one direction.
+type FileDegree struct {
+	File   string
+	Degree int
+}
+
+// FanIn returns the number of files importing each file of g, including 0 for files
+// nothing imports.
+func FanIn(g DependencyGraph) (map[string]int, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return nil, err
+	}
+
+	fanIn := make(map[string]int, len(adjacency))
+	for file := range adjacency {
+		fanIn[file] = 0
+	}
+	for _, deps := range adjacency {
+		for _, dep := range deps {
+			fanIn[dep]++
+		}
+	}
+	return fanIn, nil
+}
+
+// FanOut returns the number of dependencies of each file of g, including 0 for files
+// without any.
+func FanOut(g DependencyGraph) (map[string]int, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return nil, err
+	}
+
+	fanOut := make(map[string]int, len(adjacency))
+	for file, deps := range adjacency {
+		fanOut[file] = len(deps)
+	}
+	return fanOut, nil
+}
+
+// CountLines counts the lines of content, including a last line without a newline.
+func CountLines(content []byte) int {
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// TopFanIn returns up to n files with the most dependents, most first. Files with
+// the same count are ordered by path, and files without dependents are left out.
+func TopFanIn(g DependencyGraph, n int) ([]FileDegree, error) {
+	fanIn, err := FanIn(g)
+	if err != nil {
+		return nil, err
+	}
+	return topDegrees(fanIn, n), nil
+}
+
+// TopFanOut returns up to n files with the most dependencies, most first. Files with
+// the same count are ordered by path, and files without dependencies are left out.
+func TopFanOut(g DependencyGraph, n int) ([]FileDegree, error) {
+	fanOut, err := FanOut(g)
+	if err != nil {
+		return nil, err
+	}
+	return topDegrees(fanOut, n), nil
+}
+
+func topDegrees(degrees map[string]int, n int) []FileDegree {
+	result := make([]FileDegree, 0, len(degrees))
+	for file, degree := range degrees {
+		if degree > 0 {
+			result = append(result, FileDegree{File: file, Degree: degree})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Degree != result[j].Degree {
+			return result[i].Degree > result[j].Degree
+		}
+		return result[i].File < result[j].File
+	})
+	if n >= 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// IsolatedNodes returns the files of g with no edges in either direction, sorted.
+func IsolatedNodes(g DependencyGraph) ([]string, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return nil, err
+	}
+
+	connected := make(map[string]bool, len(adjacency))
+	for file, deps := range adjacency {
+		for _, dep := range deps {
+			connected[file] = true
+			connected[dep] = true
+		}
+	}
+
+	var isolated []string
+	for file := range adjacency {
+		if !connected[file] {
+			isolated = append(isolated, file)
+		}
+	}
+	sort.Strings(isolated)
+	return isolated, nil
+}
+
+// LargestComponentSize returns the number of files in the largest weakly connected
+// component of g, or 0 for an empty graph.
+func LargestComponentSize(g DependencyGraph) (int, error) {
+	components, err := WeaklyConnectedComponents(g)
+	if err != nil {
+		return 0, err
+	}
+	if len(components) == 0 {
+		return 0, nil
+	}
+	// Components are ordered largest first.
+	return len(components[0]), nil
+}
+
+// CountTestFiles returns how many files of fg are test files and how many are not.
+func CountTestFiles(fg FileDependencyGraph) (tests, nonTests int) {
+	for _, md := range fg.Meta.Files {
+		if md.IsTest {
+			tests++
+		} else {
+			nonTests++
+		}
+	}
+	return tests, nonTests
+}
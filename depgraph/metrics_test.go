@@ -0,0 +1,122 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {"c.go"},
+		"c.go": {"a.go"},
+		"d.go": {"e.go"},
+		"e.go": {"d.go"},
+		"f.go": {"a.go"},
+		"g.go": {},
+	})
+
+	metrics, err := ComputeMetrics(graph)
+	require.NoError(t, err)
+
+	assert.Equal(t, GraphMetrics{
+		Nodes:         7,
+		Edges:         6,
+		Cycles:        2,
+		AverageDegree: 12.0 / 7.0,
+	}, metrics)
+}
+
+func TestComputeMetrics_EmptyGraph(t *testing.T) {
+	metrics, err := ComputeMetrics(NewDependencyGraph())
+	require.NoError(t, err)
+
+	assert.Equal(t, GraphMetrics{}, metrics)
+}
+
+func TestTopFanInAndFanOut(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"a.go": {"c.go", "d.go", "e.go"},
+		"b.go": {"c.go", "d.go"},
+		"c.go": {"e.go"},
+		"d.go": {},
+		"e.go": {},
+		"f.go": {},
+	})
+
+	fanIn, err := TopFanIn(graph, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []FileDegree{{File: "c.go", Degree: 2}, {File: "d.go", Degree: 2}}, fanIn)
+
+	fanOut, err := TopFanOut(graph, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []FileDegree{
+		{File: "a.go", Degree: 3},
+		{File: "b.go", Degree: 2},
+		{File: "c.go", Degree: 1},
+	}, fanOut)
+}
+
+func TestFanInAndFanOut_IncludeFilesWithoutEdges(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"a.go": {"b.go", "c.go"},
+		"b.go": {"c.go"},
+		"c.go": {},
+		"d.go": {},
+	})
+
+	fanIn, err := FanIn(graph)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a.go": 0, "b.go": 1, "c.go": 2, "d.go": 0}, fanIn)
+
+	fanOut, err := FanOut(graph)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a.go": 2, "b.go": 1, "c.go": 0, "d.go": 0}, fanOut)
+}
+
+func TestCountLines(t *testing.T) {
+	assert.Equal(t, 0, CountLines(nil))
+	assert.Equal(t, 1, CountLines([]byte("package a")))
+	assert.Equal(t, 2, CountLines([]byte("package a\n\n")))
+	assert.Equal(t, 3, CountLines([]byte("package a\n\nvar x = 1")))
+}
+
+func TestIsolatedNodesAndLargestComponent(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {},
+		"c.go": {"a.go"},
+		"d.go": {"e.go"},
+		"e.go": {},
+		"f.go": {},
+		"g.go": {},
+	})
+
+	isolated, err := IsolatedNodes(graph)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f.go", "g.go"}, isolated)
+
+	largest, err := LargestComponentSize(graph)
+	require.NoError(t, err)
+	assert.Equal(t, 3, largest)
+
+	largest, err = LargestComponentSize(NewDependencyGraph())
+	require.NoError(t, err)
+	assert.Equal(t, 0, largest)
+}
+
+func TestCountTestFiles(t *testing.T) {
+	fileGraph, err := NewFileDependencyGraph(testGraph(map[string][]string{
+		"/project/main.go":      {},
+		"/project/main_test.go": {"/project/main.go"},
+		"/project/util.go":      {},
+	}), nil, nil)
+	require.NoError(t, err)
+
+	tests, nonTests := CountTestFiles(fileGraph)
+
+	assert.Equal(t, 1, tests)
+	assert.Equal(t, 2, nonTests)
+}
@@ -0,0 +1,66 @@
+package moduleapi
+
+import (
+	"sort"
+	"sync"
+)
+
+// DiagnosticCategory names a kind of import that resolves but that a team may want
+// to allow or ban by policy.
+type DiagnosticCategory string
+
+const (
+	// DiagnosticCrossPackageRelativeImport marks a relative import that climbs out of
+	// the importing file's package into another package of the same repository.
+	DiagnosticCrossPackageRelativeImport DiagnosticCategory = "cross-package-relative-import"
+)
+
+// Diagnostic describes one notable import found while resolving dependencies.
+type Diagnostic struct {
+	Category DiagnosticCategory
+	// File is the absolute path of the importing file.
+	File string
+	// Target is the absolute path the import resolves to.
+	Target string
+	// Linked reports whether the import became a graph edge; it is false when the
+	// target is outside the analyzed files.
+	Linked bool
+}
+
+// Diagnostics collects diagnostics reported by resolvers. It is safe for concurrent
+// use, and a nil *Diagnostics discards reports.
+type Diagnostics struct {
+	mu    sync.Mutex
+	items []Diagnostic
+}
+
+// Report records diagnostic.
+func (d *Diagnostics) Report(diagnostic Diagnostic) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.items = append(d.items, diagnostic)
+}
+
+// All returns the recorded diagnostics ordered by category, file, and target.
+func (d *Diagnostics) All() []Diagnostic {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	items := append([]Diagnostic(nil), d.items...)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Category != items[j].Category {
+			return items[i].Category < items[j].Category
+		}
+		if items[i].File != items[j].File {
+			return items[i].File < items[j].File
+		}
+		return items[i].Target < items[j].Target
+	})
+	return items
+}
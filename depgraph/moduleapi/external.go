@@ -0,0 +1,81 @@
+package moduleapi
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Ecosystems of external packages. An external node is named after its ecosystem and
+// package, as in "go:github.com/spf13/cobra", "pkg:flutter/material", or "npm:lodash".
+const (
+	EcosystemGo   = "go"
+	EcosystemDart = "pkg"
+	EcosystemNPM  = "npm"
+)
+
+var ecosystems = map[string]bool{EcosystemGo: true, EcosystemDart: true, EcosystemNPM: true}
+
+// ExternalNode returns the graph node name of an external package.
+func ExternalNode(ecosystem, pkg string) string {
+	return ecosystem + ":" + pkg
+}
+
+// IsExternalNode reports whether node names an external package rather than a file.
+// File nodes are absolute paths, so they never start with an ecosystem prefix; a
+// Windows drive letter is a single character.
+func IsExternalNode(node string) bool {
+	ecosystem, pkg, ok := strings.Cut(node, ":")
+	return ok && pkg != "" && ecosystems[ecosystem]
+}
+
+// ExternalImport is a file's import of an external package.
+type ExternalImport struct {
+	// From is the absolute path of the importing file.
+	From string
+	// Node is the ExternalNode name of the imported package.
+	Node string
+}
+
+// ExternalImports collects the external packages resolvers saw each file import. It
+// is safe for concurrent use, and a nil *ExternalImports discards records, so
+// resolvers can report unconditionally.
+type ExternalImports struct {
+	mu      sync.Mutex
+	imports map[ExternalImport]bool
+}
+
+// Record notes that from imports the external package ecosystem:pkg.
+func (e *ExternalImports) Record(from, ecosystem, pkg string) {
+	if e == nil || pkg == "" {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.imports == nil {
+		e.imports = make(map[ExternalImport]bool)
+	}
+	e.imports[ExternalImport{From: from, Node: ExternalNode(ecosystem, pkg)}] = true
+}
+
+// All returns the recorded imports, each once, ordered by importing file and package.
+func (e *ExternalImports) All() []ExternalImport {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	imports := make([]ExternalImport, 0, len(e.imports))
+	for imp := range e.imports {
+		imports = append(imports, imp)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		if imports[i].From != imports[j].From {
+			return imports[i].From < imports[j].From
+		}
+		return imports[i].Node < imports[j].Node
+	})
+	return imports
+}
@@ -10,3 +10,9 @@ type Module interface {
 	NewResolver(ctx *Context, contentReader vcs.ContentReader) Resolver
 	IsTestFile(filePath string, contentReader vcs.ContentReader) bool
 }
+
+// InterpreterModule is implemented by modules whose extensionless scripts can be
+// recognized by the interpreter named in their shebang line, e.g. "python3".
+type InterpreterModule interface {
+	Interpreters() []string
+}
@@ -0,0 +1,44 @@
+package moduleapi
+
+import (
+	"runtime"
+	"sync"
+)
+
+// WorkerCount returns how many goroutines should share n jobs: parallelism, or
+// GOMAXPROCS when parallelism is 0 or less, but never more than n nor less than 1.
+func WorkerCount(n, parallelism int) int {
+	workers := parallelism
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// ParallelFor calls fn for every index in [0, n) on WorkerCount(n, parallelism)
+// goroutines and returns once every call has returned. Callers that need ordered
+// results write them to index i of a slice and read it afterwards.
+func ParallelFor(n, parallelism int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range WorkerCount(n, parallelism) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package moduleapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// ParseCache stores what language parsers extract from a file, keyed by the file's
+// content, so a file whose content is unchanged is not parsed again. Implementations
+// must be safe for concurrent use.
+type ParseCache interface {
+	// Load decodes the value stored under key into value and reports whether there was
+	// one.
+	Load(key string, value any) bool
+	// Store records value under key. Failures are not reported: they only cost a later
+	// parse.
+	Store(key string, value any)
+}
+
+// ParseFile reads filePath through contentReader and returns parse(content), or the
+// value cache holds for the same content under kind. Content is identified by the
+// reader's hash when it has one (see vcs.ContentHasher), so a hit does not read the
+// file at all, and by a SHA-256 of the content otherwise. kind names the parser and
+// the layout of T, and must change whenever either does. A nil cache always reads and
+// parses, and failed parses are not stored.
+func ParseFile[T any](cache ParseCache, contentReader vcs.ContentReader, kind, filePath string, parse func(content []byte) (T, error)) (T, error) {
+	var zero T
+	if cache != nil {
+		if hasher, ok := contentReader.(vcs.ContentHasher); ok {
+			if hash, err := hasher.ContentHash(filePath); err == nil {
+				key := kind + "\x00hash:" + hash
+				var cached T
+				if cache.Load(key, &cached) {
+					return cached, nil
+				}
+				content, err := contentReader.ReadFile(filePath)
+				if err != nil {
+					return zero, fmt.Errorf("failed to read %s: %w", filePath, err)
+				}
+				value, err := parse(content)
+				if err == nil {
+					cache.Store(key, value)
+				}
+				return value, err
+			}
+		}
+	}
+
+	content, err := contentReader.ReadFile(filePath)
+	if err != nil {
+		return zero, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	return CachedParse(cache, kind, content, parse)
+}
+
+// CachedParse returns parse(content), or the value cache holds under kind for the
+// same content, identified by its SHA-256. It is for content already read; see
+// ParseFile for the rest.
+func CachedParse[T any](cache ParseCache, kind string, content []byte, parse func(content []byte) (T, error)) (T, error) {
+	if cache == nil {
+		return parse(content)
+	}
+	sum := sha256.Sum256(content)
+	key := kind + "\x00sha256:" + hex.EncodeToString(sum[:])
+	var cached T
+	if cache.Load(key, &cached) {
+		return cached, nil
+	}
+	value, err := parse(content)
+	if err == nil {
+		cache.Store(key, value)
+	}
+	return value, err
+}
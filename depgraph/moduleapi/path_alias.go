@@ -0,0 +1,26 @@
+package moduleapi
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CanonicalPath returns path with the longest prefix that aliases maps replaced by
+// its canonical path, as in /repo/shared/util/util.go for /repo/lib/util/util.go when
+// /repo/lib/util is a symlink to /repo/shared/util. Paths no alias covers are
+// returned unchanged.
+func CanonicalPath(aliases map[string]string, path string) string {
+	if len(aliases) == 0 {
+		return path
+	}
+	for prefix := path; ; {
+		if canonical, ok := aliases[prefix]; ok {
+			return canonical + strings.TrimPrefix(path, prefix)
+		}
+		parent := filepath.Dir(prefix)
+		if parent == prefix {
+			return path
+		}
+		prefix = parent
+	}
+}
@@ -0,0 +1,16 @@
+package moduleapi
+
+import graphlib "github.com/dominikbraun/graph"
+
+// EdgeProvenanceAttribute is the edge attribute key that records how an edge was
+// discovered. Edges without it were found by regular import resolution.
+const EdgeProvenanceAttribute = "provenance"
+
+// ExpectActualProvenance tags edges that link a Kotlin actual declaration to its
+// expect declaration.
+const ExpectActualProvenance = "expect-actual"
+
+// WithEdgeProvenance returns an AddEdge option that tags the edge with provenance.
+func WithEdgeProvenance(provenance string) func(*graphlib.EdgeProperties) {
+	return graphlib.EdgeAttribute(EdgeProvenanceAttribute, provenance)
+}
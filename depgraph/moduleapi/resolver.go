@@ -21,4 +21,29 @@ type Context struct {
 	JavaFiles     []string
 	KotlinFiles   []string
 	GoFiles       []string
+	// Diagnostics receives notable imports found during resolution. It may be nil.
+	Diagnostics *Diagnostics
+	// EdgeSymbols receives the symbols each file uses from its dependencies, for
+	// resolvers that know them. It may be nil.
+	EdgeSymbols *EdgeSymbols
+	// ExternalImports receives the third-party packages files import, for resolvers
+	// that tell them apart. It is nil unless external packages are to be shown.
+	ExternalImports *ExternalImports
+	// Parallelism bounds how many files resolvers parse at once when building their
+	// indices; 0 means GOMAXPROCS.
+	Parallelism int
+	// ParseCache reuses what parsers extracted from files whose content is unchanged;
+	// resolvers pass it to ParseFile and CachedParse. It may be nil.
+	ParseCache ParseCache
+	// Lenient makes passes that would fail on an unreadable or unparsable file log a
+	// warning and skip the file instead.
+	Lenient bool
+	// GoModules maps Go import path prefixes to the absolute directories holding
+	// their packages, for trees without go.mod files such as Bazel monorepos. When
+	// set, Go imports are resolved through it alone and go.mod files are not read.
+	GoModules map[string]string
+	// PathAliases maps symlinks inside the analyzed tree to the canonical paths they
+	// point at, so imports written through a symlinked directory resolve to the files
+	// behind it; see CanonicalPath.
+	PathAliases map[string]string
 }
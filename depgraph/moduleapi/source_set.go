@@ -0,0 +1,52 @@
+package moduleapi
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Gradle and Maven source sets. Test source sets compile against main, so their
+// files see main's declarations, while main never sees theirs.
+const (
+	SourceSetMain        = "main"
+	SourceSetTest        = "test"
+	SourceSetAndroidTest = "androidTest"
+	SourceSetTestFixture = "testFixtures"
+)
+
+var testSourceSets = map[string]bool{SourceSetTest: true, SourceSetAndroidTest: true, SourceSetTestFixture: true}
+
+// SourceSet returns the Gradle or Maven source set holding file: the module directory
+// containing src, and the set's name, as in /repo/app and test for
+// /repo/app/src/test/kotlin/App.kt. ok is false for files outside a recognized
+// src/<set>/ directory.
+func SourceSet(file string) (module, name string, ok bool) {
+	slashed := filepath.ToSlash(file)
+	for rest := slashed; ; {
+		i := strings.LastIndex(rest, "/src/")
+		if i < 0 {
+			return "", "", false
+		}
+		set, _, found := strings.Cut(slashed[i+len("/src/"):], "/")
+		if found && (set == SourceSetMain || testSourceSets[set]) {
+			return filepath.FromSlash(slashed[:i]), set, true
+		}
+		rest = rest[:i]
+	}
+}
+
+// SourceSetSees reports whether a same-package reference in from may resolve to a
+// declaration in to. Files of one module see their own source set, and test source
+// sets also see main. Files outside recognized source sets, or in different modules,
+// see each other.
+func SourceSetSees(from, to string) bool {
+	fromModule, fromSet, ok := SourceSet(from)
+	if !ok {
+		return true
+	}
+	toModule, toSet, ok := SourceSet(to)
+	if !ok || fromModule != toModule {
+		return true
+	}
+	return fromSet == toSet || (toSet == SourceSetMain && testSourceSets[fromSet])
+}
@@ -0,0 +1,122 @@
+package moduleapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	graphlib "github.com/dominikbraun/graph"
+)
+
+// EdgeSymbolsAttribute is the edge attribute key that lists the symbols the source
+// file uses from the target file, sorted and comma-separated.
+const EdgeSymbolsAttribute = "symbols"
+
+// WithEdgeSymbols returns an AddEdge or UpdateEdge option that records the symbols
+// used across the edge.
+func WithEdgeSymbols(symbols []string) func(*graphlib.EdgeProperties) {
+	return graphlib.EdgeAttribute(EdgeSymbolsAttribute, strings.Join(symbols, ","))
+}
+
+// EdgeUsagesAttribute is the edge attribute key that counts the places the source file
+// uses symbols of the target file, in decimal.
+const EdgeUsagesAttribute = "usages"
+
+// WithEdgeUsages returns an AddEdge or UpdateEdge option that records how many times
+// symbols are used across the edge.
+func WithEdgeUsages(usages int) func(*graphlib.EdgeProperties) {
+	return graphlib.EdgeAttribute(EdgeUsagesAttribute, strconv.Itoa(usages))
+}
+
+// SymbolEdge lists the symbols a file uses from one of its dependencies.
+type SymbolEdge struct {
+	// From and To are absolute file paths.
+	From    string
+	To      string
+	Symbols []string
+	// Usages counts the places From uses Symbols, or is 0 when the resolver does not
+	// count them.
+	Usages int
+}
+
+// EdgeSymbols collects the symbols resolvers saw each file use from the files it
+// depends on. It is safe for concurrent use, and a nil *EdgeSymbols discards records.
+type EdgeSymbols struct {
+	mu     sync.Mutex
+	edges  map[[2]string]map[string]bool
+	usages map[[2]string]int
+}
+
+// Record notes that from uses symbols defined in to.
+func (s *EdgeSymbols) Record(from, to string, symbols ...string) {
+	if s == nil || len(symbols) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.edges == nil {
+		s.edges = make(map[[2]string]map[string]bool)
+	}
+	key := [2]string{from, to}
+	set, ok := s.edges[key]
+	if !ok {
+		set = make(map[string]bool, len(symbols))
+		s.edges[key] = set
+	}
+	for _, symbol := range symbols {
+		if symbol != "" {
+			set[symbol] = true
+		}
+	}
+}
+
+// RecordUsages adds usages to the count of places from uses symbols defined in to.
+func (s *EdgeSymbols) RecordUsages(from, to string, usages int) {
+	if s == nil || usages <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usages == nil {
+		s.usages = make(map[[2]string]int)
+	}
+	s.usages[[2]string{from, to}] += usages
+}
+
+// All returns the recorded edges ordered by source and target, each with its
+// symbols sorted.
+func (s *EdgeSymbols) All() []SymbolEdge {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	edges := make([]SymbolEdge, 0, len(s.edges))
+	for key, set := range s.edges {
+		if len(set) == 0 {
+			continue
+		}
+		symbols := make([]string, 0, len(set))
+		for symbol := range set {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		edges = append(edges, SymbolEdge{From: key[0], To: key[1], Symbols: symbols, Usages: s.usages[key]})
+	}
+	for key, usages := range s.usages {
+		if len(s.edges[key]) == 0 {
+			edges = append(edges, SymbolEdge{From: key[0], To: key[1], Usages: usages})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
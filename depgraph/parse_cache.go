@@ -0,0 +1,223 @@
+package depgraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// parseCacheVersion is bumped whenever the entry layout changes, so entries written by
+// an older layout are ignored.
+const parseCacheVersion = 2
+
+// DefaultParseCacheMaxBytes is the size the default cache is pruned back to.
+const DefaultParseCacheMaxBytes int64 = 256 << 20
+
+// parseCacheTouchInterval is how stale an entry's modification time may get before a
+// hit refreshes it. Pruning removes the entries used least recently first, and this
+// keeps a warm run from rewriting the time of every entry it reads.
+const parseCacheTouchInterval = time.Hour
+
+// ParseCache stores on disk what language parsers extracted from files, such as their
+// import lists, so a build skips parsing every file whose content it has seen before.
+// An entry is keyed by a hash of one file's content: the blob SHA when the content
+// reader has one (see vcs.ContentHasher), and a SHA-256 of the content otherwise. A
+// changed file gets a new key, so entries never go stale, and files are still resolved
+// against the analyzed tree on every build. Once the entries outgrow the size limit,
+// those used least recently are removed after the next build that adds one.
+type ParseCache struct {
+	dir      string
+	maxBytes int64
+	// binaryID identifies the running executable, so a rebuilt binary, whose parsers
+	// may behave differently, does not reuse old entries.
+	binaryID string
+	// stored reports that an entry was added since the cache was last pruned.
+	stored atomic.Bool
+}
+
+// NewParseCache returns a cache that stores its entries in dir and is pruned back to
+// maxBytes; a maxBytes of 0 or less never prunes.
+func NewParseCache(dir string, maxBytes int64) *ParseCache {
+	return &ParseCache{dir: dir, maxBytes: maxBytes, binaryID: executableID()}
+}
+
+// CacheDirEnv names the environment variable that, when set, replaces the user's cache
+// directory as the one clarity caches in.
+const CacheDirEnv = "CLARITY_CACHE_DIR"
+
+// DefaultParseCacheDir returns the directory clarity caches parse results in, below
+// $CLARITY_CACHE_DIR when it is set and the user's cache directory otherwise. Test
+// binaries have no default: they cache only below $CLARITY_CACHE_DIR, so tests never
+// read or fill the cache of the developer running them.
+func DefaultParseCacheDir() (string, error) {
+	if dir := os.Getenv(CacheDirEnv); dir != "" {
+		return filepath.Join(dir, "parses"), nil
+	}
+	if testing.Testing() {
+		return "", fmt.Errorf("no parse cache in tests unless %s is set", CacheDirEnv)
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, "clarity", "parses"), nil
+}
+
+// Dir returns the directory the cache stores its entries in.
+func (c *ParseCache) Dir() string {
+	return c.dir
+}
+
+// Clear removes every cached entry.
+func (c *ParseCache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear parse cache %s: %w", c.dir, err)
+	}
+	return nil
+}
+
+// Load decodes the entry stored under key into value and reports whether there was
+// one. It implements moduleapi.ParseCache.
+func (c *ParseCache) Load(key string, value any) bool {
+	path := c.entryPath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		slog.Debug("ignoring corrupt parse cache entry", "entry", path, "error", err)
+		return false
+	}
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > parseCacheTouchInterval {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+	}
+	return true
+}
+
+// Store records value under key, writing through a temporary file so concurrent runs
+// never see a partial entry. It implements moduleapi.ParseCache.
+func (c *ParseCache) Store(key string, value any) {
+	path := c.entryPath(key)
+	if err := writeParseCacheEntry(path, value); err != nil {
+		slog.Debug("failed to write parse cache entry", "entry", path, "error", err)
+		return
+	}
+	c.stored.Store(true)
+}
+
+func writeParseCacheEntry(path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// entryPath spreads entries over subdirectories named after the first byte of their
+// hash, so no directory grows to hold every entry.
+func (c *ParseCache) entryPath(key string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "v%d\n%s\n%s", parseCacheVersion, c.binaryID, key)
+	name := hex.EncodeToString(hash.Sum(nil))
+	return filepath.Join(c.dir, name[:2], name+".json")
+}
+
+// prune removes the entries used least recently until the cache is back under its
+// size limit, with some headroom so the next build does not prune again. It does
+// nothing unless an entry was added since the last prune. Leftover temporary files of
+// interrupted writes are removed too.
+func (c *ParseCache) prune() {
+	if c.maxBytes <= 0 || !c.stored.Swap(false) {
+		return
+	}
+
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var entries []entry
+	var total int64
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if strings.HasSuffix(path, ".tmp") {
+			if time.Since(info.ModTime()) > parseCacheTouchInterval {
+				_ = os.Remove(path)
+			}
+			return nil
+		}
+		entries = append(entries, entry{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		slog.Debug("failed to scan parse cache", "dir", c.dir, "error", err)
+		return
+	}
+	if total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	target := c.maxBytes / 10 * 9
+	removed := 0
+	for _, e := range entries {
+		if total <= target {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		total -= e.size
+		removed++
+	}
+	slog.Debug("pruned parse cache", "dir", c.dir, "removed", removed, "bytes", total)
+}
+
+// executableID identifies the running binary by path, size, and modification time.
+func executableID() string {
+	path, err := os.Executable()
+	if err != nil {
+		return "unknown"
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return path
+	}
+	return fmt.Sprintf("%s %d %d", path, info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,292 @@
+package depgraph_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingContentReader counts full file reads, which is what parsing needs.
+type countingContentReader struct {
+	vcs.ContentReader
+	reads atomic.Int64
+}
+
+func (r *countingContentReader) ReadFile(filePath string) ([]byte, error) {
+	r.reads.Add(1)
+	return r.ContentReader.ReadFile(filePath)
+}
+
+// hashingContentReader identifies content by a caller-provided hash, like a git commit
+// reader does with blob SHAs, and counts the reads it serves.
+type hashingContentReader struct {
+	countingContentReader
+	hashes map[string]string
+}
+
+func newHashingContentReader(hashes map[string]string) *hashingContentReader {
+	return &hashingContentReader{
+		countingContentReader: countingContentReader{ContentReader: vcs.FilesystemContentReader()},
+		hashes:                hashes,
+	}
+}
+
+func (r *hashingContentReader) ContentHash(filePath string) (string, error) {
+	hash, ok := r.hashes[filePath]
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	return hash, nil
+}
+
+func writeFiles(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func writeGoModule(t testing.TB, dir string) []string {
+	t.Helper()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.22\n",
+		"main.go":       "package main\n\nimport \"example.com/app/util\"\n\nfunc main() { util.Helper() }\n",
+		"util/util.go":  "package util\n\nfunc Helper() {}\n",
+		"util/extra.go": "package util\n\nfunc Extra() {}\n",
+	})
+	return []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "util", "util.go"),
+		filepath.Join(dir, "util", "extra.go"),
+	}
+}
+
+// writePythonPackage writes a package whose modules import one another, and returns
+// the module paths with a distinct content hash for each.
+func writePythonPackage(t testing.TB, dir, name string) ([]string, map[string]string) {
+	t.Helper()
+	writeFiles(t, dir, map[string]string{
+		name + "/__init__.py": "",
+		name + "/app.py":      "from .models import Item\nfrom .views import render\n",
+		name + "/models.py":   "import os\n",
+		name + "/views.py":    "from .models import Item\n",
+	})
+	var files []string
+	hashes := make(map[string]string)
+	for _, module := range []string{"__init__.py", "app.py", "models.py", "views.py"} {
+		path := filepath.Join(dir, name, module)
+		files = append(files, path)
+		hashes[path] = name + "/" + module
+	}
+	return files, hashes
+}
+
+// cacheEntries returns the size of every entry in the cache directory by path.
+func cacheEntries(t *testing.T, dir string) map[string]int64 {
+	t.Helper()
+	entries := make(map[string]int64)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries[path] = info.Size()
+		return nil
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+func TestBuildDependencyGraphWithOptions_ParseCacheMatchesFreshBuild(t *testing.T) {
+	dir := t.TempDir()
+	files := writeGoModule(t, dir)
+	cache := depgraph.NewParseCache(t.TempDir(), depgraph.DefaultParseCacheMaxBytes)
+
+	fresh, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{})
+	require.NoError(t, err)
+	for run := 0; run < 2; run++ {
+		cached, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+		require.NoError(t, err)
+		assert.Equal(t, mustAdjacency(t, fresh), mustAdjacency(t, cached), "run %d", run)
+	}
+	assert.Contains(t, mustAdjacency(t, fresh)[files[0]], files[1])
+}
+
+func TestBuildDependencyGraphWithOptions_ParseCacheReparsesChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := writeGoModule(t, dir)
+	cache := depgraph.NewParseCache(t.TempDir(), depgraph.DefaultParseCacheMaxBytes)
+
+	_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(files[0], []byte("package main\n\nfunc main() {}\n"), 0o644))
+	graph, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	assert.Empty(t, mustAdjacency(t, graph)[files[0]])
+}
+
+func TestBuildDependencyGraphWithOptions_ParseCacheResolvesAgainstCurrentManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := writeGoModule(t, dir)
+	cache := depgraph.NewParseCache(t.TempDir(), depgraph.DefaultParseCacheMaxBytes)
+
+	_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	// No analyzed file changed, but the module path their imports resolve against did.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/renamed\n\ngo 1.22\n"), 0o644))
+	graph, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	assert.NotContains(t, mustAdjacency(t, graph)[files[0]], files[1])
+}
+
+func TestBuildDependencyGraphWithOptions_ParseCacheSkipsReadingFilesWithKnownHashes(t *testing.T) {
+	dir := t.TempDir()
+	files, hashes := writePythonPackage(t, dir, "shop")
+	cache := depgraph.NewParseCache(t.TempDir(), depgraph.DefaultParseCacheMaxBytes)
+
+	cold, _, err := depgraph.BuildDependencyGraphWithOptions(files, newHashingContentReader(hashes), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	warmReader := newHashingContentReader(hashes)
+	warm, _, err := depgraph.BuildDependencyGraphWithOptions(files, warmReader, depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	assert.Equal(t, mustAdjacency(t, cold), mustAdjacency(t, warm))
+	assert.Contains(t, mustAdjacency(t, warm)[files[1]], files[2])
+	assert.Zero(t, warmReader.reads.Load())
+}
+
+func TestBuildDependencyGraphWithOptions_ParseCacheStoresOneEntryPerFile(t *testing.T) {
+	dir := t.TempDir()
+	files, _ := writePythonPackage(t, dir, "shop")
+	cacheDir := t.TempDir()
+	cache := depgraph.NewParseCache(cacheDir, depgraph.DefaultParseCacheMaxBytes)
+
+	_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	assert.Len(t, cacheEntries(t, cacheDir), len(files))
+}
+
+func TestBuildDependencyGraphWithOptions_ParseCachePrunesLeastRecentlyUsedEntries(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := t.TempDir()
+	oldFiles, _ := writePythonPackage(t, dir, "old")
+	_, _, err := depgraph.BuildDependencyGraphWithOptions(oldFiles, vcs.FilesystemContentReader(), depgraph.BuildOptions{
+		Cache: depgraph.NewParseCache(cacheDir, 0),
+	})
+	require.NoError(t, err)
+	oldEntries := cacheEntries(t, cacheDir)
+	var oldSize int64
+	lastUsed := time.Now().Add(-48 * time.Hour)
+	for path, size := range oldEntries {
+		oldSize += size
+		require.NoError(t, os.Chtimes(path, lastUsed, lastUsed))
+	}
+
+	newFiles, hashes := writePythonPackage(t, dir, "new")
+	newFiles = newFiles[:2]
+	cache := depgraph.NewParseCache(cacheDir, oldSize)
+	_, _, err = depgraph.BuildDependencyGraphWithOptions(newFiles, newHashingContentReader(hashes), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+
+	entries := cacheEntries(t, cacheDir)
+	var size int64
+	for _, entrySize := range entries {
+		size += entrySize
+	}
+	assert.LessOrEqual(t, size, oldSize)
+	assert.Less(t, len(entries), len(oldEntries)+len(newFiles))
+
+	warmReader := newHashingContentReader(hashes)
+	_, _, err = depgraph.BuildDependencyGraphWithOptions(newFiles, warmReader, depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+	assert.Zero(t, warmReader.reads.Load())
+}
+
+func TestParseCache_Clear(t *testing.T) {
+	dir := t.TempDir()
+	files := writeGoModule(t, dir)
+	cacheDir := filepath.Join(t.TempDir(), "parses")
+	cache := depgraph.NewParseCache(cacheDir, depgraph.DefaultParseCacheMaxBytes)
+
+	_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+	require.NoError(t, err)
+	assert.NotEmpty(t, cacheEntries(t, cacheDir))
+
+	require.NoError(t, cache.Clear())
+
+	_, err = os.Stat(cacheDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDefaultParseCacheDir_FollowsCacheDirEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(depgraph.CacheDirEnv, dir)
+
+	got, err := depgraph.DefaultParseCacheDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "parses"), got)
+}
+
+func TestDefaultParseCacheDir_NoUserCacheInTests(t *testing.T) {
+	t.Setenv(depgraph.CacheDirEnv, "")
+
+	_, err := depgraph.DefaultParseCacheDir()
+	assert.ErrorContains(t, err, depgraph.CacheDirEnv)
+}
+
+// BenchmarkParseCache compares a cold build with a warm one, which reads every file
+// but parses none:
+//
+//	go test ./depgraph -run '^$' -bench ParseCache
+func BenchmarkParseCache(b *testing.B) {
+	dir := b.TempDir()
+	writeGoModule(b, dir)
+	var files []string
+	for i := range 200 {
+		name := filepath.Join(dir, "pkg", fmt.Sprintf("p%d", i), "file.go")
+		content := fmt.Sprintf("package p%d\n\nimport \"example.com/app/util\"\n\nfunc F%d() { util.Helper() }\n", i, i)
+		require.NoError(b, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(b, os.WriteFile(name, []byte(content), 0o644))
+		files = append(files, name)
+	}
+	files = append(files, filepath.Join(dir, "util", "util.go"))
+
+	b.Run("cold", func(b *testing.B) {
+		for range b.N {
+			_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{})
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("warm", func(b *testing.B) {
+		cache := depgraph.NewParseCache(b.TempDir(), depgraph.DefaultParseCacheMaxBytes)
+		_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+		require.NoError(b, err)
+		b.ResetTimer()
+		for range b.N {
+			_, _, err := depgraph.BuildDependencyGraphWithOptions(files, vcs.FilesystemContentReader(), depgraph.BuildOptions{Cache: cache})
+			require.NoError(b, err)
+		}
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/LegacyCodeHQ/clarity/depgraph/languages/svelte"
 	"github.com/LegacyCodeHQ/clarity/depgraph/languages/swift"
 	"github.com/LegacyCodeHQ/clarity/depgraph/languages/typescript"
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
 )
 
 var modules = []Module{
@@ -53,3 +54,20 @@ func ModuleForExtension(ext string) (Module, bool) {
 
 	return nil, false
 }
+
+// ModuleForInterpreter returns the module whose scripts run with the named
+// interpreter, e.g. "python3" from a "#!/usr/bin/env python3" shebang.
+func ModuleForInterpreter(interpreter string) (Module, bool) {
+	for _, module := range modules {
+		interpreterModule, ok := module.(moduleapi.InterpreterModule)
+		if !ok {
+			continue
+		}
+		for _, moduleInterpreter := range interpreterModule.Interpreters() {
+			if moduleInterpreter == interpreter {
+				return module, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,255 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// Limits of a budget, each named after the key that configures it.
+const (
+	BudgetMaxOutDegree = "max-out-degree"
+	BudgetMaxInDegree  = "max-in-degree"
+	BudgetMaxLOC       = "max-loc"
+)
+
+// Metrics a budget limits, as reported in violations.
+const (
+	MetricOutDegree = "out-degree"
+	MetricInDegree  = "in-degree"
+	MetricLOC       = "loc"
+)
+
+// budgetMetrics maps each limit key to the metric it limits, in report order.
+var budgetMetrics = []struct{ key, metric string }{
+	{BudgetMaxOutDegree, MetricOutDegree},
+	{BudgetMaxInDegree, MetricInDegree},
+	{BudgetMaxLOC, MetricLOC},
+}
+
+// budgetLimitKeys are the keys a budget limit may have.
+var budgetLimitKeys = map[string]bool{
+	"path": true, BudgetMaxOutDegree: true, BudgetMaxInDegree: true, BudgetMaxLOC: true,
+}
+
+// BudgetLimit caps the metrics of the files whose repo-relative paths match Paths.
+// Metrics missing from Max are left unchecked.
+type BudgetLimit struct {
+	Paths []string
+	// Max maps a metric (MetricOutDegree, MetricInDegree, MetricLOC) to the largest
+	// value a matching file may have.
+	Max map[string]int
+
+	paths patterns.Set
+}
+
+// Budget is the parsed budget section of a configuration file.
+type Budget struct {
+	Limits []BudgetLimit
+}
+
+// BudgetViolation is a file whose metric exceeds its limit. When a file matches
+// several limits of the same metric, the smallest applies.
+type BudgetViolation struct {
+	// File is repo-relative with forward slashes.
+	File   string `json:"file"`
+	Metric string `json:"metric"`
+	Value  int    `json:"value"`
+	Limit  int    `json:"limit"`
+	// Status is set by BudgetBaseline.Classify: BudgetStatusNew, BudgetStatusWorsened,
+	// or BudgetStatusBaselined.
+	Status string `json:"status,omitempty"`
+}
+
+// LoadBudget reads and validates the budget section of a configuration file.
+func LoadBudget(filePath string) (Budget, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Budget{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	budget, err := ParseBudget(data)
+	if err != nil {
+		return Budget{}, fmt.Errorf("invalid config file %s: %w", filePath, err)
+	}
+	return budget, nil
+}
+
+// ParseBudget parses and validates the limits of the budget section from YAML data:
+//
+//	budget:
+//	  limits:
+//	    - path: "**"
+//	      max-loc: 800
+//	      max-in-degree: 30
+//	    - path: ["cmd/**", "!cmd/root.go"]
+//	      max-out-degree: 15
+//
+// Each limit needs a path pattern, or list of patterns, and at least one maximum.
+func ParseBudget(data []byte) (Budget, error) {
+	var doc struct {
+		Budget struct {
+			Limits []yaml.Node `yaml:"limits"`
+		} `yaml:"budget"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Budget{}, err
+	}
+
+	budget := Budget{Limits: make([]BudgetLimit, 0, len(doc.Budget.Limits))}
+	for i := range doc.Budget.Limits {
+		limit, err := parseBudgetLimit(&doc.Budget.Limits[i], i+1)
+		if err != nil {
+			return Budget{}, err
+		}
+		budget.Limits = append(budget.Limits, limit)
+	}
+	return budget, nil
+}
+
+func parseBudgetLimit(node *yaml.Node, index int) (BudgetLimit, error) {
+	limitError := func(at *yaml.Node, format string, args ...any) error {
+		return fmt.Errorf("line %d: budget limit %d: %s", at.Line, index, fmt.Sprintf(format, args...))
+	}
+	if node.Kind != yaml.MappingNode {
+		return BudgetLimit{}, limitError(node, "expected a mapping of keys to values")
+	}
+
+	limit := BudgetLimit{Max: make(map[string]int)}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		if !budgetLimitKeys[key] {
+			return BudgetLimit{}, limitError(keyNode, "unknown key %q (valid keys: %s)", key, supportedBudgetLimitKeys())
+		}
+
+		if key == "path" {
+			var values []string
+			if valueNode.Kind == yaml.ScalarNode {
+				values = []string{valueNode.Value}
+			} else if err := valueNode.Decode(&values); err != nil {
+				return BudgetLimit{}, limitError(valueNode, "path must be a pattern or a list of patterns")
+			}
+			set, err := patterns.CompileSet(values)
+			if err != nil {
+				return BudgetLimit{}, limitError(valueNode, "invalid path: %v", err)
+			}
+			limit.Paths, limit.paths = values, set
+			continue
+		}
+
+		var max int
+		if valueNode.Decode(&max) != nil || max < 1 {
+			return BudgetLimit{}, limitError(valueNode, "%s must be a positive integer", key)
+		}
+		limit.Max[budgetMetric(key)] = max
+	}
+
+	if len(limit.Paths) == 0 {
+		return BudgetLimit{}, limitError(node, "path is required")
+	}
+	if len(limit.Max) == 0 {
+		return BudgetLimit{}, limitError(node, "one of %s, %s, or %s is required", BudgetMaxOutDegree, BudgetMaxInDegree, BudgetMaxLOC)
+	}
+	return limit, nil
+}
+
+func budgetMetric(key string) string {
+	for _, m := range budgetMetrics {
+		if m.key == key {
+			return m.metric
+		}
+	}
+	return ""
+}
+
+func supportedBudgetLimitKeys() string {
+	keys := make([]string, 0, len(budgetLimitKeys))
+	for key := range budgetLimitKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// Evaluate measures the files of g against the limits and returns the violations,
+// ordered by file and then by metric. Files are matched by their path relative to
+// repoRoot, and lines of code are counted from contentReader, only for files a
+// max-loc limit covers. When only is not nil, files outside it are left out.
+func (b Budget) Evaluate(g depgraph.FileDependencyGraph, repoRoot string, contentReader vcs.ContentReader, only map[string]bool) ([]BudgetViolation, error) {
+	fanOut, err := depgraph.FanOut(g.Graph)
+	if err != nil {
+		return nil, err
+	}
+	fanIn, err := depgraph.FanIn(g.Graph)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(fanOut))
+	for file := range fanOut {
+		if depgraph.IsExternalNode(file) || (only != nil && !only[file]) {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	violations := []BudgetViolation{}
+	for _, file := range files {
+		rel, err := filepath.Rel(repoRoot, file)
+		if err != nil {
+			rel = file
+		}
+		rel = filepath.ToSlash(rel)
+
+		limits := b.limitsOf(rel)
+		for _, m := range budgetMetrics {
+			limit, ok := limits[m.metric]
+			if !ok {
+				continue
+			}
+			var value int
+			switch m.metric {
+			case MetricOutDegree:
+				value = fanOut[file]
+			case MetricInDegree:
+				value = fanIn[file]
+			case MetricLOC:
+				content, err := contentReader.ReadFile(file)
+				if err != nil {
+					return nil, fmt.Errorf("failed to count lines of %s: %w", rel, err)
+				}
+				value = depgraph.CountLines(content)
+			}
+			if value > limit {
+				violations = append(violations, BudgetViolation{File: rel, Metric: m.metric, Value: value, Limit: limit})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// limitsOf returns the smallest limit of each metric among the limits matching rel.
+func (b Budget) limitsOf(rel string) map[string]int {
+	limits := make(map[string]int)
+	for _, limit := range b.Limits {
+		if !limit.paths.Match(rel) {
+			continue
+		}
+		for metric, max := range limit.Max {
+			if current, ok := limits[metric]; !ok || max < current {
+				limits[metric] = max
+			}
+		}
+	}
+	return limits
+}
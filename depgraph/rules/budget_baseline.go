@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Statuses of a budget violation checked against a baseline.
+const (
+	// BudgetStatusNew marks a violation the baseline does not record.
+	BudgetStatusNew = "new"
+	// BudgetStatusWorsened marks a recorded violation whose value has grown.
+	BudgetStatusWorsened = "worsened"
+	// BudgetStatusBaselined marks a recorded violation that has not grown.
+	BudgetStatusBaselined = "baselined"
+)
+
+// BudgetBaseline records accepted budget violations, so a check fails only on new or
+// worsened ones.
+type BudgetBaseline struct {
+	Violations []BudgetViolation `json:"violations"`
+}
+
+// LoadBudgetBaseline reads a baseline written by WriteBudgetBaseline.
+func LoadBudgetBaseline(filePath string) (BudgetBaseline, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return BudgetBaseline{}, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+
+	var baseline BudgetBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return BudgetBaseline{}, fmt.Errorf("invalid baseline file %s: %w", filePath, err)
+	}
+	return baseline, nil
+}
+
+// WriteBudgetBaseline writes violations to filePath as a baseline, without their
+// statuses.
+func WriteBudgetBaseline(filePath string, violations []BudgetViolation) error {
+	baseline := BudgetBaseline{Violations: make([]BudgetViolation, len(violations))}
+	for i, violation := range violations {
+		violation.Status = ""
+		baseline.Violations[i] = violation
+	}
+
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline file: %w", err)
+	}
+	return nil
+}
+
+// Classify returns violations with their Status set against the baseline: baselined
+// when it records the file and metric with a value at least as large, worsened when
+// it records a smaller value, and new otherwise.
+func (b BudgetBaseline) Classify(violations []BudgetViolation) []BudgetViolation {
+	type key struct{ file, metric string }
+	accepted := make(map[key]int, len(b.Violations))
+	for _, violation := range b.Violations {
+		accepted[key{violation.File, violation.Metric}] = violation.Value
+	}
+
+	classified := make([]BudgetViolation, len(violations))
+	for i, violation := range violations {
+		value, ok := accepted[key{violation.File, violation.Metric}]
+		switch {
+		case !ok:
+			violation.Status = BudgetStatusNew
+		case violation.Value > value:
+			violation.Status = BudgetStatusWorsened
+		default:
+			violation.Status = BudgetStatusBaselined
+		}
+		classified[i] = violation
+	}
+	return classified
+}
+
+// HasBudgetFailures reports whether any violation is not covered by a baseline.
+// Violations that were never classified count as failures.
+func HasBudgetFailures(violations []BudgetViolation) bool {
+	for _, violation := range violations {
+		if violation.Status != BudgetStatusBaselined {
+			return true
+		}
+	}
+	return false
+}
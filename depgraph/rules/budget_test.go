@@ -0,0 +1,194 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+const testBudget = `budget:
+  limits:
+    - path: "**"
+      max-in-degree: 2
+      max-loc: 3
+    - path: ["cmd/**", "!cmd/root.go"]
+      max-out-degree: 1
+      max-loc: 10
+`
+
+// budgetGraph writes the files of a repository under a temporary directory, where
+// three files import internal/log.go and cmd/show/show.go imports two files, and
+// returns the graph and the repository root.
+func budgetGraph(t *testing.T) (depgraph.FileDependencyGraph, string) {
+	t.Helper()
+
+	repoRoot := t.TempDir()
+	files := map[string]string{
+		"main.go":          "package main\n",
+		"cmd/root.go":      "package cmd\n\n\n\n",
+		"cmd/show/show.go": "package show\n",
+		"vcs/git.go":       "package vcs\n",
+		"internal/log.go":  "package internal\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	abs := func(name string) string { return filepath.Join(repoRoot, name) }
+	graph := depgraph.MustDependencyGraph(map[string][]string{
+		abs("main.go"):          {abs("cmd/root.go"), abs("internal/log.go")},
+		abs("cmd/root.go"):      {abs("cmd/show/show.go"), abs("internal/log.go")},
+		abs("cmd/show/show.go"): {abs("vcs/git.go"), abs("internal/log.go")},
+		abs("vcs/git.go"):       {},
+		abs("internal/log.go"):  {},
+	})
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFileDependencyGraph() error = %v", err)
+	}
+	return fileGraph, repoRoot
+}
+
+func TestParseBudget(t *testing.T) {
+	budget, err := ParseBudget([]byte(testBudget))
+	if err != nil {
+		t.Fatalf("ParseBudget() error = %v", err)
+	}
+
+	if len(budget.Limits) != 2 {
+		t.Fatalf("len(Limits) = %d, want 2", len(budget.Limits))
+	}
+	if want := []string{"cmd/**", "!cmd/root.go"}; !reflect.DeepEqual(budget.Limits[1].Paths, want) {
+		t.Errorf("Limits[1].Paths = %v, want %v", budget.Limits[1].Paths, want)
+	}
+	if want := map[string]int{MetricInDegree: 2, MetricLOC: 3}; !reflect.DeepEqual(budget.Limits[0].Max, want) {
+		t.Errorf("Limits[0].Max = %v, want %v", budget.Limits[0].Max, want)
+	}
+}
+
+func TestBudget_Evaluate(t *testing.T) {
+	budget, err := ParseBudget([]byte(testBudget))
+	if err != nil {
+		t.Fatalf("ParseBudget() error = %v", err)
+	}
+	fileGraph, repoRoot := budgetGraph(t)
+
+	violations, err := budget.Evaluate(fileGraph, repoRoot, vcs.FilesystemContentReader(), nil)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	// cmd/root.go is left out of the out-degree limit, and the smaller max-loc of the
+	// two limits applies to it.
+	want := []BudgetViolation{
+		{File: "cmd/root.go", Metric: MetricLOC, Value: 4, Limit: 3},
+		{File: "cmd/show/show.go", Metric: MetricOutDegree, Value: 2, Limit: 1},
+		{File: "internal/log.go", Metric: MetricInDegree, Value: 3, Limit: 2},
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("Evaluate() = %+v, want %+v", violations, want)
+	}
+}
+
+func TestBudget_EvaluateOnlyReportsListedFiles(t *testing.T) {
+	budget, err := ParseBudget([]byte(testBudget))
+	if err != nil {
+		t.Fatalf("ParseBudget() error = %v", err)
+	}
+	fileGraph, repoRoot := budgetGraph(t)
+
+	only := map[string]bool{filepath.Join(repoRoot, "internal/log.go"): true}
+	violations, err := budget.Evaluate(fileGraph, repoRoot, vcs.FilesystemContentReader(), only)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	want := []BudgetViolation{{File: "internal/log.go", Metric: MetricInDegree, Value: 3, Limit: 2}}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("Evaluate() = %+v, want %+v", violations, want)
+	}
+}
+
+func TestParseBudget_RejectsInvalidLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   string
+		wantErr string
+	}{
+		{"missing path", "max-loc: 10", "line 3: budget limit 1: path is required"},
+		{"missing maximum", "path: cmd", "budget limit 1: one of max-out-degree, max-in-degree, or max-loc is required"},
+		{"unknown key", "path: cmd\n      max-lines: 10", `unknown key "max-lines"`},
+		{"invalid pattern", "path: \"a[\"\n      max-loc: 10", "invalid path"},
+		{"zero maximum", "path: cmd\n      max-in-degree: 0", "max-in-degree must be a positive integer"},
+		{"text maximum", "path: cmd\n      max-loc: many", "max-loc must be a positive integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBudget([]byte("budget:\n  limits:\n    - " + tt.limit + "\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseBudget() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBudgetBaseline_WriteLoadAndClassify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget-baseline.json")
+	recorded := []BudgetViolation{
+		{File: "cmd/root.go", Metric: MetricLOC, Value: 4, Limit: 3, Status: BudgetStatusNew},
+		{File: "internal/log.go", Metric: MetricInDegree, Value: 3, Limit: 2},
+	}
+	if err := WriteBudgetBaseline(path, recorded); err != nil {
+		t.Fatalf("WriteBudgetBaseline() error = %v", err)
+	}
+
+	baseline, err := LoadBudgetBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBudgetBaseline() error = %v", err)
+	}
+	if baseline.Violations[0].Status != "" {
+		t.Errorf("baseline status = %q, want it left out", baseline.Violations[0].Status)
+	}
+
+	classified := baseline.Classify([]BudgetViolation{
+		{File: "cmd/root.go", Metric: MetricLOC, Value: 4, Limit: 3},
+		{File: "cmd/show/show.go", Metric: MetricOutDegree, Value: 2, Limit: 1},
+		{File: "internal/log.go", Metric: MetricInDegree, Value: 5, Limit: 2},
+	})
+	var statuses []string
+	for _, violation := range classified {
+		statuses = append(statuses, violation.Status)
+	}
+	if want := []string{BudgetStatusBaselined, BudgetStatusNew, BudgetStatusWorsened}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+	if !HasBudgetFailures(classified) {
+		t.Error("HasBudgetFailures() = false, want true")
+	}
+	if HasBudgetFailures(classified[:1]) {
+		t.Error("HasBudgetFailures() = true for baselined violations, want false")
+	}
+}
+
+func TestLoadBudgetBaseline_RejectsInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget-baseline.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadBudgetBaseline(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid baseline file") {
+		t.Fatalf("LoadBudgetBaseline() error = %v, want an invalid baseline error", err)
+	}
+}
@@ -0,0 +1,338 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
+)
+
+// Severities of a lint rule. Only violations of error rules fail a lint run.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Kinds of lint rule, each named after the key that configures it.
+const (
+	LintDeny     = "deny"
+	LintAllow    = "allow"
+	LintMaxFanIn = "max-fan-in"
+	LintNoCycles = "no-cycles"
+)
+
+// lintRuleKeys are the keys a lint rule may have.
+var lintRuleKeys = map[string]bool{
+	"name": true, "severity": true, LintDeny: true, LintAllow: true, LintMaxFanIn: true, LintNoCycles: true,
+}
+
+// LintRule is one structural rule of the lint section of a configuration file.
+//
+// From and To are patterns matched against repo-relative directories: a file belongs
+// to a pattern when its directory, or any directory above it, matches. A deny rule
+// forbids every dependency from a file in From to a file in To. An allow rule lets
+// files in From depend only on files in From or To. A max-fan-in rule limits how many
+// files may import any one file, and a no-cycles rule forbids dependency cycles.
+type LintRule struct {
+	Name     string
+	Severity string
+	Kind     string
+	From     []string
+	To       []string
+	MaxFanIn int
+
+	from patterns.Set
+	to   patterns.Set
+}
+
+// LintRules is the parsed lint section of a configuration file.
+type LintRules struct {
+	Rules []LintRule
+}
+
+// LintViolation is a dependency, file, or cycle that breaks a lint rule. Paths are
+// repo-relative with forward slashes.
+type LintViolation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// From and To are the offending edge. A max-fan-in violation has only To, the
+	// file imported by too many files, and a no-cycles violation has the first edge
+	// of the cycle.
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	// Detail explains the violation, such as the whole cycle or the importer count.
+	Detail string `json:"detail,omitempty"`
+}
+
+// LoadLintRules reads and validates the lint section of a configuration file.
+func LoadLintRules(filePath string) (LintRules, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return LintRules{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	lintRules, err := ParseLintRules(data)
+	if err != nil {
+		return LintRules{}, fmt.Errorf("invalid config file %s: %w", filePath, err)
+	}
+	return lintRules, nil
+}
+
+// ParseLintRules parses and validates the rules of the lint section from YAML data:
+//
+//	lint:
+//	  rules:
+//	    - name: parsers-stay-independent
+//	      deny: {from: parsers, to: cmd}
+//	    - name: cmd-layers
+//	      allow: {from: "cmd/*", to: [parsers, internal]}
+//	      severity: warning
+//	    - name: no-hubs
+//	      max-fan-in: 20
+//	    - name: acyclic
+//	      no-cycles: true
+//
+// Rule names must be unique, and severity defaults to error.
+func ParseLintRules(data []byte) (LintRules, error) {
+	var doc struct {
+		Lint struct {
+			Rules []yaml.Node `yaml:"rules"`
+		} `yaml:"lint"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return LintRules{}, err
+	}
+
+	lintRules := LintRules{Rules: make([]LintRule, 0, len(doc.Lint.Rules))}
+	names := make(map[string]bool, len(doc.Lint.Rules))
+	for i := range doc.Lint.Rules {
+		rule, err := parseLintRule(&doc.Lint.Rules[i], i+1)
+		if err != nil {
+			return LintRules{}, err
+		}
+		if names[rule.Name] {
+			return LintRules{}, fmt.Errorf("line %d: lint rule %d: duplicate name %q", doc.Lint.Rules[i].Line, i+1, rule.Name)
+		}
+		names[rule.Name] = true
+		lintRules.Rules = append(lintRules.Rules, rule)
+	}
+	return lintRules, nil
+}
+
+func parseLintRule(node *yaml.Node, index int) (LintRule, error) {
+	ruleError := func(at *yaml.Node, format string, args ...any) error {
+		return fmt.Errorf("line %d: lint rule %d: %s", at.Line, index, fmt.Sprintf(format, args...))
+	}
+	if node.Kind != yaml.MappingNode {
+		return LintRule{}, ruleError(node, "expected a mapping of keys to values")
+	}
+
+	rule := LintRule{Severity: SeverityError}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		if !lintRuleKeys[key] {
+			return LintRule{}, ruleError(keyNode, "unknown key %q (valid keys: %s)", key, supportedLintRuleKeys())
+		}
+
+		switch key {
+		case "name":
+			if valueNode.Kind != yaml.ScalarNode || valueNode.Value == "" {
+				return LintRule{}, ruleError(valueNode, "name must be a non-empty string")
+			}
+			rule.Name = valueNode.Value
+			continue
+		case "severity":
+			if valueNode.Value != SeverityError && valueNode.Value != SeverityWarning {
+				return LintRule{}, ruleError(valueNode, "severity must be %s or %s", SeverityError, SeverityWarning)
+			}
+			rule.Severity = valueNode.Value
+			continue
+		}
+
+		if rule.Kind != "" {
+			return LintRule{}, ruleError(keyNode, "%s and %s cannot be combined in one rule", rule.Kind, key)
+		}
+		rule.Kind = key
+		switch key {
+		case LintDeny, LintAllow:
+			if err := parseLintEdge(&rule, valueNode); err != nil {
+				return LintRule{}, ruleError(valueNode, "%s: %v", key, err)
+			}
+		case LintMaxFanIn:
+			if valueNode.Decode(&rule.MaxFanIn) != nil || rule.MaxFanIn < 1 {
+				return LintRule{}, ruleError(valueNode, "max-fan-in must be a positive integer")
+			}
+		case LintNoCycles:
+			var enabled bool
+			if valueNode.Decode(&enabled) != nil || !enabled {
+				return LintRule{}, ruleError(valueNode, "no-cycles must be true")
+			}
+		}
+	}
+
+	if rule.Name == "" {
+		return LintRule{}, ruleError(node, "name is required")
+	}
+	if rule.Kind == "" {
+		return LintRule{}, ruleError(node, "one of %s, %s, %s, or %s is required", LintDeny, LintAllow, LintMaxFanIn, LintNoCycles)
+	}
+	return rule, nil
+}
+
+// parseLintEdge reads the from and to patterns of a deny or allow rule. Each is a
+// pattern or a list of patterns.
+func parseLintEdge(rule *LintRule, node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("expected from and to")
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		var values []string
+		if valueNode.Kind == yaml.ScalarNode {
+			values = []string{valueNode.Value}
+		} else if err := valueNode.Decode(&values); err != nil {
+			return fmt.Errorf("%s must be a pattern or a list of patterns", keyNode.Value)
+		}
+		set, err := patterns.CompileSet(values)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", keyNode.Value, err)
+		}
+		switch keyNode.Value {
+		case "from":
+			rule.From, rule.from = values, set
+		case "to":
+			rule.To, rule.to = values, set
+		default:
+			return fmt.Errorf("unknown key %q (valid keys: from, to)", keyNode.Value)
+		}
+	}
+	if len(rule.From) == 0 || len(rule.To) == 0 {
+		return fmt.Errorf("from and to are required")
+	}
+	return nil
+}
+
+func supportedLintRuleKeys() string {
+	keys := make([]string, 0, len(lintRuleKeys))
+	for key := range lintRuleKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// HasErrors reports whether any violation is of an error rule.
+func HasErrors(violations []LintViolation) bool {
+	for _, violation := range violations {
+		if violation.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate checks every rule against g and returns the violations, ordered by rule
+// and then by edge. Files are matched by their path relative to repoRoot.
+func (r LintRules) Evaluate(g depgraph.FileDependencyGraph, repoRoot string) ([]LintViolation, error) {
+	adjacency, err := depgraph.AdjacencyList(g.Graph)
+	if err != nil {
+		return nil, err
+	}
+	relative := func(file string) string {
+		rel, err := filepath.Rel(repoRoot, file)
+		if err != nil {
+			return filepath.ToSlash(file)
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	violations := []LintViolation{}
+	for _, rule := range r.Rules {
+		var found []LintViolation
+		switch rule.Kind {
+		case LintDeny, LintAllow:
+			for from, deps := range adjacency {
+				fromRel := relative(from)
+				if !inDirectories(rule.from, fromRel) {
+					continue
+				}
+				for _, to := range deps {
+					toRel := relative(to)
+					if rule.breaksEdge(toRel) {
+						found = append(found, LintViolation{From: fromRel, To: toRel})
+					}
+				}
+			}
+		case LintMaxFanIn:
+			importers := make(map[string]int)
+			for _, deps := range adjacency {
+				for _, to := range deps {
+					importers[to]++
+				}
+			}
+			for file, count := range importers {
+				if count > rule.MaxFanIn {
+					found = append(found, LintViolation{
+						To:     relative(file),
+						Detail: fmt.Sprintf("imported by %d files (max %d)", count, rule.MaxFanIn),
+					})
+				}
+			}
+		case LintNoCycles:
+			for _, cycle := range g.Meta.Cycles {
+				steps := make([]string, len(cycle.Path))
+				for i, file := range cycle.Path {
+					steps[i] = relative(file)
+				}
+				if len(steps) < 2 || steps[len(steps)-1] != steps[0] {
+					steps = append(steps, steps[0])
+				}
+				found = append(found, LintViolation{
+					From:   steps[0],
+					To:     steps[1],
+					Detail: "cycle: " + strings.Join(steps, " -> "),
+				})
+			}
+		}
+
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].From != found[j].From {
+				return found[i].From < found[j].From
+			}
+			return found[i].To < found[j].To
+		})
+		for _, violation := range found {
+			violation.Rule, violation.Severity = rule.Name, rule.Severity
+			violations = append(violations, violation)
+		}
+	}
+	return violations, nil
+}
+
+// breaksEdge reports whether a dependency on toRel from a file in the rule's From
+// directories breaks a deny or allow rule.
+func (r LintRule) breaksEdge(toRel string) bool {
+	if r.Kind == LintDeny {
+		return inDirectories(r.to, toRel)
+	}
+	return !inDirectories(r.from, toRel) && !inDirectories(r.to, toRel)
+}
+
+// inDirectories reports whether the directory of a repo-relative file, or any
+// directory above it, matches set. Files at the repository root have no directory.
+func inDirectories(set patterns.Set, rel string) bool {
+	for dir := path.Dir(rel); dir != "." && dir != "/" && !strings.HasPrefix(dir, ".."); dir = path.Dir(dir) {
+		if set.Match(dir) {
+			return true
+		}
+	}
+	return false
+}
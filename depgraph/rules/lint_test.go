@@ -0,0 +1,182 @@
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+const testLintRules = `lint:
+  format: json
+  rules:
+    - name: parsers-stay-independent
+      deny: {from: parsers, to: cmd}
+    - name: cmd-layers
+      allow:
+        from: "cmd/*"
+        to: [parsers, internal]
+      severity: warning
+    - name: no-hubs
+      max-fan-in: 2
+    - name: acyclic
+      no-cycles: true
+`
+
+// lintGraph returns a graph of /repo where parsers/go imports cmd/show, cmd/show also
+// imports vcs, three files import internal/log.go, and parsers/go and parsers/dart
+// import each other.
+func lintGraph(t *testing.T) depgraph.FileDependencyGraph {
+	t.Helper()
+
+	graph := depgraph.MustDependencyGraph(map[string][]string{
+		"/repo/main.go":              {"/repo/cmd/root.go"},
+		"/repo/cmd/root.go":          {"/repo/cmd/show/show.go"},
+		"/repo/cmd/show/show.go":     {"/repo/parsers/dart/dart.go", "/repo/vcs/git.go", "/repo/internal/log.go"},
+		"/repo/parsers/go/go.go":     {"/repo/cmd/show/show.go", "/repo/parsers/dart/dart.go", "/repo/internal/log.go"},
+		"/repo/parsers/dart/dart.go": {"/repo/parsers/go/go.go", "/repo/internal/log.go"},
+		"/repo/vcs/git.go":           {},
+		"/repo/internal/log.go":      {},
+	})
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFileDependencyGraph() error = %v", err)
+	}
+	return fileGraph
+}
+
+func TestParseLintRules(t *testing.T) {
+	lintRules, err := ParseLintRules([]byte(testLintRules))
+	if err != nil {
+		t.Fatalf("ParseLintRules() error = %v", err)
+	}
+
+	var kinds, severities []string
+	for _, rule := range lintRules.Rules {
+		kinds = append(kinds, rule.Kind)
+		severities = append(severities, rule.Severity)
+	}
+	if want := []string{LintDeny, LintAllow, LintMaxFanIn, LintNoCycles}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+	if want := []string{SeverityError, SeverityWarning, SeverityError, SeverityError}; !reflect.DeepEqual(severities, want) {
+		t.Fatalf("severities = %v, want %v", severities, want)
+	}
+	if want := []string{"parsers", "internal"}; !reflect.DeepEqual(lintRules.Rules[1].To, want) {
+		t.Fatalf("allow to = %v, want %v", lintRules.Rules[1].To, want)
+	}
+	if lintRules.Rules[2].MaxFanIn != 2 {
+		t.Fatalf("max-fan-in = %d, want 2", lintRules.Rules[2].MaxFanIn)
+	}
+}
+
+func TestLintRules_Evaluate(t *testing.T) {
+	lintRules, err := ParseLintRules([]byte(testLintRules))
+	if err != nil {
+		t.Fatalf("ParseLintRules() error = %v", err)
+	}
+
+	violations, err := lintRules.Evaluate(lintGraph(t), "/repo")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	want := []LintViolation{
+		{Rule: "parsers-stay-independent", Severity: SeverityError, From: "parsers/go/go.go", To: "cmd/show/show.go"},
+		{Rule: "cmd-layers", Severity: SeverityWarning, From: "cmd/show/show.go", To: "vcs/git.go"},
+		{Rule: "no-hubs", Severity: SeverityError, To: "internal/log.go", Detail: "imported by 3 files (max 2)"},
+		{Rule: "acyclic", Severity: SeverityError, From: "cmd/show/show.go", To: "parsers/dart/dart.go",
+			Detail: "cycle: cmd/show/show.go -> parsers/dart/dart.go -> parsers/go/go.go -> cmd/show/show.go"},
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Fatalf("violations =\n%+v\nwant\n%+v", violations, want)
+	}
+	if !HasErrors(violations) {
+		t.Fatal("expected error violations")
+	}
+}
+
+func TestLintRules_AllowCoversFromDirectoriesAndNestedDirectories(t *testing.T) {
+	lintRules, err := ParseLintRules([]byte(`lint:
+  rules:
+    - name: cmd-layers
+      allow: {from: cmd, to: parsers}
+`))
+	if err != nil {
+		t.Fatalf("ParseLintRules() error = %v", err)
+	}
+	graph := depgraph.MustDependencyGraph(map[string][]string{
+		"/repo/cmd/root.go":      {"/repo/cmd/show/show.go", "/repo/main.go"},
+		"/repo/cmd/show/show.go": {"/repo/parsers/go/go.go"},
+		"/repo/parsers/go/go.go": {},
+		"/repo/main.go":          {},
+	})
+	fileGraph, err := depgraph.NewFileDependencyGraph(graph, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFileDependencyGraph() error = %v", err)
+	}
+
+	violations, err := lintRules.Evaluate(fileGraph, "/repo")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	want := []LintViolation{{Rule: "cmd-layers", Severity: SeverityError, From: "cmd/root.go", To: "main.go"}}
+	if !reflect.DeepEqual(violations, want) {
+		t.Fatalf("violations = %+v, want %+v", violations, want)
+	}
+}
+
+func TestLintRules_WarningsAreNotErrors(t *testing.T) {
+	lintRules, err := ParseLintRules([]byte(`lint:
+  rules:
+    - name: acyclic
+      no-cycles: true
+      severity: warning
+`))
+	if err != nil {
+		t.Fatalf("ParseLintRules() error = %v", err)
+	}
+
+	violations, err := lintRules.Evaluate(lintGraph(t), "/repo")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(violations) != 1 || HasErrors(violations) {
+		t.Fatalf("expected one warning, got %+v", violations)
+	}
+}
+
+func TestParseLintRules_RejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{"missing name", "deny: {from: a, to: b}", "line 3: lint rule 1: name is required"},
+		{"missing kind", "name: r", "lint rule 1: one of deny, allow, max-fan-in, or no-cycles is required"},
+		{"two kinds", "name: r\n      no-cycles: true\n      max-fan-in: 3", "no-cycles and max-fan-in cannot be combined in one rule"},
+		{"unknown key", "name: r\n      forbid: {from: a, to: b}", `unknown key "forbid"`},
+		{"missing to", "name: r\n      deny: {from: a}", "deny: from and to are required"},
+		{"invalid pattern", "name: r\n      deny: {from: \"a[\", to: b}", "deny: invalid from"},
+		{"bad severity", "name: r\n      no-cycles: true\n      severity: fatal", "severity must be error or warning"},
+		{"zero fan-in", "name: r\n      max-fan-in: 0", "max-fan-in must be a positive integer"},
+		{"disabled cycles", "name: r\n      no-cycles: false", "no-cycles must be true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLintRules([]byte("lint:\n  rules:\n    - " + tt.rule + "\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseLintRules() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseLintRules_RejectsDuplicateNames(t *testing.T) {
+	_, err := ParseLintRules([]byte("lint:\n  rules:\n    - name: r\n      no-cycles: true\n    - name: r\n      max-fan-in: 3\n"))
+	if err == nil || !strings.Contains(err.Error(), `lint rule 2: duplicate name "r"`) {
+		t.Fatalf("ParseLintRules() error = %v, want a duplicate name error", err)
+	}
+}
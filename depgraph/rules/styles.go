@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
+)
+
+var (
+	hexColorPattern  = regexp.MustCompile(`^#(?:[0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+	classNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+)
+
+// builtInClasses are class names the formatters already use for their own styling.
+var builtInClasses = map[string]bool{
+	"testFile":          true,
+	"prunedFile":        true,
+	"majorityExtension": true,
+	"emptyGraph":        true,
+	"newFile":           true,
+	"externalPackage":   true,
+}
+
+// StyleRule gives files matching a path pattern a custom fill, stroke, and class.
+type StyleRule struct {
+	Match    string `yaml:"match"`
+	Fill     string `yaml:"fill,omitempty"`
+	Stroke   string `yaml:"stroke,omitempty"`
+	Class    string `yaml:"class,omitempty"`
+	Override bool   `yaml:"override,omitempty"`
+
+	match patterns.Set
+}
+
+// Styles is the parsed styles section of a configuration file.
+type Styles struct {
+	Rules []StyleRule
+}
+
+// StyleError reports an invalid style rule at a position in the configuration file.
+type StyleError struct {
+	Line   int
+	Column int
+	Rule   int
+	Msg    string
+}
+
+func (e *StyleError) Error() string {
+	return fmt.Sprintf("line %d, column %d: style %d: %s", e.Line, e.Column, e.Rule, e.Msg)
+}
+
+// LoadStyles reads and validates the styles section of a configuration file.
+func LoadStyles(filePath string) (Styles, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Styles{}, fmt.Errorf("failed to read style file: %w", err)
+	}
+
+	styles, err := ParseStyles(data)
+	if err != nil {
+		return Styles{}, fmt.Errorf("invalid style file %s: %w", filePath, err)
+	}
+	return styles, nil
+}
+
+// ParseStyles parses and validates the styles section from YAML (or JSON) data.
+// Rules without a class are named style1, style2, ... after their position.
+func ParseStyles(data []byte) (Styles, error) {
+	var doc struct {
+		Styles []yaml.Node `yaml:"styles"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Styles{}, err
+	}
+
+	styles := Styles{Rules: make([]StyleRule, 0, len(doc.Styles))}
+	for i := range doc.Styles {
+		node := &doc.Styles[i]
+		rule, err := parseStyleRule(node, i+1)
+		if err != nil {
+			return Styles{}, err
+		}
+		styles.Rules = append(styles.Rules, rule)
+	}
+	return styles, nil
+}
+
+func parseStyleRule(node *yaml.Node, index int) (StyleRule, error) {
+	ruleError := func(at *yaml.Node, offset int, format string, args ...any) error {
+		return &StyleError{Line: at.Line, Column: at.Column + offset, Rule: index, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	var rule StyleRule
+	if err := node.Decode(&rule); err != nil {
+		return StyleRule{}, ruleError(node, 0, "%v", err)
+	}
+
+	if rule.Match == "" {
+		return StyleRule{}, ruleError(node, 0, "match is required")
+	}
+	matchNode := styleField(node, "match")
+	match, err := patterns.CompileSet([]string{rule.Match})
+	if err != nil {
+		offset := 0
+		var syntaxErr *patterns.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset
+			if matchNode.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+				offset++
+			}
+		}
+		return StyleRule{}, ruleError(matchNode, offset, "invalid match: %v", err)
+	}
+	rule.match = match
+
+	if rule.Fill == "" && rule.Stroke == "" && rule.Class == "" {
+		return StyleRule{}, ruleError(node, 0, "at least one of fill, stroke, or class is required")
+	}
+	for _, field := range []struct {
+		key   string
+		value string
+	}{{"fill", rule.Fill}, {"stroke", rule.Stroke}} {
+		if field.value != "" && !hexColorPattern.MatchString(field.value) {
+			return StyleRule{}, ruleError(styleField(node, field.key), 0, "invalid %s %q: colors must be #rgb or #rrggbb", field.key, field.value)
+		}
+	}
+	if rule.Class != "" {
+		if !classNamePattern.MatchString(rule.Class) {
+			return StyleRule{}, ruleError(styleField(node, "class"), 0, "invalid class %q: use letters, digits, '-' and '_', starting with a letter", rule.Class)
+		}
+		if builtInClasses[rule.Class] {
+			return StyleRule{}, ruleError(styleField(node, "class"), 0, "class %q is reserved for built-in styling", rule.Class)
+		}
+	} else {
+		rule.Class = fmt.Sprintf("style%d", index)
+	}
+
+	return rule, nil
+}
+
+// styleField returns the value node for key in a rule mapping, or the mapping itself
+// when the key is absent.
+func styleField(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return node
+}
+
+// Matches reports whether the rule applies to a repo-relative path.
+func (r StyleRule) Matches(rel string) bool {
+	return r.match.Match(rel)
+}
+
+// Resolve returns the style for a repo-relative path. When several rules match, the
+// last one wins, as with other pattern lists.
+func (s Styles) Resolve(rel string) (depgraph.NodeStyle, bool) {
+	for i := len(s.Rules) - 1; i >= 0; i-- {
+		rule := s.Rules[i]
+		if rule.Matches(rel) {
+			return depgraph.NodeStyle{
+				Fill:     rule.Fill,
+				Stroke:   rule.Stroke,
+				Class:    rule.Class,
+				Override: rule.Override,
+			}, true
+		}
+	}
+	return depgraph.NodeStyle{}, false
+}
@@ -0,0 +1,138 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testStyles = `styles:
+  - match: "domain/**"
+    fill: "#ffd700"
+    stroke: "#b8860b"
+    class: domain
+  - match: "domain/**/*_test.go"
+    fill: "#fec"
+    override: true
+`
+
+func TestParseStyles(t *testing.T) {
+	styles, err := ParseStyles([]byte(testStyles))
+	if err != nil {
+		t.Fatalf("ParseStyles() error = %v", err)
+	}
+
+	if len(styles.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(styles.Rules))
+	}
+	if styles.Rules[0].Class != "domain" || styles.Rules[0].Stroke != "#b8860b" {
+		t.Fatalf("unexpected first rule: %+v", styles.Rules[0])
+	}
+	if styles.Rules[1].Class != "style2" {
+		t.Fatalf("expected default class style2, got %q", styles.Rules[1].Class)
+	}
+	if !styles.Rules[1].Override {
+		t.Fatal("expected second rule to override built-in classes")
+	}
+}
+
+func TestStyles_ResolveLastMatchWins(t *testing.T) {
+	styles, err := ParseStyles([]byte(testStyles))
+	if err != nil {
+		t.Fatalf("ParseStyles() error = %v", err)
+	}
+
+	style, ok := styles.Resolve("domain/order/order_test.go")
+	if !ok || style.Class != "style2" || style.Fill != "#fec" || style.Stroke != "" {
+		t.Fatalf("expected later rule for test file, got %+v (ok=%v)", style, ok)
+	}
+
+	style, ok = styles.Resolve("domain/order/order.go")
+	if !ok || style.Class != "domain" {
+		t.Fatalf("expected domain rule, got %+v (ok=%v)", style, ok)
+	}
+
+	if _, ok := styles.Resolve("adapters/db.go"); ok {
+		t.Fatal("expected no style for unmatched file")
+	}
+}
+
+func TestParseStyles_RejectsInvalidRulesWithPosition(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantLine   int
+		wantColumn int
+		wantMsg    string
+	}{
+		{
+			name:       "invalid color",
+			yaml:       "styles:\n  - match: \"domain/**\"\n    fill: \"#ffd70\"\n",
+			wantLine:   3,
+			wantColumn: 11,
+			wantMsg:    `invalid fill "#ffd70"`,
+		},
+		{
+			name:       "named color",
+			yaml:       "styles:\n  - match: a\n    class: a\n  - match: b\n    stroke: gold\n",
+			wantLine:   5,
+			wantColumn: 13,
+			wantMsg:    `invalid stroke "gold"`,
+		},
+		{
+			name:       "malformed pattern",
+			yaml:       "styles:\n  - match: \"domain/[a-\"\n    fill: \"#fff\"\n",
+			wantLine:   2,
+			wantColumn: 20,
+			wantMsg:    "invalid match",
+		},
+		{
+			name:       "missing match",
+			yaml:       "styles:\n  - fill: \"#fff\"\n",
+			wantLine:   2,
+			wantColumn: 5,
+			wantMsg:    "match is required",
+		},
+		{
+			name:       "nothing to apply",
+			yaml:       "styles:\n  - match: a\n",
+			wantLine:   2,
+			wantColumn: 5,
+			wantMsg:    "at least one of fill, stroke, or class",
+		},
+		{
+			name:       "reserved class",
+			yaml:       "styles:\n  - match: a\n    class: testFile\n",
+			wantLine:   3,
+			wantColumn: 12,
+			wantMsg:    "reserved",
+		},
+		{
+			name:       "invalid class",
+			yaml:       "styles:\n  - match: a\n    class: \"my class\"\n",
+			wantLine:   3,
+			wantColumn: 12,
+			wantMsg:    `invalid class "my class"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStyles([]byte(tt.yaml))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			var styleErr *StyleError
+			if !errors.As(err, &styleErr) {
+				t.Fatalf("expected *StyleError, got %T: %v", err, err)
+			}
+			if styleErr.Line != tt.wantLine || styleErr.Column != tt.wantColumn {
+				t.Fatalf("position = %d:%d, want %d:%d (%v)", styleErr.Line, styleErr.Column, tt.wantLine, tt.wantColumn, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("expected %q in error, got %v", tt.wantMsg, err)
+			}
+		})
+	}
+}
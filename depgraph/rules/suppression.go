@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
+)
+
+// ExpiresLayout is the date layout used by the expires field of a suppression.
+const ExpiresLayout = "2006-01-02"
+
+// Suppression marks edges between matching files as known and accepted.
+type Suppression struct {
+	From    string `yaml:"from"`
+	To      string `yaml:"to"`
+	Reason  string `yaml:"reason"`
+	Expires string `yaml:"expires,omitempty"`
+
+	expiresAt time.Time
+	from      patterns.Set
+	to        patterns.Set
+}
+
+// Suppressions is the parsed content of a suppression rules file.
+type Suppressions struct {
+	Rules []Suppression `yaml:"suppressions"`
+}
+
+// LoadSuppressions reads and validates a suppression rules file.
+func LoadSuppressions(filePath string) (Suppressions, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Suppressions{}, fmt.Errorf("failed to read suppression file: %w", err)
+	}
+
+	suppressions, err := ParseSuppressions(data)
+	if err != nil {
+		return Suppressions{}, fmt.Errorf("invalid suppression file %s: %w", filePath, err)
+	}
+	return suppressions, nil
+}
+
+// ParseSuppressions parses and validates suppression rules from YAML (or JSON) data.
+func ParseSuppressions(data []byte) (Suppressions, error) {
+	var suppressions Suppressions
+	if err := yaml.Unmarshal(data, &suppressions); err != nil {
+		return Suppressions{}, err
+	}
+
+	for i := range suppressions.Rules {
+		rule := &suppressions.Rules[i]
+		if rule.From == "" || rule.To == "" {
+			return Suppressions{}, fmt.Errorf("suppression %d: from and to are required", i+1)
+		}
+		from, err := patterns.CompileSet([]string{rule.From})
+		if err != nil {
+			return Suppressions{}, fmt.Errorf("suppression %d: invalid from: %w", i+1, err)
+		}
+		to, err := patterns.CompileSet([]string{rule.To})
+		if err != nil {
+			return Suppressions{}, fmt.Errorf("suppression %d: invalid to: %w", i+1, err)
+		}
+		rule.from, rule.to = from, to
+		if rule.Expires != "" {
+			expiresAt, err := time.Parse(ExpiresLayout, rule.Expires)
+			if err != nil {
+				return Suppressions{}, fmt.Errorf("suppression %d: expires must use YYYY-MM-DD: %w", i+1, err)
+			}
+			rule.expiresAt = expiresAt
+		}
+	}
+
+	return suppressions, nil
+}
+
+// Matches reports whether the rule covers an edge between two repo-relative paths.
+// From and To use the syntax of the patterns package.
+func (s Suppression) Matches(fromRel, toRel string) bool {
+	return s.from.Match(fromRel) && s.to.Match(toRel)
+}
+
+// IsStale reports whether the rule expired before now. The expires date itself
+// is still covered by the rule.
+func (s Suppression) IsStale(now time.Time) bool {
+	if s.expiresAt.IsZero() {
+		return false
+	}
+	return !now.Before(s.expiresAt.AddDate(0, 0, 1))
+}
+
+// Partition splits rules into those still applied at now and those that are stale.
+func (s Suppressions) Partition(now time.Time) (active, stale []Suppression) {
+	for _, rule := range s.Rules {
+		if rule.IsStale(now) {
+			stale = append(stale, rule)
+			continue
+		}
+		active = append(active, rule)
+	}
+	return active, stale
+}
+
+// Match returns the first active rule that covers the edge, if any.
+func Match(active []Suppression, fromRel, toRel string) (Suppression, bool) {
+	for _, rule := range active {
+		if rule.Matches(fromRel, toRel) {
+			return rule, true
+		}
+	}
+	return Suppression{}, false
+}
@@ -0,0 +1,105 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSuppressions = `suppressions:
+  - from: "cmd/**"
+    to: "internal/legacy/**"
+    reason: "legacy db access, tracked in #42"
+  - from: "ui/*.ts"
+    to: "server/api.ts"
+    reason: "temporary shortcut"
+    expires: 2026-03-31
+`
+
+func TestParseSuppressions(t *testing.T) {
+	suppressions, err := ParseSuppressions([]byte(testSuppressions))
+	if err != nil {
+		t.Fatalf("ParseSuppressions() error = %v", err)
+	}
+
+	if len(suppressions.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(suppressions.Rules))
+	}
+	if suppressions.Rules[1].Expires != "2026-03-31" {
+		t.Fatalf("expected expires 2026-03-31, got %q", suppressions.Rules[1].Expires)
+	}
+}
+
+func TestParseSuppressions_RequiresFromAndTo(t *testing.T) {
+	_, err := ParseSuppressions([]byte("suppressions:\n  - from: \"cmd/**\"\n"))
+	if err == nil {
+		t.Fatal("expected error for missing to pattern")
+	}
+}
+
+func TestParseSuppressions_RejectsInvalidExpires(t *testing.T) {
+	_, err := ParseSuppressions([]byte("suppressions:\n  - from: a\n    to: b\n    expires: next week\n"))
+	if err == nil {
+		t.Fatal("expected error for invalid expires date")
+	}
+}
+
+func TestParseSuppressions_RejectsMalformedPatternWithPosition(t *testing.T) {
+	_, err := ParseSuppressions([]byte("suppressions:\n  - from: \"cmd/[a-\"\n    to: b\n"))
+	if err == nil {
+		t.Fatal("expected error for malformed from pattern")
+	}
+	if !strings.Contains(err.Error(), "suppression 1: invalid from") || !strings.Contains(err.Error(), "at offset 4") {
+		t.Fatalf("expected rule number and pattern position in error, got %v", err)
+	}
+}
+
+func TestSuppression_MatchesNegatedPattern(t *testing.T) {
+	suppressions, err := ParseSuppressions([]byte("suppressions:\n  - from: \"!vendor/**\"\n    to: \"(?i)LEGACY/**\"\n"))
+	if err != nil {
+		t.Fatalf("ParseSuppressions() error = %v", err)
+	}
+
+	rule := suppressions.Rules[0]
+	if !rule.Matches("cmd/main.go", "legacy/db.go") {
+		t.Fatal("expected edge from outside vendor into legacy to match")
+	}
+	if rule.Matches("vendor/lib/a.go", "legacy/db.go") {
+		t.Fatal("expected edge from vendor not to match")
+	}
+}
+
+func TestSuppressions_PartitionReportsStaleRules(t *testing.T) {
+	suppressions, err := ParseSuppressions([]byte(testSuppressions))
+	if err != nil {
+		t.Fatalf("ParseSuppressions() error = %v", err)
+	}
+
+	onExpiryDate := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	active, stale := suppressions.Partition(onExpiryDate)
+	if len(active) != 2 || len(stale) != 0 {
+		t.Fatalf("expected both rules active on the expiry date, got active=%d stale=%d", len(active), len(stale))
+	}
+
+	dayAfter := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	active, stale = suppressions.Partition(dayAfter)
+	if len(active) != 1 || len(stale) != 1 || stale[0].To != "server/api.ts" {
+		t.Fatalf("expected expiring rule to be stale after its date, got active=%v stale=%v", active, stale)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	suppressions, err := ParseSuppressions([]byte(testSuppressions))
+	if err != nil {
+		t.Fatalf("ParseSuppressions() error = %v", err)
+	}
+
+	rule, ok := Match(suppressions.Rules, "cmd/show/show_cmd.go", "internal/legacy/db.go")
+	if !ok || rule.Reason != "legacy db access, tracked in #42" {
+		t.Fatalf("expected legacy rule to match, got %v, %v", rule, ok)
+	}
+
+	if _, ok := Match(suppressions.Rules, "internal/legacy/db.go", "cmd/show/show_cmd.go"); ok {
+		t.Fatal("expected suppression to be directional")
+	}
+}
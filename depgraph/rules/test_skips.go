@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/internal/patterns"
+)
+
+// TestSkips lists test files that must never be selected automatically, such as
+// known-flaky tests, as path patterns for repo-relative paths.
+type TestSkips struct {
+	Patterns []string
+
+	set patterns.Set
+}
+
+// LoadTestSkips reads a test skip file.
+func LoadTestSkips(filePath string) (TestSkips, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return TestSkips{}, fmt.Errorf("failed to read test skip file: %w", err)
+	}
+
+	skips, err := ParseTestSkips(data)
+	if err != nil {
+		return TestSkips{}, fmt.Errorf("invalid test skip file %s: %w", filePath, err)
+	}
+	return skips, nil
+}
+
+// ParseTestSkips parses one path or path pattern per line. Blank lines and lines
+// starting with "#" are ignored.
+func ParseTestSkips(data []byte) (TestSkips, error) {
+	var skips TestSkips
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern, err := patterns.Compile(line)
+		if err != nil {
+			return TestSkips{}, fmt.Errorf("line %d: invalid pattern %q: %w", lineNumber, line, err)
+		}
+		skips.Patterns = append(skips.Patterns, line)
+		skips.set = append(skips.set, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return TestSkips{}, err
+	}
+	return skips, nil
+}
+
+// Matches reports whether a repo-relative test path is skipped by policy.
+func (s TestSkips) Matches(rel string) bool {
+	return s.set.Match(rel)
+}
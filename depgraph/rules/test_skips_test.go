@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTestSkips(t *testing.T) {
+	skips, err := ParseTestSkips([]byte(`# known-flaky tests
+internal/net/retry_test.go
+
+e2e/**/*_test.ts
+!e2e/smoke/**
+`))
+	if err != nil {
+		t.Fatalf("ParseTestSkips() error = %v", err)
+	}
+
+	if len(skips.Patterns) != 3 {
+		t.Fatalf("expected 3 patterns, got %v", skips.Patterns)
+	}
+	for path, want := range map[string]bool{
+		"internal/net/retry_test.go":      true,
+		"internal/net/dial_test.go":       false,
+		"e2e/checkout/cart_test.ts":       true,
+		"e2e/smoke/login_test.ts":         false,
+		"internal/net/sub/retry_test.go":  false,
+		"e2e/checkout/fixtures/helper.ts": false,
+	} {
+		if got := skips.Matches(path); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestParseTestSkips_ReportsLineOfInvalidPattern(t *testing.T) {
+	_, err := ParseTestSkips([]byte("ok_test.go\n\nbad/[x_test.go\n"))
+	if err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+	if !strings.HasPrefix(err.Error(), "line 3: invalid pattern") {
+		t.Fatalf("expected line 3 in error, got %v", err)
+	}
+}
+
+func TestTestSkips_EmptyMatchesNothing(t *testing.T) {
+	if (TestSkips{}).Matches("a_test.go") {
+		t.Fatal("expected empty skips to match nothing")
+	}
+}
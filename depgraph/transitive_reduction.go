@@ -0,0 +1,171 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TransitiveReduction is the outcome of ReduceTransitiveEdges.
+type TransitiveReduction struct {
+	// Graph is the reduced graph. It has the same reachability as the input.
+	Graph DependencyGraph
+	// RemovedEdges lists the edges dropped because another path already implies them.
+	RemovedEdges []FileEdge
+	// CyclicComponents lists the strongly connected components whose internal edges
+	// were left untouched; transitive reduction is not unique inside a cycle.
+	CyclicComponents [][]string
+}
+
+// ReduceTransitiveEdges removes every edge A→C for which a longer path from A to C
+// exists. The reduction runs on the condensation of g (each strongly connected
+// component collapsed to one node), so edges inside cycles are kept as-is and only
+// edges between components are candidates for removal. Edge attributes are preserved.
+func ReduceTransitiveEdges(g DependencyGraph) (TransitiveReduction, error) {
+	adjacency, err := AdjacencyList(g)
+	if err != nil {
+		return TransitiveReduction{}, err
+	}
+
+	sccs := stronglyConnectedComponents(adjacency)
+	componentOf := make(map[string]int, len(adjacency))
+	for i, scc := range sccs {
+		for _, node := range scc {
+			componentOf[node] = i
+		}
+	}
+
+	var cyclic [][]string
+	for _, scc := range sccs {
+		if isCyclicSCC(adjacency, scc) {
+			cyclic = append(cyclic, scc)
+		}
+	}
+
+	successors := make([]map[int]bool, len(sccs))
+	for i := range successors {
+		successors[i] = make(map[int]bool)
+	}
+	for from, deps := range adjacency {
+		for _, to := range deps {
+			if cf, ct := componentOf[from], componentOf[to]; cf != ct {
+				successors[cf][ct] = true
+			}
+		}
+	}
+
+	order := topologicalComponentOrder(successors)
+	position := make([]int, len(order))
+	for i, component := range order {
+		position[component] = i
+	}
+
+	// Visit components sinks first. For each component, take its successors nearest
+	// first in topological order: a successor already reachable through an earlier one
+	// makes the direct condensation edge redundant.
+	reach := make([]componentSet, len(sccs))
+	redundant := make(map[[2]int]bool)
+	for i := len(order) - 1; i >= 0; i-- {
+		component := order[i]
+		reach[component] = newComponentSet(len(sccs))
+
+		next := make([]int, 0, len(successors[component]))
+		for successor := range successors[component] {
+			next = append(next, successor)
+		}
+		sort.Slice(next, func(a, b int) bool {
+			return position[next[a]] < position[next[b]]
+		})
+
+		for _, successor := range next {
+			if reach[component].has(successor) {
+				redundant[[2]int{component, successor}] = true
+				continue
+			}
+			reach[component].add(successor)
+			reach[component].union(reach[successor])
+		}
+	}
+
+	reduced, err := g.Clone()
+	if err != nil {
+		return TransitiveReduction{}, fmt.Errorf("failed to clone graph: %w", err)
+	}
+
+	var removed []FileEdge
+	for from, deps := range adjacency {
+		for _, to := range deps {
+			if !redundant[[2]int{componentOf[from], componentOf[to]}] {
+				continue
+			}
+			if err := reduced.RemoveEdge(from, to); err != nil {
+				return TransitiveReduction{}, fmt.Errorf("failed to remove edge %s -> %s: %w", from, to, err)
+			}
+			removed = append(removed, FileEdge{From: from, To: to})
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].From != removed[j].From {
+			return removed[i].From < removed[j].From
+		}
+		return removed[i].To < removed[j].To
+	})
+
+	return TransitiveReduction{
+		Graph:            reduced,
+		RemovedEdges:     removed,
+		CyclicComponents: cyclic,
+	}, nil
+}
+
+// topologicalComponentOrder orders the nodes of the acyclic condensation so that every
+// edge points forward. The reduction of a DAG is unique, so any valid order will do.
+func topologicalComponentOrder(successors []map[int]bool) []int {
+	inDegree := make([]int, len(successors))
+	for _, next := range successors {
+		for successor := range next {
+			inDegree[successor]++
+		}
+	}
+
+	var ready []int
+	for component, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, component)
+		}
+	}
+
+	order := make([]int, 0, len(successors))
+	for len(ready) > 0 {
+		component := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		order = append(order, component)
+		for successor := range successors[component] {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				ready = append(ready, successor)
+			}
+		}
+	}
+	return order
+}
+
+// componentSet is a bitset of component indices.
+type componentSet []uint64
+
+func newComponentSet(size int) componentSet {
+	return make(componentSet, (size+63)/64)
+}
+
+func (s componentSet) add(i int) {
+	s[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (s componentSet) has(i int) bool {
+	return s[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+func (s componentSet) union(other componentSet) {
+	for i := range s {
+		s[i] |= other[i]
+	}
+}
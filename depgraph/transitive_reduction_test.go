@@ -0,0 +1,165 @@
+package depgraph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph/moduleapi"
+)
+
+func TestReduceTransitiveEdges_RemovesShortcutEdge(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"B", "C"},
+		"B": {"C"},
+		"C": {},
+	})
+
+	result, err := ReduceTransitiveEdges(graph)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FileEdge{{From: "A", To: "C"}}, result.RemovedEdges)
+	assert.Equal(t, map[string][]string{"A": {"B"}, "B": {"C"}, "C": {}}, mustAdjacencyList(t, result.Graph))
+	assert.Empty(t, result.CyclicComponents)
+}
+
+func TestReduceTransitiveEdges_KeepsDiamondBranches(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"B", "C", "D"},
+		"B": {"D"},
+		"C": {"D"},
+		"D": {},
+	})
+
+	result, err := ReduceTransitiveEdges(graph)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FileEdge{{From: "A", To: "D"}}, result.RemovedEdges)
+}
+
+func TestReduceTransitiveEdges_LeavesCyclesUntouched(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"A": {"B", "D"},
+		"B": {"C"},
+		"C": {"A", "D"},
+		"D": {},
+	})
+
+	result, err := ReduceTransitiveEdges(graph)
+
+	require.NoError(t, err)
+	// A→D is implied by A→B→C→D, but A and C share a component, so the condensation
+	// has a single edge to D and both original edges into D are kept.
+	assert.Empty(t, result.RemovedEdges)
+	assert.Equal(t, [][]string{{"A", "B", "C"}}, result.CyclicComponents)
+	assert.Equal(t, mustAdjacencyList(t, graph), mustAdjacencyList(t, result.Graph))
+}
+
+func TestReduceTransitiveEdges_RemovesShortcutAcrossCycle(t *testing.T) {
+	graph := testGraph(map[string][]string{
+		"X": {"A", "D"},
+		"A": {"B"},
+		"B": {"A", "D"},
+		"D": {},
+	})
+
+	result, err := ReduceTransitiveEdges(graph)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FileEdge{{From: "X", To: "D"}}, result.RemovedEdges)
+}
+
+func TestReduceTransitiveEdges_PreservesEdgeAttributes(t *testing.T) {
+	graph := NewDependencyGraph()
+	for _, node := range []string{"A", "B", "C"} {
+		require.NoError(t, graph.AddVertex(node))
+	}
+	require.NoError(t, graph.AddEdge("A", "B", moduleapi.WithEdgeProvenance(string(EdgeProvenanceHeuristic))))
+	require.NoError(t, graph.AddEdge("B", "C"))
+	require.NoError(t, graph.AddEdge("A", "C"))
+
+	result, err := ReduceTransitiveEdges(graph)
+
+	require.NoError(t, err)
+	provenances, err := EdgeProvenances(result.Graph)
+	require.NoError(t, err)
+	assert.Equal(t, map[FileEdge]EdgeProvenance{{From: "A", To: "B"}: EdgeProvenanceHeuristic}, provenances)
+}
+
+func TestReduceTransitiveEdges_PreservesReachability(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iteration := 0; iteration < 50; iteration++ {
+		adjacency := randomAdjacency(rng, 12, 0.25, false)
+
+		result, err := ReduceTransitiveEdges(testGraph(adjacency))
+		require.NoError(t, err)
+
+		reduced := mustAdjacencyList(t, result.Graph)
+		for node := range adjacency {
+			assert.Equal(t, bfsReachable(adjacency, node), bfsReachable(reduced, node), "iteration %d, node %s", iteration, node)
+		}
+	}
+}
+
+func TestReduceTransitiveEdges_DAGResultIsMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for iteration := 0; iteration < 50; iteration++ {
+		adjacency := randomAdjacency(rng, 12, 0.35, true)
+
+		result, err := ReduceTransitiveEdges(testGraph(adjacency))
+		require.NoError(t, err)
+
+		reduced := mustAdjacencyList(t, result.Graph)
+		for node := range adjacency {
+			assert.Equal(t, bfsReachable(adjacency, node), bfsReachable(reduced, node), "iteration %d, node %s", iteration, node)
+		}
+		for from, deps := range reduced {
+			for _, to := range deps {
+				without := withoutEdge(reduced, from, to)
+				assert.False(t, bfsReachable(without, from)[to], "iteration %d: edge %s -> %s is redundant", iteration, from, to)
+			}
+		}
+	}
+}
+
+// randomAdjacency returns a random graph; with acyclic set, edges only point to
+// higher-numbered nodes.
+func randomAdjacency(rng *rand.Rand, nodes int, density float64, acyclic bool) map[string][]string {
+	adjacency := make(map[string][]string, nodes)
+	for i := 0; i < nodes; i++ {
+		from := fmt.Sprintf("n%02d", i)
+		adjacency[from] = []string{}
+		for j := 0; j < nodes; j++ {
+			if i == j || (acyclic && j < i) {
+				continue
+			}
+			if rng.Float64() < density {
+				adjacency[from] = append(adjacency[from], fmt.Sprintf("n%02d", j))
+			}
+		}
+	}
+	return adjacency
+}
+
+func withoutEdge(adjacency map[string][]string, from, to string) map[string][]string {
+	result := make(map[string][]string, len(adjacency))
+	for node, deps := range adjacency {
+		for _, dep := range deps {
+			if node == from && dep == to {
+				continue
+			}
+			result[node] = append(result[node], dep)
+		}
+	}
+	return result
+}
+
+func mustAdjacencyList(t *testing.T, graph DependencyGraph) map[string][]string {
+	t.Helper()
+	adjacency, err := AdjacencyList(graph)
+	require.NoError(t, err)
+	return adjacency
+}
@@ -0,0 +1,199 @@
+// Package cliconfig holds the global flags shared by every clarity subcommand and
+// resolves them once into a Context, so subcommands agree on what --repo means.
+package cliconfig
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/LegacyCodeHQ/clarity/internal/projectconfig"
+	"github.com/LegacyCodeHQ/clarity/vcs/git"
+)
+
+// Names of the global flags.
+const (
+	RepoFlag             = "repo"
+	AllowOutsideRepoFlag = "allow-outside-repo"
+	LogLevelFlag         = "log-level"
+	VerboseFlag          = "verbose"
+	QuietFlag            = "quiet"
+	TimeoutFlag          = "timeout"
+)
+
+const repoFlagUsage = "Git repository path (default: current directory)"
+
+const allowOutsideRepoFlagUsage = "Allow input paths outside the repo root"
+
+// deprecatedAliasNote is appended to the usage of subcommand-local copies of the
+// global flags.
+const deprecatedAliasNote = " (deprecated as a subcommand flag: pass the global flag instead)"
+
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// Context is the resolved value of the global flags for one command run.
+type Context struct {
+	// RepoPath is the absolute directory the command operates on: --repo when given,
+	// otherwise the current directory. Relative input paths are resolved against it.
+	RepoPath string
+	// RepoRoot is the top level of the git repository containing RepoPath, or empty
+	// when RepoPath is outside a repository and the subcommand tolerates that.
+	RepoRoot string
+	// AllowOutsideRepo permits input paths outside RepoPath.
+	AllowOutsideRepo bool
+}
+
+// ResolveOptions describes what a subcommand needs from the repository.
+type ResolveOptions struct {
+	// AllowNonRepo lets the subcommand run on a directory that is not inside a git
+	// repository, e.g. when it only reads files from disk.
+	AllowNonRepo bool
+}
+
+// AddGlobalFlags registers the global flags on the root command's persistent flags.
+func AddGlobalFlags(flags *pflag.FlagSet) {
+	flags.StringP(RepoFlag, "r", "", repoFlagUsage)
+	flags.Bool(AllowOutsideRepoFlag, false, allowOutsideRepoFlagUsage)
+	flags.String(LogLevelFlag, "warn", fmt.Sprintf("Log level (%s)", SupportedLogLevels()))
+	flags.BoolP(QuietFlag, "q", false, "Suppress warnings and progress; only errors are logged")
+	flags.Duration(TimeoutFlag, 0, "Cancel the run and any git commands it started after this long, e.g. 30s (0 = no limit)")
+}
+
+// AddRepoAlias registers a subcommand-local --repo flag bound to repoPath. Subcommands
+// defined it before it became global; it is kept for one release so existing scripts
+// and standalone uses of the subcommand keep working. Attached to the root command,
+// it accepts the same values as the global flag in either position.
+func AddRepoAlias(cmd *cobra.Command, repoPath *string) {
+	cmd.Flags().StringVarP(repoPath, RepoFlag, "r", "", repoFlagUsage+deprecatedAliasNote)
+}
+
+// AddAllowOutsideRepoAlias registers a subcommand-local --allow-outside-repo flag
+// bound to allowOutside, kept for the same reason as AddRepoAlias.
+func AddAllowOutsideRepoAlias(cmd *cobra.Command, allowOutside *bool) {
+	cmd.Flags().BoolVar(allowOutside, AllowOutsideRepoFlag, false, allowOutsideRepoFlagUsage+deprecatedAliasNote)
+}
+
+// Resolve reads the global flags parsed for cmd and resolves the repository: --repo
+// or the current directory, made absolute, then the root of the git repository
+// containing it. Outside a repository it fails with guidance unless opts.AllowNonRepo
+// is set. Flags that cmd does not define resolve to their defaults.
+func Resolve(cmd *cobra.Command, opts ResolveOptions) (Context, error) {
+	flags := cmd.Flags()
+	repoPath, _ := flags.GetString(RepoFlag)
+	allowOutside, _ := flags.GetBool(AllowOutsideRepoFlag)
+
+	if repoPath == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return Context{}, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		repoPath = cwd
+	}
+	absRepoPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return Context{}, fmt.Errorf("failed to resolve repo path: %w", err)
+	}
+
+	ctx := Context{
+		RepoPath:         filepath.Clean(absRepoPath),
+		AllowOutsideRepo: allowOutside,
+	}
+
+	repoRoot, err := git.GetRepositoryRoot(ctx.RepoPath)
+	if err != nil {
+		if opts.AllowNonRepo {
+			return ctx, nil
+		}
+		switch {
+		case errors.Is(err, git.ErrRepoPathMissing):
+			return Context{}, fmt.Errorf("repository path %s does not exist: pass an existing directory with --repo", ctx.RepoPath)
+		case errors.Is(err, git.ErrNotARepository):
+			return Context{}, fmt.Errorf("%s is not inside a git repository: run clarity from a repository or pass --repo <path>", ctx.RepoPath)
+		default:
+			return Context{}, fmt.Errorf("failed to find the repository of %s: %w", ctx.RepoPath, err)
+		}
+	}
+	ctx.RepoRoot = filepath.Clean(repoRoot)
+	return ctx, nil
+}
+
+// ApplyProjectConfig sets the flags of cmd that the repository's config file
+// (.clarity.yml) covers and that were not given on the command line. The file is read
+// from the repository root, or from --repo when it is not inside a repository; a
+// missing file is not an error.
+func ApplyProjectConfig(cmd *cobra.Command) error {
+	repo, err := Resolve(cmd, ResolveOptions{AllowNonRepo: true})
+	if err != nil {
+		return err
+	}
+	dir := repo.RepoRoot
+	if dir == "" {
+		dir = repo.RepoPath
+	}
+
+	path, err := projectconfig.Find(dir)
+	if err != nil || path == "" {
+		return err
+	}
+	config, err := projectconfig.Load(path)
+	if err != nil {
+		return err
+	}
+	applied, err := config.Apply(cmd.Name(), cmd.Flags())
+	if err != nil {
+		return err
+	}
+	slog.Debug("applied config file", "path", path, "flags", applied)
+	return nil
+}
+
+// LogLevel returns the log level selected by --log-level; --verbose selects debug and
+// --quiet selects error.
+func LogLevel(cmd *cobra.Command) (slog.Level, error) {
+	flags := cmd.Flags()
+	verbose, _ := flags.GetBool(VerboseFlag)
+	quiet, _ := flags.GetBool(QuietFlag)
+	switch {
+	case verbose && quiet:
+		return 0, fmt.Errorf("--%s and --%s cannot be used together", VerboseFlag, QuietFlag)
+	case verbose:
+		return slog.LevelDebug, nil
+	case quiet:
+		return slog.LevelError, nil
+	}
+	name, _ := flags.GetString(LogLevelFlag)
+	if name == "" {
+		return slog.LevelWarn, nil
+	}
+	level, ok := logLevels[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown log level: %s (valid options: %s)", name, SupportedLogLevels())
+	}
+	return level, nil
+}
+
+// SupportedLogLevels returns the accepted --log-level values, most verbose first.
+func SupportedLogLevels() string {
+	return "debug, info, warn, error"
+}
+
+// Timeout returns the run deadline selected by --timeout; 0 means no limit.
+func Timeout(cmd *cobra.Command) (time.Duration, error) {
+	timeout, _ := cmd.Flags().GetDuration(TimeoutFlag)
+	if timeout < 0 {
+		return 0, fmt.Errorf("--%s must not be negative", TimeoutFlag)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,132 @@
+package cliconfig
+
+import (
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().BoolP(VerboseFlag, "v", false, "")
+	AddGlobalFlags(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	out, err := exec.Command("git", "-C", dir, "init").CombinedOutput()
+	require.NoError(t, err, string(out))
+	resolved, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestResolve_SubdirectoryFindsRepositoryRoot(t *testing.T) {
+	repoDir := initRepo(t)
+	subDir := filepath.Join(repoDir, "pkg")
+	require.NoError(t, os.MkdirAll(subDir, 0o755))
+
+	ctx, err := Resolve(newTestCommand(t, "--repo", subDir, "--allow-outside-repo"), ResolveOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, subDir, ctx.RepoPath)
+	assert.Equal(t, repoDir, ctx.RepoRoot)
+	assert.True(t, ctx.AllowOutsideRepo)
+}
+
+func TestResolve_DefaultsToCurrentDirectory(t *testing.T) {
+	repoDir := initRepo(t)
+	t.Chdir(repoDir)
+
+	ctx, err := Resolve(newTestCommand(t), ResolveOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, repoDir, ctx.RepoPath)
+	assert.Equal(t, repoDir, ctx.RepoRoot)
+}
+
+func TestResolve_OutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Resolve(newTestCommand(t, "-r", dir), ResolveOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not inside a git repository: run clarity from a repository or pass --repo <path>")
+
+	ctx, err := Resolve(newTestCommand(t, "-r", dir), ResolveOptions{AllowNonRepo: true})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Clean(dir), ctx.RepoPath)
+	assert.Empty(t, ctx.RepoRoot)
+}
+
+func TestResolve_MissingDirectory(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	_, err := Resolve(newTestCommand(t, "-r", missing), ResolveOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+}
+
+func TestResolve_CommandWithoutGlobalFlags(t *testing.T) {
+	repoDir := initRepo(t)
+	t.Chdir(repoDir)
+
+	ctx, err := Resolve(&cobra.Command{Use: "bare"}, ResolveOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, repoDir, ctx.RepoRoot)
+	assert.False(t, ctx.AllowOutsideRepo)
+}
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		args []string
+		want slog.Level
+	}{
+		{args: nil, want: slog.LevelWarn},
+		{args: []string{"--log-level", "debug"}, want: slog.LevelDebug},
+		{args: []string{"--log-level", "INFO"}, want: slog.LevelInfo},
+		{args: []string{"--log-level", "error"}, want: slog.LevelError},
+		{args: []string{"--log-level", "error", "--verbose"}, want: slog.LevelDebug},
+		{args: []string{"--log-level", "debug", "--quiet"}, want: slog.LevelError},
+		{args: []string{"-q"}, want: slog.LevelError},
+	}
+
+	for _, tc := range tests {
+		got, err := LogLevel(newTestCommand(t, tc.args...))
+		require.NoError(t, err, tc.args)
+		assert.Equal(t, tc.want, got, tc.args)
+	}
+
+	_, err := LogLevel(newTestCommand(t, "--log-level", "trace"))
+	assert.EqualError(t, err, "unknown log level: trace (valid options: debug, info, warn, error)")
+
+	_, err = LogLevel(newTestCommand(t, "--verbose", "--quiet"))
+	assert.EqualError(t, err, "--verbose and --quiet cannot be used together")
+}
+
+func TestTimeout(t *testing.T) {
+	got, err := Timeout(newTestCommand(t))
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), got)
+
+	got, err = Timeout(newTestCommand(t, "--timeout", "90s"))
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, got)
+
+	_, err = Timeout(newTestCommand(t, "--timeout", "-1s"))
+	assert.EqualError(t, err, "--timeout must not be negative")
+}
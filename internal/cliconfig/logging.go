@@ -0,0 +1,51 @@
+package cliconfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+)
+
+type loggerKey struct{}
+
+// NewLogger returns the logger a command run writes to w, dropping records below
+// level.
+func NewLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// WithLogger returns a copy of ctx carrying logger, which Logger returns for commands
+// run with it.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the logger the root command set up for cmd's run, or slog's default
+// logger when cmd runs on its own, e.g. in a test of the subcommand.
+func Logger(cmd *cobra.Command) *slog.Logger {
+	if ctx := cmd.Context(); ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// Warnf writes a "Warning: " line to cmd's standard error unless the run's logger
+// drops warnings, as it does under --quiet.
+func Warnf(cmd *cobra.Command, format string, args ...any) {
+	if !Logger(cmd).Enabled(commandContext(cmd), slog.LevelWarn) {
+		return
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Warning: "+format+"\n", args...)
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
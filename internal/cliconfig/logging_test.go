@@ -0,0 +1,41 @@
+package cliconfig
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLoggedCommand(level slog.Level) (*cobra.Command, *bytes.Buffer) {
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetErr(&stderr)
+	cmd.SetContext(WithLogger(context.Background(), NewLogger(&stderr, level)))
+	return cmd, &stderr
+}
+
+func TestLogger_ReturnsTheInjectedLogger(t *testing.T) {
+	cmd, stderr := newLoggedCommand(slog.LevelDebug)
+
+	Logger(cmd).Debug("discovered files", "count", 3)
+
+	assert.Contains(t, stderr.String(), `msg="discovered files" count=3`)
+}
+
+func TestLogger_FallsBackToTheDefaultLogger(t *testing.T) {
+	assert.Same(t, slog.Default(), Logger(&cobra.Command{Use: "test"}))
+}
+
+func TestWarnf_IsDroppedWhenWarningsAreFiltered(t *testing.T) {
+	cmd, stderr := newLoggedCommand(slog.LevelWarn)
+	Warnf(cmd, "stale suppression %s", "a -> b")
+	assert.Equal(t, "Warning: stale suppression a -> b\n", stderr.String())
+
+	quiet, quietStderr := newLoggedCommand(slog.LevelError)
+	Warnf(quiet, "stale suppression %s", "a -> b")
+	assert.Empty(t, quietStderr.String())
+}
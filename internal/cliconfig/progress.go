@@ -0,0 +1,84 @@
+package cliconfig
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// progressDelay is how long a run goes silent before progress is reported, so
+	// quick runs print nothing.
+	progressDelay = 2 * time.Second
+	// progressInterval is the least time between two progress lines.
+	progressInterval = 500 * time.Millisecond
+)
+
+// stderrIsTerminal reports whether w is an interactive terminal.
+var stderrIsTerminal = func(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// now is the clock progress is timed with.
+var now = time.Now
+
+// Progress returns a callback reporting "N/M files parsed" on cmd's standard error,
+// rewriting one line, once the run has taken longer than two seconds. It returns nil,
+// reporting nothing, when standard error is not a terminal or the run's logger drops
+// warnings, as it does under --quiet. The callback is safe for concurrent use.
+func Progress(cmd *cobra.Command) func(parsed, total int) {
+	out := cmd.ErrOrStderr()
+	if !stderrIsTerminal(out) || !Logger(cmd).Enabled(commandContext(cmd), slog.LevelWarn) {
+		return nil
+	}
+	p := &progress{out: out, start: now()}
+	return p.report
+}
+
+type progress struct {
+	mu      sync.Mutex
+	out     io.Writer
+	start   time.Time
+	printed time.Time
+	// last is the count of the latest line, so reports arriving out of order from
+	// concurrent parsers never move it backwards.
+	last int
+	done bool
+}
+
+func (p *progress) report(parsed, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := now()
+	done := parsed >= total
+	if p.done || parsed <= p.last || t.Sub(p.start) < progressDelay {
+		return
+	}
+	if !done && !p.printed.IsZero() && t.Sub(p.printed) < progressInterval {
+		return
+	}
+	if done && p.printed.IsZero() {
+		// The run ended before a line was due; there is nothing to finish.
+		p.done = true
+		return
+	}
+	p.printed, p.last, p.done = t, parsed, done
+	fmt.Fprintf(p.out, "\r%d/%d files parsed", parsed, total)
+	if done {
+		fmt.Fprintln(p.out)
+	}
+}
@@ -0,0 +1,60 @@
+package cliconfig
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubProgressEnvironment makes standard error a terminal and returns a function that
+// advances the progress clock.
+func stubProgressEnvironment(t *testing.T) func(time.Duration) {
+	t.Helper()
+
+	restoreTerminal, restoreNow := stderrIsTerminal, now
+	t.Cleanup(func() { stderrIsTerminal, now = restoreTerminal, restoreNow })
+
+	current := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stderrIsTerminal = func(io.Writer) bool { return true }
+	now = func() time.Time { return current }
+	return func(d time.Duration) { current = current.Add(d) }
+}
+
+func TestProgress_ReportsOnlyAfterTheDelay(t *testing.T) {
+	advance := stubProgressEnvironment(t)
+	cmd, stderr := newLoggedCommand(slog.LevelWarn)
+
+	report := Progress(cmd)
+	report(1, 4)
+	advance(3 * time.Second)
+	report(2, 4)
+	report(3, 4)
+	advance(time.Second)
+	report(4, 4)
+
+	assert.Equal(t, "\r2/4 files parsed\r4/4 files parsed\n", stderr.String())
+}
+
+func TestProgress_QuickRunPrintsNothing(t *testing.T) {
+	stubProgressEnvironment(t)
+	cmd, stderr := newLoggedCommand(slog.LevelWarn)
+
+	report := Progress(cmd)
+	report(1, 2)
+	report(2, 2)
+
+	assert.Empty(t, stderr.String())
+}
+
+func TestProgress_DisabledWhenQuietOrNotATerminal(t *testing.T) {
+	stubProgressEnvironment(t)
+	quiet, _ := newLoggedCommand(slog.LevelError)
+	assert.Nil(t, Progress(quiet))
+
+	stderrIsTerminal = func(io.Writer) bool { return false }
+	cmd, _ := newLoggedCommand(slog.LevelWarn)
+	assert.Nil(t, Progress(cmd))
+}
@@ -0,0 +1,116 @@
+// Package metadata describes how a rendered graph was produced, so a shared graph
+// can be reproduced and a re-run can be checked for changes.
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Version is the clarity version written into metadata, set via build-time ldflags.
+var Version = "dev"
+
+// WorkingTree is the commit of metadata for a graph of uncommitted changes.
+const WorkingTree = "working-tree"
+
+// Metadata is the reproducibility record appended to a graph by --metadata.
+type Metadata struct {
+	// Version is the clarity version that rendered the graph.
+	Version string `json:"version"`
+	// Repo is the repository name used in the graph label.
+	Repo string `json:"repo"`
+	// Commit is the analyzed commit or from..to range as full SHAs, or WorkingTree.
+	Commit string `json:"commit"`
+	// Flags are the flags set on the command line, as --name=value, sorted by name.
+	Flags []string `json:"flags"`
+	// Files is the number of file nodes in the graph.
+	Files int `json:"files"`
+	// ContentHash identifies the node and edge set; see ContentHash.
+	ContentHash string `json:"contentHash"`
+}
+
+// New returns the metadata of a graph of repo at commit rendered with flags, for the
+// running Version. The file count and content hash are filled in by WithGraph.
+func New(repo, commit string, flags []string) Metadata {
+	return Metadata{
+		Version: Version,
+		Repo:    repo,
+		Commit:  commit,
+		Flags:   append([]string{}, flags...),
+	}
+}
+
+// WithGraph returns m with the file count and content hash of a graph of nodes and
+// edges, each edge a from and to node.
+func (m Metadata) WithGraph(files int, nodes []string, edges [][2]string) Metadata {
+	m.Files = files
+	m.ContentHash = ContentHash(nodes, edges)
+	return m
+}
+
+// ContentHash returns the SHA-256 of the sorted node and edge set, as
+// "sha256:<hex>". Node order and duplicates do not change it.
+func ContentHash(nodes []string, edges [][2]string) string {
+	lines := make([]string, 0, len(nodes)+len(edges))
+	for _, node := range nodes {
+		lines = append(lines, "node\t"+node)
+	}
+	for _, edge := range edges {
+		lines = append(lines, "edge\t"+edge[0]+"\t"+edge[1])
+	}
+	sort.Strings(lines)
+
+	hash := sha256.New()
+	previous := ""
+	for i, line := range lines {
+		if i > 0 && line == previous {
+			continue
+		}
+		hash.Write([]byte(line + "\n"))
+		previous = line
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// Lines returns m as "key: value" lines, for formats that carry it in comments.
+func (m Metadata) Lines() []string {
+	return []string{
+		"clarity-metadata",
+		"version: " + m.Version,
+		"repo: " + m.Repo,
+		"commit: " + m.Commit,
+		"flags: " + strings.Join(m.Flags, " "),
+		fmt.Sprintf("files: %d", m.Files),
+		"content-hash: " + m.ContentHash,
+	}
+}
+
+// CommandFlags returns the flags of flags set on the command line as --name=value,
+// sorted by name. A slice flag is written once per value, and a value holding
+// whitespace or quotes is quoted.
+func CommandFlags(flags *pflag.FlagSet) []string {
+	var set []string
+	flags.Visit(func(flag *pflag.Flag) {
+		values := []string{flag.Value.String()}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			values = slice.GetSlice()
+		}
+		for _, value := range values {
+			set = append(set, "--"+flag.Name+"="+quoteFlagValue(value))
+		}
+	})
+	return set
+}
+
+func quoteFlagValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"'") {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_UsesVersion(t *testing.T) {
+	previous := Version
+	Version = "v1.2.3"
+	t.Cleanup(func() { Version = previous })
+
+	md := New("app", WorkingTree, []string{"--level=2"})
+
+	assert.Equal(t, Metadata{Version: "v1.2.3", Repo: "app", Commit: WorkingTree, Flags: []string{"--level=2"}}, md)
+}
+
+func TestContentHash_IgnoresOrderAndDuplicates(t *testing.T) {
+	hash := ContentHash([]string{"a.go", "b.go"}, [][2]string{{"a.go", "b.go"}})
+
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, hash)
+	assert.Equal(t, hash, ContentHash([]string{"b.go", "a.go", "a.go"}, [][2]string{{"a.go", "b.go"}, {"a.go", "b.go"}}))
+	assert.NotEqual(t, hash, ContentHash([]string{"a.go", "b.go"}, [][2]string{{"b.go", "a.go"}}))
+	assert.NotEqual(t, hash, ContentHash([]string{"a.go", "b.go"}, nil))
+}
+
+func TestLines(t *testing.T) {
+	md := Metadata{Version: "dev", Repo: "app", Commit: "abc..def", Flags: []string{"--level=2", "--metadata=true"}}.
+		WithGraph(2, []string{"a.go", "b.go"}, nil)
+
+	assert.Equal(t, []string{
+		"clarity-metadata",
+		"version: dev",
+		"repo: app",
+		"commit: abc..def",
+		"flags: --level=2 --metadata=true",
+		"files: 2",
+		"content-hash: " + md.ContentHash,
+	}, md.Lines())
+}
+
+func TestCommandFlags_ListsChangedFlagsSorted(t *testing.T) {
+	flags := pflag.NewFlagSet("show", pflag.ContinueOnError)
+	flags.Int("level", 0, "")
+	flags.String("file", "", "")
+	flags.StringSlice("include-ext", nil, "")
+	flags.Bool("label", false, "")
+	flags.String("output", "", "")
+
+	require.NoError(t, flags.Parse([]string{"--level", "2", "--file", "cmd/my file.go", "--include-ext", ".go,.ts", "--label"}))
+
+	assert.Equal(t, []string{
+		`--file="cmd/my file.go"`,
+		"--include-ext=.go",
+		"--include-ext=.ts",
+		"--label=true",
+		"--level=2",
+	}, CommandFlags(flags))
+}
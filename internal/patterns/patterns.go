@@ -0,0 +1,348 @@
+// Package patterns implements the path pattern syntax shared by every feature that
+// matches repo-relative paths (suppression rules, --also, --between, ...).
+//
+// A pattern is a doublestar glob matched against a slash-separated, repo-relative path:
+//
+//   - "*" matches any run of characters within one path segment
+//   - "?" matches any single character within one path segment
+//   - "**" as a whole segment matches zero or more segments ("a/**/b", "**/x.go", "a/**")
+//   - "[abc]" matches one character from the class; "[a-z]" ranges, "[!abc]" or "[^abc]" negate
+//   - "{a,b}" matches any of the alternatives, which may nest and contain globs
+//   - "\c" matches the character c literally, e.g. "\*" or "\{"
+//
+// A pattern without a slash matches the base name at any depth, so "*_test.go" matches
+// "pkg/a_test.go". A leading "/" anchors such a pattern to the repository root instead,
+// a leading "./" is ignored, and a trailing "/" matches everything below a directory.
+// "**" next to other characters in a segment behaves like "*".
+//
+// Two prefixes modify a pattern, in this order: "!" negates it within a Set, and
+// "(?i)" makes it match case-insensitively, as in "!(?i)**/vendor/**".
+//
+// Paths may use "\" as a separator (Windows); it is treated as "/" when matching.
+package patterns
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	negationPrefix = "!"
+	foldCasePrefix = "(?i)"
+)
+
+// SyntaxError reports a malformed pattern and the byte offset of the problem.
+type SyntaxError struct {
+	Pattern string
+	Offset  int
+	Msg     string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid pattern %q at offset %d: %s", e.Pattern, e.Offset, e.Msg)
+}
+
+// Pattern is a compiled path pattern.
+type Pattern struct {
+	source   string
+	negated  bool
+	foldCase bool
+	re       *regexp.Regexp
+}
+
+// Compile parses a pattern. Errors are *SyntaxError values.
+func Compile(pattern string) (Pattern, error) {
+	p := Pattern{source: pattern}
+	glob, offset := pattern, 0
+
+	if strings.HasPrefix(glob, negationPrefix) {
+		p.negated = true
+		glob, offset = glob[len(negationPrefix):], offset+len(negationPrefix)
+	}
+	if strings.HasPrefix(glob, foldCasePrefix) {
+		p.foldCase = true
+		glob, offset = glob[len(foldCasePrefix):], offset+len(foldCasePrefix)
+	}
+	if p.foldCase && strings.HasPrefix(glob, negationPrefix) {
+		return Pattern{}, &SyntaxError{Pattern: pattern, Offset: offset, Msg: "negation \"!\" must come before \"(?i)\""}
+	}
+
+	anchored := false
+	switch {
+	case strings.HasPrefix(glob, "./"):
+		glob, offset = glob[2:], offset+2
+	case strings.HasPrefix(glob, "/"):
+		anchored = true
+		glob, offset = glob[1:], offset+1
+	}
+	if strings.HasSuffix(glob, "/") && !strings.HasSuffix(glob, `\/`) {
+		glob += "**"
+	}
+	if glob == "" {
+		return Pattern{}, &SyntaxError{Pattern: pattern, Offset: offset, Msg: "empty pattern"}
+	}
+
+	t := translator{pattern: pattern, glob: glob, base: offset}
+	body, err := t.translate()
+	if err != nil {
+		return Pattern{}, err
+	}
+
+	var sb strings.Builder
+	if p.foldCase {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteString("^")
+	if !anchored && !t.sawSeparator {
+		sb.WriteString("(?:.*/)?")
+	}
+	sb.WriteString(body)
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return Pattern{}, &SyntaxError{Pattern: pattern, Offset: offset, Msg: err.Error()}
+	}
+	p.re = re
+	return p, nil
+}
+
+// MustCompile is like Compile but panics on error. Intended for tests and constants.
+func MustCompile(pattern string) Pattern {
+	p, err := Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the pattern as written.
+func (p Pattern) String() string {
+	return p.source
+}
+
+// Negated reports whether the pattern starts with "!".
+func (p Pattern) Negated() bool {
+	return p.negated
+}
+
+// Match reports whether relPath matches the glob, ignoring negation. Use a Set to
+// apply negation and precedence.
+func (p Pattern) Match(relPath string) bool {
+	if p.re == nil {
+		return false
+	}
+	return p.re.MatchString(normalizePath(relPath))
+}
+
+// HasMeta reports whether s uses any pattern syntax, so callers can tell a pattern
+// from a literal path. A backslash alone does not count, since it may be a Windows
+// separator.
+func HasMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[{") || strings.HasPrefix(s, negationPrefix) || strings.HasPrefix(s, foldCasePrefix)
+}
+
+func normalizePath(relPath string) string {
+	relPath = strings.ReplaceAll(relPath, `\`, "/")
+	return strings.TrimPrefix(relPath, "./")
+}
+
+// translator converts a glob into an unanchored regular expression body.
+type translator struct {
+	pattern string
+	glob    string
+	base    int // offset of glob within pattern, for error positions
+	pos     int
+
+	sawSeparator bool
+}
+
+func (t *translator) errorf(offset int, format string, args ...any) error {
+	return &SyntaxError{Pattern: t.pattern, Offset: t.base + offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (t *translator) translate() (string, error) {
+	return t.sequence(0)
+}
+
+// sequence translates until the end of the glob or, inside braces (depth > 0), until
+// an unescaped "," or "}". Callers inside braces check for a missing "}".
+func (t *translator) sequence(depth int) (string, error) {
+	var sb strings.Builder
+	for t.pos < len(t.glob) {
+		c := t.glob[t.pos]
+		switch c {
+		case '\\':
+			if t.pos+1 >= len(t.glob) {
+				return "", t.errorf(t.pos, "trailing backslash")
+			}
+			_, size := utf8.DecodeRuneInString(t.glob[t.pos+1:])
+			sb.WriteString(regexp.QuoteMeta(t.glob[t.pos+1 : t.pos+1+size]))
+			t.pos += 1 + size
+		case '/':
+			t.sawSeparator = true
+			if rest := t.glob[t.pos+1:]; len(rest) >= 2 && strings.Trim(rest, "*") == "" {
+				// A trailing "/**" also matches the directory path itself.
+				sb.WriteString("(?:/.*)?")
+				t.pos = len(t.glob)
+				continue
+			}
+			sb.WriteByte('/')
+			t.pos++
+		case '?':
+			sb.WriteString("[^/]")
+			t.pos++
+		case '*':
+			sb.WriteString(t.star(depth))
+		case '[':
+			class, err := t.class()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(class)
+		case '{':
+			alternatives, err := t.braces(depth)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(alternatives)
+		case ',':
+			if depth > 0 {
+				return sb.String(), nil
+			}
+			sb.WriteByte(',')
+			t.pos++
+		case '}':
+			if depth > 0 {
+				return sb.String(), nil
+			}
+			return "", t.errorf(t.pos, "unmatched \"}\"")
+		default:
+			sb.WriteString(regexp.QuoteMeta(t.glob[t.pos : t.pos+1]))
+			t.pos++
+		}
+	}
+	return sb.String(), nil
+}
+
+// star translates "*" or "**" at t.pos. Inside braces, alternative boundaries also
+// count as segment boundaries, so "{app,lib/**}" works.
+func (t *translator) star(depth int) string {
+	start := t.pos
+	if start+1 >= len(t.glob) || t.glob[start+1] != '*' {
+		t.pos++
+		return "[^/]*"
+	}
+
+	t.pos += 2
+	for t.pos < len(t.glob) && t.glob[t.pos] == '*' {
+		t.pos++
+	}
+	atSegmentStart := start == 0 || t.glob[start-1] == '/' || (depth > 0 && strings.ContainsRune("{,", rune(t.glob[start-1])))
+	atAlternativeEnd := depth > 0 && t.pos < len(t.glob) && strings.ContainsRune(",}", rune(t.glob[t.pos]))
+	atSegmentEnd := t.pos == len(t.glob) || t.glob[t.pos] == '/' || atAlternativeEnd
+	switch {
+	case !atSegmentStart || !atSegmentEnd:
+		return "[^/]*"
+	case t.pos == len(t.glob) || atAlternativeEnd:
+		return ".*"
+	default:
+		// "**/" matches zero or more leading segments.
+		t.pos++
+		t.sawSeparator = true
+		return "(?:.*/)?"
+	}
+}
+
+// class translates a "[...]" character class at t.pos. Classes never match "/".
+func (t *translator) class() (string, error) {
+	open := t.pos
+	t.pos++
+
+	negated := false
+	if t.pos < len(t.glob) && (t.glob[t.pos] == '!' || t.glob[t.pos] == '^') {
+		negated = true
+		t.pos++
+	}
+
+	var items []string
+	first := true
+	for {
+		if t.pos >= len(t.glob) {
+			return "", t.errorf(open, "unterminated character class")
+		}
+		c := t.glob[t.pos]
+		if c == ']' && !first {
+			t.pos++
+			break
+		}
+		first = false
+
+		rangeAt := t.pos
+		lo, err := t.classChar(open)
+		if err != nil {
+			return "", err
+		}
+		if t.pos+1 < len(t.glob) && t.glob[t.pos] == '-' && t.glob[t.pos+1] != ']' {
+			t.pos++
+			hi, err := t.classChar(open)
+			if err != nil {
+				return "", err
+			}
+			if hi < lo {
+				return "", t.errorf(rangeAt, "invalid range %q-%q", lo, hi)
+			}
+			items = append(items, regexp.QuoteMeta(string(lo))+"-"+regexp.QuoteMeta(string(hi)))
+			continue
+		}
+		items = append(items, regexp.QuoteMeta(string(lo)))
+	}
+
+	if negated {
+		return "[^/" + strings.Join(items, "") + "]", nil
+	}
+	return "(?:[" + strings.Join(items, "") + "])", nil
+}
+
+// classChar reads one, possibly escaped, character inside a class.
+func (t *translator) classChar(open int) (rune, error) {
+	switch t.glob[t.pos] {
+	case '\\':
+		t.pos++
+		if t.pos >= len(t.glob) {
+			return 0, t.errorf(open, "unterminated character class")
+		}
+	case '/':
+		return 0, t.errorf(t.pos, "character class cannot match \"/\"")
+	}
+	r, size := utf8.DecodeRuneInString(t.glob[t.pos:])
+	t.pos += size
+	return r, nil
+}
+
+// braces translates a "{a,b}" group at t.pos.
+func (t *translator) braces(depth int) (string, error) {
+	open := t.pos
+	t.pos++
+
+	var alternatives []string
+	for {
+		alternative, err := t.sequence(depth + 1)
+		if err != nil {
+			return "", err
+		}
+		if t.pos >= len(t.glob) {
+			return "", t.errorf(open, "unterminated \"{\"")
+		}
+		alternatives = append(alternatives, alternative)
+
+		c := t.glob[t.pos]
+		t.pos++
+		if c == '}' {
+			break
+		}
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")", nil
+}
@@ -0,0 +1,164 @@
+package patterns
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Base-name patterns match at any depth.
+		{"*_test.go", "pkg/foo/bar_test.go", true},
+		{"*_test.go", "pkg/foo/bar.go", false},
+		{"*.go", "main.go", true},
+		{"main.go", "cmd/main.go", true},
+		{"/main.go", "cmd/main.go", false},
+		{"/main.go", "main.go", true},
+
+		// Single star and question mark stay within one segment.
+		{"cmd/*.go", "cmd/root.go", true},
+		{"cmd/*.go", "cmd/show/show_cmd.go", false},
+		{"cmd/?.go", "cmd/a.go", true},
+		{"cmd/?.go", "cmd/ab.go", false},
+		{"a?b", "a/b", false},
+
+		// Globstar.
+		{"**", "any/thing/at/all.go", true},
+		{"cmd/**", "cmd/show/show_cmd.go", true},
+		{"cmd/**", "cmd", true},
+		{"cmd/**", "cmdx/a.go", false},
+		{"**/legacy/*.go", "internal/legacy/db.go", true},
+		{"**/legacy/*.go", "legacy/db.go", true},
+		{"**/legacy/*.go", "internal/legacy/sub/db.go", false},
+		{"internal/**/db.go", "internal/a/b/db.go", true},
+		{"internal/**/db.go", "internal/db.go", true},
+		{"internal/**/db.go", "internaldb.go", false},
+		{"a**b.go", "axyb.go", true},
+		{"a**b.go", "ax/yb.go", false},
+		{"vendor/", "vendor/lib/a.go", true},
+		{"vendor/", "src/vendor/a.go", false},
+
+		// Leading ./ is ignored on both sides.
+		{"./internal/*.go", "internal/x.go", true},
+		{"internal/*.go", "./internal/x.go", true},
+
+		// Character classes.
+		{"[abc].go", "b.go", true},
+		{"[abc].go", "d.go", false},
+		{"[a-c].go", "c.go", true},
+		{"[!a-c].go", "d.go", true},
+		{"[^a-c].go", "a.go", false},
+		{"a[!x]b", "a/b", false},
+		{"[]].go", "].go", true},
+		{"[\\]x].go", "].go", true},
+		{"[é].go", "é.go", true},
+
+		// Braces.
+		{"*.{go,ts}", "src/a.ts", true},
+		{"*.{go,ts}", "src/a.js", false},
+		{"src/{app,lib/**}/*.ts", "src/app/a.ts", true},
+		{"src/{app,lib/**}/*.ts", "src/lib/x/y/a.ts", true},
+		{"src/{app,lib/**}/*.ts", "src/other/a.ts", false},
+		{"{a,{b,c}}.go", "c.go", true},
+		{"{a,}x.go", "x.go", true},
+		{"a,b.go", "a,b.go", true},
+
+		// Escaped special characters.
+		{`\*.go`, "*.go", true},
+		{`\*.go`, "a.go", false},
+		{`file\[1\].go`, "file[1].go", true},
+		{`\{a,b\}.go`, "{a,b}.go", true},
+		{`\!important.go`, "!important.go", true},
+		{`a\?.go`, "a?.go", true},
+		{`a\?.go`, "ab.go", false},
+		{"a+b(c).go", "a+b(c).go", true},
+		{"a.go", "abgo", false},
+
+		// Case folding.
+		{"(?i)readme.md", "docs/README.md", true},
+		{"readme.md", "docs/README.md", false},
+		{"(?i)SRC/**", "src/a.go", true},
+
+		// Windows separators in paths.
+		{"cmd/*.go", `cmd\root.go`, true},
+		{"**/legacy/*.go", `internal\legacy\db.go`, true},
+		{"*_test.go", `pkg\foo\bar_test.go`, true},
+		{"cmd/*.go", `cmd\show\show_cmd.go`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			p, err := Compile(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.Match(tt.path))
+		})
+	}
+}
+
+func TestPattern_MatchIgnoresNegation(t *testing.T) {
+	p := MustCompile("!vendor/**")
+
+	assert.True(t, p.Negated())
+	assert.True(t, p.Match("vendor/a.go"))
+	assert.Equal(t, "!vendor/**", p.String())
+}
+
+func TestPattern_NegatedFoldCase(t *testing.T) {
+	p := MustCompile("!(?i)**/vendor/**")
+
+	assert.True(t, p.Negated())
+	assert.True(t, p.Match("third_party/VENDOR/a.go"))
+}
+
+func TestCompile_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		wantOffset int
+		wantMsg    string
+	}{
+		{"cmd/[a-", 4, "unterminated character class"},
+		{"[abc", 0, "unterminated character class"},
+		{"[z-a].go", 1, "invalid range"},
+		{"[a/b]", 2, "character class cannot match"},
+		{"src/{a,b", 4, `unterminated "{"`},
+		{"a}.go", 1, `unmatched "}"`},
+		{`a.go\`, 4, "trailing backslash"},
+		{"", 0, "empty pattern"},
+		{"!", 1, "empty pattern"},
+		{"(?i)!a.go", 4, `negation "!" must come before "(?i)"`},
+		{"!(?i)src/{a,b", 9, `unterminated "{"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := Compile(tt.pattern)
+			require.Error(t, err)
+
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			assert.Equal(t, tt.pattern, syntaxErr.Pattern)
+			assert.Equal(t, tt.wantOffset, syntaxErr.Offset)
+			assert.Contains(t, syntaxErr.Msg, tt.wantMsg)
+			assert.Contains(t, err.Error(), "at offset")
+		})
+	}
+}
+
+func TestHasMeta(t *testing.T) {
+	assert.False(t, HasMeta("lib/main.dart"))
+	assert.False(t, HasMeta("main.go"))
+	assert.False(t, HasMeta(`lib\main.dart`))
+	assert.True(t, HasMeta("lib/*.dart"))
+	assert.True(t, HasMeta("**/main.go"))
+	assert.True(t, HasMeta("main.{go,ts}"))
+	assert.True(t, HasMeta("file[1].go"))
+	assert.True(t, HasMeta("!vendor/a.go"))
+	assert.True(t, HasMeta("(?i)readme.md"))
+}
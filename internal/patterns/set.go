@@ -0,0 +1,39 @@
+package patterns
+
+// Set is an ordered list of patterns matched together.
+//
+// Precedence is last-wins, as in .gitignore: every pattern that matches a path
+// overrides the verdict of the patterns before it, so ["src/**", "!src/gen/**"]
+// selects src but not src/gen, and swapping the two selects all of src. Specificity
+// plays no part. A set whose first pattern is negated starts from "everything
+// matches", so ["!vendor/**"] alone selects every path outside vendor. An empty set
+// matches nothing.
+type Set []Pattern
+
+// CompileSet compiles patterns in order, stopping at the first malformed one.
+func CompileSet(patterns []string) (Set, error) {
+	set := make(Set, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled, err := Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, compiled)
+	}
+	return set, nil
+}
+
+// Match reports whether relPath is selected by the set.
+func (s Set) Match(relPath string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	matched := s[0].negated
+	for _, pattern := range s {
+		if pattern.Match(relPath) {
+			matched = !pattern.negated
+		}
+	}
+	return matched
+}
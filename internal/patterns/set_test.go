@@ -0,0 +1,47 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_LastMatchWins(t *testing.T) {
+	set, err := CompileSet([]string{"src/**", "!src/gen/**", "src/gen/keep.go"})
+	require.NoError(t, err)
+
+	assert.True(t, set.Match("src/app/a.go"))
+	assert.False(t, set.Match("src/gen/a.go"))
+	assert.True(t, set.Match("src/gen/keep.go"))
+	assert.False(t, set.Match("docs/a.md"))
+}
+
+func TestSet_OrderDecidesNotSpecificity(t *testing.T) {
+	set, err := CompileSet([]string{"!src/gen/**", "src/**"})
+	require.NoError(t, err)
+
+	// The broader pattern comes last, so it overrides the more specific negation.
+	assert.True(t, set.Match("src/gen/a.go"))
+}
+
+func TestSet_LeadingNegationStartsFromEverything(t *testing.T) {
+	set, err := CompileSet([]string{"!vendor/**"})
+	require.NoError(t, err)
+
+	assert.True(t, set.Match("src/a.go"))
+	assert.False(t, set.Match("vendor/lib/a.go"))
+}
+
+func TestSet_EmptyMatchesNothing(t *testing.T) {
+	var set Set
+
+	assert.False(t, set.Match("a.go"))
+}
+
+func TestCompileSet_ReportsFirstError(t *testing.T) {
+	_, err := CompileSet([]string{"*.go", "src/[", "a}"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"src/["`)
+}
@@ -0,0 +1,332 @@
+// Package projectconfig reads the .clarity.yml file at the root of a repository,
+// which sets default flag values so a team does not retype them on every run.
+//
+// Top-level keys apply to every command that has the matching flag; a section named
+// after a command (budget, files, lint, neighbors, show, stats, why) overrides them for that command:
+//
+//	exclude: [generated/, vendor/]
+//	exclude-ext: .pb.go
+//	show:
+//	  format: mermaid
+//	  cluster: dir
+//	stats:
+//	  format: json
+//
+// The go section maps Go import path prefixes to directories, relative to the file,
+// for trees without go.mod files; it sets --go-module:
+//
+//	go:
+//	  modules:
+//	    github.com/acme/monorepo: .
+//
+// Flags given on the command line always win over the file. The rules of the lint
+// section and the limits of the budget section are not flags; they are read by the
+// rules package.
+package projectconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the config file names looked up at the repository root, in order.
+var FileNames = []string{".clarity.yml", ".clarity.yaml"}
+
+// keyKind is the YAML shape a key accepts.
+type keyKind int
+
+const (
+	kindString keyKind = iota
+	kindBool
+	kindList
+)
+
+// keys are the accepted keys, each named after the flag it sets.
+var keys = map[string]keyKind{
+	"format":       kindString,
+	"exclude":      kindList,
+	"include-glob": kindList,
+	"include-ext":  kindList,
+	"exclude-ext":  kindList,
+	"label":        kindBool,
+	"url":          kindBool,
+	"cluster":      kindString,
+	"go-module":    kindList,
+}
+
+// sectionOnlyKeys may only appear in a command section: their values differ between
+// commands, e.g. show draws mermaid while stats prints json.
+var sectionOnlyKeys = map[string]bool{"format": true}
+
+// commands are the commands that may have a section of their own.
+var commands = []string{"budget", "files", "lint", "neighbors", "show", "stats", "why"}
+
+// goSection is the top-level key holding Go settings, whose modules map sets
+// --go-module.
+const goSection = "go"
+
+// goModuleKey is the key, and flag, that the modules of the go section set.
+const goModuleKey = "go-module"
+
+// structuredKeys are section keys that hold structured data read elsewhere instead
+// of flag values, such as the rules of lint.
+var structuredKeys = map[string]map[string]bool{
+	"budget": {"limits": true},
+	"lint":   {"rules": true},
+}
+
+// Config is a parsed config file.
+type Config struct {
+	// Path is the file the config was read from; empty when parsed from data.
+	Path string
+
+	defaults settings
+	sections map[string]settings
+}
+
+// settings maps a key to its value. Lists keep their items so that values
+// containing commas survive.
+type settings map[string][]string
+
+// Find returns the path of the config file in dir, or "" when there is none.
+func Find(dir string) (string, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read config file: %w", err)
+		}
+		if !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// Load reads and validates the config file at filePath.
+func Load(filePath string) (Config, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	config, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", filePath, err)
+	}
+	config.Path = filePath
+	config.resolveGoModuleDirs(filepath.Dir(filePath))
+	return config, nil
+}
+
+// Parse parses and validates config from YAML data. Unknown keys and values of the
+// wrong type are errors that name the offending key.
+func Parse(data []byte) (Config, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return Config{}, err
+	}
+	config := Config{defaults: settings{}, sections: map[string]settings{}}
+	if len(root.Content) == 0 {
+		return config, nil
+	}
+
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return Config{}, fmt.Errorf("line %d: expected a mapping of keys to values", doc.Line)
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		keyNode, valueNode := doc.Content[i], doc.Content[i+1]
+		name := keyNode.Value
+		if isCommand(name) {
+			section, err := parseSection(name, valueNode)
+			if err != nil {
+				return Config{}, err
+			}
+			config.sections[name] = section
+			continue
+		}
+		if name == goSection {
+			modules, err := parseGoSection(valueNode)
+			if err != nil {
+				return Config{}, err
+			}
+			config.defaults[goModuleKey] = modules
+			continue
+		}
+		if sectionOnlyKeys[name] {
+			return Config{}, fmt.Errorf("line %d: %s must be set in a command section (%s)", keyNode.Line, name, strings.Join(commands, ", "))
+		}
+		if err := parseKey(config.defaults, name, keyNode, valueNode); err != nil {
+			return Config{}, err
+		}
+	}
+	return config, nil
+}
+
+// parseGoSection returns the modules map of the go section as "prefix=dir" values of
+// --go-module, sorted by prefix.
+func parseGoSection(node *yaml.Node) ([]string, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %s must be a mapping of keys to values", node.Line, goSection)
+	}
+	var modules []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Value != "modules" {
+			return nil, fmt.Errorf("line %d: unknown key %q (valid keys: %s.modules)", keyNode.Line, goSection+"."+keyNode.Value, goSection)
+		}
+		var mapping map[string]string
+		if valueNode.Kind != yaml.MappingNode || valueNode.Decode(&mapping) != nil {
+			return nil, fmt.Errorf("line %d: %s.modules must map import path prefixes to directories", valueNode.Line, goSection)
+		}
+		prefixes := make([]string, 0, len(mapping))
+		for prefix := range mapping {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			modules = append(modules, prefix+"="+mapping[prefix])
+		}
+	}
+	return modules, nil
+}
+
+// resolveGoModuleDirs makes the relative directories of --go-module values set by
+// the file relative to dir, the directory holding it, instead of to --repo.
+func (c Config) resolveGoModuleDirs(dir string) {
+	resolve := func(values settings) {
+		for i, value := range values[goModuleKey] {
+			prefix, moduleDir, ok := strings.Cut(value, "=")
+			if ok && !filepath.IsAbs(moduleDir) {
+				values[goModuleKey][i] = prefix + "=" + filepath.Join(dir, moduleDir)
+			}
+		}
+	}
+	resolve(c.defaults)
+	for _, section := range c.sections {
+		resolve(section)
+	}
+}
+
+func parseSection(command string, node *yaml.Node) (settings, error) {
+	section := settings{}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %s must be a mapping of keys to values", node.Line, command)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if structuredKeys[command][keyNode.Value] {
+			continue
+		}
+		if err := parseKey(section, command+"."+keyNode.Value, keyNode, valueNode); err != nil {
+			return nil, err
+		}
+	}
+	return section, nil
+}
+
+// parseKey validates one key and stores its value in into. qualified is the key as
+// the user wrote it, for error messages.
+func parseKey(into settings, qualified string, keyNode, valueNode *yaml.Node) error {
+	name := keyNode.Value
+	kind, ok := keys[name]
+	if !ok {
+		return fmt.Errorf("line %d: unknown key %q (valid keys: %s)", keyNode.Line, qualified, SupportedKeys())
+	}
+
+	switch kind {
+	case kindBool:
+		var value bool
+		if err := valueNode.Decode(&value); err != nil {
+			return fmt.Errorf("line %d: %s must be true or false", valueNode.Line, qualified)
+		}
+		into[name] = []string{strconv.FormatBool(value)}
+	case kindString:
+		var value string
+		if valueNode.Kind != yaml.ScalarNode || valueNode.Decode(&value) != nil {
+			return fmt.Errorf("line %d: %s must be a string", valueNode.Line, qualified)
+		}
+		into[name] = []string{value}
+	case kindList:
+		var values []string
+		if valueNode.Kind == yaml.ScalarNode {
+			values = []string{valueNode.Value}
+		} else if err := valueNode.Decode(&values); err != nil {
+			return fmt.Errorf("line %d: %s must be a string or a list of strings", valueNode.Line, qualified)
+		}
+		into[name] = values
+	}
+	return nil
+}
+
+// SupportedKeys returns the accepted keys, sorted.
+func SupportedKeys() string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func isCommand(name string) bool {
+	for _, command := range commands {
+		if command == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply sets the flags of command that the config covers and that were not given on
+// the command line, and returns the names of the flags it set. Keys for flags the
+// command does not define are ignored. Flags set from the config are not marked as
+// changed, since they are defaults.
+func (c Config) Apply(command string, flags *pflag.FlagSet) ([]string, error) {
+	merged := settings{}
+	for name, values := range c.defaults {
+		merged[name] = values
+	}
+	for name, values := range c.sections[command] {
+		merged[name] = values
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var applied []string
+	for _, name := range names {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := setFlag(flag, merged[name]); err != nil {
+			return nil, fmt.Errorf("invalid %s in config file %s: %w", name, c.Path, err)
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
+
+func setFlag(flag *pflag.Flag, values []string) error {
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		return slice.Replace(values)
+	}
+	return flag.Value.Set(strings.Join(values, ","))
+}
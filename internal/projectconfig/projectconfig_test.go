@@ -0,0 +1,266 @@
+package projectconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFlags struct {
+	set     *pflag.FlagSet
+	format  string
+	exclude []string
+	ext     string
+	label   bool
+}
+
+func newTestFlags(t *testing.T, args ...string) *testFlags {
+	t.Helper()
+
+	flags := &testFlags{set: pflag.NewFlagSet("test", pflag.ContinueOnError)}
+	flags.set.StringVarP(&flags.format, "format", "f", "dot", "")
+	flags.set.StringSliceVar(&flags.exclude, "exclude", nil, "")
+	flags.set.StringVar(&flags.ext, "include-ext", "", "")
+	flags.set.BoolVar(&flags.label, "label", false, "")
+	require.NoError(t, flags.set.Parse(args))
+	return flags
+}
+
+func TestApply_SetsFlagsNotGivenOnCommandLine(t *testing.T) {
+	config, err := Parse([]byte(`
+exclude: [generated/, "a,b.go"]
+include-ext: [.go, .java]
+show:
+  format: mermaid
+  label: true
+`))
+	require.NoError(t, err)
+	flags := newTestFlags(t)
+
+	applied, err := config.Apply("show", flags.set)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"exclude", "format", "include-ext", "label"}, applied)
+	assert.Equal(t, "mermaid", flags.format)
+	assert.Equal(t, []string{"generated/", "a,b.go"}, flags.exclude)
+	assert.Equal(t, ".go,.java", flags.ext)
+	assert.True(t, flags.label)
+	assert.False(t, flags.set.Changed("format"), "config values are defaults, not command-line flags")
+}
+
+func TestApply_CommandLineFlagsWin(t *testing.T) {
+	config, err := Parse([]byte(`
+exclude: generated/
+show:
+  format: mermaid
+`))
+	require.NoError(t, err)
+	flags := newTestFlags(t, "--format", "json", "--exclude", "vendor/")
+
+	applied, err := config.Apply("show", flags.set)
+
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Equal(t, "json", flags.format)
+	assert.Equal(t, []string{"vendor/"}, flags.exclude)
+}
+
+func TestApply_SectionOverridesTopLevel(t *testing.T) {
+	config, err := Parse([]byte(`
+label: false
+show:
+  label: true
+`))
+	require.NoError(t, err)
+
+	showFlags := newTestFlags(t)
+	_, err = config.Apply("show", showFlags.set)
+	require.NoError(t, err)
+	whyFlags := newTestFlags(t)
+	_, err = config.Apply("why", whyFlags.set)
+	require.NoError(t, err)
+
+	assert.True(t, showFlags.label)
+	assert.False(t, whyFlags.label)
+}
+
+func TestApply_OtherCommandSectionIsIgnored(t *testing.T) {
+	config, err := Parse([]byte(`
+stats:
+  format: json
+`))
+	require.NoError(t, err)
+	flags := newTestFlags(t)
+
+	applied, err := config.Apply("show", flags.set)
+
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Equal(t, "dot", flags.format)
+}
+
+func TestApply_LintRulesAreNotFlags(t *testing.T) {
+	config, err := Parse([]byte(`
+lint:
+  format: json
+  rules:
+    - name: acyclic
+      no-cycles: true
+`))
+	require.NoError(t, err)
+	flags := newTestFlags(t)
+
+	applied, err := config.Apply("lint", flags.set)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"format"}, applied)
+	assert.Equal(t, "json", flags.format)
+}
+
+func TestApply_BudgetLimitsAreNotFlags(t *testing.T) {
+	config, err := Parse([]byte(`
+budget:
+  format: json
+  limits:
+    - path: "**"
+      max-loc: 800
+`))
+	require.NoError(t, err)
+	flags := newTestFlags(t)
+
+	applied, err := config.Apply("budget", flags.set)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"format"}, applied)
+	assert.Equal(t, "json", flags.format)
+}
+
+func TestApply_SkipsKeysTheCommandDoesNotDefine(t *testing.T) {
+	config, err := Parse([]byte("url: true\n"))
+	require.NoError(t, err)
+	flags := newTestFlags(t)
+
+	applied, err := config.Apply("why", flags.set)
+
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestParse_UnknownKeyIsNamed(t *testing.T) {
+	_, err := Parse([]byte(`
+exclude: generated/
+excludes: vendor/
+`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 3: unknown key "excludes"`)
+	assert.Contains(t, err.Error(), "valid keys: cluster, exclude, exclude-ext")
+}
+
+func TestParse_UnknownKeyInSectionIsQualified(t *testing.T) {
+	_, err := Parse([]byte(`
+show:
+  colour: red
+`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown key "show.colour"`)
+}
+
+func TestParse_WrongTypeIsNamed(t *testing.T) {
+	_, err := Parse([]byte(`
+show:
+  label: sometimes
+`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3: show.label must be true or false")
+}
+
+func TestParse_FormatMustBeInSection(t *testing.T) {
+	_, err := Parse([]byte("format: mermaid\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "format must be set in a command section")
+}
+
+func TestParse_EmptyFile(t *testing.T) {
+	config, err := Parse(nil)
+	require.NoError(t, err)
+
+	applied, err := config.Apply("show", newTestFlags(t).set)
+
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestLoad_ErrorNamesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".clarity.yml")
+	require.NoError(t, os.WriteFile(path, []byte("bogus: 1\n"), 0o644))
+
+	_, err := Load(path)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid config file "+path)
+	assert.Contains(t, err.Error(), `unknown key "bogus"`)
+}
+
+func TestLoad_GoModulesSetGoModuleRelativeToFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".clarity.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+go:
+  modules:
+    github.com/acme/monorepo: .
+    github.com/acme/monorepo/third_party/lib: vendor/lib
+    example.com/abs: /opt/abs
+`), 0o644))
+	config, err := Load(path)
+	require.NoError(t, err)
+
+	var modules []string
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringArrayVar(&modules, "go-module", nil, "")
+	applied, err := config.Apply("show", flags)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go-module"}, applied)
+	assert.Equal(t, []string{
+		"example.com/abs=/opt/abs",
+		"github.com/acme/monorepo=" + dir,
+		"github.com/acme/monorepo/third_party/lib=" + filepath.Join(dir, "vendor", "lib"),
+	}, modules)
+}
+
+func TestParse_GoSectionIsValidated(t *testing.T) {
+	_, err := Parse([]byte("go:\n  module: x\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown key "go.module"`)
+
+	_, err = Parse([]byte("go:\n  modules: [a, b]\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "go.modules must map import path prefixes to directories")
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := Find(dir)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".clarity.yaml"), nil, 0o644))
+	path, err = Find(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".clarity.yaml"), path)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".clarity.yml"), nil, 0o644))
+	path, err = Find(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".clarity.yml"), path)
+}
@@ -0,0 +1,58 @@
+package testhelpers
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// MapContentReader returns a vcs.ContentReader serving files from contents, keyed by
+// absolute path. Directories exist implicitly as ancestors of the supplied files.
+func MapContentReader(contents map[string]string) vcs.ContentReader {
+	return mapContentReader(contents)
+}
+
+type mapContentReader map[string]string
+
+func (m mapContentReader) ReadFile(filePath string) ([]byte, error) {
+	content, ok := m[filePath]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", filePath, fs.ErrNotExist)
+	}
+	return []byte(content), nil
+}
+
+func (m mapContentReader) Exists(filePath string) bool {
+	if _, ok := m[filePath]; ok {
+		return true
+	}
+	_, err := m.ListDir(filePath)
+	return err == nil
+}
+
+func (m mapContentReader) ListDir(dirPath string) ([]string, error) {
+	prefix := strings.TrimSuffix(dirPath, string(filepath.Separator)) + string(filepath.Separator)
+	seen := make(map[string]bool)
+	for filePath := range m {
+		rest, ok := strings.CutPrefix(filePath, prefix)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(rest, string(filepath.Separator))
+		seen[name] = true
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("%s: %w", dirPath, fs.ErrNotExist)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
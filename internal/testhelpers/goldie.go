@@ -22,6 +22,10 @@ func JSONGoldie(t *testing.T) *goldie.Goldie {
 	return goldieWithExtension(t, "json")
 }
 
+func HTMLGoldie(t *testing.T) *goldie.Goldie {
+	return goldieWithExtension(t, "html")
+}
+
 // goldieWithExtension creates a Goldie instance with a golden file suffix.
 func goldieWithExtension(t *testing.T, suffix string) *goldie.Goldie {
 	t.Helper()
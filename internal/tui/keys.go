@@ -0,0 +1,95 @@
+package tui
+
+import "unicode/utf8"
+
+// KeyType is the kind of a key press.
+type KeyType int
+
+const (
+	// KeyRune is a printable character, held in Key.Rune.
+	KeyRune KeyType = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyCtrlC
+)
+
+// Key is a key press.
+type Key struct {
+	Type KeyType
+	Rune rune
+}
+
+// RuneKey returns the key press of the character r.
+func RuneKey(r rune) Key {
+	return Key{Type: KeyRune, Rune: r}
+}
+
+// DecodeKeys returns the key presses in input read from a terminal in raw mode.
+// Escape sequences other than the arrow keys are dropped.
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		b := input[0]
+		switch {
+		case b == 0x1b:
+			key, n := decodeEscape(input)
+			if n == 0 {
+				return keys
+			}
+			if key != nil {
+				keys = append(keys, *key)
+			}
+			input = input[n:]
+			continue
+		case b == '\r' || b == '\n':
+			keys = append(keys, Key{Type: KeyEnter})
+		case b == '\t':
+			keys = append(keys, Key{Type: KeyTab})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, Key{Type: KeyBackspace})
+		case b == 0x03:
+			keys = append(keys, Key{Type: KeyCtrlC})
+		case b < 0x20:
+			// Other control characters have no binding.
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, RuneKey(r))
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// decodeEscape decodes the escape sequence at the start of input and returns its key,
+// nil for an unbound sequence, and its length. A lone ESC is the escape key.
+func decodeEscape(input []byte) (*Key, int) {
+	if len(input) == 1 || (input[1] != '[' && input[1] != 'O') {
+		return &Key{Type: KeyEscape}, 1
+	}
+	// A CSI or SS3 sequence ends at its first byte in @ through ~.
+	for i := 2; i < len(input); i++ {
+		if input[i] < 0x40 || input[i] > 0x7e {
+			continue
+		}
+		switch input[i] {
+		case 'A':
+			return &Key{Type: KeyUp}, i + 1
+		case 'B':
+			return &Key{Type: KeyDown}, i + 1
+		case 'C':
+			return &Key{Type: KeyRight}, i + 1
+		case 'D':
+			return &Key{Type: KeyLeft}, i + 1
+		}
+		return nil, i + 1
+	}
+	return nil, len(input)
+}
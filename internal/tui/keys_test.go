@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Key
+	}{
+		{"arrows", "\x1b[A\x1b[B\x1b[C\x1b[D", []Key{{Type: KeyUp}, {Type: KeyDown}, {Type: KeyRight}, {Type: KeyLeft}}},
+		{"application mode arrows", "\x1bOA", []Key{{Type: KeyUp}}},
+		{"lone escape", "\x1b", []Key{{Type: KeyEscape}}},
+		{"unbound sequence", "\x1b[3~x", []Key{RuneKey('x')}},
+		{"controls", "\r\t\x7f\x03", []Key{{Type: KeyEnter}, {Type: KeyTab}, {Type: KeyBackspace}, {Type: KeyCtrlC}}},
+		{"runes", "/é", []Key{RuneKey('/'), RuneKey('é')}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DecodeKeys([]byte(tt.input)))
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Package tui is the interactive terminal view of a dependency graph behind
+// clarity tui. Model holds the whole state and changes only through Update, so the
+// view can be driven by keys in tests the same way Run drives it from a terminal.
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+// Panes of the view that hold a list a selection moves through.
+const (
+	paneDependencies = iota
+	paneDependents
+)
+
+// maxSearchResults bounds how many matches the search lists.
+const maxSearchResults = 50
+
+// Graph is the dependency graph a Model explores.
+type Graph struct {
+	// Graph holds the files and their dependencies.
+	Graph depgraph.DependencyGraph
+	// Names maps each file to the name shown for it, such as its repo-relative path.
+	// Files without a name are shown by their path.
+	Names map[string]string
+	// Tests holds the test files, which "t" hides.
+	Tests map[string]bool
+}
+
+// Model is the state of the view: the focused file, the selection in its
+// dependency and dependent lists, and the search and why results on top of them.
+type Model struct {
+	files        []string
+	names        map[string]string
+	tests        map[string]bool
+	dependencies map[string][]string
+	dependents   map[string][]string
+	graph        depgraph.DependencyGraph
+
+	focus     string
+	previous  string
+	pane      int
+	cursor    [2]int
+	hideTests bool
+
+	searching    bool
+	query        string
+	matches      []string
+	searchCursor int
+
+	why []string
+}
+
+// New returns a model of graph focused on focus, or on the file with the most
+// dependencies and dependents when focus is empty. focus must be a file of graph.
+func New(graph Graph, focus string) (Model, error) {
+	adjacency, err := depgraph.AdjacencyList(graph.Graph)
+	if err != nil {
+		return Model{}, err
+	}
+
+	m := Model{
+		names:        make(map[string]string, len(adjacency)),
+		tests:        graph.Tests,
+		dependencies: make(map[string][]string, len(adjacency)),
+		dependents:   make(map[string][]string, len(adjacency)),
+		graph:        graph.Graph,
+	}
+	for file, deps := range adjacency {
+		m.files = append(m.files, file)
+		m.names[file] = file
+		if name, ok := graph.Names[file]; ok {
+			m.names[file] = name
+		}
+		m.dependencies[file] = append(m.dependencies[file], deps...)
+		for _, dep := range deps {
+			m.dependents[dep] = append(m.dependents[dep], file)
+		}
+	}
+	m.sortByName(m.files)
+	for _, file := range m.files {
+		m.sortByName(m.dependencies[file])
+		m.sortByName(m.dependents[file])
+	}
+
+	if focus == "" {
+		focus = m.busiestFile()
+	} else if _, ok := adjacency[focus]; !ok {
+		return Model{}, fmt.Errorf("%s is not in the graph", focus)
+	}
+	m.focus = focus
+	return m, nil
+}
+
+// Focus returns the focused file.
+func (m Model) Focus() string {
+	return m.focus
+}
+
+// busiestFile returns the file with the most dependencies and dependents, the first
+// by name among equals, or "" for an empty graph.
+func (m Model) busiestFile() string {
+	busiest, most := "", -1
+	for _, file := range m.files {
+		if degree := len(m.dependencies[file]) + len(m.dependents[file]); degree > most {
+			busiest, most = file, degree
+		}
+	}
+	return busiest
+}
+
+func (m Model) sortByName(files []string) {
+	sort.Slice(files, func(i, j int) bool {
+		if m.names[files[i]] != m.names[files[j]] {
+			return m.names[files[i]] < m.names[files[j]]
+		}
+		return files[i] < files[j]
+	})
+}
+
+// Update returns the model after key and whether the view should close.
+func (m Model) Update(key Key) (Model, bool) {
+	if key.Type == KeyCtrlC {
+		return m, true
+	}
+	if m.searching {
+		return m.updateSearch(key), false
+	}
+
+	switch key.Type {
+	case KeyUp:
+		m.moveCursor(-1)
+	case KeyDown:
+		m.moveCursor(1)
+	case KeyLeft:
+		m.pane = paneDependencies
+	case KeyRight:
+		m.pane = paneDependents
+	case KeyTab:
+		m.pane = 1 - m.pane
+	case KeyEnter:
+		if selected := m.Selected(); selected != "" {
+			m.refocus(selected)
+		}
+	case KeyBackspace:
+		if m.previous != "" {
+			m.refocus(m.previous)
+		}
+	case KeyEscape:
+		m.why = nil
+	case KeyRune:
+		switch key.Rune {
+		case 'q':
+			return m, true
+		case '/':
+			m.searching, m.query, m.searchCursor = true, "", 0
+			m.matches = m.search("")
+		case 't':
+			m.hideTests = !m.hideTests
+			m.clampCursors()
+		case 'w':
+			m.why = m.explain()
+		case 'k':
+			m.moveCursor(-1)
+		case 'j':
+			m.moveCursor(1)
+		case 'h':
+			m.pane = paneDependencies
+		case 'l':
+			m.pane = paneDependents
+		}
+	}
+	return m, false
+}
+
+func (m Model) updateSearch(key Key) Model {
+	switch key.Type {
+	case KeyEscape:
+		m.searching = false
+	case KeyEnter:
+		if m.searchCursor < len(m.matches) {
+			m.refocus(m.matches[m.searchCursor])
+		}
+		m.searching = false
+	case KeyUp:
+		m.searchCursor = max(0, m.searchCursor-1)
+	case KeyDown:
+		m.searchCursor = min(max(0, len(m.matches)-1), m.searchCursor+1)
+	case KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+			m.matches, m.searchCursor = m.search(m.query), 0
+		}
+	case KeyRune:
+		m.query += string(key.Rune)
+		m.matches, m.searchCursor = m.search(m.query), 0
+	}
+	return m
+}
+
+// refocus focuses file, remembering the focused file as the previous one.
+func (m *Model) refocus(file string) {
+	if file == m.focus {
+		return
+	}
+	m.previous, m.focus = m.focus, file
+	m.cursor = [2]int{}
+	m.why = nil
+}
+
+func (m *Model) moveCursor(delta int) {
+	count := len(m.list(m.pane))
+	m.cursor[m.pane] = min(max(0, m.cursor[m.pane]+delta), max(0, count-1))
+}
+
+func (m *Model) clampCursors() {
+	for pane := range m.cursor {
+		m.cursor[pane] = min(m.cursor[pane], max(0, len(m.list(pane))-1))
+	}
+}
+
+// list returns the visible files of pane for the focused file.
+func (m Model) list(pane int) []string {
+	files := m.dependencies[m.focus]
+	if pane == paneDependents {
+		files = m.dependents[m.focus]
+	}
+	return m.visible(files)
+}
+
+func (m Model) visible(files []string) []string {
+	if !m.hideTests {
+		return files
+	}
+	kept := make([]string, 0, len(files))
+	for _, file := range files {
+		if !m.tests[file] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// Selected returns the selected file of the active pane, or "" when it is empty.
+func (m Model) Selected() string {
+	files := m.list(m.pane)
+	if len(files) == 0 {
+		return ""
+	}
+	return files[m.cursor[m.pane]]
+}
+
+// search returns the visible files whose names hold the runes of query in order,
+// best matches first: fewer gaps between the matched runes, then shorter names.
+func (m Model) search(query string) []string {
+	type match struct {
+		file string
+		gaps int
+	}
+	var found []match
+	for _, file := range m.visible(m.files) {
+		if gaps, ok := fuzzyMatch(strings.ToLower(m.names[file]), strings.ToLower(query)); ok {
+			found = append(found, match{file, gaps})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].gaps != found[j].gaps {
+			return found[i].gaps < found[j].gaps
+		}
+		return len(m.names[found[i].file]) < len(m.names[found[j].file])
+	})
+
+	matches := make([]string, 0, min(len(found), maxSearchResults))
+	for _, f := range found[:min(len(found), maxSearchResults)] {
+		matches = append(matches, f.file)
+	}
+	return matches
+}
+
+// fuzzyMatch reports whether the runes of query appear in name in order, and the
+// number of runes of name skipped between the first and last of them.
+func fuzzyMatch(name, query string) (int, bool) {
+	queryRunes := []rune(query)
+	if len(queryRunes) == 0 {
+		return 0, true
+	}
+	gaps, next, started := 0, 0, false
+	for _, r := range name {
+		if r == queryRunes[next] {
+			started = true
+			next++
+			if next == len(queryRunes) {
+				return gaps, true
+			}
+		} else if started {
+			gaps++
+		}
+	}
+	return 0, false
+}
+
+// explain returns the lines of the why result for the previous focus, or the focus
+// when there is none, and the selected file: the shortest dependency path in each
+// direction between them.
+func (m Model) explain() []string {
+	selected := m.Selected()
+	if selected == "" {
+		return []string{"why: select a file first"}
+	}
+	from := m.previous
+	if from == "" {
+		from = m.focus
+	}
+	if from == selected {
+		return []string{"why: " + m.names[from] + " is the selected file"}
+	}
+
+	lines := []string{fmt.Sprintf("why %s %s", m.names[from], m.names[selected])}
+	for _, pair := range [][2]string{{from, selected}, {selected, from}} {
+		path, err := depgraph.ShortestPath(m.graph, pair[0], pair[1])
+		if err != nil {
+			return []string{"why: " + err.Error()}
+		}
+		if len(path) == 0 {
+			lines = append(lines, fmt.Sprintf("  %s does not depend on %s", m.names[pair[0]], m.names[pair[1]]))
+			continue
+		}
+		names := make([]string, len(path))
+		for i, file := range path {
+			names[i] = m.names[file]
+		}
+		lines = append(lines, "  "+strings.Join(names, " -> "))
+	}
+	return lines
+}
@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/depgraph"
+)
+
+// testGraph is a graph where main.go imports app.go, app.go imports util.go and
+// log.go, util.go imports log.go, and app_test.go imports app.go.
+func testGraph() Graph {
+	return Graph{
+		Graph: depgraph.MustDependencyGraph(map[string][]string{
+			"/repo/main.go":     {"/repo/app.go"},
+			"/repo/app.go":      {"/repo/util.go", "/repo/log.go"},
+			"/repo/app_test.go": {"/repo/app.go"},
+			"/repo/util.go":     {"/repo/log.go"},
+			"/repo/log.go":      {},
+		}),
+		Names: map[string]string{
+			"/repo/main.go":     "main.go",
+			"/repo/app.go":      "app.go",
+			"/repo/app_test.go": "app_test.go",
+			"/repo/util.go":     "util.go",
+			"/repo/log.go":      "log.go",
+		},
+		Tests: map[string]bool{"/repo/app_test.go": true},
+	}
+}
+
+func newTestModel(t *testing.T, focus string) Model {
+	t.Helper()
+	m, err := New(testGraph(), focus)
+	require.NoError(t, err)
+	return m
+}
+
+// press applies keys to m in order, failing the test if one closes the view.
+func press(t *testing.T, m Model, keys ...Key) Model {
+	t.Helper()
+	for _, key := range keys {
+		var quit bool
+		m, quit = m.Update(key)
+		require.False(t, quit, "key %+v closed the view", key)
+	}
+	return m
+}
+
+func typed(text string) []Key {
+	var keys []Key
+	for _, r := range text {
+		keys = append(keys, RuneKey(r))
+	}
+	return keys
+}
+
+func TestNew_FocusesTheFileWithTheMostDependenciesAndDependents(t *testing.T) {
+	m := newTestModel(t, "")
+
+	assert.Equal(t, "/repo/app.go", m.Focus())
+	assert.Equal(t, "/repo/log.go", m.Selected())
+}
+
+func TestNew_RejectsFocusOutsideTheGraph(t *testing.T) {
+	_, err := New(testGraph(), "/repo/missing.go")
+
+	require.EqualError(t, err, "/repo/missing.go is not in the graph")
+}
+
+func TestUpdate_EnterFocusesTheSelectionAndBackspaceReturns(t *testing.T) {
+	m := newTestModel(t, "/repo/app.go")
+
+	m = press(t, m, Key{Type: KeyDown}, Key{Type: KeyEnter})
+	assert.Equal(t, "/repo/util.go", m.Focus())
+
+	m = press(t, m, Key{Type: KeyRight}, Key{Type: KeyDown})
+	assert.Equal(t, "/repo/app.go", m.Selected(), "the cursor stops at the last dependent")
+
+	m = press(t, m, Key{Type: KeyBackspace})
+	assert.Equal(t, "/repo/app.go", m.Focus())
+}
+
+func TestUpdate_TogglesTestFiles(t *testing.T) {
+	m := newTestModel(t, "/repo/app.go")
+	m = press(t, m, Key{Type: KeyRight})
+	assert.Equal(t, "/repo/app_test.go", m.Selected())
+
+	m = press(t, m, RuneKey('t'))
+	assert.Equal(t, "/repo/main.go", m.Selected())
+
+	m = press(t, m, RuneKey('t'))
+	assert.Equal(t, "/repo/app_test.go", m.Selected())
+}
+
+func TestUpdate_SearchFocusesTheBestMatch(t *testing.T) {
+	m := newTestModel(t, "/repo/app.go")
+
+	m = press(t, m, append([]Key{RuneKey('/')}, typed("ut")...)...)
+	assert.Equal(t, []string{"/repo/util.go"}, m.matches)
+
+	m = press(t, m, Key{Type: KeyEnter})
+	assert.Equal(t, "/repo/util.go", m.Focus())
+	assert.False(t, m.searching)
+}
+
+func TestUpdate_SearchRanksFewerGapsThenShorterNamesFirst(t *testing.T) {
+	m := newTestModel(t, "/repo/app.go")
+
+	m = press(t, m, append([]Key{RuneKey('/')}, typed("ag")...)...)
+
+	assert.Equal(t, []string{"/repo/app.go", "/repo/main.go", "/repo/app_test.go"}, m.matches)
+}
+
+func TestUpdate_EscapeCancelsSearch(t *testing.T) {
+	m := newTestModel(t, "/repo/app.go")
+
+	m = press(t, m, RuneKey('/'), RuneKey('m'), Key{Type: KeyEscape})
+
+	assert.False(t, m.searching)
+	assert.Equal(t, "/repo/app.go", m.Focus())
+}
+
+func TestUpdate_WhyExplainsPreviousFocusAndSelection(t *testing.T) {
+	m := newTestModel(t, "/repo/main.go")
+
+	// Walk from main.go to app.go and select log.go among its imports.
+	m = press(t, m, Key{Type: KeyEnter}, RuneKey('w'))
+
+	assert.Equal(t, []string{
+		"why main.go log.go",
+		"  main.go -> app.go -> log.go",
+		"  log.go does not depend on main.go",
+	}, m.why)
+
+	m = press(t, m, Key{Type: KeyEscape})
+	assert.Nil(t, m.why)
+}
+
+func TestUpdate_QuitKeys(t *testing.T) {
+	m := newTestModel(t, "")
+
+	for _, key := range []Key{RuneKey('q'), {Type: KeyCtrlC}} {
+		_, quit := m.Update(key)
+		assert.True(t, quit, "key %+v", key)
+	}
+
+	// q is a search character, while ctrl-c still quits.
+	m = press(t, m, RuneKey('/'), RuneKey('q'))
+	_, quit := m.Update(Key{Type: KeyCtrlC})
+	assert.True(t, quit)
+}
+
+func TestView_ShowsTheThreePanes(t *testing.T) {
+	m := newTestModel(t, "/repo/util.go")
+	m = press(t, m, Key{Type: KeyRight})
+
+	assert.Equal(t, `clarity tui
+Focused            │ Imports (1)        │ [Imported by (1)]
+util.go            │   log.go           │ > app.go
+imports 1          │                    │
+imported by 1      │                    │
+↑/↓ select  ←/→ pane  enter focus  backspace back  / search  t tests  w why  q quit`, m.View(60, 6))
+}
+
+func TestView_ShowsSearchResults(t *testing.T) {
+	m := newTestModel(t, "/repo/util.go")
+	m = press(t, m, append([]Key{RuneKey('/')}, typed("app")...)...)
+
+	assert.Equal(t, `clarity tui
+/app
+> app.go
+  app_test.go
+type to search  ↑/↓ select  enter focus  esc cancel`, m.View(50, 10))
+}
+
+func TestFit_CutsLongNamesFromTheLeft(t *testing.T) {
+	assert.Equal(t, "…/deep/file.go", fit("src/very/deep/file.go", 14))
+	assert.Equal(t, "short  ", fit("short", 7))
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// defaultWidth and defaultHeight size the view when the terminal size is unknown.
+	defaultWidth  = 80
+	defaultHeight = 24
+
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// ErrNotTerminal is returned by Run when in is not an interactive terminal.
+var ErrNotTerminal = errors.New("not an interactive terminal")
+
+// Run shows m on the alternate screen of the terminal in, writing to out, and
+// updates it with each key press until it closes. The terminal is restored
+// afterwards. The size is read again before each render, so a resized terminal is
+// picked up on the next key press.
+func Run(in *os.File, out io.Writer, m Model) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return ErrNotTerminal
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	fmt.Fprint(out, enterAltScreen)
+	defer fmt.Fprint(out, exitAltScreen)
+
+	buf := make([]byte, 256)
+	for {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = defaultWidth, defaultHeight
+		}
+		// Raw mode turns off the newline translation, so lines need a carriage return.
+		view := strings.ReplaceAll(m.View(width, height), "\n", "\r\n")
+		if _, err := fmt.Fprint(out, clearScreen+view); err != nil {
+			return err
+		}
+
+		n, err := in.Read(buf)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, key := range DecodeKeys(buf[:n]) {
+			var quit bool
+			if m, quit = m.Update(key); quit {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// paneSeparator sits between the three panes.
+	paneSeparator = " │ "
+	// minPaneWidth keeps names readable in narrow terminals; wider lines are cut by
+	// the terminal.
+	minPaneWidth = 12
+	// helpLine lists the keys outside search.
+	helpLine = "↑/↓ select  ←/→ pane  enter focus  backspace back  / search  t tests  w why  q quit"
+	// searchHelpLine lists the keys of the search.
+	searchHelpLine = "type to search  ↑/↓ select  enter focus  esc cancel"
+)
+
+// View renders the model for a terminal of width columns and height rows, as lines
+// separated by "\n".
+func (m Model) View(width, height int) string {
+	var lines []string
+	title := "clarity tui"
+	if m.hideTests {
+		title += "  (tests hidden)"
+	}
+	lines = append(lines, title)
+
+	if m.searching {
+		lines = append(lines, m.searchLines(height-2)...)
+		lines = append(lines, searchHelpLine)
+		return strings.Join(lines, "\n")
+	}
+
+	rows := max(1, height-3-len(m.why))
+	lines = append(lines, m.paneLines(width, rows)...)
+	lines = append(lines, m.why...)
+	lines = append(lines, helpLine)
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) searchLines(rows int) []string {
+	lines := []string{"/" + m.query}
+	if len(m.matches) == 0 {
+		return append(lines, "  (no matching files)")
+	}
+	start := scrollStart(m.searchCursor, rows-1)
+	for i := start; i < len(m.matches) && i < start+rows-1; i++ {
+		lines = append(lines, marker(i == m.searchCursor)+m.names[m.matches[i]])
+	}
+	return lines
+}
+
+// paneLines renders the focused file, its dependencies, and its dependents side by
+// side: a header row and rows list rows.
+func (m Model) paneLines(width, rows int) []string {
+	paneWidth := max(minPaneWidth, (width-2*utf8.RuneCountInString(paneSeparator))/3)
+	if m.focus == "" {
+		return []string{"(no files)"}
+	}
+
+	dependencies, dependents := m.list(paneDependencies), m.list(paneDependents)
+	focused := []string{
+		m.names[m.focus],
+		fmt.Sprintf("imports %d", len(dependencies)),
+		fmt.Sprintf("imported by %d", len(dependents)),
+	}
+	if m.tests[m.focus] {
+		focused = append(focused, "test file")
+	}
+	if m.previous != "" {
+		focused = append(focused, "", "previous:", m.names[m.previous])
+	}
+
+	columns := [3][]string{
+		focused,
+		m.listLines(paneDependencies, dependencies, rows),
+		m.listLines(paneDependents, dependents, rows),
+	}
+	headers := [3]string{
+		"Focused",
+		fmt.Sprintf("Imports (%d)", len(dependencies)),
+		fmt.Sprintf("Imported by (%d)", len(dependents)),
+	}
+	headers[m.pane+1] = "[" + headers[m.pane+1] + "]"
+
+	lines := []string{strings.TrimRight(joinColumns(headers[:], paneWidth), " ")}
+	for row := 0; row < rows; row++ {
+		var cells []string
+		for _, column := range columns {
+			cell := ""
+			if row < len(column) {
+				cell = column[row]
+			}
+			cells = append(cells, cell)
+		}
+		lines = append(lines, strings.TrimRight(joinColumns(cells, paneWidth), " "))
+	}
+	return lines
+}
+
+// listLines renders the files of pane scrolled so the selection is in view.
+func (m Model) listLines(pane int, files []string, rows int) []string {
+	if len(files) == 0 {
+		return []string{"  (none)"}
+	}
+	start := scrollStart(m.cursor[pane], rows)
+	var lines []string
+	for i := start; i < len(files) && i < start+rows; i++ {
+		lines = append(lines, marker(pane == m.pane && i == m.cursor[pane])+m.names[files[i]])
+	}
+	return lines
+}
+
+// scrollStart returns the first of rows visible items that keeps cursor in view.
+func scrollStart(cursor, rows int) int {
+	return max(0, cursor-max(1, rows)+1)
+}
+
+func marker(selected bool) string {
+	if selected {
+		return "> "
+	}
+	return "  "
+}
+
+// joinColumns pads or cuts each cell to width and joins them with paneSeparator.
+func joinColumns(cells []string, width int) string {
+	fitted := make([]string, len(cells))
+	for i, cell := range cells {
+		fitted[i] = fit(cell, width)
+	}
+	return strings.Join(fitted, paneSeparator)
+}
+
+// fit pads s to width runes, or cuts it from the left with "…" so the file name at
+// the end stays visible.
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return "…" + string(runes[len(runes)-width+1:])
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
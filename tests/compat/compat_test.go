@@ -0,0 +1,136 @@
+// Package compat holds the output compatibility corpus: checked-in inputs and the exact
+// output clarity emits for them in every format at the current OutputSchemaVersion.
+// A failure here means a release would break downstream golden files.
+package compat
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LegacyCodeHQ/clarity/cmd/show"
+	"github.com/LegacyCodeHQ/clarity/cmd/show/formatters"
+)
+
+var updateCorpus = flag.Bool("update-corpus", false, "write expected outputs for the current OutputSchemaVersion")
+
+const inputsDir = "testdata/inputs"
+
+// corpusCases are the show invocations the corpus pins, each run on a copy of one
+// fixture under testdata/inputs.
+var corpusCases = []struct {
+	name    string
+	fixture string
+	args    []string
+}{
+	{name: "gocycle", fixture: "gocycle"},
+	{name: "typescript", fixture: "typescript"},
+	{name: "typescript-edge-labels", fixture: "typescript", args: []string{"--label"}},
+	{name: "typescript-top-bottom", fixture: "typescript", args: []string{"--direction", "tb"}},
+	{name: "python", fixture: "python"},
+}
+
+func TestCompatibilityCorpus(t *testing.T) {
+	versionDir := filepath.Join("testdata", fmt.Sprintf("v%d", formatters.OutputSchemaVersion))
+	frozen := false
+	if *updateCorpus {
+		frozen = corpusHasOutputs(t, versionDir)
+		require.NoError(t, removeOtherVersions(versionDir))
+	}
+
+	for _, tc := range corpusCases {
+		for _, format := range formatters.OutputFormats() {
+			// html wraps the mermaid output pinned here in a page whose script depends
+			// on whether Mermaid was vendored into the build.
+			if format == formatters.OutputFormatHTML {
+				continue
+			}
+			// svg and png are Graphviz renderings of the dot output pinned here.
+			if format.IsImage() {
+				continue
+			}
+			t.Run(tc.name+"/"+format.String(), func(t *testing.T) {
+				actual := runShow(t, tc.fixture, format, tc.args)
+				expectedPath := filepath.Join(versionDir, tc.name+format.FileExtension())
+
+				expected, err := os.ReadFile(expectedPath)
+				if os.IsNotExist(err) && *updateCorpus {
+					require.NoError(t, os.MkdirAll(versionDir, 0o755))
+					require.NoError(t, os.WriteFile(expectedPath, []byte(actual), 0o644))
+					return
+				}
+				require.NoError(t, err, "missing corpus output; run make compat-corpus")
+
+				if string(expected) == actual {
+					return
+				}
+				if frozen {
+					t.Fatalf("%s changed but OutputSchemaVersion is still %d: bump it before regenerating the corpus\n%s",
+						expectedPath, formatters.OutputSchemaVersion, goldie.Diff(goldie.ClassicDiff, actual, string(expected)))
+				}
+				t.Fatalf("output differs from %s (a breaking change for downstream golden files)\n%s",
+					expectedPath, goldie.Diff(goldie.ClassicDiff, actual, string(expected)))
+			})
+		}
+	}
+}
+
+// corpusRepoPath stands in for the temporary fixture copy in absolute paths, which
+// only the json format emits.
+const corpusRepoPath = "/corpus"
+
+// runShow copies fixture to a temporary directory outside any git repository, so the
+// output carries no commit label or file stats, and renders it in format.
+func runShow(t *testing.T, fixture string, format formatters.OutputFormat, extraArgs []string) string {
+	t.Helper()
+
+	repoDir := filepath.Join(t.TempDir(), fixture)
+	require.NoError(t, os.CopyFS(repoDir, os.DirFS(filepath.Join(inputsDir, fixture))))
+
+	cmd := show.NewCommand()
+	cmd.SetArgs(append([]string{"-r", repoDir, "-i", ".", "-f", format.String(), "--no-stats"}, extraArgs...))
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	require.NoError(t, cmd.Execute(), "stderr: %s", strings.TrimSpace(stderr.String()))
+
+	return strings.ReplaceAll(stdout.String(), filepath.Dir(repoDir), corpusRepoPath)
+}
+
+// corpusHasOutputs reports whether versionDir already holds expected outputs. Those
+// are frozen: -update-corpus only fills in outputs missing for the current version.
+func corpusHasOutputs(t *testing.T, versionDir string) bool {
+	t.Helper()
+
+	entries, err := os.ReadDir(versionDir)
+	if os.IsNotExist(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return len(entries) > 0
+}
+
+// removeOtherVersions deletes the corpus of every schema version but the current one.
+func removeOtherVersions(versionDir string) error {
+	entries, err := os.ReadDir("testdata")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join("testdata", entry.Name())
+		if !entry.IsDir() || path == inputsDir || path == versionDir || !strings.HasPrefix(entry.Name(), "v") {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package a
+
+import "example.com/gocycle/b"
+
+func Run() {
+	b.Step()
+}
+
+func Done() {}
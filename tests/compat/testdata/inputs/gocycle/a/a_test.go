@@ -0,0 +1,7 @@
+package a
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	Run()
+}
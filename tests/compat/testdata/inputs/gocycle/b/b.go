@@ -0,0 +1,7 @@
+package b
+
+import "example.com/gocycle/a"
+
+func Step() {
+	a.Done()
+}
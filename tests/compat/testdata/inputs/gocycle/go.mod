@@ -0,0 +1,3 @@
+module example.com/gocycle
+
+go 1.22
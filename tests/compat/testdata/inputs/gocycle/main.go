@@ -0,0 +1,7 @@
+package main
+
+import "example.com/gocycle/a"
+
+func main() {
+	a.Run()
+}
@@ -0,0 +1,6 @@
+from app import models
+from app.services import billing
+
+
+def run():
+    billing.charge(models.Customer())
@@ -0,0 +1,7 @@
+import { format } from './util';
+import { Logger } from './logging/logger';
+
+export function main(): string {
+  new Logger().log('start');
+  return format('done');
+}
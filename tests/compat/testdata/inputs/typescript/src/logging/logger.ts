@@ -0,0 +1,5 @@
+export class Logger {
+  log(message: string): void {
+    console.log(message);
+  }
+}
@@ -0,0 +1,5 @@
+import { format } from './util';
+
+test('format', () => {
+  expect(format('x')).toBe('[x]');
+});
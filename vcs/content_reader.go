@@ -1,14 +1,95 @@
 package vcs
 
-import "os"
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+)
 
-// ContentReader is a function that reads file content given a file path.
-// This allows the caller to control how files are read (filesystem, git, etc.)
-type ContentReader func(filePath string) ([]byte, error)
+// ContentReader gives resolvers a view of the analyzed tree. This allows the caller to
+// control where files come from (filesystem, git, etc.), so resolvers must use it for
+// every read and existence probe instead of touching the filesystem directly.
+type ContentReader interface {
+	// ReadFile returns the content of the file at filePath.
+	ReadFile(filePath string) ([]byte, error)
+	// Exists reports whether filePath names a file or directory in the analyzed tree.
+	Exists(filePath string) bool
+	// ListDir returns the sorted names of the files and directories directly inside dirPath.
+	ListDir(dirPath string) ([]string, error)
+}
+
+// PrefixReader is implemented by content readers that can read the start of a file
+// without reading all of it.
+type PrefixReader interface {
+	// ReadFilePrefix returns at most n bytes from the start of the file at filePath.
+	ReadFilePrefix(filePath string, n int) ([]byte, error)
+}
+
+// ContentHasher is implemented by content readers that can identify a file's content
+// without reading it, such as by its git blob SHA. Equal hashes mean equal content.
+type ContentHasher interface {
+	// ContentHash returns an identifier of the content of the file at filePath.
+	ContentHash(filePath string) (string, error)
+}
+
+// ReadFilePrefix returns at most n bytes from the start of filePath, reading only the
+// prefix when reader supports it.
+func ReadFilePrefix(reader ContentReader, filePath string, n int) ([]byte, error) {
+	if prefixReader, ok := reader.(PrefixReader); ok {
+		return prefixReader.ReadFilePrefix(filePath, n)
+	}
+	content, err := reader.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > n {
+		content = content[:n]
+	}
+	return content, nil
+}
 
 // FilesystemContentReader returns a ContentReader that reads from the filesystem.
 func FilesystemContentReader() ContentReader {
-	return func(absPath string) ([]byte, error) {
-		return os.ReadFile(absPath)
+	return filesystemContentReader{}
+}
+
+type filesystemContentReader struct{}
+
+func (filesystemContentReader) ReadFile(absPath string) ([]byte, error) {
+	return os.ReadFile(absPath)
+}
+
+func (filesystemContentReader) ReadFilePrefix(absPath string, n int) ([]byte, error) {
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, n)
+	read, err := io.ReadFull(file, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf[:read], nil
+}
+
+func (filesystemContentReader) Exists(absPath string) bool {
+	_, err := os.Stat(absPath)
+	return err == nil
+}
+
+func (filesystemContentReader) ListDir(absPath string) ([]string, error) {
+	entries, err := os.ReadDir(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
 	}
+	sort.Strings(names)
+	return names, nil
 }
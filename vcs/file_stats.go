@@ -5,4 +5,8 @@ type FileStats struct {
 	Additions int
 	Deletions int
 	IsNew     bool
+	// IsBinary reports that git could not count lines because the file is binary.
+	IsBinary bool
+	// RenamedFrom is the repository-relative path the file had before it was renamed.
+	RenamedFrom string
 }
@@ -1,6 +1,8 @@
 package git
 
+import "context"
+
 // ValidateCommit validates that a commit reference resolves in the given repository.
 func ValidateCommit(repoPath, commitID string) error {
-	return validateCommit(repoPath, commitID)
+	return validateCommit(context.Background(), repoPath, commitID)
 }
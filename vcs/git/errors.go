@@ -0,0 +1,81 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Failure classes reported by this package. Errors returned by its functions wrap at
+// most one of them, so callers can tell failures apart with errors.Is while the error
+// text stays readable.
+var (
+	// ErrRepoPathMissing reports that the repository path does not exist.
+	ErrRepoPathMissing = errors.New("repository path does not exist")
+	// ErrNotARepository reports that a path is not inside a git repository.
+	ErrNotARepository = errors.New("not a git repository")
+	// ErrInvalidCommit reports a commit reference that does not name a commit.
+	ErrInvalidCommit = errors.New("invalid commit reference")
+	// ErrPathNotInCommit reports a file that is not in a commit's tree. It also
+	// matches fs.ErrNotExist, so content readers over commits fail like the filesystem.
+	ErrPathNotInCommit = fmt.Errorf("path not in commit: %w", fs.ErrNotExist)
+)
+
+// classifiedError attaches a failure class to an error without changing its text.
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// classify marks err as belonging to class. A nil err stays nil.
+func classify(class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+func repoPathMissingError(repoPath string) error {
+	return fmt.Errorf("%w: %s", ErrRepoPathMissing, repoPath)
+}
+
+func notARepositoryError(repoPath string) error {
+	return fmt.Errorf("%s is %w (use 'git init' to initialize)", repoPath, ErrNotARepository)
+}
+
+// classifyStderrError marks err with the failure class git's stderr describes, if any.
+func classifyStderrError(err error, stderr string) error {
+	if class := classifyStderr(stderr); class != nil {
+		return classify(class, err)
+	}
+	return err
+}
+
+// classifyStderr returns the failure class git's stderr describes, or nil when it
+// describes none of them.
+func classifyStderr(stderr string) error {
+	switch {
+	case strings.Contains(stderr, "not a git repository"):
+		return ErrNotARepository
+	case strings.Contains(stderr, "does not exist in '"),
+		strings.Contains(stderr, "exists on disk, but not in '"):
+		return ErrPathNotInCommit
+	case strings.Contains(stderr, "unknown revision"),
+		strings.Contains(stderr, "bad revision"),
+		strings.Contains(stderr, "invalid object name"),
+		strings.Contains(stderr, "Not a valid object name"),
+		strings.Contains(stderr, "Needed a single revision"):
+		return ErrInvalidCommit
+	default:
+		return nil
+	}
+}
@@ -1,28 +1,79 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"io/fs"
+	"log/slog"
 	"path/filepath"
 	"strings"
 )
 
 // isGitRepository checks if the given path is inside a git repository
 func isGitRepository(path string) bool {
-	_, _, err := runGitCommand(path, "rev-parse", "--git-dir")
-	return err == nil
+	return checkGitRepository(context.Background(), path) == nil
 }
 
-// GetRepositoryRoot returns the absolute path to the repository root
+// checkGitRepository returns an error when path is not inside a git repository, or
+// the error of ctx when it ended before git answered.
+func checkGitRepository(ctx context.Context, path string) error {
+	if _, _, err := runGitCommandContext(ctx, path, "rev-parse", "--git-dir"); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return notARepositoryError(path)
+	}
+	return nil
+}
+
+// GetRepositoryRoot returns the absolute path to the repository root. A bare
+// repository has no working tree, so its git directory stands in for the root and
+// files are addressed as if they were checked out there.
 func GetRepositoryRoot(repoPath string) (string, error) {
-	stdout, stderr, err := runGitCommand(repoPath, "rev-parse", "--show-toplevel")
+	return getRepositoryRoot(context.Background(), repoPath)
+}
+
+// GetRepositoryRootContext returns the root like GetRepositoryRoot, stopping git when
+// ctx is done.
+func GetRepositoryRootContext(ctx context.Context, repoPath string) (string, error) {
+	return getRepositoryRoot(ctx, repoPath)
+}
+
+func getRepositoryRoot(ctx context.Context, repoPath string) (string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "rev-parse", "--show-toplevel")
 	if err != nil {
+		if strings.Contains(stderr, "must be run in a work tree") {
+			return getBareRepositoryRoot(ctx, repoPath)
+		}
+		// git cannot start in a directory that does not exist.
+		if stderr == "" && errors.Is(err, fs.ErrNotExist) {
+			return "", repoPathMissingError(repoPath)
+		}
 		return "", gitCommandError(err, stderr)
 	}
 
 	return strings.TrimSpace(string(stdout)), nil
 }
 
+// IsBareRepository reports whether repoPath is inside a repository without a working
+// tree.
+func IsBareRepository(repoPath string) (bool, error) {
+	stdout, stderr, err := runGitCommand(repoPath, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false, gitCommandError(err, stderr)
+	}
+	return strings.TrimSpace(string(stdout)) == "true", nil
+}
+
+func getBareRepositoryRoot(ctx context.Context, repoPath string) (string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", gitCommandError(err, stderr)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
 // filterDartFiles filters a list of file paths to include only .dart files
 func filterDartFiles(files []string) []string {
 	var dartFiles []string
@@ -74,26 +125,29 @@ func validateGitRelPath(path string) error {
 	return nil
 }
 
-// validateCommit checks if the given commit reference exists in the repository
-func validateCommit(repoPath, commitID string) error {
-	if err := validateGitRef(commitID); err != nil {
-		return err
-	}
-
-	_, stderr, err := runGitCommand(repoPath, "rev-parse", "--verify", commitID+"^{commit}")
-	if err != nil {
-		if stderr != "" {
-			return fmt.Errorf("invalid commit reference '%s': %s", commitID, stderr)
-		}
-		return fmt.Errorf("invalid commit reference '%s'", commitID)
-	}
+// validateCommit checks that the given commit reference resolves in the repository,
+// failing like resolveRef when it does not.
+func validateCommit(ctx context.Context, repoPath, commitID string) error {
+	_, err := resolveRef(ctx, repoPath, commitID)
+	return err
+}
 
-	return nil
+// isShallowRepository reports whether repoPath is a shallow clone, whose history
+// stops at the commits it was cloned or fetched with.
+func isShallowRepository(ctx context.Context, repoPath string) bool {
+	stdout, _, err := runGitCommandContext(ctx, repoPath, "rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(string(stdout)) == "true"
 }
 
 // GetCurrentCommitHash returns the current commit hash (HEAD)
 func GetCurrentCommitHash(repoPath string) (string, error) {
-	stdout, stderr, err := runGitCommand(repoPath, "rev-parse", "--short", "HEAD")
+	return GetCurrentCommitHashContext(context.Background(), repoPath)
+}
+
+// GetCurrentCommitHashContext returns the current commit hash like
+// GetCurrentCommitHash, stopping git when ctx is done.
+func GetCurrentCommitHashContext(ctx context.Context, repoPath string) (string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "rev-parse", "--short", "HEAD")
 	if err != nil {
 		return "", gitCommandError(err, stderr)
 	}
@@ -101,13 +155,20 @@ func GetCurrentCommitHash(repoPath string) (string, error) {
 	return strings.TrimSpace(string(stdout)), nil
 }
 
+// GetCommitHash returns the full SHA of the commit a reference names, resolved like
+// ResolveRef.
+func GetCommitHash(repoPath, commitID string) (string, error) {
+	return ResolveRef(repoPath, commitID)
+}
+
 // GetShortCommitHash returns the short version of a given commit hash
 func GetShortCommitHash(repoPath, commitID string) (string, error) {
-	if err := validateGitRef(commitID); err != nil {
+	sha, err := ResolveRef(repoPath, commitID)
+	if err != nil {
 		return "", err
 	}
 
-	stdout, stderr, err := runGitCommand(repoPath, "rev-parse", "--short", commitID)
+	stdout, stderr, err := runGitCommand(repoPath, "rev-parse", "--short", sha)
 	if err != nil {
 		return "", gitCommandError(err, stderr)
 	}
@@ -117,13 +178,23 @@ func GetShortCommitHash(repoPath, commitID string) (string, error) {
 
 // HasUncommittedChanges checks if there are any uncommitted changes in the repository
 func HasUncommittedChanges(repoPath string) (bool, error) {
+	return HasUncommittedChangesWithOptions(repoPath, AllUncommittedChanges())
+}
+
+// HasUncommittedChangesWithOptions checks if there are uncommitted changes selected by
+// opts in the repository
+func HasUncommittedChangesWithOptions(repoPath string, opts UncommittedOptions) (bool, error) {
 	stdout, stderr, err := runGitCommand(repoPath, "status", "--porcelain")
 	if err != nil {
 		return false, gitCommandError(err, stderr)
 	}
 
-	// If output is not empty, there are uncommitted changes
-	return strings.TrimSpace(string(stdout)) != "", nil
+	for _, line := range strings.Split(string(stdout), "\n") {
+		if len(line) >= 2 && opts.selects(line[0], line[1]) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // ParseCommitRange parses a commit specification and returns the from/to commits.
@@ -144,9 +215,15 @@ func ParseCommitRange(commitSpec string) (string, string, bool) {
 	return "", commitSpec, false
 }
 
+// IsMergeBaseRange reports whether commitSpec uses the three-dot syntax, which
+// compares the right side with its merge-base with the left side.
+func IsMergeBaseRange(commitSpec string) bool {
+	return strings.Contains(commitSpec, "...")
+}
+
 // isAncestor checks if possibleAncestor is an ancestor of possibleDescendant.
 // Returns true if possibleAncestor is older than (or equal to) possibleDescendant.
-func isAncestor(repoPath, possibleAncestor, possibleDescendant string) (bool, error) {
+func isAncestor(ctx context.Context, repoPath, possibleAncestor, possibleDescendant string) (bool, error) {
 	if err := validateGitRef(possibleAncestor); err != nil {
 		return false, err
 	}
@@ -154,10 +231,10 @@ func isAncestor(repoPath, possibleAncestor, possibleDescendant string) (bool, er
 		return false, err
 	}
 
-	_, _, err := runGitCommand(repoPath, "merge-base", "--is-ancestor", possibleAncestor, possibleDescendant)
+	_, _, err := runGitCommandContext(ctx, repoPath, "merge-base", "--is-ancestor", possibleAncestor, possibleDescendant)
 	if err != nil {
 		// Exit code 1 means not an ancestor, which is not an error for our purposes
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if exitCode(err) == 1 {
 			return false, nil
 		}
 		return false, err
@@ -166,13 +243,19 @@ func isAncestor(repoPath, possibleAncestor, possibleDescendant string) (bool, er
 }
 
 // NormalizeCommitRange ensures commits are in chronological order (older first).
-// If the commits are reversed (newer...older), it swaps them.
+// If the commits are reversed (newer...older), it swaps them. In a shallow clone,
+// where the history linking the commits may be missing, a range whose order cannot be
+// determined is kept as given and a warning is logged.
 // Returns (olderCommit, newerCommit, swapped, error)
 func NormalizeCommitRange(repoPath, from, to string) (string, string, bool, error) {
+	return normalizeCommitRange(context.Background(), repoPath, from, to)
+}
+
+func normalizeCommitRange(ctx context.Context, repoPath, from, to string) (string, string, bool, error) {
 	// Check if 'from' is an ancestor of 'to' (correct order)
-	isCorrectOrder, err := isAncestor(repoPath, from, to)
+	isCorrectOrder, err := isAncestor(ctx, repoPath, from, to)
 	if err != nil {
-		return from, to, false, err
+		return from, to, false, shallowAncestryError(ctx, repoPath, from, to, err)
 	}
 
 	if isCorrectOrder {
@@ -181,9 +264,9 @@ func NormalizeCommitRange(repoPath, from, to string) (string, string, bool, erro
 	}
 
 	// Check if 'to' is an ancestor of 'from' (reversed order)
-	isReversed, err := isAncestor(repoPath, to, from)
+	isReversed, err := isAncestor(ctx, repoPath, to, from)
 	if err != nil {
-		return from, to, false, err
+		return from, to, false, shallowAncestryError(ctx, repoPath, from, to, err)
 	}
 
 	if isReversed {
@@ -191,11 +274,103 @@ func NormalizeCommitRange(repoPath, from, to string) (string, string, bool, erro
 		return to, from, true, nil
 	}
 
+	// A shallow clone may lack the history that links the commits, so their order is
+	// unknown rather than diverged
+	if isShallowRepository(ctx, repoPath) {
+		slog.Warn("cannot determine the order of the commit range in a shallow clone; using it as given (run 'git fetch --unshallow' to fetch the full history)",
+			"from", from, "to", to)
+	}
+
 	// Commits are not in a linear ancestry (e.g., different branches)
 	// Keep original order - git diff will still work
 	return from, to, false, nil
 }
 
+// shallowAncestryError explains an ancestry check between from and to that failed with
+// err. In a shallow clone it names the commit the clone lacks, or suggests fetching the
+// full history when both are present.
+func shallowAncestryError(ctx context.Context, repoPath, from, to string, err error) error {
+	if ctx.Err() != nil || !isShallowRepository(ctx, repoPath) {
+		return err
+	}
+	for _, commit := range []string{from, to} {
+		if commitErr := validateCommit(ctx, repoPath, commit); commitErr != nil {
+			return commitErr
+		}
+	}
+	return fmt.Errorf("cannot determine the ancestry of %s and %s in shallow clone (run 'git fetch --unshallow' to fetch the full history): %w", from, to, err)
+}
+
+// ResolveCommitRange parses commitSpec like ParseCommitRange and resolves each commit
+// to its full SHA with ResolveRef, then a range to the commits it compares: a reversed
+// linear range is put back in order, and a three-dot range starts from the
+// merge-base of its two sides.
+// Returns (from, to, isRange, error)
+func ResolveCommitRange(repoPath, commitSpec string) (string, string, bool, error) {
+	return ResolveCommitRangeContext(context.Background(), repoPath, commitSpec)
+}
+
+// ResolveCommitRangeContext resolves commitSpec like ResolveCommitRange, stopping git
+// when ctx is done.
+func ResolveCommitRangeContext(ctx context.Context, repoPath, commitSpec string) (string, string, bool, error) {
+	from, to, isRange := ParseCommitRange(commitSpec)
+	if !isRange {
+		to, err := resolveRef(ctx, repoPath, to)
+		if err != nil {
+			return "", "", false, err
+		}
+		return "", to, false, nil
+	}
+	from, err := resolveRef(ctx, repoPath, from)
+	if err != nil {
+		return "", "", false, err
+	}
+	to, err = resolveRef(ctx, repoPath, to)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	from, to, swapped, err := normalizeCommitRange(ctx, repoPath, from, to)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to normalize commit range: %w", err)
+	}
+
+	// A reversed linear range keeps its swapped order; otherwise the merge-base is the
+	// left commit itself or, for diverged branches, their fork point.
+	if IsMergeBaseRange(commitSpec) && !swapped {
+		from, err = getMergeBase(ctx, repoPath, from, to)
+		if err != nil {
+			return "", "", false, fmt.Errorf("failed to find merge-base of commit range: %w", err)
+		}
+	}
+
+	return from, to, true, nil
+}
+
+// GetCommitRangeCommits lists the abbreviated SHAs of the first-parent commits reachable
+// from toCommit but not fromCommit, oldest first.
+func GetCommitRangeCommits(repoPath, fromCommit, toCommit string) ([]string, error) {
+	return GetCommitRangeCommitsContext(context.Background(), repoPath, fromCommit, toCommit)
+}
+
+// GetCommitRangeCommitsContext lists the commits like GetCommitRangeCommits, stopping
+// git when ctx is done.
+func GetCommitRangeCommitsContext(ctx context.Context, repoPath, fromCommit, toCommit string) ([]string, error) {
+	if err := validateGitRef(fromCommit); err != nil {
+		return nil, err
+	}
+	if err := validateGitRef(toCommit); err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "rev-list", "--reverse", "--first-parent", "--abbrev-commit", fromCommit+".."+toCommit)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	return strings.Fields(string(stdout)), nil
+}
+
 // GetCommitRangeLabel returns a label like "abc123...def456" for display
 func GetCommitRangeLabel(repoPath, fromCommit, toCommit string) (string, error) {
 	fromShort, err := GetShortCommitHash(repoPath, fromCommit)
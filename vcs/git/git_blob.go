@@ -0,0 +1,153 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GetCommitBlobSHAs returns the blob SHA of every file in a commit's tree below
+// repoPath, keyed by absolute path. The tree is read with a single git ls-tree
+// invocation; when repoPath is a subdirectory, git lists only that subtree with
+// paths relative to it, so no extra repository root lookup is needed.
+func GetCommitBlobSHAs(repoPath, commitID string) (map[string]string, error) {
+	return GetCommitBlobSHAsContext(context.Background(), repoPath, commitID)
+}
+
+// GetCommitBlobSHAsContext returns the blob SHAs like GetCommitBlobSHAs, stopping git
+// when ctx is done.
+func GetCommitBlobSHAsContext(ctx context.Context, repoPath, commitID string) (map[string]string, error) {
+	if err := validateGitRef(commitID); err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "ls-tree", "-r", "-z", commitID)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	return parseLsTreeBlobs(repoPath, stdout)
+}
+
+// parseLsTreeBlobs parses NUL-terminated "<mode> <type> <sha>\t<path>" records.
+// Non-blob entries such as submodule commits are skipped.
+func parseLsTreeBlobs(basePath string, output []byte) (map[string]string, error) {
+	blobs := make(map[string]string)
+	for _, record := range bytes.Split(output, []byte{0}) {
+		if len(record) == 0 {
+			continue
+		}
+
+		header, path, ok := strings.Cut(string(record), "\t")
+		if !ok {
+			return nil, fmt.Errorf("unexpected git ls-tree output: %q", record)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git ls-tree output: %q", record)
+		}
+		if fields[1] != "blob" {
+			continue
+		}
+
+		blobs[filepath.Join(basePath, filepath.FromSlash(path))] = fields[2]
+	}
+	return blobs, nil
+}
+
+// GetWorkingTreeBlobSHAs returns the blob SHA git would record for each file as it
+// currently exists on disk, keyed by absolute path. All files are hashed with a single
+// git hash-object invocation that reads their paths from standard input, so the file
+// count is not bounded by the command line length limit.
+//
+// Files outside repoPath, files missing from disk, and files whose names contain a
+// line break, which cannot be listed one per line, are omitted: callers leave their SHA
+// empty.
+func GetWorkingTreeBlobSHAs(repoPath string, filePaths []string) (map[string]string, error) {
+	return GetWorkingTreeBlobSHAsContext(context.Background(), repoPath, filePaths)
+}
+
+// GetWorkingTreeBlobSHAsContext returns the blob SHAs like GetWorkingTreeBlobSHAs,
+// stopping git when ctx is done.
+func GetWorkingTreeBlobSHAsContext(ctx context.Context, repoPath string, filePaths []string) (map[string]string, error) {
+	var hashable []string
+	var input strings.Builder
+	for _, filePath := range filePaths {
+		rel, err := filepath.Rel(repoPath, filePath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if strings.ContainsAny(filePath, "\n\r") {
+			continue
+		}
+		if info, err := os.Stat(filePath); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		hashable = append(hashable, filePath)
+		input.WriteString(filePath)
+		input.WriteByte('\n')
+	}
+
+	blobs := make(map[string]string, len(hashable))
+	if len(hashable) == 0 {
+		return blobs, nil
+	}
+
+	stdout, stderr, err := runGitCommandWithInput(ctx, repoPath, []byte(input.String()), "hash-object", "--stdin-paths")
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	shas := strings.Fields(string(stdout))
+	if len(shas) != len(hashable) {
+		return nil, fmt.Errorf("git hash-object returned %d hashes for %d files", len(shas), len(hashable))
+	}
+	for i, filePath := range hashable {
+		blobs[filePath] = shas[i]
+	}
+	return blobs, nil
+}
+
+// GetCommitFileSizes returns the size in bytes of every file in a commit's tree below
+// repoPath, keyed by absolute path. Sizes come from a single git ls-tree -l, so no
+// file content is read.
+func GetCommitFileSizes(repoPath, commitID string) (map[string]int64, error) {
+	if err := validateGitRef(commitID); err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, err := runGitCommand(repoPath, "ls-tree", "-r", "-l", "-z", commitID)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	sizes := make(map[string]int64)
+	for _, record := range bytes.Split(stdout, []byte{0}) {
+		if len(record) == 0 {
+			continue
+		}
+
+		header, path, ok := strings.Cut(string(record), "\t")
+		if !ok {
+			return nil, fmt.Errorf("unexpected git ls-tree output: %q", record)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git ls-tree output: %q", record)
+		}
+		if fields[1] != "blob" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git ls-tree size %q: %w", fields[3], err)
+		}
+		sizes[filepath.Join(repoPath, filepath.FromSlash(path))] = size
+	}
+	return sizes, nil
+}
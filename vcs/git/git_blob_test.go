@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCommitBlobSHAs_ParsesTreeWithSingleInvocation(t *testing.T) {
+	runner := useFakeGitRunner(t).
+		on("ls-tree -r -z abc123", fakeGitResponse{
+			stdout: "100644 blob 1111111111111111111111111111111111111111\tmain.go\x00" +
+				"100755 blob 2222222222222222222222222222222222222222\tlib/has space.go\x00" +
+				"160000 commit 3333333333333333333333333333333333333333\tvendor/sub\x00",
+		})
+
+	blobs, err := GetCommitBlobSHAs("/repo", "abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.Join("/repo", "main.go"):             "1111111111111111111111111111111111111111",
+		filepath.Join("/repo", "lib", "has space.go"): "2222222222222222222222222222222222222222",
+	}, blobs)
+	assert.Len(t, runner.calls, 1)
+}
+
+func TestGetCommitBlobSHAs_RejectsOptionLikeCommit(t *testing.T) {
+	runner := useFakeGitRunner(t)
+
+	_, err := GetCommitBlobSHAs("/repo", "--output=x")
+
+	require.Error(t, err)
+	assert.Empty(t, runner.calls)
+}
+
+func TestGetCommitBlobSHAs_ReportsGitError(t *testing.T) {
+	useFakeGitRunner(t).
+		on("ls-tree -r -z missing", fakeGitResponse{stderr: "fatal: Not a valid object name missing", exitCode: 128})
+
+	_, err := GetCommitBlobSHAs("/repo", "missing")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Not a valid object name")
+}
+
+func TestGetCommitFileSizes_ParsesLongTreeListing(t *testing.T) {
+	runner := useFakeGitRunner(t).
+		on("ls-tree -r -l -z abc123", fakeGitResponse{
+			stdout: "100644 blob 1111111111111111111111111111111111111111     120\tmain.go\x00" +
+				"100755 blob 2222222222222222222222222222222222222222 1048576\tlib/has space.go\x00" +
+				"160000 commit 3333333333333333333333333333333333333333       -\tvendor/sub\x00",
+		})
+
+	sizes, err := GetCommitFileSizes("/repo", "abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		filepath.Join("/repo", "main.go"):             120,
+		filepath.Join("/repo", "lib", "has space.go"): 1048576,
+	}, sizes)
+	assert.Len(t, runner.calls, 1)
+}
+
+func TestGetWorkingTreeBlobSHAs_HashesAllFilesWithSingleInvocation(t *testing.T) {
+	repoRoot := t.TempDir()
+	first := filepath.Join(repoRoot, "a.go")
+	second := filepath.Join(repoRoot, "b.go")
+	require.NoError(t, os.WriteFile(first, []byte("package a\n"), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte("package b\n"), 0o644))
+	deleted := filepath.Join(repoRoot, "deleted.go")
+	outside := filepath.Join(t.TempDir(), "outside.go")
+	require.NoError(t, os.WriteFile(outside, []byte("package outside\n"), 0o644))
+
+	runner := useFakeGitRunner(t).
+		on("hash-object --stdin-paths", fakeGitResponse{
+			stdout: "1111111111111111111111111111111111111111\n2222222222222222222222222222222222222222\n",
+		})
+
+	blobs, err := GetWorkingTreeBlobSHAs(repoRoot, []string{first, deleted, outside, second})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		first:  "1111111111111111111111111111111111111111",
+		second: "2222222222222222222222222222222222222222",
+	}, blobs)
+	assert.Equal(t, []string{first + "\n" + second + "\n"}, runner.inputs)
+}
+
+func TestGetWorkingTreeBlobSHAs_NoHashableFiles_SkipsGit(t *testing.T) {
+	runner := useFakeGitRunner(t)
+
+	blobs, err := GetWorkingTreeBlobSHAs(t.TempDir(), []string{"/elsewhere/x.go"})
+
+	require.NoError(t, err)
+	assert.Empty(t, blobs)
+	assert.Empty(t, runner.calls)
+}
@@ -1,17 +1,157 @@
 package git
 
 import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
 // GitCommitContentReader returns a ContentReader that reads file content from a specific git commit.
+// Existence checks and directory listings reflect the commit's tree, never the working tree.
 func GitCommitContentReader(repoPath, commitID string) vcs.ContentReader {
-	return func(absPath string) ([]byte, error) {
-		relPath := getRelativePath(absPath, repoPath)
-		return GetFileContentFromCommit(repoPath, commitID, relPath)
+	return GitCommitContentReaderContext(context.Background(), repoPath, commitID)
+}
+
+// GitCommitContentReaderContext returns a ContentReader like GitCommitContentReader
+// whose git commands are stopped once ctx is done.
+func GitCommitContentReaderContext(ctx context.Context, repoPath, commitID string) vcs.ContentReader {
+	return &commitContentReader{ctx: ctx, repoPath: repoPath, commitID: commitID}
+}
+
+// commitContentReader serves reads with git show and answers Exists, ListDir and
+// ContentHash from a single git ls-tree of the commit, loaded on first use.
+type commitContentReader struct {
+	ctx      context.Context
+	repoPath string
+	commitID string
+
+	treeOnce sync.Once
+	tree     commitTree
+	treeErr  error
+}
+
+// commitTree indexes a commit's files and directories by repository-relative slash path.
+// files maps each file to its object name.
+type commitTree struct {
+	files map[string]string
+	dirs  map[string]map[string]bool
+}
+
+func (r *commitContentReader) ReadFile(absPath string) ([]byte, error) {
+	relPath := getRelativePath(absPath, r.repoPath)
+	return getFileContentFromCommit(r.ctx, r.repoPath, r.commitID, relPath)
+}
+
+func (r *commitContentReader) Exists(absPath string) bool {
+	relPath, ok := r.treePath(absPath)
+	if !ok {
+		return false
+	}
+	tree, err := r.loadTree()
+	if err != nil {
+		return false
+	}
+	_, isDir := tree.dirs[relPath]
+	return tree.files[relPath] != "" || isDir
+}
+
+// ContentHash returns the blob SHA of absPath in the commit, so callers can tell whether
+// content changed without reading it.
+func (r *commitContentReader) ContentHash(absPath string) (string, error) {
+	relPath, ok := r.treePath(absPath)
+	if !ok {
+		return "", fmt.Errorf("%s is outside the repository: %w", absPath, fs.ErrNotExist)
+	}
+	tree, err := r.loadTree()
+	if err != nil {
+		return "", err
+	}
+	object, ok := tree.files[relPath]
+	if !ok {
+		return "", classify(ErrPathNotInCommit, fmt.Errorf("%s not found in commit %s: %w", absPath, r.commitID, fs.ErrNotExist))
+	}
+	return object, nil
+}
+
+func (r *commitContentReader) ListDir(absPath string) ([]string, error) {
+	relPath, ok := r.treePath(absPath)
+	if !ok {
+		return nil, fmt.Errorf("%s is outside the repository: %w", absPath, fs.ErrNotExist)
+	}
+	tree, err := r.loadTree()
+	if err != nil {
+		return nil, err
+	}
+	children, ok := tree.dirs[relPath]
+	if !ok {
+		return nil, classify(ErrPathNotInCommit, fmt.Errorf("%s not found in commit %s: %w", absPath, r.commitID, fs.ErrNotExist))
+	}
+
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// treePath converts absPath to the slash path used as a key in commitTree.
+func (r *commitContentReader) treePath(absPath string) (string, bool) {
+	relPath := filepath.ToSlash(getRelativePath(absPath, r.repoPath))
+	if relPath == ".." || strings.HasPrefix(relPath, "../") || filepath.IsAbs(relPath) {
+		return "", false
+	}
+	return relPath, true
+}
+
+func (r *commitContentReader) loadTree() (commitTree, error) {
+	r.treeOnce.Do(func() {
+		r.tree, r.treeErr = readCommitTree(r.ctx, r.repoPath, r.commitID)
+	})
+	return r.tree, r.treeErr
+}
+
+func readCommitTree(ctx context.Context, repoPath, commitID string) (commitTree, error) {
+	if err := validateGitRef(commitID); err != nil {
+		return commitTree{}, err
+	}
+
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "ls-tree", "-r", "-z", "--full-tree", commitID)
+	if err != nil {
+		return commitTree{}, gitCommandError(err, stderr)
+	}
+
+	tree := commitTree{
+		files: make(map[string]string),
+		dirs:  map[string]map[string]bool{".": {}},
+	}
+	for _, record := range strings.Split(string(stdout), "\x00") {
+		if record == "" {
+			continue
+		}
+		header, filePath, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(header)
+		if !ok || len(fields) != 3 {
+			return commitTree{}, fmt.Errorf("unexpected git ls-tree output: %q", record)
+		}
+		tree.files[filePath] = fields[2]
+		for child := filePath; child != "."; {
+			parent := path.Dir(child)
+			if tree.dirs[parent] == nil {
+				tree.dirs[parent] = make(map[string]bool)
+			}
+			tree.dirs[parent][path.Base(child)] = true
+			child = parent
+		}
 	}
+	return tree, nil
 }
 
 // GetRelativePath converts an absolute file path to a path relative to the repository root
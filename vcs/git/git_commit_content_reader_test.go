@@ -0,0 +1,77 @@
+package git
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitCommitContentReader_ExistsAndListDirUseCommitTree(t *testing.T) {
+	runner := useFakeGitRunner(t).
+		on("ls-tree -r -z --full-tree abc123", fakeGitResponse{
+			stdout: "100644 blob 1111111111111111111111111111111111111111\tgo.mod\x00" +
+				"100644 blob 2222222222222222222222222222222222222222\tcmd/app/main.go\x00" +
+				"100644 blob 3333333333333333333333333333333333333333\tcmd/app/flags.go\x00" +
+				"100644 blob 4444444444444444444444444444444444444444\tREADME.md\x00",
+		})
+	repo := filepath.FromSlash("/repo")
+	reader := GitCommitContentReader(repo, "abc123")
+
+	assert.True(t, reader.Exists(filepath.Join(repo, "go.mod")))
+	assert.True(t, reader.Exists(filepath.Join(repo, "cmd", "app")))
+	assert.False(t, reader.Exists(filepath.Join(repo, "cmd", "app", "missing.go")))
+	assert.False(t, reader.Exists(filepath.FromSlash("/elsewhere/go.mod")))
+
+	names, err := reader.ListDir(repo)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "cmd", "go.mod"}, names)
+
+	names, err = reader.ListDir(filepath.Join(repo, "cmd", "app"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flags.go", "main.go"}, names)
+
+	_, err = reader.ListDir(filepath.Join(repo, "pkg"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	assert.ErrorIs(t, err, ErrPathNotInCommit)
+
+	assert.Len(t, runner.calls, 1)
+}
+
+func TestGitCommitContentReader_ContentHashIsBlobSHA(t *testing.T) {
+	runner := useFakeGitRunner(t).
+		on("ls-tree -r -z --full-tree abc123", fakeGitResponse{
+			stdout: "100644 blob 1111111111111111111111111111111111111111\tgo.mod\x00" +
+				"100644 blob 2222222222222222222222222222222222222222\tcmd/app/main.go\x00",
+		})
+	repo := filepath.FromSlash("/repo")
+	reader := GitCommitContentReader(repo, "abc123")
+	hasher, ok := reader.(vcs.ContentHasher)
+	require.True(t, ok)
+
+	hash, err := hasher.ContentHash(filepath.Join(repo, "cmd", "app", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "2222222222222222222222222222222222222222", hash)
+
+	_, err = hasher.ContentHash(filepath.Join(repo, "cmd", "app"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	assert.ErrorIs(t, err, ErrPathNotInCommit)
+
+	assert.Len(t, runner.calls, 1)
+}
+
+func TestGitCommitContentReader_TreeErrorMeansNothingExists(t *testing.T) {
+	useFakeGitRunner(t).
+		on("ls-tree -r -z --full-tree missing", fakeGitResponse{stderr: "fatal: Not a valid object name missing", exitCode: 128})
+	repo := filepath.FromSlash("/repo")
+	reader := GitCommitContentReader(repo, "missing")
+
+	assert.False(t, reader.Exists(filepath.Join(repo, "go.mod")))
+	_, err := reader.ListDir(repo)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Not a valid object name")
+}
@@ -1,32 +1,71 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 )
 
+// UncommittedOptions selects which uncommitted changes are reported. A file is selected
+// when any of its changes is.
+type UncommittedOptions struct {
+	// IncludeStaged selects changes in the index that are not in HEAD.
+	IncludeStaged bool
+	// IncludeUnstaged selects changes to tracked files in the working tree that are not
+	// in the index.
+	IncludeUnstaged bool
+	// IncludeUntracked selects files git does not track and does not ignore.
+	IncludeUntracked bool
+}
+
+// AllUncommittedChanges selects staged, unstaged, and untracked changes.
+func AllUncommittedChanges() UncommittedOptions {
+	return UncommittedOptions{IncludeStaged: true, IncludeUnstaged: true, IncludeUntracked: true}
+}
+
+// selects reports whether a file with the porcelain status XY is selected.
+func (o UncommittedOptions) selects(x, y byte) bool {
+	if x == '?' && y == '?' {
+		return o.IncludeUntracked
+	}
+	return (o.IncludeStaged && x != ' ') || (o.IncludeUnstaged && y != ' ')
+}
+
 // GetUncommittedFiles finds all uncommitted files in a git repository.
 // Returns absolute paths to all uncommitted files (staged, unstaged, and untracked).
 func GetUncommittedFiles(repoPath string) ([]string, error) {
+	return GetUncommittedFilesWithOptions(repoPath, AllUncommittedChanges())
+}
+
+// GetUncommittedFilesWithOptions finds the uncommitted files in a git repository with
+// changes selected by opts, limited like GetCommitFiles to the extensions exts when it
+// is not empty. Returns absolute paths.
+func GetUncommittedFilesWithOptions(repoPath string, opts UncommittedOptions, exts ...string) ([]string, error) {
+	return GetUncommittedFilesWithOptionsContext(context.Background(), repoPath, opts, exts...)
+}
+
+// GetUncommittedFilesWithOptionsContext finds the uncommitted files like
+// GetUncommittedFilesWithOptions, stopping git when ctx is done.
+func GetUncommittedFilesWithOptionsContext(ctx context.Context, repoPath string, opts UncommittedOptions, exts ...string) ([]string, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository (use 'git init' to initialize)", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	// Get all uncommitted files
-	uncommittedFiles, err := getUncommittedFiles(repoPath)
+	// Get the selected uncommitted files
+	uncommittedFiles, err := getUncommittedFiles(ctx, repoPath, opts, exts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get uncommitted files: %w", err)
 	}
@@ -37,9 +76,12 @@ func GetUncommittedFiles(repoPath string) ([]string, error) {
 	return absolutePaths, nil
 }
 
-// getUncommittedFiles returns a list of all uncommitted files (relative to repo root)
-func getUncommittedFiles(repoPath string) ([]string, error) {
-	stdout, stderr, err := runGitCommand(repoPath, "status", "--porcelain", "--untracked-files=all")
+// getUncommittedFiles returns a list of the uncommitted files selected by opts (relative
+// to repo root), limited to exts when it is not empty. Submodules are left out: their entries are directories, not files the
+// graph builder can read.
+func getUncommittedFiles(ctx context.Context, repoPath string, opts UncommittedOptions, exts []string) ([]string, error) {
+	args := []string{"status", "--porcelain", "--untracked-files=all", "--ignore-submodules=all"}
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, withExtensionPathspecs(args, exts)...)
 	if err != nil {
 		// Check if git is not installed
 		if strings.Contains(stderr, "not found") || strings.Contains(stderr, "not recognized") {
@@ -64,13 +106,16 @@ func getUncommittedFiles(repoPath string) ([]string, error) {
 		if statusX == 'D' || statusY == 'D' {
 			continue
 		}
+		if !opts.selects(statusX, statusY) {
+			continue
+		}
 
-		filePath := strings.TrimSpace(line[3:])
+		// Renamed files are listed as "old -> new"; use the new filename
+		_, filePath := splitPorcelainRename(strings.TrimSpace(line[3:]))
 
-		// Handle renamed files (format: "old -> new")
-		if strings.Contains(filePath, " -> ") {
-			parts := strings.Split(filePath, " -> ")
-			filePath = parts[1] // Use the new filename
+		// Untracked nested repositories are listed as directories, with a trailing slash
+		if strings.HasSuffix(filePath, "/") {
+			continue
 		}
 
 		if filePath != "" {
@@ -78,50 +123,70 @@ func getUncommittedFiles(repoPath string) ([]string, error) {
 		}
 	}
 
-	return files, nil
+	return keepGitExtensions(files, exts), nil
 }
 
-// GetCommitDartFiles finds all files that were changed in a specific commit.
-// Returns absolute paths to all files added, modified, or renamed in the commit.
-func GetCommitDartFiles(repoPath, commitID string) ([]string, error) {
+// GetCommitFiles finds the files that were changed in a specific commit.
+// Returns absolute paths to all files added, modified, or renamed in the commit. When
+// exts, such as ".go" or "dart", is not empty, only files with one of those extensions,
+// compared case-insensitively, are listed; git does the filtering, so a commit touching
+// thousands of other files is not listed only to be discarded.
+func GetCommitFiles(repoPath, commitID string, exts ...string) ([]string, error) {
+	return GetCommitFilesContext(context.Background(), repoPath, commitID, exts...)
+}
+
+// GetCommitFilesContext finds the files changed in a commit like GetCommitFiles,
+// stopping git when ctx is done.
+func GetCommitFilesContext(ctx context.Context, repoPath, commitID string, exts ...string) ([]string, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository (use 'git init' to initialize)", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
-	// Validate the commit exists
-	if err := validateCommit(repoPath, commitID); err != nil {
+	// Resolve the commit to its SHA
+	commitID, err := resolveRef(ctx, repoPath, commitID)
+	if err != nil {
 		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
 	// Get files changed in the commit
-	commitFiles, err := getCommitFiles(repoPath, commitID)
+	commitFiles, err := getCommitFiles(ctx, repoPath, commitID, exts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get files from commit: %w", err)
 	}
 
-	// Convert to absolute paths (no filtering - include all files)
+	// Convert to absolute paths
 	absolutePaths := toAbsolutePaths(repoRoot, commitFiles)
 
 	return absolutePaths, nil
 }
 
-// getCommitFiles returns a list of all files changed in the specified commit (relative to repo root)
-func getCommitFiles(repoPath, commitID string) ([]string, error) {
+// GetCommitDartFiles finds all files that were changed in a specific commit.
+//
+// Deprecated: despite its name it lists files of every language; use GetCommitFiles.
+func GetCommitDartFiles(repoPath, commitID string) ([]string, error) {
+	return GetCommitFiles(repoPath, commitID)
+}
+
+// getCommitFiles returns a list of the files changed in the specified commit (relative to
+// repo root), limited to exts when it is not empty.
+func getCommitFiles(ctx context.Context, repoPath, commitID string, exts []string) ([]string, error) {
 	// Use --root flag to handle root commits (first commit in repo)
 	// Use --diff-filter=d to exclude deleted files (only include added, modified, and renamed files)
-	stdout, stderr, err := runGitCommand(repoPath, "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", "--diff-filter=d", commitID)
+	// Use --ignore-submodules=all to skip submodule pointer updates, which are not files
+	args := []string{"diff-tree", "--no-commit-id", "--name-only", "-r", "--root", "--diff-filter=d", "--ignore-submodules=all", commitID}
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, withExtensionPathspecs(args, exts)...)
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
@@ -132,18 +197,22 @@ func getCommitFiles(repoPath, commitID string) ([]string, error) {
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line != "" {
-			files = append(files, line)
+			files = append(files, unquoteGitPath(line))
 		}
 	}
 
-	return files, nil
+	return keepGitExtensions(files, exts), nil
 }
 
 // GetFileContentFromCommit reads the content of a file at a specific commit
 // using 'git show commit:path'. The filePath should be relative to the repository root.
 func GetFileContentFromCommit(repoPath, commitID, filePath string) ([]byte, error) {
+	return getFileContentFromCommit(context.Background(), repoPath, commitID, filePath)
+}
+
+func getFileContentFromCommit(ctx context.Context, repoPath, commitID, filePath string) ([]byte, error) {
 	if err := validateGitRef(commitID); err != nil {
-		return nil, err
+		return nil, classify(ErrInvalidCommit, err)
 	}
 	if err := validateGitRelPath(filePath); err != nil {
 		return nil, err
@@ -152,10 +221,13 @@ func GetFileContentFromCommit(repoPath, commitID, filePath string) ([]byte, erro
 	// Format: commit:path
 	ref := fmt.Sprintf("%s:%s", commitID, filePath)
 
-	stdout, stderr, err := runGitCommand(repoPath, "show", ref)
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "show", ref)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
 		if stderr != "" {
-			return nil, fmt.Errorf("git show failed: %s", stderr)
+			return nil, classifyStderrError(fmt.Errorf("git show failed: %s", stderr), stderr)
 		}
 		return nil, err
 	}
@@ -165,35 +237,47 @@ func GetFileContentFromCommit(repoPath, commitID, filePath string) ([]byte, erro
 
 // GetCommitRangeFiles finds all files changed between two commits.
 // Uses: git diff --name-only --diff-filter=d <from> <to>
-// Returns absolute paths to all files added, modified, or renamed between the commits.
-func GetCommitRangeFiles(repoPath, fromCommit, toCommit string) ([]string, error) {
+// Returns absolute paths to all files added, modified, or renamed between the commits,
+// limited like GetCommitFiles to the extensions exts when it is not empty.
+func GetCommitRangeFiles(repoPath, fromCommit, toCommit string, exts ...string) ([]string, error) {
+	return GetCommitRangeFilesContext(context.Background(), repoPath, fromCommit, toCommit, exts...)
+}
+
+// GetCommitRangeFilesContext finds the files changed between two commits like
+// GetCommitRangeFiles, stopping git when ctx is done.
+func GetCommitRangeFilesContext(ctx context.Context, repoPath, fromCommit, toCommit string, exts ...string) ([]string, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository (use 'git init' to initialize)", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
-	// Validate both commits exist
-	if err := validateCommit(repoPath, fromCommit); err != nil {
+	// Resolve both commits to their SHAs
+	fromCommit, err := resolveRef(ctx, repoPath, fromCommit)
+	if err != nil {
 		return nil, err
 	}
-	if err := validateCommit(repoPath, toCommit); err != nil {
+	toCommit, err = resolveRef(ctx, repoPath, toCommit)
+	if err != nil {
 		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
 	// Get files changed between the two commits
+	// -M reports a renamed file once, under its new path, instead of as a delete and an add
 	// --diff-filter=d excludes deleted files (only include added, modified, and renamed files)
-	stdout, stderr, err := runGitCommand(repoPath, "diff", "--name-only", "--diff-filter=d", fromCommit, toCommit)
+	// --ignore-submodules=all skips submodule pointer updates, which are not files
+	args := []string{"diff", "--name-only", "-M", "--diff-filter=d", "--ignore-submodules=all", fromCommit, toCommit}
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, withExtensionPathspecs(args, exts)...)
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
@@ -204,12 +288,12 @@ func GetCommitRangeFiles(repoPath, fromCommit, toCommit string) ([]string, error
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line != "" {
-			files = append(files, line)
+			files = append(files, unquoteGitPath(line))
 		}
 	}
 
 	// Convert to absolute paths
-	absolutePaths := toAbsolutePaths(repoRoot, files)
+	absolutePaths := toAbsolutePaths(repoRoot, keepGitExtensions(files, exts))
 
 	return absolutePaths, nil
 }
@@ -1,6 +1,9 @@
+//go:build integration
+
 package git
 
 import (
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -167,6 +170,7 @@ func TestGetUncommittedDartFiles_NotGitRepo(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not a git repository")
+	assert.ErrorIs(t, err, ErrNotARepository)
 }
 
 func TestGetUncommittedDartFiles_InvalidPath(t *testing.T) {
@@ -174,6 +178,7 @@ func TestGetUncommittedDartFiles_InvalidPath(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "does not exist")
+	assert.ErrorIs(t, err, ErrRepoPathMissing)
 }
 
 func TestGetUncommittedFiles_RenamedFiles(t *testing.T) {
@@ -341,6 +346,7 @@ func TestGetCommitDartFiles_InvalidCommit(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid commit reference")
+	assert.ErrorIs(t, err, ErrInvalidCommit)
 }
 
 func TestGetCommitDartFiles_HeadReference(t *testing.T) {
@@ -395,6 +401,7 @@ func TestGetCommitDartFiles_NotGitRepo(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not a git repository")
+	assert.ErrorIs(t, err, ErrNotARepository)
 }
 
 func TestGetCommitDartFiles_InvalidPath(t *testing.T) {
@@ -402,6 +409,63 @@ func TestGetCommitDartFiles_InvalidPath(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "does not exist")
+	assert.ErrorIs(t, err, ErrRepoPathMissing)
+}
+
+func TestGetCommitFiles_WithExtensionsListsOnlyMatchingFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "lib"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "web"), 0755))
+	createFile(t, tmpDir, "main.go", "package main")
+	createFile(t, tmpDir, "lib/App.DART", "void main() {}")
+	createFile(t, tmpDir, "web/index.ts", "export {};")
+	createFile(t, tmpDir, "README.md", "# Test")
+	gitAdd(t, tmpDir, ".")
+	commitID := gitCommitAndGetSHA(t, tmpDir, "Add mixed files")
+
+	files, err := GetCommitFiles(tmpDir, commitID, ".go", "dart")
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/lib/App.DART\n$REPO/main.go", normalizeFilePaths(tmpDir, files))
+
+	files, err = GetCommitFiles(tmpDir, commitID, ".rs")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	files, err = GetCommitFiles(tmpDir, commitID)
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+}
+
+func TestGetCommitRangeFiles_WithExtensionsListsOnlyMatchingFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "first.txt", "content")
+	gitAdd(t, tmpDir, "first.txt")
+	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "pkg"), 0755))
+	createFile(t, tmpDir, "pkg/a.go", "package pkg")
+	createFile(t, tmpDir, "second.txt", "content")
+	gitAdd(t, tmpDir, ".")
+	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+
+	files, err := GetCommitRangeFiles(tmpDir, firstCommit, secondCommit, ".go")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/pkg/a.go", normalizeFilePaths(tmpDir, files))
+}
+
+func TestGetUncommittedFilesWithOptions_WithExtensionsListsOnlyMatchingFiles(t *testing.T) {
+	tmpDir := setupMixedChangesRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "cmd"), 0755))
+	createFile(t, tmpDir, "cmd/main.go", "package main")
+
+	files, err := GetUncommittedFilesWithOptions(tmpDir, AllUncommittedChanges(), ".go")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/cmd/main.go", normalizeFilePaths(tmpDir, files))
 }
 
 // Tests for GetFileContentFromCommit
@@ -480,6 +544,9 @@ func TestGetFileContentFromCommit_FileNotFound(t *testing.T) {
 	_, err := GetFileContentFromCommit(tmpDir, commitID, "nonexistent.txt")
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrPathNotInCommit)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NotErrorIs(t, err, ErrInvalidCommit)
 }
 
 func TestGetFileContentFromCommit_InvalidCommit(t *testing.T) {
@@ -493,6 +560,17 @@ func TestGetFileContentFromCommit_InvalidCommit(t *testing.T) {
 	_, err := GetFileContentFromCommit(tmpDir, "invalid-sha", "test.txt")
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidCommit)
+	assert.NotErrorIs(t, err, ErrPathNotInCommit)
+}
+
+func TestGetFileContentFromCommit_NotGitRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := GetFileContentFromCommit(tmpDir, "HEAD", "test.txt")
+
+	assert.ErrorIs(t, err, ErrNotARepository)
+	assert.Contains(t, err.Error(), "git show failed")
 }
 
 // Tests for GetCommitRangeFiles
@@ -620,6 +698,7 @@ func TestGetCommitRangeFiles_InvalidFromCommit(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid commit reference")
+	assert.ErrorIs(t, err, ErrInvalidCommit)
 }
 
 func TestGetCommitRangeFiles_InvalidToCommit(t *testing.T) {
@@ -634,6 +713,7 @@ func TestGetCommitRangeFiles_InvalidToCommit(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid commit reference")
+	assert.ErrorIs(t, err, ErrInvalidCommit)
 }
 
 func TestGetCommitRangeFiles_NotGitRepo(t *testing.T) {
@@ -643,6 +723,7 @@ func TestGetCommitRangeFiles_NotGitRepo(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not a git repository")
+	assert.ErrorIs(t, err, ErrNotARepository)
 }
 
 func TestGetCommitRangeFiles_InvalidPath(t *testing.T) {
@@ -650,4 +731,62 @@ func TestGetCommitRangeFiles_InvalidPath(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "does not exist")
+	assert.ErrorIs(t, err, ErrRepoPathMissing)
+}
+
+// setupMixedChangesRepo commits two files, then stages an edit to staged.txt, makes an
+// unstaged edit to unstaged.txt, and adds an untracked file.
+func setupMixedChangesRepo(t *testing.T) string {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "staged.txt", "one\n")
+	createFile(t, tmpDir, "unstaged.txt", "one\n")
+	gitAdd(t, tmpDir, "staged.txt")
+	gitAdd(t, tmpDir, "unstaged.txt")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	createFile(t, tmpDir, "staged.txt", "one\ntwo\n")
+	gitAdd(t, tmpDir, "staged.txt")
+	createFile(t, tmpDir, "unstaged.txt", "one\ntwo\n")
+	createFile(t, tmpDir, "untracked.txt", "one\n")
+	return tmpDir
+}
+
+func TestGetUncommittedFilesWithOptions_SelectsChangeKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		opts UncommittedOptions
+		want string
+	}{
+		{"none", UncommittedOptions{}, "(empty)"},
+		{"staged", UncommittedOptions{IncludeStaged: true}, "$REPO/staged.txt"},
+		{"unstaged", UncommittedOptions{IncludeUnstaged: true}, "$REPO/unstaged.txt"},
+		{"untracked", UncommittedOptions{IncludeUntracked: true}, "$REPO/untracked.txt"},
+		{"tracked", UncommittedOptions{IncludeStaged: true, IncludeUnstaged: true}, "$REPO/staged.txt\n$REPO/unstaged.txt"},
+		{"unstaged and untracked", UncommittedOptions{IncludeUnstaged: true, IncludeUntracked: true}, "$REPO/unstaged.txt\n$REPO/untracked.txt"},
+		{"all", AllUncommittedChanges(), "$REPO/staged.txt\n$REPO/unstaged.txt\n$REPO/untracked.txt"},
+	}
+	tmpDir := setupMixedChangesRepo(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := GetUncommittedFilesWithOptions(tmpDir, tt.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, normalizeFilePaths(tmpDir, files))
+		})
+	}
+}
+
+func TestGetUncommittedFilesWithOptions_PartiallyStagedFileMatchesBothSides(t *testing.T) {
+	tmpDir := setupPartiallyStagedRepo(t)
+
+	staged, err := GetUncommittedFilesWithOptions(tmpDir, UncommittedOptions{IncludeStaged: true})
+	require.NoError(t, err)
+	unstaged, err := GetUncommittedFilesWithOptions(tmpDir, UncommittedOptions{IncludeUnstaged: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/partial.txt", normalizeFilePaths(tmpDir, staged))
+	assert.Equal(t, "$REPO/partial.txt", normalizeFilePaths(tmpDir, unstaged))
 }
@@ -0,0 +1,272 @@
+//go:build integration
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain keeps host git configuration from leaking into tests that shell out to git.
+func TestMain(m *testing.M) {
+	isolateHostGitConfig()
+	os.Exit(m.Run())
+}
+
+func TestGetRepositoryRoot_FromSubdirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	// Create subdirectory
+	subDir := filepath.Join(tmpDir, "lib", "src")
+	err := os.MkdirAll(subDir, 0755)
+	require.NoError(t, err)
+
+	root, err := GetRepositoryRoot(subDir)
+
+	require.NoError(t, err)
+	resolvedTmp, _ := filepath.EvalSymlinks(tmpDir)
+	assert.Equal(t, resolvedTmp, root)
+}
+
+func TestGetCurrentCommitHash_MatchesHEAD(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	expectedHash := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+
+	hash, err := GetCurrentCommitHash(tmpDir)
+
+	require.NoError(t, err)
+	// The current hash should be a prefix of the full commit SHA
+	assert.True(t, strings.HasPrefix(expectedHash, hash), "current hash should match HEAD")
+}
+
+func TestGetShortCommitHash_Success(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	fullHash := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+
+	shortHash, err := GetShortCommitHash(tmpDir, fullHash)
+
+	require.NoError(t, err)
+	assert.True(t, len(shortHash) >= 7 && len(shortHash) <= 12)
+	assert.True(t, strings.HasPrefix(fullHash, shortHash))
+}
+
+func TestGetShortCommitHash_AlreadyShort(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	fullHash := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+
+	// Get short hash first
+	shortHash, err := GetShortCommitHash(tmpDir, fullHash[:7])
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, shortHash)
+}
+
+func TestGetShortCommitHash_HEAD(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	shortHash, err := GetShortCommitHash(tmpDir, "HEAD")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, shortHash)
+}
+
+func TestHasUncommittedChanges_ModifiedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	filePath := createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	// Modify the file
+	modifyFile(t, filePath)
+
+	hasChanges, err := HasUncommittedChanges(tmpDir)
+
+	require.NoError(t, err)
+	assert.True(t, hasChanges)
+}
+
+func TestHasUncommittedChanges_StagedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "first.txt", "content")
+	gitAdd(t, tmpDir, "first.txt")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	// Create and stage a new file
+	createFile(t, tmpDir, "staged.txt", "new content")
+	gitAdd(t, tmpDir, "staged.txt")
+
+	hasChanges, err := HasUncommittedChanges(tmpDir)
+
+	require.NoError(t, err)
+	assert.True(t, hasChanges)
+}
+
+func TestHasUncommittedChanges_EmptyRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	// Empty repo with no commits
+	hasChanges, err := HasUncommittedChanges(tmpDir)
+
+	require.NoError(t, err)
+	assert.False(t, hasChanges)
+}
+
+// Tests for ParseCommitRange
+
+func TestGetCommitRangeLabel_WithHEAD(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "first.txt", "content")
+	gitAdd(t, tmpDir, "first.txt")
+	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+
+	createFile(t, tmpDir, "second.txt", "content")
+	gitAdd(t, tmpDir, "second.txt")
+	gitCommit(t, tmpDir, "Second commit")
+
+	label, err := GetCommitRangeLabel(tmpDir, firstCommit, "HEAD")
+
+	require.NoError(t, err)
+	assert.Contains(t, label, "...")
+}
+
+func TestGetCommitRangeLabel_InvalidToCommit(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
+
+	_, err := GetCommitRangeLabel(tmpDir, commit, "invalid-sha")
+
+	assert.Error(t, err)
+}
+
+func TestBlobSHAs_WorkingTreeMatchesCommittedTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "lib"), 0755))
+	createFile(t, tmpDir, "main.go", "package main\n")
+	createFile(t, tmpDir, "lib/util.go", "package lib\n")
+	gitAdd(t, tmpDir, "main.go")
+	gitAdd(t, tmpDir, "lib/util.go")
+	commitID := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+
+	committed, err := GetCommitBlobSHAs(tmpDir, commitID)
+	require.NoError(t, err)
+
+	files := []string{filepath.Join(tmpDir, "main.go"), filepath.Join(tmpDir, "lib", "util.go")}
+	working, err := GetWorkingTreeBlobSHAs(tmpDir, files)
+	require.NoError(t, err)
+
+	assert.Equal(t, committed, working)
+}
+
+func TestGetWorkingTreeBlobSHAs_HashesMoreFilesThanFitOnACommandLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	// 12,000 paths of over 200 bytes each add up to more than the 2 MiB Linux allows
+	// for a command line.
+	prefix := strings.Repeat("x", 200)
+	files := make([]string, 0, 12000)
+	for i := range 12000 {
+		path := filepath.Join(tmpDir, fmt.Sprintf("%s%05d.go", prefix, i))
+		require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o644))
+		files = append(files, path)
+	}
+
+	blobs, err := GetWorkingTreeBlobSHAs(tmpDir, files)
+	require.NoError(t, err)
+
+	require.Len(t, blobs, len(files))
+	assert.Equal(t, blobs[files[0]], blobs[files[len(files)-1]])
+}
+
+func TestGitCommitContentReader_CommitViewWinsOverWorkingTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "go.mod", "module example.com/app\n")
+	gitAdd(t, tmpDir, "go.mod")
+	commitID := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+
+	// The working tree deliberately disagrees with the commit.
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "go.mod")))
+	createFile(t, tmpDir, "local.go", "package main\n")
+
+	reader := GitCommitContentReader(tmpDir, commitID)
+
+	assert.True(t, reader.Exists(filepath.Join(tmpDir, "go.mod")))
+	assert.False(t, reader.Exists(filepath.Join(tmpDir, "local.go")))
+	names, err := reader.ListDir(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod"}, names)
+	content, err := reader.ReadFile(filepath.Join(tmpDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "module example.com/app\n", string(content))
+}
+
+func TestHasUncommittedChangesWithOptions_SelectsChangeKinds(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "committed.txt", "content")
+	gitAdd(t, tmpDir, "committed.txt")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	// Only an untracked file differs from HEAD.
+	createFile(t, tmpDir, "untracked.txt", "content")
+
+	tests := []struct {
+		name string
+		opts UncommittedOptions
+		want bool
+	}{
+		{"staged", UncommittedOptions{IncludeStaged: true}, false},
+		{"unstaged", UncommittedOptions{IncludeUnstaged: true}, false},
+		{"tracked", UncommittedOptions{IncludeStaged: true, IncludeUnstaged: true}, false},
+		{"untracked", UncommittedOptions{IncludeUntracked: true}, true},
+		{"all", AllUncommittedChanges(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasChanges, err := HasUncommittedChangesWithOptions(tmpDir, tt.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hasChanges)
+		})
+	}
+}
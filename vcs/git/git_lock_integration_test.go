@@ -0,0 +1,40 @@
+//go:build integration
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOperations_SucceedWhileIndexIsLocked(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+
+	createFile(t, dir, "main.go", "package main\n")
+	gitAdd(t, dir, "main.go")
+	gitCommit(t, dir, "initial")
+	createFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+
+	// Simulate an editor's git integration holding the index lock.
+	lockPath := filepath.Join(dir, ".git", "index.lock")
+	require.NoError(t, os.WriteFile(lockPath, nil, 0o644))
+
+	dirty, err := HasUncommittedChanges(dir)
+	require.NoError(t, err)
+	assert.True(t, dirty)
+
+	files, err := GetUncommittedFiles(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	_, err = GetRepositoryStateSignature(dir)
+	require.NoError(t, err)
+
+	_, err = os.Stat(lockPath)
+	assert.NoError(t, err, "clarity must not touch another process's lock file")
+}
@@ -0,0 +1,186 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommitInfo identifies a commit in a branch history.
+type CommitInfo struct {
+	SHA  string
+	Tree string
+	Date time.Time
+}
+
+// ListFirstParentCommits returns the commits on the first-parent history of branch,
+// oldest first. When since is set, only commits committed on or after it are returned;
+// since accepts any date git understands, and a bare date such as 2024-01-01 means the
+// start of that day.
+func ListFirstParentCommits(repoPath, branch, since string) ([]CommitInfo, error) {
+	return ListFirstParentCommitsContext(context.Background(), repoPath, branch, since)
+}
+
+// ListFirstParentCommitsContext lists commits like ListFirstParentCommits, stopping
+// git when ctx is done.
+func ListFirstParentCommitsContext(ctx context.Context, repoPath, branch, since string) ([]CommitInfo, error) {
+	if err := validateGitRef(branch); err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "--first-parent", "--reverse", "--format=%H %T %cI"}
+	if since != "" {
+		args = append(args, "--since="+sinceArg(since))
+	}
+	args = append(args, branch, "--")
+
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, args...)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	var commits []CommitInfo
+	for _, line := range strings.Split(string(stdout), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git log output: %q", line)
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log date %q: %w", fields[2], err)
+		}
+		commits = append(commits, CommitInfo{SHA: fields[0], Tree: fields[1], Date: date})
+	}
+	return commits, nil
+}
+
+// sinceArg returns the --since value for since. git fills in the current time of day
+// for a bare date, which would drop the commits made earlier that day, so a bare date
+// is given as the start of the day.
+func sinceArg(since string) string {
+	if _, err := time.Parse(time.DateOnly, since); err == nil {
+		return since + "T00:00:00"
+	}
+	return since
+}
+
+// FileCommit is a commit that changed a file, as listed by GetCommitsTouchingFile.
+type FileCommit struct {
+	SHA  string
+	Date time.Time
+	// Path is the path of the file at the commit, relative to the repository root with
+	// forward slashes. It is the old path for commits made before a rename.
+	Path string
+	// CoChanged lists, sorted, the other files the commit changed, relative to the
+	// repository root with forward slashes. It is empty for merge commits.
+	CoChanged []string
+}
+
+// GetCommitsTouchingFile returns the commits that changed filePath, newest first,
+// following the file across renames. filePath is relative to the repository root.
+// A limit of 0 returns every commit.
+func GetCommitsTouchingFile(repoPath, filePath string, limit int) ([]FileCommit, error) {
+	if err := validateGitRelPath(filePath); err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("commit limit must be at least 0, got %d", limit)
+	}
+
+	repoRoot, err := GetRepositoryRoot(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every record starts with a NUL so commit headers cannot be confused with paths.
+	args := []string{"log", "--follow", "--name-only", "--format=%x00%H %cI"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	args = append(args, "--", filepath.ToSlash(filePath))
+	stdout, stderr, err := runGitCommand(repoRoot, args...)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	var commits []FileCommit
+	for _, record := range logRecords(stdout) {
+		fields := strings.Fields(record.header)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("unexpected git log output: %q", record.header)
+		}
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log date %q: %w", fields[1], err)
+		}
+		commit := FileCommit{SHA: fields[0], Date: date, Path: filepath.ToSlash(filePath)}
+		if len(record.paths) > 0 {
+			commit.Path = record.paths[0]
+		}
+		commits = append(commits, commit)
+	}
+	if len(commits) == 0 {
+		return commits, nil
+	}
+
+	// --follow limits the listed paths to the followed file, so the other files of each
+	// commit come from a second log over exactly those commits.
+	args = []string{"log", "--no-walk=unsorted", "--name-only", "--format=%x00%H"}
+	for _, commit := range commits {
+		args = append(args, commit.SHA)
+	}
+	args = append(args, "--")
+	stdout, stderr, err = runGitCommand(repoRoot, args...)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	changed := make(map[string][]string, len(commits))
+	for _, record := range logRecords(stdout) {
+		changed[strings.TrimSpace(record.header)] = record.paths
+	}
+	for i, commit := range commits {
+		coChanged := []string{}
+		for _, path := range changed[commit.SHA] {
+			if path != commit.Path {
+				coChanged = append(coChanged, path)
+			}
+		}
+		sort.Strings(coChanged)
+		commits[i].CoChanged = coChanged
+	}
+	return commits, nil
+}
+
+// logRecord is one commit of git log output written with a NUL-prefixed format and
+// --name-only.
+type logRecord struct {
+	header string
+	paths  []string
+}
+
+func logRecords(stdout []byte) []logRecord {
+	var records []logRecord
+	for _, chunk := range strings.Split(string(stdout), "\x00") {
+		lines := strings.Split(chunk, "\n")
+		if strings.TrimSpace(lines[0]) == "" {
+			continue
+		}
+		record := logRecord{header: lines[0]}
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				record.paths = append(record.paths, unquoteGitPath(line))
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
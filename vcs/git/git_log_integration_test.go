@@ -0,0 +1,109 @@
+//go:build integration
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRenamedFileHistory commits a.go four times: with b.go, with c.go, when it is
+// renamed to x.go alongside b.go, and as x.go with c.go. A last commit changes only
+// b.go.
+func setupRenamedFileHistory(t *testing.T) string {
+	repoDir := t.TempDir()
+	setupGitRepo(t, repoDir)
+
+	createFile(t, repoDir, "a.go", "package a\n")
+	createFile(t, repoDir, "b.go", "package a\n")
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Add a and b")
+
+	createFile(t, repoDir, "a.go", "package a\n\nconst A = 1\n")
+	createFile(t, repoDir, "c.go", "package a\n")
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Change a, add c")
+
+	gitMove(t, repoDir, "a.go", "x.go")
+	createFile(t, repoDir, "b.go", "package a\n\nconst B = 1\n")
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Rename a to x")
+
+	createFile(t, repoDir, "x.go", "package a\n\nconst A = 2\n")
+	createFile(t, repoDir, "c.go", "package a\n\nconst C = 1\n")
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Change x and c")
+
+	createFile(t, repoDir, "b.go", "package a\n\nconst B = 2\n")
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Change b")
+
+	return repoDir
+}
+
+func gitMove(t *testing.T, repoDir, from, to string) {
+	cmd := exec.Command("git", "mv", from, to)
+	cmd.Dir = repoDir
+	require.NoError(t, cmd.Run(), "failed to git mv %s %s", from, to)
+}
+
+func TestGetCommitsTouchingFile_FollowsRenames(t *testing.T) {
+	repoDir := setupRenamedFileHistory(t)
+
+	commits, err := GetCommitsTouchingFile(repoDir, "x.go", 0)
+
+	require.NoError(t, err)
+	require.Len(t, commits, 4)
+	var paths [][]string
+	for _, commit := range commits {
+		assert.Len(t, commit.SHA, 40)
+		assert.False(t, commit.Date.IsZero())
+		paths = append(paths, append([]string{commit.Path}, commit.CoChanged...))
+	}
+	assert.Equal(t, [][]string{
+		{"x.go", "c.go"},
+		{"x.go", "b.go"},
+		{"a.go", "c.go"},
+		{"a.go", "b.go"},
+	}, paths)
+}
+
+func TestGetCommitsTouchingFile_Limit(t *testing.T) {
+	repoDir := setupRenamedFileHistory(t)
+
+	commits, err := GetCommitsTouchingFile(repoDir, "x.go", 2)
+
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, []string{"c.go"}, commits[0].CoChanged)
+	assert.Equal(t, []string{"b.go"}, commits[1].CoChanged)
+}
+
+func TestGetCommitsTouchingFile_FromSubdirectory(t *testing.T) {
+	repoDir := setupRenamedFileHistory(t)
+	createFile(t, repoDir, "docs.md", "# docs\n")
+	subDir := filepath.Join(repoDir, "pkg")
+	require.NoError(t, os.Mkdir(subDir, 0o755))
+	createFile(t, repoDir, "pkg/y.go", "package pkg\n")
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Add pkg")
+
+	commits, err := GetCommitsTouchingFile(subDir, "pkg/y.go", 0)
+
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "pkg/y.go", commits[0].Path)
+	assert.Equal(t, []string{"docs.md"}, commits[0].CoChanged)
+}
+
+func TestGetCommitsTouchingFile_RejectsAbsolutePath(t *testing.T) {
+	_, err := GetCommitsTouchingFile(t.TempDir(), "/abs/x.go", 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git path must be relative")
+}
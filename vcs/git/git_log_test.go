@@ -0,0 +1,45 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFirstParentCommits_ParsesLog(t *testing.T) {
+	useFakeGitRunner(t).
+		on("log --first-parent --reverse --format=%H %T %cI --since=2024-01-01T00:00:00 main --", fakeGitResponse{
+			stdout: "aaaa1111 tree1111 2024-01-02T10:00:00+01:00\n" +
+				"bbbb2222 tree2222 2024-01-09T08:30:00Z\n",
+		})
+
+	commits, err := ListFirstParentCommits("/repo", "main", "2024-01-01")
+
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "aaaa1111", commits[0].SHA)
+	assert.Equal(t, "tree1111", commits[0].Tree)
+	assert.True(t, commits[0].Date.Equal(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "bbbb2222", commits[1].SHA)
+}
+
+func TestListFirstParentCommits_PassesOtherSinceValuesThrough(t *testing.T) {
+	useFakeGitRunner(t).
+		on("log --first-parent --reverse --format=%H %T %cI --since=2.weeks.ago main --", fakeGitResponse{})
+
+	commits, err := ListFirstParentCommits("/repo", "main", "2.weeks.ago")
+
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+}
+
+func TestListFirstParentCommits_RejectsOptionLikeBranch(t *testing.T) {
+	runner := useFakeGitRunner(t)
+
+	_, err := ListFirstParentCommits("/repo", "--output=x", "")
+
+	require.Error(t, err)
+	assert.Empty(t, runner.calls)
+}
@@ -1,35 +1,81 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// gitlinkMode is the index and tree mode of a submodule entry.
+const gitlinkMode = "160000"
+
 // ListTrackedFiles returns absolute paths for files tracked in the git index.
+// Submodules are skipped: they are tracked as gitlinks, and their paths are
+// directories.
 func ListTrackedFiles(repoPath string) ([]string, error) {
-	repoRoot, err := ensureRepoRoot(repoPath)
+	return ListTrackedFilesContext(context.Background(), repoPath)
+}
+
+// ListTrackedFilesContext lists tracked files like ListTrackedFiles, stopping git
+// when ctx is done.
+func ListTrackedFilesContext(ctx context.Context, repoPath string) ([]string, error) {
+	repoRoot, err := ensureRepoRoot(ctx, repoPath)
 	if err != nil {
 		return nil, err
 	}
 
-	stdout, stderr, err := runGitCommand(repoPath, "ls-files", "-z", "--cached")
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "ls-files", "-z", "--cached", "--stage")
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
 
-	return toAbsolutePaths(repoRoot, parseNullSeparatedPaths(stdout)), nil
+	files, err := parseStagedFiles(stdout)
+	if err != nil {
+		return nil, err
+	}
+	return toAbsolutePaths(repoRoot, files), nil
+}
+
+// parseStagedFiles parses NUL-terminated "<mode> <object> <stage>\t<path>" records
+// from git ls-files --stage. Gitlinks are skipped, and paths listed once per stage
+// during a merge conflict are returned once.
+func parseStagedFiles(output []byte) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, record := range strings.Split(string(output), "\x00") {
+		if record == "" {
+			continue
+		}
+		header, path, ok := strings.Cut(record, "\t")
+		if !ok {
+			return nil, fmt.Errorf("unexpected git ls-files output: %q", record)
+		}
+		mode, _, _ := strings.Cut(header, " ")
+		if mode == gitlinkMode || seen[path] {
+			continue
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+	return files, nil
 }
 
 // ListUntrackedFiles returns absolute paths for non-ignored untracked files.
 func ListUntrackedFiles(repoPath string) ([]string, error) {
-	repoRoot, err := ensureRepoRoot(repoPath)
+	return ListUntrackedFilesContext(context.Background(), repoPath)
+}
+
+// ListUntrackedFilesContext lists untracked files like ListUntrackedFiles, stopping
+// git when ctx is done.
+func ListUntrackedFilesContext(ctx context.Context, repoPath string) ([]string, error) {
+	repoRoot, err := ensureRepoRoot(ctx, repoPath)
 	if err != nil {
 		return nil, err
 	}
 
-	stdout, stderr, err := runGitCommand(repoPath, "ls-files", "-z", "--others", "--exclude-standard")
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "ls-files", "-z", "--others", "--exclude-standard")
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
@@ -40,7 +86,8 @@ func ListUntrackedFiles(repoPath string) ([]string, error) {
 // ResolveFirstParent resolves the first parent of a commit.
 // Returns hasParent=false for root commits.
 func ResolveFirstParent(repoPath, commitID string) (parent string, hasParent bool, err error) {
-	if err := validateCommit(repoPath, commitID); err != nil {
+	commitID, err = ResolveRef(repoPath, commitID)
+	if err != nil {
 		return "", false, err
 	}
 
@@ -60,15 +107,15 @@ func ResolveFirstParent(repoPath, commitID string) (parent string, hasParent boo
 	return fields[1], true, nil
 }
 
-func ensureRepoRoot(repoPath string) (string, error) {
+func ensureRepoRoot(ctx context.Context, repoPath string) (string, error) {
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("repository path does not exist: %s", repoPath)
+		return "", repoPathMissingError(repoPath)
 	}
-	if !isGitRepository(repoPath) {
-		return "", fmt.Errorf("%s is not a git repository (use 'git init' to initialize)", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return "", err
 	}
 
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to get repository root: %w", err)
 	}
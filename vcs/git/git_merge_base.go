@@ -0,0 +1,55 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
+
+// GetMergeBase returns the full SHA of the best common ancestor of two commits, the
+// commit a three-dot range "base...head" is compared from.
+func GetMergeBase(repoPath, base, head string) (string, error) {
+	return getMergeBase(context.Background(), repoPath, base, head)
+}
+
+func getMergeBase(ctx context.Context, repoPath, base, head string) (string, error) {
+	if err := validateGitRef(base); err != nil {
+		return "", err
+	}
+	if err := validateGitRef(head); err != nil {
+		return "", err
+	}
+
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "merge-base", base, head)
+	if err != nil {
+		if exitCode(err) == 1 {
+			return "", fmt.Errorf("commits '%s' and '%s' have no common ancestor", base, head)
+		}
+		return "", gitCommandError(err, stderr)
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// GetMergeBaseRangeFiles finds all files changed on head since it diverged from base,
+// leaving out changes made only on base.
+// Returns absolute paths to all files added, modified, or renamed since the merge-base.
+func GetMergeBaseRangeFiles(repoPath, base, head string) ([]string, error) {
+	mergeBase, err := GetMergeBase(repoPath, base, head)
+	if err != nil {
+		return nil, err
+	}
+	return GetCommitRangeFiles(repoPath, mergeBase, head)
+}
+
+// GetMergeBaseRangeFileStats returns statistics for files changed on head since it
+// diverged from base, keyed by absolute file path.
+func GetMergeBaseRangeFileStats(repoPath, base, head string) (map[string]vcs.FileStats, error) {
+	mergeBase, err := GetMergeBase(repoPath, base, head)
+	if err != nil {
+		return nil, err
+	}
+	return GetCommitRangeFileStats(repoPath, mergeBase, head)
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDivergedBranches creates branch base with shared.go, then commits base.go on
+// base and feature.go on a feature branch forked before it.
+func setupDivergedBranches(t *testing.T) string {
+	repoDir := t.TempDir()
+	setupGitRepo(t, repoDir)
+	gitCheckout(t, repoDir, "-b", "base")
+
+	createFile(t, repoDir, "shared.go", "package shared\n")
+	gitAdd(t, repoDir, "shared.go")
+	gitCommit(t, repoDir, "Initial commit")
+
+	gitCheckout(t, repoDir, "-b", "feature")
+	createFile(t, repoDir, "feature.go", "package shared\n")
+	gitAdd(t, repoDir, "feature.go")
+	gitCommit(t, repoDir, "Add feature")
+
+	gitCheckout(t, repoDir, "base")
+	createFile(t, repoDir, "base.go", "package shared\n")
+	modifyFile(t, filepath.Join(repoDir, "shared.go"))
+	gitAdd(t, repoDir, ".")
+	gitCommit(t, repoDir, "Change base")
+
+	return repoDir
+}
+
+func gitCheckout(t *testing.T, repoDir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"checkout", "-q"}, args...)...)
+	cmd.Dir = repoDir
+	require.NoError(t, cmd.Run(), "failed to git checkout %v", args)
+}
+
+func TestGetMergeBaseRangeFiles_ExcludesChangesOnlyOnBase(t *testing.T) {
+	repoDir := setupDivergedBranches(t)
+
+	files, err := GetMergeBaseRangeFiles(repoDir, "base", "feature")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/feature.go", normalizeFilePaths(repoDir, files))
+
+	// A plain range diff reports the base-only changes as reverted on feature
+	rangeFiles, err := GetCommitRangeFiles(repoDir, "base", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/feature.go\n$REPO/shared.go", normalizeFilePaths(repoDir, rangeFiles))
+}
+
+func TestGetMergeBaseRangeFileStats_ExcludesChangesOnlyOnBase(t *testing.T) {
+	repoDir := setupDivergedBranches(t)
+
+	stats, err := GetMergeBaseRangeFileStats(repoDir, "base", "feature")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/feature.go: +1 -0 new=true", normalizeFileStats(repoDir, stats))
+}
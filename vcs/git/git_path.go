@@ -0,0 +1,91 @@
+package git
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// unquoteGitPath returns the file name git printed as path. Git wraps names that
+// contain a double quote, a backslash or a control character in double quotes with
+// C-style escapes, and with core.quotepath enabled it also escapes every byte above
+// 0x7f as octal. Names that are not quoted, or that cannot be unquoted, are returned
+// unchanged.
+func unquoteGitPath(path string) string {
+	if len(path) < 2 || !strings.HasPrefix(path, `"`) || !strings.HasSuffix(path, `"`) {
+		return path
+	}
+	unquoted, err := strconv.Unquote(path)
+	if err != nil {
+		return path
+	}
+	return unquoted
+}
+
+// splitPorcelainRename returns the old and new names of a git status --porcelain
+// entry, "old -> new" for renames and copies. Each side is quoted on its own. For
+// entries that are not renames both names are the entry itself.
+func splitPorcelainRename(entry string) (string, string) {
+	if oldPath, newPath, ok := strings.Cut(entry, " -> "); ok {
+		return unquoteGitPath(oldPath), unquoteGitPath(newPath)
+	}
+	path := unquoteGitPath(entry)
+	return path, path
+}
+
+// withExtensionPathspecs appends to the git arguments args one pathspec per extension
+// in exts, such as :(top,icase)*.go for ".go", so git lists only files with those
+// extensions anywhere in the repository. Without exts, args are returned unchanged.
+func withExtensionPathspecs(args []string, exts []string) []string {
+	if len(exts) == 0 {
+		return args
+	}
+	args = append(args, "--")
+	for _, ext := range exts {
+		args = append(args, ":(top,icase)*"+escapePathspecGlob(normalizeGitExtension(ext)))
+	}
+	return args
+}
+
+// keepGitExtensions returns the repo-relative files whose extension, compared
+// case-insensitively, is one of exts. A wildcard pathspec may also match a file
+// below a directory named like an extension, which this drops. An empty exts keeps
+// every file.
+func keepGitExtensions(files []string, exts []string) []string {
+	if len(exts) == 0 {
+		return files
+	}
+	wanted := make(map[string]bool, len(exts))
+	for _, ext := range exts {
+		wanted[normalizeGitExtension(ext)] = true
+	}
+	kept := files[:0]
+	for _, file := range files {
+		if wanted[strings.ToLower(path.Ext(file))] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// normalizeGitExtension returns ext lowercased with its leading dot, as in ".go" for
+// "GO".
+func normalizeGitExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// escapePathspecGlob escapes the characters a wildcard pathspec gives meaning to.
+func escapePathspecGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
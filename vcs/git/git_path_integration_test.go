@@ -0,0 +1,95 @@
+//go:build integration
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unusualFileNames are names git quotes by default: runs of spaces, non-ASCII
+// characters, and a double quote, which git quotes even with core.quotepath off.
+var unusualFileNames = []string{"my  file.dart", "日本語.dart", `we"ird.dart`}
+
+// setupUnusualFileNamesRepo commits plain.dart, then commits unusualFileNames and
+// leaves each of them modified in the working tree. It returns the SHAs of both
+// commits.
+func setupUnusualFileNamesRepo(t *testing.T) (string, string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "plain.dart", "class Plain {}\n")
+	gitAdd(t, tmpDir, "plain.dart")
+	firstCommit := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+
+	for _, name := range unusualFileNames {
+		createFile(t, tmpDir, name, "class A {}\n")
+		gitAdd(t, tmpDir, name)
+	}
+	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Add unusual names")
+
+	for _, name := range unusualFileNames {
+		createFile(t, tmpDir, name, "class A {}\nclass B {}\n")
+	}
+	return tmpDir, firstCommit, secondCommit
+}
+
+func unusualFilePaths(t *testing.T, tmpDir string) []string {
+	t.Helper()
+	repoRoot, err := GetRepositoryRoot(tmpDir)
+	require.NoError(t, err)
+	var paths []string
+	for _, name := range unusualFileNames {
+		paths = append(paths, filepath.Join(repoRoot, name))
+	}
+	return paths
+}
+
+func TestUnusualFileNames_ChangedFiles(t *testing.T) {
+	tmpDir, firstCommit, secondCommit := setupUnusualFileNamesRepo(t)
+	want := unusualFilePaths(t, tmpDir)
+
+	uncommitted, err := GetUncommittedFiles(tmpDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, uncommitted)
+
+	commitFiles, err := GetCommitDartFiles(tmpDir, secondCommit)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, commitFiles)
+
+	rangeFiles, err := GetCommitRangeFiles(tmpDir, firstCommit, secondCommit)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, rangeFiles)
+
+	treeFiles, err := GetCommitTreeFiles(tmpDir, secondCommit)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append(want, filepath.Join(filepath.Dir(want[0]), "plain.dart")), treeFiles)
+}
+
+func TestUnusualFileNames_Stats(t *testing.T) {
+	tmpDir, firstCommit, secondCommit := setupUnusualFileNamesRepo(t)
+	want := unusualFilePaths(t, tmpDir)
+
+	uncommitted, err := GetUncommittedFileStats(tmpDir)
+	require.NoError(t, err)
+	commitStats, err := GetCommitFileStats(tmpDir, secondCommit)
+	require.NoError(t, err)
+	rangeStats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
+	require.NoError(t, err)
+
+	for _, path := range want {
+		assert.Equal(t, 1, uncommitted[path].Additions, path)
+		assert.False(t, uncommitted[path].IsNew, path)
+		assert.Equal(t, 1, commitStats[path].Additions, path)
+		assert.True(t, commitStats[path].IsNew, path)
+		assert.Equal(t, 1, rangeStats[path].Additions, path)
+		assert.True(t, rangeStats[path].IsNew, path)
+	}
+	assert.Len(t, uncommitted, len(want))
+	assert.Len(t, commitStats, len(want))
+	assert.Len(t, rangeStats, len(want))
+}
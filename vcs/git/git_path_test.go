@@ -0,0 +1,54 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnquoteGitPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "plain", path: "lib/main.dart", want: "lib/main.dart"},
+		{name: "spaces are not quoted", path: "lib/my file.dart", want: "lib/my file.dart"},
+		{name: "escaped quote", path: `"lib/we\"ird.dart"`, want: `lib/we"ird.dart`},
+		{name: "escaped tab", path: `"lib/tab\tx.dart"`, want: "lib/tab\tx.dart"},
+		{name: "octal escaped UTF-8", path: `"lib/\346\227\245\346\234\254.dart"`, want: "lib/日本.dart"},
+		{name: "lone quote", path: `"`, want: `"`},
+		{name: "malformed escape", path: `"lib/\q.dart"`, want: `"lib/\q.dart"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, unquoteGitPath(tt.path))
+		})
+	}
+}
+
+func TestSplitPorcelainRename(t *testing.T) {
+	oldPath, newPath := splitPorcelainRename(`"we\"ird.dart" -> "sp  ace.dart"`)
+	assert.Equal(t, `we"ird.dart`, oldPath)
+	assert.Equal(t, "sp  ace.dart", newPath)
+
+	oldPath, newPath = splitPorcelainRename("日本語.dart")
+	assert.Equal(t, "日本語.dart", oldPath)
+	assert.Equal(t, "日本語.dart", newPath)
+}
+
+func TestWithExtensionPathspecs(t *testing.T) {
+	args := []string{"diff-tree", "HEAD"}
+
+	assert.Equal(t, args, withExtensionPathspecs(args, nil))
+	assert.Equal(t,
+		[]string{"diff-tree", "HEAD", "--", ":(top,icase)*.go", ":(top,icase)*.dart", `:(top,icase)*.a\*b`},
+		withExtensionPathspecs(args, []string{".go", "DART", "a*b"}))
+}
+
+func TestKeepGitExtensions(t *testing.T) {
+	files := []string{"main.go", "lib/App.DART", "build.go/notes.txt", "README.md"}
+
+	assert.Equal(t, []string{"main.go", "lib/App.DART"}, keepGitExtensions(files, []string{".go", "dart"}))
+}
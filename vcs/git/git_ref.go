@@ -0,0 +1,224 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxRefSuggestions bounds how many close matches an unknown ref error names.
+	maxRefSuggestions = 3
+	// remoteRefPrefix is where git keeps remote-tracking branches.
+	remoteRefPrefix = "refs/remotes/"
+)
+
+// RefError reports a commit reference that names no commit, or more than one. It
+// wraps ErrInvalidCommit.
+type RefError struct {
+	// Ref is the reference as given.
+	Ref string
+	// Ambiguous is set when Ref matches several commits, which Candidates lists;
+	// otherwise Ref matches none and Candidates lists the refs with the closest names.
+	Ambiguous  bool
+	Candidates []string
+}
+
+func (e *RefError) Error() string {
+	quoted := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		quoted[i] = "'" + candidate + "'"
+	}
+	if e.Ambiguous {
+		return fmt.Sprintf("%s '%s': ambiguous ref matching %s", ErrInvalidCommit, e.Ref, strings.Join(quoted, ", "))
+	}
+	if len(quoted) == 0 {
+		return fmt.Sprintf("%s '%s': unknown ref", ErrInvalidCommit, e.Ref)
+	}
+	return fmt.Sprintf("%s '%s': unknown ref (did you mean %s?)", ErrInvalidCommit, e.Ref, strings.Join(quoted, " or "))
+}
+
+func (e *RefError) Unwrap() error {
+	return ErrInvalidCommit
+}
+
+// ResolveRef returns the full SHA of the commit ref names. A ref may be a full or
+// short SHA, a tag, a local branch, a remote-tracking branch such as origin/main, or
+// a revision expression such as HEAD~2. A branch name that exists only on one remote,
+// such as feature for origin/feature, resolves to that remote-tracking branch.
+// Unknown and ambiguous refs fail with a *RefError.
+func ResolveRef(repoPath, ref string) (string, error) {
+	return resolveRef(context.Background(), repoPath, ref)
+}
+
+func resolveRef(ctx context.Context, repoPath, ref string) (string, error) {
+	if err := validateGitRef(ref); err != nil {
+		return "", classify(ErrInvalidCommit, err)
+	}
+
+	sha, stderr, err := revParseCommit(ctx, repoPath, ref)
+	if err == nil {
+		return sha, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if candidates := ambiguousCommitCandidates(stderr); len(candidates) > 0 {
+		return "", &RefError{Ref: ref, Ambiguous: true, Candidates: candidates}
+	}
+
+	// Without the ref list there is no fallback or suggestion, only the plain error.
+	refNames, _ := listRefNames(ctx, repoPath)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	switch remoteRefs := remoteTrackingRefs(refNames, ref); len(remoteRefs) {
+	case 0:
+	case 1:
+		if sha, _, err := revParseCommit(ctx, repoPath, remoteRefs[0]); err == nil {
+			return sha, nil
+		}
+	default:
+		return "", &RefError{Ref: ref, Ambiguous: true, Candidates: shortRefNames(remoteRefs)}
+	}
+
+	if isShallowRepository(ctx, repoPath) {
+		return "", fmt.Errorf("%w '%s': commit not present in shallow clone (run 'git fetch --unshallow' to fetch the full history)", ErrInvalidCommit, ref)
+	}
+	return "", &RefError{Ref: ref, Candidates: closeRefNames(refNames, ref)}
+}
+
+// revParseCommit returns the full SHA of the commit ref names, and git's stderr when
+// it names none.
+func revParseCommit(ctx context.Context, repoPath, ref string) (string, string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", stderr, err
+	}
+	return strings.TrimSpace(string(stdout)), stderr, nil
+}
+
+// ambiguousCommitCandidates returns the abbreviated commits git lists in stderr when
+// a short SHA matches more than one of them.
+func ambiguousCommitCandidates(stderr string) []string {
+	if !strings.Contains(stderr, "is ambiguous") {
+		return nil
+	}
+	var candidates []string
+	for _, line := range strings.Split(stderr, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "hint:"))
+		if len(fields) >= 2 && fields[1] == "commit" {
+			candidates = append(candidates, fields[0])
+		}
+	}
+	return candidates
+}
+
+// listRefNames returns the full names of the branches, remote-tracking branches, and
+// tags of the repository.
+func listRefNames(ctx context.Context, repoPath string) ([]string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes", "refs/tags")
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+	return strings.Fields(string(stdout)), nil
+}
+
+// remoteTrackingRefs returns the remote-tracking branches named branch, one per remote
+// that has it.
+func remoteTrackingRefs(refNames []string, branch string) []string {
+	var matches []string
+	for _, refName := range refNames {
+		rest, ok := strings.CutPrefix(refName, remoteRefPrefix)
+		if !ok {
+			continue
+		}
+		if _, name, ok := strings.Cut(rest, "/"); ok && name == branch {
+			matches = append(matches, refName)
+		}
+	}
+	return matches
+}
+
+// shortRefNames strips the refs/heads/, refs/tags/, and refs/remotes/ prefixes, giving
+// names such as main, v1.2.0, and origin/main. The HEAD of remotes is left out.
+func shortRefNames(refNames []string) []string {
+	short := make([]string, 0, len(refNames))
+	for _, refName := range refNames {
+		if name := shortRefName(refName); !strings.HasSuffix(name, "/HEAD") {
+			short = append(short, name)
+		}
+	}
+	return short
+}
+
+func shortRefName(refName string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", remoteRefPrefix} {
+		if name, ok := strings.CutPrefix(refName, prefix); ok {
+			return name
+		}
+	}
+	return refName
+}
+
+// closeRefNames returns the short names of up to maxRefSuggestions of refNames within
+// a third of ref's length, and at least two edits, of ref, closest first and then by
+// name. A remote-tracking branch is also matched by its name without the remote, the
+// way ResolveRef falls back to it.
+func closeRefNames(refNames []string, ref string) []string {
+	maxDistance := max(2, len(ref)/3)
+	distances := make(map[string]int)
+	for _, refName := range refNames {
+		name := shortRefName(refName)
+		if strings.HasSuffix(name, "/HEAD") {
+			continue
+		}
+		distance := editDistance(name, ref)
+		if rest, ok := strings.CutPrefix(refName, remoteRefPrefix); ok {
+			if _, branch, ok := strings.Cut(rest, "/"); ok {
+				distance = min(distance, editDistance(branch, ref))
+			}
+		}
+		if previous, seen := distances[name]; distance <= maxDistance && (!seen || distance < previous) {
+			distances[name] = distance
+		}
+	}
+
+	matches := make([]string, 0, len(distances))
+	for name := range distances {
+		matches = append(matches, name)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if distances[matches[i]] != distances[matches[j]] {
+			return distances[matches[i]] < distances[matches[j]]
+		}
+		return matches[i] < matches[j]
+	})
+	if len(matches) > maxRefSuggestions {
+		matches = matches[:maxRefSuggestions]
+	}
+	return matches
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
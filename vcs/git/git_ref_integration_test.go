@@ -0,0 +1,92 @@
+//go:build integration
+
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupClonedRepo commits main.go on the default branch and feature.go on branch
+// feature, tags the first commit v1.0.0, and clones the repository, so the clone has
+// feature only as the remote-tracking branch origin/feature.
+func setupClonedRepo(t *testing.T) (cloneDir, tagCommit, featureCommit string) {
+	repoDir := t.TempDir()
+	setupGitRepo(t, repoDir)
+
+	createFile(t, repoDir, "main.go", "package refs\n")
+	gitAdd(t, repoDir, "main.go")
+	tagCommit = gitCommitAndGetSHA(t, repoDir, "Add main.go")
+	gitTag(t, repoDir, "v1.0.0")
+
+	gitCheckout(t, repoDir, "-b", "feature")
+	createFile(t, repoDir, "feature.go", "package refs\n")
+	gitAdd(t, repoDir, "feature.go")
+	featureCommit = gitCommitAndGetSHA(t, repoDir, "Add feature.go")
+	gitCheckout(t, repoDir, "-")
+
+	cloneDir = filepath.Join(t.TempDir(), "clone")
+	cmd := exec.Command("git", "clone", "-q", "file://"+repoDir, cloneDir)
+	require.NoError(t, cmd.Run(), "failed to clone")
+
+	return cloneDir, tagCommit, featureCommit
+}
+
+func gitTag(t *testing.T, repoDir, name string) {
+	cmd := exec.Command("git", "tag", "-a", name, "-m", name)
+	cmd.Dir = repoDir
+	require.NoError(t, cmd.Run(), "failed to create tag %s", name)
+}
+
+func TestResolveRef_AnnotatedTagResolvesToItsCommit(t *testing.T) {
+	cloneDir, tagCommit, _ := setupClonedRepo(t)
+
+	sha, err := ResolveRef(cloneDir, "v1.0.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, tagCommit, sha)
+}
+
+func TestResolveRef_RemoteTrackingBranch(t *testing.T) {
+	cloneDir, _, featureCommit := setupClonedRepo(t)
+
+	for _, ref := range []string{"origin/feature", "feature"} {
+		sha, err := ResolveRef(cloneDir, ref)
+
+		require.NoError(t, err, ref)
+		assert.Equal(t, featureCommit, sha, ref)
+	}
+}
+
+func TestResolveRef_MisspelledBranchSuggestsItInTheError(t *testing.T) {
+	cloneDir, _, _ := setupClonedRepo(t)
+
+	_, err := ResolveRef(cloneDir, "featrue")
+
+	require.ErrorIs(t, err, ErrInvalidCommit)
+	assert.EqualError(t, err, "invalid commit reference 'featrue': unknown ref (did you mean 'origin/feature'?)")
+}
+
+func TestGetCommitFiles_ResolvesRemoteOnlyBranch(t *testing.T) {
+	cloneDir, _, _ := setupClonedRepo(t)
+
+	files, err := GetCommitFiles(cloneDir, "feature")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/feature.go", normalizeFilePaths(cloneDir, files))
+}
+
+func TestResolveCommitRange_TagToRemoteBranchResolvesToSHAs(t *testing.T) {
+	cloneDir, tagCommit, featureCommit := setupClonedRepo(t)
+
+	from, to, isRange, err := ResolveCommitRange(cloneDir, "v1.0.0..feature")
+
+	require.NoError(t, err)
+	assert.True(t, isRange)
+	assert.Equal(t, tagCommit, from)
+	assert.Equal(t, featureCommit, to)
+}
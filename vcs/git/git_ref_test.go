@@ -0,0 +1,112 @@
+package git
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const listRefsArgs = "for-each-ref --format=%(refname) refs/heads refs/remotes refs/tags"
+
+// onUnknownRef registers the git invocations with which ref fails to resolve in a
+// full clone that has no branches, remote-tracking branches, or tags.
+func onUnknownRef(runner *fakeGitRunner, ref string) *fakeGitRunner {
+	return runner.
+		on("rev-parse --verify "+ref+"^{commit}", fakeGitResponse{
+			stderr:   "fatal: Needed a single revision",
+			exitCode: 128,
+		}).
+		on(listRefsArgs, fakeGitResponse{}).
+		on("rev-parse --is-shallow-repository", fakeGitResponse{stdout: "false\n"})
+}
+
+func TestResolveRef_ReturnsFullSHA(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --verify v1.0.0^{commit}", fakeGitResponse{stdout: olderCommit + "\n"})
+
+	sha, err := ResolveRef("/repo", "v1.0.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, olderCommit, sha)
+}
+
+func TestResolveRef_AmbiguousShortSHAListsCandidates(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --verify 0d66^{commit}", fakeGitResponse{
+			stderr: "error: short object ID 0d66 is ambiguous\n" +
+				"hint: The candidates are:\n" +
+				"hint:   0d667d1 commit 2026-10-16 - Add parser\n" +
+				"hint:   0d66a02 commit 2026-10-15 - Fix cache\n" +
+				"hint:   0d667b5 blob\n" +
+				"fatal: Needed a single revision",
+			exitCode: 128,
+		})
+
+	_, err := ResolveRef("/repo", "0d66")
+
+	var refErr *RefError
+	require.True(t, errors.As(err, &refErr))
+	assert.True(t, refErr.Ambiguous)
+	assert.ErrorIs(t, err, ErrInvalidCommit)
+	assert.EqualError(t, err, "invalid commit reference '0d66': ambiguous ref matching '0d667d1', '0d66a02'")
+}
+
+func TestResolveRef_MisspelledBranchSuggestsCloseMatches(t *testing.T) {
+	onUnknownRef(useFakeGitRunner(t), "mian").
+		on(listRefsArgs, fakeGitResponse{stdout: "refs/heads/main\nrefs/heads/feature/login\nrefs/remotes/origin/HEAD\nrefs/remotes/origin/main\nrefs/tags/v1.0.0\n"})
+
+	_, err := ResolveRef("/repo", "mian")
+
+	var refErr *RefError
+	require.True(t, errors.As(err, &refErr))
+	assert.False(t, refErr.Ambiguous)
+	assert.EqualError(t, err, "invalid commit reference 'mian': unknown ref (did you mean 'main' or 'origin/main'?)")
+}
+
+func TestResolveRef_FallsBackToTheOnlyRemoteTrackingBranch(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --verify feature^{commit}", fakeGitResponse{
+			stderr:   "fatal: Needed a single revision",
+			exitCode: 128,
+		}).
+		on(listRefsArgs, fakeGitResponse{stdout: "refs/heads/main\nrefs/remotes/origin/feature\nrefs/remotes/origin/main\n"}).
+		on("rev-parse --verify refs/remotes/origin/feature^{commit}", fakeGitResponse{stdout: newerCommit + "\n"})
+
+	sha, err := ResolveRef("/repo", "feature")
+
+	require.NoError(t, err)
+	assert.Equal(t, newerCommit, sha)
+}
+
+func TestResolveRef_BranchOnSeveralRemotesIsAmbiguous(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --verify feature^{commit}", fakeGitResponse{
+			stderr:   "fatal: Needed a single revision",
+			exitCode: 128,
+		}).
+		on(listRefsArgs, fakeGitResponse{stdout: "refs/remotes/origin/feature\nrefs/remotes/upstream/feature\n"})
+
+	_, err := ResolveRef("/repo", "feature")
+
+	assert.ErrorIs(t, err, ErrInvalidCommit)
+	assert.EqualError(t, err, "invalid commit reference 'feature': ambiguous ref matching 'origin/feature', 'upstream/feature'")
+}
+
+func TestResolveRef_RejectsOptionLikeRef(t *testing.T) {
+	runner := useFakeGitRunner(t)
+
+	_, err := ResolveRef("/repo", "--output=/tmp/x")
+
+	assert.ErrorIs(t, err, ErrInvalidCommit)
+	assert.Empty(t, runner.calls)
+}
+
+func TestCloseRefNames_OrdersByDistanceThenName(t *testing.T) {
+	refNames := []string{"refs/heads/develop", "refs/heads/main", "refs/heads/maint", "refs/tags/release/1.0", "refs/remotes/origin/mainn-fix", "refs/remotes/origin/HEAD"}
+
+	assert.Equal(t, []string{"main", "maint"}, closeRefNames(refNames, "mainn"))
+	assert.Equal(t, []string{"origin/mainn-fix"}, closeRefNames(refNames, "mainn-fx"))
+	assert.Empty(t, closeRefNames(refNames, "zzz"))
+}
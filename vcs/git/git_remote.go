@@ -0,0 +1,24 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetRemoteURL returns the URL configured for the named remote, such as "origin".
+func GetRemoteURL(repoPath, remote string) (string, error) {
+	if err := validateGitRef(remote); err != nil {
+		return "", err
+	}
+
+	stdout, stderr, err := runGitCommand(repoPath, "config", "--get", "remote."+remote+".url")
+	if err != nil {
+		// Exit code 1 means the key is not set
+		if exitCode(err) == 1 {
+			return "", fmt.Errorf("remote '%s' has no URL configured", remote)
+		}
+		return "", gitCommandError(err, stderr)
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
@@ -5,42 +5,173 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 )
 
 const gitCommandTimeout = 10 * time.Second
 
-func runGitCommand(repoPath string, args ...string) ([]byte, string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
-	defer cancel()
+// lockRetryDelays are the waits between attempts when git reports that another
+// process holds a lock file. The total stays well under a second so save-hooks
+// remain responsive.
+var lockRetryDelays = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+}
+
+// lockContentionPattern matches git's report that a lock file such as
+// .git/index.lock is already held.
+var lockContentionPattern = regexp.MustCompile(`Unable to create '([^']+\.lock)': File exists`)
+
+// LockContentionError reports that git could not take a lock held by another
+// process, even after retrying.
+type LockContentionError struct {
+	LockPath string
+	Attempts int
+}
+
+func (e *LockContentionError) Error() string {
+	return fmt.Sprintf("git could not create %s after %d attempts: another git process is using this repository "+
+		"(often an editor's git integration or a git command still running); try again once it finishes, "+
+		"or delete the lock file if no git process is running", e.LockPath, e.Attempts)
+}
+
+// GitRunner executes git subcommands. It is the single seam through which this
+// package talks to git, so tests can substitute a fake implementation. stdin is fed
+// to git's standard input; nil leaves it empty.
+type GitRunner interface {
+	Run(ctx context.Context, dir string, stdin []byte, args ...string) (stdout, stderr []byte, err error)
+}
 
-	cmd := exec.CommandContext(ctx, "git", args...)
-	cmd.Dir = repoPath
+// execGitRunner runs git as a subprocess.
+type execGitRunner struct{}
+
+func (execGitRunner) Run(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, []byte, error) {
+	// core.quotepath=off makes git print non-ASCII file names as-is instead of as
+	// octal escapes; names that still need quoting are unquoted by unquoteGitPath.
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-c", "core.quotepath=off"}, args...)...)
+	cmd.Dir = dir
+	// Clarity only reads from the repository, so tell git not to take optional
+	// locks, such as the index refresh "git status" otherwise performs.
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	// Once ctx is done git is killed; stop waiting for its output shortly after in
+	// case a child it started still holds the pipes open.
+	cmd.WaitDelay = time.Second
+
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
-		stderrText := strings.TrimSpace(stderr.String())
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// gitRunner is the runner used by all git operations in this package.
+var gitRunner GitRunner = execGitRunner{}
+
+// SetGitRunner replaces the runner used by this package and returns a function
+// that restores the previous one.
+func SetGitRunner(runner GitRunner) (restore func()) {
+	previous := gitRunner
+	gitRunner = runner
+	return func() {
+		gitRunner = previous
+	}
+}
+
+// runGitCommand runs git in repoPath, retrying briefly when another process holds
+// one of the repository's lock files.
+func runGitCommand(repoPath string, args ...string) ([]byte, string, error) {
+	return runGitCommandContext(context.Background(), repoPath, args...)
+}
+
+// runGitCommandContext runs git like runGitCommand and kills it when ctx is done, in
+// which case the returned error wraps ctx.Err().
+func runGitCommandContext(ctx context.Context, repoPath string, args ...string) ([]byte, string, error) {
+	return runGitCommandWithInput(ctx, repoPath, nil, args...)
+}
+
+// runGitCommandWithInput runs git like runGitCommandContext with stdin on its
+// standard input, for lists too long to pass as arguments.
+func runGitCommandWithInput(ctx context.Context, repoPath string, stdin []byte, args ...string) ([]byte, string, error) {
+	for attempt := 0; ; attempt++ {
+		stdout, stderrText, err := runGitCommandOnce(ctx, repoPath, stdin, args...)
+		if err == nil {
+			return stdout, stderrText, nil
+		}
+
+		match := lockContentionPattern.FindStringSubmatch(stderrText)
+		if match == nil {
+			return nil, stderrText, err
+		}
+		if attempt == len(lockRetryDelays) {
+			return nil, stderrText, &LockContentionError{LockPath: match[1], Attempts: attempt + 1}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, stderrText, fmt.Errorf("git %s canceled: %w", args[0], ctx.Err())
+		case <-time.After(lockRetryDelays[attempt]):
+		}
+	}
+}
+
+func runGitCommandOnce(parent context.Context, repoPath string, stdin []byte, args ...string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(parent, gitCommandTimeout)
+	defer cancel()
+
+	start := time.Now()
+	stdout, stderr, err := gitRunner.Run(ctx, repoPath, stdin, args...)
+	slog.Debug("git command", "args", strings.Join(args, " "), "duration", time.Since(start), "failed", err != nil)
+	stderrText := strings.TrimSpace(string(stderr))
+	if err != nil {
+		// The caller's context ending takes precedence over the per-command timeout,
+		// so callers can tell a cancelled run from a hung git.
+		if parentErr := parent.Err(); parentErr != nil {
+			return nil, "", fmt.Errorf("git %s canceled: %w", args[0], parentErr)
+		}
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return nil, stderrText, fmt.Errorf("git command timed out after %s", gitCommandTimeout)
 		}
 		return nil, stderrText, err
 	}
 
-	return stdout.Bytes(), strings.TrimSpace(stderr.String()), nil
+	return stdout, stderrText, nil
 }
 
 func gitCommandError(err error, stderr string) error {
 	if err == nil {
 		return nil
 	}
+	var lockErr *LockContentionError
+	if errors.As(err, &lockErr) {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
 	if stderr != "" {
-		return fmt.Errorf("git command failed: %s", stderr)
+		return classifyStderrError(fmt.Errorf("git command failed: %s", stderr), stderr)
 	}
 	return err
 }
+
+// exitCode returns the process exit code carried by err, or -1 when err does
+// not describe a git process that ran to completion.
+func exitCode(err error) int {
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return -1
+}
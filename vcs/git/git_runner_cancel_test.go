@@ -0,0 +1,61 @@
+//go:build !windows
+
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installSleepingGit puts a git on PATH that records its process ID in the returned
+// file and then sleeps far longer than any test waits.
+func installSleepingGit(t *testing.T) string {
+	t.Helper()
+
+	binDir := t.TempDir()
+	pidFile := filepath.Join(t.TempDir(), "git.pid")
+	script := "#!/bin/sh\necho $$ > '" + pidFile + "'\nexec sleep 30\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "git"), []byte(script), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return pidFile
+}
+
+func TestRunGitCommandContext_DeadlineKillsGit(t *testing.T) {
+	pidFile := installSleepingGit(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := runGitCommandContext(ctx, t.TempDir(), "status")
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err.Error())
+	assert.Equal(t, "git status canceled: context deadline exceeded", err.Error())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	pidText, readErr := os.ReadFile(pidFile)
+	require.NoError(t, readErr)
+	pid, convErr := strconv.Atoi(strings.TrimSpace(string(pidText)))
+	require.NoError(t, convErr)
+	assert.ErrorIs(t, syscall.Kill(pid, 0), syscall.ESRCH, "git should no longer be running")
+}
+
+func TestGetCommitTreeFilesContext_CanceledContextReturnsItsError(t *testing.T) {
+	installSleepingGit(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := GetCommitTreeFilesContext(ctx, t.TempDir(), "HEAD")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeGitResponse is a canned result for one git invocation.
+type fakeGitResponse struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// fakeExitError mimics *exec.ExitError for a git process that exited non-zero.
+type fakeExitError struct {
+	code int
+}
+
+func (e fakeExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func (e fakeExitError) ExitCode() int {
+	return e.code
+}
+
+// fakeGitRunner replays recorded git responses keyed by the space-joined argument list.
+// Unexpected invocations fail the test so each unit test documents the git calls it relies on.
+type fakeGitRunner struct {
+	t         *testing.T
+	responses map[string]fakeGitResponse
+	calls     []string
+	// inputs holds what each call received on standard input, in call order.
+	inputs []string
+}
+
+// useFakeGitRunner installs a fakeGitRunner for the duration of the test.
+func useFakeGitRunner(t *testing.T) *fakeGitRunner {
+	t.Helper()
+
+	runner := &fakeGitRunner{
+		t:         t,
+		responses: make(map[string]fakeGitResponse),
+	}
+	t.Cleanup(SetGitRunner(runner))
+	return runner
+}
+
+// on records the response returned for the given git arguments.
+func (f *fakeGitRunner) on(args string, response fakeGitResponse) *fakeGitRunner {
+	f.responses[args] = response
+	return f
+}
+
+func (f *fakeGitRunner) Run(_ context.Context, _ string, stdin []byte, args ...string) ([]byte, []byte, error) {
+	key := strings.Join(args, " ")
+	f.calls = append(f.calls, key)
+	f.inputs = append(f.inputs, string(stdin))
+
+	response, ok := f.responses[key]
+	if !ok {
+		f.t.Errorf("unexpected git invocation: git %s", key)
+		return nil, nil, fakeExitError{code: 128}
+	}
+
+	if response.exitCode != 0 {
+		return []byte(response.stdout), []byte(response.stderr), fakeExitError{code: response.exitCode}
+	}
+	return []byte(response.stdout), []byte(response.stderr), nil
+}
+
+var notARepository = fakeGitResponse{
+	stderr:   "fatal: not a git repository (or any of the parent directories): .git",
+	exitCode: 128,
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const indexLockStderr = "fatal: Unable to create '/repo/.git/index.lock': File exists.\n\n" +
+	"Another git process seems to be running in this repository."
+
+// lockedGitRunner reports index.lock contention for the first `failures` calls and
+// succeeds afterwards.
+type lockedGitRunner struct {
+	failures int
+	calls    int
+}
+
+func (r *lockedGitRunner) Run(_ context.Context, _ string, _ []byte, _ ...string) ([]byte, []byte, error) {
+	r.calls++
+	if r.calls <= r.failures {
+		return nil, []byte(indexLockStderr), fakeExitError{code: 128}
+	}
+	return []byte(" M main.go\n"), nil, nil
+}
+
+// useShortLockRetries makes lock retries immediate for the duration of the test.
+func useShortLockRetries(t *testing.T) {
+	t.Helper()
+
+	previous := lockRetryDelays
+	lockRetryDelays = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+	t.Cleanup(func() {
+		lockRetryDelays = previous
+	})
+}
+
+func TestRunGitCommand_RetriesLockContention(t *testing.T) {
+	useShortLockRetries(t)
+	runner := &lockedGitRunner{failures: 2}
+	t.Cleanup(SetGitRunner(runner))
+
+	dirty, err := HasUncommittedChanges("/repo")
+
+	require.NoError(t, err)
+	assert.True(t, dirty)
+	assert.Equal(t, 3, runner.calls)
+}
+
+func TestRunGitCommand_LockContentionExhaustsRetries(t *testing.T) {
+	useShortLockRetries(t)
+	runner := &lockedGitRunner{failures: 100}
+	t.Cleanup(SetGitRunner(runner))
+
+	_, err := HasUncommittedChanges("/repo")
+
+	require.Error(t, err)
+	assert.Equal(t, 4, runner.calls)
+	var lockErr *LockContentionError
+	require.True(t, errors.As(err, &lockErr))
+	assert.Equal(t, "/repo/.git/index.lock", lockErr.LockPath)
+	assert.Equal(t, 4, lockErr.Attempts)
+	assert.Contains(t, err.Error(), "git could not create /repo/.git/index.lock after 4 attempts")
+	assert.Contains(t, err.Error(), "another git process is using this repository")
+	assert.Contains(t, err.Error(), "delete the lock file if no git process is running")
+}
+
+func TestRunGitCommand_DoesNotRetryOtherFailures(t *testing.T) {
+	useShortLockRetries(t)
+	runner := useFakeGitRunner(t).on("status --porcelain", notARepository)
+
+	_, err := HasUncommittedChanges("/repo")
+
+	require.Error(t, err)
+	assert.Len(t, runner.calls, 1)
+	assert.Contains(t, err.Error(), "not a git repository")
+}
@@ -0,0 +1,86 @@
+//go:build integration
+
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupShallowClone commits first.go, second.go and third.go in turn, with branch old
+// left at the first commit, and clones the repository with depth 1. Every branch tip
+// is present in the clone, but none of the history linking them: the returned SHA of
+// the second commit is missing.
+func setupShallowClone(t *testing.T) (cloneDir, secondCommit string) {
+	repoDir := t.TempDir()
+	setupGitRepo(t, repoDir)
+
+	createFile(t, repoDir, "first.go", "package shallow\n")
+	gitAdd(t, repoDir, "first.go")
+	gitCommit(t, repoDir, "Add first.go")
+	gitBranch(t, repoDir, "old")
+
+	createFile(t, repoDir, "second.go", "package shallow\n")
+	gitAdd(t, repoDir, "second.go")
+	secondCommit = gitCommitAndGetSHA(t, repoDir, "Add second.go")
+
+	createFile(t, repoDir, "third.go", "package shallow\n")
+	gitAdd(t, repoDir, "third.go")
+	gitCommit(t, repoDir, "Add third.go")
+
+	cloneDir = filepath.Join(t.TempDir(), "clone")
+	cmd := exec.Command("git", "clone", "-q", "--depth", "1", "--no-single-branch", "file://"+repoDir, cloneDir)
+	require.NoError(t, cmd.Run(), "failed to clone with depth 1")
+
+	return cloneDir, secondCommit
+}
+
+func gitBranch(t *testing.T, repoDir, name string) {
+	cmd := exec.Command("git", "branch", name)
+	cmd.Dir = repoDir
+	require.NoError(t, cmd.Run(), "failed to create branch %s", name)
+}
+
+func TestNormalizeCommitRange_ShallowCloneKeepsUnlinkedRangeAsGiven(t *testing.T) {
+	cloneDir, _ := setupShallowClone(t)
+
+	from, to, swapped, err := NormalizeCommitRange(cloneDir, "HEAD", "origin/old")
+
+	require.NoError(t, err)
+	assert.Equal(t, "HEAD", from)
+	assert.Equal(t, "origin/old", to)
+	assert.False(t, swapped)
+}
+
+func TestResolveCommitRange_ShallowCloneMissingCommitSuggestsUnshallow(t *testing.T) {
+	cloneDir, _ := setupShallowClone(t)
+
+	_, _, _, err := ResolveCommitRange(cloneDir, "HEAD~1...HEAD")
+
+	require.ErrorIs(t, err, ErrInvalidCommit)
+	assert.Contains(t, err.Error(), "commit not present in shallow clone")
+	assert.Contains(t, err.Error(), "git fetch --unshallow")
+}
+
+func TestGetCommitRangeFiles_ShallowCloneMissingCommit(t *testing.T) {
+	cloneDir, secondCommit := setupShallowClone(t)
+
+	_, err := GetCommitRangeFiles(cloneDir, secondCommit, "HEAD")
+
+	require.ErrorIs(t, err, ErrInvalidCommit)
+	assert.Contains(t, err.Error(), "commit not present in shallow clone")
+	assert.Contains(t, err.Error(), "git fetch --unshallow")
+}
+
+func TestGetCommitRangeFiles_ShallowCloneListsFilesBetweenPresentCommits(t *testing.T) {
+	cloneDir, _ := setupShallowClone(t)
+
+	files, err := GetCommitRangeFiles(cloneDir, "origin/old", "HEAD")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/second.go\n$REPO/third.go", normalizeFilePaths(cloneDir, files))
+}
@@ -2,8 +2,8 @@ package git
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -14,33 +14,98 @@ import (
 	"github.com/LegacyCodeHQ/clarity/vcs"
 )
 
+// UncommittedScope selects which uncommitted changes GetUncommittedFileStatsInScope
+// counts.
+type UncommittedScope int
+
+const (
+	// UncommittedAll compares the working tree with HEAD, so a file with staged and
+	// further unstaged edits reports both. Untracked files count every line as added.
+	UncommittedAll UncommittedScope = iota
+	// UncommittedStaged compares the index with HEAD.
+	UncommittedStaged
+	// UncommittedUnstaged compares the working tree with the index. Untracked files
+	// are not included.
+	UncommittedUnstaged
+)
+
 // GetUncommittedFileStats returns statistics (additions/deletions) for uncommitted files
-// Returns a map from relative file paths to their FileStats
+// relative to HEAD, reading untracked files from the filesystem.
+// Returns a map from absolute file paths to their FileStats
 func GetUncommittedFileStats(repoPath string) (map[string]vcs.FileStats, error) {
+	return GetUncommittedFileStatsWithOptions(repoPath, AllUncommittedChanges(), vcs.FilesystemContentReader())
+}
+
+// GetUncommittedFileStatsInScope returns statistics for the uncommitted changes selected
+// by scope. Lines of untracked files are counted from contentReader.
+// Returns a map from absolute file paths to their FileStats
+func GetUncommittedFileStatsInScope(repoPath string, scope UncommittedScope, contentReader vcs.ContentReader) (map[string]vcs.FileStats, error) {
+	return uncommittedFileStats(context.Background(), repoPath, scope, true, scope == UncommittedAll, contentReader)
+}
+
+// GetUncommittedFileStatsWithOptions returns statistics for the uncommitted changes
+// selected by opts. Lines of untracked files are counted from contentReader.
+// Returns a map from absolute file paths to their FileStats
+func GetUncommittedFileStatsWithOptions(repoPath string, opts UncommittedOptions, contentReader vcs.ContentReader) (map[string]vcs.FileStats, error) {
+	return GetUncommittedFileStatsWithOptionsContext(context.Background(), repoPath, opts, contentReader)
+}
+
+// GetUncommittedFileStatsWithOptionsContext returns statistics like
+// GetUncommittedFileStatsWithOptions, stopping git when ctx is done.
+func GetUncommittedFileStatsWithOptionsContext(ctx context.Context, repoPath string, opts UncommittedOptions, contentReader vcs.ContentReader) (map[string]vcs.FileStats, error) {
+	scope := UncommittedAll
+	switch {
+	case opts.IncludeStaged && !opts.IncludeUnstaged:
+		scope = UncommittedStaged
+	case !opts.IncludeStaged && opts.IncludeUnstaged:
+		scope = UncommittedUnstaged
+	}
+	tracked := opts.IncludeStaged || opts.IncludeUnstaged
+	return uncommittedFileStats(ctx, repoPath, scope, tracked, opts.IncludeUntracked, contentReader)
+}
+
+// uncommittedFileStats returns statistics for the tracked changes selected by scope,
+// when tracked is set, and for untracked files when untracked is set.
+func uncommittedFileStats(ctx context.Context, repoPath string, scope UncommittedScope, tracked, untracked bool, contentReader vcs.ContentReader) (map[string]vcs.FileStats, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	// Run git diff --numstat to get stats for uncommitted changes
-	// This includes both staged and unstaged changes
-	stdout, stderr, err := runGitCommand(repoPath, "diff", "--numstat", "HEAD")
-	if err != nil {
-		return nil, gitCommandError(err, stderr)
+	// -M reports a renamed file once, under its new path with only its content
+	// changes, whatever diff.renames is set to.
+	var diffArgs []string
+	switch scope {
+	case UncommittedAll:
+		diffArgs = []string{"diff", "--numstat", "-M", "HEAD"}
+	case UncommittedStaged:
+		diffArgs = []string{"diff", "--numstat", "-M", "--cached", "HEAD"}
+	case UncommittedUnstaged:
+		diffArgs = []string{"diff", "--numstat", "-M"}
+	default:
+		return nil, fmt.Errorf("unknown uncommitted scope: %d", scope)
+	}
+	var stdout []byte
+	if tracked {
+		var stderr string
+		stdout, stderr, err = runGitCommandContext(ctx, repoPath, diffArgs...)
+		if err != nil {
+			return nil, gitCommandError(err, stderr)
+		}
 	}
 
-	statusMap, err := getUncommittedFileStatuses(repoPath)
+	statusMap, err := getUncommittedFileStatuses(ctx, repoPath)
 	if err != nil {
 		return nil, err
 	}
@@ -54,57 +119,38 @@ func GetUncommittedFileStats(repoPath string) (map[string]vcs.FileStats, error)
 			continue
 		}
 
-		// Format: additions	deletions	filename
-		parts := strings.Fields(line)
-		if len(parts) < 3 {
+		relPath, fileStats, ok := parseNumstatLine(line)
+		if !ok {
 			continue
 		}
-
-		additions := 0
-		deletions := 0
-
-		// Parse additions (may be "-" for binary files)
-		if parts[0] != "-" {
-			additions, _ = strconv.Atoi(parts[0])
-		}
-
-		// Parse deletions (may be "-" for binary files)
-		if parts[1] != "-" {
-			deletions, _ = strconv.Atoi(parts[1])
-		}
-
-		// Handle renamed files
-		filePath := strings.Join(parts[2:], " ")
-		filePath = parseRenamedFilePath(filePath)
-		filePath = filepath.Clean(filePath)
-
-		// Convert to absolute path
-		absPath := filepath.Join(repoRoot, filePath)
-
-		stats[absPath] = vcs.FileStats{
-			Additions: additions,
-			Deletions: deletions,
-			IsNew:     isNewStatus(statusMap[filePath]),
-		}
+		fileStats.IsNew = isNewInScope(statusMap[relPath], scope)
+		stats[filepath.Join(repoRoot, relPath)] = fileStats
 	}
 
-	// Include entries for new/untracked files that may not appear in numstat output.
+	// Include entries for new files that may not appear in numstat output: untracked
+	// files, and files added empty when comparing the working tree with HEAD.
 	var lineCountTargets []string
 	for relPath, status := range statusMap {
 		if !isNewStatus(status) {
 			continue
 		}
+		if status == "??" && !untracked {
+			continue
+		}
+		if status != "??" && (!tracked || scope != UncommittedAll) {
+			continue
+		}
 
 		absPath := filepath.Join(repoRoot, relPath)
 		fileStats := stats[absPath]
 		fileStats.IsNew = true
-		if fileStats.Additions == 0 && fileStats.Deletions == 0 {
+		if fileStats.Additions == 0 && fileStats.Deletions == 0 && !fileStats.IsBinary {
 			lineCountTargets = append(lineCountTargets, absPath)
 		}
 		stats[absPath] = fileStats
 	}
 
-	for absPath, lineCount := range countLinesInFilesParallel(lineCountTargets) {
+	for absPath, lineCount := range countLinesInFilesParallel(contentReader, lineCountTargets) {
 		fileStats := stats[absPath]
 		fileStats.Additions = lineCount
 		stats[absPath] = fileStats
@@ -116,35 +162,42 @@ func GetUncommittedFileStats(repoPath string) (map[string]vcs.FileStats, error)
 // GetCommitFileStats returns statistics (additions/deletions) for files in a specific commit
 // Returns a map from absolute file paths to their FileStats
 func GetCommitFileStats(repoPath, commitID string) (map[string]vcs.FileStats, error) {
+	return GetCommitFileStatsContext(context.Background(), repoPath, commitID)
+}
+
+// GetCommitFileStatsContext returns statistics like GetCommitFileStats, stopping git
+// when ctx is done.
+func GetCommitFileStatsContext(ctx context.Context, repoPath, commitID string) (map[string]vcs.FileStats, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
-	// Validate the commit exists
-	if err := validateCommit(repoPath, commitID); err != nil {
+	// Resolve the commit to its SHA
+	commitID, err := resolveRef(ctx, repoPath, commitID)
+	if err != nil {
 		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	// Run git show --numstat to get stats for the commit
+	// Run git show --numstat to get stats for the commit, with renames detected (-M)
 	// Use --root flag to handle root commits
-	stdout, stderr, err := runGitCommand(repoPath, "show", "--numstat", "--format=", commitID)
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "show", "--numstat", "-M", "--format=", commitID)
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
 
-	statusMap, err := getCommitFileStatuses(repoPath, commitID)
+	statusMap, err := getCommitFileStatuses(ctx, repoPath, commitID)
 	if err != nil {
 		return nil, err
 	}
@@ -158,38 +211,12 @@ func GetCommitFileStats(repoPath, commitID string) (map[string]vcs.FileStats, er
 			continue
 		}
 
-		// Format: additions	deletions	filename
-		parts := strings.Fields(line)
-		if len(parts) < 3 {
+		relPath, fileStats, ok := parseNumstatLine(line)
+		if !ok {
 			continue
 		}
-
-		additions := 0
-		deletions := 0
-
-		// Parse additions (may be "-" for binary files)
-		if parts[0] != "-" {
-			additions, _ = strconv.Atoi(parts[0])
-		}
-
-		// Parse deletions (may be "-" for binary files)
-		if parts[1] != "-" {
-			deletions, _ = strconv.Atoi(parts[1])
-		}
-
-		// Handle renamed files
-		filePath := strings.Join(parts[2:], " ")
-		filePath = parseRenamedFilePath(filePath)
-		filePath = filepath.Clean(filePath)
-
-		// Convert to absolute path
-		absPath := filepath.Join(repoRoot, filePath)
-
-		stats[absPath] = vcs.FileStats{
-			Additions: additions,
-			Deletions: deletions,
-			IsNew:     isNewStatus(statusMap[filePath]),
-		}
+		fileStats.IsNew = isNewStatus(statusMap[relPath])
+		stats[filepath.Join(repoRoot, relPath)] = fileStats
 	}
 
 	// Include entries for new files that may not appear in numstat output
@@ -210,38 +237,47 @@ func GetCommitFileStats(repoPath, commitID string) (map[string]vcs.FileStats, er
 // GetCommitRangeFileStats returns statistics (additions/deletions) for files changed between two commits.
 // Returns a map from absolute file paths to their FileStats.
 func GetCommitRangeFileStats(repoPath, fromCommit, toCommit string) (map[string]vcs.FileStats, error) {
+	return GetCommitRangeFileStatsContext(context.Background(), repoPath, fromCommit, toCommit)
+}
+
+// GetCommitRangeFileStatsContext returns statistics like GetCommitRangeFileStats,
+// stopping git when ctx is done.
+func GetCommitRangeFileStatsContext(ctx context.Context, repoPath, fromCommit, toCommit string) (map[string]vcs.FileStats, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
-	// Validate both commits exist
-	if err := validateCommit(repoPath, fromCommit); err != nil {
+	// Resolve both commits to their SHAs
+	fromCommit, err := resolveRef(ctx, repoPath, fromCommit)
+	if err != nil {
 		return nil, err
 	}
-	if err := validateCommit(repoPath, toCommit); err != nil {
+	toCommit, err = resolveRef(ctx, repoPath, toCommit)
+	if err != nil {
 		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	// Run git diff --numstat to get stats for the range
-	stdout, stderr, err := runGitCommand(repoPath, "diff", "--numstat", fromCommit, toCommit)
+	// Run git diff --numstat to get stats for the range. -M detects renames whatever
+	// diff.renames is set to, so a renamed file counts only its content changes.
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "diff", "--numstat", "-M", fromCommit, toCommit)
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
 
 	// Get file statuses to determine if files are new
-	statusMap, err := getCommitRangeFileStatuses(repoPath, fromCommit, toCommit)
+	statusMap, err := getCommitRangeFileStatuses(ctx, repoPath, fromCommit, toCommit)
 	if err != nil {
 		return nil, err
 	}
@@ -255,46 +291,20 @@ func GetCommitRangeFileStats(repoPath, fromCommit, toCommit string) (map[string]
 			continue
 		}
 
-		// Format: additions	deletions	filename
-		parts := strings.Fields(line)
-		if len(parts) < 3 {
+		relPath, fileStats, ok := parseNumstatLine(line)
+		if !ok {
 			continue
 		}
-
-		additions := 0
-		deletions := 0
-
-		// Parse additions (may be "-" for binary files)
-		if parts[0] != "-" {
-			additions, _ = strconv.Atoi(parts[0])
-		}
-
-		// Parse deletions (may be "-" for binary files)
-		if parts[1] != "-" {
-			deletions, _ = strconv.Atoi(parts[1])
-		}
-
-		// Handle renamed files
-		filePath := strings.Join(parts[2:], " ")
-		filePath = parseRenamedFilePath(filePath)
-		filePath = filepath.Clean(filePath)
-
-		// Convert to absolute path
-		absPath := filepath.Join(repoRoot, filePath)
-
-		stats[absPath] = vcs.FileStats{
-			Additions: additions,
-			Deletions: deletions,
-			IsNew:     statusMap[filePath] == "A",
-		}
+		fileStats.IsNew = statusMap[relPath] == "A"
+		stats[filepath.Join(repoRoot, relPath)] = fileStats
 	}
 
 	return stats, nil
 }
 
 // getUncommittedFileStatuses returns a map of relative file paths to their git status codes
-func getUncommittedFileStatuses(repoPath string) (map[string]string, error) {
-	stdout, stderr, err := runGitCommand(repoPath, "status", "--porcelain", "--untracked-files=all")
+func getUncommittedFileStatuses(ctx context.Context, repoPath string) (map[string]string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "status", "--porcelain", "--untracked-files=all", "--renames")
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
@@ -307,13 +317,8 @@ func getUncommittedFileStatuses(repoPath string) (map[string]string, error) {
 		}
 
 		status := line[:2]
-		filePath := strings.TrimSpace(line[3:])
-
-		// Handle renamed files (format: "old -> new")
-		if strings.Contains(filePath, " -> ") {
-			parts := strings.Split(filePath, " -> ")
-			filePath = parts[1]
-		}
+		// Renamed files are listed as "old -> new"
+		_, filePath := splitPorcelainRename(strings.TrimSpace(line[3:]))
 
 		if filePath == "" {
 			continue
@@ -327,8 +332,8 @@ func getUncommittedFileStatuses(repoPath string) (map[string]string, error) {
 }
 
 // getCommitFileStatuses returns a map of file paths to their status codes for a commit
-func getCommitFileStatuses(repoPath, commitID string) (map[string]string, error) {
-	stdout, stderr, err := runGitCommand(repoPath, "show", "--name-status", "--format=", commitID)
+func getCommitFileStatuses(ctx context.Context, repoPath, commitID string) (map[string]string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "show", "--name-status", "-M", "--format=", commitID)
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
@@ -352,9 +357,9 @@ func getCommitFileStatuses(repoPath, commitID string) (map[string]string, error)
 			if len(parts) < 3 {
 				continue
 			}
-			filePath = parts[2]
+			filePath = unquoteGitPath(parts[2])
 		} else {
-			filePath = parts[1]
+			filePath = unquoteGitPath(parts[1])
 		}
 
 		if filePath == "" {
@@ -369,8 +374,8 @@ func getCommitFileStatuses(repoPath, commitID string) (map[string]string, error)
 }
 
 // getCommitRangeFileStatuses returns a map of file paths to their status codes for a commit range
-func getCommitRangeFileStatuses(repoPath, fromCommit, toCommit string) (map[string]string, error) {
-	stdout, stderr, err := runGitCommand(repoPath, "diff", "--name-status", fromCommit, toCommit)
+func getCommitRangeFileStatuses(ctx context.Context, repoPath, fromCommit, toCommit string) (map[string]string, error) {
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "diff", "--name-status", "-M", fromCommit, toCommit)
 	if err != nil {
 		return nil, gitCommandError(err, stderr)
 	}
@@ -394,9 +399,9 @@ func getCommitRangeFileStatuses(repoPath, fromCommit, toCommit string) (map[stri
 			if len(parts) < 3 {
 				continue
 			}
-			filePath = parts[2]
+			filePath = unquoteGitPath(parts[2])
 		} else {
-			filePath = parts[1]
+			filePath = unquoteGitPath(parts[1])
 		}
 
 		if filePath == "" {
@@ -410,12 +415,45 @@ func getCommitRangeFileStatuses(repoPath, fromCommit, toCommit string) (map[stri
 	return statuses, nil
 }
 
+// parseNumstatLine parses one line of git --numstat output into the file's
+// repository-relative path and its stats. Binary files, which git reports as "-",
+// are marked IsBinary; renamed files carry their previous path in RenamedFrom.
+func parseNumstatLine(line string) (string, vcs.FileStats, bool) {
+	// Format: additions	deletions	filename. The name may itself contain spaces,
+	// so only the two tabs separate fields.
+	parts := strings.SplitN(line, "\t", 3)
+	if len(parts) < 3 {
+		return "", vcs.FileStats{}, false
+	}
+
+	var stats vcs.FileStats
+	if parts[0] == "-" && parts[1] == "-" {
+		stats.IsBinary = true
+	} else {
+		stats.Additions, _ = strconv.Atoi(parts[0])
+		stats.Deletions, _ = strconv.Atoi(parts[1])
+	}
+
+	oldPath, newPath := splitRenamedFilePath(parts[2])
+	if oldPath != newPath {
+		stats.RenamedFrom = filepath.ToSlash(filepath.Clean(oldPath))
+	}
+	return filepath.Clean(newPath), stats, true
+}
+
 // parseRenamedFilePath parses a renamed file path from git numstat output
 // and returns the new (destination) file path.
 // Handles two formats:
 // 1. Full format: "old_path => new_path" (returns new_path)
 // 2. Abbreviated format: "prefix/{old => new}/suffix" (returns prefix/new/suffix)
 func parseRenamedFilePath(filePath string) string {
+	_, newPath := splitRenamedFilePath(filePath)
+	return newPath
+}
+
+// splitRenamedFilePath returns the old and new paths of a numstat file entry. For
+// entries that are not renames both paths are the entry itself.
+func splitRenamedFilePath(filePath string) (string, string) {
 	// Check for abbreviated rename format: "prefix/{ => new}/suffix" or "prefix/{old => new}/suffix"
 	if strings.Contains(filePath, "{") && strings.Contains(filePath, "}") {
 		// Find the positions of { and }
@@ -432,9 +470,9 @@ func parseRenamedFilePath(filePath string) string {
 			if strings.Contains(middle, " => ") {
 				parts := strings.Split(middle, " => ")
 				if len(parts) == 2 {
-					// Use the new (right) part
+					oldMiddle := strings.TrimSpace(parts[0])
 					newMiddle := strings.TrimSpace(parts[1])
-					return prefix + newMiddle + suffix
+					return unquoteGitPath(joinRenamedPath(prefix, oldMiddle, suffix)), unquoteGitPath(joinRenamedPath(prefix, newMiddle, suffix))
 				}
 			}
 		}
@@ -444,15 +482,39 @@ func parseRenamedFilePath(filePath string) string {
 	if strings.Contains(filePath, " => ") {
 		renameParts := strings.Split(filePath, " => ")
 		if len(renameParts) == 2 {
-			return strings.TrimSpace(renameParts[1])
+			return unquoteGitPath(strings.TrimSpace(renameParts[0])), unquoteGitPath(strings.TrimSpace(renameParts[1]))
 		}
 	}
 
-	// Not a rename, return as-is
-	return filePath
+	// Not a rename; git quotes names with special characters
+	filePath = unquoteGitPath(filePath)
+	return filePath, filePath
+}
+
+// joinRenamedPath rebuilds one side of an abbreviated rename. An empty middle, as in
+// "src/{ => lib}/a.go", leaves a doubled separator that is collapsed here.
+func joinRenamedPath(prefix, middle, suffix string) string {
+	if middle == "" {
+		return prefix + strings.TrimPrefix(suffix, "/")
+	}
+	return prefix + middle + suffix
 }
 
 // isNewStatus determines if a git status code represents a new or untracked file
+// isNewInScope reports whether a file with the given porcelain status is new in the
+// changes selected by scope: added to the index for staged changes, never for
+// unstaged ones, since the index already holds the file.
+func isNewInScope(status string, scope UncommittedScope) bool {
+	switch scope {
+	case UncommittedStaged:
+		return len(status) > 0 && status[0] == 'A'
+	case UncommittedUnstaged:
+		return false
+	default:
+		return isNewStatus(status)
+	}
+}
+
 func isNewStatus(status string) bool {
 	status = strings.TrimSpace(status)
 	if status == "" {
@@ -466,43 +528,20 @@ func isNewStatus(status string) bool {
 	return status[0] == 'A'
 }
 
-func countLinesInFile(path string) (int, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return 0, err
-	}
-	defer file.Close()
-
-	buf := make([]byte, 32*1024)
-	lineCount := 0
-	hasContent := false
-	var lastByte byte
-
-	for {
-		n, err := file.Read(buf)
-		if n > 0 {
-			hasContent = true
-			lastByte = buf[n-1]
-			lineCount += bytes.Count(buf[:n], []byte{'\n'})
-		}
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return 0, err
-		}
-	}
-
-	if !hasContent {
-		return 0, nil
+// countLines counts lines the way git numstat does for an added file: every newline,
+// plus a final line without a trailing newline.
+func countLines(content []byte) int {
+	if len(content) == 0 {
+		return 0
 	}
-	if lastByte != '\n' {
+	lineCount := bytes.Count(content, []byte{'\n'})
+	if content[len(content)-1] != '\n' {
 		lineCount++
 	}
-	return lineCount, nil
+	return lineCount
 }
 
-func countLinesInFilesParallel(paths []string) map[string]int {
+func countLinesInFilesParallel(contentReader vcs.ContentReader, paths []string) map[string]int {
 	if len(paths) == 0 {
 		return make(map[string]int)
 	}
@@ -529,13 +568,13 @@ func countLinesInFilesParallel(paths []string) map[string]int {
 		go func() {
 			defer wg.Done()
 			for path := range jobs {
-				lines, err := countLinesInFile(path)
+				content, err := contentReader.ReadFile(path)
 				if err != nil {
 					continue
 				}
 				results <- lineCountResult{
 					path:  path,
-					lines: lines,
+					lines: countLines(content),
 				}
 			}
 		}()
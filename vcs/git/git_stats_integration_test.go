@@ -0,0 +1,368 @@
+//go:build integration
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
+	"github.com/LegacyCodeHQ/clarity/vcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUncommittedFileStats_MarksNewAndUntrackedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	committedFile := createDartFile(t, tmpDir, "committed.dart")
+	gitAdd(t, tmpDir, "committed.dart")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	createDartFile(t, tmpDir, "staged.dart")
+	gitAdd(t, tmpDir, "staged.dart")
+
+	createDartFile(t, tmpDir, "untracked.dart")
+
+	modifyFile(t, committedFile)
+
+	stats, err := GetUncommittedFileStats(tmpDir)
+	require.NoError(t, err)
+
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetCommitFileStats_MarksNewFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	existingFile := createDartFile(t, tmpDir, "existing.dart")
+	gitAdd(t, tmpDir, "existing.dart")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	createDartFile(t, tmpDir, "added.dart")
+	gitAdd(t, tmpDir, "added.dart")
+	modifyFile(t, existingFile)
+	gitAdd(t, tmpDir, "existing.dart")
+	commitID := gitCommitAndGetSHA(t, tmpDir, "Add new file and modify existing")
+
+	stats, err := GetCommitFileStats(tmpDir, commitID)
+	require.NoError(t, err)
+
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+// Tests for parseRenamedFilePath
+
+func TestGetCommitRangeFileStats_AdditionsAndDeletions(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	// Create first commit
+	createFile(t, tmpDir, "test.txt", "line1\nline2\nline3\n")
+	gitAdd(t, tmpDir, "test.txt")
+	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+
+	// Modify file: add 2 lines, remove 1
+	createFile(t, tmpDir, "test.txt", "line1\nline3\nnew1\nnew2\n")
+	gitAdd(t, tmpDir, "test.txt")
+	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+
+	stats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
+
+	require.NoError(t, err)
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetCommitRangeFileStats_NewFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	// Create first commit
+	createFile(t, tmpDir, "first.txt", "content")
+	gitAdd(t, tmpDir, "first.txt")
+	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+
+	// Add new file
+	createFile(t, tmpDir, "new.txt", "line1\nline2\n")
+	gitAdd(t, tmpDir, "new.txt")
+	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+
+	stats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
+
+	require.NoError(t, err)
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetCommitRangeFileStats_MultipleFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	// Create first commit
+	createFile(t, tmpDir, "file1.txt", "content")
+	gitAdd(t, tmpDir, "file1.txt")
+	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+
+	// Modify and add new file
+	createFile(t, tmpDir, "file1.txt", "content\nnew line\n")
+	createFile(t, tmpDir, "file2.txt", "new file\n")
+	gitAdd(t, tmpDir, "file1.txt")
+	gitAdd(t, tmpDir, "file2.txt")
+	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+
+	stats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
+
+	require.NoError(t, err)
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetCommitRangeFileStats_InvalidCommit(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "test.txt", "content")
+	gitAdd(t, tmpDir, "test.txt")
+	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
+
+	_, err := GetCommitRangeFileStats(tmpDir, "invalid-sha", commit)
+
+	assert.Error(t, err)
+}
+
+func TestGetCommitRangeFileStats_NotGitRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := GetCommitRangeFileStats(tmpDir, "abc", "def")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not a git repository")
+}
+
+// setupPartiallyStagedRepo commits a file, stages one hunk of edits to it, then makes
+// a further unstaged edit and adds an untracked file.
+func setupPartiallyStagedRepo(t *testing.T) string {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	createFile(t, tmpDir, "partial.txt", "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	gitAdd(t, tmpDir, "partial.txt")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	createFile(t, tmpDir, "partial.txt", "ONE\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	gitAdd(t, tmpDir, "partial.txt")
+	createFile(t, tmpDir, "partial.txt", "ONE\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\n")
+
+	createFile(t, tmpDir, "untracked.txt", "a\nb\nc")
+	return tmpDir
+}
+
+func TestGetUncommittedFileStatsInScope_AllCombinesStagedAndUnstaged(t *testing.T) {
+	tmpDir := setupPartiallyStagedRepo(t)
+
+	stats, err := GetUncommittedFileStatsInScope(tmpDir, UncommittedAll, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetUncommittedFileStatsInScope_Staged(t *testing.T) {
+	tmpDir := setupPartiallyStagedRepo(t)
+
+	stats, err := GetUncommittedFileStatsInScope(tmpDir, UncommittedStaged, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetUncommittedFileStatsInScope_Unstaged(t *testing.T) {
+	tmpDir := setupPartiallyStagedRepo(t)
+
+	stats, err := GetUncommittedFileStatsInScope(tmpDir, UncommittedUnstaged, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	g := testhelpers.TextGoldie(t)
+	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+}
+
+func TestGetUncommittedFileStatsInScope_CountsUntrackedLinesThroughContentReader(t *testing.T) {
+	tmpDir := setupPartiallyStagedRepo(t)
+	resolvedTmpDir, err := filepath.EvalSymlinks(tmpDir)
+	require.NoError(t, err)
+	untrackedPath := filepath.Join(resolvedTmpDir, "untracked.txt")
+	reader := overlayContentReader{
+		ContentReader: vcs.FilesystemContentReader(),
+		files:         map[string][]byte{untrackedPath: []byte("1\n2\n3\n4\n5\n")},
+	}
+
+	stats, err := GetUncommittedFileStatsInScope(tmpDir, UncommittedAll, reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, stats[untrackedPath].Additions)
+	assert.True(t, stats[untrackedPath].IsNew)
+}
+
+// overlayContentReader serves files from a map before falling back to another reader.
+type overlayContentReader struct {
+	vcs.ContentReader
+	files map[string][]byte
+}
+
+func (r overlayContentReader) ReadFile(filePath string) ([]byte, error) {
+	if content, ok := r.files[filePath]; ok {
+		return content, nil
+	}
+	return r.ContentReader.ReadFile(filePath)
+}
+
+// setupStagedRenameRepo commits lib/old.dart and stages its move to newPath after
+// applying edit to its content. Rename detection is turned off in the repository
+// config, so the stats only see the rename if they ask git for it.
+func setupStagedRenameRepo(t *testing.T, newPath string, edit func(string) string) string {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	gitConfig(t, tmpDir, "diff.renames", "false")
+	gitConfig(t, tmpDir, "status.renames", "false")
+
+	content := "class Old {}\n"
+	for i := 0; i < 20; i++ {
+		content += fmt.Sprintf("const value%d = %d;\n", i, i)
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "lib"), 0755))
+	createFile(t, tmpDir, "lib/old.dart", content)
+	gitAdd(t, tmpDir, "lib/old.dart")
+	gitCommit(t, tmpDir, "Initial commit")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, newPath)), 0755))
+	require.NoError(t, os.Rename(filepath.Join(tmpDir, "lib", "old.dart"), filepath.Join(tmpDir, newPath)))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, newPath), []byte(edit(content)), 0644))
+	gitAdd(t, tmpDir, ".")
+	return tmpDir
+}
+
+func TestGetUncommittedFileStats_StagedRenameWithoutEdits(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "lib/new.dart", func(content string) string { return content })
+
+	stats, err := GetUncommittedFileStats(tmpDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/lib/new.dart: +0 -0 new=false", normalizeFileStats(tmpDir, stats))
+	assert.Equal(t, "lib/old.dart", statsFor(t, tmpDir, stats, "lib/new.dart").RenamedFrom)
+}
+
+func TestGetUncommittedFileStats_StagedRenameWithEdits(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "lib/new.dart", func(content string) string {
+		return strings.Replace(content, "class Old {}", "class New {}", 1) + "const extra = 1;\n"
+	})
+
+	stats, err := GetUncommittedFileStatsInScope(tmpDir, UncommittedStaged, vcs.FilesystemContentReader())
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/lib/new.dart: +2 -1 new=false", normalizeFileStats(tmpDir, stats))
+	assert.Equal(t, "lib/old.dart", statsFor(t, tmpDir, stats, "lib/new.dart").RenamedFrom)
+}
+
+func TestGetUncommittedFileStats_StagedRenameAcrossDirectories(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "src/feature/new.dart", func(content string) string { return content })
+
+	stats, err := GetUncommittedFileStats(tmpDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/src/feature/new.dart: +0 -0 new=false", normalizeFileStats(tmpDir, stats))
+	assert.Equal(t, "lib/old.dart", statsFor(t, tmpDir, stats, "src/feature/new.dart").RenamedFrom)
+}
+
+// commitStagedRename commits the rename staged by setupStagedRenameRepo.
+func commitStagedRename(t *testing.T, tmpDir string) {
+	t.Helper()
+	gitCommit(t, tmpDir, "Rename")
+}
+
+func TestGetCommitRangeFileStats_PureRename(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "lib/new.dart", func(content string) string { return content })
+	commitStagedRename(t, tmpDir)
+
+	stats, err := GetCommitRangeFileStats(tmpDir, "HEAD~1", "HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/lib/new.dart: +0 -0 new=false", normalizeFileStats(tmpDir, stats))
+	assert.Equal(t, "lib/old.dart", statsFor(t, tmpDir, stats, "lib/new.dart").RenamedFrom)
+}
+
+func TestGetCommitRangeFileStats_RenameWithEdits(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "src/feature/new.dart", func(content string) string {
+		return strings.Replace(content, "class Old {}", "class New {}", 1) + "const extra = 1;\n"
+	})
+	commitStagedRename(t, tmpDir)
+
+	stats, err := GetCommitRangeFileStats(tmpDir, "HEAD~1", "HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/src/feature/new.dart: +2 -1 new=false", normalizeFileStats(tmpDir, stats))
+	assert.Equal(t, "lib/old.dart", statsFor(t, tmpDir, stats, "src/feature/new.dart").RenamedFrom)
+}
+
+func TestGetCommitFileStats_Rename(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "lib/new.dart", func(content string) string { return content })
+	commitStagedRename(t, tmpDir)
+
+	stats, err := GetCommitFileStats(tmpDir, "HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, "$REPO/lib/new.dart: +0 -0 new=false", normalizeFileStats(tmpDir, stats))
+	assert.Equal(t, "lib/old.dart", statsFor(t, tmpDir, stats, "lib/new.dart").RenamedFrom)
+}
+
+func TestGetCommitRangeFiles_RenameListsNewPathOnly(t *testing.T) {
+	tmpDir := setupStagedRenameRepo(t, "lib/new.dart", func(content string) string { return content })
+	commitStagedRename(t, tmpDir)
+
+	files, err := GetCommitRangeFiles(tmpDir, "HEAD~1", "HEAD")
+	require.NoError(t, err)
+
+	require.Len(t, files, 1)
+	assert.Equal(t, "new.dart", filepath.Base(files[0]))
+}
+
+func statsFor(t *testing.T, tmpDir string, stats map[string]vcs.FileStats, relPath string) vcs.FileStats {
+	t.Helper()
+	resolvedTmpDir, err := filepath.EvalSymlinks(tmpDir)
+	require.NoError(t, err)
+	fileStats, ok := stats[filepath.Join(resolvedTmpDir, filepath.FromSlash(relPath))]
+	require.True(t, ok, "no stats for %s", relPath)
+	return fileStats
+}
+
+func TestGetUncommittedFileStatsWithOptions_SelectsChangeKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		opts UncommittedOptions
+	}{
+		{"Staged", UncommittedOptions{IncludeStaged: true}},
+		{"Unstaged", UncommittedOptions{IncludeUnstaged: true}},
+		{"Untracked", UncommittedOptions{IncludeUntracked: true}},
+		{"UnstagedAndUntracked", UncommittedOptions{IncludeUnstaged: true, IncludeUntracked: true}},
+		{"All", AllUncommittedChanges()},
+	}
+	tmpDir := setupPartiallyStagedRepo(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := GetUncommittedFileStatsWithOptions(tmpDir, tt.opts, vcs.FilesystemContentReader())
+			require.NoError(t, err)
+
+			g := testhelpers.TextGoldie(t)
+			g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+		})
+	}
+}
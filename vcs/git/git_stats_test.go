@@ -3,55 +3,10 @@ package git
 import (
 	"testing"
 
-	"github.com/LegacyCodeHQ/clarity/internal/testhelpers"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
-)
-
-func TestGetUncommittedFileStats_MarksNewAndUntrackedFiles(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	committedFile := createDartFile(t, tmpDir, "committed.dart")
-	gitAdd(t, tmpDir, "committed.dart")
-	gitCommit(t, tmpDir, "Initial commit")
-
-	createDartFile(t, tmpDir, "staged.dart")
-	gitAdd(t, tmpDir, "staged.dart")
-
-	createDartFile(t, tmpDir, "untracked.dart")
-
-	modifyFile(t, committedFile)
-
-	stats, err := GetUncommittedFileStats(tmpDir)
-	require.NoError(t, err)
-
-	g := testhelpers.TextGoldie(t)
-	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
-}
 
-func TestGetCommitFileStats_MarksNewFiles(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	existingFile := createDartFile(t, tmpDir, "existing.dart")
-	gitAdd(t, tmpDir, "existing.dart")
-	gitCommit(t, tmpDir, "Initial commit")
-
-	createDartFile(t, tmpDir, "added.dart")
-	gitAdd(t, tmpDir, "added.dart")
-	modifyFile(t, existingFile)
-	gitAdd(t, tmpDir, "existing.dart")
-	commitID := gitCommitAndGetSHA(t, tmpDir, "Add new file and modify existing")
-
-	stats, err := GetCommitFileStats(tmpDir, commitID)
-	require.NoError(t, err)
-
-	g := testhelpers.TextGoldie(t)
-	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
-}
-
-// Tests for parseRenamedFilePath
+	"github.com/LegacyCodeHQ/clarity/vcs"
+)
 
 func TestParseRenamedFilePath_AbbreviatedFormat(t *testing.T) {
 	tests := []struct {
@@ -143,89 +98,93 @@ func TestParseRenamedFilePath_NoRename(t *testing.T) {
 
 // Tests for GetCommitRangeFileStats
 
-func TestGetCommitRangeFileStats_AdditionsAndDeletions(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	// Create first commit
-	createFile(t, tmpDir, "test.txt", "line1\nline2\nline3\n")
-	gitAdd(t, tmpDir, "test.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
-
-	// Modify file: add 2 lines, remove 1
-	createFile(t, tmpDir, "test.txt", "line1\nline3\nnew1\nnew2\n")
-	gitAdd(t, tmpDir, "test.txt")
-	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
-
-	stats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
-
-	require.NoError(t, err)
-	g := testhelpers.TextGoldie(t)
-	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
-}
-
-func TestGetCommitRangeFileStats_NewFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	// Create first commit
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
-
-	// Add new file
-	createFile(t, tmpDir, "new.txt", "line1\nline2\n")
-	gitAdd(t, tmpDir, "new.txt")
-	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
-
-	stats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
-
-	require.NoError(t, err)
-	g := testhelpers.TextGoldie(t)
-	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
-}
-
-func TestGetCommitRangeFileStats_MultipleFiles(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	// Create first commit
-	createFile(t, tmpDir, "file1.txt", "content")
-	gitAdd(t, tmpDir, "file1.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
-
-	// Modify and add new file
-	createFile(t, tmpDir, "file1.txt", "content\nnew line\n")
-	createFile(t, tmpDir, "file2.txt", "new file\n")
-	gitAdd(t, tmpDir, "file1.txt")
-	gitAdd(t, tmpDir, "file2.txt")
-	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
-
-	stats, err := GetCommitRangeFileStats(tmpDir, firstCommit, secondCommit)
+func TestParseNumstatLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantPath string
+		want     vcs.FileStats
+	}{
+		{
+			name:     "text file",
+			line:     "12\t3\tsrc/main.go",
+			wantPath: "src/main.go",
+			want:     vcs.FileStats{Additions: 12, Deletions: 3},
+		},
+		{
+			name:     "binary file",
+			line:     "-\t-\tassets/logo.png",
+			wantPath: "assets/logo.png",
+			want:     vcs.FileStats{IsBinary: true},
+		},
+		{
+			name:     "abbreviated rename",
+			line:     "2\t1\tsrc/{old.go => new.go}",
+			wantPath: "src/new.go",
+			want:     vcs.FileStats{Additions: 2, Deletions: 1, RenamedFrom: "src/old.go"},
+		},
+		{
+			name:     "rename into new directory",
+			line:     "0\t0\tsrc/{ => util}/helper.go",
+			wantPath: "src/util/helper.go",
+			want:     vcs.FileStats{RenamedFrom: "src/helper.go"},
+		},
+		{
+			name:     "full rename",
+			line:     "4\t0\told/a.go => new/a.go",
+			wantPath: "new/a.go",
+			want:     vcs.FileStats{Additions: 4, RenamedFrom: "old/a.go"},
+		},
+		{
+			name:     "name with repeated spaces",
+			line:     "1\t0\tlib/my  file.dart",
+			wantPath: "lib/my  file.dart",
+			want:     vcs.FileStats{Additions: 1},
+		},
+		{
+			name:     "quoted name",
+			line:     "3\t1\t\"lib/tab\\tx.dart\"",
+			wantPath: "lib/tab\tx.dart",
+			want:     vcs.FileStats{Additions: 3, Deletions: 1},
+		},
+		{
+			name:     "rename with quoted sides",
+			line:     "0\t0\t\"we\\\"ird.dart\" => sp  ace.dart",
+			wantPath: "sp  ace.dart",
+			want:     vcs.FileStats{RenamedFrom: `we"ird.dart`},
+		},
+	}
 
-	require.NoError(t, err)
-	g := testhelpers.TextGoldie(t)
-	g.Assert(t, t.Name(), []byte(normalizeFileStats(tmpDir, stats)))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, stats, ok := parseNumstatLine(tt.line)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.want, stats)
+		})
+	}
 }
 
-func TestGetCommitRangeFileStats_InvalidCommit(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
-
-	_, err := GetCommitRangeFileStats(tmpDir, "invalid-sha", commit)
-
-	assert.Error(t, err)
+func TestParseNumstatLine_RejectsShortLines(t *testing.T) {
+	_, _, ok := parseNumstatLine("12\t3")
+	assert.False(t, ok)
 }
 
-func TestGetCommitRangeFileStats_NotGitRepo(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	_, err := GetCommitRangeFileStats(tmpDir, "abc", "def")
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "trailing newline", content: "a\nb\n", want: 2},
+		{name: "no trailing newline", content: "a\nb", want: 2},
+		{name: "single newline", content: "\n", want: 1},
+	}
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not a git repository")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, countLines([]byte(tt.content)))
+		})
+	}
 }
@@ -0,0 +1,118 @@
+//go:build integration
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSuperproject creates a repository with a committed main.go and a submodule at
+// libs/sub, and returns the superproject directory.
+func setupSuperproject(t *testing.T) string {
+	root := t.TempDir()
+
+	subRepo := filepath.Join(root, "sub-origin")
+	require.NoError(t, os.MkdirAll(subRepo, 0755))
+	setupGitRepo(t, subRepo)
+	createFile(t, subRepo, "lib.go", "package lib\n")
+	gitAdd(t, subRepo, "lib.go")
+	gitCommit(t, subRepo, "Initial commit")
+
+	superDir := filepath.Join(root, "super")
+	require.NoError(t, os.MkdirAll(superDir, 0755))
+	setupGitRepo(t, superDir)
+	createFile(t, superDir, "main.go", "package main\n")
+	gitAdd(t, superDir, "main.go")
+
+	cmd := exec.Command("git", "-c", "protocol.file.allow=always", "submodule", "add", subRepo, "libs/sub")
+	cmd.Dir = superDir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "failed to add submodule: %s", output)
+	gitCommit(t, superDir, "Add submodule")
+
+	subDir := filepath.Join(superDir, "libs", "sub")
+	gitConfig(t, subDir, "user.name", "Test User")
+	gitConfig(t, subDir, "user.email", "test@example.com")
+
+	return superDir
+}
+
+// advanceSubmodule commits a change inside the submodule, so the superproject sees a
+// new submodule commit.
+func advanceSubmodule(t *testing.T, superDir string) {
+	subDir := filepath.Join(superDir, "libs", "sub")
+	modifyFile(t, filepath.Join(subDir, "lib.go"))
+	gitAdd(t, subDir, "lib.go")
+	gitCommit(t, subDir, "Change lib")
+}
+
+func TestGetUncommittedFiles_SkipsSubmodules(t *testing.T) {
+	superDir := setupSuperproject(t)
+	modifyFile(t, filepath.Join(superDir, "main.go"))
+	advanceSubmodule(t, superDir)
+	createFile(t, filepath.Join(superDir, "libs", "sub"), "untracked.go", "package lib\n")
+
+	// An untracked nested repository is reported by git status as a directory
+	nestedDir := filepath.Join(superDir, "nested")
+	require.NoError(t, os.MkdirAll(nestedDir, 0755))
+	setupGitRepo(t, nestedDir)
+	createFile(t, nestedDir, "nested.go", "package nested\n")
+
+	files, err := GetUncommittedFiles(superDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/main.go", normalizeFilePaths(superDir, files))
+}
+
+func TestGetCommitDartFiles_SkipsSubmodules(t *testing.T) {
+	superDir := setupSuperproject(t)
+	advanceSubmodule(t, superDir)
+	modifyFile(t, filepath.Join(superDir, "main.go"))
+	gitAdd(t, superDir, ".")
+	commit := gitCommitAndGetSHA(t, superDir, "Bump submodule")
+
+	files, err := GetCommitDartFiles(superDir, commit)
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/main.go", normalizeFilePaths(superDir, files))
+}
+
+func TestGetCommitRangeFiles_SkipsSubmodules(t *testing.T) {
+	superDir := setupSuperproject(t)
+	from, err := GetCurrentCommitHash(superDir)
+	require.NoError(t, err)
+	advanceSubmodule(t, superDir)
+	modifyFile(t, filepath.Join(superDir, "main.go"))
+	gitAdd(t, superDir, ".")
+	to := gitCommitAndGetSHA(t, superDir, "Bump submodule")
+
+	var files []string
+	files, err = GetCommitRangeFiles(superDir, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/main.go", normalizeFilePaths(superDir, files))
+}
+
+func TestGetCommitTreeFiles_SkipsSubmodules(t *testing.T) {
+	superDir := setupSuperproject(t)
+
+	files, err := GetCommitTreeFiles(superDir, "HEAD")
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/.gitmodules\n$REPO/main.go", normalizeFilePaths(superDir, files))
+}
+
+func TestListTrackedFiles_SkipsSubmodules(t *testing.T) {
+	superDir := setupSuperproject(t)
+
+	files, err := ListTrackedFiles(superDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "$REPO/.gitmodules\n$REPO/main.go", normalizeFilePaths(superDir, files))
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// symlinkMode is the index and tree mode of a symbolic link entry.
+const symlinkMode = "120000"
+
+// ListSymlinks returns the symbolic links git tracks in a commit's tree, or in the
+// index when commitID is empty, mapping the absolute path of each link to the
+// absolute path it points at. Relative targets are resolved against the link's
+// directory; targets that are themselves links are not followed. Links of a commit
+// are read from git objects, and links in the index from the working tree, where a
+// link deleted or replaced since it was staged is skipped.
+func ListSymlinks(repoPath, commitID string) (map[string]string, error) {
+	return ListSymlinksContext(context.Background(), repoPath, commitID)
+}
+
+// ListSymlinksContext lists symbolic links like ListSymlinks, stopping git when ctx is
+// done.
+func ListSymlinksContext(ctx context.Context, repoPath, commitID string) (map[string]string, error) {
+	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+		return nil, repoPathMissingError(repoPath)
+	}
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
+	}
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+	repoRoot = filepath.Clean(repoRoot)
+
+	args := []string{"ls-files", "-z", "--cached", "--stage"}
+	if commitID != "" {
+		if err := validateGitRef(commitID); err != nil {
+			return nil, err
+		}
+		args = []string{"ls-tree", "-r", "-z", "--full-tree", commitID}
+	}
+	stdout, stderr, err := runGitCommandContext(ctx, repoRoot, args...)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
+	}
+
+	links := make(map[string]string)
+	for _, record := range bytes.Split(stdout, []byte{0}) {
+		if len(record) == 0 {
+			continue
+		}
+		header, path, ok := strings.Cut(string(record), "\t")
+		if !ok {
+			return nil, fmt.Errorf("unexpected git %s output: %q", args[0], record)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git %s output: %q", args[0], record)
+		}
+		if fields[0] != symlinkMode {
+			continue
+		}
+
+		linkPath := filepath.Join(repoRoot, filepath.FromSlash(path))
+		var target string
+		if commitID != "" {
+			// A link's blob holds its target.
+			content, stderr, err := runGitCommandContext(ctx, repoRoot, "cat-file", "blob", fields[2])
+			if err != nil {
+				return nil, gitCommandError(err, stderr)
+			}
+			target = string(content)
+		} else {
+			if target, err = os.Readlink(linkPath); err != nil {
+				continue
+			}
+		}
+
+		target = filepath.FromSlash(target)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(linkPath), target)
+		}
+		links[linkPath] = filepath.Clean(target)
+	}
+	return links, nil
+}
@@ -0,0 +1,42 @@
+//go:build integration
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSymlinks_CommitAndIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	repoRoot, err := filepath.EvalSymlinks(tmpDir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "shared"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "lib"), 0o755))
+	createFile(t, tmpDir, "shared/util.go", "package shared\n")
+	require.NoError(t, os.Symlink("../shared", filepath.Join(tmpDir, "lib", "shared")))
+	gitAdd(t, tmpDir, ".")
+	commitID := gitCommitAndGetSHA(t, tmpDir, "Add link")
+
+	// Repoint the link in the working tree only.
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "lib", "shared")))
+	require.NoError(t, os.Symlink("/elsewhere", filepath.Join(tmpDir, "lib", "shared")))
+
+	fromCommit, err := ListSymlinks(tmpDir, commitID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.Join(repoRoot, "lib", "shared"): filepath.Join(repoRoot, "shared"),
+	}, fromCommit)
+
+	fromIndex, err := ListSymlinks(tmpDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.Join(repoRoot, "lib", "shared"): "/elsewhere",
+	}, fromIndex)
+}
@@ -1,29 +1,32 @@
 package git
 
 import (
-	"os"
-	"path/filepath"
-	"strings"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+const (
+	olderCommit = "1111111111111111111111111111111111111111"
+	newerCommit = "2222222222222222222222222222222222222222"
+)
+
 func TestIsGitRepository_Valid(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	useFakeGitRunner(t).
+		on("rev-parse --git-dir", fakeGitResponse{stdout: ".git\n"})
 
-	isRepo := isGitRepository(tmpDir)
+	isRepo := isGitRepository("/repo")
 
 	assert.True(t, isRepo)
 }
 
 func TestIsGitRepository_Invalid(t *testing.T) {
-	tmpDir := t.TempDir()
-	// Don't initialize git
+	useFakeGitRunner(t).
+		on("rev-parse --git-dir", notARepository)
 
-	isRepo := isGitRepository(tmpDir)
+	isRepo := isGitRepository("/repo")
 
 	assert.False(t, isRepo)
 }
@@ -63,221 +66,194 @@ func TestToAbsolutePaths(t *testing.T) {
 // Tests for GetRepositoryRoot
 
 func TestGetRepositoryRoot_FromRepoRoot(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	useFakeGitRunner(t).
+		on("rev-parse --show-toplevel", fakeGitResponse{stdout: "/repo\n"})
 
-	root, err := GetRepositoryRoot(tmpDir)
+	root, err := GetRepositoryRoot("/repo")
 
 	require.NoError(t, err)
-	// Resolve symlinks for comparison (macOS /var -> /private/var)
-	resolvedTmp, _ := filepath.EvalSymlinks(tmpDir)
-	assert.Equal(t, resolvedTmp, root)
+	assert.Equal(t, "/repo", root)
 }
 
-func TestGetRepositoryRoot_FromSubdirectory(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+func TestGetRepositoryRoot_NotGitRepo(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --show-toplevel", notARepository)
+
+	_, err := GetRepositoryRoot("/repo")
 
-	// Create subdirectory
-	subDir := filepath.Join(tmpDir, "lib", "src")
-	err := os.MkdirAll(subDir, 0755)
-	require.NoError(t, err)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a git repository")
+	assert.ErrorIs(t, err, ErrNotARepository)
+}
 
-	root, err := GetRepositoryRoot(subDir)
+func TestGetRepositoryRoot_BareRepositoryUsesGitDir(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --show-toplevel", fakeGitResponse{stderr: "fatal: this operation must be run in a work tree", exitCode: 128}).
+		on("rev-parse --absolute-git-dir", fakeGitResponse{stdout: "/mirrors/repo.git\n"})
+
+	root, err := GetRepositoryRoot("/mirrors/repo.git")
 
 	require.NoError(t, err)
-	resolvedTmp, _ := filepath.EvalSymlinks(tmpDir)
-	assert.Equal(t, resolvedTmp, root)
+	assert.Equal(t, "/mirrors/repo.git", root)
 }
 
-func TestGetRepositoryRoot_NotGitRepo(t *testing.T) {
-	tmpDir := t.TempDir()
-	// Don't initialize git
+func TestIsBareRepository(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --is-bare-repository", fakeGitResponse{stdout: "true\n"})
 
-	_, err := GetRepositoryRoot(tmpDir)
+	bare, err := IsBareRepository("/mirrors/repo.git")
 
-	assert.Error(t, err)
+	require.NoError(t, err)
+	assert.True(t, bare)
 }
 
-// Tests for GetCurrentCommitHash
+func TestGetMergeBase_ReturnsForkPoint(t *testing.T) {
+	useFakeGitRunner(t).
+		on("merge-base main feature", fakeGitResponse{stdout: "abc123def456\n"})
 
-func TestGetCurrentCommitHash_Success(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	mergeBase, err := GetMergeBase("/repo", "main", "feature")
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	gitCommit(t, tmpDir, "Initial commit")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123def456", mergeBase)
+}
 
-	hash, err := GetCurrentCommitHash(tmpDir)
+func TestGetMergeBase_UnrelatedHistories(t *testing.T) {
+	useFakeGitRunner(t).
+		on("merge-base main orphan", fakeGitResponse{exitCode: 1})
 
-	require.NoError(t, err)
-	assert.NotEmpty(t, hash)
-	// Short hash is typically 7 characters
-	assert.True(t, len(hash) >= 7 && len(hash) <= 12, "hash should be short format")
+	_, err := GetMergeBase("/repo", "main", "orphan")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "have no common ancestor")
 }
 
-func TestGetCurrentCommitHash_MatchesHEAD(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+func TestIsMergeBaseRange(t *testing.T) {
+	assert.True(t, IsMergeBaseRange("main...HEAD"))
+	assert.False(t, IsMergeBaseRange("main..HEAD"))
+	assert.False(t, IsMergeBaseRange("HEAD"))
+}
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	expectedHash := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+// Tests for GetCurrentCommitHash
 
-	hash, err := GetCurrentCommitHash(tmpDir)
+func TestGetCurrentCommitHash_Success(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --short HEAD", fakeGitResponse{stdout: "1111111\n"})
+
+	hash, err := GetCurrentCommitHash("/repo")
 
 	require.NoError(t, err)
-	// The current hash should be a prefix of the full commit SHA
-	assert.True(t, strings.HasPrefix(expectedHash, hash), "current hash should match HEAD")
+	assert.Equal(t, "1111111", hash)
 }
 
 func TestGetCurrentCommitHash_NoCommits(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	useFakeGitRunner(t).
+		on("rev-parse --short HEAD", fakeGitResponse{
+			stderr:   "fatal: ambiguous argument 'HEAD': unknown revision or path not in the working tree.",
+			exitCode: 128,
+		})
 
-	// No commits made
-	_, err := GetCurrentCommitHash(tmpDir)
+	_, err := GetCurrentCommitHash("/repo")
 
 	assert.Error(t, err)
 }
 
-// Tests for GetShortCommitHash
-
-func TestGetShortCommitHash_Success(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+// Tests for GetCommitHash
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	fullHash := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+func TestGetCommitHash_ResolvesFullSHA(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --verify HEAD^{commit}", fakeGitResponse{stdout: newerCommit + "\n"})
 
-	shortHash, err := GetShortCommitHash(tmpDir, fullHash)
+	hash, err := GetCommitHash("/repo", "HEAD")
 
 	require.NoError(t, err)
-	assert.True(t, len(shortHash) >= 7 && len(shortHash) <= 12)
-	assert.True(t, strings.HasPrefix(fullHash, shortHash))
+	assert.Equal(t, newerCommit, hash)
 }
 
-func TestGetShortCommitHash_AlreadyShort(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+// Tests for GetRemoteURL
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	fullHash := gitCommitAndGetSHA(t, tmpDir, "Initial commit")
+func TestGetRemoteURL_Success(t *testing.T) {
+	useFakeGitRunner(t).
+		on("config --get remote.origin.url", fakeGitResponse{stdout: "git@github.com:org/repo.git\n"})
 
-	// Get short hash first
-	shortHash, err := GetShortCommitHash(tmpDir, fullHash[:7])
+	remoteURL, err := GetRemoteURL("/repo", "origin")
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, shortHash)
+	assert.Equal(t, "git@github.com:org/repo.git", remoteURL)
 }
 
-func TestGetShortCommitHash_HEAD(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	gitCommit(t, tmpDir, "Initial commit")
+func TestGetRemoteURL_MissingRemote(t *testing.T) {
+	useFakeGitRunner(t).
+		on("config --get remote.origin.url", fakeGitResponse{exitCode: 1})
 
-	shortHash, err := GetShortCommitHash(tmpDir, "HEAD")
+	_, err := GetRemoteURL("/repo", "origin")
 
-	require.NoError(t, err)
-	assert.NotEmpty(t, shortHash)
+	assert.EqualError(t, err, "remote 'origin' has no URL configured")
 }
 
+// Tests for GetShortCommitHash
+
 func TestGetShortCommitHash_InvalidCommit(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	onUnknownRef(useFakeGitRunner(t), "invalid-sha-that-does-not-exist")
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	gitCommit(t, tmpDir, "Initial commit")
+	_, err := GetShortCommitHash("/repo", "invalid-sha-that-does-not-exist")
 
-	_, err := GetShortCommitHash(tmpDir, "invalid-sha-that-does-not-exist")
+	assert.Error(t, err)
+}
+
+func TestGetShortCommitHash_RejectsOptionLikeRef(t *testing.T) {
+	runner := useFakeGitRunner(t)
+
+	_, err := GetShortCommitHash("/repo", "--output=/tmp/x")
 
 	assert.Error(t, err)
+	assert.Empty(t, runner.calls)
 }
 
 // Tests for HasUncommittedChanges
 
 func TestHasUncommittedChanges_Clean(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	useFakeGitRunner(t).
+		on("status --porcelain", fakeGitResponse{})
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	gitCommit(t, tmpDir, "Initial commit")
-
-	hasChanges, err := HasUncommittedChanges(tmpDir)
+	hasChanges, err := HasUncommittedChanges("/repo")
 
 	require.NoError(t, err)
 	assert.False(t, hasChanges)
 }
 
 func TestHasUncommittedChanges_UntrackedFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	createFile(t, tmpDir, "committed.txt", "content")
-	gitAdd(t, tmpDir, "committed.txt")
-	gitCommit(t, tmpDir, "Initial commit")
+	useFakeGitRunner(t).
+		on("status --porcelain", fakeGitResponse{stdout: "?? untracked.txt\n"})
 
-	// Create untracked file
-	createFile(t, tmpDir, "untracked.txt", "new content")
-
-	hasChanges, err := HasUncommittedChanges(tmpDir)
+	hasChanges, err := HasUncommittedChanges("/repo")
 
 	require.NoError(t, err)
 	assert.True(t, hasChanges)
 }
 
-func TestHasUncommittedChanges_ModifiedFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	filePath := createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	gitCommit(t, tmpDir, "Initial commit")
+// Tests for GetUncommittedFiles
 
-	// Modify the file
-	modifyFile(t, filePath)
+func TestGetUncommittedFiles_ParsesPorcelainStatus(t *testing.T) {
+	repoDir := t.TempDir()
+	useFakeGitRunner(t).
+		on("rev-parse --git-dir", fakeGitResponse{stdout: ".git\n"}).
+		on("rev-parse --show-toplevel", fakeGitResponse{stdout: "/repo\n"}).
+		on("status --porcelain --untracked-files=all --ignore-submodules=all", fakeGitResponse{stdout: " M lib/main.dart\n" +
+			"A  lib/added.dart\n" +
+			" D lib/deleted.dart\n" +
+			"R  old.ts -> src/new.ts\n" +
+			"?? notes.md\n" +
+			"?? vendor/nested/\n"})
 
-	hasChanges, err := HasUncommittedChanges(tmpDir)
+	files, err := GetUncommittedFiles(repoDir)
 
 	require.NoError(t, err)
-	assert.True(t, hasChanges)
-}
-
-func TestHasUncommittedChanges_StagedFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	gitCommit(t, tmpDir, "Initial commit")
-
-	// Create and stage a new file
-	createFile(t, tmpDir, "staged.txt", "new content")
-	gitAdd(t, tmpDir, "staged.txt")
-
-	hasChanges, err := HasUncommittedChanges(tmpDir)
-
-	require.NoError(t, err)
-	assert.True(t, hasChanges)
-}
-
-func TestHasUncommittedChanges_EmptyRepo(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	// Empty repo with no commits
-	hasChanges, err := HasUncommittedChanges(tmpDir)
-
-	require.NoError(t, err)
-	assert.False(t, hasChanges)
+	assert.Equal(t, []string{
+		"/repo/lib/main.dart",
+		"/repo/lib/added.dart",
+		"/repo/src/new.ts",
+		"/repo/notes.md",
+	}, files)
 }
 
 // Tests for ParseCommitRange
@@ -335,186 +311,170 @@ func TestParseCommitRange_ThreeDotPreferredOverTwoDot(t *testing.T) {
 // Tests for isAncestor
 
 func TestIsAncestor_True(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	// Create first commit
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
-
-	// Create second commit
-	createFile(t, tmpDir, "second.txt", "content")
-	gitAdd(t, tmpDir, "second.txt")
-	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" "+newerCommit, fakeGitResponse{})
 
-	// First should be ancestor of second
-	result, err := isAncestor(tmpDir, firstCommit, secondCommit)
+	result, err := isAncestor(context.Background(), "/repo", olderCommit, newerCommit)
 
 	require.NoError(t, err)
 	assert.True(t, result)
 }
 
 func TestIsAncestor_False(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	// Create first commit
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
-
-	// Create second commit
-	createFile(t, tmpDir, "second.txt", "content")
-	gitAdd(t, tmpDir, "second.txt")
-	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+newerCommit+" "+olderCommit, fakeGitResponse{exitCode: 1})
 
-	// Second should NOT be ancestor of first
-	result, err := isAncestor(tmpDir, secondCommit, firstCommit)
+	result, err := isAncestor(context.Background(), "/repo", newerCommit, olderCommit)
 
 	require.NoError(t, err)
 	assert.False(t, result)
 }
 
 func TestIsAncestor_SameCommit(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" "+olderCommit, fakeGitResponse{})
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
-
-	// A commit is its own ancestor
-	result, err := isAncestor(tmpDir, commit, commit)
+	result, err := isAncestor(context.Background(), "/repo", olderCommit, olderCommit)
 
 	require.NoError(t, err)
 	assert.True(t, result)
 }
 
-// Tests for NormalizeCommitRange
+func TestIsAncestor_GitFailureIsAnError(t *testing.T) {
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" missing", fakeGitResponse{
+			stderr:   "fatal: Not a valid object name missing",
+			exitCode: 128,
+		})
 
-func TestNormalizeCommitRange_AlreadyCorrectOrder(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	_, err := isAncestor(context.Background(), "/repo", olderCommit, "missing")
+
+	assert.Error(t, err)
+}
 
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	older := gitCommitAndGetSHA(t, tmpDir, "First commit")
+// Tests for NormalizeCommitRange
 
-	createFile(t, tmpDir, "second.txt", "content")
-	gitAdd(t, tmpDir, "second.txt")
-	newer := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+func TestNormalizeCommitRange_AlreadyCorrectOrder(t *testing.T) {
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" "+newerCommit, fakeGitResponse{})
 
-	from, to, swapped, err := NormalizeCommitRange(tmpDir, older, newer)
+	from, to, swapped, err := NormalizeCommitRange("/repo", olderCommit, newerCommit)
 
 	require.NoError(t, err)
-	assert.Equal(t, older, from)
-	assert.Equal(t, newer, to)
+	assert.Equal(t, olderCommit, from)
+	assert.Equal(t, newerCommit, to)
 	assert.False(t, swapped)
 }
 
 func TestNormalizeCommitRange_ReversedOrder(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	older := gitCommitAndGetSHA(t, tmpDir, "First commit")
-
-	createFile(t, tmpDir, "second.txt", "content")
-	gitAdd(t, tmpDir, "second.txt")
-	newer := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+newerCommit+" "+olderCommit, fakeGitResponse{exitCode: 1}).
+		on("merge-base --is-ancestor "+olderCommit+" "+newerCommit, fakeGitResponse{})
 
 	// Pass in reversed order (newer...older)
-	from, to, swapped, err := NormalizeCommitRange(tmpDir, newer, older)
+	from, to, swapped, err := NormalizeCommitRange("/repo", newerCommit, olderCommit)
 
 	require.NoError(t, err)
-	assert.Equal(t, older, from)
-	assert.Equal(t, newer, to)
+	assert.Equal(t, olderCommit, from)
+	assert.Equal(t, newerCommit, to)
 	assert.True(t, swapped)
 }
 
 func TestNormalizeCommitRange_SameCommit(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" "+olderCommit, fakeGitResponse{})
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
-
-	from, to, swapped, err := NormalizeCommitRange(tmpDir, commit, commit)
+	from, to, swapped, err := NormalizeCommitRange("/repo", olderCommit, olderCommit)
 
 	require.NoError(t, err)
-	assert.Equal(t, commit, from)
-	assert.Equal(t, commit, to)
+	assert.Equal(t, olderCommit, from)
+	assert.Equal(t, olderCommit, to)
 	assert.False(t, swapped)
 }
 
-// Tests for GetCommitRangeLabel
+func TestNormalizeCommitRange_DivergedBranchesKeepOrder(t *testing.T) {
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" "+newerCommit, fakeGitResponse{exitCode: 1}).
+		on("merge-base --is-ancestor "+newerCommit+" "+olderCommit, fakeGitResponse{exitCode: 1}).
+		on("rev-parse --is-shallow-repository", fakeGitResponse{stdout: "false\n"})
 
-func TestGetCommitRangeLabel_Success(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	from, to, swapped, err := NormalizeCommitRange("/repo", olderCommit, newerCommit)
 
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+	require.NoError(t, err)
+	assert.Equal(t, olderCommit, from)
+	assert.Equal(t, newerCommit, to)
+	assert.False(t, swapped)
+}
 
-	createFile(t, tmpDir, "second.txt", "content")
-	gitAdd(t, tmpDir, "second.txt")
-	secondCommit := gitCommitAndGetSHA(t, tmpDir, "Second commit")
+func TestNormalizeCommitRange_ShallowCloneMissingCommitSuggestsUnshallow(t *testing.T) {
+	useFakeGitRunner(t).
+		on("merge-base --is-ancestor "+olderCommit+" "+newerCommit, fakeGitResponse{
+			stderr:   "fatal: Not a valid commit name " + olderCommit,
+			exitCode: 128,
+		}).
+		on("rev-parse --is-shallow-repository", fakeGitResponse{stdout: "true\n"}).
+		on("rev-parse --verify "+olderCommit+"^{commit}", fakeGitResponse{
+			stderr:   "fatal: Needed a single revision",
+			exitCode: 128,
+		}).
+		on("for-each-ref --format=%(refname) refs/heads refs/remotes refs/tags", fakeGitResponse{})
 
-	label, err := GetCommitRangeLabel(tmpDir, firstCommit, secondCommit)
+	_, _, _, err := NormalizeCommitRange("/repo", olderCommit, newerCommit)
 
-	require.NoError(t, err)
-	assert.Contains(t, label, "...")
-	// Should be in format "abc123...def456"
-	parts := strings.Split(label, "...")
-	assert.Len(t, parts, 2)
-	assert.True(t, len(parts[0]) >= 7)
-	assert.True(t, len(parts[1]) >= 7)
+	require.ErrorIs(t, err, ErrInvalidCommit)
+	assert.Contains(t, err.Error(), "git fetch --unshallow")
 }
 
-func TestGetCommitRangeLabel_WithHEAD(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
-
-	createFile(t, tmpDir, "first.txt", "content")
-	gitAdd(t, tmpDir, "first.txt")
-	firstCommit := gitCommitAndGetSHA(t, tmpDir, "First commit")
+// Tests for GetCommitRangeLabel
 
-	createFile(t, tmpDir, "second.txt", "content")
-	gitAdd(t, tmpDir, "second.txt")
-	gitCommit(t, tmpDir, "Second commit")
+func TestGetCommitRangeLabel_Success(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-parse --verify "+olderCommit+"^{commit}", fakeGitResponse{stdout: olderCommit + "\n"}).
+		on("rev-parse --short "+olderCommit, fakeGitResponse{stdout: "1111111\n"}).
+		on("rev-parse --verify "+newerCommit+"^{commit}", fakeGitResponse{stdout: newerCommit + "\n"}).
+		on("rev-parse --short "+newerCommit, fakeGitResponse{stdout: "2222222\n"})
 
-	label, err := GetCommitRangeLabel(tmpDir, firstCommit, "HEAD")
+	label, err := GetCommitRangeLabel("/repo", olderCommit, newerCommit)
 
 	require.NoError(t, err)
-	assert.Contains(t, label, "...")
+	assert.Equal(t, "1111111...2222222", label)
 }
 
 func TestGetCommitRangeLabel_InvalidFromCommit(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+	onUnknownRef(useFakeGitRunner(t), "invalid-sha")
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
-
-	_, err := GetCommitRangeLabel(tmpDir, "invalid-sha", commit)
+	_, err := GetCommitRangeLabel("/repo", "invalid-sha", newerCommit)
 
 	assert.Error(t, err)
 }
 
-func TestGetCommitRangeLabel_InvalidToCommit(t *testing.T) {
-	tmpDir := t.TempDir()
-	setupGitRepo(t, tmpDir)
+// Tests for GetCommitRangeCommits
+
+func TestGetCommitRangeCommits_ListsOldestFirst(t *testing.T) {
+	useFakeGitRunner(t).
+		on("rev-list --reverse --first-parent --abbrev-commit "+olderCommit+".."+newerCommit, fakeGitResponse{stdout: "1111111\n2222222\n"})
+
+	commits, err := GetCommitRangeCommits("/repo", olderCommit, newerCommit)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1111111", "2222222"}, commits)
+}
 
-	createFile(t, tmpDir, "test.txt", "content")
-	gitAdd(t, tmpDir, "test.txt")
-	commit := gitCommitAndGetSHA(t, tmpDir, "Commit")
+func TestGetCommitRangeCommits_RejectsOptionLikeRef(t *testing.T) {
+	runner := useFakeGitRunner(t)
 
-	_, err := GetCommitRangeLabel(tmpDir, commit, "invalid-sha")
+	_, err := GetCommitRangeCommits("/repo", "--all", newerCommit)
 
 	assert.Error(t, err)
+	assert.Empty(t, runner.calls)
+}
+
+func TestGetShortCommitHash_UnknownRevisionIsInvalidCommit(t *testing.T) {
+	onUnknownRef(useFakeGitRunner(t), "missing")
+
+	_, err := GetShortCommitHash("/repo", "missing")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidCommit)
+	assert.EqualError(t, err, "invalid commit reference 'missing': unknown ref")
 }
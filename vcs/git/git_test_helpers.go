@@ -14,6 +14,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// isolateHostGitConfig points git at empty global and system configuration so
+// settings on the host (init.defaultBranch, commit signing, hooks) cannot alter
+// test results. It affects every git subprocess started by the test binary.
+func isolateHostGitConfig() {
+	_ = os.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	_ = os.Setenv("GIT_CONFIG_SYSTEM", os.DevNull)
+	_ = os.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
 // setupGitRepo initializes a git repository in a temporary directory
 func setupGitRepo(t *testing.T, dir string) {
 	cmd := exec.Command("git", "init")
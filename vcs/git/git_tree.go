@@ -1,62 +1,65 @@
 package git
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 )
 
 // GetCommitTreeFiles returns all files that exist in a commit's tree.
-// Unlike GetCommitDartFiles which only returns files changed in a commit,
+// Unlike GetCommitFiles which only returns files changed in a commit,
 // this returns all files that existed at that point in time.
 // Returns absolute paths to all files in the commit tree.
 func GetCommitTreeFiles(repoPath, commitID string) ([]string, error) {
+	return GetCommitTreeFilesContext(context.Background(), repoPath, commitID)
+}
+
+// GetCommitTreeFilesContext lists a commit's files like GetCommitTreeFiles, stopping
+// git when ctx is done.
+func GetCommitTreeFilesContext(ctx context.Context, repoPath, commitID string) ([]string, error) {
 	// Validate the repository path exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("repository path does not exist: %s", repoPath)
+		return nil, repoPathMissingError(repoPath)
 	}
 
 	// Verify it's a git repository
-	if !isGitRepository(repoPath) {
-		return nil, fmt.Errorf("%s is not a git repository (use 'git init' to initialize)", repoPath)
+	if err := checkGitRepository(ctx, repoPath); err != nil {
+		return nil, err
 	}
 
-	// Validate the commit exists
-	if err := validateCommit(repoPath, commitID); err != nil {
+	// Resolve the commit to its SHA
+	commitID, err := resolveRef(ctx, repoPath, commitID)
+	if err != nil {
 		return nil, err
 	}
 
 	// Get the repository root
-	repoRoot, err := GetRepositoryRoot(repoPath)
+	repoRoot, err := getRepositoryRoot(ctx, repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
 	// Use git ls-tree to list all files in the commit tree
-	cmd := exec.Command("git", "ls-tree", "-r", "--name-only", commitID)
-	cmd.Dir = repoPath
-
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		if stderr.Len() > 0 {
-			return nil, fmt.Errorf("git command failed: %s", stderr.String())
-		}
-		return nil, err
+	stdout, stderr, err := runGitCommandContext(ctx, repoPath, "ls-tree", "-r", commitID)
+	if err != nil {
+		return nil, gitCommandError(err, stderr)
 	}
 
-	// Parse the output - one file per line
+	// Parse the output - one "<mode> <type> <object>\t<path>" entry per line.
+	// Submodules are "commit" entries; only blobs are files.
 	var files []string
-	lines := strings.Split(stdout.String(), "\n")
+	lines := strings.Split(string(stdout), "\n")
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			files = append(files, line)
+		header, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if fields := strings.Fields(header); len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		if path != "" {
+			files = append(files, unquoteGitPath(path))
 		}
 	}
 
@@ -1,3 +1,5 @@
+//go:build integration
+
 package git
 
 import (
@@ -172,6 +174,7 @@ func TestGetCommitTreeFiles_InvalidCommit(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid commit reference")
+	assert.ErrorIs(t, err, ErrInvalidCommit)
 }
 
 func TestGetCommitTreeFiles_NotGitRepo(t *testing.T) {
@@ -182,6 +185,7 @@ func TestGetCommitTreeFiles_NotGitRepo(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not a git repository")
+	assert.ErrorIs(t, err, ErrNotARepository)
 }
 
 func TestGetCommitTreeFiles_InvalidPath(t *testing.T) {
@@ -189,4 +193,5 @@ func TestGetCommitTreeFiles_InvalidPath(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "does not exist")
+	assert.ErrorIs(t, err, ErrRepoPathMissing)
 }